@@ -0,0 +1,48 @@
+#!/usr/bin/env bash
+
+# standard bash error handling
+set -o nounset # treat unset variables as an error and exit immediately.
+set -o errexit # exit immediately when a command fails.
+set -E         # needs to be set if we want the ERR trap
+
+readonly CURRENT_DIR=$( cd "$( dirname "${BASH_SOURCE[0]}" )" && pwd )
+readonly ROOT_PATH="${1:-$( cd "${CURRENT_DIR}/.." && pwd )}" # first argument or root of the project
+
+# Modules which must not be part of the dependency graph of parallel-install.
+# Importing them drags in the Terraform dependency tree and its conflicting Kubernetes pins.
+readonly FORBIDDEN_MODULES=(
+  github.com/kyma-incubator/hydroform/provision
+  github.com/hashicorp/terraform
+  github.com/terraform-providers/
+)
+
+source "${CURRENT_DIR}/utilities.sh" || { echo 'Cannot load CI utilities.'; exit 1; }
+
+# verify_dependencies fails if the module in the given directory depends on one of the forbidden modules
+modules::verify_dependencies() {
+  local module_dir="$1"
+  shout "Verify dependencies of $(basename "${module_dir}")"
+
+  cd "${module_dir}"
+  local dependencies
+  dependencies="$(go list -m all)"
+
+  local failed=0
+  for forbidden in "${FORBIDDEN_MODULES[@]}"; do
+    if grep -q "^${forbidden}" <<< "${dependencies}"; then
+      echo -e "${RED}x $(basename "${module_dir}") depends on ${forbidden}${NC}"
+      failed=1
+    fi
+  done
+  if [[ ${failed} != 0 ]]; then
+    exit 1
+  fi
+
+  echo -e "${GREEN}√ dependencies of $(basename "${module_dir}")${NC}"
+}
+
+main() {
+  modules::verify_dependencies "${ROOT_PATH}/parallel-install"
+}
+
+main
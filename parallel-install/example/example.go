@@ -116,6 +116,11 @@ func main() {
 		log.Fatalf("Failed to create namespaces: %s", err)
 	}
 
+	cleanup, err := preInstaller.Cleanup()
+	if err != nil || len(cleanup.NotDeleted) > 0 {
+		log.Fatalf("Failed to delete the CRDs and namespaces of previous installations: %s", err)
+	}
+
 	//Deploy Kyma
 	deployer, err := deployment.NewDeployment(installationCfg, builder, callbackUpdate)
 	if err != nil {
@@ -0,0 +1,306 @@
+//Package backup stores the Helm release state of a cluster before destructive operations and restores it.
+//
+//The snapshot contains the Helm release secrets, which also carry the Kyma component metadata,
+//and the installer override ConfigMaps. It is stored as a gzipped tar archive in a local directory
+//or uploaded to an HTTP(S) URL, for example a pre-signed S3 or GCS URL.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	helmSecretSelector  = "owner=helm"
+	overridesCMSelector = "installer=overrides"
+	secretsDir          = "secrets"
+	configMapsDir       = "configmaps"
+	archiveFilePattern  = "release-state-%s.tgz"
+	archiveTimeLayout   = "20060102-150405"
+	releaseNameLabel    = "name"
+	releaseOwnerLabel   = "owner"
+	releaseOwnerHelm    = "helm"
+	uploadContentType   = "application/gzip"
+	maxArchiveEntrySize = 64 << 20
+)
+
+//HTTPClientTimeout is the timeout the HTTP client of archive uploads and downloads should have, archives of large clusters take a while to transfer
+const HTTPClientTimeout = 5 * time.Minute
+
+//Snapshot stores the Helm release state of the cluster in the target and returns the location of the archive.
+//If the target is an HTTP(S) URL, the archive is uploaded with a PUT request to the URL using the HTTP client.
+//Otherwise, the target is a local directory the archive is written to.
+func Snapshot(kubeClient kubernetes.Interface, httpClient *http.Client, target string) (string, error) {
+	data, err := createArchive(kubeClient)
+	if err != nil {
+		return "", err
+	}
+	return store(httpClient, target, archiveFilePattern, data)
+}
+
+//RestoreReleaseState reverts the Helm release bookkeeping to the state stored in the archive.
+//The source is a local archive file or an HTTP(S) URL to download it from with the HTTP client.
+//Release secrets which were created after the snapshot are deleted, so Helm considers the stored revisions as the latest ones.
+//Only the bookkeeping is restored, the workloads of the releases are not touched.
+func RestoreReleaseState(kubeClient kubernetes.Interface, httpClient *http.Client, source string) error {
+	data, err := load(httpClient, source)
+	if err != nil {
+		return errors.Wrap(err, "Failed to read the release state archive")
+	}
+
+	secrets, configMaps, err := readArchive(data)
+	if err != nil {
+		return err
+	}
+
+	if err := removeNewerRevisions(kubeClient, secrets); err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		if err := restoreSecret(kubeClient, secret); err != nil {
+			return err
+		}
+	}
+	for _, cm := range configMaps {
+		if err := restoreConfigMap(kubeClient, cm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createArchive(kubeClient kubernetes.Interface) ([]byte, error) {
+	secrets, err := kubeClient.CoreV1().Secrets("").List(context.Background(), metav1.ListOptions{LabelSelector: helmSecretSelector})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list Helm release secrets")
+	}
+	configMaps, err := kubeClient.CoreV1().ConfigMaps("").List(context.Background(), metav1.ListOptions{LabelSelector: overridesCMSelector})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list override ConfigMaps")
+	}
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for i := range secrets.Items {
+		secret := secrets.Items[i]
+		if err := writeEntry(tw, path.Join(secretsDir, secret.Namespace, secret.Name+".json"), &secret); err != nil {
+			return nil, err
+		}
+	}
+	for i := range configMaps.Items {
+		cm := configMaps.Items[i]
+		if err := writeEntry(tw, path.Join(configMapsDir, cm.Namespace, cm.Name+".json"), &cm); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeEntry(tw *tar.Writer, name string, obj interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = tw.Write(data)
+	return err
+}
+
+func readArchive(data []byte) ([]*v1.Secret, []*v1.ConfigMap, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Release state archive is not gzipped")
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	var secrets []*v1.Secret
+	var configMaps []*v1.ConfigMap
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if header.Size > maxArchiveEntrySize {
+			return nil, nil, fmt.Errorf("archive entry '%s' exceeds the maximum size", header.Name)
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case strings.HasPrefix(header.Name, secretsDir+"/"):
+			secret := &v1.Secret{}
+			if err := json.Unmarshal(content, secret); err != nil {
+				return nil, nil, errors.Wrapf(err, "Invalid secret '%s' in archive", header.Name)
+			}
+			secrets = append(secrets, secret)
+		case strings.HasPrefix(header.Name, configMapsDir+"/"):
+			cm := &v1.ConfigMap{}
+			if err := json.Unmarshal(content, cm); err != nil {
+				return nil, nil, errors.Wrapf(err, "Invalid ConfigMap '%s' in archive", header.Name)
+			}
+			configMaps = append(configMaps, cm)
+		}
+	}
+	return secrets, configMaps, nil
+}
+
+//removeNewerRevisions deletes release secrets of releases in the archive which are not part of the archive
+func removeNewerRevisions(kubeClient kubernetes.Interface, secrets []*v1.Secret) error {
+	stored := make(map[string]bool)
+	releases := make(map[string]string) //release name -> namespace
+	for _, secret := range secrets {
+		stored[secret.Namespace+"/"+secret.Name] = true
+		if name, ok := secret.Labels[releaseNameLabel]; ok {
+			releases[name] = secret.Namespace
+		}
+	}
+
+	for name, namespace := range releases {
+		selector := fmt.Sprintf("%s=%s,%s=%s", releaseOwnerLabel, releaseOwnerHelm, releaseNameLabel, name)
+		current, err := kubeClient.CoreV1().Secrets(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return err
+		}
+		for _, secret := range current.Items {
+			if stored[secret.Namespace+"/"+secret.Name] {
+				continue
+			}
+			err := kubeClient.CoreV1().Secrets(namespace).Delete(context.Background(), secret.Name, metav1.DeleteOptions{})
+			if err != nil && !apierr.IsNotFound(err) {
+				return errors.Wrapf(err, "Failed to delete release secret '%s/%s'", namespace, secret.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func restoreSecret(kubeClient kubernetes.Interface, secret *v1.Secret) error {
+	secret.ResourceVersion = ""
+	secret.UID = ""
+	client := kubeClient.CoreV1().Secrets(secret.Namespace)
+	_, err := client.Create(context.Background(), secret, metav1.CreateOptions{})
+	if apierr.IsAlreadyExists(err) {
+		current, err := client.Get(context.Background(), secret.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		secret.ResourceVersion = current.ResourceVersion
+		_, err = client.Update(context.Background(), secret, metav1.UpdateOptions{})
+		return err
+	}
+	return err
+}
+
+func restoreConfigMap(kubeClient kubernetes.Interface, cm *v1.ConfigMap) error {
+	cm.ResourceVersion = ""
+	cm.UID = ""
+	client := kubeClient.CoreV1().ConfigMaps(cm.Namespace)
+	_, err := client.Create(context.Background(), cm, metav1.CreateOptions{})
+	if apierr.IsAlreadyExists(err) {
+		current, err := client.Get(context.Background(), cm.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		cm.ResourceVersion = current.ResourceVersion
+		_, err = client.Update(context.Background(), cm, metav1.UpdateOptions{})
+		return err
+	}
+	return err
+}
+
+//store uploads the archive to the target URL or writes it to a file named after the pattern and the current time in the target directory
+func store(httpClient *http.Client, target, filePattern string, data []byte) (string, error) {
+	if isURL(target) {
+		return target, upload(httpClient, target, data)
+	}
+	if err := os.MkdirAll(target, 0700); err != nil {
+		return "", err
+	}
+	file := filepath.Join(target, fmt.Sprintf(filePattern, time.Now().UTC().Format(archiveTimeLayout)))
+	if err := ioutil.WriteFile(file, data, 0600); err != nil {
+		return "", errors.Wrap(err, "Failed to write the archive")
+	}
+	return file, nil
+}
+
+//load downloads the archive from the source URL or reads it from the source file
+func load(httpClient *http.Client, source string) ([]byte, error) {
+	if isURL(source) {
+		return download(httpClient, source)
+	}
+	return ioutil.ReadFile(source)
+}
+
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+func upload(httpClient *http.Client, url string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", uploadContentType)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Failed to upload the archive")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Failed to upload the archive: %s", resp.Status)
+	}
+	return nil
+}
+
+func download(httpClient *http.Client, url string) ([]byte, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
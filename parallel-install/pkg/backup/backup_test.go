@@ -0,0 +1,123 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSnapshotAndRestore(t *testing.T) {
+
+	t.Run("Restore local archive", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "backup")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		kubeClient := fake.NewSimpleClientset(fixReleaseSecret("foo", 1, "rev1"), fixOverridesConfigMap("foo-overrides", "v1"))
+
+		archive, err := Snapshot(kubeClient, nil, dir)
+		require.NoError(t, err)
+		require.FileExists(t, archive)
+
+		simulateUpgrade(t, kubeClient)
+
+		err = RestoreReleaseState(kubeClient, nil, archive)
+		require.NoError(t, err)
+		verifyRestored(t, kubeClient)
+	})
+
+	t.Run("Restore uploaded archive", func(t *testing.T) {
+		var stored []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPut:
+				stored, _ = ioutil.ReadAll(r.Body)
+			case http.MethodGet:
+				_, _ = w.Write(stored)
+			}
+		}))
+		defer server.Close()
+
+		kubeClient := fake.NewSimpleClientset(fixReleaseSecret("foo", 1, "rev1"), fixOverridesConfigMap("foo-overrides", "v1"))
+
+		location, err := Snapshot(kubeClient, server.Client(), server.URL)
+		require.NoError(t, err)
+		require.Equal(t, server.URL, location)
+		require.NotEmpty(t, stored)
+
+		simulateUpgrade(t, kubeClient)
+
+		err = RestoreReleaseState(kubeClient, server.Client(), location)
+		require.NoError(t, err)
+		verifyRestored(t, kubeClient)
+	})
+
+	t.Run("Invalid archive", func(t *testing.T) {
+		file, err := ioutil.TempFile("", "backup")
+		require.NoError(t, err)
+		defer os.Remove(file.Name())
+		_, err = file.WriteString("not an archive")
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		err = RestoreReleaseState(fake.NewSimpleClientset(), nil, file.Name())
+		require.Error(t, err)
+	})
+}
+
+func simulateUpgrade(t *testing.T, kubeClient *fake.Clientset) {
+	_, err := kubeClient.CoreV1().Secrets("kyma-system").Create(context.Background(), fixReleaseSecret("foo", 2, "rev2"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = kubeClient.CoreV1().Secrets("kyma-system").Update(context.Background(), fixReleaseSecret("foo", 1, "superseded"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = kubeClient.CoreV1().ConfigMaps("kyma-installer").Update(context.Background(), fixOverridesConfigMap("foo-overrides", "v2"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func verifyRestored(t *testing.T, kubeClient *fake.Clientset) {
+	_, err := kubeClient.CoreV1().Secrets("kyma-system").Get(context.Background(), "sh.helm.release.v1.foo.v2", metav1.GetOptions{})
+	require.True(t, apierr.IsNotFound(err))
+
+	secret, err := kubeClient.CoreV1().Secrets("kyma-system").Get(context.Background(), "sh.helm.release.v1.foo.v1", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "rev1", string(secret.Data["release"]))
+
+	cm, err := kubeClient.CoreV1().ConfigMaps("kyma-installer").Get(context.Background(), "foo-overrides", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "v1", cm.Data["value"])
+}
+
+func fixReleaseSecret(release string, revision int, content string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", release, revision),
+			Namespace: "kyma-system",
+			Labels: map[string]string{
+				"owner": "helm",
+				"name":  release,
+			},
+		},
+		Data: map[string][]byte{"release": []byte(content)},
+	}
+}
+
+func fixOverridesConfigMap(name, value string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kyma-installer",
+			Labels:    map[string]string{"installer": "overrides"},
+		},
+		Data: map[string]string{"value": value},
+	}
+}
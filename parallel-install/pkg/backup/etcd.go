@@ -0,0 +1,170 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	defaultEtcdNamespace = "kube-system"
+	defaultEtcdSelector  = "component=etcd"
+	defaultEtcdContainer = "etcd"
+	defaultEtcdDirectory = "/var/lib/etcd"
+	defaultEtcdPKIDir    = "/etc/kubernetes/pki/etcd"
+	defaultEtcdTimeout   = 5 * time.Minute
+	etcdEndpoint         = "https://127.0.0.1:2379"
+	etcdIPv6Endpoint     = "https://[::1]:2379"
+	etcdSnapshotFileExt  = ".db"
+	etcdSnapshotPrefix   = "kyma-pre-upgrade-"
+)
+
+//PodExecFunc runs the command in the container of the Pod and returns its output
+type PodExecFunc func(ctx context.Context, namespace, pod, container string, command []string) (string, error)
+
+//NewPodExecutor returns a PodExecFunc which runs commands with the exec subresource of the Kubernetes API
+func NewPodExecutor(restConfig *rest.Config, kubeClient kubernetes.Interface) PodExecFunc {
+	return func(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+		req := kubeClient.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Namespace(namespace).
+			Name(pod).
+			SubResource("exec").
+			VersionedParams(&v1.PodExecOptions{
+				Container: container,
+				Command:   command,
+				Stdout:    true,
+				Stderr:    true,
+			}, scheme.ParameterCodec)
+		executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+		if err != nil {
+			return "", err
+		}
+
+		var output bytes.Buffer
+		done := make(chan error, 1)
+		go func() {
+			done <- executor.Stream(remotecommand.StreamOptions{Stdout: &output, Stderr: &output})
+		}()
+		select {
+		case err := <-done:
+			return output.String(), err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+//EtcdSnapshot saves a snapshot of etcd with etcdctl in an etcd Pod of the cluster
+type EtcdSnapshot struct {
+	kubeClient kubernetes.Interface
+	exec       PodExecFunc
+	cfg        config.EtcdSnapshotConfig
+	log        logger.Interface
+	now        func() time.Time
+}
+
+//NewEtcdSnapshot creates a new EtcdSnapshot instance
+func NewEtcdSnapshot(kubeClient kubernetes.Interface, exec PodExecFunc, cfg config.EtcdSnapshotConfig, log logger.Interface) *EtcdSnapshot {
+	if cfg.Namespace == "" {
+		cfg.Namespace = defaultEtcdNamespace
+	}
+	if cfg.Selector == "" {
+		cfg.Selector = defaultEtcdSelector
+	}
+	if cfg.Container == "" {
+		cfg.Container = defaultEtcdContainer
+	}
+	if cfg.Directory == "" {
+		cfg.Directory = defaultEtcdDirectory
+	}
+	if cfg.CACertFile == "" {
+		cfg.CACertFile = path.Join(defaultEtcdPKIDir, "ca.crt")
+	}
+	if cfg.CertFile == "" {
+		cfg.CertFile = path.Join(defaultEtcdPKIDir, "server.crt")
+	}
+	if cfg.KeyFile == "" {
+		cfg.KeyFile = path.Join(defaultEtcdPKIDir, "server.key")
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultEtcdTimeout
+	}
+	return &EtcdSnapshot{
+		kubeClient: kubeClient,
+		exec:       exec,
+		cfg:        cfg,
+		log:        log,
+		now:        time.Now,
+	}
+}
+
+//Run saves the snapshot and blocks until etcdctl finished.
+//It returns the location of the snapshot in the format '<node>:<file>', as the snapshot is stored on the node of the etcd Pod.
+//Restore it with 'etcdctl snapshot restore <file>' on that node.
+func (e *EtcdSnapshot) Run(ctx context.Context) (string, error) {
+	pod, err := e.etcdPod(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	file := path.Join(e.cfg.Directory, etcdSnapshotPrefix+e.now().UTC().Format(archiveTimeLayout)+etcdSnapshotFileExt)
+	command := []string{
+		"etcdctl",
+		"--endpoints=" + endpoint(pod),
+		"--cacert=" + e.cfg.CACertFile,
+		"--cert=" + e.cfg.CertFile,
+		"--key=" + e.cfg.KeyFile,
+		"snapshot", "save", file,
+	}
+
+	e.log.Infof("Saving etcd snapshot '%s' in Pod '%s/%s'", file, pod.Namespace, pod.Name)
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+	output, err := e.exec(ctx, pod.Namespace, pod.Name, e.cfg.Container, command)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to save etcd snapshot: %s", strings.TrimSpace(output))
+	}
+	return fmt.Sprintf("%s:%s", pod.Spec.NodeName, file), nil
+}
+
+//etcdPod returns the first running etcd Pod by name, so that snapshots of consecutive upgrades are stored on the same node
+func (e *EtcdSnapshot) etcdPod(ctx context.Context) (*v1.Pod, error) {
+	pods, err := e.kubeClient.CoreV1().Pods(e.cfg.Namespace).List(ctx, metav1.ListOptions{LabelSelector: e.cfg.Selector})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list etcd Pods")
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == v1.PodRunning {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("No running etcd Pod with selector '%s' found in namespace '%s', etcd snapshots require a cluster whose etcd runs as Pods", e.cfg.Selector, e.cfg.Namespace)
+}
+
+//endpoint returns the local client URL of etcd. etcd listens on the loopback address of the IP family of its Pod,
+//so IPv6-only clusters have no etcd endpoint on 127.0.0.1.
+func endpoint(pod *v1.Pod) string {
+	if ip := net.ParseIP(pod.Status.PodIP); ip != nil && ip.To4() == nil {
+		return etcdIPv6Endpoint
+	}
+	return etcdEndpoint
+}
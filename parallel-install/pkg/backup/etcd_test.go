@@ -0,0 +1,99 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEtcdSnapshot_Run(t *testing.T) {
+
+	t.Run("Snapshot is saved in the first running etcd Pod", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(
+			fixEtcdPod("etcd-master-2", "master-2", v1.PodRunning),
+			fixEtcdPod("etcd-master-1", "master-1", v1.PodRunning),
+			fixEtcdPod("etcd-master-0", "master-0", v1.PodPending),
+		)
+		var execPod, execContainer string
+		var execCommand []string
+		exec := func(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+			execPod, execContainer, execCommand = namespace+"/"+pod, container, command
+			return "Snapshot saved", nil
+		}
+
+		location, err := newTestEtcdSnapshot(kubeClient, exec).Run(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "master-1:/var/lib/etcd/kyma-pre-upgrade-20210301-120000.db", location)
+		require.Equal(t, "kube-system/etcd-master-1", execPod)
+		require.Equal(t, "etcd", execContainer)
+		require.Equal(t, []string{
+			"etcdctl",
+			"--endpoints=https://127.0.0.1:2379",
+			"--cacert=/etc/kubernetes/pki/etcd/ca.crt",
+			"--cert=/etc/kubernetes/pki/etcd/server.crt",
+			"--key=/etc/kubernetes/pki/etcd/server.key",
+			"snapshot", "save", "/var/lib/etcd/kyma-pre-upgrade-20210301-120000.db",
+		}, execCommand)
+	})
+
+	t.Run("IPv6-only cluster", func(t *testing.T) {
+		pod := fixEtcdPod("etcd-master-0", "master-0", v1.PodRunning)
+		pod.Status.PodIP = "2001:db8::10"
+		var execCommand []string
+		exec := func(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+			execCommand = command
+			return "Snapshot saved", nil
+		}
+
+		_, err := newTestEtcdSnapshot(fake.NewSimpleClientset(pod), exec).Run(context.Background())
+		require.NoError(t, err)
+		require.Contains(t, execCommand, "--endpoints=https://[::1]:2379")
+	})
+
+	t.Run("Failed etcdctl", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(fixEtcdPod("etcd-master-0", "master-0", v1.PodRunning))
+		exec := func(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+			return "Error: context deadline exceeded\n", fmt.Errorf("command terminated with exit code 1")
+		}
+
+		_, err := newTestEtcdSnapshot(kubeClient, exec).Run(context.Background())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "context deadline exceeded")
+	})
+
+	t.Run("No etcd Pod", func(t *testing.T) {
+		exec := func(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+			t.Fatal("etcdctl must not be executed")
+			return "", nil
+		}
+
+		_, err := newTestEtcdSnapshot(fake.NewSimpleClientset(), exec).Run(context.Background())
+		require.Error(t, err)
+	})
+}
+
+func newTestEtcdSnapshot(kubeClient *fake.Clientset, exec PodExecFunc) *EtcdSnapshot {
+	etcdSnapshot := NewEtcdSnapshot(kubeClient, exec, config.EtcdSnapshotConfig{}, logger.NewLogger(true))
+	etcdSnapshot.now = func() time.Time { return testBackupTime }
+	return etcdSnapshot
+}
+
+func fixEtcdPod(name, node string, phase v1.PodPhase) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kube-system",
+			Labels:    map[string]string{"component": "etcd"},
+		},
+		Spec:   v1.PodSpec{NodeName: node},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
@@ -0,0 +1,152 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	resourcesDir         = "resources"
+	resourcesFilePattern = "user-resources-%s.tgz"
+	managedByLabel       = "app.kubernetes.io/managed-by"
+	managedByHelm        = "Helm"
+)
+
+//DefaultUserResources are the Kyma custom resources which are typically created by users
+var DefaultUserResources = []schema.GroupVersionResource{
+	{Group: "serverless.kyma-project.io", Version: "v1alpha1", Resource: "functions"},
+	{Group: "gateway.kyma-project.io", Version: "v1alpha1", Resource: "apirules"},
+	{Group: "eventing.kyma-project.io", Version: "v1alpha1", Resource: "subscriptions"},
+	{Group: "servicecatalog.k8s.io", Version: "v1beta1", Resource: "serviceinstances"},
+}
+
+//ExportResources stores the custom resources created by users in the target, so that they can be imported after Kyma is reinstalled.
+//The target is a local directory or an HTTP(S) URL the archive is uploaded to with the HTTP client, like for Snapshot. Resources which are owned by other objects or deployed by Helm are skipped,
+//as they are recreated by their owners. Resource types whose CRD is not installed are skipped as well.
+//It returns the location of the archive and the number of exported resources.
+func ExportResources(dynamicClient dynamic.Interface, httpClient *http.Client, resources []schema.GroupVersionResource, target string) (string, int, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	var count int
+	for _, gvr := range resources {
+		list, err := dynamicClient.Resource(gvr).Namespace(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			if apierr.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return "", 0, errors.Wrapf(err, "Failed to list %s", gvr.GroupResource())
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if !userCreated(obj) {
+				continue
+			}
+			name := path.Join(resourcesDir, gvr.Group, gvr.Version, gvr.Resource, obj.GetNamespace(), obj.GetName()+".json")
+			if err := writeEntry(tw, name, portable(obj)); err != nil {
+				return "", 0, err
+			}
+			count++
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return "", 0, err
+	}
+	if err := gw.Close(); err != nil {
+		return "", 0, err
+	}
+	location, err := store(httpClient, target, resourcesFilePattern, buf.Bytes())
+	return location, count, err
+}
+
+//ImportResources creates the custom resources stored by ExportResources. The source is the archive file or URL returned by the export.
+//Resources which exist already are left untouched, as they might have been recreated by users in the meantime.
+//It returns the number of created resources.
+func ImportResources(dynamicClient dynamic.Interface, httpClient *http.Client, source string) (int, error) {
+	data, err := load(httpClient, source)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to read the user resources archive")
+	}
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return 0, errors.Wrap(err, "User resources archive is not gzipped")
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	var count int
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return count, err
+		}
+		if header.Size > maxArchiveEntrySize {
+			return count, fmt.Errorf("archive entry '%s' exceeds the maximum size", header.Name)
+		}
+		gvr, ok := entryResource(header.Name)
+		if !ok {
+			continue
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return count, err
+		}
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(content, &obj.Object); err != nil {
+			return count, errors.Wrapf(err, "Invalid resource '%s' in archive", header.Name)
+		}
+		_, err = dynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(context.Background(), obj, metav1.CreateOptions{})
+		if apierr.IsAlreadyExists(err) {
+			continue
+		}
+		if err != nil {
+			return count, errors.Wrapf(err, "Failed to create %s '%s/%s'", gvr.GroupResource(), obj.GetNamespace(), obj.GetName())
+		}
+		count++
+	}
+	return count, nil
+}
+
+//userCreated returns false for resources which are owned by other objects or deployed by Helm
+func userCreated(obj *unstructured.Unstructured) bool {
+	return len(obj.GetOwnerReferences()) == 0 && obj.GetLabels()[managedByLabel] != managedByHelm
+}
+
+//portable removes the status and the metadata which is assigned by the API server
+func portable(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range []string{"resourceVersion", "uid", "selfLink", "creationTimestamp", "generation", "managedFields"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	return obj
+}
+
+//entryResource returns the resource type of an archive entry named resources/<group>/<version>/<resource>/<namespace>/<name>.json
+func entryResource(name string) (schema.GroupVersionResource, bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 6 || parts[0] != resourcesDir {
+		return schema.GroupVersionResource{}, false
+	}
+	return schema.GroupVersionResource{Group: parts[1], Version: parts[2], Resource: parts[3]}, true
+}
@@ -0,0 +1,95 @@
+package backup
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+var (
+	functions = schema.GroupVersionResource{Group: "serverless.kyma-project.io", Version: "v1alpha1", Resource: "functions"}
+	apiRules  = schema.GroupVersionResource{Group: "gateway.kyma-project.io", Version: "v1alpha1", Resource: "apirules"}
+)
+
+func TestExportAndImportResources(t *testing.T) {
+
+	t.Run("Import exported user resources", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "resources")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		source := fake.NewSimpleDynamicClient(runtime.NewScheme(),
+			fixResource("serverless.kyma-project.io/v1alpha1", "Function", "default", "orders", nil),
+			fixResource("gateway.kyma-project.io/v1alpha1", "APIRule", "default", "orders", nil),
+			fixResource("gateway.kyma-project.io/v1alpha1", "APIRule", "kyma-system", "console", map[string]interface{}{"app.kubernetes.io/managed-by": "Helm"}),
+		)
+
+		archive, count, err := ExportResources(source, nil, []schema.GroupVersionResource{functions, apiRules}, dir)
+		require.NoError(t, err)
+		require.FileExists(t, archive)
+		require.Equal(t, 2, count)
+
+		target := fake.NewSimpleDynamicClient(runtime.NewScheme())
+		count, err = ImportResources(target, nil, archive)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+
+		function, err := target.Resource(functions).Namespace("default").Get(context.Background(), "orders", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "nodejs14", function.Object["spec"].(map[string]interface{})["runtime"])
+		require.Empty(t, function.GetResourceVersion())
+		_, found := function.Object["status"]
+		require.False(t, found)
+
+		_, err = target.Resource(apiRules).Namespace("default").Get(context.Background(), "orders", metav1.GetOptions{})
+		require.NoError(t, err)
+		_, err = target.Resource(apiRules).Namespace("kyma-system").Get(context.Background(), "console", metav1.GetOptions{})
+		require.Error(t, err)
+
+		//existing resources are kept
+		count, err = ImportResources(target, nil, archive)
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+	})
+
+	t.Run("Invalid archive", func(t *testing.T) {
+		file, err := ioutil.TempFile("", "resources")
+		require.NoError(t, err)
+		defer os.Remove(file.Name())
+		_, err = file.WriteString("not an archive")
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		_, err = ImportResources(fake.NewSimpleDynamicClient(runtime.NewScheme()), nil, file.Name())
+		require.Error(t, err)
+	})
+}
+
+func fixResource(apiVersion, kind, namespace, name string, labels map[string]interface{}) *unstructured.Unstructured {
+	metadata := map[string]interface{}{
+		"name":            name,
+		"namespace":       namespace,
+		"resourceVersion": "42",
+		"uid":             "3f2a",
+	}
+	if labels != nil {
+		metadata["labels"] = labels
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata":   metadata,
+			"spec":       map[string]interface{}{"runtime": "nodejs14"},
+			"status":     map[string]interface{}{"phase": "Running"},
+		},
+	}
+}
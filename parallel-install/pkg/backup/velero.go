@@ -0,0 +1,142 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	defaultVeleroNamespace = "velero"
+	defaultVeleroTimeout   = 30 * time.Minute
+	veleroPollInterval     = 5 * time.Second
+	veleroBackupPrefix     = "kyma-pre-upgrade-"
+	veleroScheduleLabel    = "velero.io/schedule-name"
+)
+
+var (
+	veleroBackupResource   = schema.GroupVersionResource{Group: "velero.io", Version: "v1", Resource: "backups"}
+	veleroScheduleResource = schema.GroupVersionResource{Group: "velero.io", Version: "v1", Resource: "schedules"}
+)
+
+//VeleroBackup triggers a Velero backup and waits for its completion
+type VeleroBackup struct {
+	dynamicClient dynamic.Interface
+	cfg           config.VeleroConfig
+	log           logger.Interface
+	pollInterval  time.Duration
+	now           func() time.Time
+}
+
+//NewVeleroBackup creates a new VeleroBackup instance
+func NewVeleroBackup(dynamicClient dynamic.Interface, cfg config.VeleroConfig, log logger.Interface) *VeleroBackup {
+	if cfg.Namespace == "" {
+		cfg.Namespace = defaultVeleroNamespace
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultVeleroTimeout
+	}
+	return &VeleroBackup{
+		dynamicClient: dynamicClient,
+		cfg:           cfg,
+		log:           log,
+		pollInterval:  veleroPollInterval,
+		now:           time.Now,
+	}
+}
+
+//Run creates the Velero backup and blocks until Velero reports it as completed.
+//It returns the name of the backup, which can be passed to 'velero restore create --from-backup'.
+func (v *VeleroBackup) Run(ctx context.Context) (string, error) {
+	spec, labels, err := v.backupSpec(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	name := veleroBackupPrefix + v.now().UTC().Format(archiveTimeLayout)
+	backup := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "Backup",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": v.cfg.Namespace,
+		},
+		"spec": spec,
+	}}
+	backup.SetLabels(labels)
+
+	client := v.dynamicClient.Resource(veleroBackupResource).Namespace(v.cfg.Namespace)
+	if _, err := client.Create(ctx, backup, metav1.CreateOptions{}); err != nil {
+		return "", errors.Wrap(err, "Failed to create Velero backup")
+	}
+	v.log.Infof("Waiting for Velero backup '%s' to complete", name)
+
+	err = wait.PollImmediate(v.pollInterval, v.cfg.Timeout, func() (bool, error) {
+		current, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		phase, _, _ := unstructured.NestedString(current.Object, "status", "phase")
+		switch phase {
+		case "Completed":
+			return true, nil
+		case "Failed", "PartiallyFailed", "FailedValidation":
+			return false, fmt.Errorf("Velero backup '%s' finished with phase %s", name, phase)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "Velero backup '%s' did not complete", name)
+	}
+	return name, nil
+}
+
+//backupSpec returns the spec and labels of the backup, taken from the schedule template if a schedule is configured
+func (v *VeleroBackup) backupSpec(ctx context.Context) (map[string]interface{}, map[string]string, error) {
+	if v.cfg.Schedule != "" {
+		schedule, err := v.dynamicClient.Resource(veleroScheduleResource).Namespace(v.cfg.Namespace).Get(ctx, v.cfg.Schedule, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to get Velero schedule '%s'", v.cfg.Schedule)
+		}
+		template, _, err := unstructured.NestedMap(schedule.Object, "spec", "template")
+		if err != nil {
+			return nil, nil, err
+		}
+		if template == nil {
+			template = map[string]interface{}{}
+		}
+		return template, map[string]string{veleroScheduleLabel: v.cfg.Schedule}, nil
+	}
+
+	spec := map[string]interface{}{}
+	if len(v.cfg.IncludedNamespaces) > 0 {
+		namespaces := make([]interface{}, 0, len(v.cfg.IncludedNamespaces))
+		for _, ns := range v.cfg.IncludedNamespaces {
+			namespaces = append(namespaces, ns)
+		}
+		spec["includedNamespaces"] = namespaces
+	}
+	if len(v.cfg.LabelSelector) > 0 {
+		matchLabels := make(map[string]interface{}, len(v.cfg.LabelSelector))
+		for k, val := range v.cfg.LabelSelector {
+			matchLabels[k] = val
+		}
+		spec["labelSelector"] = map[string]interface{}{"matchLabels": matchLabels}
+	}
+	if v.cfg.StorageLocation != "" {
+		spec["storageLocation"] = v.cfg.StorageLocation
+	}
+	if v.cfg.TTL != 0 {
+		spec["ttl"] = v.cfg.TTL.String()
+	}
+	return spec, nil, nil
+}
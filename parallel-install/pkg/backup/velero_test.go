@@ -0,0 +1,114 @@
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestVeleroBackup_Run(t *testing.T) {
+
+	t.Run("Backup with selector completes", func(t *testing.T) {
+		dynamicClient := newVeleroFakeClient()
+		veleroBackup := newTestVeleroBackup(dynamicClient, config.VeleroConfig{
+			IncludedNamespaces: []string{"kyma-system"},
+			LabelSelector:      map[string]string{"app": "kyma"},
+			TTL:                time.Hour,
+		})
+		go completeBackups(dynamicClient, "Completed")
+
+		name, err := veleroBackup.Run(context.Background())
+		require.NoError(t, err)
+
+		backup := getBackup(t, dynamicClient, name)
+		namespaces, _, _ := unstructured.NestedStringSlice(backup.Object, "spec", "includedNamespaces")
+		require.Equal(t, []string{"kyma-system"}, namespaces)
+		selector, _, _ := unstructured.NestedStringMap(backup.Object, "spec", "labelSelector", "matchLabels")
+		require.Equal(t, map[string]string{"app": "kyma"}, selector)
+		ttl, _, _ := unstructured.NestedString(backup.Object, "spec", "ttl")
+		require.Equal(t, "1h0m0s", ttl)
+	})
+
+	t.Run("Backup from schedule", func(t *testing.T) {
+		schedule := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "velero.io/v1",
+			"kind":       "Schedule",
+			"metadata":   map[string]interface{}{"name": "daily", "namespace": defaultVeleroNamespace},
+			"spec": map[string]interface{}{
+				"schedule": "@daily",
+				"template": map[string]interface{}{"includedNamespaces": []interface{}{"*"}},
+			},
+		}}
+		dynamicClient := newVeleroFakeClient(schedule)
+		veleroBackup := newTestVeleroBackup(dynamicClient, config.VeleroConfig{Schedule: "daily"})
+		go completeBackups(dynamicClient, "Completed")
+
+		name, err := veleroBackup.Run(context.Background())
+		require.NoError(t, err)
+
+		backup := getBackup(t, dynamicClient, name)
+		require.Equal(t, "daily", backup.GetLabels()[veleroScheduleLabel])
+		namespaces, _, _ := unstructured.NestedStringSlice(backup.Object, "spec", "includedNamespaces")
+		require.Equal(t, []string{"*"}, namespaces)
+	})
+
+	t.Run("Failed backup", func(t *testing.T) {
+		dynamicClient := newVeleroFakeClient()
+		veleroBackup := newTestVeleroBackup(dynamicClient, config.VeleroConfig{})
+		go completeBackups(dynamicClient, "PartiallyFailed")
+
+		_, err := veleroBackup.Run(context.Background())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "PartiallyFailed")
+	})
+
+	t.Run("Missing schedule", func(t *testing.T) {
+		veleroBackup := newTestVeleroBackup(newVeleroFakeClient(), config.VeleroConfig{Schedule: "daily"})
+
+		_, err := veleroBackup.Run(context.Background())
+		require.Error(t, err)
+	})
+}
+
+func newVeleroFakeClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+}
+
+func newTestVeleroBackup(dynamicClient *fake.FakeDynamicClient, cfg config.VeleroConfig) *VeleroBackup {
+	cfg.Timeout = 2 * time.Second
+	veleroBackup := NewVeleroBackup(dynamicClient, cfg, logger.NewLogger(true))
+	veleroBackup.pollInterval = 10 * time.Millisecond
+	veleroBackup.now = func() time.Time { return testBackupTime }
+	return veleroBackup
+}
+
+var testBackupTime = time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+//completeBackups simulates Velero by setting the phase of the backup once it appears
+func completeBackups(dynamicClient *fake.FakeDynamicClient, phase string) {
+	client := dynamicClient.Resource(veleroBackupResource).Namespace(defaultVeleroNamespace)
+	name := veleroBackupPrefix + testBackupTime.Format(archiveTimeLayout)
+	for i := 0; i < 100; i++ {
+		backup, err := client.Get(context.Background(), name, metav1.GetOptions{})
+		if err == nil {
+			_ = unstructured.SetNestedField(backup.Object, phase, "status", "phase")
+			_, _ = client.Update(context.Background(), backup, metav1.UpdateOptions{})
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func getBackup(t *testing.T, dynamicClient *fake.FakeDynamicClient, name string) *unstructured.Unstructured {
+	backup, err := dynamicClient.Resource(veleroBackupResource).Namespace(defaultVeleroNamespace).Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return backup
+}
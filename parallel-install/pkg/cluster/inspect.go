@@ -0,0 +1,400 @@
+//Package cluster inspects the Kubernetes cluster Kyma is installed on.
+//
+//The detected capabilities, such as the cloud provider, the CNI, or existing Istio and Knative CRDs,
+//let downstream logic like profiles and override interceptors adjust to the cluster.
+package cluster
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+//Provider of the cluster
+type Provider string
+
+//Providers detected by the inspection
+const (
+	ProviderUnknown   Provider = "unknown"
+	ProviderGardener  Provider = "gardener"
+	ProviderGKE       Provider = "gke"
+	ProviderEKS       Provider = "eks"
+	ProviderAKS       Provider = "aks"
+	ProviderOpenShift Provider = "openshift"
+	ProviderK3d       Provider = "k3d"
+	ProviderK3s       Provider = "k3s"
+	ProviderKind      Provider = "kind"
+	ProviderMinikube  Provider = "minikube"
+)
+
+const (
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	defaultStorageClassBetaAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+//Info describes the capabilities of a cluster
+type Info struct {
+	//Kubernetes version of the API server, e.g. v1.20.2
+	KubernetesVersion string
+	Provider          Provider
+	//GKEAutopilot is true for GKE clusters in Autopilot mode
+	GKEAutopilot bool
+	//CNI plugin, e.g. calico or cilium. Empty if not detected.
+	CNI     string
+	Ingress IngressCapabilities
+	//Name of the default storage class. Empty if there is none.
+	DefaultStorageClass string
+	//IstioCRDs is true if API groups of Istio, such as networking.istio.io, are served
+	IstioCRDs bool
+	//KnativeCRDs is true if API groups of Knative, such as serving.knative.dev, are served
+	KnativeCRDs bool
+	//IP families of the cluster network, the primary family first, e.g. IPv6 and IPv4 for a dual-stack cluster
+	//whose primary family is IPv6. Empty if not detected.
+	IPFamilies []v1.IPFamily
+}
+
+//IngressCapabilities describes how workloads can be exposed
+type IngressCapabilities struct {
+	//LoadBalancer is true if services of type LoadBalancer get an external address
+	LoadBalancer bool
+	//Names of the ingress classes
+	IngressClasses []string
+	//Name of the default ingress class. Empty if there is none.
+	DefaultIngressClass string
+}
+
+//Fact returns the value of a capability by name, so that conditions like skip conditions of components can refer to it.
+//The names are provider, kubernetesVersion, gkeAutopilot, cni, loadBalancer, defaultIngressClass, defaultStorageClass, istioCRDs, knativeCRDs,
+//ipFamilies, and dualStack. The IP families are comma-separated, e.g. IPv6,IPv4.
+func (i *Info) Fact(name string) (interface{}, bool) {
+	switch name {
+	case "provider":
+		return string(i.Provider), true
+	case "kubernetesVersion":
+		return i.KubernetesVersion, true
+	case "gkeAutopilot":
+		return i.GKEAutopilot, true
+	case "cni":
+		return i.CNI, true
+	case "loadBalancer":
+		return i.Ingress.LoadBalancer, true
+	case "defaultIngressClass":
+		return i.Ingress.DefaultIngressClass, true
+	case "defaultStorageClass":
+		return i.DefaultStorageClass, true
+	case "istioCRDs":
+		return i.IstioCRDs, true
+	case "knativeCRDs":
+		return i.KnativeCRDs, true
+	case "ipFamilies":
+		families := make([]string, 0, len(i.IPFamilies))
+		for _, family := range i.IPFamilies {
+			families = append(families, string(family))
+		}
+		return strings.Join(families, ","), true
+	case "dualStack":
+		return i.DualStack(), true
+	}
+	return nil, false
+}
+
+//DualStack returns true if the cluster network has IPv4 and IPv6 addresses
+func (i *Info) DualStack() bool {
+	return len(i.IPFamilies) > 1
+}
+
+//HasIPFamily returns true if the cluster network has addresses of the IP family
+func (i *Info) HasIPFamily(family v1.IPFamily) bool {
+	for _, f := range i.IPFamilies {
+		if f == family {
+			return true
+		}
+	}
+	return false
+}
+
+//Inspect connects to the cluster of the kubeconfig and detects its capabilities
+func Inspect(kubeconfig config.KubeconfigSource) (*Info, error) {
+	restConfig, err := config.RestConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return InspectClient(kubeClient)
+}
+
+//InspectClient detects the capabilities of the cluster of the client.
+//Capabilities which cannot be read due to missing permissions are left empty.
+func InspectClient(kubeClient kubernetes.Interface) (*Info, error) {
+	version, err := kubeClient.Discovery().ServerVersion()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the Kubernetes version")
+	}
+	info := &Info{KubernetesVersion: version.GitVersion}
+
+	groups, err := apiGroups(kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	info.IstioCRDs = hasGroupSuffix(groups, "istio.io")
+	info.KnativeCRDs = hasGroupSuffix(groups, "knative.dev")
+
+	if info.Provider, info.GKEAutopilot, err = detectProvider(kubeClient, groups); err != nil {
+		return nil, err
+	}
+	if info.CNI, err = detectCNI(kubeClient, info.Provider); err != nil {
+		return nil, err
+	}
+	if info.Ingress, err = detectIngress(kubeClient, info.Provider); err != nil {
+		return nil, err
+	}
+	if info.DefaultStorageClass, err = detectDefaultStorageClass(kubeClient); err != nil {
+		return nil, err
+	}
+	if info.IPFamilies, err = detectIPFamilies(kubeClient); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func apiGroups(kubeClient kubernetes.Interface) ([]string, error) {
+	groupList, err := kubeClient.Discovery().ServerGroups()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the API groups")
+	}
+	var groups []string
+	for _, group := range groupList.Groups {
+		groups = append(groups, group.Name)
+	}
+	return groups, nil
+}
+
+func hasGroupSuffix(groups []string, suffix string) bool {
+	for _, group := range groups {
+		if group == suffix || strings.HasSuffix(group, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func detectProvider(kubeClient kubernetes.Interface, groups []string) (provider Provider, autopilot bool, err error) {
+	_, err = kubeClient.CoreV1().ConfigMaps("kube-system").Get(context.Background(), "shoot-info", metav1.GetOptions{})
+	if err == nil {
+		return ProviderGardener, false, nil
+	}
+	if !ignorable(err) {
+		return ProviderUnknown, false, err
+	}
+	if hasGroupSuffix(groups, "openshift.io") {
+		return ProviderOpenShift, false, nil
+	}
+
+	nodes, err := kubeClient.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		if ignorable(err) {
+			return ProviderUnknown, false, nil
+		}
+		return ProviderUnknown, false, err
+	}
+	for _, node := range nodes.Items {
+		if provider := nodeProvider(node); provider != ProviderUnknown {
+			//Autopilot nodes are named gk3-<cluster>-<pool>-<id>
+			return provider, provider == ProviderGKE && strings.HasPrefix(node.Name, "gk3-"), nil
+		}
+	}
+	return ProviderUnknown, false, nil
+}
+
+func nodeProvider(node v1.Node) Provider {
+	labels := node.GetLabels()
+	providerID := node.Spec.ProviderID
+	switch {
+	case strings.HasPrefix(node.Name, "k3d-"):
+		return ProviderK3d
+	case strings.HasPrefix(providerID, "k3s://"):
+		return ProviderK3s
+	case strings.HasPrefix(providerID, "kind://"):
+		return ProviderKind
+	case labels["minikube.k8s.io/name"] != "":
+		return ProviderMinikube
+	case labels["cloud.google.com/gke-nodepool"] != "":
+		return ProviderGKE
+	case labels["eks.amazonaws.com/nodegroup"] != "" || labels["alpha.eksctl.io/cluster-name"] != "":
+		return ProviderEKS
+	case labels["kubernetes.azure.com/cluster"] != "":
+		return ProviderAKS
+	}
+	return ProviderUnknown
+}
+
+//cniDaemonSets maps name prefixes of the DaemonSets in kube-system to the CNI they belong to
+var cniDaemonSets = []struct {
+	prefix string
+	cni    string
+}{
+	{"calico-node", "calico"},
+	{"canal", "canal"},
+	{"cilium", "cilium"},
+	{"kube-flannel", "flannel"},
+	{"weave-net", "weave"},
+	{"aws-node", "aws-vpc-cni"},
+	{"azure-cns", "azure"},
+	{"kindnet", "kindnet"},
+	{"antrea-agent", "antrea"},
+}
+
+func detectCNI(kubeClient kubernetes.Interface, provider Provider) (string, error) {
+	daemonSets, err := kubeClient.AppsV1().DaemonSets("kube-system").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		if ignorable(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, cni := range cniDaemonSets {
+		for _, ds := range daemonSets.Items {
+			if strings.HasPrefix(ds.Name, cni.prefix) {
+				return cni.cni, nil
+			}
+		}
+	}
+	switch provider {
+	case ProviderK3d, ProviderK3s:
+		//k3s embeds flannel without a DaemonSet
+		return "flannel", nil
+	case ProviderOpenShift:
+		return "openshift-sdn", nil
+	}
+	return "", nil
+}
+
+func detectIngress(kubeClient kubernetes.Interface, provider Provider) (IngressCapabilities, error) {
+	var ingress IngressCapabilities
+
+	classes, err := kubeClient.NetworkingV1().IngressClasses().List(context.Background(), metav1.ListOptions{})
+	if err != nil && !ignorable(err) {
+		return ingress, err
+	}
+	if err == nil {
+		for _, class := range classes.Items {
+			ingress.IngressClasses = append(ingress.IngressClasses, class.Name)
+			if class.Annotations["ingressclass.kubernetes.io/is-default-class"] == "true" {
+				ingress.DefaultIngressClass = class.Name
+			}
+		}
+	}
+
+	switch provider {
+	case ProviderGardener, ProviderGKE, ProviderEKS, ProviderAKS, ProviderK3d, ProviderK3s:
+		ingress.LoadBalancer = true
+		return ingress, nil
+	}
+	//other clusters support load balancers if an existing one got an address
+	services, err := kubeClient.CoreV1().Services("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		if ignorable(err) {
+			return ingress, nil
+		}
+		return ingress, err
+	}
+	for _, svc := range services.Items {
+		if svc.Spec.Type == v1.ServiceTypeLoadBalancer && len(svc.Status.LoadBalancer.Ingress) > 0 {
+			ingress.LoadBalancer = true
+			break
+		}
+	}
+	return ingress, nil
+}
+
+func detectDefaultStorageClass(kubeClient kubernetes.Interface) (string, error) {
+	classes, err := kubeClient.StorageV1().StorageClasses().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		if ignorable(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, class := range classes.Items {
+		if class.Annotations[defaultStorageClassAnnotation] == "true" || class.Annotations[defaultStorageClassBetaAnnotation] == "true" {
+			return class.Name, nil
+		}
+	}
+	return "", nil
+}
+
+//detectIPFamilies reads the IP families of the Pod CIDRs of a node, or of its internal IPs if the CNI does not use the Pod CIDRs.
+//The cluster IP of the kubernetes Service is the last resort, as it only reveals the primary family.
+func detectIPFamilies(kubeClient kubernetes.Interface) ([]v1.IPFamily, error) {
+	nodes, err := kubeClient.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil && !ignorable(err) {
+		return nil, err
+	}
+	if err == nil {
+		for _, node := range nodes.Items {
+			cidrs := node.Spec.PodCIDRs
+			if len(cidrs) == 0 && node.Spec.PodCIDR != "" {
+				cidrs = []string{node.Spec.PodCIDR}
+			}
+			if families := ipFamilies(cidrs); len(families) > 0 {
+				return families, nil
+			}
+			var addresses []string
+			for _, addr := range node.Status.Addresses {
+				if addr.Type == v1.NodeInternalIP {
+					addresses = append(addresses, addr.Address)
+				}
+			}
+			if families := ipFamilies(addresses); len(families) > 0 {
+				return families, nil
+			}
+		}
+	}
+
+	svc, err := kubeClient.CoreV1().Services("default").Get(context.Background(), "kubernetes", metav1.GetOptions{})
+	if err != nil {
+		if ignorable(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	clusterIPs := svc.Spec.ClusterIPs
+	if len(clusterIPs) == 0 {
+		clusterIPs = []string{svc.Spec.ClusterIP}
+	}
+	return ipFamilies(clusterIPs), nil
+}
+
+//ipFamilies returns the distinct IP families of the addresses or CIDRs in their order. Invalid addresses are ignored.
+func ipFamilies(addresses []string) []v1.IPFamily {
+	var families []v1.IPFamily
+	for _, address := range addresses {
+		ip := net.ParseIP(strings.SplitN(address, "/", 2)[0])
+		if ip == nil {
+			continue
+		}
+		family := v1.IPv6Protocol
+		if ip.To4() != nil {
+			family = v1.IPv4Protocol
+		}
+		if len(families) == 0 || (len(families) == 1 && families[0] != family) {
+			families = append(families, family)
+		}
+	}
+	return families
+}
+
+//ignorable returns true for errors caused by missing permissions or resources, which leave a capability undetected
+func ignorable(err error) bool {
+	return apierr.IsNotFound(err) || apierr.IsForbidden(err) || apierr.IsUnauthorized(err)
+}
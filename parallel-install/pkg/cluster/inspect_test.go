@@ -0,0 +1,138 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestInspectClient(t *testing.T) {
+
+	t.Run("GKE cluster with Istio", func(t *testing.T) {
+		kubeClient := newFakeClient([]string{"networking.istio.io/v1alpha3", "security.istio.io/v1beta1"},
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gke-node", Labels: map[string]string{"cloud.google.com/gke-nodepool": "default"}}},
+			&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "calico-node", Namespace: "kube-system"}},
+			&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard", Annotations: map[string]string{defaultStorageClassAnnotation: "true"}}},
+			&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "premium"}},
+			&networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: "gce", Annotations: map[string]string{"ingressclass.kubernetes.io/is-default-class": "true"}}},
+		)
+
+		info, err := InspectClient(kubeClient)
+		require.NoError(t, err)
+		require.Equal(t, "v1.20.2", info.KubernetesVersion)
+		require.Equal(t, ProviderGKE, info.Provider)
+		require.Equal(t, "calico", info.CNI)
+		require.Equal(t, "standard", info.DefaultStorageClass)
+		require.True(t, info.Ingress.LoadBalancer)
+		require.Equal(t, []string{"gce"}, info.Ingress.IngressClasses)
+		require.Equal(t, "gce", info.Ingress.DefaultIngressClass)
+		require.True(t, info.IstioCRDs)
+		require.False(t, info.KnativeCRDs)
+		require.Empty(t, info.IPFamilies)
+	})
+
+	t.Run("Dual-stack cluster", func(t *testing.T) {
+		kubeClient := newFakeClient(nil,
+			&v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node"},
+				Spec:       v1.NodeSpec{PodCIDR: "fd00:10:244::/64", PodCIDRs: []string{"fd00:10:244::/64", "10.244.0.0/24"}},
+			},
+		)
+
+		info, err := InspectClient(kubeClient)
+		require.NoError(t, err)
+		require.Equal(t, []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol}, info.IPFamilies)
+		require.True(t, info.DualStack())
+		families, _ := info.Fact("ipFamilies")
+		require.Equal(t, "IPv6,IPv4", families)
+	})
+
+	t.Run("IP families of the node addresses and the kubernetes Service", func(t *testing.T) {
+		kubeClient := newFakeClient(nil,
+			&v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node"},
+				Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "2001:db8::10"}}},
+			},
+		)
+		info, err := InspectClient(kubeClient)
+		require.NoError(t, err)
+		require.Equal(t, []v1.IPFamily{v1.IPv6Protocol}, info.IPFamilies)
+
+		kubeClient = newFakeClient(nil,
+			&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "kubernetes", Namespace: "default"}, Spec: v1.ServiceSpec{ClusterIP: "10.96.0.1"}},
+		)
+		info, err = InspectClient(kubeClient)
+		require.NoError(t, err)
+		require.Equal(t, []v1.IPFamily{v1.IPv4Protocol}, info.IPFamilies)
+		require.False(t, info.DualStack())
+	})
+
+	t.Run("Gardener takes precedence", func(t *testing.T) {
+		kubeClient := newFakeClient(nil,
+			&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "shoot-info", Namespace: "kube-system"}},
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{"eks.amazonaws.com/nodegroup": "default"}}},
+		)
+
+		info, err := InspectClient(kubeClient)
+		require.NoError(t, err)
+		require.Equal(t, ProviderGardener, info.Provider)
+	})
+
+	t.Run("k3d cluster with Knative", func(t *testing.T) {
+		kubeClient := newFakeClient([]string{"serving.knative.dev/v1"},
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "k3d-kyma-server-0"}},
+		)
+
+		info, err := InspectClient(kubeClient)
+		require.NoError(t, err)
+		require.Equal(t, ProviderK3d, info.Provider)
+		require.Equal(t, "flannel", info.CNI)
+		require.True(t, info.Ingress.LoadBalancer)
+		require.True(t, info.KnativeCRDs)
+		require.False(t, info.IstioCRDs)
+	})
+
+	t.Run("OpenShift cluster", func(t *testing.T) {
+		kubeClient := newFakeClient([]string{"route.openshift.io/v1"})
+
+		info, err := InspectClient(kubeClient)
+		require.NoError(t, err)
+		require.Equal(t, ProviderOpenShift, info.Provider)
+	})
+
+	t.Run("Unknown cluster supports load balancers if one has an address", func(t *testing.T) {
+		kubeClient := newFakeClient(nil,
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}},
+			&v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "default"},
+				Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+				Status:     v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}}}},
+			},
+		)
+
+		info, err := InspectClient(kubeClient)
+		require.NoError(t, err)
+		require.Equal(t, ProviderUnknown, info.Provider)
+		require.Empty(t, info.CNI)
+		require.True(t, info.Ingress.LoadBalancer)
+	})
+}
+
+func newFakeClient(groupVersions []string, objects ...runtime.Object) *fake.Clientset {
+	kubeClient := fake.NewSimpleClientset(objects...)
+	discovery := kubeClient.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.FakedServerVersion = &version.Info{GitVersion: "v1.20.2"}
+	for _, gv := range groupVersions {
+		discovery.Resources = append(discovery.Resources, &metav1.APIResourceList{GroupVersion: gv})
+	}
+	return kubeClient
+}
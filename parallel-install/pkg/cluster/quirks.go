@@ -0,0 +1,257 @@
+package cluster
+
+import (
+	"github.com/imdario/mergo"
+	v1 "k8s.io/api/core/v1"
+)
+
+//Expectations are the assumptions preflight checks make about the cluster
+type Expectations struct {
+	//Privileged containers and containers with the NET_ADMIN capability can be scheduled
+	PrivilegedContainers bool
+	//hostPath volumes can be mounted
+	HostPathVolumes bool
+	//Every container has to declare resource requests
+	ResourceRequestsRequired bool
+	//Workloads have to be admitted by security context constraints
+	SecurityContextConstraints bool
+	//Services of type LoadBalancer get an external address
+	LoadBalancer bool
+	//IP families the workloads get addresses of, the primary family first. Empty if not detected.
+	IPFamilies []v1.IPFamily
+}
+
+//Quirk adjusts the installation to a particularity of a provider
+type Quirk interface {
+	//Name of the quirk, used for logging
+	Name() string
+	//Applies returns true if the quirk applies to the inspected cluster
+	Applies(info *Info) bool
+	//Overrides returns the overrides per chart which adjust the installation to the cluster
+	Overrides(info *Info) map[string]interface{}
+	//Expect adjusts the preflight expectations
+	Expect(expectations *Expectations)
+}
+
+//DefaultQuirks are the quirks of the supported managed clusters
+var DefaultQuirks = []Quirk{
+	GKEAutopilotQuirk{},
+	EKSLoadBalancerQuirk{},
+	OpenShiftQuirk{},
+	K3sQuirk{},
+	IPFamilyQuirk{},
+}
+
+//Adjustments are the overrides and expectations of the quirks which apply to a cluster
+type Adjustments struct {
+	//Names of the applied quirks
+	Quirks []string
+	//Overrides per chart. They serve as defaults, overrides of the user take precedence.
+	Overrides    map[string]interface{}
+	Expectations Expectations
+}
+
+//Adjust applies the quirks to the inspected cluster. The DefaultQuirks are used if no quirk is passed.
+func Adjust(info *Info, quirks ...Quirk) (*Adjustments, error) {
+	if len(quirks) == 0 {
+		quirks = DefaultQuirks
+	}
+	adjustments := &Adjustments{
+		Overrides: make(map[string]interface{}),
+		Expectations: Expectations{
+			PrivilegedContainers: true,
+			HostPathVolumes:      true,
+			LoadBalancer:         info.Ingress.LoadBalancer,
+			IPFamilies:           info.IPFamilies,
+		},
+	}
+	for _, quirk := range quirks {
+		if !quirk.Applies(info) {
+			continue
+		}
+		if err := mergo.Map(&adjustments.Overrides, quirk.Overrides(info), mergo.WithOverride); err != nil {
+			return nil, err
+		}
+		quirk.Expect(&adjustments.Expectations)
+		adjustments.Quirks = append(adjustments.Quirks, quirk.Name())
+	}
+	return adjustments, nil
+}
+
+//istioCNI enables the Istio CNI plugin with the given directories, so that sidecars need no privileged init containers
+func istioCNI(binDir, confDir string) map[string]interface{} {
+	cni := map[string]interface{}{
+		"cniBinDir": binDir,
+	}
+	if confDir != "" {
+		cni["cniConfDir"] = confDir
+	}
+	return map[string]interface{}{
+		"istio": map[string]interface{}{
+			"components": map[string]interface{}{
+				"cni": map[string]interface{}{
+					"enabled": true,
+				},
+			},
+			"helmValues": map[string]interface{}{
+				"cni": cni,
+			},
+		},
+	}
+}
+
+//GKEAutopilotQuirk adjusts to the limits of GKE Autopilot: no privileged containers, no hostPath volumes, and mandatory resource requests
+type GKEAutopilotQuirk struct{}
+
+//Name implements Quirk.Name
+func (GKEAutopilotQuirk) Name() string {
+	return "gke-autopilot"
+}
+
+//Applies implements Quirk.Applies
+func (GKEAutopilotQuirk) Applies(info *Info) bool {
+	return info.GKEAutopilot
+}
+
+//Overrides implements Quirk.Overrides
+func (GKEAutopilotQuirk) Overrides(info *Info) map[string]interface{} {
+	return istioCNI("/home/kubernetes/bin", "")
+}
+
+//Expect implements Quirk.Expect
+func (GKEAutopilotQuirk) Expect(expectations *Expectations) {
+	expectations.PrivilegedContainers = false
+	expectations.HostPathVolumes = false
+	expectations.ResourceRequestsRequired = true
+}
+
+//EKSLoadBalancerQuirk exposes the Istio ingress gateway with a network load balancer, as the classic ELB does not preserve client IPs
+type EKSLoadBalancerQuirk struct{}
+
+//Name implements Quirk.Name
+func (EKSLoadBalancerQuirk) Name() string {
+	return "eks-load-balancer"
+}
+
+//Applies implements Quirk.Applies
+func (EKSLoadBalancerQuirk) Applies(info *Info) bool {
+	return info.Provider == ProviderEKS
+}
+
+//Overrides implements Quirk.Overrides
+func (EKSLoadBalancerQuirk) Overrides(info *Info) map[string]interface{} {
+	return map[string]interface{}{
+		"istio": map[string]interface{}{
+			"components": map[string]interface{}{
+				"ingressGateways": map[string]interface{}{
+					"config": map[string]interface{}{
+						"serviceAnnotations": map[string]interface{}{
+							"service.beta.kubernetes.io/aws-load-balancer-type":                              "nlb",
+							"service.beta.kubernetes.io/aws-load-balancer-cross-zone-load-balancing-enabled": "true",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+//Expect implements Quirk.Expect
+func (EKSLoadBalancerQuirk) Expect(expectations *Expectations) {
+	expectations.LoadBalancer = true
+}
+
+//OpenShiftQuirk adjusts to the security context constraints of OpenShift, which reject privileged Istio init containers
+type OpenShiftQuirk struct{}
+
+//Name implements Quirk.Name
+func (OpenShiftQuirk) Name() string {
+	return "openshift-scc"
+}
+
+//Applies implements Quirk.Applies
+func (OpenShiftQuirk) Applies(info *Info) bool {
+	return info.Provider == ProviderOpenShift
+}
+
+//Overrides implements Quirk.Overrides
+func (OpenShiftQuirk) Overrides(info *Info) map[string]interface{} {
+	return istioCNI("/var/lib/cni/bin", "/etc/cni/multus/net.d")
+}
+
+//Expect implements Quirk.Expect
+func (OpenShiftQuirk) Expect(expectations *Expectations) {
+	expectations.PrivilegedContainers = false
+	expectations.SecurityContextConstraints = true
+}
+
+//K3sQuirk adjusts to the CNI directories of k3s, which differ from the upstream defaults
+type K3sQuirk struct{}
+
+//Name implements Quirk.Name
+func (K3sQuirk) Name() string {
+	return "k3s-paths"
+}
+
+//Applies implements Quirk.Applies
+func (K3sQuirk) Applies(info *Info) bool {
+	return info.Provider == ProviderK3s || info.Provider == ProviderK3d
+}
+
+//Overrides implements Quirk.Overrides
+func (K3sQuirk) Overrides(info *Info) map[string]interface{} {
+	return map[string]interface{}{
+		"istio": map[string]interface{}{
+			"helmValues": map[string]interface{}{
+				"cni": map[string]interface{}{
+					"cniBinDir":  "/bin",
+					"cniConfDir": "/var/lib/rancher/k3s/agent/etc/cni/net.d",
+				},
+			},
+		},
+	}
+}
+
+//Expect implements Quirk.Expect
+func (K3sQuirk) Expect(expectations *Expectations) {
+	expectations.LoadBalancer = true
+}
+
+//IPFamilyQuirk tells the charts the IP families of IPv6-only and dual-stack clusters, as most charts assume IPv4,
+//for example when they bind to 0.0.0.0 or create single-stack Services
+type IPFamilyQuirk struct{}
+
+//Name implements Quirk.Name
+func (IPFamilyQuirk) Name() string {
+	return "ip-families"
+}
+
+//Applies implements Quirk.Applies
+func (IPFamilyQuirk) Applies(info *Info) bool {
+	return info.HasIPFamily(v1.IPv6Protocol)
+}
+
+//Overrides implements Quirk.Overrides
+func (IPFamilyQuirk) Overrides(info *Info) map[string]interface{} {
+	var families []interface{}
+	for _, family := range info.IPFamilies {
+		families = append(families, string(family))
+	}
+	policy := v1.IPFamilyPolicySingleStack
+	if info.DualStack() {
+		policy = v1.IPFamilyPolicyPreferDualStack
+	}
+	return map[string]interface{}{
+		"global": map[string]interface{}{
+			"ipFamilies":     families,
+			"ipFamilyPolicy": string(policy),
+			//on Linux, the unspecified IPv6 address accepts IPv4 connections as well
+			"bindAddress": "::",
+		},
+	}
+}
+
+//Expect implements Quirk.Expect
+func (IPFamilyQuirk) Expect(expectations *Expectations) {
+}
+
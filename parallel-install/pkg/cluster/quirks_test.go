@@ -0,0 +1,59 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestAdjust(t *testing.T) {
+
+	t.Run("GKE Autopilot", func(t *testing.T) {
+		adjustments, err := Adjust(&Info{Provider: ProviderGKE, GKEAutopilot: true, Ingress: IngressCapabilities{LoadBalancer: true}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"gke-autopilot"}, adjustments.Quirks)
+		require.Equal(t, Expectations{ResourceRequestsRequired: true, LoadBalancer: true}, adjustments.Expectations)
+		require.Equal(t, true, adjustments.Overrides["istio"].(map[string]interface{})["components"].(map[string]interface{})["cni"].(map[string]interface{})["enabled"])
+	})
+
+	t.Run("OpenShift", func(t *testing.T) {
+		adjustments, err := Adjust(&Info{Provider: ProviderOpenShift})
+		require.NoError(t, err)
+		require.Equal(t, []string{"openshift-scc"}, adjustments.Quirks)
+		require.True(t, adjustments.Expectations.SecurityContextConstraints)
+		require.False(t, adjustments.Expectations.PrivilegedContainers)
+		cni := adjustments.Overrides["istio"].(map[string]interface{})["helmValues"].(map[string]interface{})["cni"].(map[string]interface{})
+		require.Equal(t, "/etc/cni/multus/net.d", cni["cniConfDir"])
+	})
+
+	t.Run("IP families", func(t *testing.T) {
+		adjustments, err := Adjust(&Info{Provider: ProviderKind, IPFamilies: []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"ip-families"}, adjustments.Quirks)
+		require.Equal(t, []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol}, adjustments.Expectations.IPFamilies)
+		require.Equal(t, map[string]interface{}{
+			"ipFamilies":     []interface{}{"IPv4", "IPv6"},
+			"ipFamilyPolicy": "PreferDualStack",
+			"bindAddress":    "::",
+		}, adjustments.Overrides["global"])
+
+		adjustments, err = Adjust(&Info{IPFamilies: []v1.IPFamily{v1.IPv4Protocol}})
+		require.NoError(t, err)
+		require.Empty(t, adjustments.Quirks)
+	})
+
+	t.Run("No quirk applies", func(t *testing.T) {
+		adjustments, err := Adjust(&Info{Provider: ProviderKind})
+		require.NoError(t, err)
+		require.Empty(t, adjustments.Quirks)
+		require.Empty(t, adjustments.Overrides)
+		require.Equal(t, Expectations{PrivilegedContainers: true, HostPathVolumes: true}, adjustments.Expectations)
+	})
+
+	t.Run("Custom quirks", func(t *testing.T) {
+		adjustments, err := Adjust(&Info{Provider: ProviderEKS}, K3sQuirk{}, EKSLoadBalancerQuirk{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"eks-load-balancer"}, adjustments.Quirks)
+	})
+}
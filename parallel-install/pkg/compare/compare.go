@@ -0,0 +1,303 @@
+//Package compare reports the component-level differences between two Kyma source revisions,
+//so that the impact of an upgrade can be assessed without diffing the sources manually.
+package compare
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/git"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+const (
+	defaultComponentsFile = "installation/resources/components.yaml"
+	resourcesDir          = "resources"
+)
+
+//Change is the kind of a difference
+type Change string
+
+const (
+	//Added is set for components and values which only exist in the newer revision
+	Added Change = "added"
+	//Removed is set for components and values which only exist in the older revision
+	Removed Change = "removed"
+	//Changed is set for components and values which exist in both revisions and differ
+	Changed Change = "changed"
+)
+
+//Source is a Kyma source revision in a local directory
+type Source struct {
+	//Path of the Kyma sources
+	Path string
+	//Component list file relative to the path. Defaults to installation/resources/components.yaml.
+	ComponentsFile string
+}
+
+//ValueDiff is a changed default value of a chart
+type ValueDiff struct {
+	//Key is the path of the value, e.g. global.domainName
+	Key    string
+	Change Change
+	//From is the value of the older revision, nil if the value was added
+	From interface{}
+	//To is the value of the newer revision, nil if the value was removed
+	To interface{}
+}
+
+//ComponentDiff is the difference of a component between two revisions
+type ComponentDiff struct {
+	Name   string
+	Change Change
+	//Prerequisite is set if the component is a prerequisite in the newer revision, or in the older revision if it was removed
+	Prerequisite bool
+	//FromNamespace and ToNamespace differ if the component moved to another namespace
+	FromNamespace string
+	ToNamespace   string
+	//FromVersion and ToVersion are the chart versions. They are empty if the revision has no chart of the component.
+	FromVersion string
+	ToVersion   string
+	//Values are the changed default values of the chart, sorted by key. They are only set for changed components.
+	Values []ValueDiff
+}
+
+//String returns a one-line summary of the difference
+func (d ComponentDiff) String() string {
+	switch d.Change {
+	case Added:
+		return fmt.Sprintf("%s: added in %s (chart %s)", d.Name, d.ToNamespace, d.ToVersion)
+	case Removed:
+		return fmt.Sprintf("%s: removed from %s (chart %s)", d.Name, d.FromNamespace, d.FromVersion)
+	}
+	var changes []string
+	if d.FromVersion != d.ToVersion {
+		changes = append(changes, fmt.Sprintf("chart %s -> %s", d.FromVersion, d.ToVersion))
+	}
+	if d.FromNamespace != d.ToNamespace {
+		changes = append(changes, fmt.Sprintf("namespace %s -> %s", d.FromNamespace, d.ToNamespace))
+	}
+	if len(d.Values) > 0 {
+		changes = append(changes, fmt.Sprintf("%d default value(s) changed", len(d.Values)))
+	}
+	return fmt.Sprintf("%s: %s", d.Name, strings.Join(changes, ", "))
+}
+
+//Comparison lists the components which differ between two revisions, sorted by name
+type Comparison struct {
+	Components []ComponentDiff
+}
+
+//Empty returns true if the revisions do not differ
+func (c *Comparison) Empty() bool {
+	return len(c.Components) == 0
+}
+
+//Filter returns the differences of the given kind
+func (c *Comparison) Filter(change Change) []ComponentDiff {
+	var diffs []ComponentDiff
+	for _, diff := range c.Components {
+		if diff.Change == change {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs
+}
+
+//String returns a summary with one line per component
+func (c *Comparison) String() string {
+	if c.Empty() {
+		return "No component differences"
+	}
+	lines := make([]string, 0, len(c.Components))
+	for _, diff := range c.Components {
+		lines = append(lines, diff.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+//Revisions compares the components of two Kyma source revisions: the components added to or removed from the component list,
+//and for the components of both revisions the chart version, the namespace, and the default values of the chart.
+func Revisions(from, to Source) (*Comparison, error) {
+	fromComps, err := from.components()
+	if err != nil {
+		return nil, err
+	}
+	toComps, err := to.components()
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &Comparison{}
+	for name, toComp := range toComps {
+		fromComp, ok := fromComps[name]
+		if !ok {
+			comparison.Components = append(comparison.Components, ComponentDiff{
+				Name:         name,
+				Change:       Added,
+				Prerequisite: toComp.prerequisite,
+				ToNamespace:  toComp.namespace,
+				ToVersion:    toComp.version,
+			})
+			continue
+		}
+		diff := ComponentDiff{
+			Name:          name,
+			Change:        Changed,
+			Prerequisite:  toComp.prerequisite,
+			FromNamespace: fromComp.namespace,
+			ToNamespace:   toComp.namespace,
+			FromVersion:   fromComp.version,
+			ToVersion:     toComp.version,
+			Values:        diffValues(fromComp.values, toComp.values),
+		}
+		if diff.FromVersion != diff.ToVersion || diff.FromNamespace != diff.ToNamespace || len(diff.Values) > 0 {
+			comparison.Components = append(comparison.Components, diff)
+		}
+	}
+	for name, fromComp := range fromComps {
+		if _, ok := toComps[name]; !ok {
+			comparison.Components = append(comparison.Components, ComponentDiff{
+				Name:          name,
+				Change:        Removed,
+				Prerequisite:  fromComp.prerequisite,
+				FromNamespace: fromComp.namespace,
+				FromVersion:   fromComp.version,
+			})
+		}
+	}
+	sort.Slice(comparison.Components, func(i, j int) bool {
+		return comparison.Components[i].Name < comparison.Components[j].Name
+	})
+	return comparison, nil
+}
+
+//GitRevisions clones two revisions of a Kyma repository into the workspace and compares them.
+//The revisions can be 'main', a release version (e.g. 1.4.1), a commit hash (e.g. 34edf09a) or a PR (e.g. PR-9486).
+func GitRevisions(source git.Source, workspace, fromRev, toRev string) (*Comparison, error) {
+	from := Source{Path: filepath.Join(workspace, "from")}
+	to := Source{Path: filepath.Join(workspace, "to")}
+	for _, dir := range []string{from.Path, to.Path} {
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+	}
+	if err := source.Clone(from.Path, fromRev); err != nil {
+		return nil, errors.Wrapf(err, "Failed to check out revision %s", fromRev)
+	}
+	if err := source.Clone(to.Path, toRev); err != nil {
+		return nil, errors.Wrapf(err, "Failed to check out revision %s", toRev)
+	}
+	return Revisions(from, to)
+}
+
+//component is a component of a revision with its chart
+type component struct {
+	namespace    string
+	prerequisite bool
+	version      string
+	values       map[string]interface{}
+}
+
+//components reads the component list of the revision and the charts of its components
+func (s Source) components() (map[string]*component, error) {
+	componentsFile := s.ComponentsFile
+	if componentsFile == "" {
+		componentsFile = defaultComponentsFile
+	}
+	compList, err := config.NewComponentList(filepath.Join(s.Path, componentsFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read the component list of %s", s.Path)
+	}
+
+	comps := map[string]*component{}
+	add := func(definitions []config.ComponentDefinition, prerequisite bool) error {
+		for _, definition := range definitions {
+			comp := &component{namespace: definition.Namespace, prerequisite: prerequisite}
+			if err := s.readChart(definition.Name, comp); err != nil {
+				return err
+			}
+			comps[definition.Name] = comp
+		}
+		return nil
+	}
+	if err := add(compList.Prerequisites, true); err != nil {
+		return nil, err
+	}
+	if err := add(compList.Components, false); err != nil {
+		return nil, err
+	}
+	return comps, nil
+}
+
+//readChart reads the version and the default values of the chart of a component.
+//Components without a chart in the revision, e.g. modules, have no version and values.
+func (s Source) readChart(name string, comp *component) error {
+	chartDir := filepath.Join(s.Path, resourcesDir, name)
+	metadata, err := chartutil.LoadChartfile(filepath.Join(chartDir, chartutil.ChartfileName))
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read the chart of component '%s' in %s", name, s.Path)
+	}
+	comp.version = metadata.Version
+
+	values, err := chartutil.ReadValuesFile(filepath.Join(chartDir, chartutil.ValuesfileName))
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read the values of component '%s' in %s", name, s.Path)
+	}
+	comp.values = values
+	return nil
+}
+
+//diffValues returns the differences of the leaf values, sorted by key
+func diffValues(from, to map[string]interface{}) []ValueDiff {
+	fromLeaves := map[string]interface{}{}
+	flatten("", from, fromLeaves)
+	toLeaves := map[string]interface{}{}
+	flatten("", to, toLeaves)
+
+	var diffs []ValueDiff
+	for key, toValue := range toLeaves {
+		fromValue, ok := fromLeaves[key]
+		switch {
+		case !ok:
+			diffs = append(diffs, ValueDiff{Key: key, Change: Added, To: toValue})
+		case !reflect.DeepEqual(fromValue, toValue):
+			diffs = append(diffs, ValueDiff{Key: key, Change: Changed, From: fromValue, To: toValue})
+		}
+	}
+	for key, fromValue := range fromLeaves {
+		if _, ok := toLeaves[key]; !ok {
+			diffs = append(diffs, ValueDiff{Key: key, Change: Removed, From: fromValue})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
+
+//flatten adds the leaf values of the map with their dotted keys. Lists are leaves.
+func flatten(prefix string, values map[string]interface{}, leaves map[string]interface{}) {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flatten(key, nested, leaves)
+			continue
+		}
+		leaves[key] = value
+	}
+}
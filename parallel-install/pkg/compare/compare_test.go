@@ -0,0 +1,96 @@
+package compare
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevisions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compare")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	from := writeSource(t, filepath.Join(dir, "from"), `
+prerequisites:
+  - name: cluster-essentials
+components:
+  - name: serverless
+  - name: monitoring
+  - name: tracing
+`, map[string]testChart{
+		"cluster-essentials": {"1.0.0", "replicas: 1\n"},
+		"serverless":         {"1.0.0", "webhook:\n  enabled: true\n  port: 8443\nimages: [a, b]\n"},
+		"monitoring":         {"2.0.0", "retention: 1d\n"},
+		"tracing":            {"1.0.0", ""},
+	})
+	to := writeSource(t, filepath.Join(dir, "to"), `
+prerequisites:
+  - name: cluster-essentials
+components:
+  - name: serverless
+  - name: monitoring
+    namespace: kyma-monitoring
+  - name: eventing
+`, map[string]testChart{
+		"cluster-essentials": {"1.0.0", "replicas: 1\n"},
+		"serverless":         {"1.1.0", "webhook:\n  enabled: false\nimages: [a, c]\ntimeout: 30\n"},
+		"monitoring":         {"2.0.0", "retention: 1d\n"},
+		"eventing":           {"3.0.0", ""},
+	})
+
+	comparison, err := Revisions(from, to)
+	require.NoError(t, err)
+
+	require.Equal(t, []ComponentDiff{
+		{Name: "eventing", Change: Added, ToNamespace: "kyma-system", ToVersion: "3.0.0"},
+		{Name: "monitoring", Change: Changed, FromNamespace: "kyma-system", ToNamespace: "kyma-monitoring", FromVersion: "2.0.0", ToVersion: "2.0.0"},
+		{Name: "serverless", Change: Changed, FromNamespace: "kyma-system", ToNamespace: "kyma-system", FromVersion: "1.0.0", ToVersion: "1.1.0", Values: []ValueDiff{
+			{Key: "images", Change: Changed, From: []interface{}{"a", "b"}, To: []interface{}{"a", "c"}},
+			{Key: "timeout", Change: Added, To: float64(30)},
+			{Key: "webhook.enabled", Change: Changed, From: true, To: false},
+			{Key: "webhook.port", Change: Removed, From: float64(8443)},
+		}},
+		{Name: "tracing", Change: Removed, FromNamespace: "kyma-system", FromVersion: "1.0.0"},
+	}, comparison.Components)
+
+	require.Len(t, comparison.Filter(Added), 1)
+	require.Equal(t, "serverless: chart 1.0.0 -> 1.1.0, 4 default value(s) changed", comparison.Filter(Changed)[1].String())
+	require.Equal(t, "monitoring: namespace kyma-system -> kyma-monitoring", comparison.Filter(Changed)[0].String())
+
+	t.Run("Same revision", func(t *testing.T) {
+		comparison, err := Revisions(from, from)
+		require.NoError(t, err)
+		require.True(t, comparison.Empty())
+	})
+
+	t.Run("Missing component list", func(t *testing.T) {
+		_, err := Revisions(Source{Path: filepath.Join(dir, "missing")}, to)
+		require.Error(t, err)
+	})
+}
+
+//testChart is the version and the values file of a chart. The values file is not written if empty.
+type testChart struct {
+	version string
+	values  string
+}
+
+//writeSource writes the component list and the charts of a revision
+func writeSource(t *testing.T, path, componentList string, charts map[string]testChart) Source {
+	require.NoError(t, os.MkdirAll(filepath.Join(path, "installation", "resources"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, defaultComponentsFile), []byte(componentList), 0600))
+	for name, chart := range charts {
+		chartDir := filepath.Join(path, resourcesDir, name)
+		require.NoError(t, os.MkdirAll(chartDir, 0700))
+		chartFile := "apiVersion: v2\nname: " + name + "\nversion: " + chart.version + "\n"
+		require.NoError(t, ioutil.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(chartFile), 0600))
+		if chart.values != "" {
+			require.NoError(t, ioutil.WriteFile(filepath.Join(chartDir, "values.yaml"), []byte(chart.values), 0600))
+		}
+	}
+	return Source{Path: path}
+}
@@ -2,14 +2,52 @@ package components
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
 )
 
-const StatusError = "Error"
-const StatusInstalled = "Installed"
-const StatusUninstalled = "Uninstalled"
+//Status is the processing state of a component
+type Status string
+
+//Final states of a processed component
+const StatusError Status = "Error"
+const StatusInstalled Status = "Installed"
+const StatusUninstalled Status = "Uninstalled"
+const StatusDegraded Status = "Degraded"
+const StatusSkipped Status = "Skipped"
+
+//Intermediate states of a component which is being processed
+const StatusPending Status = "Pending"
+const StatusRendering Status = "Rendering"
+const StatusApplying Status = "Applying"
+const StatusWaitingForReadiness Status = "WaitingForReadiness"
+const StatusRollingBack Status = "RollingBack"
+
+//Final returns whether the processing of the component is finished
+func (s Status) Final() bool {
+	switch s {
+	case StatusError, StatusInstalled, StatusUninstalled, StatusDegraded, StatusSkipped:
+		return true
+	}
+	return false
+}
+
+//StatusOf returns the status of a component whose Helm release is in the given phase
+func StatusOf(phase helm.ReleasePhase) Status {
+	switch phase {
+	case helm.PhaseRendering:
+		return StatusRendering
+	case helm.PhaseWaiting:
+		return StatusWaitingForReadiness
+	case helm.PhaseRollingBack:
+		return StatusRollingBack
+	}
+	return StatusApplying
+}
 
 const logPrefix = "[components/component.go]"
 
@@ -34,10 +72,18 @@ type KymaComponent struct {
 	Namespace string
 	//Profile defines the Kyma release namespace
 	Profile string
-	Status  string
+	Status  Status
 	Error   error
+	//Retries of the last operation, if the Helm client counts them
+	Retries int
+	//Duration of the last operation, set by the engine
+	Duration time.Duration
+	//Notes rendered from the NOTES.txt of the chart by the last deployment, if the Helm client keeps them
+	Notes string
 	//ChartDir is a local filesystem directory with the component's chart.
 	ChartDir string
+	//RequiredAPIs have to be served before the component is deployed.
+	RequiredAPIs []config.APIRequirement
 	//OverridesGetter is a function that returns overrides for the release.
 	OverridesGetter func() map[string]interface{}
 	HelmClient      helm.ClientInterface
@@ -51,6 +97,8 @@ func (c *KymaComponent) Deploy(ctx context.Context) error {
 	overrides := c.OverridesGetter()
 
 	err := c.HelmClient.DeployRelease(ctx, c.ChartDir, c.Namespace, c.Name, overrides, c.Profile)
+	c.countRetries()
+	c.readNotes()
 	if err != nil {
 		c.Log.Errorf("%s Error deploying %s: %v", logPrefix, c.Name, err)
 		return err
@@ -66,6 +114,7 @@ func (c *KymaComponent) Uninstall(ctx context.Context) error {
 	c.Log.Infof("%s Uninstalling %s in %s from %s", logPrefix, c.Name, c.Namespace, c.ChartDir)
 
 	err := c.HelmClient.UninstallRelease(ctx, c.Namespace, c.Name)
+	c.countRetries()
 	if err != nil {
 		c.Log.Infof("%s Error uninstalling %s: %v", logPrefix, c.Name, err)
 		return err
@@ -75,3 +124,37 @@ func (c *KymaComponent) Uninstall(ctx context.Context) error {
 
 	return nil
 }
+
+//Rollback rolls the component back to the revision before its last upgrade.
+//It fails if the Helm client does not support rollbacks.
+func (c *KymaComponent) Rollback(ctx context.Context) error {
+	rollbacker, ok := c.HelmClient.(helm.Rollbacker)
+	if !ok {
+		return fmt.Errorf("Helm client does not support the rollback of %s", c.Name)
+	}
+	c.Log.Infof("%s Rolling back %s in %s", logPrefix, c.Name, c.Namespace)
+
+	err := rollbacker.RollbackRelease(ctx, c.Namespace, c.Name)
+	if err != nil {
+		c.Log.Errorf("%s Error rolling back %s: %v", logPrefix, c.Name, err)
+		return err
+	}
+
+	c.Log.Infof("%s Rolled back %s in %s", logPrefix, c.Name, c.Namespace)
+
+	return nil
+}
+
+//readNotes takes over the notes of the last deployment from the Helm client
+func (c *KymaComponent) readNotes() {
+	if reader, ok := c.HelmClient.(helm.NotesReader); ok {
+		c.Notes = reader.Notes(c.Namespace, c.Name)
+	}
+}
+
+//countRetries takes over the retries of the last operation from the Helm client
+func (c *KymaComponent) countRetries() {
+	if counter, ok := c.HelmClient.(helm.RetryCounter); ok {
+		c.Retries = counter.Retries(c.Namespace, c.Name)
+	}
+}
@@ -2,11 +2,15 @@ package components
 
 import (
 	"path"
+	"time"
 
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/modules"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/simulation"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/transform"
 )
 
 //Provider is an entity that produces a list of components for Kyma installation or uninstallation.
@@ -20,6 +24,9 @@ type ComponentsProvider struct {
 	resourcesPath     string //A root directory where subdirectories of components' charts are located.
 	components        []config.ComponentDefinition
 	helmConfig        helm.Config
+	modulesConfig     modules.Config
+	simulationConfig  config.SimulationConfig
+	backend           config.InstallationBackend
 	log               logger.Interface
 	profile           string
 }
@@ -35,6 +42,29 @@ func NewComponentsProvider(overridesProvider overrides.Provider, cfg *config.Con
 		Atomic:                        cfg.Atomic,
 		KymaComponentMetadataTemplate: tpl,
 		KubeconfigSource:              cfg.KubeconfigSource,
+		Repositories:                  cfg.HelmRepositories,
+		Storage:                       cfg.HelmStorage,
+		SQLConnectionString:           cfg.HelmSQLConnectionString,
+		ChartMemoryBudget:             cfg.ChartMemoryBudget,
+		CreateChunkSize:               cfg.HelmCreateChunkSize,
+		Proxy:                         cfg.Proxy,
+		ClientTLS:                     cfg.OutboundTLS(),
+		KeepKinds:                     cfg.KeptKinds(),
+		SkipUnchanged:                 cfg.SkipUnchangedComponents,
+		SeedDeployedValues:            cfg.SeedDeployedValues,
+		Transformations:               transform.ForConfig(cfg),
+	}
+
+	modulesCfg := modules.Config{
+		Channel:          cfg.ModuleChannel,
+		Timeout:          time.Duration(cfg.HelmTimeoutSeconds) * time.Second,
+		Log:              cfg.Log,
+		KubeconfigSource: cfg.KubeconfigSource,
+	}
+
+	var simulationCfg config.SimulationConfig
+	if cfg.Simulation != nil {
+		simulationCfg = *cfg.Simulation
 	}
 
 	return &ComponentsProvider{
@@ -42,6 +72,9 @@ func NewComponentsProvider(overridesProvider overrides.Provider, cfg *config.Con
 		resourcesPath:     cfg.ResourcePath,
 		components:        components,
 		helmConfig:        helmCfg,
+		modulesConfig:     modulesCfg,
+		simulationConfig:  simulationCfg,
+		backend:           cfg.Backend,
 		log:               cfg.Log,
 		profile:           cfg.Profile,
 	}
@@ -49,16 +82,17 @@ func NewComponentsProvider(overridesProvider overrides.Provider, cfg *config.Con
 
 //Implements Provider.GetComponents.
 func (p *ComponentsProvider) GetComponents() []KymaComponent {
-	helmClient := helm.NewClient(p.helmConfig)
+	helmClient := p.helmClient()
 
 	var components []KymaComponent
 	for _, component := range p.components {
 		cmp := KymaComponent{
-			Name:            component.Name,
+			Name:            component.Release(),
 			Namespace:       component.Namespace,
 			Profile:         p.profile,
 			OverridesGetter: p.overridesProvider.OverridesGetterFunctionFor(component.Name),
 			ChartDir:        path.Join(p.resourcesPath, component.Name),
+			RequiredAPIs:    component.RequiresAPIs,
 			HelmClient:      helmClient,
 			Log:             p.log,
 		}
@@ -67,3 +101,27 @@ func (p *ComponentsProvider) GetComponents() []KymaComponent {
 
 	return components
 }
+
+//GetRelease returns a component for an installed release, e.g. to uninstall a single release which is not part of the component list.
+//The component has no chart and no overrides, so it can't be deployed.
+func (p *ComponentsProvider) GetRelease(name, namespace string) KymaComponent {
+	return KymaComponent{
+		Name:            name,
+		Namespace:       namespace,
+		Profile:         p.profile,
+		OverridesGetter: func() map[string]interface{} { return nil },
+		HelmClient:      p.helmClient(),
+		Log:             p.log,
+	}
+}
+
+//helmClient returns the client of the configured installation backend
+func (p *ComponentsProvider) helmClient() helm.ClientInterface {
+	switch p.backend {
+	case config.ModulesBackend:
+		return modules.NewClient(p.modulesConfig)
+	case config.SimulationBackend:
+		return simulation.NewClient(p.simulationConfig)
+	}
+	return helm.NewClient(p.helmConfig)
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/modules"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/simulation"
 
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 
@@ -58,4 +60,19 @@ func Test_GetComponents(t *testing.T) {
 
 	res := provider.GetComponents()
 	require.Equal(t, 2, len(res), "Number of components not as expected")
+	require.IsType(t, &helm.Client{}, res[0].HelmClient)
+
+	instCfg.Backend = config.ModulesBackend
+	provider = NewComponentsProvider(overridesProvider, instCfg, instCfg.ComponentList.Components, cmpMetadataTpl)
+
+	res = provider.GetComponents()
+	require.Equal(t, 2, len(res), "Number of components not as expected")
+	require.IsType(t, &modules.Client{}, res[0].HelmClient)
+
+	instCfg.Backend = config.SimulationBackend
+	provider = NewComponentsProvider(overridesProvider, instCfg, instCfg.ComponentList.Components, cmpMetadataTpl)
+
+	res = provider.GetComponents()
+	require.Equal(t, 2, len(res), "Number of components not as expected")
+	require.IsType(t, &simulation.Client{}, res[0].HelmClient)
 }
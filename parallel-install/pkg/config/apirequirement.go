@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// APIRequirement is an API which has to be served before a component is deployed, e.g. because its charts contain custom resources
+// of CRDs which are installed by another component. It is either an API group version, e.g. "networking.istio.io/v1alpha3",
+// or a resource of it, e.g. "networking.istio.io/v1alpha3/virtualservices".
+type APIRequirement string
+
+// GroupVersion returns the API group version of the requirement
+func (r APIRequirement) GroupVersion() string {
+	groupVersion, _, _ := r.parse()
+	return groupVersion
+}
+
+// Resource returns the resource of the requirement, or an empty string if only the API group version is required
+func (r APIRequirement) Resource() string {
+	_, resource, _ := r.parse()
+	return resource
+}
+
+func (r APIRequirement) parse() (string, string, error) {
+	parts := strings.Split(strings.TrimSpace(string(r)), "/")
+	for _, part := range parts {
+		if part == "" {
+			parts = nil
+			break
+		}
+	}
+	switch len(parts) {
+	case 2:
+		return parts[0] + "/" + parts[1], "", nil
+	case 3:
+		return parts[0] + "/" + parts[1], parts[2], nil
+	}
+	return "", "", fmt.Errorf("Required API '%s' is invalid: expected '<group>/<version>' or '<group>/<version>/<resource>'", r)
+}
+
+// validateRequiredAPIs verifies that the required APIs of all components can be parsed
+func (cl *ComponentList) validateRequiredAPIs() error {
+	for _, comp := range append(append([]ComponentDefinition{}, cl.Prerequisites...), cl.Components...) {
+		for _, api := range comp.RequiresAPIs {
+			if _, _, err := api.parse(); err != nil {
+				return fmt.Errorf("Component '%s': %v", comp.Name, err)
+			}
+		}
+	}
+	return nil
+}
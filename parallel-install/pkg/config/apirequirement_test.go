@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIRequirement(t *testing.T) {
+	api := APIRequirement("networking.istio.io/v1alpha3")
+	require.Equal(t, "networking.istio.io/v1alpha3", api.GroupVersion())
+	require.Equal(t, "", api.Resource())
+
+	api = APIRequirement("networking.istio.io/v1alpha3/virtualservices")
+	require.Equal(t, "networking.istio.io/v1alpha3", api.GroupVersion())
+	require.Equal(t, "virtualservices", api.Resource())
+
+	for _, invalid := range []APIRequirement{"", "v1", "networking.istio.io//virtualservices", "a/b/c/d"} {
+		_, _, err := invalid.parse()
+		require.Error(t, err, string(invalid))
+	}
+}
+
+func TestComponentList_ValidateRequiredAPIs(t *testing.T) {
+	cl := &ComponentList{Components: []ComponentDefinition{{Name: "api-gateway", RequiresAPIs: []APIRequirement{"networking.istio.io/v1alpha3"}}}}
+	require.NoError(t, cl.validateRequiredAPIs())
+
+	cl.Components[0].RequiresAPIs = append(cl.Components[0].RequiresAPIs, "virtualservices")
+	require.EqualError(t, cl.validateRequiredAPIs(), "Component 'api-gateway': Required API 'virtualservices' is invalid: expected '<group>/<version>' or '<group>/<version>/<resource>'")
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Bundles maps the name of a bundle, e.g. "observability", to its entries.
+// An entry is the name of a component or of another bundle, so that bundles like "full" can be composed of smaller ones.
+type Bundles map[string][]string
+
+// Select returns a copy of the component list which only contains the components of the given bundles.
+// Prerequisites are always kept. Without bundles, the list is returned unchanged.
+func (cl *ComponentList) Select(bundles []string) (*ComponentList, error) {
+	if cl == nil || len(bundles) == 0 {
+		return cl, nil
+	}
+	selected := map[string]bool{}
+	for _, bundle := range bundles {
+		if _, ok := cl.Bundles[bundle]; !ok {
+			return nil, fmt.Errorf("Bundle '%s' is not defined in the component list. Defined bundles: %s", bundle, strings.Join(cl.Bundles.names(), ", "))
+		}
+		if err := cl.resolve(bundle, selected, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ComponentList{
+		Prerequisites: append([]ComponentDefinition{}, cl.Prerequisites...),
+		Bundles:       cl.Bundles,
+	}
+	for _, comp := range cl.Components {
+		if selected[comp.Name] {
+			result.Components = append(result.Components, comp)
+		}
+	}
+	return result, nil
+}
+
+// resolve adds the components of the bundle, including those of nested bundles, to the selected components
+func (cl *ComponentList) resolve(bundle string, selected map[string]bool, path []string) error {
+	for _, name := range path {
+		if name == bundle {
+			return fmt.Errorf("Bundle '%s' includes itself: %s", bundle, strings.Join(append(path, bundle), " -> "))
+		}
+	}
+	path = append(path, bundle)
+	for _, entry := range cl.Bundles[bundle] {
+		if _, ok := cl.Bundles[entry]; ok {
+			if err := cl.resolve(entry, selected, path); err != nil {
+				return err
+			}
+			continue
+		}
+		if !cl.contains(entry) {
+			return fmt.Errorf("Bundle '%s' refers to '%s', which is neither a component nor a bundle", bundle, entry)
+		}
+		selected[entry] = true
+	}
+	return nil
+}
+
+func (cl *ComponentList) contains(name string) bool {
+	for _, comp := range append(append([]ComponentDefinition{}, cl.Prerequisites...), cl.Components...) {
+		if comp.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateBundles verifies that all bundles refer to existing components or bundles without cycles
+func (cl *ComponentList) validateBundles() error {
+	for _, bundle := range cl.Bundles.names() {
+		if err := cl.resolve(bundle, map[string]bool{}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b Bundles) names() []string {
+	var names []string
+	for name := range b {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
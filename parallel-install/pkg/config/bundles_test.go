@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentList_Select(t *testing.T) {
+	t.Run("Select nested bundles", func(t *testing.T) {
+		compList := newCompList(t, "../test/data/componentlist.yaml")
+		require.NoError(t, compList.validateBundles())
+
+		selected, err := compList.Select([]string{"extended"})
+		require.NoError(t, err)
+		require.Equal(t, compList.Prerequisites, selected.Prerequisites)
+		require.Len(t, selected.Components, 2)
+		require.Equal(t, "comp1", selected.Components[0].Name)
+		require.Equal(t, "comp3", selected.Components[1].Name)
+	})
+
+	t.Run("Combine bundles", func(t *testing.T) {
+		compList := &ComponentList{
+			Components: []ComponentDefinition{{Name: "eventing"}, {Name: "logging"}, {Name: "monitoring"}, {Name: "serverless"}},
+			Bundles: Bundles{
+				"core":          {"serverless"},
+				"eventing":      {"eventing"},
+				"observability": {"logging", "monitoring"},
+			},
+		}
+		selected, err := compList.Select([]string{"core", "eventing"})
+		require.NoError(t, err)
+		require.Equal(t, []ComponentDefinition{{Name: "eventing"}, {Name: "serverless"}}, selected.Components)
+	})
+
+	t.Run("Without bundles", func(t *testing.T) {
+		compList := newCompList(t, "../test/data/componentlist.yaml")
+		selected, err := compList.Select(nil)
+		require.NoError(t, err)
+		require.Equal(t, compList, selected)
+	})
+
+	t.Run("Unknown bundle", func(t *testing.T) {
+		compList := newCompList(t, "../test/data/componentlist.yaml")
+		_, err := compList.Select([]string{"observability"})
+		require.EqualError(t, err, "Bundle 'observability' is not defined in the component list. Defined bundles: core, extended")
+	})
+
+	t.Run("Invalid bundles", func(t *testing.T) {
+		compList := &ComponentList{
+			Components: []ComponentDefinition{{Name: "comp1"}},
+			Bundles:    Bundles{"a": {"b"}, "b": {"a", "comp1"}},
+		}
+		require.EqualError(t, compList.validateBundles(), "Bundle 'a' includes itself: a -> b -> a")
+
+		compList.Bundles = Bundles{"a": {"comp2"}}
+		require.EqualError(t, compList.validateBundles(), "Bundle 'a' refers to 'comp2', which is neither a component nor a bundle")
+	})
+}
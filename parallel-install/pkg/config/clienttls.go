@@ -0,0 +1,209 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// fipsCipherSuites are the approved cipher suites of TLS 1.2: ECDHE key exchange with AES-GCM
+var fipsCipherSuites = []string{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+}
+
+// ClientTLSConfig defines the TLS settings of all outbound connections:
+// Kubernetes clients, git cloning, file downloads, image registry requests, backup uploads, and metrics pushes.
+type ClientTLSConfig struct {
+	// Minimum TLS version: 1.2|1.3. Defaults to 1.2.
+	MinVersion string
+	// Cipher suites of TLS 1.2 connections by name, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. Defaults to the secure cipher suites of Go.
+	// The cipher suites of TLS 1.3 are not configurable.
+	CipherSuites []string
+	// Path to the PEM encoded client certificate presented to servers which request one. Kubernetes clients use the credentials of the kubeconfig instead.
+	CertFile string
+	// Path to the PEM encoded private key of the client certificate
+	KeyFile string
+
+	// fips limits the connections to TLS 1.2, see Config.OutboundTLS
+	fips bool
+}
+
+// validate verifies the TLS version, the cipher suites, and the client certificate.
+// In FIPS mode, only the approved cipher suites and certificates are accepted.
+func (t *ClientTLSConfig) validate(fips bool) error {
+	version, err := t.minVersion()
+	if err != nil {
+		return err
+	}
+	if fips && version == tls.VersionTLS13 {
+		return fmt.Errorf("Minimum TLS version cannot be 1.3 in FIPS mode, as the cipher suites of TLS 1.3 are not configurable")
+	}
+	approved := make(map[string]bool, len(fipsCipherSuites))
+	for _, name := range fipsCipherSuites {
+		approved[name] = true
+	}
+	for _, name := range t.CipherSuites {
+		if _, err := cipherSuite(name); err != nil {
+			return err
+		}
+		if fips && !approved[name] {
+			return fmt.Errorf("Cipher suite '%s' is not approved in FIPS mode", name)
+		}
+	}
+
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("Client certificate and key have to be provided together")
+	}
+	if t.CertFile == "" {
+		return nil
+	}
+	if _, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile); err != nil {
+		return errors.Wrapf(err, "Failed to load client certificate '%s'", t.CertFile)
+	}
+	if !fips {
+		return nil
+	}
+	crt, err := ioutil.ReadFile(t.CertFile)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read client certificate '%s'", t.CertFile)
+	}
+	return CheckFIPSCertificate(fmt.Sprintf("client certificate '%s'", t.CertFile), crt)
+}
+
+// minVersion returns the minimum TLS version, TLS 1.2 by default
+func (t *ClientTLSConfig) minVersion() (uint16, error) {
+	switch t.MinVersion {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("Minimum TLS version '%s' is not supported: 1.2|1.3", t.MinVersion)
+	}
+}
+
+// cipherSuite returns the ID of a secure cipher suite of TLS 1.2
+func cipherSuite(name string) (uint16, error) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name != name {
+			continue
+		}
+		for _, version := range suite.SupportedVersions {
+			if version == tls.VersionTLS12 {
+				return suite.ID, nil
+			}
+		}
+		return 0, fmt.Errorf("Cipher suite '%s' belongs to TLS 1.3 and is not configurable", name)
+	}
+	return 0, fmt.Errorf("Cipher suite '%s' is unknown or insecure", name)
+}
+
+// apply restricts a TLS config to the TLS versions and the cipher suites, and adds the client certificate if clientCert is set
+func (t *ClientTLSConfig) apply(tlsConfig *tls.Config, clientCert bool) error {
+	version, err := t.minVersion()
+	if err != nil {
+		return err
+	}
+	tlsConfig.MinVersion = version
+	if t.fips {
+		tlsConfig.MaxVersion = tls.VersionTLS12
+	}
+	if len(t.CipherSuites) > 0 {
+		tlsConfig.CipherSuites = nil
+		for _, name := range t.CipherSuites {
+			id, err := cipherSuite(name)
+			if err != nil {
+				return err
+			}
+			tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
+		}
+	}
+
+	if !clientCert || t.CertFile == "" {
+		return nil
+	}
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to load client certificate '%s'", t.CertFile)
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return nil
+}
+
+// Apply restricts a TLS config to the TLS versions and the cipher suites, and adds the client certificate.
+// It serves connections which do not use Transport, e.g. the chart downloads of Helm.
+func (t *ClientTLSConfig) Apply(tlsConfig *tls.Config) error {
+	return t.apply(tlsConfig, true)
+}
+
+// WrapTransport restricts the connections of a Kubernetes client to the TLS versions and the cipher suites, see rest.Config.Wrap.
+// The client certificate is not added, as Kubernetes clients authenticate with the credentials of the kubeconfig.
+func (t *ClientTLSConfig) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return rt
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	if err := t.apply(transport.TLSClientConfig, false); err != nil {
+		// unreachable for validated settings, the client keeps the settings of the kubeconfig
+		return rt
+	}
+	return transport
+}
+
+// OutboundTLS returns the TLS settings of all outbound connections: ClientTLS, restricted to TLS 1.2 and the approved cipher suites in FIPS mode.
+// It returns nil if neither ClientTLS nor FIPS is set, so the connections keep the defaults of Go.
+func (c *Config) OutboundTLS() *ClientTLSConfig {
+	if c.ClientTLS == nil && !c.FIPS {
+		return nil
+	}
+	var result ClientTLSConfig
+	if c.ClientTLS != nil {
+		result = *c.ClientTLS
+	}
+	if c.FIPS {
+		result.fips = true
+		if len(result.CipherSuites) == 0 {
+			result.CipherSuites = fipsCipherSuites
+		}
+	}
+	return &result
+}
+
+// Transport returns an HTTP transport which uses the proxy and the CA bundles, see ProxyConfig.Transport, and the OutboundTLS settings
+func (c *Config) Transport() (*http.Transport, error) {
+	transport, err := c.Proxy.Transport()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := c.OutboundTLS()
+	if tlsConfig == nil {
+		return transport, nil
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	if err := tlsConfig.apply(transport.TLSClientConfig, true); err != nil {
+		return nil, err
+	}
+	return transport, nil
+}
+
+// HTTPClient returns an HTTP client with the given timeout which uses the Transport of the config
+func (c *Config) HTTPClient(timeout time.Duration) (*http.Client, error) {
+	transport, err := c.Transport()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ClientTLSConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clienttls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	crt, key := newCertificate(t, "installer", time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	certFile := filepath.Join(dir, "client.crt")
+	require.NoError(t, ioutil.WriteFile(certFile, crt, 0600))
+	keyFile := filepath.Join(dir, "client.key")
+	require.NoError(t, ioutil.WriteFile(keyFile, key, 0600))
+
+	t.Run("Validate", func(t *testing.T) {
+		require.NoError(t, (&ClientTLSConfig{}).validate(false))
+		require.NoError(t, (&ClientTLSConfig{MinVersion: "1.3", CertFile: certFile, KeyFile: keyFile}).validate(false))
+		require.NoError(t, (&ClientTLSConfig{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA"}}).validate(false))
+		require.Error(t, (&ClientTLSConfig{MinVersion: "1.1"}).validate(false))
+		require.Error(t, (&ClientTLSConfig{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}).validate(false))
+		require.Error(t, (&ClientTLSConfig{CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}}).validate(false))
+		require.Error(t, (&ClientTLSConfig{CertFile: certFile}).validate(false))
+		require.Error(t, (&ClientTLSConfig{CertFile: keyFile, KeyFile: certFile}).validate(false))
+	})
+
+	t.Run("Validate in FIPS mode", func(t *testing.T) {
+		require.NoError(t, (&ClientTLSConfig{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}, CertFile: certFile, KeyFile: keyFile}).validate(true))
+		require.Error(t, (&ClientTLSConfig{MinVersion: "1.3"}).validate(true))
+		require.Error(t, (&ClientTLSConfig{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA"}}).validate(true))
+	})
+
+	t.Run("Transport applies the TLS settings and the client certificate", func(t *testing.T) {
+		cfg := &Config{ClientTLS: &ClientTLSConfig{
+			MinVersion:   "1.3",
+			CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+			CertFile:     certFile,
+			KeyFile:      keyFile,
+		}}
+		transport, err := cfg.Transport()
+		require.NoError(t, err)
+		require.Equal(t, uint16(tls.VersionTLS13), transport.TLSClientConfig.MinVersion)
+		require.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}, transport.TLSClientConfig.CipherSuites)
+		require.Len(t, transport.TLSClientConfig.Certificates, 1)
+		require.NotNil(t, transport.Proxy)
+	})
+
+	t.Run("FIPS mode restricts the transport to the approved cipher suites", func(t *testing.T) {
+		transport, err := (&Config{FIPS: true}).Transport()
+		require.NoError(t, err)
+		require.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+		require.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MaxVersion)
+		require.Len(t, transport.TLSClientConfig.CipherSuites, len(fipsCipherSuites))
+	})
+
+	t.Run("Transport keeps the defaults without TLS settings", func(t *testing.T) {
+		require.Nil(t, (&Config{}).OutboundTLS())
+		transport, err := (&Config{}).Transport()
+		require.NoError(t, err)
+		require.Nil(t, transport.TLSClientConfig)
+	})
+
+	t.Run("Kubernetes clients use the TLS settings without the client certificate", func(t *testing.T) {
+		clientTLS := &ClientTLSConfig{MinVersion: "1.3", CertFile: certFile, KeyFile: keyFile}
+		res, err := RestConfig(KubeconfigSource{Content: correctKubeConfig(), TLS: clientTLS})
+		require.NoError(t, err)
+		require.NotNil(t, res.WrapTransport)
+
+		wrapped, ok := res.WrapTransport(&http.Transport{}).(*http.Transport)
+		require.True(t, ok)
+		require.Equal(t, uint16(tls.VersionTLS13), wrapped.TLSClientConfig.MinVersion)
+		require.Empty(t, wrapped.TLSClientConfig.Certificates)
+	})
+}
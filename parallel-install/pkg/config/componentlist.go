@@ -17,12 +17,28 @@ const defaultNamespace = "kyma-system"
 type ComponentList struct {
 	Prerequisites []ComponentDefinition
 	Components    []ComponentDefinition
+	// Bundles are named subsets of the components which can be selected by Config.Bundles
+	Bundles Bundles
 }
 
 // ComponentDefinition defines a component in components list
 type ComponentDefinition struct {
 	Name      string
 	Namespace string
+	// ReleaseName of the Helm release. Defaults to the component name or the release name template.
+	ReleaseName string `yaml:"releaseName,omitempty" json:"releaseName,omitempty"`
+	// SkipIf lists conditions under which the component is not deployed. The component is skipped if any of them holds.
+	SkipIf []SkipCondition `yaml:"skipIf,omitempty" json:"skipIf,omitempty"`
+	// RequiresAPIs lists the APIs which have to be served before the component is deployed
+	RequiresAPIs []APIRequirement `yaml:"requiresAPIs,omitempty" json:"requiresAPIs,omitempty"`
+}
+
+// Release returns the name of the Helm release of the component
+func (c ComponentDefinition) Release() string {
+	if c.ReleaseName != "" {
+		return c.ReleaseName
+	}
+	return c.Name
 }
 
 // ComponentListData is the raw component list
@@ -30,10 +46,11 @@ type ComponentListData struct {
 	DefaultNamespace string `yaml:"defaultNamespace" json:"defaultNamespace"`
 	Prerequisites    []ComponentDefinition
 	Components       []ComponentDefinition
+	Bundles          Bundles `yaml:"bundles,omitempty" json:"bundles,omitempty"`
 }
 
 func (cld *ComponentListData) process() *ComponentList {
-	compList := &ComponentList{}
+	compList := &ComponentList{Bundles: cld.Bundles}
 
 	// read prerequisites
 	for _, compDef := range cld.Prerequisites {
@@ -87,6 +104,14 @@ func NewComponentList(componentsListPath string) (*ComponentList, error) {
 	return compListData.process(), nil
 }
 
+// NewComponentListFromData creates a new component list out of raw component list data, e.g. embedded in another file
+func NewComponentListFromData(data ComponentListData) *ComponentList {
+	if data.DefaultNamespace == "" {
+		data.DefaultNamespace = defaultNamespace
+	}
+	return data.process()
+}
+
 // Remove drops any component definition with this particular name (independent whether it is listed as prequisite or component)
 func (cl *ComponentList) Remove(compName string) {
 	for idx, comp := range cl.Prerequisites {
@@ -6,6 +6,31 @@ import (
 	"time"
 
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
+)
+
+//InstallationBackend defines how Kyma components are installed
+type InstallationBackend string
+
+const (
+	//HelmBackend deploys every component as a Helm release (classic installation)
+	HelmBackend InstallationBackend = "helm"
+	//ModulesBackend adds every component as a module to the Kyma custom resource of the lifecycle manager (modular installation)
+	ModulesBackend InstallationBackend = "modules"
+	//SimulationBackend simulates the component operations with configurable latency and failures, without applying anything
+	SimulationBackend InstallationBackend = "simulation"
+)
+
+//UninstallMode defines what an uninstallation removes
+type UninstallMode string
+
+const (
+	//FullUninstall removes the releases and the Kyma namespaces with all their resources
+	FullUninstall UninstallMode = "full"
+	//SoftReset removes the workloads and releases, but keeps the CRDs, the PersistentVolumeClaims, and the Kyma namespaces,
+	//so that custom resources created by users and persisted data survive a reinstallation
+	SoftReset UninstallMode = "soft-reset"
 )
 
 //Configures various install/uninstall operation parameters.
@@ -22,6 +47,8 @@ type Config struct {
 	QuitTimeout time.Duration
 	//Timeout for the underlying Helm client
 	HelmTimeoutSeconds int
+	//Maximum time a component waits for the APIs it requires before it is deployed, defaults to 5 minutes
+	RequiredAPIsTimeout time.Duration
 	//Initial interval used for exponent backoff retry policy
 	BackoffInitialIntervalSeconds int
 	//Maximum time used for exponent backoff retry policy
@@ -34,6 +61,8 @@ type Config struct {
 	Profile string
 	// Kyma components list
 	ComponentList *ComponentList
+	// Bundles of the component list to install. All components are installed if no bundle is selected.
+	Bundles []string
 	// Path to Kyma resources
 	ResourcePath string
 	// Path to Kyma installation resources
@@ -42,8 +71,109 @@ type Config struct {
 	KubeconfigSource KubeconfigSource
 	//Kyma version
 	Version string
+	//Source of the component charts, recorded in the metadata of the releases. Components installed from another source
+	//are upgraded even if their version did not change. Not recorded if nil.
+	Source *Source
 	//Atomic deployment
 	Atomic bool
+	//Skips the upgrade of components whose rendered manifests and values did not change since their last deployment
+	SkipUnchangedComponents bool
+	//Seeds the overrides of each component with the values of its deployed release, so values set outside of Kyma are kept on upgrades
+	SeedDeployedValues bool
+	//Custom Kyma domain. If empty, the domain is detected from the cluster.
+	Domain string
+	//Certificate of the custom domain. If nil, the default certificate of the cluster type is used.
+	TLS *TLSConfig
+	//Installation backend: helm|modules|simulation. Defaults to helm.
+	Backend InstallationBackend
+	//Release channel of the modules, only used by the modules backend
+	ModuleChannel string
+	//Latency and failures of the simulation backend
+	Simulation *SimulationConfig
+	//Retry policy of Kubernetes operations. Defaults to three attempts with a fixed delay of two seconds.
+	RetryPolicy retry.Policy
+	//Local directory or HTTP(S) URL the Helm release state is stored to before an upgrade or uninstallation. Backup is disabled if empty.
+	BackupLocation string
+	//Velero backup which is taken before Kyma is upgraded. Disabled if nil.
+	Velero *VeleroConfig
+	//etcd snapshot which is taken before Kyma is upgraded. Disabled if nil.
+	EtcdSnapshot *EtcdSnapshotConfig
+	//Deadline and forced finalization of the Kyma namespace deletion. If nil, namespaces with running Pods are skipped and the removal is not awaited.
+	NamespaceDeletion *NamespaceDeletionConfig
+	//Pods which prevent the deletion of a Kyma namespace. By default, every running Pod blocks the deletion.
+	NamespacePodCheck PodCheckPolicy
+	//Records phase transitions and component failures as Kubernetes Events in the cluster. Disabled if nil.
+	Events *EventsConfig
+	//Inspects the cluster and adjusts overrides and preflight expectations to the quirks of its provider
+	ProviderQuirks bool
+	//Compatibility mode for OpenShift clusters. Disabled if nil.
+	OpenShift *OpenShiftConfig
+	//Preflight check that component images support the architectures of the cluster nodes. Disabled if nil.
+	ImageCheck *ImageCheckConfig
+	//Deploys to clusters whose Kubernetes version is outside of the supported range instead of failing the preflight check
+	SkipKubernetesVersionCheck bool
+	//Handling of existing namespaces which are not owned by Kyma: adopt|fail|skip. Empty reuses them with a warning.
+	NamespaceConflicts NamespaceConflictPolicy
+	//Handling of existing Helm releases of the components which were not installed by Kyma: adopt|fail|replace. Empty upgrades them with a warning.
+	ReleaseConflicts ReleaseConflictPolicy
+	//Strict mode for override keys which are not defined in the values of any chart: warn|fail. Empty does not check them.
+	UnknownOverrides UnknownOverridesPolicy
+	//Strength requirements of generated and provided passwords, checked when the deployment is created. Disabled if nil.
+	PasswordPolicy *PasswordPolicy
+	//Adjustments of the resource requests and limits of the workloads of selected components, applied in order. Helm backend only.
+	ResourceScaling []ResourceScaling
+	//Node selector, tolerations, and topology spread constraints injected into the workloads of all components. Helm backend only. Disabled if nil.
+	SchedulingConstraints *SchedulingConstraints
+	//Priority classes created for Kyma and assigned to the workloads of the components. Helm backend only. Disabled if nil.
+	PriorityClasses *PriorityClassConfig
+	//Replicas, anti-affinity, and PodDisruptionBudgets of the Deployments of the selected components. Helm backend only. Disabled if nil.
+	HighAvailability *HighAvailabilityConfig
+	//FIPS mode: keys and certificates are generated with approved parameters, and certificates with other parameters or the built-in default certificates are rejected
+	FIPS bool
+	//Templates of user-facing messages per message ID. Missing messages fall back to the English defaults.
+	Messages messages.Catalog
+	//Chart repositories with credentials and TLS settings, used to resolve the dependencies of component charts
+	HelmRepositories []HelmRepository
+	//Storage driver of the Helm releases: secrets|configmaps|sql. Defaults to secrets.
+	HelmStorage HelmStorageDriver
+	//Connection string of the PostgreSQL database used by the sql storage driver
+	HelmSQLConnectionString string
+	//Templates of the Helm release names and namespaces of the components. Disabled if nil.
+	ReleaseNaming *ReleaseNaming
+	//Installs Kyma as a namespace-scoped tenant. Cannot be combined with ReleaseNaming. Disabled if nil.
+	Tenancy *TenancyConfig
+	//File the observed component durations are kept in, so that heavyweight components are started first. Disabled if empty.
+	ComponentDurationsFile string
+	//Upper limit of the summed size in bytes of the charts deployed in parallel. Larger charts are deployed alone. No limit if 0.
+	ChartMemoryBudget int64
+	//Number of resources Helm creates in parallel when a release is installed. No limit if 0.
+	HelmCreateChunkSize int
+	//Stores the durations, retries and failures of every run, so that runs can be compared. Disabled if nil.
+	Statistics *StatisticsConfig
+	//HTTP(S) proxy and additional CA bundles of all outbound connections. Disabled if nil.
+	Proxy *ProxyConfig
+	//Minimum TLS version, cipher suites, and client certificate of all outbound connections. Restricted to the approved cipher suites in FIPS mode. Go defaults if nil.
+	ClientTLS *ClientTLSConfig
+	//Pushes the metrics of a successful deployment to the Prometheus Pushgateway of the cluster. Disabled if nil.
+	MetricsPush *MetricsPushConfig
+	//Enables or disables features by name, e.g. experimental features which are disabled by default. Unset features keep their default.
+	FeatureGates FeatureGates
+	//What an uninstallation removes: full|soft-reset. Defaults to full.
+	UninstallMode UninstallMode
+	//Exports the custom resources created by users before an uninstallation, so that they can be imported after a reinstallation. Disabled if nil.
+	UserResources *UserResourcesConfig
+	//Period, interval, and restart threshold of the health monitoring after an installation. Defaults are used if nil.
+	Monitor *MonitorConfig
+	//Batches and verification of an upgrade of an installed Kyma. All components are upgraded in parallel if nil.
+	StagedUpgrade *StagedUpgradeConfig
+	//Windows a deployment is restricted to. A deployment waits for the next window and is cancelled when the window closes. Disabled if nil.
+	MaintenanceWindow *MaintenanceWindow
+	//Coalesces identical process updates and limits the rate of running updates before they are delivered to the callback. Disabled if nil.
+	UpdateThrottle *UpdateThrottleConfig
+	//Maximum number of Kyma namespaces deleted in parallel during an uninstallation. Defaults to 5.
+	NamespaceDeletionConcurrency int
+	//Kyma namespaces which are kept during an uninstallation, selected by name or labels. Disabled if nil.
+	ExcludedNamespaces *NamespaceExclusion
 }
 
 // KubeconfigSource aggregates kubeconfig in a form of either a path or a raw content.
@@ -53,6 +183,56 @@ type KubeconfigSource struct {
 	Path string
 	// Kubeconfig content in YAML format
 	Content string
+	// Context of the kubeconfig to use. Defaults to the current context.
+	Context string
+	// Cluster of the kubeconfig to use instead of the cluster of the context
+	Cluster string
+	// User of the kubeconfig to use instead of the user of the context
+	User string
+	// Proxy and CA bundles of the connections to the API server. NewDeployment and NewDeletion set it to Config.Proxy.
+	Proxy *ProxyConfig
+	// TLS versions and cipher suites of the connections to the API server. NewDeployment and NewDeletion set it to Config.OutboundTLS.
+	TLS *ClientTLSConfig
+}
+
+// Retry returns the configured retry policy or the default policy
+func (c *Config) Retry() retry.Policy {
+	if c.RetryPolicy == nil {
+		return retry.Default()
+	}
+	return c.RetryPolicy
+}
+
+// Catalog returns the configured message catalog or the default catalog
+func (c *Config) Catalog() messages.Catalog {
+	if c.Messages == nil {
+		return messages.Default
+	}
+	return c.Messages
+}
+
+// KeptKinds returns the kinds of the release resources which are kept by the uninstallation
+func (c *Config) KeptKinds() []string {
+	if c.UninstallMode != SoftReset {
+		return nil
+	}
+	return []string{"CustomResourceDefinition", "PersistentVolumeClaim"}
+}
+
+// Naming returns the release naming templates of the tenant or the configured release naming
+func (c *Config) Naming() *ReleaseNaming {
+	if c.Tenancy != nil {
+		return c.Tenancy.ReleaseNaming()
+	}
+	return c.ReleaseNaming
+}
+
+// Tenant returns the tenant of a namespace-scoped installation or an empty string
+func (c *Config) Tenant() string {
+	if c.Tenancy == nil {
+		return ""
+	}
+	return c.Tenancy.Tenant
 }
 
 // validate verifies that mandatory options are provided
@@ -63,6 +243,91 @@ func (c *Config) validate() error {
 	if c.ComponentList == nil {
 		return fmt.Errorf("Component list undefined")
 	}
+	if err := c.ComponentList.validateSkipConditions(); err != nil {
+		return err
+	}
+	if err := c.ComponentList.validateBundles(); err != nil {
+		return err
+	}
+	if err := c.ComponentList.validateRequiredAPIs(); err != nil {
+		return err
+	}
+	if _, err := c.ComponentList.Select(c.Bundles); err != nil {
+		return err
+	}
+	switch c.Backend {
+	case "", HelmBackend, ModulesBackend, SimulationBackend:
+	default:
+		return fmt.Errorf("Unknown installation backend '%s'", c.Backend)
+	}
+	if c.Source != nil {
+		if err := c.Source.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Simulation != nil {
+		if err := c.Simulation.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Tenancy != nil {
+		if c.ReleaseNaming != nil {
+			return fmt.Errorf("Release naming cannot be combined with tenancy")
+		}
+		if err := c.Tenancy.validate(); err != nil {
+			return err
+		}
+	}
+	if naming := c.Naming(); naming != nil {
+		if err := naming.validate(c.ComponentList); err != nil {
+			return err
+		}
+	}
+	if c.ChartMemoryBudget < 0 || c.HelmCreateChunkSize < 0 {
+		return fmt.Errorf("Chart memory budget and Helm create chunk size cannot be < 0")
+	}
+	if err := c.FeatureGates.validate(); err != nil {
+		return err
+	}
+	if c.Proxy != nil {
+		if err := c.Proxy.validate(); err != nil {
+			return err
+		}
+	}
+	if c.ClientTLS != nil {
+		if err := c.ClientTLS.validate(c.FIPS); err != nil {
+			return err
+		}
+	}
+	if c.UpdateThrottle != nil {
+		if err := c.UpdateThrottle.validate(); err != nil {
+			return err
+		}
+	}
+	if c.UserResources != nil {
+		if err := c.UserResources.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Statistics != nil && c.Statistics.MaxRuns < 0 {
+		return fmt.Errorf("Maximum number of statistics runs cannot be < 0")
+	}
+	if err := c.validateHelmStorage(); err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, repository := range c.HelmRepositories {
+		if err := repository.validate(); err != nil {
+			return err
+		}
+		if names[repository.Name] {
+			return fmt.Errorf("Helm repository '%s' is defined twice", repository.Name)
+		}
+		names[repository.Name] = true
+	}
+	if err := c.Messages.Validate(); err != nil {
+		return fmt.Errorf("Invalid message catalog: %v", err)
+	}
 	return nil
 }
 
@@ -71,6 +336,19 @@ func (c *Config) ValidateDeletion() error {
 	if err := c.validate(); err != nil { //deployment requires all core options
 		return err
 	}
+	if c.NamespaceDeletion != nil {
+		if err := c.NamespaceDeletion.validate(); err != nil {
+			return err
+		}
+	}
+	if c.NamespaceDeletionConcurrency < 0 {
+		return fmt.Errorf("Namespace deletion concurrency cannot be < 0")
+	}
+	switch c.UninstallMode {
+	case "", FullUninstall, SoftReset:
+	default:
+		return fmt.Errorf("Unknown uninstall mode '%s'", c.UninstallMode)
+	}
 	return nil
 }
 
@@ -88,6 +366,83 @@ func (c *Config) ValidateDeployment() error {
 	if c.Version == "" {
 		return fmt.Errorf("Version is empty")
 	}
+	if err := c.NamespaceConflicts.validate(); err != nil {
+		return err
+	}
+	if err := c.ReleaseConflicts.validate(); err != nil {
+		return err
+	}
+	if err := c.UnknownOverrides.validate(); err != nil {
+		return err
+	}
+	if c.PasswordPolicy != nil {
+		if err := c.PasswordPolicy.validate(); err != nil {
+			return err
+		}
+	}
+	for i := range c.ResourceScaling {
+		if err := c.ResourceScaling[i].validate(); err != nil {
+			return err
+		}
+	}
+	if c.SchedulingConstraints != nil {
+		if err := c.SchedulingConstraints.validate(); err != nil {
+			return err
+		}
+	}
+	if c.PriorityClasses != nil {
+		if err := c.PriorityClasses.validate(); err != nil {
+			return err
+		}
+	}
+	if c.HighAvailability != nil {
+		if err := c.HighAvailability.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Velero != nil {
+		if err := c.Velero.validate(); err != nil {
+			return err
+		}
+	}
+	if c.EtcdSnapshot != nil {
+		if err := c.EtcdSnapshot.validate(); err != nil {
+			return err
+		}
+	}
+	if c.ImageCheck != nil {
+		if err := c.ImageCheck.validate(); err != nil {
+			return err
+		}
+	}
+	if c.MetricsPush != nil {
+		if err := c.MetricsPush.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Monitor != nil {
+		if err := c.Monitor.validate(); err != nil {
+			return err
+		}
+	}
+	if c.StagedUpgrade != nil {
+		if err := c.StagedUpgrade.validate(); err != nil {
+			return err
+		}
+	}
+	if c.MaintenanceWindow != nil {
+		if err := c.MaintenanceWindow.validate(); err != nil {
+			return err
+		}
+	}
+	if c.TLS != nil {
+		if c.Domain == "" {
+			return fmt.Errorf("Domain is required when a TLS certificate is provided")
+		}
+		if err := c.TLS.validate(c.Domain); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,6 +41,34 @@ func Test_ValidateDeletion(t *testing.T) {
 		err = config.ValidateDeletion()
 		assert.NoError(t, err)
 	})
+
+	t.Run("Unknown uninstall mode", func(t *testing.T) {
+		config := Config{
+			WorkersCount:  1,
+			ComponentList: newComponentList(t),
+			UninstallMode: "keep-all",
+		}
+		err := config.ValidateDeletion()
+		assert.EqualError(t, err, "Unknown uninstall mode 'keep-all'")
+	})
+
+	t.Run("Invalid user resources", func(t *testing.T) {
+		config := Config{
+			WorkersCount:  1,
+			ComponentList: newComponentList(t),
+			UserResources: &UserResourcesConfig{Location: "/tmp", Resources: []string{"functions"}},
+		}
+		err := config.ValidateDeletion()
+		assert.EqualError(t, err, "User resource 'functions' must be in the format <resource>.<version>.<group>")
+
+		config.UserResources = &UserResourcesConfig{Location: "/tmp", Resources: []string{"functions.v1alpha1.serverless.kyma-project.io"}}
+		assert.NoError(t, config.ValidateDeletion())
+		gvrs, err := config.UserResources.GroupVersionResources()
+		assert.NoError(t, err)
+		assert.Equal(t, "serverless.kyma-project.io", gvrs[0].Group)
+		assert.Equal(t, "v1alpha1", gvrs[0].Version)
+		assert.Equal(t, "functions", gvrs[0].Resource)
+	})
 }
 
 func Test_ValidateDeployment(t *testing.T) {
@@ -101,6 +130,63 @@ func Test_ValidateDeployment(t *testing.T) {
 		err := config.ValidateDeployment()
 		assert.NoError(t, err)
 	})
+
+	t.Run("Pushgateway URL invalid", func(t *testing.T) {
+		fpath := filePath(t)
+		config = Config{
+			WorkersCount:             1,
+			ComponentList:            newComponentList(t),
+			ResourcePath:             filepath.Dir(fpath),
+			InstallationResourcePath: filepath.Dir(fpath),
+			Version:                  "abc",
+			MetricsPush:              &MetricsPushConfig{URL: "pushgateway:9091"},
+		}
+		err := config.ValidateDeployment()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Pushgateway URL 'pushgateway:9091' is invalid")
+	})
+
+	t.Run("Namespace conflict policy unknown", func(t *testing.T) {
+		fpath := filePath(t)
+		config = Config{
+			WorkersCount:             1,
+			ComponentList:            newComponentList(t),
+			ResourcePath:             filepath.Dir(fpath),
+			InstallationResourcePath: filepath.Dir(fpath),
+			Version:                  "abc",
+			NamespaceConflicts:       "ignore",
+		}
+		err := config.ValidateDeployment()
+		assert.EqualError(t, err, "Unknown namespace conflict policy 'ignore': use adopt, fail, or skip")
+	})
+
+	t.Run("Release conflict policy unknown", func(t *testing.T) {
+		fpath := filePath(t)
+		config = Config{
+			WorkersCount:             1,
+			ComponentList:            newComponentList(t),
+			ResourcePath:             filepath.Dir(fpath),
+			InstallationResourcePath: filepath.Dir(fpath),
+			Version:                  "abc",
+			ReleaseConflicts:         "skip",
+		}
+		err := config.ValidateDeployment()
+		assert.EqualError(t, err, "Unknown release conflict policy 'skip': use adopt, fail, or replace")
+	})
+
+	t.Run("Unknown overrides policy unknown", func(t *testing.T) {
+		fpath := filePath(t)
+		config = Config{
+			WorkersCount:             1,
+			ComponentList:            newComponentList(t),
+			ResourcePath:             filepath.Dir(fpath),
+			InstallationResourcePath: filepath.Dir(fpath),
+			Version:                  "abc",
+			UnknownOverrides:         "ignore",
+		}
+		err := config.ValidateDeployment()
+		assert.EqualError(t, err, "Unknown policy for unknown overrides 'ignore': use warn or fail")
+	})
 }
 
 func newComponentList(t *testing.T) *ComponentList {
@@ -114,3 +200,122 @@ func filePath(t *testing.T) string {
 	assert.True(t, ok)
 	return fpath
 }
+
+func Test_ValidateHelmRepositories(t *testing.T) {
+	newConfig := func(repositories ...HelmRepository) Config {
+		return Config{
+			WorkersCount:     1,
+			ComponentList:    newComponentList(t),
+			HelmRepositories: repositories,
+		}
+	}
+
+	t.Run("Valid repositories", func(t *testing.T) {
+		config := newConfig(
+			HelmRepository{Name: "harbor", URL: "https://harbor.example.com/chartrepo/kyma", Username: "robot", Password: "secret"},
+			HelmRepository{Name: "nexus", URL: "https://nexus.example.com/repository/helm", CAFile: filePath(t)},
+		)
+		require.NoError(t, config.ValidateDeletion())
+	})
+
+	t.Run("Invalid repositories", func(t *testing.T) {
+		for name, repository := range map[string]HelmRepository{
+			"name is missing":        {URL: "https://harbor.example.com"},
+			"URL is invalid":         {Name: "harbor", URL: "harbor"},
+			"key is missing":         {Name: "harbor", URL: "https://harbor.example.com", CertFile: filePath(t)},
+			"password is missing":    {Name: "harbor", URL: "https://harbor.example.com", Username: "robot"},
+			"CA file does not exist": {Name: "harbor", URL: "https://harbor.example.com", CAFile: "/not/existing/ca.crt"},
+		} {
+			config := newConfig(repository)
+			require.Error(t, config.ValidateDeletion(), name)
+		}
+	})
+
+	t.Run("Duplicate names", func(t *testing.T) {
+		repository := HelmRepository{Name: "harbor", URL: "https://harbor.example.com"}
+		config := newConfig(repository, repository)
+		require.Error(t, config.ValidateDeletion())
+	})
+}
+
+func Test_ValidateHelmStorage(t *testing.T) {
+	newConfig := func(driver HelmStorageDriver, connection string) Config {
+		return Config{
+			WorkersCount:            1,
+			ComponentList:           newComponentList(t),
+			HelmStorage:             driver,
+			HelmSQLConnectionString: connection,
+		}
+	}
+
+	for _, driver := range []HelmStorageDriver{"", HelmStorageSecrets, HelmStorageConfigMaps} {
+		config := newConfig(driver, "")
+		require.NoError(t, config.ValidateDeletion())
+	}
+	config := newConfig(HelmStorageSQL, "postgresql://helm@db:5432/helm")
+	require.NoError(t, config.ValidateDeletion())
+
+	config = newConfig(HelmStorageSQL, "")
+	require.Error(t, config.ValidateDeletion())
+	config = newConfig("memory", "")
+	require.Error(t, config.ValidateDeletion())
+}
+
+func Test_ValidateTenancy(t *testing.T) {
+	newConfig := func(tenancy *TenancyConfig) Config {
+		return Config{
+			WorkersCount:  1,
+			ComponentList: newComponentList(t),
+			Tenancy:       tenancy,
+		}
+	}
+
+	config := newConfig(&TenancyConfig{Tenant: "acme", SharedComponents: []string{"istio"}})
+	require.NoError(t, config.ValidateDeletion())
+	require.Equal(t, "acme", config.Tenant())
+	require.Equal(t, "acme", config.Naming().InstallationID)
+
+	for _, tenancy := range []*TenancyConfig{
+		{},
+		{Tenant: "Acme"},
+		{Tenant: "a-tenant-with-a-very-long-name"},
+		{Tenant: "acme", SharedComponents: []string{"serverless"}},
+	} {
+		config = newConfig(tenancy)
+		require.Error(t, config.ValidateDeletion())
+	}
+
+	config = newConfig(&TenancyConfig{Tenant: "acme"})
+	config.ReleaseNaming = &ReleaseNaming{InstallationID: "acme"}
+	require.Error(t, config.ValidateDeletion())
+}
+
+func Test_ValidateStagedUpgrade(t *testing.T) {
+	for _, stagedUpgrade := range []*StagedUpgradeConfig{
+		{BatchSize: 3},
+		{BatchSize: 1, VerificationPeriod: time.Minute, OnFailure: PauseOnFailure},
+		{BatchSize: 5, OnFailure: RollbackOnFailure},
+	} {
+		require.NoError(t, stagedUpgrade.validate())
+	}
+
+	for _, stagedUpgrade := range []*StagedUpgradeConfig{
+		{},
+		{BatchSize: -1},
+		{BatchSize: 3, VerificationPeriod: -time.Second},
+		{BatchSize: 3, OnFailure: "retry"},
+	} {
+		require.Error(t, stagedUpgrade.validate())
+	}
+}
+
+func Test_NamespaceExclusion(t *testing.T) {
+	exclusion := &NamespaceExclusion{Names: []string{"istio-system"}, Labels: map[string]string{"keep": "true", "team": "a"}}
+	assert.True(t, exclusion.Excludes("istio-system", nil))
+	assert.True(t, exclusion.Excludes("natss", map[string]string{"keep": "true", "team": "a", "other": "x"}))
+	assert.False(t, exclusion.Excludes("natss", map[string]string{"keep": "true"}))
+	assert.False(t, exclusion.Excludes("natss", nil))
+
+	var disabled *NamespaceExclusion
+	assert.False(t, disabled.Excludes("istio-system", nil))
+}
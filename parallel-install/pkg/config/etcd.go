@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// EtcdSnapshotConfig defines the etcd snapshot which is taken before Kyma is upgraded.
+// The snapshot is saved with etcdctl in an etcd Pod of the cluster, so it requires a cluster whose etcd runs as Pods, such as a kubeadm cluster.
+// The defaults match the static etcd Pods of kubeadm.
+type EtcdSnapshotConfig struct {
+	// Namespace of the etcd Pods. Defaults to kube-system.
+	Namespace string
+	// Label selector of the etcd Pods. Defaults to component=etcd.
+	Selector string
+	// Container of the etcd Pods which provides etcdctl. Defaults to etcd.
+	Container string
+	// Directory in the etcd container the snapshot is saved to. Defaults to /var/lib/etcd, the data directory kubeadm mounts from the node.
+	Directory string
+	// Client certificate files in the etcd container. Default to the certificates of kubeadm in /etc/kubernetes/pki/etcd.
+	CACertFile string
+	CertFile   string
+	KeyFile    string
+	// Maximum time to wait for the snapshot. Defaults to 5 minutes.
+	Timeout time.Duration
+}
+
+// validate verifies that the timeout is not negative
+func (e *EtcdSnapshotConfig) validate() error {
+	if e.Timeout < 0 {
+		return fmt.Errorf("etcd snapshot timeout cannot be negative")
+	}
+	return nil
+}
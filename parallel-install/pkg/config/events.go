@@ -0,0 +1,9 @@
+package config
+
+// EventsConfig defines where the installation history is recorded as Kubernetes Events
+type EventsConfig struct {
+	// Namespace of the ConfigMap the Events refer to. Defaults to kyma-installer.
+	Namespace string
+	// Name of the ConfigMap the Events refer to. Defaults to kyma-installation-history.
+	ConfigMap string
+}
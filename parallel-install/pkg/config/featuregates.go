@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feature is the name of a feature gate
+type Feature string
+
+const (
+	// DurationScheduling starts the components with the longest observed duration first and keeps the observed durations
+	DurationScheduling Feature = "DurationScheduling"
+	// ServerSideApply applies the resources of the preinstaller with server-side apply instead of get, create, and update
+	ServerSideApply Feature = "ServerSideApply"
+)
+
+// FeatureStage is the maturity of a feature
+type FeatureStage string
+
+const (
+	// Alpha features are experimental and disabled by default. They may change or be removed without notice.
+	Alpha FeatureStage = "Alpha"
+	// Beta features are tested and enabled by default, but can still be disabled.
+	Beta FeatureStage = "Beta"
+)
+
+// FeatureSpec defines the default and the maturity of a feature
+type FeatureSpec struct {
+	Default bool
+	Stage   FeatureStage
+}
+
+// KnownFeatures are all features which can be toggled by feature gates
+var KnownFeatures = map[Feature]FeatureSpec{
+	DurationScheduling: {Default: true, Stage: Beta},
+	ServerSideApply:    {Default: false, Stage: Alpha},
+}
+
+// FeatureGates enable or disable features by their name. Features which are not set keep their default.
+type FeatureGates map[string]bool
+
+// Enabled returns whether the feature is enabled
+func (g FeatureGates) Enabled(feature Feature) bool {
+	if enabled, ok := g[string(feature)]; ok {
+		return enabled
+	}
+	return KnownFeatures[feature].Default
+}
+
+// ParseFeatureGates parses feature gates in the format of Kubernetes components, e.g. 'ServerSideApply=true,DurationScheduling=false'
+func ParseFeatureGates(value string) (FeatureGates, error) {
+	gates := FeatureGates{}
+	for _, gate := range strings.Split(value, ",") {
+		gate = strings.TrimSpace(gate)
+		if gate == "" {
+			continue
+		}
+		parts := strings.SplitN(gate, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Feature gate '%s' must be in the format <feature>=<true|false>", gate)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("Feature gate '%s' must be in the format <feature>=<true|false>", gate)
+		}
+		gates[strings.TrimSpace(parts[0])] = enabled
+	}
+	return gates, gates.validate()
+}
+
+// validate verifies that only known features are set
+func (g FeatureGates) validate() error {
+	for name := range g {
+		if _, ok := KnownFeatures[Feature(name)]; !ok {
+			return fmt.Errorf("Unknown feature gate '%s', known feature gates are: %s", name, strings.Join(knownFeatureNames(), ", "))
+		}
+	}
+	return nil
+}
+
+func knownFeatureNames() []string {
+	var names []string
+	for feature := range KnownFeatures {
+		names = append(names, string(feature))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FeatureGates(t *testing.T) {
+	t.Run("Unset features keep their default", func(t *testing.T) {
+		var gates FeatureGates
+		require.True(t, gates.Enabled(DurationScheduling))
+		require.False(t, gates.Enabled(ServerSideApply))
+	})
+
+	t.Run("Set features override the default", func(t *testing.T) {
+		gates := FeatureGates{string(DurationScheduling): false, string(ServerSideApply): true}
+		require.False(t, gates.Enabled(DurationScheduling))
+		require.True(t, gates.Enabled(ServerSideApply))
+	})
+
+	t.Run("Parse feature gates", func(t *testing.T) {
+		gates, err := ParseFeatureGates("ServerSideApply=true, DurationScheduling=false")
+		require.NoError(t, err)
+		require.Equal(t, FeatureGates{"ServerSideApply": true, "DurationScheduling": false}, gates)
+
+		gates, err = ParseFeatureGates("")
+		require.NoError(t, err)
+		require.Empty(t, gates)
+	})
+
+	t.Run("Invalid feature gates", func(t *testing.T) {
+		_, err := ParseFeatureGates("ServerSideApply")
+		require.EqualError(t, err, "Feature gate 'ServerSideApply' must be in the format <feature>=<true|false>")
+
+		_, err = ParseFeatureGates("ServerSideApply=yes")
+		require.EqualError(t, err, "Feature gate 'ServerSideApply=yes' must be in the format <feature>=<true|false>")
+
+		_, err = ParseFeatureGates("DAGScheduling=true")
+		require.EqualError(t, err, "Unknown feature gate 'DAGScheduling', known feature gates are: DurationScheduling, ServerSideApply")
+	})
+
+	t.Run("Unknown feature gates fail the validation", func(t *testing.T) {
+		config := Config{
+			WorkersCount:  1,
+			ComponentList: newComponentList(t),
+			FeatureGates:  FeatureGates{"NewDeletion": true},
+		}
+		err := config.ValidateDeletion()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Unknown feature gate 'NewDeletion'")
+	})
+}
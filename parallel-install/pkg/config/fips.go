@@ -0,0 +1,87 @@
+package config
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/pkg/errors"
+)
+
+const (
+	// FIPSMinRSABits is the minimum size of RSA keys in FIPS mode
+	FIPSMinRSABits = 2048
+	// FIPSDefaultRSABits is the size of the RSA keys the installer generates in FIPS mode
+	FIPSDefaultRSABits = 3072
+)
+
+// fipsSignatureAlgorithms are the approved signature algorithms of certificates: RSA and ECDSA with SHA-2
+var fipsSignatureAlgorithms = map[x509.SignatureAlgorithm]bool{
+	x509.SHA256WithRSA:    true,
+	x509.SHA384WithRSA:    true,
+	x509.SHA512WithRSA:    true,
+	x509.SHA256WithRSAPSS: true,
+	x509.SHA384WithRSAPSS: true,
+	x509.SHA512WithRSAPSS: true,
+	x509.ECDSAWithSHA256:  true,
+	x509.ECDSAWithSHA384:  true,
+	x509.ECDSAWithSHA512:  true,
+}
+
+// CheckFIPSKey verifies that a public key uses approved parameters: RSA with at least 2048 bits, or ECDSA on the curves P-256, P-384 or P-521.
+// The source names the key in the returned ErrNotFIPSCompliant.
+func CheckFIPSKey(source string, key crypto.PublicKey) error {
+	var reason string
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if bits := k.N.BitLen(); bits < FIPSMinRSABits {
+			reason = fmt.Sprintf("RSA key of %d bits, at least %d bits are required", bits, FIPSMinRSABits)
+		}
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+		default:
+			reason = fmt.Sprintf("ECDSA curve %s is not approved", k.Curve.Params().Name)
+		}
+	default:
+		reason = fmt.Sprintf("key type %T is not approved", key)
+	}
+	if reason != "" {
+		return &installerrors.ErrNotFIPSCompliant{Source: source, Reason: reason}
+	}
+	return nil
+}
+
+// CheckFIPSCertificate verifies the keys and signature algorithms of all PEM encoded certificates, e.g. of a certificate chain.
+// Certificates signed with SHA-1 or MD5 are rejected.
+func CheckFIPSCertificate(source string, crt []byte) error {
+	found := false
+	for block, rest := pem.Decode(crt); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		found = true
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to parse certificate of %s", source)
+		}
+		if !fipsSignatureAlgorithms[cert.SignatureAlgorithm] {
+			return &installerrors.ErrNotFIPSCompliant{
+				Source: source,
+				Reason: fmt.Sprintf("signature algorithm %s of certificate '%s' is not approved", cert.SignatureAlgorithm, cert.Subject.CommonName),
+			}
+		}
+		if err := CheckFIPSKey(source, cert.PublicKey); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return fmt.Errorf("No PEM encoded certificate found in %s", source)
+	}
+	return nil
+}
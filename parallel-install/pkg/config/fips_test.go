@@ -0,0 +1,65 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckFIPSCertificate(t *testing.T) {
+	now := time.Now()
+
+	t.Run("Approved certificate", func(t *testing.T) {
+		crt, _ := newCertificate(t, "*.kyma.example.com", now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, CheckFIPSCertificate("global.tlsCrt", crt))
+	})
+
+	t.Run("Small RSA key", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+		crt := selfSigned(t, &key.PublicKey, key)
+
+		err = CheckFIPSCertificate("global.tlsCrt", crt)
+		var notCompliant *installerrors.ErrNotFIPSCompliant
+		require.True(t, errors.As(err, &notCompliant))
+		require.Equal(t, "global.tlsCrt", notCompliant.Source)
+		require.Contains(t, notCompliant.Reason, "1024 bits")
+	})
+
+	t.Run("Curve not approved", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+		require.NoError(t, err)
+
+		err = CheckFIPSKey("signing", &key.PublicKey)
+		var notCompliant *installerrors.ErrNotFIPSCompliant
+		require.True(t, errors.As(err, &notCompliant))
+		require.Contains(t, notCompliant.Reason, "P-224")
+	})
+
+	t.Run("No certificate", func(t *testing.T) {
+		require.Error(t, CheckFIPSCertificate("global.tlsCrt", []byte("no PEM data")))
+	})
+}
+
+func selfSigned(t *testing.T, pub interface{}, priv interface{}) []byte {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kyma.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, priv)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// HelmRepository is a chart repository used to resolve the dependencies of component charts
+type HelmRepository struct {
+	// Name of the repository. Dependencies can refer to it as "@name" or "alias:name".
+	Name string
+	// URL of the repository. Dependencies declaring this URL use the credentials and TLS settings of the repository.
+	URL string
+	// Username and Password for basic authentication
+	Username string
+	Password string
+	// CAFile verifies the certificate of the repository, e.g. a self-signed CA
+	CAFile string
+	// CertFile and KeyFile are the client certificate for mutual TLS
+	CertFile string
+	KeyFile  string
+	// InsecureSkipTLSVerify disables the verification of the certificate of the repository
+	InsecureSkipTLSVerify bool
+}
+
+// validate verifies the repository settings
+func (r HelmRepository) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("Name of Helm repository '%s' is empty", r.URL)
+	}
+	if _, err := url.ParseRequestURI(r.URL); err != nil {
+		return fmt.Errorf("URL of Helm repository '%s' is invalid: %v", r.Name, err)
+	}
+	if (r.CertFile == "") != (r.KeyFile == "") {
+		return fmt.Errorf("Helm repository '%s' requires both a client certificate and a key", r.Name)
+	}
+	if (r.Username == "") != (r.Password == "") {
+		return fmt.Errorf("Helm repository '%s' requires both a username and a password", r.Name)
+	}
+	for _, file := range []string{r.CAFile, r.CertFile, r.KeyFile} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			return fmt.Errorf("TLS file '%s' of Helm repository '%s' not found", file, r.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package config
+
+import "fmt"
+
+// HelmStorageDriver defines where Helm stores the release data
+type HelmStorageDriver string
+
+const (
+	// HelmStorageSecrets stores releases in Secrets (default)
+	HelmStorageSecrets HelmStorageDriver = "secrets"
+	// HelmStorageConfigMaps stores releases in ConfigMaps
+	HelmStorageConfigMaps HelmStorageDriver = "configmaps"
+	// HelmStorageSQL stores releases in a PostgreSQL database
+	HelmStorageSQL HelmStorageDriver = "sql"
+)
+
+// validateHelmStorage verifies the Helm storage driver and its connection settings
+func (c *Config) validateHelmStorage() error {
+	switch c.HelmStorage {
+	case "", HelmStorageSecrets, HelmStorageConfigMaps:
+	case HelmStorageSQL:
+		if c.HelmSQLConnectionString == "" {
+			return fmt.Errorf("Helm storage driver '%s' requires a connection string", c.HelmStorage)
+		}
+	default:
+		return fmt.Errorf("Unknown Helm storage driver '%s'", c.HelmStorage)
+	}
+	return nil
+}
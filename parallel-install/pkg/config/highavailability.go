@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+)
+
+const (
+	defaultHAReplicas    = 2
+	defaultHATopologyKey = "kubernetes.io/hostname"
+)
+
+// defaultHAComponents are the components whose Deployments are stateless and can run with several replicas
+var defaultHAComponents = []string{"api-gateway", "application-connector", "eventing", "ory", "serverless"}
+
+// HighAvailabilityConfig runs the Deployments of the selected components with several replicas,
+// spreads the replicas over the nodes, and protects them with PodDisruptionBudgets
+type HighAvailabilityConfig struct {
+	// Minimum number of replicas of the Deployments. Defaults to 2.
+	Replicas int32
+	// Components whose Deployments are highly available.
+	// Defaults to api-gateway, application-connector, eventing, ory and serverless.
+	Components []string
+	// Node label the replicas are spread over, e.g. topology.kubernetes.io/zone. Defaults to kubernetes.io/hostname.
+	TopologyKey string
+	// RequiredAntiAffinity schedules the replicas of a Deployment only on different nodes or zones instead of preferring it
+	RequiredAntiAffinity bool
+}
+
+// validate verifies the number of replicas
+func (h *HighAvailabilityConfig) validate() error {
+	if h.Replicas < 0 || h.Replicas == 1 {
+		return fmt.Errorf("High availability requires at least 2 replicas")
+	}
+	return nil
+}
+
+// MinReplicas returns the minimum number of replicas of the Deployments
+func (h *HighAvailabilityConfig) MinReplicas() int32 {
+	if h.Replicas == 0 {
+		return defaultHAReplicas
+	}
+	return h.Replicas
+}
+
+// SpreadTopologyKey returns the node label the replicas are spread over
+func (h *HighAvailabilityConfig) SpreadTopologyKey() string {
+	if h.TopologyKey == "" {
+		return defaultHATopologyKey
+	}
+	return h.TopologyKey
+}
+
+// Selects returns whether the Deployments of the component are highly available
+func (h *HighAvailabilityConfig) Selects(component string) bool {
+	components := h.Components
+	if len(components) == 0 {
+		components = defaultHAComponents
+	}
+	for _, name := range components {
+		if name == component {
+			return true
+		}
+	}
+	return false
+}
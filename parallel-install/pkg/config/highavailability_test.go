@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HighAvailabilityConfig(t *testing.T) {
+
+	t.Run("Defaults", func(t *testing.T) {
+		ha := &HighAvailabilityConfig{}
+		require.NoError(t, ha.validate())
+		require.Equal(t, int32(2), ha.MinReplicas())
+		require.Equal(t, "kubernetes.io/hostname", ha.SpreadTopologyKey())
+		require.True(t, ha.Selects("eventing"))
+		require.False(t, ha.Selects("monitoring"))
+	})
+
+	t.Run("Custom settings", func(t *testing.T) {
+		ha := &HighAvailabilityConfig{Replicas: 3, Components: []string{"monitoring"}, TopologyKey: "topology.kubernetes.io/zone"}
+		require.NoError(t, ha.validate())
+		require.Equal(t, int32(3), ha.MinReplicas())
+		require.Equal(t, "topology.kubernetes.io/zone", ha.SpreadTopologyKey())
+		require.True(t, ha.Selects("monitoring"))
+		require.False(t, ha.Selects("eventing"))
+	})
+
+	t.Run("Invalid replicas", func(t *testing.T) {
+		require.Error(t, (&HighAvailabilityConfig{Replicas: 1}).validate())
+		require.Error(t, (&HighAvailabilityConfig{Replicas: -2}).validate())
+	})
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ImageCheckConfig defines the preflight check that component images support the architectures of the cluster nodes
+type ImageCheckConfig struct {
+	// Images to check, mapped to the override key which sets the image, e.g. "eu.gcr.io/kyma-project/function-controller:v1"
+	// to "serverless.containers.manager.image". If the key is set, an image without matching platforms is replaced by its mirror.
+	Images map[string]string
+	// Mirrors maps image prefixes to prefixes of multi-arch mirrors, e.g. "eu.gcr.io/kyma-project/" to "ghcr.io/kyma-project/"
+	Mirrors map[string]string
+	// FailOnMismatch fails the deployment if an image does not support a node architecture and cannot be replaced by a mirror.
+	// Otherwise, a warning is logged.
+	FailOnMismatch bool
+	// Timeout of the check. Defaults to one minute.
+	Timeout time.Duration
+}
+
+// validate verifies the image check settings
+func (i *ImageCheckConfig) validate() error {
+	if i.Timeout < 0 {
+		return fmt.Errorf("Image check timeout cannot be negative")
+	}
+	for image, overrideKey := range i.Images {
+		if overrideKey != "" && !strings.Contains(strings.Trim(overrideKey, "."), ".") {
+			return fmt.Errorf("Override key '%s' of image '%s' must start with the chart name", overrideKey, image)
+		}
+	}
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"io/ioutil"
 	"os"
 
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
 	"github.com/pkg/errors"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 const (
@@ -25,7 +27,21 @@ func Path(kubeconfigSource KubeconfigSource) (resPath string, cf CleanupFunc, er
 	contentSet := notEmpty(kubeconfigSource.Content)
 
 	if !pathSet && !contentSet {
-		return "", nil, errors.New("Either kubeconfig path or kubeconfig content property must be set")
+		return "", nil, &installerrors.ErrInvalidKubeconfig{Err: errors.New("Either kubeconfig path or kubeconfig content property must be set")}
+	}
+
+	if kubeconfigSource.rewrites() {
+		// render the selected context to a temporary file
+		selected, err := selectKubeconfig(kubeconfigSource)
+		if err != nil {
+			return "", nil, err
+		}
+		content, err := clientcmd.Write(*selected)
+		if err != nil {
+			return "", nil, err
+		}
+		kubeconfigSource = KubeconfigSource{Content: string(content)}
+		pathSet = false
 	}
 
 	if pathSet {
@@ -56,14 +72,84 @@ func RestConfig(kubeconfigSource KubeconfigSource) (*rest.Config, error) {
 	contentSet := notEmpty(kubeconfigSource.Content)
 
 	if !pathSet && !contentSet {
-		return nil, errors.New("Either kubeconfig path or kubeconfig content property must be set")
+		return nil, &installerrors.ErrInvalidKubeconfig{Err: errors.New("Either kubeconfig path or kubeconfig content property must be set")}
 	}
 
+	var restConfig *rest.Config
+	var err error
+	if kubeconfigSource.rewrites() {
+		var selected *clientcmdapi.Config
+		if selected, err = selectKubeconfig(kubeconfigSource); err != nil {
+			return nil, err
+		}
+		restConfig, err = clientcmd.NewDefaultClientConfig(*selected, &clientcmd.ConfigOverrides{}).ClientConfig()
+	} else if notEmpty(kubeconfigSource.Path) {
+		restConfig, err = clientcmd.BuildConfigFromFlags("", kubeconfigSource.Path)
+	} else {
+		restConfig, err = clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfigSource.Content))
+	}
+	if err != nil {
+		return nil, &installerrors.ErrInvalidKubeconfig{Err: err}
+	}
+	if kubeconfigSource.TLS != nil {
+		restConfig.Wrap(kubeconfigSource.TLS.WrapTransport)
+	}
+	return restConfig, nil
+}
+
+// rewrites returns true if a context, cluster, or user of the kubeconfig is selected or a proxy is applied
+func (k KubeconfigSource) rewrites() bool {
+	return notEmpty(k.Context) || notEmpty(k.Cluster) || notEmpty(k.User) || k.Proxy != nil
+}
+
+// selectKubeconfig reduces the kubeconfig to the selected context, cluster, and user, and applies the proxy.
+// It returns an ErrInvalidKubeconfig if one of them does not exist.
+func selectKubeconfig(kubeconfigSource KubeconfigSource) (*clientcmdapi.Config, error) {
+	var raw *clientcmdapi.Config
+	var err error
 	if notEmpty(kubeconfigSource.Path) {
-		return clientcmd.BuildConfigFromFlags("", kubeconfigSource.Path)
+		// the loading rules resolve paths relative to the kubeconfig file
+		raw, err = (&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigSource.Path}).Load()
 	} else {
-		return clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfigSource.Content))
+		raw, err = clientcmd.Load([]byte(kubeconfigSource.Content))
+	}
+	if err != nil {
+		return nil, &installerrors.ErrInvalidKubeconfig{Err: err}
+	}
+
+	contextName := kubeconfigSource.Context
+	if contextName == "" {
+		contextName = raw.CurrentContext
+	}
+	context, ok := raw.Contexts[contextName]
+	if !ok {
+		return nil, &installerrors.ErrInvalidKubeconfig{Err: errors.Errorf("context '%s' not found", contextName)}
+	}
+	context = context.DeepCopy()
+	if notEmpty(kubeconfigSource.Cluster) {
+		context.Cluster = kubeconfigSource.Cluster
+	}
+	if notEmpty(kubeconfigSource.User) {
+		context.AuthInfo = kubeconfigSource.User
+	}
+	if _, ok := raw.Clusters[context.Cluster]; !ok {
+		return nil, &installerrors.ErrInvalidKubeconfig{Err: errors.Errorf("cluster '%s' not found", context.Cluster)}
+	}
+	if _, ok := raw.AuthInfos[context.AuthInfo]; !ok {
+		return nil, &installerrors.ErrInvalidKubeconfig{Err: errors.Errorf("user '%s' not found", context.AuthInfo)}
+	}
+
+	raw.Contexts[contextName] = context
+	raw.CurrentContext = contextName
+	if err := clientcmdapi.MinifyConfig(raw); err != nil {
+		return nil, &installerrors.ErrInvalidKubeconfig{Err: err}
+	}
+	if kubeconfigSource.Proxy != nil {
+		if err := kubeconfigSource.Proxy.applyToCluster(raw.Clusters[context.Cluster]); err != nil {
+			return nil, err
+		}
 	}
+	return raw, nil
 }
 
 func notEmpty(property string) bool {
@@ -7,6 +7,7 @@ import (
 
 	"errors"
 
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -296,3 +297,75 @@ users:
         somerandomtoken
 `
 }
+
+func Test_KubeconfigSelection(t *testing.T) {
+
+	t.Run("should use the selected context", func(t *testing.T) {
+		res, err := RestConfig(KubeconfigSource{Content: multiContextKubeConfig(), Context: "prod"})
+		assert.NoError(t, err)
+		assert.Equal(t, "https://prod.example.com", res.Host)
+		assert.Equal(t, "prod-token", res.BearerToken)
+	})
+
+	t.Run("should override cluster and user of the context", func(t *testing.T) {
+		res, err := RestConfig(KubeconfigSource{Content: multiContextKubeConfig(), Cluster: "prod", User: "prod-admin"})
+		assert.NoError(t, err)
+		assert.Equal(t, "https://prod.example.com", res.Host)
+		assert.Equal(t, "prod-token", res.BearerToken)
+	})
+
+	t.Run("should render the selected context for Helm", func(t *testing.T) {
+		path, cleanup, err := Path(KubeconfigSource{Content: multiContextKubeConfig(), Context: "prod"})
+		assert.NoError(t, err)
+		defer cleanup()
+
+		res, err := RestConfig(KubeconfigSource{Path: path})
+		assert.NoError(t, err)
+		assert.Equal(t, "https://prod.example.com", res.Host)
+	})
+
+	t.Run("should return a typed error for an unknown context", func(t *testing.T) {
+		res, err := RestConfig(KubeconfigSource{Content: multiContextKubeConfig(), Context: "staging"})
+		assert.Nil(t, res)
+		var invalid *installerrors.ErrInvalidKubeconfig
+		assert.True(t, errors.As(err, &invalid))
+		assert.Contains(t, err.Error(), "context 'staging' not found")
+	})
+
+	t.Run("should return a typed error for an unknown user", func(t *testing.T) {
+		_, _, err := Path(KubeconfigSource{Content: multiContextKubeConfig(), User: "admin"})
+		var invalid *installerrors.ErrInvalidKubeconfig
+		assert.True(t, errors.As(err, &invalid))
+		assert.Contains(t, err.Error(), "user 'admin' not found")
+	})
+}
+
+func multiContextKubeConfig() string {
+	return `apiVersion: v1
+kind: Config
+clusters:
+  - name: dev
+    cluster:
+      server: 'https://dev.example.com'
+  - name: prod
+    cluster:
+      server: 'https://prod.example.com'
+contexts:
+  - name: dev
+    context:
+      cluster: dev
+      user: dev-admin
+  - name: prod
+    context:
+      cluster: prod
+      user: prod-admin
+current-context: dev
+users:
+  - name: dev-admin
+    user:
+      token: dev-token
+  - name: prod-admin
+    user:
+      token: prod-token
+`
+}
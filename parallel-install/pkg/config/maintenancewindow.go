@@ -0,0 +1,210 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchDays limits the search for the next opening of a maintenance window, so that schedules like "0 0 31 2 *" end
+const cronSearchDays = 4 * 366
+
+// cronShortcuts are the supported shortcuts of cron schedules
+var cronShortcuts = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// cronFieldRanges are the allowed values of the minute, hour, day of month, month, and day of week fields.
+// Sunday is 0 or 7.
+var cronFieldRanges = [5]struct{ min, max int }{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// MaintenanceWindow restricts a deployment to the windows which open at the times of a cron schedule
+type MaintenanceWindow struct {
+	// Cron schedule of the window openings with the fields minute, hour, day of month, month, and day of week,
+	// e.g. "0 2 * * 6" for every Saturday at 2:00. The shortcuts @hourly, @daily, @weekly, and @monthly are supported.
+	Schedule string
+	// Time a window stays open
+	Duration time.Duration
+	// Remaining time of an open window which is required to start a deployment in it. Otherwise, the deployment waits for the next window.
+	MinimumRemaining time.Duration
+	// Time zone of the schedule. Defaults to UTC.
+	Location *time.Location
+}
+
+// validate verifies the schedule and the durations
+func (w *MaintenanceWindow) validate() error {
+	if _, err := parseCronSchedule(w.Schedule); err != nil {
+		return err
+	}
+	if w.Duration <= 0 {
+		return fmt.Errorf("Maintenance window duration must be positive")
+	}
+	if w.MinimumRemaining < 0 || w.MinimumRemaining > w.Duration {
+		return fmt.Errorf("Minimum remaining time of the maintenance window must be between 0 and its duration")
+	}
+	return nil
+}
+
+// Window returns the start and the end of the open maintenance window which closes last at the given time,
+// or of the next window if none is open
+func (w *MaintenanceWindow) Window(t time.Time) (time.Time, time.Time, error) {
+	start, err := w.NextOpening(t.Add(-w.Duration))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	//windows may overlap, so the last opening until t is used
+	for !start.After(t) {
+		next, err := w.NextOpening(start)
+		if err != nil || next.After(t) {
+			break
+		}
+		start = next
+	}
+	return start, start.Add(w.Duration), nil
+}
+
+// NextOpening returns the first opening of a maintenance window after the given time
+func (w *MaintenanceWindow) NextOpening(t time.Time) (time.Time, error) {
+	schedule, err := parseCronSchedule(w.Schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	location := w.Location
+	if location == nil {
+		location = time.UTC
+	}
+	start, ok := schedule.next(t.In(location).Add(time.Nanosecond))
+	if !ok {
+		return time.Time{}, fmt.Errorf("Maintenance window schedule '%s' does not open within %d days", w.Schedule, cronSearchDays)
+	}
+	return start, nil
+}
+
+// cronField is the set of the allowed values of a field of a cron schedule
+type cronField uint64
+
+func (f cronField) has(value int) bool {
+	return f&(1<<uint(value)) != 0
+}
+
+// cronSchedule is a parsed cron schedule
+type cronSchedule struct {
+	minutes, hours, days, months, weekdays cronField
+	// anyDay and anyWeekday are set if the day of month or day of week is not restricted.
+	// If both are restricted, a day matches if one of them matches.
+	anyDay, anyWeekday bool
+}
+
+func parseCronSchedule(text string) (*cronSchedule, error) {
+	text = strings.TrimSpace(text)
+	if shortcut, ok := cronShortcuts[text]; ok {
+		text = shortcut
+	}
+	fields := strings.Fields(text)
+	if len(fields) != len(cronFieldRanges) {
+		return nil, fmt.Errorf("Maintenance window schedule '%s' is invalid: expected the fields minute, hour, day of month, month, and day of week", text)
+	}
+	var parsed [5]cronField
+	for i, field := range fields {
+		set, err := parseCronField(field, cronFieldRanges[i].min, cronFieldRanges[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("Maintenance window schedule '%s' is invalid: %v", text, err)
+		}
+		parsed[i] = set
+	}
+	weekdays := parsed[4]
+	if weekdays.has(7) {
+		weekdays |= 1
+	}
+	return &cronSchedule{
+		minutes:    parsed[0],
+		hours:      parsed[1],
+		days:       parsed[2],
+		months:     parsed[3],
+		weekdays:   weekdays,
+		anyDay:     strings.HasPrefix(fields[2], "*"),
+		anyWeekday: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges, and steps, e.g. "0,30", "1-5", or "*/15"
+func parseCronField(field string, min, max int) (cronField, error) {
+	var set cronField
+	for _, part := range strings.Split(field, ",") {
+		rangeText, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("step of '%s' must be a positive number", part)
+			}
+			rangeText, step = part[:i], s
+		}
+		low, high := min, max
+		if rangeText != "*" {
+			bounds := strings.SplitN(rangeText, "-", 2)
+			var err error
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("'%s' is not a number, range, or step", part)
+			}
+			switch {
+			case len(bounds) == 2:
+				if high, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("'%s' is not a number, range, or step", part)
+				}
+			case step == 1:
+				high = low
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("'%s' is not within %d-%d", part, min, max)
+		}
+		for value := low; value <= high; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+	return set, nil
+}
+
+// next returns the first time of the schedule at or after t
+func (s *cronSchedule) next(t time.Time) (time.Time, bool) {
+	for day := 0; day <= cronSearchDays; day++ {
+		date := time.Date(t.Year(), t.Month(), t.Day()+day, 0, 0, 0, 0, t.Location())
+		if !s.matchesDay(date) {
+			continue
+		}
+		for hour := 0; hour < 24; hour++ {
+			if !s.hours.has(hour) {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if !s.minutes.has(minute) {
+					continue
+				}
+				candidate := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, t.Location())
+				if !candidate.Before(t) {
+					return candidate, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (s *cronSchedule) matchesDay(date time.Time) bool {
+	if !s.months.has(int(date.Month())) {
+		return false
+	}
+	day := s.days.has(date.Day())
+	weekday := s.weekdays.has(int(date.Weekday()))
+	switch {
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	}
+	return day || weekday
+}
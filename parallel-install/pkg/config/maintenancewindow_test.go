@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MaintenanceWindow(t *testing.T) {
+	//1 April 2021 is a Thursday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2021, 4, day, hour, minute, 0, 0, time.UTC)
+	}
+	requireWindow := func(t *testing.T, window *MaintenanceWindow, now, start, end time.Time) {
+		require.NoError(t, window.validate())
+		actualStart, actualEnd, err := window.Window(now)
+		require.NoError(t, err)
+		require.True(t, start.Equal(actualStart), "expected start %v, got %v", start, actualStart)
+		require.True(t, end.Equal(actualEnd), "expected end %v, got %v", end, actualEnd)
+	}
+
+	t.Run("Open window", func(t *testing.T) {
+		window := &MaintenanceWindow{Schedule: "0 2 * * 6", Duration: 4 * time.Hour}
+		requireWindow(t, window, at(3, 3, 0), at(3, 2, 0), at(3, 6, 0))
+	})
+
+	t.Run("Next window", func(t *testing.T) {
+		window := &MaintenanceWindow{Schedule: "0 2 * * 6", Duration: 4 * time.Hour}
+		requireWindow(t, window, at(1, 12, 0), at(3, 2, 0), at(3, 6, 0))
+		requireWindow(t, window, at(3, 6, 0), at(10, 2, 0), at(10, 6, 0)) //the window is closed at its end
+	})
+
+	t.Run("Overlapping windows", func(t *testing.T) {
+		window := &MaintenanceWindow{Schedule: "@hourly", Duration: 2 * time.Hour}
+		requireWindow(t, window, at(1, 12, 30), at(1, 12, 0), at(1, 14, 0))
+	})
+
+	t.Run("Lists, ranges, and steps", func(t *testing.T) {
+		window := &MaintenanceWindow{Schedule: "*/15 9-17 * * 1-5", Duration: 5 * time.Minute}
+		requireWindow(t, window, at(1, 12, 7), at(1, 12, 15), at(1, 12, 20))
+		requireWindow(t, window, at(2, 17, 50), at(5, 9, 0), at(5, 9, 5))
+
+		window = &MaintenanceWindow{Schedule: "0,30 22 * * *", Duration: time.Minute}
+		requireWindow(t, window, at(1, 22, 10), at(1, 22, 30), at(1, 22, 31))
+	})
+
+	t.Run("Day of month or day of week", func(t *testing.T) {
+		window := &MaintenanceWindow{Schedule: "0 0 13 * 5", Duration: time.Hour}
+		requireWindow(t, window, at(1, 12, 0), at(2, 0, 0), at(2, 1, 0))
+		requireWindow(t, window, at(10, 0, 0), at(13, 0, 0), at(13, 1, 0))
+	})
+
+	t.Run("Time zone", func(t *testing.T) {
+		window := &MaintenanceWindow{Schedule: "0 2 * * *", Duration: time.Hour, Location: time.FixedZone("CEST", 2*60*60)}
+		requireWindow(t, window, at(1, 0, 30), at(1, 0, 0), at(1, 1, 0))
+	})
+
+	t.Run("Schedule which never opens", func(t *testing.T) {
+		window := &MaintenanceWindow{Schedule: "0 0 31 2 *", Duration: time.Hour}
+		require.NoError(t, window.validate())
+		_, _, err := window.Window(at(1, 12, 0))
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid windows", func(t *testing.T) {
+		for _, window := range []*MaintenanceWindow{
+			{Schedule: "", Duration: time.Hour},
+			{Schedule: "* * *", Duration: time.Hour},
+			{Schedule: "60 * * * *", Duration: time.Hour},
+			{Schedule: "5-1 * * * *", Duration: time.Hour},
+			{Schedule: "*/0 * * * *", Duration: time.Hour},
+			{Schedule: "0 2 * * sat", Duration: time.Hour},
+			{Schedule: "0 2 * * *"},
+			{Schedule: "0 2 * * *", Duration: time.Hour, MinimumRemaining: 2 * time.Hour},
+		} {
+			require.Error(t, window.validate(), "schedule '%s'", window.Schedule)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// MetricsPushConfig defines the Prometheus Pushgateway the metrics of a successful deployment are pushed to,
+// so that the monitoring stack of the cluster records when and how Kyma was installed or upgraded
+type MetricsPushConfig struct {
+	// URL of the Pushgateway. If empty, the metrics are pushed through the API server proxy to the Pushgateway Service in the cluster.
+	URL string
+	// Namespace of the Pushgateway Service. Defaults to kyma-system.
+	Namespace string
+	// Name of the Pushgateway Service. Defaults to monitoring-prometheus-pushgateway.
+	Service string
+	// Port of the Pushgateway Service. Defaults to 9091.
+	Port int
+	// Job label of the pushed metrics. Defaults to kyma-installer.
+	Job string
+}
+
+// validate verifies that the URL and the port are valid
+func (m *MetricsPushConfig) validate() error {
+	if m.URL != "" {
+		if u, err := url.Parse(m.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("Pushgateway URL '%s' is invalid", m.URL)
+		}
+	}
+	if m.Port < 0 || m.Port > 65535 {
+		return fmt.Errorf("Pushgateway port %d is invalid", m.Port)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// MonitorConfig defines how long and how often Monitor checks the workloads of the deployed components
+type MonitorConfig struct {
+	// Period the workloads are watched for. Defaults to 5 minutes.
+	Period time.Duration
+	// Interval between two checks. Defaults to 10 seconds.
+	Interval time.Duration
+	// Container restarts since the start of the monitoring which mark a component as degraded. Defaults to 3.
+	RestartThreshold int32
+}
+
+// validate verifies that the durations and the threshold are not negative
+func (m *MonitorConfig) validate() error {
+	if m.Period < 0 || m.Interval < 0 {
+		return fmt.Errorf("Monitoring period and interval cannot be negative")
+	}
+	if m.RestartThreshold < 0 {
+		return fmt.Errorf("Monitoring restart threshold cannot be negative")
+	}
+	return nil
+}
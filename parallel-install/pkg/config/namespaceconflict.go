@@ -0,0 +1,25 @@
+package config
+
+import "fmt"
+
+// NamespaceConflictPolicy defines how the deployment handles a namespace required by Kyma which already exists,
+// but was neither created by Kyma nor contains Kyma releases
+type NamespaceConflictPolicy string
+
+const (
+	// NamespaceConflictAdopt labels the namespace as Kyma namespace and deploys the components into it
+	NamespaceConflictAdopt NamespaceConflictPolicy = "adopt"
+	// NamespaceConflictFail fails the deployment before any component is deployed
+	NamespaceConflictFail NamespaceConflictPolicy = "fail"
+	// NamespaceConflictSkip skips the components of the namespace
+	NamespaceConflictSkip NamespaceConflictPolicy = "skip"
+)
+
+// validate verifies that the policy is known. An empty policy reuses the namespace with a warning.
+func (p NamespaceConflictPolicy) validate() error {
+	switch p {
+	case "", NamespaceConflictAdopt, NamespaceConflictFail, NamespaceConflictSkip:
+		return nil
+	}
+	return fmt.Errorf("Unknown namespace conflict policy '%s': use %s, %s, or %s", p, NamespaceConflictAdopt, NamespaceConflictFail, NamespaceConflictSkip)
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// NamespaceDeletionConfig bounds the time the uninstallation waits for each Kyma namespace to be removed
+type NamespaceDeletionConfig struct {
+	// Deadline per namespace. It covers waiting for running Pods to terminate and for the namespace to disappear.
+	Timeout time.Duration
+	// ForceFinalize deletes namespaces with running Pods anyway and removes the finalizers of namespaces which are stuck in termination.
+	// Otherwise, stuck namespaces are reported as error with the resources which block them.
+	ForceFinalize bool
+	// Deprecated: the Pods and the namespace are watched instead of polled, so the interval is ignored.
+	PollInterval time.Duration
+}
+
+// validate verifies the namespace deletion settings
+func (n *NamespaceDeletionConfig) validate() error {
+	if n.Timeout <= 0 {
+		return fmt.Errorf("Namespace deletion timeout must be greater than zero")
+	}
+	if n.PollInterval < 0 {
+		return fmt.Errorf("Namespace deletion poll interval cannot be negative")
+	}
+	return nil
+}
+
+// NamespaceExclusion selects Kyma namespaces which are kept when Kyma is uninstalled
+type NamespaceExclusion struct {
+	// Names of the kept namespaces
+	Names []string
+	// Labels a namespace must have to be kept. Namespaces which have all labels are kept.
+	Labels map[string]string
+}
+
+// Excludes returns whether the namespace with the given name and labels is kept
+func (n *NamespaceExclusion) Excludes(name string, labels map[string]string) bool {
+	if n == nil {
+		return false
+	}
+	for _, excluded := range n.Names {
+		if excluded == name {
+			return true
+		}
+	}
+	if len(n.Labels) == 0 {
+		return false
+	}
+	for key, value := range n.Labels {
+		if actual, ok := labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
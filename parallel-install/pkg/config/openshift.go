@@ -0,0 +1,12 @@
+package config
+
+// OpenShiftConfig enables the compatibility mode for OpenShift clusters
+type OpenShiftConfig struct {
+	// Security context constraints the service accounts of the Kyma namespaces are allowed to use. Defaults to anyuid and privileged.
+	SecurityContextConstraints []string
+	// Components which are not installed because they conflict with OpenShift.
+	// Defaults to monitoring, whose Prometheus operator conflicts with the cluster monitoring of OpenShift.
+	SkipComponents []string
+	// UseIngress exposes Kyma with Ingress resources instead of OpenShift Routes
+	UseIngress bool
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// PasswordRule is a requirement of the password policy
+type PasswordRule string
+
+const (
+	// PasswordRuleMinLength requires a minimum number of characters
+	PasswordRuleMinLength PasswordRule = "minLength"
+	// PasswordRuleCharacterClasses requires characters of a minimum number of classes: lowercase and uppercase letters, digits, and symbols
+	PasswordRuleCharacterClasses PasswordRule = "characterClasses"
+	// PasswordRuleForbidden rejects well-known default passwords
+	PasswordRuleForbidden PasswordRule = "forbidden"
+)
+
+// PasswordPolicy defines the strength requirements of the passwords Kyma is deployed with:
+// passwords generated for declared secrets, passwords of existing generated secrets, and passwords provided as overrides
+type PasswordPolicy struct {
+	// Minimum number of characters
+	MinLength int
+	// Minimum number of character classes, from 1 to 4: lowercase and uppercase letters, digits, and symbols
+	MinCharacterClasses int
+	// Forbidden passwords, such as defaults of the charts, compared case-insensitively
+	Forbidden []string
+	// Override keys of passwords provided by the user, e.g. global.adminPassword.
+	// Overrides of generated passwords are always checked.
+	OverrideKeys []string
+}
+
+// validate verifies that the requirements can be met
+func (p *PasswordPolicy) validate() error {
+	if p.MinLength < 0 {
+		return fmt.Errorf("Minimum password length cannot be negative")
+	}
+	if p.MinCharacterClasses < 0 || p.MinCharacterClasses > 4 {
+		return fmt.Errorf("Minimum number of password character classes must be between 0 and 4")
+	}
+	return nil
+}
+
+// Violations returns the rules of the policy the password violates
+func (p *PasswordPolicy) Violations(password string) []PasswordRule {
+	var violations []PasswordRule
+	if len([]rune(password)) < p.MinLength {
+		violations = append(violations, PasswordRuleMinLength)
+	}
+	if CharacterClasses(password) < p.MinCharacterClasses {
+		violations = append(violations, PasswordRuleCharacterClasses)
+	}
+	for _, forbidden := range p.Forbidden {
+		if strings.EqualFold(password, forbidden) {
+			violations = append(violations, PasswordRuleForbidden)
+			break
+		}
+	}
+	return violations
+}
+
+// CharacterClasses returns the number of character classes the password contains
+func CharacterClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PasswordPolicy(t *testing.T) {
+	policy := &PasswordPolicy{
+		MinLength:           12,
+		MinCharacterClasses: 3,
+		Forbidden:           []string{"Kyma12345678!"},
+	}
+
+	t.Run("Strong password", func(t *testing.T) {
+		require.Empty(t, policy.Violations("correct-Horse-battery"))
+	})
+
+	t.Run("Weak passwords", func(t *testing.T) {
+		require.Equal(t, []PasswordRule{PasswordRuleMinLength}, policy.Violations("Sh0rt-pw"))
+		require.Equal(t, []PasswordRule{PasswordRuleCharacterClasses}, policy.Violations("onlylowercaseletters"))
+		require.Equal(t, []PasswordRule{PasswordRuleForbidden}, policy.Violations("kyma12345678!"))
+		require.Equal(t, []PasswordRule{PasswordRuleMinLength, PasswordRuleCharacterClasses}, policy.Violations("admin"))
+	})
+
+	t.Run("Character classes", func(t *testing.T) {
+		require.Equal(t, 0, CharacterClasses(""))
+		require.Equal(t, 2, CharacterClasses("abc123"))
+		require.Equal(t, 4, CharacterClasses("aB3_"))
+	})
+
+	t.Run("Invalid policy", func(t *testing.T) {
+		require.Error(t, (&PasswordPolicy{MinLength: -1}).validate())
+		require.Error(t, (&PasswordPolicy{MinCharacterClasses: 5}).validate())
+		require.NoError(t, policy.validate())
+	})
+}
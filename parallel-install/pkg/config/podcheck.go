@@ -0,0 +1,12 @@
+package config
+
+// PodCheckPolicy defines which Pods prevent the deletion of a Kyma namespace.
+// The zero value blocks the deletion on every Pod in the Running phase.
+type PodCheckPolicy struct {
+	// Skip disables the check: namespaces are deleted regardless of their Pods
+	Skip bool
+	// IgnoreNotRunning ignores Pods which are terminating or have containers which are not running, such as Pods in CrashLoopBackOff
+	IgnoreNotRunning bool
+	// IgnoreDaemonSetPodsOnCordonedNodes ignores Pods owned by a DaemonSet which run on unschedulable nodes
+	IgnoreDaemonSetPodsOnCordonedNodes bool
+}
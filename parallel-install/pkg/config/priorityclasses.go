@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxUserPriority is the highest value of a priority class which is not a system class
+const maxUserPriority = 1000000000
+
+// PriorityClassConfig creates priority classes and assigns them to the workloads of the components,
+// so that the Kyma system workloads are not evicted before the user workloads under node pressure
+type PriorityClassConfig struct {
+	// Priority classes created before the components are deployed and deleted when Kyma is uninstalled
+	Classes []PriorityClass
+	// Name of the class assigned to the workloads of the components which are not listed in Components. Not assigned if empty.
+	Default string
+	// Names of the classes assigned to the workloads of the components, per component, e.g. {"istio": "kyma-critical"}.
+	// An empty name excludes the component from the default class.
+	Components map[string]string
+}
+
+// PriorityClass defines a priority class created by the installer
+type PriorityClass struct {
+	// Name of the class, which cannot start with 'system-'
+	Name string
+	// Priority of the Pods of the class, up to 1000000000. Pods with a higher priority are evicted later.
+	Value int32
+	// Description of the class for the users of the cluster
+	Description string
+}
+
+// validate verifies the classes and the names of the assigned classes, which can also refer to classes of the cluster
+func (p *PriorityClassConfig) validate() error {
+	names := make(map[string]bool)
+	for _, class := range p.Classes {
+		if errs := validation.IsDNS1123Subdomain(class.Name); len(errs) > 0 {
+			return fmt.Errorf("Invalid priority class name '%s': %v", class.Name, errs)
+		}
+		if strings.HasPrefix(class.Name, "system-") {
+			return fmt.Errorf("Priority class name '%s' cannot use the reserved prefix 'system-'", class.Name)
+		}
+		if class.Value > maxUserPriority {
+			return fmt.Errorf("Value of priority class '%s' cannot be higher than %d", class.Name, maxUserPriority)
+		}
+		if names[class.Name] {
+			return fmt.Errorf("Priority class '%s' is defined more than once", class.Name)
+		}
+		names[class.Name] = true
+	}
+	assigned := []string{p.Default}
+	for _, name := range p.Components {
+		assigned = append(assigned, name)
+	}
+	for _, name := range assigned {
+		if name == "" {
+			continue
+		}
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("Invalid name of the assigned priority class '%s': %v", name, errs)
+		}
+	}
+	return nil
+}
+
+// ClassOf returns the name of the priority class assigned to the workloads of the component, or an empty string
+func (p *PriorityClassConfig) ClassOf(component string) string {
+	if name, ok := p.Components[component]; ok {
+		return name
+	}
+	return p.Default
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PriorityClassConfig(t *testing.T) {
+	cfg := &PriorityClassConfig{
+		Classes:    []PriorityClass{{Name: "kyma-system", Value: 1000000}},
+		Default:    "kyma-system",
+		Components: map[string]string{"istio": "system-cluster-critical", "serverless": ""},
+	}
+
+	t.Run("Class of a component", func(t *testing.T) {
+		require.Equal(t, "kyma-system", cfg.ClassOf("monitoring"))
+		require.Equal(t, "system-cluster-critical", cfg.ClassOf("istio"))
+		require.Empty(t, cfg.ClassOf("serverless"))
+	})
+
+	t.Run("Valid config", func(t *testing.T) {
+		require.NoError(t, cfg.validate())
+	})
+
+	t.Run("Invalid config", func(t *testing.T) {
+		require.Error(t, (&PriorityClassConfig{Classes: []PriorityClass{{Name: "Kyma"}}}).validate())
+		require.Error(t, (&PriorityClassConfig{Classes: []PriorityClass{{Name: "system-kyma"}}}).validate())
+		require.Error(t, (&PriorityClassConfig{Classes: []PriorityClass{{Name: "kyma", Value: 2000000000}}}).validate())
+		require.Error(t, (&PriorityClassConfig{Classes: []PriorityClass{{Name: "kyma"}, {Name: "kyma"}}}).validate())
+		require.Error(t, (&PriorityClassConfig{Default: "Kyma System"}).validate())
+	})
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ProxyConfig defines the HTTP(S) proxy and the additional CA certificates of all outbound connections:
+// git cloning, chart and file downloads, image registry requests, backup uploads, and Kubernetes clients.
+type ProxyConfig struct {
+	// Proxy of HTTP requests, e.g. http://proxy.corp:3128
+	HTTPProxy string
+	// Proxy of HTTPS requests
+	HTTPSProxy string
+	// Comma-separated hosts, domains, and CIDRs which are connected to directly, in the format of the NO_PROXY environment variable
+	NoProxy string
+	// Paths to PEM encoded CA bundles which are trusted in addition to the system CAs, e.g. the CA of a TLS-intercepting proxy
+	CABundles []string
+}
+
+// validate verifies that the proxy URLs are valid and the CA bundles contain certificates
+func (p *ProxyConfig) validate() error {
+	for _, proxy := range []string{p.HTTPProxy, p.HTTPSProxy} {
+		if proxy == "" {
+			continue
+		}
+		if u, err := url.Parse(proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("Proxy URL '%s' is invalid", proxy)
+		}
+	}
+	for _, bundle := range p.CABundles {
+		data, err := ioutil.ReadFile(bundle)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read CA bundle '%s'", bundle)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(data) {
+			return fmt.Errorf("CA bundle '%s' contains no PEM encoded certificate", bundle)
+		}
+	}
+	return nil
+}
+
+// ProxyFunc returns the proxy of a request. Without proxy config, the proxy is read from the environment.
+func (p *ProxyConfig) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	if p == nil {
+		return http.ProxyFromEnvironment
+	}
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  p.HTTPProxy,
+		HTTPSProxy: p.HTTPSProxy,
+		NoProxy:    p.NoProxy,
+	}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
+
+// CAData returns the concatenated CA bundles
+func (p *ProxyConfig) CAData() ([]byte, error) {
+	var data []byte
+	if p == nil {
+		return data, nil
+	}
+	for _, bundle := range p.CABundles {
+		pem, err := ioutil.ReadFile(bundle)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read CA bundle '%s'", bundle)
+		}
+		data = append(data, bytes.TrimSpace(pem)...)
+		data = append(data, '\n')
+	}
+	return data, nil
+}
+
+// Transport returns an HTTP transport which uses the proxy and trusts the CA bundles in addition to the system CAs
+func (p *ProxyConfig) Transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = p.ProxyFunc()
+	if p == nil || len(p.CABundles) == 0 {
+		return transport, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	data, err := p.CAData()
+	if err != nil {
+		return nil, err
+	}
+	pool.AppendCertsFromPEM(data)
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return transport, nil
+}
+
+// HTTPClient returns an HTTP client with the given timeout which uses the Transport of the proxy config
+func (p *ProxyConfig) HTTPClient(timeout time.Duration) (*http.Client, error) {
+	transport, err := p.Transport()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// Export sets the proxy environment variables for libraries which only read the proxy from the environment, like the Helm chart downloader.
+// Go reads the environment at the first proxied request of the process, so Export has to be called before.
+func (p *ProxyConfig) Export() error {
+	variables := map[string]string{
+		"HTTP_PROXY":  p.HTTPProxy,
+		"HTTPS_PROXY": p.HTTPSProxy,
+		"NO_PROXY":    p.NoProxy,
+	}
+	for name, value := range variables {
+		if value == "" {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyToCluster sets the proxy of the cluster of a kubeconfig, unless the kubeconfig defines one or NO_PROXY matches the API server.
+// The CA bundles are added to the CA of the cluster. Clusters without CA use the system CAs, which are kept.
+func (p *ProxyConfig) applyToCluster(cluster *clientcmdapi.Cluster) error {
+	if cluster.ProxyURL == "" {
+		server, err := url.Parse(cluster.Server)
+		if err != nil {
+			return errors.Wrapf(err, "API server URL '%s' is invalid", cluster.Server)
+		}
+		proxy, err := p.ProxyFunc()(&http.Request{URL: server})
+		if err != nil {
+			return err
+		}
+		if proxy != nil {
+			cluster.ProxyURL = proxy.String()
+		}
+	}
+
+	if len(p.CABundles) == 0 || cluster.InsecureSkipTLSVerify {
+		return nil
+	}
+	caData := cluster.CertificateAuthorityData
+	if len(caData) == 0 && cluster.CertificateAuthority != "" {
+		var err error
+		if caData, err = ioutil.ReadFile(cluster.CertificateAuthority); err != nil {
+			return errors.Wrapf(err, "Failed to read CA of the cluster '%s'", cluster.CertificateAuthority)
+		}
+	}
+	if len(caData) == 0 {
+		return nil
+	}
+	bundles, err := p.CAData()
+	if err != nil {
+		return err
+	}
+	cluster.CertificateAuthority = ""
+	cluster.CertificateAuthorityData = append(append(bytes.TrimSpace(caData), '\n'), bundles...)
+	return nil
+}
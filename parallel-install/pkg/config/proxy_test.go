@@ -0,0 +1,82 @@
+package config
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ProxyConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proxy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caCert, _ := newCertificate(t, "*.proxy.corp", time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	caBundle := filepath.Join(dir, "proxy-ca.pem")
+	require.NoError(t, ioutil.WriteFile(caBundle, caCert, 0600))
+	invalidBundle := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, ioutil.WriteFile(invalidBundle, []byte("no certificate"), 0600))
+
+	proxy := &ProxyConfig{
+		HTTPProxy:  "http://proxy.corp:3128",
+		HTTPSProxy: "http://proxy.corp:3129",
+		NoProxy:    ".internal,10.0.0.0/8",
+		CABundles:  []string{caBundle},
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		require.NoError(t, proxy.validate())
+		require.Error(t, (&ProxyConfig{HTTPSProxy: "proxy.corp"}).validate())
+		require.Error(t, (&ProxyConfig{CABundles: []string{invalidBundle}}).validate())
+		require.Error(t, (&ProxyConfig{CABundles: []string{filepath.Join(dir, "missing.pem")}}).validate())
+	})
+
+	t.Run("Proxy respects NO_PROXY", func(t *testing.T) {
+		proxyFunc := proxy.ProxyFunc()
+		for target, expected := range map[string]string{
+			"https://github.com/kyma-project/kyma": "http://proxy.corp:3129",
+			"http://charts.example.com/index.yaml": "http://proxy.corp:3128",
+			"https://registry.internal/v2/":        "",
+			"https://10.1.2.3:6443/version":        "",
+		} {
+			targetURL, err := url.Parse(target)
+			require.NoError(t, err)
+			proxyURL, err := proxyFunc(&http.Request{URL: targetURL})
+			require.NoError(t, err)
+			if expected == "" {
+				require.Nil(t, proxyURL, target)
+			} else {
+				require.Equal(t, expected, proxyURL.String(), target)
+			}
+		}
+	})
+
+	t.Run("Transport trusts the CA bundles", func(t *testing.T) {
+		transport, err := proxy.Transport()
+		require.NoError(t, err)
+		require.NotNil(t, transport.TLSClientConfig.RootCAs)
+		require.NotNil(t, transport.Proxy)
+	})
+
+	t.Run("Kubernetes clients use the proxy and the CA bundles", func(t *testing.T) {
+		res, err := RestConfig(KubeconfigSource{Content: correctKubeConfig(), Proxy: proxy})
+		require.NoError(t, err)
+		require.NotNil(t, res.Proxy)
+		proxyURL, err := res.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "from.content.example.com"}})
+		require.NoError(t, err)
+		require.Equal(t, "http://proxy.corp:3129", proxyURL.String())
+		require.Contains(t, string(res.CAData), string(caCert[:40]))
+	})
+
+	t.Run("Kubernetes clients connect directly to clusters in NO_PROXY", func(t *testing.T) {
+		res, err := RestConfig(KubeconfigSource{Content: correctKubeConfig(), Proxy: &ProxyConfig{HTTPSProxy: "http://proxy.corp:3129", NoProxy: ".example.com"}})
+		require.NoError(t, err)
+		require.Nil(t, res.Proxy)
+	})
+}
@@ -0,0 +1,25 @@
+package config
+
+import "fmt"
+
+// ReleaseConflictPolicy defines how the deployment handles a Helm release of a component which already exists,
+// but was not installed by Kyma, so it carries no Kyma metadata
+type ReleaseConflictPolicy string
+
+const (
+	// ReleaseConflictAdopt attaches the Kyma metadata to the release and upgrades it
+	ReleaseConflictAdopt ReleaseConflictPolicy = "adopt"
+	// ReleaseConflictFail fails the deployment before any component is deployed
+	ReleaseConflictFail ReleaseConflictPolicy = "fail"
+	// ReleaseConflictReplace uninstalls the release, so that the component is installed from scratch
+	ReleaseConflictReplace ReleaseConflictPolicy = "replace"
+)
+
+// validate verifies that the policy is known. An empty policy upgrades the release with a warning.
+func (p ReleaseConflictPolicy) validate() error {
+	switch p {
+	case "", ReleaseConflictAdopt, ReleaseConflictFail, ReleaseConflictReplace:
+		return nil
+	}
+	return fmt.Errorf("Unknown release conflict policy '%s': use %s, %s, or %s", p, ReleaseConflictAdopt, ReleaseConflictFail, ReleaseConflictReplace)
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"text/template"
+)
+
+const maxReleaseNameLength = 53
+
+var (
+	releaseNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	namespacePattern   = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+)
+
+// ReleaseNaming defines templates for the Helm release names and namespaces of the components,
+// so that multiple Kyma installations can coexist on a cluster. The templates use the text/template syntax
+// and can refer to {{.Component}}, {{.Namespace}} and {{.InstallationID}}.
+type ReleaseNaming struct {
+	// ReleaseName template, e.g. "{{.Component}}-{{.InstallationID}}". Defaults to the component name.
+	ReleaseName string
+	// Namespace template, e.g. "{{.Namespace}}-{{.InstallationID}}". Defaults to the namespace of the component.
+	Namespace string
+	// InstallationID identifies the installation, e.g. a tenant name
+	InstallationID string
+}
+
+type releaseNamingData struct {
+	Component      string
+	Namespace      string
+	InstallationID string
+}
+
+// Apply returns a copy of the component list with rendered release names and namespaces.
+// Components with an explicit release name are taken over as they are. A nil ReleaseNaming returns the list unchanged.
+func (n *ReleaseNaming) Apply(list *ComponentList) (*ComponentList, error) {
+	if n == nil || list == nil {
+		return list, nil
+	}
+	rendered := &ComponentList{Bundles: list.Bundles}
+	var err error
+	if rendered.Prerequisites, err = n.apply(list.Prerequisites); err != nil {
+		return nil, err
+	}
+	if rendered.Components, err = n.apply(list.Components); err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+func (n *ReleaseNaming) apply(comps []ComponentDefinition) ([]ComponentDefinition, error) {
+	var rendered []ComponentDefinition
+	for _, comp := range comps {
+		if comp.ReleaseName == "" {
+			data := releaseNamingData{Component: comp.Name, Namespace: comp.Namespace, InstallationID: n.InstallationID}
+			releaseName, err := renderName(n.ReleaseName, comp.Name, data)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to render the release name of component '%s': %v", comp.Name, err)
+			}
+			namespace, err := renderName(n.Namespace, comp.Namespace, data)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to render the namespace of component '%s': %v", comp.Name, err)
+			}
+			comp.ReleaseName = releaseName
+			comp.Namespace = namespace
+		}
+		rendered = append(rendered, comp)
+	}
+	return rendered, nil
+}
+
+func renderName(text, fallback string, data releaseNamingData) (string, error) {
+	if text == "" {
+		return fallback, nil
+	}
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// validate verifies that the templates render valid and unique release names and valid namespaces
+func (n *ReleaseNaming) validate(list *ComponentList) error {
+	rendered, err := n.Apply(list)
+	if err != nil {
+		return err
+	}
+	releases := map[string]bool{}
+	for _, comp := range append(append([]ComponentDefinition{}, rendered.Prerequisites...), rendered.Components...) {
+		if len(comp.ReleaseName) > maxReleaseNameLength || !releaseNamePattern.MatchString(comp.ReleaseName) {
+			return fmt.Errorf("Release name '%s' of component '%s' is invalid: it must be a DNS name of at most %d characters", comp.ReleaseName, comp.Name, maxReleaseNameLength)
+		}
+		if len(comp.Namespace) > 63 || !namespacePattern.MatchString(comp.Namespace) {
+			return fmt.Errorf("Namespace '%s' of component '%s' is invalid: it must be a DNS label", comp.Namespace, comp.Name)
+		}
+		key := comp.Namespace + "/" + comp.ReleaseName
+		if releases[key] {
+			return fmt.Errorf("Release name '%s' is used by more than one component in namespace '%s'", comp.ReleaseName, comp.Namespace)
+		}
+		releases[key] = true
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReleaseNaming(t *testing.T) {
+	list := &ComponentList{
+		Prerequisites: []ComponentDefinition{{Name: "cluster-essentials", Namespace: "kyma-system"}},
+		Components: []ComponentDefinition{
+			{Name: "serverless", Namespace: "kyma-system"},
+			{Name: "eventing", Namespace: "kyma-system", ReleaseName: "shared-eventing"},
+		},
+	}
+
+	t.Run("Templates are rendered per component", func(t *testing.T) {
+		naming := &ReleaseNaming{
+			ReleaseName:    "{{.Component}}-{{.InstallationID}}",
+			Namespace:      "{{.Namespace}}-{{.InstallationID}}",
+			InstallationID: "tenant1",
+		}
+		rendered, err := naming.Apply(list)
+		require.NoError(t, err)
+		require.Equal(t, []ComponentDefinition{{Name: "cluster-essentials", Namespace: "kyma-system-tenant1", ReleaseName: "cluster-essentials-tenant1"}}, rendered.Prerequisites)
+		require.Equal(t, ComponentDefinition{Name: "serverless", Namespace: "kyma-system-tenant1", ReleaseName: "serverless-tenant1"}, rendered.Components[0])
+		require.Equal(t, ComponentDefinition{Name: "eventing", Namespace: "kyma-system", ReleaseName: "shared-eventing"}, rendered.Components[1])
+
+		//the original list is not modified
+		require.Equal(t, "serverless", list.Components[0].Release())
+		require.NoError(t, naming.validate(list))
+	})
+
+	t.Run("Without templates the list is unchanged", func(t *testing.T) {
+		var naming *ReleaseNaming
+		rendered, err := naming.Apply(list)
+		require.NoError(t, err)
+		require.Equal(t, list, rendered)
+
+		rendered, err = (&ReleaseNaming{}).Apply(list)
+		require.NoError(t, err)
+		require.Equal(t, "serverless", rendered.Components[0].Release())
+		require.Equal(t, "kyma-system", rendered.Components[0].Namespace)
+	})
+
+	t.Run("Invalid templates", func(t *testing.T) {
+		for name, naming := range map[string]*ReleaseNaming{
+			"unknown field":      {ReleaseName: "{{.Tenant}}"},
+			"invalid syntax":     {ReleaseName: "{{.Component"},
+			"invalid name":       {ReleaseName: "{{.Component}}_{{.InstallationID}}", InstallationID: "a"},
+			"name too long":      {ReleaseName: "{{.Component}}-0123456789012345678901234567890123456789"},
+			"invalid namespace":  {Namespace: "{{.Namespace}}.{{.InstallationID}}", InstallationID: "a"},
+			"colliding releases": {ReleaseName: "kyma-{{.InstallationID}}", InstallationID: "a"},
+		} {
+			require.Error(t, naming.validate(list), name)
+		}
+	})
+}
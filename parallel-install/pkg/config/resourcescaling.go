@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ResourceScaling adjusts the resource requests and limits of the containers of all workloads of the selected components,
+// e.g. to run Kyma at 50% of its resources on a small cluster
+type ResourceScaling struct {
+	// Names of the components whose workloads are adjusted. All components if empty.
+	Components []string
+	// Factor the requests and limits of the charts are multiplied with, e.g. 0.5. Not scaled if 0.
+	Factor float64
+	// Requests set on all containers after scaling, e.g. {"memory": "64Mi"}
+	Requests map[string]string
+	// Limits set on all containers after scaling
+	Limits map[string]string
+}
+
+// validate verifies the factor and the quantities
+func (s *ResourceScaling) validate() error {
+	if s.Factor < 0 {
+		return fmt.Errorf("Resource scaling factor cannot be negative")
+	}
+	if s.Factor == 0 && len(s.Requests) == 0 && len(s.Limits) == 0 {
+		return fmt.Errorf("Resource scaling requires a factor, requests, or limits")
+	}
+	for _, quantities := range []map[string]string{s.Requests, s.Limits} {
+		for name, value := range quantities {
+			if _, err := resource.ParseQuantity(value); err != nil {
+				return fmt.Errorf("Invalid quantity '%s' of resource '%s': %v", value, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Selects returns whether the workloads of the component are adjusted
+func (s *ResourceScaling) Selects(component string) bool {
+	if len(s.Components) == 0 {
+		return true
+	}
+	for _, name := range s.Components {
+		if name == component {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResourceScaling(t *testing.T) {
+
+	t.Run("Selects components", func(t *testing.T) {
+		require.True(t, (&ResourceScaling{}).Selects("istio"))
+		scaling := &ResourceScaling{Components: []string{"monitoring", "logging"}}
+		require.True(t, scaling.Selects("logging"))
+		require.False(t, scaling.Selects("istio"))
+	})
+
+	t.Run("Valid scaling", func(t *testing.T) {
+		require.NoError(t, (&ResourceScaling{Factor: 0.5}).validate())
+		require.NoError(t, (&ResourceScaling{Requests: map[string]string{"cpu": "10m"}, Limits: map[string]string{"memory": "1Gi"}}).validate())
+	})
+
+	t.Run("Invalid scaling", func(t *testing.T) {
+		require.Error(t, (&ResourceScaling{Factor: -1}).validate())
+		require.Error(t, (&ResourceScaling{Components: []string{"istio"}}).validate())
+		require.Error(t, (&ResourceScaling{Limits: map[string]string{"memory": "a lot"}}).validate())
+	})
+}
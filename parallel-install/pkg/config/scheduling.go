@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// SchedulingConstraints are injected into the workloads of all components, e.g. to run Kyma on a dedicated infrastructure node pool
+type SchedulingConstraints struct {
+	// Labels of the nodes the Pods are scheduled on, merged into the node selectors of the charts. DaemonSets are not restricted.
+	NodeSelector map[string]string
+	// Tolerations added to the Pods, e.g. for the taints of the node pool. DaemonSets get them as well.
+	Tolerations []v1.Toleration
+	// Topology spread constraints added to the Pods. Without a label selector, the labels of the Pod template are used. DaemonSets are not affected.
+	TopologySpreadConstraints []v1.TopologySpreadConstraint
+	// Names of the components which are not constrained, e.g. components that must run next to the user workloads
+	ExcludedComponents []string
+}
+
+// validate verifies the node selector, the tolerations and the topology spread constraints
+func (s *SchedulingConstraints) validate() error {
+	for key, value := range s.NodeSelector {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("Invalid node selector key '%s': %v", key, errs)
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("Invalid node selector value '%s': %v", value, errs)
+		}
+	}
+	for _, toleration := range s.Tolerations {
+		switch toleration.Operator {
+		case v1.TolerationOpEqual, "":
+			if toleration.Key == "" {
+				return fmt.Errorf("Toleration with operator 'Equal' requires a key")
+			}
+		case v1.TolerationOpExists:
+			if toleration.Value != "" {
+				return fmt.Errorf("Toleration of key '%s' with operator 'Exists' cannot have a value", toleration.Key)
+			}
+		default:
+			return fmt.Errorf("Invalid operator '%s' of the toleration of key '%s'", toleration.Operator, toleration.Key)
+		}
+		switch toleration.Effect {
+		case "", v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute:
+		default:
+			return fmt.Errorf("Invalid effect '%s' of the toleration of key '%s'", toleration.Effect, toleration.Key)
+		}
+	}
+	for _, constraint := range s.TopologySpreadConstraints {
+		if constraint.TopologyKey == "" {
+			return fmt.Errorf("Topology spread constraint requires a topology key")
+		}
+		if constraint.MaxSkew < 1 {
+			return fmt.Errorf("Max skew of the topology spread constraint of key '%s' must be at least 1", constraint.TopologyKey)
+		}
+		switch constraint.WhenUnsatisfiable {
+		case v1.DoNotSchedule, v1.ScheduleAnyway:
+		default:
+			return fmt.Errorf("Invalid action '%s' of the topology spread constraint of key '%s', use '%s' or '%s'",
+				constraint.WhenUnsatisfiable, constraint.TopologyKey, v1.DoNotSchedule, v1.ScheduleAnyway)
+		}
+	}
+	return nil
+}
+
+// Selects returns whether the workloads of the component are constrained
+func (s *SchedulingConstraints) Selects(component string) bool {
+	for _, name := range s.ExcludedComponents {
+		if name == component {
+			return false
+		}
+	}
+	return true
+}
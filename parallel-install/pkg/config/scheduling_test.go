@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func Test_SchedulingConstraints(t *testing.T) {
+
+	t.Run("Valid constraints", func(t *testing.T) {
+		constraints := &SchedulingConstraints{
+			NodeSelector: map[string]string{"node.kubernetes.io/pool": "infra"},
+			Tolerations: []v1.Toleration{
+				{Key: "dedicated", Value: "infra", Effect: v1.TaintEffectNoSchedule},
+				{Operator: v1.TolerationOpExists},
+			},
+			TopologySpreadConstraints: []v1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.ScheduleAnyway},
+			},
+		}
+		require.NoError(t, constraints.validate())
+	})
+
+	t.Run("Invalid constraints", func(t *testing.T) {
+		require.Error(t, (&SchedulingConstraints{NodeSelector: map[string]string{"pool": "in fra"}}).validate())
+		require.Error(t, (&SchedulingConstraints{Tolerations: []v1.Toleration{{Value: "infra"}}}).validate())
+		require.Error(t, (&SchedulingConstraints{Tolerations: []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpExists, Value: "infra"}}}).validate())
+		require.Error(t, (&SchedulingConstraints{Tolerations: []v1.Toleration{{Key: "dedicated", Effect: "NoWay"}}}).validate())
+		require.Error(t, (&SchedulingConstraints{TopologySpreadConstraints: []v1.TopologySpreadConstraint{{MaxSkew: 1}}}).validate())
+		require.Error(t, (&SchedulingConstraints{TopologySpreadConstraints: []v1.TopologySpreadConstraint{{TopologyKey: "zone", WhenUnsatisfiable: v1.DoNotSchedule}}}).validate())
+	})
+
+	t.Run("Selects components", func(t *testing.T) {
+		constraints := &SchedulingConstraints{ExcludedComponents: []string{"istio"}}
+		require.True(t, constraints.Selects("monitoring"))
+		require.False(t, constraints.Selects("istio"))
+	})
+}
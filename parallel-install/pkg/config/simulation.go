@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	//SimulateDeploy restricts a simulated failure to deployments
+	SimulateDeploy = "deploy"
+	//SimulateUninstall restricts a simulated failure to uninstallations
+	SimulateUninstall = "uninstall"
+	//SimulateRollback restricts a simulated failure to rollbacks
+	SimulateRollback = "rollback"
+)
+
+// SimulationConfig configures the simulation backend, which runs the installation schedule without applying any chart
+type SimulationConfig struct {
+	// Duration of every component operation unless configured per component
+	DefaultLatency time.Duration
+	// Duration of the operations per component name
+	Latencies map[string]time.Duration
+	// Failures injected per component name
+	Failures map[string]SimulatedFailure
+	// BlockOnCancel mimics Helm, which does not support cancellation: operations last their full latency even if the context is canceled
+	BlockOnCancel bool
+}
+
+// SimulatedFailure defines an error returned by a simulated component operation
+type SimulatedFailure struct {
+	// Error message. Defaults to "simulated failure".
+	Message string
+	// Number of attempts which fail before the operation succeeds. Zero fails every attempt.
+	Attempts int
+	// Operation the failure applies to: deploy, uninstall, rollback, or empty for all
+	Operation string
+}
+
+// validate verifies the simulation settings
+func (s *SimulationConfig) validate() error {
+	if s.DefaultLatency < 0 {
+		return fmt.Errorf("Simulation latency cannot be negative")
+	}
+	for name, latency := range s.Latencies {
+		if latency < 0 {
+			return fmt.Errorf("Simulation latency of component '%s' cannot be negative", name)
+		}
+	}
+	for name, failure := range s.Failures {
+		if failure.Attempts < 0 {
+			return fmt.Errorf("Simulated failure attempts of component '%s' cannot be negative", name)
+		}
+		switch failure.Operation {
+		case "", SimulateDeploy, SimulateUninstall, SimulateRollback:
+		default:
+			return fmt.Errorf("Unknown operation '%s' of simulated failure of component '%s'", failure.Operation, name)
+		}
+	}
+	return nil
+}
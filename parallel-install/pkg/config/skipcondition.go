@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ClusterFactPrefix marks skip conditions which refer to a fact of the inspected cluster instead of an override value
+const ClusterFactPrefix = "cluster."
+
+// SkipCondition is a condition under which a component is not deployed, e.g. "global.monitoring.enabled==false".
+// The supported forms are "key==value", "key!=value", "key" (the value is true), and "!key" (the value is not true).
+// Keys refer to override values or, prefixed with "cluster.", to facts of the cluster, e.g. "cluster.provider==k3d".
+// A missing value is an empty string.
+type SkipCondition string
+
+type parsedSkipCondition struct {
+	key    string
+	value  string
+	negate bool
+}
+
+func (c SkipCondition) parse() (parsedSkipCondition, error) {
+	text := strings.TrimSpace(string(c))
+	var parsed parsedSkipCondition
+	switch {
+	case strings.Contains(text, "!="):
+		parts := strings.SplitN(text, "!=", 2)
+		parsed = parsedSkipCondition{key: parts[0], value: parts[1], negate: true}
+	case strings.Contains(text, "=="):
+		parts := strings.SplitN(text, "==", 2)
+		parsed = parsedSkipCondition{key: parts[0], value: parts[1]}
+	case strings.HasPrefix(text, "!"):
+		parsed = parsedSkipCondition{key: text[1:], value: "true", negate: true}
+	default:
+		parsed = parsedSkipCondition{key: text, value: "true"}
+	}
+	parsed.key = strings.TrimSpace(parsed.key)
+	parsed.value = strings.Trim(strings.TrimSpace(parsed.value), `"'`)
+	if parsed.key == "" || strings.ContainsAny(parsed.key, "=! ") {
+		return parsed, fmt.Errorf("Skip condition '%s' is invalid: expected 'key==value', 'key!=value', 'key', or '!key'", c)
+	}
+	return parsed, nil
+}
+
+// Key returns the override key or cluster fact the condition refers to
+func (c SkipCondition) Key() string {
+	parsed, _ := c.parse()
+	return parsed.key
+}
+
+// Evaluate returns whether the condition holds. The lookup returns the value of an override key or cluster fact.
+func (c SkipCondition) Evaluate(lookup func(key string) (interface{}, bool)) (bool, error) {
+	parsed, err := c.parse()
+	if err != nil {
+		return false, err
+	}
+	actual := ""
+	if value, ok := lookup(parsed.key); ok && value != nil {
+		actual = fmt.Sprintf("%v", value)
+	}
+	return (actual == parsed.value) != parsed.negate, nil
+}
+
+// Skipped returns whether any skip condition of the component holds
+func (c ComponentDefinition) Skipped(lookup func(key string) (interface{}, bool)) (bool, SkipCondition, error) {
+	for _, condition := range c.SkipIf {
+		holds, err := condition.Evaluate(lookup)
+		if err != nil {
+			return false, condition, fmt.Errorf("Failed to evaluate the skip conditions of component '%s': %v", c.Name, err)
+		}
+		if holds {
+			return true, condition, nil
+		}
+	}
+	return false, "", nil
+}
+
+// validateSkipConditions verifies that the skip conditions of all components can be parsed
+func (cl *ComponentList) validateSkipConditions() error {
+	for _, comp := range append(append([]ComponentDefinition{}, cl.Prerequisites...), cl.Components...) {
+		for _, condition := range comp.SkipIf {
+			if _, err := condition.parse(); err != nil {
+				return fmt.Errorf("Component '%s': %v", comp.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkipCondition_Evaluate(t *testing.T) {
+	values := map[string]interface{}{
+		"global.monitoring.enabled": false,
+		"global.domainName":         "kyma.example.com",
+		"cluster.istioCRDs":         true,
+	}
+	lookup := func(key string) (interface{}, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+
+	tests := []struct {
+		condition SkipCondition
+		holds     bool
+	}{
+		{"global.monitoring.enabled==false", true},
+		{"global.monitoring.enabled == 'false'", true},
+		{"global.monitoring.enabled!=false", false},
+		{"global.domainName==kyma.example.com", true},
+		{"global.missing==", true},
+		{"global.missing!=", false},
+		{"cluster.istioCRDs", true},
+		{"!cluster.istioCRDs", false},
+		{"!global.missing", true},
+	}
+	for _, test := range tests {
+		holds, err := test.condition.Evaluate(lookup)
+		require.NoError(t, err)
+		require.Equal(t, test.holds, holds, string(test.condition))
+	}
+
+	t.Run("Invalid condition", func(t *testing.T) {
+		_, err := SkipCondition("==false").Evaluate(lookup)
+		require.Error(t, err)
+		_, err = SkipCondition("a b").Evaluate(lookup)
+		require.Error(t, err)
+	})
+}
+
+func TestComponentDefinition_Skipped(t *testing.T) {
+	comp := ComponentDefinition{Name: "monitoring", SkipIf: []SkipCondition{"global.monitoring.enabled==false", "cluster.provider==k3d"}}
+
+	skipped, condition, err := comp.Skipped(func(key string) (interface{}, bool) {
+		if key == "cluster.provider" {
+			return "k3d", true
+		}
+		return nil, false
+	})
+	require.NoError(t, err)
+	require.True(t, skipped)
+	require.Equal(t, SkipCondition("cluster.provider==k3d"), condition)
+
+	skipped, _, err = comp.Skipped(func(key string) (interface{}, bool) { return nil, false })
+	require.NoError(t, err)
+	require.False(t, skipped)
+
+	list := &ComponentList{Components: []ComponentDefinition{{Name: "monitoring", SkipIf: []SkipCondition{"!"}}}}
+	require.EqualError(t, list.validateSkipConditions(), "Component 'monitoring': Skip condition '!' is invalid: expected 'key==value', 'key!=value', 'key', or '!key'")
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+//sourceIDLength is the number of hex characters of a source ID, which has to fit into a label value
+const sourceIDLength = 16
+
+//SourceType defines where the charts of the components are taken from
+type SourceType string
+
+const (
+	//LocalSource is a local directory
+	LocalSource SourceType = "local"
+	//GitSource is a revision of a Git repository
+	GitSource SourceType = "git"
+	//ArchiveSource is a release archive, e.g. the tarball of a Kyma release
+	ArchiveSource SourceType = "archive"
+)
+
+//Source identifies where the charts of the components are taken from. It is recorded in the metadata of the Helm releases,
+//so that an installation can be upgraded from another source, e.g. from a release archive to a Git revision.
+type Source struct {
+	//Type of the source: local|git|archive
+	Type SourceType
+	//Reference of the source, e.g. the directory, the URL and revision of the Git repository, or the URL of the archive
+	Reference string
+}
+
+//ID returns a short digest of the source, which is stored as label of the Helm releases. Sources with the same type and reference have the same ID.
+func (s *Source) ID() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s", s.Type, s.Reference)))
+	return hex.EncodeToString(sum[:])[:sourceIDLength]
+}
+
+func (s *Source) String() string {
+	return fmt.Sprintf("%s %s", s.Type, s.Reference)
+}
+
+func (s *Source) validate() error {
+	switch s.Type {
+	case LocalSource, GitSource, ArchiveSource:
+	default:
+		return fmt.Errorf("Unknown source type '%s'", s.Type)
+	}
+	if s.Reference == "" {
+		return fmt.Errorf("Reference of the %s source is missing", s.Type)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// StagedUpgradeFailureAction defines how a staged upgrade reacts to a failed batch
+type StagedUpgradeFailureAction string
+
+const (
+	// PauseOnFailure stops the upgrade after the failed batch, so that it can be inspected and resumed by deploying again
+	PauseOnFailure StagedUpgradeFailureAction = "pause"
+	// RollbackOnFailure rolls back the components of the failed batch and stops the upgrade
+	RollbackOnFailure StagedUpgradeFailureAction = "rollback"
+)
+
+// StagedUpgradeConfig defines how an upgrade of an installed Kyma is split into batches of components
+type StagedUpgradeConfig struct {
+	// Number of components upgraded in parallel per batch
+	BatchSize int
+	// Period the workloads of a batch have to become and stay healthy before the next batch is upgraded. Defaults to 1 minute.
+	VerificationPeriod time.Duration
+	// Reaction to a batch which fails to upgrade or to pass the verification. Defaults to pause.
+	OnFailure StagedUpgradeFailureAction
+}
+
+// validate verifies the batch size, the verification period, and the failure action
+func (s *StagedUpgradeConfig) validate() error {
+	if s.BatchSize <= 0 {
+		return fmt.Errorf("Staged upgrade batch size must be positive")
+	}
+	if s.VerificationPeriod < 0 {
+		return fmt.Errorf("Staged upgrade verification period cannot be negative")
+	}
+	switch s.OnFailure {
+	case "", PauseOnFailure, RollbackOnFailure:
+	default:
+		return fmt.Errorf("Unknown staged upgrade failure action '%s', use '%s' or '%s'", s.OnFailure, PauseOnFailure, RollbackOnFailure)
+	}
+	return nil
+}
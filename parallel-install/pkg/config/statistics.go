@@ -0,0 +1,13 @@
+package config
+
+// StatisticsConfig defines where the statistics of every run are stored, so that runs can be compared
+type StatisticsConfig struct {
+	// Local directory the statistics are stored in as JSON files. If empty, they are stored in a ConfigMap in the cluster.
+	Directory string
+	// Namespace of the ConfigMap. Defaults to kyma-installer.
+	Namespace string
+	// Name of the ConfigMap. Defaults to kyma-installation-statistics.
+	ConfigMap string
+	// Number of runs kept in the ConfigMap. Defaults to 20.
+	MaxRuns int
+}
@@ -0,0 +1,66 @@
+package config
+
+import "fmt"
+
+const maxTenantLength = 20
+
+var defaultClusterScopedComponents = []string{"cluster-essentials", "istio", "cluster-users", "certificates"}
+
+// TenancyConfig installs Kyma as a namespace-scoped tenant, so that several lightweight Kyma instances share a cluster.
+// The release names and namespaces of the components are prefixed with the tenant.
+type TenancyConfig struct {
+	// Tenant ID, a DNS label of at most 20 characters, e.g. "acme"
+	Tenant string
+	// Components which install cluster-scoped resources and are not installed per tenant.
+	// Defaults to cluster-essentials, istio, cluster-users and certificates.
+	ClusterScopedComponents []string
+	// Cluster-scoped components which are installed once per cluster and reused by all tenants.
+	// They must be installed before a tenant is deployed. Other cluster-scoped components are skipped.
+	SharedComponents []string
+}
+
+// ClusterScoped returns the components which are not installed per tenant
+func (t *TenancyConfig) ClusterScoped() []string {
+	if t.ClusterScopedComponents == nil {
+		return defaultClusterScopedComponents
+	}
+	return t.ClusterScopedComponents
+}
+
+// IsShared returns true if the component is reused from the cluster-wide installation
+func (t *TenancyConfig) IsShared(component string) bool {
+	for _, shared := range t.SharedComponents {
+		if shared == component {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleaseNaming returns the templates which prefix the release names and namespaces with the tenant
+func (t *TenancyConfig) ReleaseNaming() *ReleaseNaming {
+	return &ReleaseNaming{
+		ReleaseName:    "{{.InstallationID}}-{{.Component}}",
+		Namespace:      "{{.InstallationID}}-{{.Namespace}}",
+		InstallationID: t.Tenant,
+	}
+}
+
+func (t *TenancyConfig) validate() error {
+	if t.Tenant == "" {
+		return fmt.Errorf("Tenant is empty")
+	}
+	if len(t.Tenant) > maxTenantLength || !namespacePattern.MatchString(t.Tenant) {
+		return fmt.Errorf("Tenant '%s' is invalid: it must be a DNS label of at most %d characters", t.Tenant, maxTenantLength)
+	}
+	clusterScoped := map[string]bool{}
+	for _, component := range t.ClusterScoped() {
+		clusterScoped[component] = true
+	}
+	for _, component := range t.SharedComponents {
+		if !clusterScoped[component] {
+			return fmt.Errorf("Shared component '%s' is not a cluster-scoped component", component)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// probeSubdomain is used to verify that a certificate covers the subdomains Kyma exposes its services on
+const probeSubdomain = "console"
+
+// TLSConfig defines the certificate of a custom Kyma domain.
+// Provide exactly one source: PEM encoded strings, files or a reference to a Kubernetes TLS secret.
+type TLSConfig struct {
+	// PEM encoded certificate
+	Cert string
+	// PEM encoded private key
+	Key string
+	// Path to the PEM encoded certificate file
+	CertFile string
+	// Path to the PEM encoded private key file
+	KeyFile string
+	// Reference to a secret of type kubernetes.io/tls in the cluster
+	SecretRef *SecretRef
+}
+
+// SecretRef references a secret in the cluster
+type SecretRef struct {
+	Namespace string
+	Name      string
+}
+
+// validate verifies that exactly one certificate source is provided and that local certificates are valid for the domain
+func (t *TLSConfig) validate(domain string) error {
+	sources := 0
+	if t.Cert != "" || t.Key != "" {
+		sources++
+	}
+	if t.CertFile != "" || t.KeyFile != "" {
+		sources++
+	}
+	if t.SecretRef != nil {
+		sources++
+		if t.SecretRef.Namespace == "" || t.SecretRef.Name == "" {
+			return fmt.Errorf("TLS secret reference requires a namespace and a name")
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("Exactly one TLS certificate source (PEM strings, files or secret reference) must be set")
+	}
+
+	// secrets can only be verified when the cluster is accessed
+	if t.SecretRef != nil {
+		return nil
+	}
+	crt, key, err := t.Load(nil)
+	if err != nil {
+		return err
+	}
+	return ValidateCertificate(crt, key, domain, time.Now())
+}
+
+// Load returns the PEM encoded certificate and key. The kubeClient is only used if the certificate is stored in a secret.
+func (t *TLSConfig) Load(kubeClient kubernetes.Interface) (crt []byte, key []byte, err error) {
+	switch {
+	case t.SecretRef != nil:
+		if kubeClient == nil {
+			return nil, nil, fmt.Errorf("Kubernetes client is required to read TLS secret '%s/%s'", t.SecretRef.Namespace, t.SecretRef.Name)
+		}
+		secret, err := kubeClient.CoreV1().Secrets(t.SecretRef.Namespace).Get(context.TODO(), t.SecretRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to read TLS secret '%s/%s'", t.SecretRef.Namespace, t.SecretRef.Name)
+		}
+		return secret.Data["tls.crt"], secret.Data["tls.key"], nil
+	case t.CertFile != "" || t.KeyFile != "":
+		if crt, err = ioutil.ReadFile(t.CertFile); err != nil {
+			return nil, nil, errors.Wrap(err, "Failed to read TLS certificate file")
+		}
+		if key, err = ioutil.ReadFile(t.KeyFile); err != nil {
+			return nil, nil, errors.Wrap(err, "Failed to read TLS key file")
+		}
+		return crt, key, nil
+	default:
+		return []byte(t.Cert), []byte(t.Key), nil
+	}
+}
+
+// ValidateCertificate verifies that the certificate matches the key, is valid at the given time and
+// covers the subdomains of the given domain.
+func ValidateCertificate(crt, key []byte, domain string, now time.Time) error {
+	pair, err := tls.X509KeyPair(crt, key)
+	if err != nil {
+		return errors.Wrap(err, "TLS certificate and key do not match")
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse TLS certificate")
+	}
+	if now.Before(leaf.NotBefore) {
+		return fmt.Errorf("TLS certificate is not valid before %s", leaf.NotBefore)
+	}
+	if now.After(leaf.NotAfter) {
+		return fmt.Errorf("TLS certificate expired on %s", leaf.NotAfter)
+	}
+	if err := leaf.VerifyHostname(fmt.Sprintf("%s.%s", probeSubdomain, domain)); err != nil {
+		return errors.Wrapf(err, "TLS certificate does not cover the subdomains of '%s'", domain)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ValidateCertificate(t *testing.T) {
+	now := time.Now()
+	crt, key := newCertificate(t, "*.kyma.example.com", now.Add(-time.Hour), now.Add(time.Hour))
+
+	t.Run("Valid certificate", func(t *testing.T) {
+		assert.NoError(t, ValidateCertificate(crt, key, "kyma.example.com", now))
+	})
+
+	t.Run("Expired certificate", func(t *testing.T) {
+		err := ValidateCertificate(crt, key, "kyma.example.com", now.Add(2*time.Hour))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expired")
+	})
+
+	t.Run("Domain not covered", func(t *testing.T) {
+		err := ValidateCertificate(crt, key, "other.example.com", now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not cover")
+	})
+
+	t.Run("Key does not match", func(t *testing.T) {
+		_, otherKey := newCertificate(t, "*.kyma.example.com", now.Add(-time.Hour), now.Add(time.Hour))
+		err := ValidateCertificate(crt, otherKey, "kyma.example.com", now)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "do not match")
+	})
+}
+
+func Test_TLSConfig(t *testing.T) {
+	now := time.Now()
+	crt, key := newCertificate(t, "*.kyma.example.com", now.Add(-time.Hour), now.Add(time.Hour))
+
+	t.Run("PEM strings", func(t *testing.T) {
+		tlsCfg := &TLSConfig{Cert: string(crt), Key: string(key)}
+		assert.NoError(t, tlsCfg.validate("kyma.example.com"))
+	})
+
+	t.Run("Multiple sources", func(t *testing.T) {
+		tlsCfg := &TLSConfig{Cert: string(crt), Key: string(key), CertFile: "tls.crt", KeyFile: "tls.key"}
+		err := tlsCfg.validate("kyma.example.com")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Exactly one")
+	})
+
+	t.Run("Incomplete secret reference", func(t *testing.T) {
+		tlsCfg := &TLSConfig{SecretRef: &SecretRef{Name: "tls"}}
+		assert.Error(t, tlsCfg.validate("kyma.example.com"))
+	})
+
+	t.Run("Load from secret", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "default"},
+			Data:       map[string][]byte{"tls.crt": crt, "tls.key": key},
+		})
+		tlsCfg := &TLSConfig{SecretRef: &SecretRef{Namespace: "default", Name: "tls"}}
+		require.NoError(t, tlsCfg.validate("kyma.example.com"))
+
+		loadedCrt, loadedKey, err := tlsCfg.Load(kubeClient)
+		require.NoError(t, err)
+		assert.Equal(t, crt, loadedCrt)
+		assert.Equal(t, key, loadedKey)
+	})
+}
+
+func newCertificate(t *testing.T, dnsName string, notBefore, notAfter time.Time) ([]byte, []byte) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
@@ -0,0 +1,23 @@
+package config
+
+import "fmt"
+
+// UnknownOverridesPolicy defines how the deployment handles override keys which are not defined in the values of any chart,
+// e.g. a typo like global.domianName
+type UnknownOverridesPolicy string
+
+const (
+	// UnknownOverridesWarn logs a warning for every unknown override key and deploys Kyma
+	UnknownOverridesWarn UnknownOverridesPolicy = "warn"
+	// UnknownOverridesFail fails the deployment before any component is deployed
+	UnknownOverridesFail UnknownOverridesPolicy = "fail"
+)
+
+// validate verifies that the policy is known. An empty policy does not check the override keys.
+func (p UnknownOverridesPolicy) validate() error {
+	switch p {
+	case "", UnknownOverridesWarn, UnknownOverridesFail:
+		return nil
+	}
+	return fmt.Errorf("Unknown policy for unknown overrides '%s': use %s or %s", p, UnknownOverridesWarn, UnknownOverridesFail)
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// UpdateThrottleConfig defines how repetitive process updates are coalesced before they are delivered to the callback,
+// so that tight retry loops do not flood user interfaces
+type UpdateThrottleConfig struct {
+	// Minimum time between two identical updates. Identical updates within the interval are dropped. Defaults to 1 second.
+	Interval time.Duration
+	// Maximum number of running updates delivered per second. Phase transitions and failures are always delivered. No limit if 0.
+	MaxPerSecond int
+}
+
+// validate verifies that the interval and the rate limit are not negative
+func (u *UpdateThrottleConfig) validate() error {
+	if u.Interval < 0 {
+		return fmt.Errorf("Update throttle interval cannot be negative")
+	}
+	if u.MaxPerSecond < 0 {
+		return fmt.Errorf("Update throttle rate limit cannot be < 0")
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// UserResourcesConfig defines the custom resources created by users which are exported before Kyma is uninstalled,
+// so that they can be imported again after Kyma is reinstalled
+type UserResourcesConfig struct {
+	// Local directory or HTTP(S) URL the archive of the resources is stored to
+	Location string
+	// Exported resources in the format <resource>.<version>.<group>, e.g. functions.v1alpha1.serverless.kyma-project.io.
+	// Defaults to Functions, APIRules, Subscriptions and ServiceInstances.
+	Resources []string
+}
+
+// GroupVersionResources returns the configured resources. It returns nil if no resources are configured.
+func (u *UserResourcesConfig) GroupVersionResources() ([]schema.GroupVersionResource, error) {
+	var gvrs []schema.GroupVersionResource
+	for _, resource := range u.Resources {
+		gvr, _ := schema.ParseResourceArg(resource)
+		if gvr == nil || gvr.Resource == "" || gvr.Group == "" {
+			return nil, fmt.Errorf("User resource '%s' must be in the format <resource>.<version>.<group>", resource)
+		}
+		gvrs = append(gvrs, *gvr)
+	}
+	return gvrs, nil
+}
+
+// validate verifies that a location is set and the resources are valid
+func (u *UserResourcesConfig) validate() error {
+	if u.Location == "" {
+		return fmt.Errorf("Location of the user resources is empty")
+	}
+	_, err := u.GroupVersionResources()
+	return err
+}
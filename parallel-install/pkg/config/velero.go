@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// VeleroConfig defines the Velero backup which is taken before Kyma is upgraded.
+// Either a schedule is referenced, whose backup template is used, or the backup is described by namespaces and a label selector.
+type VeleroConfig struct {
+	// Namespace Velero is installed in. Defaults to velero.
+	Namespace string
+	// Name of a Velero schedule whose template is used for the backup
+	Schedule string
+	// Namespaces included in the backup. All namespaces are included if empty.
+	IncludedNamespaces []string
+	// Labels the backed up resources have to match
+	LabelSelector map[string]string
+	// Backup storage location. The default location of Velero is used if empty.
+	StorageLocation string
+	// Retention time of the backup. The Velero default is used if zero.
+	TTL time.Duration
+	// Maximum time to wait for the backup to complete. Defaults to 30 minutes.
+	Timeout time.Duration
+}
+
+// validate verifies that a schedule is not combined with an explicit backup description
+func (v *VeleroConfig) validate() error {
+	if v.Schedule != "" && (len(v.IncludedNamespaces) > 0 || len(v.LabelSelector) > 0 || v.StorageLocation != "" || v.TTL != 0) {
+		return fmt.Errorf("Velero schedule cannot be combined with namespaces, label selector, storage location or TTL")
+	}
+	if v.Timeout < 0 || v.TTL < 0 {
+		return fmt.Errorf("Velero timeout and TTL cannot be negative")
+	}
+	return nil
+}
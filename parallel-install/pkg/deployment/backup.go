@@ -0,0 +1,201 @@
+package deployment
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/backup"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+//newDynamicClient creates a dynamic client for the cluster of the kubeconfig
+func newDynamicClient(kubeconfigSource config.KubeconfigSource) (dynamic.Interface, error) {
+	restConfig, err := config.RestConfig(kubeconfigSource)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(restConfig)
+}
+
+//newPodExecutor creates the executor of commands in Pods of the cluster of the kubeconfig
+func newPodExecutor(kubeconfigSource config.KubeconfigSource) (backup.PodExecFunc, error) {
+	restConfig, err := config.RestConfig(kubeconfigSource)
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return backup.NewPodExecutor(restConfig, kubeClient), nil
+}
+
+//backupBeforeUpgrade stores the Helm release state, runs the Velero backup, and saves the etcd snapshot if Kyma components are installed.
+//The upgrade is aborted if a configured backup fails.
+func (i *core) backupBeforeUpgrade() error {
+	if i.cfg.BackupLocation == "" && i.cfg.Velero == nil && i.cfg.EtcdSnapshot == nil {
+		return nil
+	}
+	installed, err := i.kymaInstalled()
+	if err != nil || !installed {
+		return err
+	}
+	if err := i.snapshotReleaseState(); err != nil {
+		return err
+	}
+	if err := i.runVeleroBackup(); err != nil {
+		return err
+	}
+	return i.saveEtcdSnapshot()
+}
+
+//runVeleroBackup triggers the configured Velero backup and waits for its completion or a shutdown request
+func (i *core) runVeleroBackup() error {
+	if i.cfg.Velero == nil {
+		return nil
+	}
+	dynamicClient, err := i.newDynamicClient(i.cfg.KubeconfigSource)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := i.shutdown.context(context.Background())
+	defer cancel()
+	name, err := backup.NewVeleroBackup(dynamicClient, *i.cfg.Velero, i.cfg.Log).Run(ctx)
+	if err != nil {
+		if i.shutdown.requested() {
+			return i.interrupted()
+		}
+		return err
+	}
+	i.cfg.Log.Infof("Velero backup '%s' completed", name)
+	return nil
+}
+
+//saveEtcdSnapshot saves the configured etcd snapshot in an etcd Pod of the cluster. A shutdown request aborts it.
+func (i *core) saveEtcdSnapshot() error {
+	if i.cfg.EtcdSnapshot == nil {
+		return nil
+	}
+	exec, err := i.newPodExecutor(i.cfg.KubeconfigSource)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := i.shutdown.context(context.Background())
+	defer cancel()
+	location, err := backup.NewEtcdSnapshot(i.kubeClient, exec, *i.cfg.EtcdSnapshot, i.cfg.Log).Run(ctx)
+	if err != nil {
+		if i.shutdown.requested() {
+			return i.interrupted()
+		}
+		return err
+	}
+	i.cfg.Log.Infof("etcd snapshot saved to '%s'", location)
+	return nil
+}
+
+func (i *core) kymaInstalled() (bool, error) {
+	versions, err := i.metadataProvider().Versions()
+	if err != nil {
+		return false, err
+	}
+	return len(versions.InstalledComponents()) > 0, nil
+}
+
+//backupReleaseState stores the Helm release state if a backup location is configured and Kyma components are installed
+func (i *core) backupReleaseState() error {
+	if i.cfg.BackupLocation == "" {
+		return nil
+	}
+	installed, err := i.kymaInstalled()
+	if err != nil || !installed {
+		return err
+	}
+	return i.snapshotReleaseState()
+}
+
+//backupHTTPClient returns the HTTP client which uploads and downloads backup archives with the proxy and the TLS settings of the config
+func (i *core) backupHTTPClient() (*http.Client, error) {
+	return i.cfg.HTTPClient(backup.HTTPClientTimeout)
+}
+
+func (i *core) snapshotReleaseState() error {
+	if i.cfg.BackupLocation == "" {
+		return nil
+	}
+	httpClient, err := i.backupHTTPClient()
+	if err != nil {
+		return err
+	}
+	location, err := backup.Snapshot(i.kubeClient, httpClient, i.cfg.BackupLocation)
+	if err != nil {
+		return errors.Wrap(err, "Failed to back up the Helm release state")
+	}
+	i.cfg.Log.Infof("Helm release state stored in '%s'", location)
+	return nil
+}
+
+//RestoreReleaseState reverts the Helm release bookkeeping to a snapshot taken before an upgrade or uninstallation.
+//The source is the archive file or URL reported when the snapshot was taken.
+func (i *core) RestoreReleaseState(source string) error {
+	httpClient, err := i.backupHTTPClient()
+	if err != nil {
+		return err
+	}
+	if err := backup.RestoreReleaseState(i.kubeClient, httpClient, source); err != nil {
+		return err
+	}
+	i.cfg.Log.Infof("Helm release state restored from '%s'", source)
+	return nil
+}
+
+//exportUserResources stores the custom resources created by users if the export is configured.
+//The uninstallation is aborted if the export fails, as the resources would be lost.
+func (i *core) exportUserResources() error {
+	if i.cfg.UserResources == nil {
+		return nil
+	}
+	resources, err := i.cfg.UserResources.GroupVersionResources()
+	if err != nil {
+		return err
+	}
+	if len(resources) == 0 {
+		resources = backup.DefaultUserResources
+	}
+	dynamicClient, err := i.newDynamicClient(i.cfg.KubeconfigSource)
+	if err != nil {
+		return err
+	}
+	httpClient, err := i.backupHTTPClient()
+	if err != nil {
+		return err
+	}
+	location, count, err := backup.ExportResources(dynamicClient, httpClient, resources, i.cfg.UserResources.Location)
+	if err != nil {
+		return errors.Wrap(err, "Failed to export the user resources")
+	}
+	i.cfg.Log.Infof("%d user resources exported to '%s'", count, location)
+	return nil
+}
+
+//ImportUserResources creates the custom resources which were exported before Kyma was uninstalled.
+//It has to be called after Kyma is reinstalled, as the CRDs of the resources must exist.
+//The source is the archive file or URL reported when the resources were exported.
+func (i *core) ImportUserResources(source string) error {
+	dynamicClient, err := i.newDynamicClient(i.cfg.KubeconfigSource)
+	if err != nil {
+		return err
+	}
+	httpClient, err := i.backupHTTPClient()
+	if err != nil {
+		return err
+	}
+	count, err := backup.ImportResources(dynamicClient, httpClient, source)
+	if err != nil {
+		return err
+	}
+	i.cfg.Log.Infof("%d user resources imported from '%s'", count, source)
+	return nil
+}
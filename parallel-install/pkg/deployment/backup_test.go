@@ -0,0 +1,105 @@
+package deployment
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/backup"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCore_UserResources(t *testing.T) {
+	functions := schema.GroupVersionResource{Group: "serverless.kyma-project.io", Version: "v1alpha1", Resource: "functions"}
+	function := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "serverless.kyma-project.io/v1alpha1",
+		"kind":       "Function",
+		"metadata":   map[string]interface{}{"name": "orders", "namespace": "default"},
+	}}
+
+	dir, err := ioutil.TempDir("", "user-resources")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := &config.Config{
+		Log: logger.NewLogger(true),
+		UserResources: &config.UserResourcesConfig{
+			Location:  dir,
+			Resources: []string{"functions.v1alpha1.serverless.kyma-project.io"},
+		},
+	}
+	c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+
+	//export from the cluster before the uninstallation
+	c.newDynamicClient = func(config.KubeconfigSource) (dynamic.Interface, error) {
+		return dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), function), nil
+	}
+	require.NoError(t, c.exportUserResources())
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	//import into the reinstalled cluster
+	reinstalled := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	c.newDynamicClient = func(config.KubeconfigSource) (dynamic.Interface, error) {
+		return reinstalled, nil
+	}
+	require.NoError(t, c.ImportUserResources(filepath.Join(dir, files[0].Name())))
+
+	_, err = reinstalled.Resource(functions).Namespace("default").Get(context.Background(), "orders", metav1.GetOptions{})
+	require.NoError(t, err)
+}
+
+func TestCore_EtcdSnapshot(t *testing.T) {
+	etcdPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-master", Namespace: "kube-system", Labels: map[string]string{"component": "etcd"}},
+		Spec:       v1.PodSpec{NodeName: "master"},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	cfg := &config.Config{
+		Log:          logger.NewLogger(true),
+		EtcdSnapshot: &config.EtcdSnapshotConfig{Directory: "/var/lib/etcd/backups"},
+	}
+	c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(etcdPod), nil)
+
+	var executed []string
+	c.newPodExecutor = func(config.KubeconfigSource) (backup.PodExecFunc, error) {
+		return func(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+			executed = command
+			return "", nil
+		}, nil
+	}
+	require.NoError(t, c.saveEtcdSnapshot())
+	require.Contains(t, executed, "save")
+	require.Regexp(t, "^/var/lib/etcd/backups/kyma-pre-upgrade-.*\\.db$", executed[len(executed)-1])
+	t.Run("Shutdown request aborts the snapshot", func(t *testing.T) {
+		c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(etcdPod), nil)
+		c.newPodExecutor = func(config.KubeconfigSource) (backup.PodExecFunc, error) {
+			return func(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+				c.Shutdown()
+				<-ctx.Done()
+				return "", ctx.Err()
+			}, nil
+		}
+		err := c.saveEtcdSnapshot()
+		require.Error(t, err)
+		require.True(t, messages.Is(err, messages.DeploymentInterrupted))
+		require.True(t, errors.Is(err, installerrors.ErrCancelled))
+	})
+}
@@ -0,0 +1,19 @@
+package deployment
+
+import (
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
+	"k8s.io/client-go/kubernetes"
+)
+
+//verifyCluster requests the version of the API server, so an unreachable cluster or invalid credentials fail before any phase starts
+func verifyCluster(kubeClient kubernetes.Interface, host string, policy retry.Policy) error {
+	err := policy.Do(func() error {
+		_, err := kubeClient.Discovery().ServerVersion()
+		return err
+	})
+	if err != nil {
+		return &installerrors.ErrClusterUnreachable{Host: host, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package deployment
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func Test_VerifyCluster(t *testing.T) {
+
+	t.Run("Reachable cluster", func(t *testing.T) {
+		err := verifyCluster(fake.NewSimpleClientset(), "https://example.com", retry.Fixed(time.Millisecond, 1))
+		require.NoError(t, err)
+	})
+
+	t.Run("Unreachable cluster", func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		kubeClient, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		err = verifyCluster(kubeClient, server.URL, retry.Fixed(time.Millisecond, 2))
+		var unreachable *installerrors.ErrClusterUnreachable
+		require.True(t, errors.As(err, &unreachable))
+		require.Equal(t, server.URL, unreachable.Host)
+		require.Equal(t, 2, requests)
+	})
+}
@@ -3,22 +3,29 @@ package deployment
 
 import (
 	"context"
+	"encoding/base64"
 	"strings"
 	"time"
 	"unicode"
 
 	"k8s.io/apimachinery/pkg/labels"
 
-	"github.com/avast/retry-go"
 	"github.com/pkg/errors"
 
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/backup"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/cluster"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/engine"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/secrets"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -29,6 +36,38 @@ type core struct {
 	// Used to send progress events of a running install/uninstall process
 	processUpdates func(ProcessUpdate)
 	kubeClient     kubernetes.Interface
+	// Records the installation history as Kubernetes Events, nil if disabled
+	events *eventRecorder
+	// Stores the statistics of every run, nil if disabled
+	statistics *statisticsRecorder
+	// Pushes the metrics of successful deployments, nil if disabled
+	metrics *metricsPusher
+	// Adjustments of the provider quirks, nil if disabled
+	adjustments *cluster.Adjustments
+	// Observed component durations used by the engine scheduler, nil until the engines are created
+	durations *engine.Durations
+	// Time source of the timeouts and durations
+	clock engine.Clock
+	// Coalesces repetitive process updates, nil if disabled
+	throttle *updateThrottle
+	// Drops components whose skip conditions hold from the component list, only set for deployments
+	evaluateSkipConditions bool
+	// Namespaces not owned by Kyma whose components are skipped, see config.NamespaceConflictSkip
+	skippedNamespaces map[string]bool
+	// End of the open maintenance window, zero if no maintenance window is configured
+	windowEnd time.Time
+	// Shutdown requests of the caller, see Shutdown and HandleSignals
+	shutdown *shutdown
+	// Channels of the running StartKymaDeploymentAsync calls which receive the updates in addition to the callback
+	streams *updateStreams
+	// Rendered notes of the components installed by the last deployment
+	notes componentNotes
+	// Creates the component of a single release, the components provider is used if nil. Replaced in tests.
+	newReleaseComponent func(name, namespace string) components.KymaComponent
+	// Creates the dynamic client of Velero backups and user resource exports, replaced in tests
+	newDynamicClient func(kubeconfigSource config.KubeconfigSource) (dynamic.Interface, error)
+	// Creates the executor of etcd snapshots, replaced in tests
+	newPodExecutor func(kubeconfigSource config.KubeconfigSource) (backup.PodExecFunc, error)
 }
 
 //new creates a new core instance
@@ -42,10 +81,19 @@ type core struct {
 //processUpdates can be an optional feedback channel provided by the caller
 func newCore(cfg *config.Config, overrides *OverridesBuilder, kubeClient kubernetes.Interface, processUpdates func(ProcessUpdate)) *core {
 	return &core{
-		cfg:            cfg,
-		overrides:      overrides,
-		processUpdates: processUpdates,
-		kubeClient:     kubeClient,
+		cfg:              cfg,
+		overrides:        overrides,
+		processUpdates:   processUpdates,
+		kubeClient:       kubeClient,
+		events:           newEventRecorder(cfg, kubeClient),
+		statistics:       newStatisticsRecorder(cfg, kubeClient),
+		metrics:          newMetricsPusher(cfg, kubeClient),
+		clock:            engine.RealClock,
+		throttle:         newUpdateThrottle(cfg),
+		shutdown:         newShutdown(),
+		streams:          newUpdateStreams(),
+		newDynamicClient: newDynamicClient,
+		newPodExecutor:   newPodExecutor,
 	}
 }
 
@@ -69,18 +117,42 @@ func (i *core) getConfig() (overrides.Provider, *engine.Engine, *engine.Engine,
 	}
 
 	//create KymaComponentMetadataTemplate and set prerequisites flag
-	kymaMetadataTpl := helm.NewKymaComponentMetadataTemplate(i.cfg.Version, i.cfg.Profile)
-	prerequisitesProvider := components.NewComponentsProvider(overridesProvider, i.cfg, i.cfg.ComponentList.Prerequisites, kymaMetadataTpl.ForPrerequisites())
-	componentsProvider := components.NewComponentsProvider(overridesProvider, i.cfg, i.cfg.ComponentList.Components, kymaMetadataTpl.ForComponents())
+	compList, err := i.componentList()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if i.evaluateSkipConditions {
+		var skipped []skippedComponent
+		if compList, skipped, err = i.withoutSkippedComponents(o, compList); err != nil {
+			return nil, nil, nil, err
+		}
+		i.reportSkipped(skipped)
+	}
+	kymaMetadataTpl := i.metadataTemplate()
+	prerequisitesProvider := components.NewComponentsProvider(overridesProvider, i.cfg, compList.Prerequisites, kymaMetadataTpl.ForPrerequisites())
+	componentsProvider := components.NewComponentsProvider(overridesProvider, i.cfg, compList.Components, kymaMetadataTpl.ForComponents())
 
+	if err := i.loadDurations(); err != nil {
+		return nil, nil, nil, err
+	}
 	prerequisitesEngineCfg := engine.Config{
 		// prerequisite components need to be installed sequentially, so only 1 worker should be used
-		WorkersCount: 1,
-		Log:          i.cfg.Log,
+		WorkersCount:        1,
+		Log:                 i.cfg.Log,
+		Durations:           i.durations,
+		Clock:               i.clock,
+		ReportProgress:      true,
+		APIs:                i.apiChecker(),
+		RequiredAPIsTimeout: i.cfg.RequiredAPIsTimeout,
 	}
 	componentsEngineCfg := engine.Config{
-		WorkersCount: i.cfg.WorkersCount,
-		Log:          i.cfg.Log,
+		WorkersCount:        i.cfg.WorkersCount,
+		Log:                 i.cfg.Log,
+		Durations:           i.durations,
+		Clock:               i.clock,
+		ReportProgress:      true,
+		APIs:                i.apiChecker(),
+		RequiredAPIsTimeout: i.cfg.RequiredAPIsTimeout,
 	}
 
 	prerequisitesEng := engine.NewEngine(overridesProvider, prerequisitesProvider, prerequisitesEngineCfg)
@@ -89,18 +161,133 @@ func (i *core) getConfig() (overrides.Provider, *engine.Engine, *engine.Engine,
 	return overridesProvider, prerequisitesEng, componentsEng, nil
 }
 
-func calculateDuration(start time.Time, end time.Time, duration time.Duration) time.Duration {
-	elapsedTime := end.Sub(start)
-	return duration - elapsedTime
+//apiChecker returns the checker of the APIs required by components. The simulation does not install any APIs, so they are not checked.
+func (i *core) apiChecker() engine.APIChecker {
+	if i.cfg.Backend == config.SimulationBackend {
+		return nil
+	}
+	return &engine.DiscoveryAPIChecker{Discovery: i.kubeClient.Discovery()}
+}
+
+//componentList returns the components of the selected bundles with the release names and namespaces of the release naming templates
+func (i *core) componentList() (*config.ComponentList, error) {
+	compList, err := i.cfg.ComponentList.Select(i.cfg.Bundles)
+	if err != nil {
+		return nil, err
+	}
+	return i.cfg.Naming().Apply(compList)
+}
+
+//loadDurations reads the observed component durations once. Without durations file, they are only kept in memory.
+//Durations are not used if the DurationScheduling feature is disabled.
+func (i *core) loadDurations() error {
+	if i.durations != nil || !i.cfg.FeatureGates.Enabled(config.DurationScheduling) {
+		return nil
+	}
+	if i.cfg.ComponentDurationsFile == "" {
+		i.durations = engine.NewDurations()
+		return nil
+	}
+	durations, err := engine.LoadDurations(i.cfg.ComponentDurationsFile)
+	if err != nil {
+		return err
+	}
+	i.durations = durations
+	return nil
+}
+
+//saveDurations stores the observed component durations for the next run. Failures are only logged.
+func (i *core) saveDurations() {
+	if i.durations == nil || i.cfg.ComponentDurationsFile == "" {
+		return
+	}
+	if err := i.durations.Save(i.cfg.ComponentDurationsFile); err != nil {
+		i.cfg.Log.Warnf("Failed to store component durations in '%s': %v", i.cfg.ComponentDurationsFile, err)
+	}
+}
+
+//releaseComponent returns the component of a single release which is uninstalled with the configured installation backend
+func (i *core) releaseComponent(name, namespace string) components.KymaComponent {
+	if i.newReleaseComponent != nil {
+		return i.newReleaseComponent(name, namespace)
+	}
+	return components.NewComponentsProvider(nil, i.cfg, nil, nil).GetRelease(name, namespace)
+}
+
+//metadataTemplate returns the template of the Kyma metadata which is attached to the deployed releases
+func (i *core) metadataTemplate() *helm.KymaComponentMetadataTemplate {
+	tpl := helm.NewKymaComponentMetadataTemplate(i.cfg.Version, i.cfg.Profile)
+	tpl.Tenant = i.cfg.Tenant()
+	if i.cfg.Source != nil {
+		tpl.SourceType = string(i.cfg.Source.Type)
+		tpl.SourceID = i.cfg.Source.ID()
+	}
+	return tpl
+}
+
+//metadataProvider returns a KymaMetadataProvider for the releases of the configured tenant
+func (i *core) metadataProvider() *helm.KymaMetadataProvider {
+	return helm.GetKymaMetadataProvider(i.kubeClient).WithStorage(i.cfg.HelmStorage).WithTenant(i.cfg.Tenant())
+}
+
+//deadlines are the cancel and quit deadlines of a process. They span all phases of the process,
+//so the time of a phase is not granted again to the following phases.
+type deadlines struct {
+	cancel time.Time
+	quit   time.Time
+	//windowEnd is set if the process is cancelled because the maintenance window closes before the cancel timeout
+	windowEnd time.Time
+}
+
+//newDeadlines starts the cancel and quit timeouts of a process.
+//The process is cancelled when the maintenance window closes, and running components get the regular time until the quit timeout to finish.
+func (i *core) newDeadlines() deadlines {
+	now := i.clock.Now()
+	d := deadlines{
+		cancel: now.Add(i.cfg.CancelTimeout),
+		quit:   now.Add(i.cfg.QuitTimeout),
+	}
+	if !i.windowEnd.IsZero() && i.windowEnd.Before(d.cancel) {
+		d.quit = i.windowEnd.Add(i.cfg.QuitTimeout - i.cfg.CancelTimeout)
+		d.cancel = i.windowEnd
+		d.windowEnd = i.windowEnd
+	}
+	return d
+}
+
+//timeouts returns channels which receive a value at the cancel and quit deadlines. Deadlines which passed already fire immediately.
+func (i *core) timeouts(d deadlines) (cancel <-chan time.Time, quit <-chan time.Time) {
+	now := i.clock.Now()
+	return i.clock.After(remainingDuration(now, d.cancel)), i.clock.After(remainingDuration(now, d.quit))
+}
+
+//timeoutError returns the error of a deployment which reached the cancel deadline of the deadlines
+func (i *core) timeoutError(d deadlines) error {
+	if !d.windowEnd.IsZero() {
+		return i.cfg.Catalog().New(messages.MaintenanceWindowExceeded, messages.Args{"End": d.windowEnd.Format(time.RFC3339)}).Wrap(installerrors.ErrCancelled)
+	}
+	return i.cfg.Catalog().New(messages.DeploymentTimeout, nil).Wrap(installerrors.ErrCancelled)
+}
+
+func remainingDuration(now time.Time, deadline time.Time) time.Duration {
+	if remaining := deadline.Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
 }
 
 // Send process update event
 func (i *core) processUpdate(phase InstallationPhase, event ProcessEvent, err error) {
-	if i.processUpdates == nil {
-		return
+	i.events.recordPhase(phase, event, err)
+	if log, ok := i.cfg.Log.(logger.StructuredInterface); ok {
+		if err != nil {
+			log.Errorw("Process update", "phase", phase, "event", event, "error", err.Error())
+		} else {
+			log.Infow("Process update", "phase", phase, "event", event)
+		}
 	}
 	//fire callback
-	i.processUpdates(ProcessUpdate{
+	i.deliverUpdate(ProcessUpdate{
 		Event:     event,
 		Phase:     phase,
 		Component: components.KymaComponent{},
@@ -110,31 +297,67 @@ func (i *core) processUpdate(phase InstallationPhase, event ProcessEvent, err er
 
 // Send process update event related to a component
 func (i *core) processUpdateComponent(phase InstallationPhase, comp components.KymaComponent) {
-	if i.processUpdates == nil {
-		return
-	}
+	i.events.recordComponentFailure(phase, comp)
+	i.statistics.recordComponent(phase, comp)
+	i.metrics.recordComponent(phase, comp)
 	// define event type
 	event := ProcessRunning
 	if comp.Status == components.StatusError {
 		event = ProcessExecutionFailure
 	}
+	if log, ok := i.cfg.Log.(logger.StructuredInterface); ok {
+		if comp.Error != nil {
+			log.Errorw("Component update", "phase", phase, "event", event, "component", comp.Name, "namespace", comp.Namespace, "status", comp.Status, "error", comp.Error.Error())
+		} else {
+			log.Infow("Component update", "phase", phase, "event", event, "component", comp.Name, "namespace", comp.Namespace, "status", comp.Status)
+		}
+	}
 	//// fire callback
-	i.processUpdates(ProcessUpdate{
+	i.deliverUpdate(ProcessUpdate{
 		Event:     event,
 		Phase:     phase,
 		Component: comp,
 	})
 }
 
-func isK3dCluster(kubeClient kubernetes.Interface) (isK3d bool, err error) {
+//deliverUpdate fires the callback and sends the update to the open update streams.
+//Repetitive updates are coalesced if the update throttle is enabled.
+func (i *core) deliverUpdate(update ProcessUpdate) {
+	if i.processUpdates == nil && !i.streams.active() {
+		return
+	}
+	if i.throttle == nil {
+		i.notify(update)
+		return
+	}
+	i.throttle.send(update, i.clock.Now(), i.notify)
+}
 
-	retryOptions := []retry.Option{
-		retry.Delay(2 * time.Second),
-		retry.Attempts(3),
-		retry.DelayType(retry.FixedDelay),
+//notify passes the update to the callback and the update streams
+func (i *core) notify(update ProcessUpdate) {
+	if i.processUpdates != nil {
+		i.processUpdates(update)
 	}
+	i.streams.send(update)
+}
+
+//processed returns whether the component was installed, uninstalled, or failed.
+//Intermediate statuses and skipped components are not processed.
+func processed(comp components.KymaComponent) bool {
+	return comp.Status.Final() && comp.Status != components.StatusSkipped
+}
 
-	err = retry.Do(func() error {
+//componentFailure returns the failure of a component which was processed with an error
+func componentFailure(comp components.KymaComponent) *installerrors.ErrComponentFailed {
+	var failure *installerrors.ErrComponentFailed
+	if errors.As(comp.Error, &failure) {
+		return failure
+	}
+	return &installerrors.ErrComponentFailed{Component: comp.Name, Err: comp.Error}
+}
+
+func isK3dCluster(kubeClient kubernetes.Interface, retryPolicy retry.Policy) (isK3d bool, err error) {
+	err = retryPolicy.Do(func() error {
 		nodeList, err := kubeClient.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
 		if err != nil {
 			return err
@@ -148,7 +371,7 @@ func isK3dCluster(kubeClient kubernetes.Interface) (isK3d bool, err error) {
 		}
 
 		return nil
-	}, retryOptions...)
+	})
 	if err != nil {
 		return isK3d, err
 	}
@@ -156,14 +379,8 @@ func isK3dCluster(kubeClient kubernetes.Interface) (isK3d bool, err error) {
 	return isK3d, nil
 }
 
-func getK3dClusterName(kubeClient kubernetes.Interface) (k3dName string, err error) {
-	retryOptions := []retry.Option{
-		retry.Delay(2 * time.Second),
-		retry.Attempts(3),
-		retry.DelayType(retry.FixedDelay),
-	}
-
-	err = retry.Do(func() error {
+func getK3dClusterName(kubeClient kubernetes.Interface, retryPolicy retry.Policy) (k3dName string, err error) {
+	err = retryPolicy.Do(func() error {
 		labelSelector := metav1.LabelSelector{
 			MatchLabels: map[string]string{"node-role.kubernetes.io/master": "true"},
 		}
@@ -189,7 +406,7 @@ func getK3dClusterName(kubeClient kubernetes.Interface) (k3dName string, err err
 		}
 
 		return nil
-	}, retryOptions...)
+	})
 	if err != nil {
 		return k3dName, err
 	}
@@ -197,10 +414,19 @@ func getK3dClusterName(kubeClient kubernetes.Interface) (k3dName string, err err
 	return k3dName, nil
 }
 
-func registerOverridesInterceptors(ob *OverridesBuilder, kubeClient kubernetes.Interface, log logger.Interface) {
+func registerOverridesInterceptors(ob *OverridesBuilder, kubeClient kubernetes.Interface, log logger.Interface, retryPolicy retry.Policy, fips bool) {
 	//hide certificate data
-	ob.AddInterceptor([]string{"global.domainName", "global.ingress.domainName"}, NewDomainNameOverrideInterceptor(kubeClient, log))
-	ob.AddInterceptor([]string{"global.tlsCrt", "global.tlsKey"}, NewCertificateOverrideInterceptor("global.tlsCrt", "global.tlsKey", kubeClient))
+	var detectors []DomainDetector
+	for _, newDetector := range ob.domainDetectors {
+		detectors = append(detectors, newDetector(kubeClient))
+	}
+	domainNameInterceptor := NewDomainNameOverrideInterceptor(kubeClient, log, detectors...)
+	domainNameInterceptor.retryPolicy = retryPolicy
+	ob.AddInterceptor([]string{"global.domainName", "global.ingress.domainName"}, domainNameInterceptor)
+	certificateInterceptor := NewCertificateOverrideInterceptor("global.tlsCrt", "global.tlsKey", kubeClient)
+	certificateInterceptor.retryPolicy = retryPolicy
+	certificateInterceptor.fips = fips
+	ob.AddInterceptor([]string{"global.tlsCrt", "global.tlsKey"}, certificateInterceptor)
 	// make sure we don't install legacy CRDs
 	ob.AddInterceptor([]string{"global.installCRDs"}, NewInstallLegacyCRDsInterceptor())
 
@@ -208,8 +434,85 @@ func registerOverridesInterceptors(ob *OverridesBuilder, kubeClient kubernetes.I
 	ob.AddInterceptor([]string{"tracing.kcproxy.enabled", "kiali.kcproxy.enabled"}, NewDisableKCProxyInterceptor())
 
 	// make sure k3d clusters use k3d container registry
-	ob.AddInterceptor([]string{"serverless.dockerRegistry.internalServerAddress", "serverless.dockerRegistry.serverAddress", "serverless.dockerRegistry.registryAddress"}, NewRegistryInterceptor(kubeClient))
+	registryInterceptor := NewRegistryInterceptor(kubeClient)
+	registryInterceptor.retryPolicy = retryPolicy
+	ob.AddInterceptor([]string{"serverless.dockerRegistry.internalServerAddress", "serverless.dockerRegistry.serverAddress", "serverless.dockerRegistry.registryAddress"}, registryInterceptor)
 
 	// make sure k3d clusters disable internal container registry
-	ob.AddInterceptor([]string{"serverless.dockerRegistry.enableInternal"}, NewRegistryDisableInterceptor(kubeClient))
+	registryDisableInterceptor := NewRegistryDisableInterceptor(kubeClient)
+	registryDisableInterceptor.retryPolicy = retryPolicy
+	ob.AddInterceptor([]string{"serverless.dockerRegistry.enableInternal"}, registryDisableInterceptor)
+}
+
+// applyProviderQuirks inspects the cluster and adds the overrides of the provider quirks as defaults, which user overrides take precedence over
+func applyProviderQuirks(ob *OverridesBuilder, cfg *config.Config, kubeClient kubernetes.Interface) (*cluster.Adjustments, error) {
+	if !cfg.ProviderQuirks {
+		return nil, nil
+	}
+	info, err := cluster.InspectClient(kubeClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to inspect the cluster")
+	}
+	adjustments, err := cluster.Adjust(info)
+	if err != nil {
+		return nil, err
+	}
+	if len(adjustments.Quirks) > 0 {
+		cfg.Log.Infof("Applying quirks of provider %s: %s", info.Provider, strings.Join(adjustments.Quirks, ", "))
+	}
+	ob.addDefaults(adjustments.Overrides)
+	return adjustments, nil
+}
+
+// registerGeneratedSecretInterceptors generates the declared secrets and exposes their values as overrides.
+// Generated passwords comply with the password policy, and the passwords the deployment uses are checked against it.
+// In FIPS mode, keys and certificates are generated with approved parameters.
+func registerGeneratedSecretInterceptors(ob *OverridesBuilder, kubeClient kubernetes.Interface, cfg *config.Config) error {
+	generator := secrets.NewGenerator(kubeClient, cfg.Log).WithPasswordPolicy(cfg.PasswordPolicy).WithFIPS(cfg.FIPS)
+	passwords := make(map[*secrets.Spec]string)
+	for i := range ob.secretSpecs {
+		spec := &ob.secretSpecs[i]
+		data, err := generator.Generate(*spec)
+		if err != nil {
+			return err
+		}
+		if spec.Kind == secrets.Password {
+			passwords[spec] = string(data[secrets.PasswordKey])
+		}
+		for dataKey, overrideKey := range spec.Overrides {
+			value := string(data[dataKey])
+			if spec.Base64 {
+				value = base64.StdEncoding.EncodeToString(data[dataKey])
+			}
+			ob.AddInterceptor([]string{overrideKey}, NewGeneratedSecretOverrideInterceptor(value))
+		}
+	}
+	return checkPasswordPolicy(ob, passwords, cfg.PasswordPolicy)
+}
+
+// registerCustomDomainInterceptors replaces the domain and certificate interceptors if a custom domain is configured
+func registerCustomDomainInterceptors(ob *OverridesBuilder, cfg *config.Config, kubeClient kubernetes.Interface) error {
+	if cfg.Domain == "" {
+		return nil
+	}
+	ob.AddInterceptor([]string{"global.domainName", "global.ingress.domainName"}, NewCustomDomainOverrideInterceptor(cfg.Domain))
+
+	if cfg.TLS == nil {
+		return nil
+	}
+	crt, key, err := cfg.TLS.Load(kubeClient)
+	if err != nil {
+		return err
+	}
+	if err := config.ValidateCertificate(crt, key, cfg.Domain, time.Now()); err != nil {
+		return err
+	}
+	if cfg.FIPS {
+		if err := config.CheckFIPSCertificate("TLS certificate of the custom domain", crt); err != nil {
+			return err
+		}
+	}
+	ob.AddInterceptor([]string{"global.tlsCrt"}, NewCustomCertificateOverrideInterceptor(crt))
+	ob.AddInterceptor([]string{"global.tlsKey"}, NewCustomCertificateOverrideInterceptor(key))
+	return nil
 }
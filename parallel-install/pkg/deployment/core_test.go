@@ -1,10 +1,21 @@
 package deployment
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
 	"time"
 
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/engine"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
 )
@@ -69,3 +80,228 @@ func (o *mockOverridesProvider) OverridesGetterFunctionFor(name string) func() m
 func (o *mockOverridesProvider) ReadOverridesFromCluster() error {
 	return nil
 }
+
+func TestCore_LogProcessUpdates(t *testing.T) {
+	out := &bytes.Buffer{}
+	cfg := &config.Config{Log: logger.NewJSONLogger(true, out)}
+	c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+
+	c.processUpdate(InstallComponents, ProcessStart, nil)
+	c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: "broken", Namespace: "kyma-system", Status: components.StatusError, Error: errors.New("chart failed")})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var phase map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &phase))
+	require.Equal(t, "info", phase["level"])
+	require.Equal(t, string(InstallComponents), phase["phase"])
+	require.Equal(t, string(ProcessStart), phase["event"])
+
+	var component map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &component))
+	require.Equal(t, "error", component["level"])
+	require.Equal(t, "broken", component["component"])
+	require.Equal(t, string(components.StatusError), component["status"])
+	require.Equal(t, "chart failed", component["error"])
+}
+
+func TestCore_DurationSchedulingFeature(t *testing.T) {
+	cfg := &config.Config{
+		Log:          logger.NewLogger(true),
+		FeatureGates: config.FeatureGates{string(config.DurationScheduling): false},
+	}
+	c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+
+	require.NoError(t, c.loadDurations())
+	require.Nil(t, c.durations)
+
+	cfg.FeatureGates = nil
+	require.NoError(t, c.loadDurations())
+	require.NotNil(t, c.durations)
+}
+
+func TestCore_Deadlines(t *testing.T) {
+	cfg := &config.Config{
+		CancelTimeout: 10 * time.Minute,
+		QuitTimeout:   15 * time.Minute,
+		Log:           logger.NewLogger(true),
+	}
+
+	t.Run("should span all phases", func(t *testing.T) {
+		clock := newFakeClock()
+		c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+		c.clock = clock
+
+		deadlines := c.newDeadlines()
+		//first phase
+		c.timeouts(deadlines)
+		clock.Advance(4 * time.Minute)
+		//second phase
+		c.timeouts(deadlines)
+
+		require.Equal(t, []time.Duration{10 * time.Minute, 15 * time.Minute, 6 * time.Minute, 11 * time.Minute}, clock.Requested())
+	})
+
+	t.Run("should fire at the deadlines", func(t *testing.T) {
+		clock := newFakeClock()
+		c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+		c.clock = clock
+
+		cancel, quit := c.timeouts(c.newDeadlines())
+		clock.Advance(10*time.Minute - time.Second)
+		requireNotFired(t, cancel)
+		clock.Advance(time.Second)
+		requireFired(t, cancel)
+		requireNotFired(t, quit)
+		clock.Advance(5 * time.Minute)
+		requireFired(t, quit)
+	})
+
+	t.Run("should fire immediately if the deadlines passed in a previous phase", func(t *testing.T) {
+		clock := newFakeClock()
+		c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+		c.clock = clock
+
+		deadlines := c.newDeadlines()
+		clock.Advance(20 * time.Minute)
+		cancel, quit := c.timeouts(deadlines)
+
+		requireFired(t, cancel)
+		requireFired(t, quit)
+	})
+
+	t.Run("should quit the next phase if the deadline passed", func(t *testing.T) {
+		clock := newFakeClock()
+		kubeClient := fake.NewSimpleClientset()
+		i := newDeletion(t, nil, kubeClient, nil)
+		i.clock = clock
+
+		hc := &blockingHelmClient{release: make(chan struct{})}
+		defer close(hc.release)
+		eng := engine.NewEngine(&mockOverridesProvider{}, &blockingProvider{hc}, engine.Config{
+			WorkersCount: 1,
+			Log:          logger.NewLogger(true),
+			Clock:        clock,
+		})
+
+		deadlines := i.newDeadlines()
+		clock.Advance(i.cfg.QuitTimeout)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := i.uninstallComponents(ctx, cancel, UninstallPreRequisites, eng, deadlines)
+		require.True(t, errors.Is(err, installerrors.ErrQuitTimeout))
+	})
+}
+
+func requireFired(t *testing.T, timeout <-chan time.Time) {
+	select {
+	case <-timeout:
+	default:
+		require.Fail(t, "Timeout did not fire")
+	}
+}
+
+func requireNotFired(t *testing.T, timeout <-chan time.Time) {
+	select {
+	case <-timeout:
+		require.Fail(t, "Timeout fired too early")
+	default:
+	}
+}
+
+//fakeClock only advances when it is told to. Its timers fire when the clock is advanced past their deadline.
+type fakeClock struct {
+	mu        sync.Mutex
+	now       time.Time
+	requested []time.Duration
+	timers    []fakeTimer
+}
+
+type fakeTimer struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requested = append(c.requested, d)
+	timer := fakeTimer{deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		timer.c <- c.now
+	} else {
+		c.timers = append(c.timers, timer)
+	}
+	return timer.c
+}
+
+//Advance moves the clock forward and fires the timers whose deadline passed
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	var pending []fakeTimer
+	for _, timer := range c.timers {
+		if timer.deadline.After(c.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.c <- c.now
+	}
+	c.timers = pending
+}
+
+//Requested returns the durations of all timers
+func (c *fakeClock) Requested() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration{}, c.requested...)
+}
+
+//blockingHelmClient blocks every operation until it is released
+type blockingHelmClient struct {
+	release chan struct{}
+}
+
+func (c *blockingHelmClient) DeployRelease(ctx context.Context, chartDir, namespace, name string, overrides map[string]interface{}, profile string) error {
+	<-c.release
+	return nil
+}
+
+func (c *blockingHelmClient) UninstallRelease(ctx context.Context, namespace, name string) error {
+	<-c.release
+	return nil
+}
+
+//blockingProvider provides a single component which is processed by a blockingHelmClient
+type blockingProvider struct {
+	hc *blockingHelmClient
+}
+
+func (p *blockingProvider) GetComponents() []components.KymaComponent {
+	return []components.KymaComponent{
+		{
+			Name:            "blocked",
+			Namespace:       "blocked",
+			OverridesGetter: func() map[string]interface{} { return nil },
+			HelmClient:      p.hc,
+			Log:             logger.NewLogger(true),
+		},
+	}
+}
@@ -8,8 +8,8 @@ import (
 	"fmt"
 	"html/template"
 
-	"github.com/avast/retry-go"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
 	v1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -53,8 +53,8 @@ const (
 )
 
 // patchCoreDNS patches the CoreDNS cnfiguration based on the overrides and the cloud provider.
-func patchCoreDNS(kubeClient kubernetes.Interface, overrides *OverridesBuilder, isK3s bool, log logger.Interface) (cm *v1.ConfigMap, err error) {
-	err = retry.Do(func() error {
+func patchCoreDNS(kubeClient kubernetes.Interface, overrides *OverridesBuilder, isK3s bool, log logger.Interface, retryPolicy retry.Policy) (cm *v1.ConfigMap, err error) {
+	err = retryPolicy.Do(func() error {
 		_, err := kubeClient.AppsV1().Deployments("kube-system").Get(context.TODO(), "coredns", metav1.GetOptions{})
 		if err != nil {
 			if apierr.IsNotFound(err) {
@@ -65,7 +65,7 @@ func patchCoreDNS(kubeClient kubernetes.Interface, overrides *OverridesBuilder,
 		}
 
 		// patches contains each key and value that needs to be patched in the coredns configmap data field.
-		patches, err := generatePatches(kubeClient, overrides, isK3s)
+		patches, err := generatePatches(kubeClient, overrides, isK3s, retryPolicy)
 		if err != nil {
 			return err
 		}
@@ -74,7 +74,7 @@ func patchCoreDNS(kubeClient kubernetes.Interface, overrides *OverridesBuilder,
 			return err
 		}
 		return nil
-	}, defaultRetryOptions()...)
+	})
 
 	return
 }
@@ -131,10 +131,10 @@ func newCoreDNSConfigMap(data map[string]string) *v1.ConfigMap {
 	}
 }
 
-func generatePatches(kubeClient kubernetes.Interface, overrides *OverridesBuilder, isK3s bool) (map[string]string, error) {
+func generatePatches(kubeClient kubernetes.Interface, overrides *OverridesBuilder, isK3s bool, retryPolicy retry.Policy) (map[string]string, error) {
 	patches := make(map[string]string)
 	// patch the CoreFile only if not on gardener and no custom domain is provided
-	gardenerDomain, err := findGardenerDomain(kubeClient)
+	gardenerDomain, err := findGardenerDomain(kubeClient, retryPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +158,7 @@ func generatePatches(kubeClient kubernetes.Interface, overrides *OverridesBuilde
 
 	// Patch NodeHosts only on K3s
 	if isK3s {
-		patches["NodeHosts"], err = generateHosts(kubeClient)
+		patches["NodeHosts"], err = generateHosts(kubeClient, retryPolicy)
 		if err != nil {
 			return nil, err
 		}
@@ -182,8 +182,8 @@ func generateCorefile(domainName string) (coreFile string, err error) {
 	return
 }
 
-func generateHosts(kubeClient kubernetes.Interface) (string, error) {
-	clusterName, err := getK3dClusterName(kubeClient)
+func generateHosts(kubeClient kubernetes.Interface, retryPolicy retry.Policy) (string, error) {
+	clusterName, err := getK3dClusterName(kubeClient, retryPolicy)
 	if err != nil {
 		return "", err
 	}
@@ -7,6 +7,7 @@ import (
 	dockerTypes "github.com/docker/docker/api/types"
 	dockerNet "github.com/docker/docker/api/types/network"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
 	"github.com/stretchr/testify/require"
 	v1apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -25,7 +26,7 @@ func Test_patchCoreDNS(t *testing.T) {
 		kubeClient := fake.NewSimpleClientset(coreDNSConfigMap)
 
 		// when
-		cm, err := patchCoreDNS(kubeClient, &OverridesBuilder{}, false, log)
+		cm, err := patchCoreDNS(kubeClient, &OverridesBuilder{}, false, log, retry.Default())
 
 		// then
 		require.NoError(t, err)
@@ -44,7 +45,7 @@ func Test_patchCoreDNS(t *testing.T) {
 		kubeClient := fake.NewSimpleClientset(coreDNSDeployment, emptyConfigMap)
 
 		// when
-		cm, err := patchCoreDNS(kubeClient, &OverridesBuilder{}, false, log)
+		cm, err := patchCoreDNS(kubeClient, &OverridesBuilder{}, false, log, retry.Default())
 
 		// then
 		require.NoError(t, err)
@@ -56,7 +57,7 @@ func Test_patchCoreDNS(t *testing.T) {
 		kubeClient := fake.NewSimpleClientset(coreDNSDeployment, fakeWrongCoreDNSConfigMap())
 
 		// when
-		cm, err := patchCoreDNS(kubeClient, &OverridesBuilder{}, false, log)
+		cm, err := patchCoreDNS(kubeClient, &OverridesBuilder{}, false, log, retry.Default())
 
 		// then
 		require.NoError(t, err)
@@ -81,7 +82,7 @@ func Test_patchCoreDNS(t *testing.T) {
 		}
 
 		// when
-		cm, err := patchCoreDNS(kubeClient, &OverridesBuilder{}, true, log)
+		cm, err := patchCoreDNS(kubeClient, &OverridesBuilder{}, true, log, retry.Default())
 
 		// then
 		require.NoError(t, err)
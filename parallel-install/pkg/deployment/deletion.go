@@ -5,16 +5,17 @@ import (
 	"context"
 	"fmt"
 	"sync"
-	"time"
 
-	"github.com/avast/retry-go"
+	retrygo "github.com/avast/retry-go"
 	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/engine"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
 	"github.com/pkg/errors"
-	v1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -22,21 +23,33 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	defaultNamespaceDeletionConcurrency = 5
+	serviceCatalogGroupVersion          = "servicecatalog.k8s.io/v1beta1"
+)
+
 //Deletion removes Kyma from a cluster
 type Deletion struct {
 	*core
-	mp           *helm.KymaMetadataProvider
-	scclient     *clientset.Clientset
+	mp *helm.KymaMetadataProvider
+	//scclient is created by newSCClient when the cluster serves the Service Catalog API, nil until then
+	scclient     clientset.Interface
+	newSCClient  func() (clientset.Interface, error)
 	dClient      dynamic.Interface
-	retryOptions []retry.Option
+	retryOptions []retrygo.Option
 }
 
 //NewDeletion creates a new Deployment instance for deleting Kyma on a cluster.
-func NewDeletion(cfg *config.Config, ob *OverridesBuilder, processUpdates func(ProcessUpdate), retryOptions []retry.Option) (*Deletion, error) {
+//It is kept for compatibility, new code should use NewDeletion of the v2 package, which accepts functional options.
+func NewDeletion(cfg *config.Config, ob *OverridesBuilder, processUpdates func(ProcessUpdate), retryOptions []retrygo.Option) (*Deletion, error) {
 	if err := cfg.ValidateDeletion(); err != nil {
 		return nil, err
 	}
 
+	if err := applyTransport(cfg); err != nil {
+		return nil, err
+	}
+
 	restConfig, err := config.RestConfig(cfg.KubeconfigSource)
 	if err != nil {
 		return nil, err
@@ -46,9 +59,7 @@ func NewDeletion(cfg *config.Config, ob *OverridesBuilder, processUpdates func(P
 	if err != nil {
 		return nil, err
 	}
-
-	scclient, err := clientset.NewForConfig(restConfig)
-	if err != nil {
+	if err := verifyCluster(kubeClient, restConfig.Host, cfg.Retry()); err != nil {
 		return nil, err
 	}
 
@@ -56,7 +67,9 @@ func NewDeletion(cfg *config.Config, ob *OverridesBuilder, processUpdates func(P
 	if err != nil {
 		return nil, err
 	}
-	registerOverridesInterceptors(ob, kubeClient, cfg.Log)
+	//the deletion deploys no certificates, so the FIPS mode does not apply
+	registerOverridesInterceptors(ob, kubeClient, cfg.Log, cfg.Retry(), false)
+	applyTenancy(cfg)
 
 	core := newCore(cfg, ob, kubeClient, processUpdates)
 
@@ -64,8 +77,12 @@ func NewDeletion(cfg *config.Config, ob *OverridesBuilder, processUpdates func(P
 	if err != nil {
 		return nil, err
 	}
+	mp.WithStorage(cfg.HelmStorage).WithTenant(cfg.Tenant())
 
-	return &Deletion{core, mp, scclient, dClient, retryOptions}, nil
+	newSCClient := func() (clientset.Interface, error) {
+		return clientset.NewForConfig(restConfig)
+	}
+	return &Deletion{core: core, mp: mp, newSCClient: newSCClient, dClient: dClient, retryOptions: retryOptions}, nil
 }
 
 //StartKymaUninstallation removes Kyma from a cluster
@@ -75,51 +92,94 @@ func (i *Deletion) StartKymaUninstallation() error {
 		return err
 	}
 
-	return i.startKymaUninstallation(prerequisitesEng, componentsEng)
+	i.statistics.start(StatisticsUninstall, i.cfg.Version)
+	err = i.startKymaUninstallation(prerequisitesEng, componentsEng)
+	i.statistics.finish(err)
+	return err
 }
 
 func (i *Deletion) startKymaUninstallation(prerequisitesEng *engine.Engine, componentsEng *engine.Engine) error {
-	i.cfg.Log.Info("Kyma uninstallation started")
+	i.cfg.Log.Info(i.cfg.Catalog().Text(messages.UninstallationStarted, nil))
 
 	cancelCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	defer i.saveDurations()
 
-	cancelTimeout := i.cfg.CancelTimeout
-	quitTimeout := i.cfg.QuitTimeout
+
+	if err := i.backupReleaseState(); err != nil {
+		return err
+	}
+	if err := i.exportUserResources(); err != nil {
+		return err
+	}
 
 	namespaces, err := i.mp.Namespaces()
 	if err != nil {
 		return err
 	}
 	//TODO: Delete this when kyma-installer is not used any more.
-	namespaces = append(namespaces, "kyma-installer")
+	if i.cfg.Tenancy == nil { //the installer namespace is shared by all tenants
+		namespaces = append(namespaces, "kyma-installer")
+	}
 
-	startTime := time.Now()
-	err = i.uninstallComponents(cancelCtx, cancel, UninstallComponents, componentsEng, cancelTimeout, quitTimeout)
+	deadlines := i.newDeadlines()
+	err = i.uninstallComponents(cancelCtx, cancel, UninstallComponents, componentsEng, deadlines)
 	if err != nil {
 		return err
 	}
-	endTime := time.Now()
 
-	i.cfg.Log.Info("Kyma prerequisites uninstallation")
+	i.cfg.Log.Info(i.cfg.Catalog().Text(messages.PrerequisitesUninstallationStarted, nil))
 
-	cancelTimeout = calculateDuration(startTime, endTime, i.cfg.CancelTimeout)
-	quitTimeout = calculateDuration(startTime, endTime, i.cfg.QuitTimeout)
-
-	err = i.uninstallComponents(cancelCtx, cancel, UninstallPreRequisites, prerequisitesEng, cancelTimeout, quitTimeout)
+	err = i.uninstallComponents(cancelCtx, cancel, UninstallPreRequisites, prerequisitesEng, deadlines)
 	if err != nil {
 		return err
 	}
 
-	return i.deleteKymaNamespaces(namespaces)
+	if i.cfg.UninstallMode == config.SoftReset {
+		i.cfg.Log.Info(i.cfg.Catalog().Text(messages.NamespacesKept, messages.Args{"Namespaces": namespaces}))
+	} else if err := i.deleteKymaNamespaces(namespaces); err != nil {
+		return err
+	}
+	if err := i.revokeSecurityContextConstraints(); err != nil {
+		return err
+	}
+	return i.deletePriorityClasses()
+}
+
+//UninstallRelease removes a single Kyma release, e.g. a broken component release, without uninstalling the other components.
+//The namespace of the release is kept. Releases which were not installed by Kyma are rejected.
+func (i *Deletion) UninstallRelease(name, namespace string) error {
+	selector := fmt.Sprintf("%sname=%s,%snamespace=%s", helm.KymaLabelPrefix, name, helm.KymaLabelPrefix, namespace)
+	releases, err := i.mp.ReleasesBySelector(selector)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to look up release '%s' in namespace '%s'", name, namespace)
+	}
+	if len(releases) == 0 {
+		return i.cfg.Catalog().New(messages.ReleaseNotFound, messages.Args{"Release": name, "Namespace": namespace})
+	}
+
+	i.cfg.Log.Info(i.cfg.Catalog().Text(messages.ReleaseUninstallationStarted, messages.Args{"Release": name, "Namespace": namespace}))
+	comp := i.releaseComponent(name, namespace)
+	ctx, cancel := context.WithTimeout(context.Background(), i.cfg.QuitTimeout)
+	defer cancel()
+	if err := comp.Uninstall(ctx); err != nil {
+		comp.Status = components.StatusError
+		comp.Error = err
+		i.processUpdateComponent(UninstallComponents, comp)
+		return componentFailure(comp)
+	}
+	comp.Status = components.StatusUninstalled
+	i.processUpdateComponent(UninstallComponents, comp)
+	return nil
 }
 
-func (i *Deletion) uninstallComponents(ctx context.Context, cancelFunc context.CancelFunc, phase InstallationPhase, eng *engine.Engine, cancelTimeout time.Duration, quitTimeout time.Duration) error {
-	cancelTimeoutChan := time.After(cancelTimeout)
-	quitTimeoutChan := time.After(quitTimeout)
+func (i *Deletion) uninstallComponents(ctx context.Context, cancelFunc context.CancelFunc, phase InstallationPhase, eng *engine.Engine, deadlines deadlines) error {
+	cancelTimeoutChan, quitTimeoutChan := i.timeouts(deadlines)
+	shutdownChan := i.shutdown.cancel
 	var statusMap = map[string]string{}
-	var errCount int = 0
+	var failures installerrors.ComponentFailures
 	var timeoutOccured bool = false
+	var interrupted bool = false
 
 	statusChan, err := eng.Uninstall(ctx)
 	if err != nil {
@@ -136,18 +196,21 @@ UninstallLoop:
 			if ok {
 				i.processUpdateComponent(phase, cmp)
 				if cmp.Status == components.StatusError {
-					errCount++
+					failures = append(failures, componentFailure(cmp))
 				}
-				statusMap[cmp.Name] = cmp.Status
+				statusMap[cmp.Name] = string(cmp.Status)
 			} else {
-				if errCount > 0 {
-					err := fmt.Errorf("Kyma uninstallation failed due to errors in %d component(s)", errCount)
+				if len(failures) > 0 {
+					err := i.cfg.Catalog().New(messages.UninstallationComponentsFailed, messages.Args{"Count": len(failures)}).Wrap(failures)
 					i.processUpdate(phase, ProcessExecutionFailure, err)
 					i.logStatuses(statusMap)
 					return err
 				}
 				if timeoutOccured {
-					err := fmt.Errorf("Kyma uninstallation failed due to the timeout")
+					err := i.cfg.Catalog().New(messages.UninstallationTimeout, nil).Wrap(installerrors.ErrCancelled)
+					if interrupted {
+						err = i.cfg.Catalog().New(messages.UninstallationInterrupted, nil).Wrap(installerrors.ErrCancelled)
+					}
 					i.processUpdate(phase, ProcessTimeoutFailure, err)
 					i.logStatuses(statusMap)
 					return err
@@ -156,12 +219,26 @@ UninstallLoop:
 			}
 		case <-cancelTimeoutChan:
 			timeoutOccured = true
-			i.cfg.Log.Errorf("Timeout occurred after %v minutes. Cancelling uninstallation", cancelTimeout.Minutes())
+			i.cfg.Log.Error(i.cfg.Catalog().Text(messages.UninstallationCancelled, messages.Args{"Minutes": i.cfg.CancelTimeout.Minutes()}))
 			cancelFunc()
+		case <-shutdownChan:
+			//the channel stays closed, so it is only received once
+			shutdownChan = nil
+			if !timeoutOccured {
+				timeoutOccured = true
+				interrupted = true
+				i.cfg.Log.Error(i.cfg.Catalog().Text(messages.UninstallationInterrupting, nil))
+				cancelFunc()
+			}
 		case <-quitTimeoutChan:
-			err := fmt.Errorf("Force quit: Kyma uninstallation failed due to the timeout")
+			err := i.cfg.Catalog().New(messages.UninstallationForceQuit, nil).Wrap(installerrors.ErrQuitTimeout)
+			i.processUpdate(phase, ProcessForceQuitFailure, err)
+			i.cfg.Log.Error(i.cfg.Catalog().Text(messages.UninstallationForceQuitting, nil))
+			return err
+		case <-i.shutdown.quit:
+			err := i.cfg.Catalog().New(messages.UninstallationInterruptedForceQuit, nil).Wrap(installerrors.ErrCancelled)
 			i.processUpdate(phase, ProcessForceQuitFailure, err)
-			i.cfg.Log.Error("Uninstallation doesn't stop after it's canceled. Enforcing quit")
+			i.cfg.Log.Error(err)
 			return err
 		}
 	}
@@ -169,107 +246,69 @@ UninstallLoop:
 	return nil
 }
 
+//retryPolicy returns the policy for Kubernetes operations. Retry options passed to NewDeletion take precedence over the configured policy.
+func (i *Deletion) retryPolicy() retry.Policy {
+	if len(i.retryOptions) > 0 {
+		return retry.FromOptions(i.retryOptions...)
+	}
+	return i.cfg.Retry()
+}
+
 func (i *Deletion) deleteKymaNamespaces(namespaces []string) error {
+	namespaces, err := i.withoutExcludedNamespaces(namespaces)
+	if err != nil {
+		return err
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(len(namespaces))
 
 	finishedCh := make(chan bool)
 	errorCh := make(chan error)
+	//bounds the number of namespaces deleted in parallel
+	concurrency := i.cfg.NamespaceDeletionConcurrency
+	if concurrency == 0 {
+		concurrency = defaultNamespaceDeletionConcurrency
+	}
+	slots := make(chan struct{}, concurrency)
 
 	// start deletion in goroutines
 	for _, namespace := range namespaces {
-		err := retry.Do(func() error {
+		if i.cfg.NamespaceDeletion != nil {
+			go func(ns string) {
+				defer wg.Done()
+				slots <- struct{}{}
+				defer func() { <-slots }()
+				if err := i.deleteNamespaceWithDeadline(ns, errorCh); err != nil {
+					errorCh <- err
+				}
+			}(namespace)
+			continue
+		}
+
+		err := i.retryPolicy().Do(func() error {
 			// Check if there are any running Pods left on the namespace
-			pods, err := i.kubeClient.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+			pods, err := i.blockingPods(namespace)
 			if err != nil {
-				errorCh <- err
+				return err
 			}
-
-			if len(pods.Items) > 0 {
-				for _, pod := range pods.Items {
-					if pod.Status.Phase == v1.PodRunning {
-						return errors.New(fmt.Sprintf("Namespace %s could not be deleted because of the running Pod: %s. Trying again..", namespace, pod.Name))
-					}
-				}
+			if len(pods) > 0 {
+				return errors.New(fmt.Sprintf("Namespace %s could not be deleted because of the running Pod: %s. Trying again..", namespace, pods[0].Name))
 			}
 			return nil
-		}, i.retryOptions...)
+		})
 
 		if err != nil {
-			i.cfg.Log.Infof("Namespace %s could not be deleted because of running Pod(s)", namespace)
+			i.cfg.Log.Info(i.cfg.Catalog().Text(messages.NamespaceBlocked, messages.Args{"Namespace": namespace}))
 			wg.Done()
 			continue
 		}
 
 		go func(ns string) {
 			defer wg.Done()
-			if ns == "kyma-system" {
-				//HACK: Delete finalizers of leftover Cluster Service Brokers
-				csbList, err := i.scclient.ServicecatalogV1beta1().ClusterServiceBrokers().List(context.Background(), metav1.ListOptions{})
-				if err != nil {
-					errorCh <- err
-				}
-				for _, csb := range csbList.Items {
-					csb.Finalizers = []string{}
-					_, err := i.scclient.ServicecatalogV1beta1().ClusterServiceBrokers().Update(context.Background(), &csb, metav1.UpdateOptions{})
-					if err != nil {
-						errorCh <- err
-					}
-					i.cfg.Log.Infof("Deleted finalizer from CSB: %s", csb.Name)
-				}
-
-				//HACK: Delete finalizers of leftover Service Brokers
-				sbList, err := i.scclient.ServicecatalogV1beta1().ServiceBrokers(ns).List(context.Background(), metav1.ListOptions{})
-				if err != nil {
-					errorCh <- err
-				}
-				for _, sb := range sbList.Items {
-					sb.Finalizers = []string{}
-					_, err := i.scclient.ServicecatalogV1beta1().ServiceBrokers(ns).Update(context.Background(), &sb, metav1.UpdateOptions{})
-					if err != nil {
-						errorCh <- err
-					}
-					i.cfg.Log.Infof("Deleted finalizer from SB: %s", sb.Name)
-				}
-
-				//HACK: Delete finalizers of leftover Secret
-				secret, err := i.kubeClient.CoreV1().Secrets(ns).Get(context.Background(), "serverless-registry-config-default", metav1.GetOptions{})
-				if err != nil && !apierr.IsNotFound(err) {
-					errorCh <- err
-				}
-				if secret != nil {
-					secret.Finalizers = []string{}
-					if _, err := i.kubeClient.CoreV1().Secrets(ns).Update(context.Background(), secret, metav1.UpdateOptions{}); err != nil {
-						errorCh <- err
-					}
-					i.cfg.Log.Infof("Deleted finalizer from Secret: %s", secret.Name)
-				}
-
-				//HACK: Delete finalizers of leftover ORY Rules
-				ruleResource := schema.GroupVersionResource{
-					Group:    "oathkeeper.ory.sh",
-					Version:  "v1alpha1",
-					Resource: "rules",
-				}
-
-				rules, err := i.dClient.Resource(ruleResource).Namespace(ns).List(context.Background(), metav1.ListOptions{})
-				if err != nil {
-					errorCh <- err
-				}
-				for _, rule := range rules.Items {
-					rule.SetFinalizers(nil)
-					_, err := i.dClient.Resource(ruleResource).Namespace(ns).Update(context.Background(), &rule, metav1.UpdateOptions{})
-					if err != nil {
-						errorCh <- err
-					}
-					i.cfg.Log.Infof("Deleted finalizer from Rule: %s", rule.GetName())
-				}
-			}
-			//remove namespace
-			if err := i.kubeClient.CoreV1().Namespaces().Delete(context.Background(), ns, metav1.DeleteOptions{}); err != nil && !apierr.IsNotFound(err) {
-				errorCh <- err
-			}
-			i.cfg.Log.Infof("Namespace '%s' is removed", ns)
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			i.removeNamespace(ns, errorCh)
 		}(namespace)
 	}
 
@@ -297,3 +336,136 @@ func (i *Deletion) deleteKymaNamespaces(namespaces []string) error {
 		}
 	}
 }
+
+//serviceCatalogClient returns the Service Catalog client. It is only created if the cluster serves the Service Catalog API,
+//otherwise nil is returned.
+func (i *Deletion) serviceCatalogClient() (clientset.Interface, error) {
+	if i.scclient != nil {
+		return i.scclient, nil
+	}
+	resources, err := i.kubeClient.Discovery().ServerResourcesForGroupVersion(serviceCatalogGroupVersion)
+	if apierr.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resources == nil || len(resources.APIResources) == 0 || i.newSCClient == nil {
+		return nil, nil
+	}
+	i.scclient, err = i.newSCClient()
+	return i.scclient, err
+}
+
+//removeServiceBrokerFinalizers deletes the finalizers of leftover (Cluster) Service Brokers, if Service Catalog is installed
+func (i *Deletion) removeServiceBrokerFinalizers(ns string, errorCh chan<- error) {
+	scclient, err := i.serviceCatalogClient()
+	if err != nil {
+		errorCh <- err
+		return
+	}
+	if scclient == nil {
+		i.cfg.Log.Infof("Service Catalog is not installed, skipping the cleanup of Service Brokers")
+		return
+	}
+
+	csbList, err := scclient.ServicecatalogV1beta1().ClusterServiceBrokers().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		errorCh <- err
+		return
+	}
+	for _, csb := range csbList.Items {
+		csb.Finalizers = []string{}
+		_, err := scclient.ServicecatalogV1beta1().ClusterServiceBrokers().Update(context.Background(), &csb, metav1.UpdateOptions{})
+		if err != nil {
+			errorCh <- err
+		}
+		i.cfg.Log.Infof("Deleted finalizer from CSB: %s", csb.Name)
+	}
+
+	sbList, err := scclient.ServicecatalogV1beta1().ServiceBrokers(ns).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		errorCh <- err
+		return
+	}
+	for _, sb := range sbList.Items {
+		sb.Finalizers = []string{}
+		_, err := scclient.ServicecatalogV1beta1().ServiceBrokers(ns).Update(context.Background(), &sb, metav1.UpdateOptions{})
+		if err != nil {
+			errorCh <- err
+		}
+		i.cfg.Log.Infof("Deleted finalizer from SB: %s", sb.Name)
+	}
+}
+
+//withoutExcludedNamespaces removes the namespaces which are kept by the configured exclusion
+func (i *Deletion) withoutExcludedNamespaces(namespaces []string) ([]string, error) {
+	if i.cfg.ExcludedNamespaces == nil {
+		return namespaces, nil
+	}
+	var deleted []string
+	for _, namespace := range namespaces {
+		var nsLabels map[string]string
+		if len(i.cfg.ExcludedNamespaces.Labels) > 0 {
+			ns, err := i.kubeClient.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+			if err != nil && !apierr.IsNotFound(err) {
+				return nil, err
+			}
+			if ns != nil {
+				nsLabels = ns.Labels
+			}
+		}
+		if i.cfg.ExcludedNamespaces.Excludes(namespace, nsLabels) {
+			i.cfg.Log.Infof("Namespace '%s' is excluded from the deletion", namespace)
+			continue
+		}
+		deleted = append(deleted, namespace)
+	}
+	return deleted, nil
+}
+
+//removeNamespace deletes the finalizers of known leftover resources and deletes the namespace
+func (i *Deletion) removeNamespace(ns string, errorCh chan<- error) {
+	if ns == "kyma-system" {
+		//HACK: Delete finalizers of leftover Service Brokers
+		i.removeServiceBrokerFinalizers(ns, errorCh)
+
+		//HACK: Delete finalizers of leftover Secret
+		secret, err := i.kubeClient.CoreV1().Secrets(ns).Get(context.Background(), "serverless-registry-config-default", metav1.GetOptions{})
+		if err != nil && !apierr.IsNotFound(err) {
+			errorCh <- err
+		}
+		if secret != nil {
+			secret.Finalizers = []string{}
+			if _, err := i.kubeClient.CoreV1().Secrets(ns).Update(context.Background(), secret, metav1.UpdateOptions{}); err != nil {
+				errorCh <- err
+			}
+			i.cfg.Log.Infof("Deleted finalizer from Secret: %s", secret.Name)
+		}
+
+		//HACK: Delete finalizers of leftover ORY Rules
+		ruleResource := schema.GroupVersionResource{
+			Group:    "oathkeeper.ory.sh",
+			Version:  "v1alpha1",
+			Resource: "rules",
+		}
+
+		rules, err := i.dClient.Resource(ruleResource).Namespace(ns).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			errorCh <- err
+		}
+		for _, rule := range rules.Items {
+			rule.SetFinalizers(nil)
+			_, err := i.dClient.Resource(ruleResource).Namespace(ns).Update(context.Background(), &rule, metav1.UpdateOptions{})
+			if err != nil {
+				errorCh <- err
+			}
+			i.cfg.Log.Infof("Deleted finalizer from Rule: %s", rule.GetName())
+		}
+	}
+	//remove namespace
+	if err := i.kubeClient.CoreV1().Namespaces().Delete(context.Background(), ns, metav1.DeleteOptions{}); err != nil && !apierr.IsNotFound(err) {
+		errorCh <- err
+	}
+	i.cfg.Log.Info(i.cfg.Catalog().Text(messages.NamespaceRemoved, messages.Args{"Namespace": ns}))
+}
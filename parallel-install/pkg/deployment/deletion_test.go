@@ -1,11 +1,21 @@
 package deployment
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/avast/retry-go"
+	"github.com/kubernetes-sigs/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset"
+	scfake "github.com/kubernetes-sigs/service-catalog/pkg/client/clientset_generated/clientset/fake"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/engine"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -46,6 +56,36 @@ func TestDeployment_StartKymaUninstallation(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("should keep the Kyma namespaces on soft reset", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(&v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "kyma-installer",
+				Labels: map[string]string{"istio-injection": "disabled", "kyma-project.io/installation": ""},
+			},
+		})
+		i := newDeletion(t, nil, kubeClient, nil)
+		i.cfg.UninstallMode = config.SoftReset
+
+		provider := &mockProvider{
+			hc: &mockHelmClient{},
+		}
+		overridesProvider := &mockOverridesProvider{}
+		prerequisitesEng := engine.NewEngine(overridesProvider, provider, engine.Config{
+			WorkersCount: 1,
+			Log:          logger.NewLogger(true),
+		})
+		componentsEng := engine.NewEngine(overridesProvider, provider, engine.Config{
+			WorkersCount: 2,
+			Log:          logger.NewLogger(true),
+		})
+
+		err := i.startKymaUninstallation(prerequisitesEng, componentsEng)
+		assert.NoError(t, err)
+
+		_, err = kubeClient.CoreV1().Namespaces().Get(context.TODO(), "kyma-installer", metav1.GetOptions{})
+		assert.NoError(t, err)
+	})
+
 	t.Run("should fail to uninstall Kyma components", func(t *testing.T) {
 		t.Run("due to cancel timeout", func(t *testing.T) {
 			hc := &mockHelmClient{
@@ -298,6 +338,154 @@ func TestDeployment_DeleteNamespaces(t *testing.T) {
 	})
 }
 
+func TestDeployment_DeleteNamespacesWithExclusion(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kyma-integration"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "natss"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "knative-eventing", Labels: map[string]string{"keep": "true"}}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}},
+	)
+	i := newDeletion(t, nil, kubeClient, nil)
+	i.cfg.NamespaceDeletionConcurrency = 1
+	i.cfg.ExcludedNamespaces = &config.NamespaceExclusion{
+		Names:  []string{"istio-system"},
+		Labels: map[string]string{"keep": "true"},
+	}
+
+	err := i.deleteKymaNamespaces([]string{"kyma-integration", "natss", "knative-eventing", "istio-system", "missing"})
+	assert.NoError(t, err)
+
+	nsList, err := kubeClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	var kept []string
+	for _, ns := range nsList.Items {
+		kept = append(kept, ns.Name)
+	}
+	assert.ElementsMatch(t, []string{"knative-eventing", "istio-system"}, kept)
+}
+
+func TestDeployment_ServiceCatalogCleanup(t *testing.T) {
+	broker := &v1beta1.ClusterServiceBroker{
+		ObjectMeta: metav1.ObjectMeta{Name: "helm-broker", Finalizers: []string{"kubernetes-incubator/service-catalog"}},
+	}
+
+	t.Run("should skip the cleanup without Service Catalog", func(t *testing.T) {
+		i := newDeletion(t, nil, fake.NewSimpleClientset(), nil)
+		i.newSCClient = func() (clientset.Interface, error) {
+			t.Fatal("Service Catalog client must not be created")
+			return nil, nil
+		}
+		errorCh := make(chan error, 10)
+		i.removeServiceBrokerFinalizers("kyma-system", errorCh)
+		close(errorCh)
+		assert.Empty(t, errorCh)
+	})
+
+	t.Run("should remove the finalizers of Service Brokers", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		kubeClient.Resources = []*metav1.APIResourceList{{
+			GroupVersion: "servicecatalog.k8s.io/v1beta1",
+			APIResources: []metav1.APIResource{{Name: "clusterservicebrokers", Kind: "ClusterServiceBroker"}},
+		}}
+		scclient := scfake.NewSimpleClientset(broker)
+		i := newDeletion(t, nil, kubeClient, nil)
+		i.newSCClient = func() (clientset.Interface, error) {
+			return scclient, nil
+		}
+		errorCh := make(chan error, 10)
+		i.removeServiceBrokerFinalizers("kyma-system", errorCh)
+		close(errorCh)
+		assert.Empty(t, errorCh)
+
+		csb, err := scclient.ServicecatalogV1beta1().ClusterServiceBrokers().Get(context.TODO(), "helm-broker", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, csb.Finalizers)
+	})
+}
+
+func TestDeployment_UninstallRelease(t *testing.T) {
+	releaseSecret := func(name, namespace string) *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("sh.helm.release.v1.%s.v1", name),
+				Namespace: namespace,
+				Labels: map[string]string{
+					helm.KymaLabelPrefix + "name":         name,
+					helm.KymaLabelPrefix + "namespace":    namespace,
+					helm.KymaLabelPrefix + "component":    "true",
+					helm.KymaLabelPrefix + "profile":      "evaluation",
+					helm.KymaLabelPrefix + "version":      "1.0.0",
+					helm.KymaLabelPrefix + "operationID":  "opsid",
+					helm.KymaLabelPrefix + "creationTime": "1615831194",
+					helm.KymaLabelPrefix + "priority":     "1",
+					helm.KymaLabelPrefix + "prerequisite": "false",
+				},
+			},
+		}
+	}
+	newReleaseDeletion := func(hc *releaseHelmClient, procUpdates func(ProcessUpdate)) *Deletion {
+		kubeClient := fake.NewSimpleClientset(releaseSecret("logging", "kyma-system"), releaseSecret("monitoring", "kyma-system"))
+		i := newDeletion(t, procUpdates, kubeClient, nil)
+		i.newReleaseComponent = func(name, namespace string) components.KymaComponent {
+			return components.KymaComponent{Name: name, Namespace: namespace, HelmClient: hc, Log: logger.NewLogger(true)}
+		}
+		return i
+	}
+
+	t.Run("should uninstall only the release", func(t *testing.T) {
+		hc := &releaseHelmClient{}
+		var updates []ProcessUpdate
+		i := newReleaseDeletion(hc, func(update ProcessUpdate) { updates = append(updates, update) })
+
+		err := i.UninstallRelease("logging", "kyma-system")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"kyma-system/logging"}, hc.uninstalled)
+		assert.Len(t, updates, 1)
+		assert.Equal(t, components.StatusUninstalled, updates[0].Component.Status)
+	})
+
+	t.Run("should reject releases which are not installed by Kyma", func(t *testing.T) {
+		hc := &releaseHelmClient{}
+		i := newReleaseDeletion(hc, nil)
+
+		err := i.UninstallRelease("logging", "default")
+		assert.Error(t, err)
+		assert.True(t, messages.Is(err, messages.ReleaseNotFound))
+		assert.Empty(t, hc.uninstalled)
+	})
+
+	t.Run("should report a failed uninstallation", func(t *testing.T) {
+		hc := &releaseHelmClient{err: errors.New("release is stuck")}
+		var updates []ProcessUpdate
+		i := newReleaseDeletion(hc, func(update ProcessUpdate) { updates = append(updates, update) })
+
+		err := i.UninstallRelease("monitoring", "kyma-system")
+		var failure *installerrors.ErrComponentFailed
+		assert.True(t, errors.As(err, &failure))
+		assert.Equal(t, "monitoring", failure.Component)
+		assert.Len(t, updates, 1)
+		assert.Equal(t, components.StatusError, updates[0].Component.Status)
+	})
+}
+
+//releaseHelmClient records the uninstalled releases
+type releaseHelmClient struct {
+	uninstalled []string
+	err         error
+}
+
+func (c *releaseHelmClient) DeployRelease(ctx context.Context, chartDir, namespace, name string, overrides map[string]interface{}, profile string) error {
+	return nil
+}
+
+func (c *releaseHelmClient) UninstallRelease(ctx context.Context, namespace, name string) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.uninstalled = append(c.uninstalled, namespace+"/"+name)
+	return nil
+}
+
 // Pass optionally an receiver-channel to get progress updates
 func newDeletion(t *testing.T, procUpdates func(ProcessUpdate), kubeClient kubernetes.Interface, retryOptions []retry.Option) *Deletion {
 	compList, err := config.NewComponentList("../test/data/componentlist.yaml")
@@ -312,6 +500,6 @@ func newDeletion(t *testing.T, procUpdates func(ProcessUpdate), kubeClient kuber
 	}
 	core := newCore(config, &OverridesBuilder{}, kubeClient, procUpdates)
 	metaProv := helm.GetKymaMetadataProvider(kubeClient)
-	return &Deletion{core, metaProv, nil, nil, retryOptions}
+	return &Deletion{core: core, mp: metaProv, retryOptions: retryOptions}
 
 }
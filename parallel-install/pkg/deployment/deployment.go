@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/cluster"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/engine"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/namespace"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
 	"k8s.io/client-go/kubernetes"
@@ -20,11 +23,16 @@ type Deployment struct {
 }
 
 //NewDeployment creates a new Deployment instance for deploying Kyma on a cluster.
+//It is kept for compatibility, new code should use NewDeployment of the v2 package, which accepts functional options.
 func NewDeployment(cfg *config.Config, ob *OverridesBuilder, processUpdates func(ProcessUpdate)) (*Deployment, error) {
 	if err := cfg.ValidateDeployment(); err != nil {
 		return nil, err
 	}
 
+	if err := applyTransport(cfg); err != nil {
+		return nil, err
+	}
+
 	restConfig, err := config.RestConfig(cfg.KubeconfigSource)
 	if err != nil {
 		return nil, err
@@ -34,47 +42,120 @@ func NewDeployment(cfg *config.Config, ob *OverridesBuilder, processUpdates func
 	if err != nil {
 		return nil, err
 	}
+	if err := verifyCluster(kubeClient, restConfig.Host, cfg.Retry()); err != nil {
+		return nil, err
+	}
+	if !cfg.SkipKubernetesVersionCheck {
+		if err := verifyKubernetesVersion(kubeClient); err != nil {
+			return nil, err
+		}
+	}
 
-	registerOverridesInterceptors(ob, kubeClient, cfg.Log)
+	registerOverridesInterceptors(ob, kubeClient, cfg.Log, cfg.Retry(), cfg.FIPS)
+	if err := registerCustomDomainInterceptors(ob, cfg, kubeClient); err != nil {
+		return nil, err
+	}
+	if err := registerGeneratedSecretInterceptors(ob, kubeClient, cfg); err != nil {
+		return nil, err
+	}
+	adjustments, err := applyProviderQuirks(ob, cfg, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	applyTenancy(cfg)
+	applyOpenShiftCompatibility(ob, cfg)
 
 	core := newCore(cfg, ob, kubeClient, processUpdates)
+	core.adjustments = adjustments
+	core.evaluateSkipConditions = true
 
 	return &Deployment{core}, nil
 }
 
+//ProviderAdjustments returns the overrides and preflight expectations of the provider quirks which apply to the cluster.
+//It returns nil if provider quirks are disabled.
+func (d *Deployment) ProviderAdjustments() *cluster.Adjustments {
+	return d.adjustments
+}
+
 //StartKymaDeployment deploys Kyma to a cluster
 func (d *Deployment) StartKymaDeployment() error {
+	//typos in the overrides fail the deployment before waiting for the maintenance window
+	if err := d.checkUnknownOverrides(); err != nil {
+		return err
+	}
+
+	windowEnd, err := d.awaitMaintenanceWindow()
+	if err != nil {
+		return err
+	}
+	d.windowEnd = windowEnd
+
+	if err := d.checkImageArchitectures(); err != nil {
+		return err
+	}
+	if err := d.checkSharedComponents(); err != nil {
+		return err
+	}
+	if err := d.resolveNamespaceConflicts(); err != nil {
+		return err
+	}
+	if err := d.resolveReleaseConflicts(); err != nil {
+		return err
+	}
+
 	overridesProvider, prerequisitesEng, componentsEng, err := d.getConfig()
 	if err != nil {
 		return err
 	}
 
-	return d.startKymaDeployment(overridesProvider, prerequisitesEng, componentsEng)
+	d.statistics.start(StatisticsDeploy, d.cfg.Version)
+	d.metrics.start(StatisticsDeploy, d.cfg.Version)
+	err = d.startKymaDeployment(overridesProvider, prerequisitesEng, componentsEng)
+	d.statistics.finish(err)
+	d.metrics.finish(err)
+	return err
 }
 
 func (d *Deployment) startKymaDeployment(overridesProvider overrides.Provider, prerequisitesEng *engine.Engine, componentsEng *engine.Engine) error {
 	cancelCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	defer d.saveDurations()
+	d.notes.reset()
 
-	d.cfg.Log.Info("Kyma prerequisites deployment")
+	d.cfg.Log.Info(d.cfg.Catalog().Text(messages.PrerequisitesDeploymentStarted, nil))
 
 	err := overridesProvider.ReadOverridesFromCluster()
 	if err != nil {
 		return fmt.Errorf("error while reading overrides: %v", err)
 	}
 
-	isK3s, err := isK3dCluster(d.kubeClient)
+	if err := d.backupBeforeUpgrade(); err != nil {
+		return err
+	}
+
+	//the prerequisites install releases, so whether Kyma is installed is determined before they are deployed
+	staged, err := d.isStagedUpgrade()
 	if err != nil {
 		return err
 	}
-	if _, err := patchCoreDNS(d.kubeClient, d.overrides, isK3s, d.cfg.Log); err != nil {
+
+	if err := d.grantSecurityContextConstraints(); err != nil {
+		return err
+	}
+	if err := d.createPriorityClasses(); err != nil {
 		return err
 	}
 
-	cancelTimeout := d.cfg.CancelTimeout
-	quitTimeout := d.cfg.QuitTimeout
+	isK3s, err := isK3dCluster(d.kubeClient, d.cfg.Retry())
+	if err != nil {
+		return err
+	}
+	if _, err := patchCoreDNS(d.kubeClient, d.overrides, isK3s, d.cfg.Log, d.cfg.Retry()); err != nil {
+		return err
+	}
 
-	startTime := time.Now()
+	deadlines := d.newDeadlines()
 	ns := namespace.Namespace{
 		KubeClient: d.kubeClient,
 		Log:        d.cfg.Log,
@@ -83,26 +164,26 @@ func (d *Deployment) startKymaDeployment(overridesProvider overrides.Provider, p
 	if err != nil {
 		return err
 	}
-	err = d.deployComponents(cancelCtx, cancel, InstallPreRequisites, prerequisitesEng, cancelTimeout, quitTimeout)
+	err = d.deployComponents(cancelCtx, cancel, InstallPreRequisites, prerequisitesEng, deadlines)
 	if err != nil {
 		return err
 	}
-	endTime := time.Now()
-
-	d.cfg.Log.Info("Kyma deployment")
 
-	cancelTimeout = calculateDuration(startTime, endTime, d.cfg.CancelTimeout)
-	quitTimeout = calculateDuration(startTime, endTime, d.cfg.QuitTimeout)
+	d.cfg.Log.Info(d.cfg.Catalog().Text(messages.DeploymentStarted, nil))
 
-	return d.deployComponents(cancelCtx, cancel, InstallComponents, componentsEng, cancelTimeout, quitTimeout)
+	if staged {
+		return d.deployInStages(cancelCtx, cancel, componentsEng, deadlines)
+	}
+	return d.deployComponents(cancelCtx, cancel, InstallComponents, componentsEng, deadlines)
 }
 
-func (i *Deployment) deployComponents(ctx context.Context, cancelFunc context.CancelFunc, phase InstallationPhase, eng *engine.Engine, cancelTimeout time.Duration, quitTimeout time.Duration) error {
-	cancelTimeoutChan := time.After(cancelTimeout)
-	quitTimeoutChan := time.After(quitTimeout)
+func (i *Deployment) deployComponents(ctx context.Context, cancelFunc context.CancelFunc, phase InstallationPhase, eng *engine.Engine, deadlines deadlines) error {
+	cancelTimeoutChan, quitTimeoutChan := i.timeouts(deadlines)
+	shutdownChan := i.shutdown.cancel
 	timeoutOccurred := false
+	interrupted := false
 	statusMap := map[string]string{}
-	errCount := 0
+	var failures installerrors.ComponentFailures
 
 	statusChan, err := eng.Deploy(ctx)
 	if err != nil {
@@ -120,19 +201,23 @@ InstallLoop:
 				i.processUpdateComponent(phase, cmp)
 				//Received a status update
 				if cmp.Status == components.StatusError {
-					errCount++
+					failures = append(failures, componentFailure(cmp))
 				}
-				statusMap[cmp.Name] = cmp.Status
+				i.notes.record(cmp)
+				statusMap[cmp.Name] = string(cmp.Status)
 			} else {
 				//statusChan is closed
-				if errCount > 0 {
-					err := fmt.Errorf("Kyma deployment failed due to errors in %d component(s)", errCount)
+				if len(failures) > 0 {
+					err := i.cfg.Catalog().New(messages.DeploymentComponentsFailed, messages.Args{"Count": len(failures)}).Wrap(failures)
 					i.processUpdate(phase, ProcessExecutionFailure, err)
 					i.logStatuses(statusMap)
 					return err
 				}
 				if timeoutOccurred {
-					err := fmt.Errorf("Kyma deployment failed due to the timeout")
+					err := i.timeoutError(deadlines)
+					if interrupted {
+						err = i.cfg.Catalog().New(messages.DeploymentInterrupted, nil).Wrap(installerrors.ErrCancelled)
+					}
 					i.processUpdate(phase, ProcessTimeoutFailure, err)
 					i.logStatuses(statusMap)
 					return err
@@ -141,12 +226,30 @@ InstallLoop:
 			}
 		case <-cancelTimeoutChan:
 			timeoutOccurred = true
-			i.cfg.Log.Errorf("Timeout occurred after %v minutes. Cancelling deployment", cancelTimeout.Minutes())
+			if deadlines.windowEnd.IsZero() {
+				i.cfg.Log.Error(i.cfg.Catalog().Text(messages.DeploymentCancelled, messages.Args{"Minutes": i.cfg.CancelTimeout.Minutes()}))
+			} else {
+				i.cfg.Log.Error(i.cfg.Catalog().Text(messages.MaintenanceWindowClosing, messages.Args{"End": deadlines.windowEnd.Format(time.RFC3339)}))
+			}
 			cancelFunc()
+		case <-shutdownChan:
+			//the channel stays closed, so it is only received once
+			shutdownChan = nil
+			if !timeoutOccurred {
+				timeoutOccurred = true
+				interrupted = true
+				i.cfg.Log.Error(i.cfg.Catalog().Text(messages.DeploymentInterrupting, nil))
+				cancelFunc()
+			}
 		case <-quitTimeoutChan:
-			err := fmt.Errorf("Force quit: Kyma deployment failed due to the timeout")
+			err := i.cfg.Catalog().New(messages.DeploymentForceQuit, nil).Wrap(installerrors.ErrQuitTimeout)
+			i.processUpdate(phase, ProcessForceQuitFailure, err)
+			i.cfg.Log.Error(i.cfg.Catalog().Text(messages.DeploymentForceQuitting, nil))
+			return err
+		case <-i.shutdown.quit:
+			err := i.cfg.Catalog().New(messages.DeploymentInterruptedForceQuit, nil).Wrap(installerrors.ErrCancelled)
 			i.processUpdate(phase, ProcessForceQuitFailure, err)
-			i.cfg.Log.Errorf("Deployment doesn't stop after it's canceled. Enforcing quit")
+			i.cfg.Log.Error(err)
 			return err
 		}
 	}
@@ -160,20 +263,20 @@ func (i *Deployment) DefaultUpdater() func(update ProcessUpdate) {
 
 		showCompStatus := func(comp components.KymaComponent) {
 			if comp.Name != "" {
-				i.cfg.Log.Infof("Status of component '%s': %s", comp.Name, comp.Status)
+				i.cfg.Log.Info(i.cfg.Catalog().Text(messages.ComponentStatus, messages.Args{"Component": comp.Name, "Status": comp.Status}))
 			}
 		}
 
 		switch update.Event {
 		case ProcessStart:
-			i.cfg.Log.Infof("Starting installation phase '%s'", update.Phase)
+			i.cfg.Log.Info(i.cfg.Catalog().Text(messages.PhaseStarted, messages.Args{"Phase": update.Phase}))
 		case ProcessRunning:
 			showCompStatus(update.Component)
 		case ProcessFinished:
-			i.cfg.Log.Infof("Finished installation phase '%s' successfully", update.Phase)
+			i.cfg.Log.Info(i.cfg.Catalog().Text(messages.PhaseFinished, messages.Args{"Phase": update.Phase}))
 		default:
 			//any failure case
-			i.cfg.Log.Infof("Process failed in phase '%s' with error state '%s':", update.Phase, update.Event)
+			i.cfg.Log.Info(i.cfg.Catalog().Text(messages.PhaseFailed, messages.Args{"Phase": update.Phase, "Event": update.Event}))
 			showCompStatus(update.Component)
 		}
 	}
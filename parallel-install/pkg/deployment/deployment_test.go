@@ -1,6 +1,7 @@
 package deployment
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
@@ -10,6 +11,8 @@ import (
 
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/engine"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
@@ -160,6 +163,8 @@ func TestDeployment_StartKymaDeployment(t *testing.T) {
 
 			assert.Error(t, err)
 			assert.EqualError(t, err, "Kyma deployment failed due to the timeout")
+			assert.True(t, messages.Is(err, messages.DeploymentTimeout))
+			assert.True(t, errors.Is(err, installerrors.ErrCancelled))
 
 			t.Logf("Elapsed time: %v", elapsed.Seconds())
 			// Cancel timeout occurs at 150 ms
@@ -195,6 +200,8 @@ func TestDeployment_StartKymaDeployment(t *testing.T) {
 
 			assert.Error(t, err)
 			assert.EqualError(t, err, "Force quit: Kyma deployment failed due to the timeout")
+			assert.True(t, messages.Is(err, messages.DeploymentForceQuit))
+			assert.True(t, errors.Is(err, installerrors.ErrQuitTimeout))
 
 			t.Logf("Elapsed time: %v", elapsed.Seconds())
 			// One component deployment lasts 300 ms
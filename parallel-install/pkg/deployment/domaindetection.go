@@ -0,0 +1,189 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DomainSource describes how the domain of a cluster was detected
+type DomainSource string
+
+const (
+	// DomainSourceGardener is the domain of a Gardener shoot
+	DomainSourceGardener DomainSource = "Gardener"
+	// DomainSourceLocal is the domain used for local k3d clusters
+	DomainSourceLocal DomainSource = "Local"
+	// DomainSourceExplicitIP is a wildcard DNS domain of an IP provided by the user
+	DomainSourceExplicitIP DomainSource = "ExplicitIP"
+	// DomainSourceLoadBalancer is a wildcard DNS domain of the IP of the ingress gateway load balancer
+	DomainSourceLoadBalancer DomainSource = "LoadBalancer"
+	// DomainSourceNode is a wildcard DNS domain of an external node IP, used by clusters exposing the ingress gateway as NodePort or hostPort
+	DomainSourceNode DomainSource = "Node"
+	// DomainSourceDefault is the fallback domain for remote clusters
+	DomainSourceDefault DomainSource = "Default"
+
+	defaultWildcardDNSSuffix = "nip.io"
+	// ipv6WildcardDNSSuffix replaces the default suffix for IPv6 addresses, which nip.io does not resolve
+	ipv6WildcardDNSSuffix = "sslip.io"
+)
+
+// DetectedDomain is the result of a domain detection
+type DetectedDomain struct {
+	Domain string
+	// IP the domain resolves to, nil if not known
+	IP     net.IP
+	Source DomainSource
+}
+
+func (d DetectedDomain) String() string {
+	if d.IP == nil {
+		return fmt.Sprintf("%s (source: %s)", d.Domain, d.Source)
+	}
+	return fmt.Sprintf("%s (source: %s, IP: %s)", d.Domain, d.Source, d.IP)
+}
+
+// DomainDetector detects the domain of a cluster.
+// A detector returns nil if it does not apply to the cluster, so the next detector is asked.
+type DomainDetector interface {
+	Detect() (*DetectedDomain, error)
+}
+
+// WildcardDNSDomainDetector builds a wildcard DNS domain, such as 1.2.3.4.nip.io, out of the IP under which the cluster is reachable.
+// The IP is taken from the first of these sources that is available:
+// the explicit IP, the ingress IP of the ingress gateway load balancer, and the external addresses of the cluster nodes.
+type WildcardDNSDomainDetector struct {
+	kubeClient kubernetes.Interface
+	// IP overrides the detection
+	IP string
+	// Suffix is the wildcard DNS service. Defaults to nip.io, which is replaced by sslip.io for IPv6 addresses.
+	Suffix string
+	// GatewayNamespace and GatewayService identify the ingress gateway. Default to istio-system and istio-ingressgateway.
+	GatewayNamespace string
+	GatewayService   string
+}
+
+// NewWildcardDNSDomainDetector creates a new WildcardDNSDomainDetector instance
+func NewWildcardDNSDomainDetector(kubeClient kubernetes.Interface, ip string) *WildcardDNSDomainDetector {
+	return &WildcardDNSDomainDetector{
+		kubeClient:       kubeClient,
+		IP:               ip,
+		Suffix:           defaultWildcardDNSSuffix,
+		GatewayNamespace: "istio-system",
+		GatewayService:   "istio-ingressgateway",
+	}
+}
+
+func (d *WildcardDNSDomainDetector) Detect() (*DetectedDomain, error) {
+	if d.IP != "" {
+		ip := net.ParseIP(d.IP)
+		if ip == nil {
+			return nil, fmt.Errorf("'%s' is not a valid IP address", d.IP)
+		}
+		return d.domain(ip, DomainSourceExplicitIP), nil
+	}
+
+	ip, err := d.loadBalancerIP()
+	if err != nil {
+		return nil, err
+	}
+	if ip != nil {
+		return d.domain(ip, DomainSourceLoadBalancer), nil
+	}
+
+	ip, err = d.nodeIP()
+	if err != nil {
+		return nil, err
+	}
+	if ip != nil {
+		return d.domain(ip, DomainSourceNode), nil
+	}
+	return nil, nil
+}
+
+// loadBalancerIP returns the ingress IP of the gateway service, or nil if the service does not exist or is no load balancer
+func (d *WildcardDNSDomainDetector) loadBalancerIP() (net.IP, error) {
+	svc, err := d.kubeClient.CoreV1().Services(d.GatewayNamespace).Get(context.TODO(), d.GatewayService, metav1.GetOptions{})
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "could not read the ingress gateway service")
+	}
+	if svc.Spec.Type != v1.ServiceTypeLoadBalancer {
+		return nil, nil
+	}
+	var ips []net.IP
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ip := net.ParseIP(ingress.IP); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return preferIPv4(ips), nil
+}
+
+// nodeIP returns an external IP of the first node which has one, or nil if no node has one.
+// Internal node IPs are not reachable from outside of the cluster network, so no domain is built out of them.
+func (d *WildcardDNSDomainDetector) nodeIP() (net.IP, error) {
+	nodes, err := d.kubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list the cluster nodes")
+	}
+	for _, node := range nodes.Items {
+		var ips []net.IP
+		for _, addr := range node.Status.Addresses {
+			if addr.Type != v1.NodeExternalIP {
+				continue
+			}
+			if ip := net.ParseIP(addr.Address); ip != nil {
+				ips = append(ips, ip)
+			}
+		}
+		if ip := preferIPv4(ips); ip != nil {
+			return ip, nil
+		}
+	}
+	return nil, nil
+}
+
+// preferIPv4 returns the first IPv4 address, or the first IPv6 address if there is none.
+// Dual-stack clusters have addresses of both families, and not every client can reach IPv6 addresses.
+func preferIPv4(ips []net.IP) net.IP {
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip
+		}
+	}
+	if len(ips) > 0 {
+		return ips[0]
+	}
+	return nil
+}
+
+func (d *WildcardDNSDomainDetector) domain(ip net.IP, source DomainSource) *DetectedDomain {
+	suffix := d.Suffix
+	if suffix == "" {
+		suffix = defaultWildcardDNSSuffix
+	}
+
+	host := ip.String()
+	if ip.To4() == nil {
+		// wildcard DNS services expect IPv6 addresses in dashed notation, e.g. 2001-db8--1.sslip.io
+		host = strings.ReplaceAll(host, ":", "-")
+		if suffix == defaultWildcardDNSSuffix {
+			suffix = ipv6WildcardDNSSuffix
+		}
+	}
+	return &DetectedDomain{
+		Domain: fmt.Sprintf("%s.%s", host, suffix),
+		IP:     ip,
+		Source: source,
+	}
+}
@@ -0,0 +1,134 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_WildcardDNSDomainDetector(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{
+				{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+				{Type: v1.NodeExternalIP, Address: "34.1.2.3"},
+			},
+		},
+	}
+	gateway := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-ingressgateway", Namespace: "istio-system"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		Status: v1.ServiceStatus{
+			LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: "35.1.2.3"}}},
+		},
+	}
+
+	t.Run("test explicit IP", func(t *testing.T) {
+		detected, err := NewWildcardDNSDomainDetector(fake.NewSimpleClientset(gateway, node), "1.2.3.4").Detect()
+		require.NoError(t, err)
+		require.Equal(t, "1.2.3.4.nip.io", detected.Domain)
+		require.Equal(t, DomainSourceExplicitIP, detected.Source)
+	})
+
+	t.Run("test invalid explicit IP", func(t *testing.T) {
+		_, err := NewWildcardDNSDomainDetector(fake.NewSimpleClientset(), "1.2.3").Detect()
+		require.Error(t, err)
+	})
+
+	t.Run("test IPv6", func(t *testing.T) {
+		detector := NewWildcardDNSDomainDetector(fake.NewSimpleClientset(), "2001:db8::1")
+		detector.Suffix = "sslip.io"
+		detected, err := detector.Detect()
+		require.NoError(t, err)
+		require.Equal(t, "2001-db8--1.sslip.io", detected.Domain)
+	})
+
+	t.Run("test IPv6 with default suffix", func(t *testing.T) {
+		detected, err := NewWildcardDNSDomainDetector(fake.NewSimpleClientset(), "2001:db8::1").Detect()
+		require.NoError(t, err)
+		require.Equal(t, "2001-db8--1.sslip.io", detected.Domain)
+	})
+
+	t.Run("test dual-stack load balancer prefers IPv4", func(t *testing.T) {
+		dualStackGateway := gateway.DeepCopy()
+		dualStackGateway.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{IP: "2001:db8::35"}, {IP: "35.1.2.3"}}
+		detected, err := NewWildcardDNSDomainDetector(fake.NewSimpleClientset(dualStackGateway), "").Detect()
+		require.NoError(t, err)
+		require.Equal(t, "35.1.2.3.nip.io", detected.Domain)
+	})
+
+	t.Run("test load balancer IP", func(t *testing.T) {
+		detected, err := NewWildcardDNSDomainDetector(fake.NewSimpleClientset(gateway, node), "").Detect()
+		require.NoError(t, err)
+		require.Equal(t, "35.1.2.3.nip.io", detected.Domain)
+		require.Equal(t, DomainSourceLoadBalancer, detected.Source)
+	})
+
+	t.Run("test external node IP without load balancer", func(t *testing.T) {
+		nodePortGateway := gateway.DeepCopy()
+		nodePortGateway.Spec.Type = v1.ServiceTypeNodePort
+		detected, err := NewWildcardDNSDomainDetector(fake.NewSimpleClientset(nodePortGateway, node), "").Detect()
+		require.NoError(t, err)
+		require.Equal(t, "34.1.2.3.nip.io", detected.Domain)
+		require.Equal(t, DomainSourceNode, detected.Source)
+	})
+
+	t.Run("test internal node IP is ignored", func(t *testing.T) {
+		internalNode := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status:     v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}}},
+		}
+		detected, err := NewWildcardDNSDomainDetector(fake.NewSimpleClientset(internalNode), "").Detect()
+		require.NoError(t, err)
+		require.Nil(t, detected)
+	})
+
+	t.Run("test nothing detected", func(t *testing.T) {
+		detected, err := NewWildcardDNSDomainDetector(fake.NewSimpleClientset(), "").Detect()
+		require.NoError(t, err)
+		require.Nil(t, detected)
+	})
+}
+
+func Test_DomainDetectorRegistration(t *testing.T) {
+	// given
+	ob := OverridesBuilder{}
+	ob.AddDomainDetector(func(kubeClient kubernetes.Interface) DomainDetector {
+		return NewWildcardDNSDomainDetector(kubeClient, "1.2.3.4")
+	})
+	kubeClient := fake.NewSimpleClientset()
+	registerOverridesInterceptors(&ob, kubeClient, logger.NewLogger(true), retry.Default(), false)
+
+	// when
+	overrides, err := ob.Build()
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3.4.nip.io", getOverride(overrides.Map(), "global.domainName"))
+	require.Equal(t, DomainSourceExplicitIP, ob.DetectedDomain().Source)
+}
+
+func Test_DomainDetectorLocalCluster(t *testing.T) {
+	// given
+	ob := OverridesBuilder{}
+	ob.AddDomainDetector(func(kubeClient kubernetes.Interface) DomainDetector {
+		return NewWildcardDNSDomainDetector(kubeClient, "1.2.3.4")
+	})
+	kubeClient := fake.NewSimpleClientset(fakeK3dNode())
+	registerOverridesInterceptors(&ob, kubeClient, logger.NewLogger(true), retry.Default(), false)
+
+	// when
+	overrides, err := ob.Build()
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, localKymaDevDomain, getOverride(overrides.Map(), "global.domainName"))
+	require.Equal(t, DomainSourceLocal, ob.DetectedDomain().Source)
+}
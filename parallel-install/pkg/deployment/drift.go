@@ -0,0 +1,50 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
+)
+
+//DetectDrift compares the deployed release of every component against the release rendered with the current
+//resources and overrides, and against the objects running in the cluster.
+//Use it to detect manual changes of the cluster before starting an upgrade.
+func (d *Deployment) DetectDrift() ([]*helm.ReleaseDrift, error) {
+	overridesProvider, _, _, err := d.getConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := overridesProvider.ReadOverridesFromCluster(); err != nil {
+		return nil, fmt.Errorf("error while reading overrides: %v", err)
+	}
+
+	tpl := helm.NewKymaComponentMetadataTemplate(d.cfg.Version, d.cfg.Profile)
+	compList, err := d.componentList()
+	if err != nil {
+		return nil, err
+	}
+	var comps []config.ComponentDefinition
+	comps = append(comps, compList.Prerequisites...)
+	comps = append(comps, compList.Components...)
+	provider := components.NewComponentsProvider(overridesProvider, d.cfg, comps, tpl.ForComponents())
+
+	var drifts []*helm.ReleaseDrift
+	for _, comp := range provider.GetComponents() {
+		detector, ok := comp.HelmClient.(helm.DriftDetector)
+		if !ok {
+			return nil, fmt.Errorf("Drift detection is not supported by the '%s' installation backend", d.cfg.Backend)
+		}
+		drift, err := detector.DetectDrift(context.Background(), comp.ChartDir, comp.Namespace, comp.Name, comp.OverridesGetter(), comp.Profile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to detect drift of component '%s': %v", comp.Name, err)
+		}
+		if drift.Drifted() {
+			d.cfg.Log.Infof("Drift detected for %s", drift)
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
@@ -0,0 +1,144 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultEventsNamespace = "kyma-installer"
+	defaultEventsConfigMap = "kyma-installation-history"
+	eventSource            = "kyma-parallel-install"
+)
+
+//eventRecorder records phase transitions and component failures as Kubernetes Events on a dedicated ConfigMap,
+//so that the installation history can be inspected with 'kubectl describe configmap'.
+//A nil recorder records nothing.
+type eventRecorder struct {
+	namespace  string
+	configMap  string
+	kubeClient kubernetes.Interface
+	log        logger.Interface
+	//involvedObject is resolved when the first Event is recorded
+	involvedObject *v1.ObjectReference
+	//sequence keeps the names of Events recorded at the same time unique
+	sequence int
+}
+
+func newEventRecorder(cfg *config.Config, kubeClient kubernetes.Interface) *eventRecorder {
+	if cfg.Events == nil || kubeClient == nil {
+		return nil
+	}
+	recorder := &eventRecorder{
+		namespace:  cfg.Events.Namespace,
+		configMap:  cfg.Events.ConfigMap,
+		kubeClient: kubeClient,
+		log:        cfg.Log,
+	}
+	if recorder.namespace == "" {
+		recorder.namespace = defaultEventsNamespace
+	}
+	if recorder.configMap == "" {
+		recorder.configMap = defaultEventsConfigMap
+	}
+	return recorder
+}
+
+//recordPhase records a phase transition. Failures are recorded as warnings.
+func (r *eventRecorder) recordPhase(phase InstallationPhase, event ProcessEvent, err error) {
+	if r == nil {
+		return
+	}
+	eventType := v1.EventTypeNormal
+	message := fmt.Sprintf("Phase %s: %s", phase, event)
+	if err != nil {
+		eventType = v1.EventTypeWarning
+		message = fmt.Sprintf("%s: %s", message, err)
+	}
+	r.record(eventType, string(event), message)
+}
+
+//recordComponentFailure records a failed component as warning
+func (r *eventRecorder) recordComponentFailure(phase InstallationPhase, comp components.KymaComponent) {
+	if r == nil || comp.Status != components.StatusError {
+		return
+	}
+	message := fmt.Sprintf("Phase %s: component %s in namespace %s failed", phase, comp.Name, comp.Namespace)
+	if comp.Error != nil {
+		message = fmt.Sprintf("%s: %s", message, comp.Error)
+	}
+	r.record(v1.EventTypeWarning, "ComponentFailed", message)
+}
+
+//recordComponentDegraded records a component which became degraded after the installation as warning
+func (r *eventRecorder) recordComponentDegraded(comp components.KymaComponent) {
+	if r == nil || comp.Status != components.StatusDegraded {
+		return
+	}
+	r.record(v1.EventTypeWarning, "ComponentDegraded", fmt.Sprintf("Component %s in namespace %s is degraded: %s", comp.Name, comp.Namespace, comp.Error))
+}
+
+//record creates the Event. Errors are only logged, as recording must not affect the installation.
+func (r *eventRecorder) record(eventType, reason, message string) {
+	ref, err := r.reference()
+	if err != nil {
+		r.log.Warnf("Failed to record event '%s': %s", reason, err)
+		return
+	}
+	now := metav1.NewTime(time.Now())
+	r.sequence++
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x.%d", r.configMap, now.UnixNano(), r.sequence),
+			Namespace: r.namespace,
+		},
+		InvolvedObject: *ref,
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         v1.EventSource{Component: eventSource},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if _, err := r.kubeClient.CoreV1().Events(r.namespace).Create(context.Background(), event, metav1.CreateOptions{}); err != nil {
+		r.log.Warnf("Failed to record event '%s': %s", reason, err)
+	}
+}
+
+//reference returns the ConfigMap the Events refer to. The namespace and the ConfigMap are created if they do not exist.
+func (r *eventRecorder) reference() (*v1.ObjectReference, error) {
+	if r.involvedObject != nil {
+		return r.involvedObject, nil
+	}
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: r.namespace}}
+	if _, err := r.kubeClient.CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{}); err != nil && !apierr.IsAlreadyExists(err) {
+		return nil, err
+	}
+	cm, err := r.kubeClient.CoreV1().ConfigMaps(r.namespace).Get(context.Background(), r.configMap, metav1.GetOptions{})
+	if apierr.IsNotFound(err) {
+		cm, err = r.kubeClient.CoreV1().ConfigMaps(r.namespace).Create(context.Background(), &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: r.configMap, Namespace: r.namespace},
+		}, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.involvedObject = &v1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       cm.Name,
+		Namespace:  cm.Namespace,
+		UID:        cm.UID,
+	}
+	return r.involvedObject, nil
+}
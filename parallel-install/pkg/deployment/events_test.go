@@ -0,0 +1,57 @@
+package deployment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCore_RecordEvents(t *testing.T) {
+
+	t.Run("Phase transitions and component failures are recorded", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		cfg := &config.Config{Log: logger.NewLogger(true), Events: &config.EventsConfig{}}
+		c := newCore(cfg, &OverridesBuilder{}, kubeClient, nil)
+
+		c.processUpdate(InstallComponents, ProcessStart, nil)
+		c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: "ok", Namespace: "kyma-system", Status: components.StatusInstalled})
+		c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: "broken", Namespace: "kyma-system", Status: components.StatusError, Error: errors.New("chart failed")})
+		c.processUpdate(InstallComponents, ProcessExecutionFailure, errors.New("1 component failed"))
+
+		cm, err := kubeClient.CoreV1().ConfigMaps(defaultEventsNamespace).Get(context.Background(), defaultEventsConfigMap, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		events, err := kubeClient.CoreV1().Events(defaultEventsNamespace).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, events.Items, 3)
+
+		reasons := map[string]v1.Event{}
+		for _, event := range events.Items {
+			require.Equal(t, cm.Name, event.InvolvedObject.Name)
+			require.Equal(t, "ConfigMap", event.InvolvedObject.Kind)
+			reasons[event.Reason] = event
+		}
+		require.Equal(t, v1.EventTypeNormal, reasons[string(ProcessStart)].Type)
+		require.Equal(t, v1.EventTypeWarning, reasons["ComponentFailed"].Type)
+		require.Contains(t, reasons["ComponentFailed"].Message, "broken")
+		require.Contains(t, reasons["ComponentFailed"].Message, "chart failed")
+		require.Equal(t, v1.EventTypeWarning, reasons[string(ProcessExecutionFailure)].Type)
+	})
+
+	t.Run("Nothing is recorded if disabled", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		c := newCore(&config.Config{Log: logger.NewLogger(true)}, &OverridesBuilder{}, kubeClient, nil)
+
+		c.processUpdate(InstallComponents, ProcessStart, nil)
+
+		require.Empty(t, kubeClient.Actions())
+	})
+}
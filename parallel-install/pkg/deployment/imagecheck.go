@@ -0,0 +1,124 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/images"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultImageCheckTimeout = time.Minute
+
+//newImageRegistry is replaced in tests
+var newImageRegistry = func(client *http.Client) *images.Registry {
+	return images.NewRegistry(client)
+}
+
+//checkImageArchitectures verifies that the configured images support the architectures of all cluster nodes.
+//Images which do not are replaced by their multi-arch mirror if they have an override key,
+//otherwise the deployment fails or a warning is logged, depending on the configuration.
+func (d *Deployment) checkImageArchitectures() error {
+	cfg := d.cfg.ImageCheck
+	if cfg == nil || len(cfg.Images) == 0 {
+		return nil
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultImageCheckTimeout
+	}
+
+	architectures, err := d.nodeArchitectures()
+	if err != nil {
+		return err
+	}
+	d.cfg.Log.Infof("Checking %d images for the node architectures %s", len(cfg.Images), strings.Join(architectures, ", "))
+
+	imageList := make([]string, 0, len(cfg.Images))
+	for image := range cfg.Images {
+		imageList = append(imageList, image)
+	}
+	sort.Strings(imageList)
+
+	shutdownCtx, cancelShutdown := d.shutdown.context(context.Background())
+	defer cancelShutdown()
+	ctx, cancel := context.WithTimeout(shutdownCtx, timeout)
+	defer cancel()
+	client, err := d.cfg.HTTPClient(timeout)
+	if err != nil {
+		return err
+	}
+	results := newImageRegistry(client).CheckArchitectures(ctx, imageList, architectures, cfg.Mirrors)
+	if d.shutdown.requested() {
+		return d.interrupted()
+	}
+
+	var mismatches []string
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			d.cfg.Log.Warnf("Failed to check the architectures of image '%s': %s", result.Image, result.Err)
+		case result.OK():
+		case result.Mirror != "" && cfg.Images[result.Image] != "":
+			d.cfg.Log.Infof("Image '%s' does not support %s: using mirror '%s'", result.Image, strings.Join(result.Missing, ", "), result.Mirror)
+			if err := d.overrideImage(cfg.Images[result.Image], result.Mirror); err != nil {
+				return err
+			}
+		default:
+			mismatch := fmt.Sprintf("image '%s' does not support %s", result.Image, strings.Join(result.Missing, ", "))
+			if result.Mirror != "" {
+				mismatch = fmt.Sprintf("%s (mirror '%s' does, but the image has no override key)", mismatch, result.Mirror)
+			}
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+	if cfg.FailOnMismatch {
+		return fmt.Errorf("Images do not match the node architectures: %s", strings.Join(mismatches, "; "))
+	}
+	for _, mismatch := range mismatches {
+		d.cfg.Log.Warnf("Pods might fail with ImagePullBackOff: %s", mismatch)
+	}
+	return nil
+}
+
+//nodeArchitectures returns the sorted architectures of the cluster nodes
+func (d *Deployment) nodeArchitectures() ([]string, error) {
+	nodes, err := d.kubeClient.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the node architectures")
+	}
+	unique := map[string]bool{}
+	for _, node := range nodes.Items {
+		arch := node.Status.NodeInfo.Architecture
+		if arch == "" {
+			arch = node.Labels["kubernetes.io/arch"]
+		}
+		if arch != "" {
+			unique[arch] = true
+		}
+	}
+	architectures := make([]string, 0, len(unique))
+	for arch := range unique {
+		architectures = append(architectures, arch)
+	}
+	sort.Strings(architectures)
+	return architectures, nil
+}
+
+//overrideImage sets the image at the override key, which starts with the chart name
+func (d *Deployment) overrideImage(overrideKey, image string) error {
+	keys := strings.Split(strings.Trim(overrideKey, "."), ".")
+	var value interface{} = image
+	for idx := len(keys) - 1; idx > 0; idx-- {
+		value = map[string]interface{}{keys[idx]: value}
+	}
+	return d.overrides.AddOverrides(keys[0], value.(map[string]interface{}))
+}
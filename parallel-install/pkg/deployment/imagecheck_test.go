@@ -0,0 +1,82 @@
+package deployment
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/images"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_CheckImageArchitectures(t *testing.T) {
+	//registry serving the amd64 image 'controller' and its multi-arch mirror 'mirror/controller'
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/mirror/controller/manifests/v1":
+			fmt.Fprint(w, `{"manifests": [{"platform": {"os": "linux", "architecture": "amd64"}}, {"platform": {"os": "linux", "architecture": "arm64"}}]}`)
+		case "/v2/controller/manifests/v1":
+			fmt.Fprint(w, `{"manifests": [{"platform": {"os": "linux", "architecture": "amd64"}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	origRegistry := newImageRegistry
+	defer func() { newImageRegistry = origRegistry }()
+	newImageRegistry = func(client *http.Client) *images.Registry {
+		return images.NewRegistry(server.Client())
+	}
+
+	kubeClient := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "amd"}, Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{Architecture: "amd64"}}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "arm", Labels: map[string]string{"kubernetes.io/arch": "arm64"}}},
+	)
+	image := host + "/controller:v1"
+
+	newTestDeployment := func(imageCheck *config.ImageCheckConfig) *Deployment {
+		cfg := &config.Config{Log: logger.NewLogger(true), ImageCheck: imageCheck}
+		return &Deployment{newCore(cfg, &OverridesBuilder{}, kubeClient, nil)}
+	}
+
+	t.Run("Image is replaced by its mirror", func(t *testing.T) {
+		d := newTestDeployment(&config.ImageCheckConfig{
+			Images:  map[string]string{image: "serverless.containers.manager.image"},
+			Mirrors: map[string]string{host + "/": host + "/mirror/"},
+		})
+
+		require.NoError(t, d.checkImageArchitectures())
+
+		overrides, err := d.overrides.Raw()
+		require.NoError(t, err)
+		mirror, ok := overrides.Find("serverless.containers.manager.image")
+		require.True(t, ok)
+		require.Equal(t, host+"/mirror/controller:v1", mirror)
+	})
+
+	t.Run("Mismatch fails the deployment", func(t *testing.T) {
+		d := newTestDeployment(&config.ImageCheckConfig{
+			Images:         map[string]string{image: ""},
+			FailOnMismatch: true,
+		})
+
+		err := d.checkImageArchitectures()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not support arm64")
+	})
+
+	t.Run("Mismatch is a warning", func(t *testing.T) {
+		d := newTestDeployment(&config.ImageCheckConfig{Images: map[string]string{image: ""}})
+
+		require.NoError(t, d.checkImageArchitectures())
+	})
+}
@@ -0,0 +1,158 @@
+package deployment
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	consoleSubdomain     = "console"
+	adminUserNamespace   = "kyma-system"
+	adminUserSecret      = "admin-user"
+	gatewayCertNamespace = "istio-system"
+	gatewayCertSecret    = "kyma-gateway-certs"
+)
+
+//InstallationInfo summarizes how users access a deployed Kyma
+type InstallationInfo struct {
+	//Version of Kyma
+	Version string
+	//Domain of the cluster, the Kyma endpoints are its subdomains. Empty if it can't be determined.
+	Domain string
+	//ConsoleURL is the address of the Kyma console. Empty without domain.
+	ConsoleURL string
+	//Admin is the user created by Kyma, nil if its secret does not exist
+	Admin *AdminUser
+	//Certificate of the Kyma gateway, nil if its secret does not exist
+	Certificate *GatewayCertificate
+}
+
+//AdminUser contains the credentials of the admin user created by Kyma
+type AdminUser struct {
+	Email    string
+	Password string
+	//Secret containing the credentials, in the format namespace/name
+	Secret string
+}
+
+//GatewayCertificate describes the certificate the Kyma gateway serves
+type GatewayCertificate struct {
+	Subject  string
+	Issuer   string
+	DNSNames []string
+	NotAfter time.Time
+	//SelfSigned certificates have to be trusted by the clients, e.g. by importing the PEM data
+	SelfSigned bool
+	//PEM encoded certificate
+	PEM []byte
+}
+
+//InstallationInfo gathers the access data of the deployed Kyma: the console URL, the credentials of the admin user, and the gateway certificate.
+//Call it after StartKymaDeployment returned. Data which does not exist on the cluster is left empty.
+func (d *Deployment) InstallationInfo() (*InstallationInfo, error) {
+	info := &InstallationInfo{Version: d.cfg.Version}
+
+	adminSecret, err := d.optionalSecret(adminUserNamespace, adminUserSecret)
+	if err != nil {
+		return nil, err
+	}
+	if adminSecret != nil {
+		info.Admin = &AdminUser{
+			Email:    string(adminSecret.Data["email"]),
+			Password: string(adminSecret.Data["password"]),
+			Secret:   fmt.Sprintf("%s/%s", adminUserNamespace, adminUserSecret),
+		}
+	}
+
+	certSecret, err := d.optionalSecret(gatewayCertNamespace, gatewayCertSecret)
+	if err != nil {
+		return nil, err
+	}
+	if certSecret != nil {
+		if info.Certificate, err = parseGatewayCertificate(certSecret.Data[v1.TLSCertKey]); err != nil {
+			return nil, errors.Wrapf(err, "Failed to read the certificate of secret '%s/%s'", gatewayCertNamespace, gatewayCertSecret)
+		}
+	}
+
+	if info.Domain, err = d.installedDomain(info.Certificate); err != nil {
+		return nil, err
+	}
+	if info.Domain != "" {
+		info.ConsoleURL = fmt.Sprintf("https://%s.%s", consoleSubdomain, info.Domain)
+	}
+	return info, nil
+}
+
+//installedDomain returns the configured domain, the domain detected by the deployment, or the domain of the overrides.
+//Without them, e.g. in a new process, the domain is derived from the wildcard name of the gateway certificate.
+func (d *Deployment) installedDomain(cert *GatewayCertificate) (string, error) {
+	if d.cfg.Domain != "" {
+		return d.cfg.Domain, nil
+	}
+	if detected := d.overrides.DetectedDomain(); detected != nil {
+		return detected.Domain, nil
+	}
+	o, err := d.overrides.Raw()
+	if err != nil {
+		return "", err
+	}
+	if domain, ok := o.Find("global.domainName"); ok && fmt.Sprint(domain) != "" {
+		return fmt.Sprint(domain), nil
+	}
+	if cert != nil {
+		for _, name := range cert.DNSNames {
+			if strings.HasPrefix(name, "*.") {
+				return strings.TrimPrefix(name, "*."), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+//optionalSecret returns the secret, or nil if it does not exist
+func (d *Deployment) optionalSecret(namespace, name string) (*v1.Secret, error) {
+	var secret *v1.Secret
+	err := d.cfg.Retry().Do(func() error {
+		var err error
+		secret, err = d.kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if apierr.IsNotFound(err) {
+			secret = nil
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read secret '%s/%s'", namespace, name)
+	}
+	return secret, nil
+}
+
+//parseGatewayCertificate reads the first certificate of the PEM data, which is the certificate of the gateway
+func parseGatewayCertificate(data []byte) (*GatewayCertificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &GatewayCertificate{
+		Subject:    cert.Subject.CommonName,
+		Issuer:     cert.Issuer.CommonName,
+		DNSNames:   cert.DNSNames,
+		NotAfter:   cert.NotAfter,
+		SelfSigned: bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil,
+		PEM:        data,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package deployment
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeployment_InstallationInfo(t *testing.T) {
+	notAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	crt := newSelfSignedCertificate(t, "*.kyma.example.com", notAfter)
+
+	adminSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin-user", Namespace: "kyma-system"},
+		Data:       map[string][]byte{"email": []byte("admin@kyma.cx"), "password": []byte("s3cr3t")},
+	}
+	certSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kyma-gateway-certs", Namespace: "istio-system"},
+		Data:       map[string][]byte{v1.TLSCertKey: crt},
+	}
+
+	t.Run("Access data of the cluster", func(t *testing.T) {
+		inst := newDeployment(t, nil, fake.NewSimpleClientset(adminSecret, certSecret))
+		inst.cfg.Version = "1.20.0"
+
+		info, err := inst.InstallationInfo()
+		require.NoError(t, err)
+		require.Equal(t, "1.20.0", info.Version)
+		require.Equal(t, "kyma.example.com", info.Domain)
+		require.Equal(t, "https://console.kyma.example.com", info.ConsoleURL)
+		require.Equal(t, &AdminUser{Email: "admin@kyma.cx", Password: "s3cr3t", Secret: "kyma-system/admin-user"}, info.Admin)
+		require.Equal(t, &GatewayCertificate{
+			Subject:    "*.kyma.example.com",
+			Issuer:     "*.kyma.example.com",
+			DNSNames:   []string{"*.kyma.example.com"},
+			NotAfter:   notAfter,
+			SelfSigned: true,
+			PEM:        crt,
+		}, info.Certificate)
+	})
+
+	t.Run("Domain of the overrides", func(t *testing.T) {
+		inst := newDeployment(t, nil, fake.NewSimpleClientset(certSecret))
+		require.NoError(t, inst.overrides.AddOverrides("global", map[string]interface{}{"domainName": "local.kyma.dev"}))
+
+		info, err := inst.InstallationInfo()
+		require.NoError(t, err)
+		require.Equal(t, "https://console.local.kyma.dev", info.ConsoleURL)
+		require.Nil(t, info.Admin)
+	})
+
+	t.Run("Configured domain", func(t *testing.T) {
+		inst := newDeployment(t, nil, fake.NewSimpleClientset())
+		inst.cfg.Domain = "custom.example.com"
+
+		info, err := inst.InstallationInfo()
+		require.NoError(t, err)
+		require.Equal(t, "https://console.custom.example.com", info.ConsoleURL)
+		require.Nil(t, info.Certificate)
+	})
+
+	t.Run("Nothing installed", func(t *testing.T) {
+		info, err := newDeployment(t, nil, fake.NewSimpleClientset()).InstallationInfo()
+		require.NoError(t, err)
+		require.Empty(t, info.Domain)
+		require.Empty(t, info.ConsoleURL)
+		require.Nil(t, info.Admin)
+		require.Nil(t, info.Certificate)
+	})
+
+	t.Run("Invalid certificate", func(t *testing.T) {
+		invalid := certSecret.DeepCopy()
+		invalid.Data[v1.TLSCertKey] = []byte("not a certificate")
+
+		_, err := newDeployment(t, nil, fake.NewSimpleClientset(invalid)).InstallationInfo()
+		require.Error(t, err)
+	})
+}
+
+func newSelfSignedCertificate(t *testing.T, dnsName string, notAfter time.Time) []byte {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,36 @@
+package deployment
+
+import (
+	"fmt"
+
+	"github.com/blang/semver/v4"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+//Supported range of Kubernetes minor versions. The pinned client-go v0.20 and Helm v3.5 libraries are tested against these versions.
+//Kubernetes 1.22 removed the beta APIs, such as apiextensions.k8s.io/v1beta1, which the charts still use.
+//The range is raised together with the library pins, once the charts no longer use the beta APIs.
+const (
+	MinKubernetesVersion = "1.16"
+	MaxKubernetesVersion = "1.21"
+)
+
+//verifyKubernetesVersion fails if the minor version of the API server is outside of the supported range
+func verifyKubernetesVersion(kubeClient kubernetes.Interface) error {
+	info, err := kubeClient.Discovery().ServerVersion()
+	if err != nil {
+		return errors.Wrap(err, "Failed to read the Kubernetes version")
+	}
+	//provider suffixes like v1.20.2-gke.1000 or v1.21.1+k3s1 are ignored
+	version, err := semver.ParseTolerant(info.GitVersion)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Failed to parse the Kubernetes version '%s'", info.GitVersion))
+	}
+	minor := semver.Version{Major: version.Major, Minor: version.Minor}
+	if minor.LT(semver.MustParse(MinKubernetesVersion+".0")) || minor.GT(semver.MustParse(MaxKubernetesVersion+".0")) {
+		return &installerrors.ErrUnsupportedKubernetesVersion{Version: info.GitVersion, Min: MinKubernetesVersion, Max: MaxKubernetesVersion}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package deployment
+
+import (
+	"errors"
+	"testing"
+
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_VerifyKubernetesVersion(t *testing.T) {
+	newClient := func(gitVersion string) *fake.Clientset {
+		kubeClient := fake.NewSimpleClientset()
+		kubeClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: gitVersion}
+		return kubeClient
+	}
+
+	t.Run("Supported versions", func(t *testing.T) {
+		for _, v := range []string{"v1.16.0", "v1.20.2-gke.1000", "v1.21.14+k3s1"} {
+			require.NoError(t, verifyKubernetesVersion(newClient(v)), v)
+		}
+	})
+
+	t.Run("Unsupported versions", func(t *testing.T) {
+		for _, v := range []string{"v1.15.12", "v1.22.0"} {
+			err := verifyKubernetesVersion(newClient(v))
+			var unsupported *installerrors.ErrUnsupportedKubernetesVersion
+			require.True(t, errors.As(err, &unsupported), v)
+			require.Equal(t, v, unsupported.Version)
+		}
+		require.EqualError(t, verifyKubernetesVersion(newClient("v1.22.1")), "Kubernetes version v1.22.1 is not supported: supported versions are 1.16 to 1.21")
+	})
+
+	t.Run("Invalid version", func(t *testing.T) {
+		require.Error(t, verifyKubernetesVersion(newClient("unknown")))
+	})
+}
@@ -0,0 +1,223 @@
+package deployment
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+//LockfileKind is the kind of lockfiles
+const LockfileKind = "InstallationLock"
+
+//Lockfile pins the sources of an installation manifest: the Git revision is resolved to a commit and
+//the charts of the components are recorded with their digests, so the installation can be reproduced later.
+type Lockfile struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   ManifestMetadata `yaml:"metadata"`
+	//Source is the pinned Git source. It is nil for local sources.
+	Source *LockedSource `yaml:"source,omitempty"`
+	//Charts of the components
+	Charts []LockedChart `yaml:"charts"`
+}
+
+//LockedSource is a Git source pinned to a commit
+type LockedSource struct {
+	URL     string   `yaml:"url"`
+	Mirrors []string `yaml:"mirrors,omitempty"`
+	//Revision of the manifest, e.g. a branch or PR
+	Revision string `yaml:"revision"`
+	//Commit the revision pointed to when the lockfile was created
+	Commit string `yaml:"commit"`
+}
+
+//LockedChart is the chart of a component with its digest
+type LockedChart struct {
+	Name string `yaml:"name"`
+	//Digest is the SHA-256 of the files of the chart
+	Digest string `yaml:"digest"`
+}
+
+//Lock resolves the Git revision of the manifest to a commit and records the digests of the component charts.
+//The source is cloned at the commit if its workspace does not exist yet.
+func (m *Manifest) Lock() (*Lockfile, error) {
+	lock := &Lockfile{
+		APIVersion: ManifestAPIVersion,
+		Kind:       LockfileKind,
+		Metadata:   m.Metadata,
+	}
+	if m.Spec.Source.Git != nil {
+		source := m.gitSource()
+		revision := m.gitRevision()
+		commit, err := source.ResolveCommit(revision)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to resolve revision '%s'", revision)
+		}
+		lock.Source = &LockedSource{
+			URL:      source.URL,
+			Mirrors:  source.Mirrors,
+			Revision: revision,
+			Commit:   commit,
+		}
+	}
+
+	cfg, _, err := m.pinned(lock.Source).Build()
+	if err != nil {
+		return nil, err
+	}
+	for _, component := range lockedComponents(cfg.ComponentList) {
+		digest, err := chartDigest(filepath.Join(cfg.ResourcePath, component))
+		if err != nil {
+			return nil, err
+		}
+		lock.Charts = append(lock.Charts, LockedChart{Name: component, Digest: digest})
+	}
+	return lock, nil
+}
+
+//BuildLocked creates the installation config and the overrides like Build, but installs the Git source at the commit of the lockfile
+//and fails if a component chart differs from the lockfile or a component is not in the lockfile.
+func (m *Manifest) BuildLocked(lock *Lockfile) (*config.Config, *OverridesBuilder, error) {
+	if (m.Spec.Source.Git == nil) != (lock.Source == nil) {
+		return nil, nil, fmt.Errorf("Lockfile does not match the source of the installation manifest")
+	}
+	if lock.Source != nil && lock.Source.URL != m.gitSource().URL {
+		return nil, nil, fmt.Errorf("Lockfile pins repository '%s', but the installation manifest uses '%s'", lock.Source.URL, m.gitSource().URL)
+	}
+
+	cfg, ob, err := m.pinned(lock.Source).Build()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	digests := make(map[string]string, len(lock.Charts))
+	for _, chart := range lock.Charts {
+		digests[chart.Name] = chart.Digest
+	}
+	components := lockedComponents(cfg.ComponentList)
+	for _, component := range components {
+		locked, ok := digests[component]
+		if !ok {
+			return nil, nil, fmt.Errorf("Component '%s' is not in the lockfile", component)
+		}
+		digest, err := chartDigest(filepath.Join(cfg.ResourcePath, component))
+		if err != nil {
+			return nil, nil, err
+		}
+		if digest != locked {
+			return nil, nil, fmt.Errorf("Chart of component '%s' differs from the lockfile: expected digest %s, got %s", component, locked, digest)
+		}
+	}
+	if len(components) != len(digests) {
+		return nil, nil, fmt.Errorf("Lockfile contains %d charts, but the installation manifest defines %d components", len(digests), len(components))
+	}
+	return cfg, ob, nil
+}
+
+//FromLockfile creates a Deployment out of an installation manifest, which is only installed as pinned by the lockfile
+func FromLockfile(manifestPath, lockfilePath string) (*Deployment, error) {
+	manifest, err := LoadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := LoadLockfile(lockfilePath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, ob, err := manifest.BuildLocked(lock)
+	if err != nil {
+		return nil, err
+	}
+	return NewDeployment(cfg, ob, nil)
+}
+
+//LoadLockfile reads a lockfile
+func LoadLockfile(path string) (*Lockfile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read lockfile '%s'", path)
+	}
+	lock := &Lockfile{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(lock); err != nil {
+		return nil, errors.Wrapf(err, "Failed to process lockfile '%s'", path)
+	}
+	if lock.APIVersion != ManifestAPIVersion || lock.Kind != LockfileKind {
+		return nil, fmt.Errorf("Lockfile '%s' must be of apiVersion '%s' and kind '%s'", path, ManifestAPIVersion, LockfileKind)
+	}
+	if lock.Source != nil && lock.Source.Commit == "" {
+		return nil, fmt.Errorf("Commit is missing in lockfile '%s'", path)
+	}
+	return lock, nil
+}
+
+//Write stores the lockfile
+func (l *Lockfile) Write(path string) error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+//pinned returns a copy of the manifest whose Git source is pinned to the commit of the locked source
+func (m *Manifest) pinned(source *LockedSource) *Manifest {
+	if source == nil || m.Spec.Source.Git == nil {
+		return m
+	}
+	pinned := *m
+	gitSource := *m.Spec.Source.Git
+	gitSource.Revision = source.Commit
+	pinned.Spec.Source.Git = &gitSource
+	return &pinned
+}
+
+//lockedComponents returns the names of all prerequisites and components
+func lockedComponents(compList *config.ComponentList) []string {
+	var names []string
+	for _, component := range append(append([]config.ComponentDefinition{}, compList.Prerequisites...), compList.Components...) {
+		names = append(names, component.Name)
+	}
+	return names
+}
+
+//chartDigest calculates the SHA-256 of the paths and contents of all files in the chart directory
+func chartDigest(chartDir string) (string, error) {
+	hash := sha256.New()
+	err := filepath.Walk(chartDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(chartDir, path)
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		fmt.Fprintf(hash, "%s\x00", filepath.ToSlash(relPath))
+		if _, err := io.Copy(hash, file); err != nil {
+			return err
+		}
+		_, err = hash.Write([]byte{0})
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to calculate the digest of chart '%s'", chartDir)
+	}
+	return fmt.Sprintf("sha256:%x", hash.Sum(nil)), nil
+}
@@ -0,0 +1,89 @@
+package deployment
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Lockfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lockfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFile := func(path, content string) {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+	writeFile("kyma/resources/istio/Chart.yaml", "name: istio\nversion: 1.0.0\n")
+	writeFile("kyma/resources/eventing/Chart.yaml", "name: eventing\nversion: 1.0.0\n")
+	writeFile("kyma/resources/eventing/templates/deployment.yaml", "kind: Deployment\n")
+	writeFile("installation.yaml", `apiVersion: hydroform.kyma-project.io/v1alpha1
+kind: Installation
+spec:
+  source:
+    local: kyma
+  version: 1.20.0
+  components:
+    prerequisites:
+      - name: istio
+        namespace: istio-system
+    components:
+      - name: eventing
+        namespace: kyma-system
+`)
+	manifestPath := filepath.Join(dir, "installation.yaml")
+	lockfilePath := filepath.Join(dir, "installation.lock")
+
+	manifest, err := LoadManifest(manifestPath)
+	require.NoError(t, err)
+	lock, err := manifest.Lock()
+	require.NoError(t, err)
+	require.Nil(t, lock.Source)
+	require.Len(t, lock.Charts, 2)
+	require.Equal(t, "istio", lock.Charts[0].Name)
+	require.Equal(t, "eventing", lock.Charts[1].Name)
+	require.Contains(t, lock.Charts[0].Digest, "sha256:")
+	require.NoError(t, lock.Write(lockfilePath))
+
+	t.Run("Build from unchanged sources", func(t *testing.T) {
+		lock, err := LoadLockfile(lockfilePath)
+		require.NoError(t, err)
+		cfg, _, err := manifest.BuildLocked(lock)
+		require.NoError(t, err)
+		require.Equal(t, "1.20.0", cfg.Version)
+	})
+
+	t.Run("Changed chart is rejected", func(t *testing.T) {
+		writeFile("kyma/resources/eventing/templates/deployment.yaml", "kind: StatefulSet\n")
+		defer writeFile("kyma/resources/eventing/templates/deployment.yaml", "kind: Deployment\n")
+
+		_, _, err := manifest.BuildLocked(lock)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Chart of component 'eventing' differs from the lockfile")
+	})
+
+	t.Run("Component missing in the lockfile is rejected", func(t *testing.T) {
+		partial := *lock
+		partial.Charts = lock.Charts[:1]
+		_, _, err := manifest.BuildLocked(&partial)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Component 'eventing' is not in the lockfile")
+	})
+
+	t.Run("Lockfile of a Git source is rejected for a local source", func(t *testing.T) {
+		pinned := *lock
+		pinned.Source = &LockedSource{URL: defaultKymaRepository, Revision: "main", Commit: "34edf09a34edf09a34edf09a34edf09a34edf09a"}
+		_, _, err := manifest.BuildLocked(&pinned)
+		require.Error(t, err)
+	})
+
+	t.Run("Manifest is not accepted as lockfile", func(t *testing.T) {
+		_, err := LoadLockfile(manifestPath)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,41 @@
+package deployment
+
+import (
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+)
+
+//awaitMaintenanceWindow waits until a maintenance window is open with at least the minimum remaining time and returns its end.
+//It returns a zero time if no maintenance window is configured, and an error wrapping ErrCancelled if a shutdown is requested while waiting.
+func (d *Deployment) awaitMaintenanceWindow() (time.Time, error) {
+	window := d.cfg.MaintenanceWindow
+	if window == nil {
+		return time.Time{}, nil
+	}
+	for {
+		now := d.clock.Now()
+		start, end, err := window.Window(now)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !start.After(now) {
+			if end.Sub(now) >= window.MinimumRemaining {
+				d.cfg.Log.Info(d.cfg.Catalog().Text(messages.MaintenanceWindowOpen, messages.Args{"End": end.Format(time.RFC3339)}))
+				return end, nil
+			}
+			//too little time remains in the open window, so the deployment waits for the next one
+			if start, err = window.NextOpening(now); err != nil {
+				return time.Time{}, err
+			}
+		}
+		d.cfg.Log.Info(d.cfg.Catalog().Text(messages.MaintenanceWindowWaiting, messages.Args{"Start": start.Format(time.RFC3339)}))
+		select {
+		case <-d.clock.After(start.Sub(now)):
+		case <-d.shutdown.cancel:
+			return time.Time{}, d.interrupted()
+		case <-d.shutdown.quit:
+			return time.Time{}, d.interrupted()
+		}
+	}
+}
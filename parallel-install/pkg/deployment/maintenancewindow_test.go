@@ -0,0 +1,117 @@
+package deployment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeployment_AwaitMaintenanceWindow(t *testing.T) {
+	newWindowDeployment := func(window *config.MaintenanceWindow) (*Deployment, *fakeClock) {
+		cfg := &config.Config{
+			CancelTimeout:     2 * time.Hour,
+			QuitTimeout:       150 * time.Minute,
+			Log:               logger.NewLogger(true),
+			MaintenanceWindow: window,
+		}
+		d := &Deployment{newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)}
+		clock := newFakeClock()
+		d.clock = clock
+		return d, clock
+	}
+	//the fake clock starts on 1 April 2021 at 12:00 UTC
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2021, 4, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	t.Run("Without maintenance window", func(t *testing.T) {
+		d, _ := newWindowDeployment(nil)
+
+		end, err := d.awaitMaintenanceWindow()
+		require.NoError(t, err)
+		require.True(t, end.IsZero())
+	})
+
+	t.Run("Open window", func(t *testing.T) {
+		d, clock := newWindowDeployment(&config.MaintenanceWindow{Schedule: "0 12 * * *", Duration: time.Hour})
+
+		end, err := d.awaitMaintenanceWindow()
+		require.NoError(t, err)
+		require.True(t, at(1, 13, 0).Equal(end))
+		require.Empty(t, clock.Requested())
+	})
+
+	t.Run("Waits for the next window", func(t *testing.T) {
+		d, clock := newWindowDeployment(&config.MaintenanceWindow{Schedule: "0 14 * * *", Duration: time.Hour})
+
+		result := make(chan time.Time, 1)
+		go func() {
+			end, err := d.awaitMaintenanceWindow()
+			require.NoError(t, err)
+			result <- end
+		}()
+
+		require.Eventually(t, func() bool { return len(clock.Requested()) == 1 }, time.Second, time.Millisecond)
+		require.Equal(t, 2*time.Hour, clock.Requested()[0])
+		clock.Advance(2 * time.Hour)
+		require.True(t, at(1, 15, 0).Equal(<-result))
+	})
+
+	t.Run("Shutdown request ends the wait", func(t *testing.T) {
+		d, clock := newWindowDeployment(&config.MaintenanceWindow{Schedule: "0 14 * * *", Duration: time.Hour})
+
+		result := make(chan error, 1)
+		go func() {
+			_, err := d.awaitMaintenanceWindow()
+			result <- err
+		}()
+
+		require.Eventually(t, func() bool { return len(clock.Requested()) == 1 }, time.Second, time.Millisecond)
+		d.Shutdown()
+		err := <-result
+		require.True(t, messages.Is(err, messages.DeploymentInterrupted))
+		require.True(t, errors.Is(err, installerrors.ErrCancelled))
+	})
+
+	t.Run("Skips a window with too little remaining time", func(t *testing.T) {
+		d, clock := newWindowDeployment(&config.MaintenanceWindow{Schedule: "0 11 * * *", Duration: 90 * time.Minute, MinimumRemaining: time.Hour})
+
+		result := make(chan time.Time, 1)
+		go func() {
+			end, err := d.awaitMaintenanceWindow()
+			require.NoError(t, err)
+			result <- end
+		}()
+
+		require.Eventually(t, func() bool { return len(clock.Requested()) == 1 }, time.Second, time.Millisecond)
+		require.Equal(t, 23*time.Hour, clock.Requested()[0])
+		clock.Advance(23 * time.Hour)
+		require.True(t, at(2, 12, 30).Equal(<-result))
+	})
+
+	t.Run("Deployment is cancelled when the window closes", func(t *testing.T) {
+		d, _ := newWindowDeployment(nil)
+
+		deadlines := d.newDeadlines()
+		require.True(t, at(1, 14, 0).Equal(deadlines.cancel))
+		require.True(t, deadlines.windowEnd.IsZero())
+
+		d.windowEnd = at(1, 13, 0)
+		deadlines = d.newDeadlines()
+		require.True(t, at(1, 13, 0).Equal(deadlines.cancel))
+		require.True(t, at(1, 13, 30).Equal(deadlines.quit))
+		require.True(t, at(1, 13, 0).Equal(deadlines.windowEnd))
+
+		d.windowEnd = at(1, 15, 0)
+		deadlines = d.newDeadlines()
+		require.True(t, at(1, 14, 0).Equal(deadlines.cancel))
+		require.True(t, deadlines.windowEnd.IsZero())
+	})
+}
@@ -0,0 +1,393 @@
+package deployment
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	archivepkg "github.com/kyma-incubator/hydroform/parallel-install/pkg/archive"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/download"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/git"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	//ManifestAPIVersion is the supported API version of installation manifests
+	ManifestAPIVersion = "hydroform.kyma-project.io/v1alpha1"
+	//ManifestKind is the kind of installation manifests
+	ManifestKind = "Installation"
+
+	defaultKymaRepository    = "https://github.com/kyma-project/kyma"
+	defaultComponentsFile    = "installation/resources/components.yaml"
+	resourcesDir             = "resources"
+	installationResourcesDir = "installation/resources"
+)
+
+//Manifest is a declarative description of a Kyma installation
+type Manifest struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   ManifestMetadata `yaml:"metadata"`
+	Spec       ManifestSpec     `yaml:"spec"`
+}
+
+//ManifestMetadata identifies the installation
+type ManifestMetadata struct {
+	Name string `yaml:"name"`
+}
+
+//ManifestSpec describes what gets installed and how
+type ManifestSpec struct {
+	//Source of the Kyma resources
+	Source ManifestSource `yaml:"source"`
+	//Kyma version
+	Version string `yaml:"version"`
+	//Installation profile: evaluation|production
+	Profile string `yaml:"profile"`
+	//Path to the kubeconfig. Defaults to the KUBECONFIG environment variable.
+	Kubeconfig string `yaml:"kubeconfig"`
+	//Path to a component list file, relative to the source. Defaults to the component list of the source.
+	ComponentsFile string `yaml:"componentsFile"`
+	//Inline component list, takes precedence over the components file
+	Components *config.ComponentListData `yaml:"components"`
+	//Bundles of the component list to install. Defaults to all components.
+	Bundles []string `yaml:"bundles"`
+	//Override files, relative to the manifest
+	OverridesFiles []string `yaml:"overridesFiles"`
+	//Overrides per chart
+	Overrides map[string]map[string]interface{} `yaml:"overrides"`
+	//Custom Kyma domain
+	Domain string `yaml:"domain"`
+	//Installation backend: helm|modules
+	Backend config.InstallationBackend `yaml:"backend"`
+	//Installer settings
+	Settings ManifestSettings `yaml:"settings"`
+}
+
+//ManifestSource defines where the Kyma resources are taken from. Set either a local directory, a Git repository, or a release archive.
+//The source can change between upgrades, e.g. from a release archive to a Git revision.
+type ManifestSource struct {
+	//Local directory containing the Kyma sources, relative to the manifest
+	Local string `yaml:"local"`
+	//Git repository to clone the Kyma sources from
+	Git *ManifestGitSource `yaml:"git"`
+	//Release archive (.tar.gz, .tgz or .zip) containing the Kyma sources, as HTTP(S) URL or path relative to the manifest.
+	//It is extracted to a new directory in the temp folder.
+	Archive string `yaml:"archive"`
+}
+
+//ManifestGitSource defines a Git repository containing the Kyma sources
+type ManifestGitSource struct {
+	//Repository URL. Defaults to the Kyma repository.
+	URL string `yaml:"url"`
+	//Mirrors of the repository, which are used in order if the repository is not reachable
+	Mirrors []string `yaml:"mirrors"`
+	//Branch, release version, commit hash or PR (e.g. PR-9486). Defaults to the version of the installation.
+	Revision string `yaml:"revision"`
+	//Directory the repository is cloned to. An existing clone is fetched and reset to the revision, local changes are discarded. Defaults to a new directory in the temp folder.
+	Workspace string `yaml:"workspace"`
+}
+
+//ManifestSettings contains the installer settings. Durations use the Go duration format, e.g. 20m.
+type ManifestSettings struct {
+	WorkersCount           int    `yaml:"workersCount"`
+	CancelTimeout          string `yaml:"cancelTimeout"`
+	QuitTimeout            string `yaml:"quitTimeout"`
+	HelmTimeout            string `yaml:"helmTimeout"`
+	HelmMaxRevisionHistory int    `yaml:"helmMaxRevisionHistory"`
+	Atomic                 bool   `yaml:"atomic"`
+	Verbose                bool   `yaml:"verbose"`
+	//LogFormat is either text (default) or json
+	LogFormat string `yaml:"logFormat"`
+}
+
+//LoadManifest reads and validates an installation manifest. Unknown fields are rejected to catch typos.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read installation manifest '%s'", path)
+	}
+
+	manifest := &Manifest{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(manifest); err != nil {
+		return nil, errors.Wrapf(err, "Failed to process installation manifest '%s'", path)
+	}
+	if manifest.APIVersion != ManifestAPIVersion || manifest.Kind != ManifestKind {
+		return nil, fmt.Errorf("Installation manifest '%s' must be of apiVersion '%s' and kind '%s'", path, ManifestAPIVersion, ManifestKind)
+	}
+	if manifest.Spec.Version == "" {
+		return nil, fmt.Errorf("Version is missing in installation manifest '%s'", path)
+	}
+	if manifest.Spec.Source.count() != 1 {
+		return nil, fmt.Errorf("Installation manifest '%s' must define either a local, a Git, or an archive source", path)
+	}
+
+	//relative paths are resolved against the directory of the manifest
+	baseDir := filepath.Dir(path)
+	manifest.Spec.Source.Local = resolvePath(baseDir, manifest.Spec.Source.Local)
+	if !isURL(manifest.Spec.Source.Archive) {
+		manifest.Spec.Source.Archive = resolvePath(baseDir, manifest.Spec.Source.Archive)
+	}
+	manifest.Spec.Kubeconfig = resolvePath(baseDir, manifest.Spec.Kubeconfig)
+	for i, file := range manifest.Spec.OverridesFiles {
+		manifest.Spec.OverridesFiles[i] = resolvePath(baseDir, file)
+	}
+	return manifest, nil
+}
+
+//Build creates the installation config and the overrides described by the manifest.
+//A Git source is cloned if its workspace does not exist yet.
+func (m *Manifest) Build() (*config.Config, *OverridesBuilder, error) {
+	spec := m.Spec
+
+	sourceDir, err := m.sourceDir()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var compList *config.ComponentList
+	if spec.Components != nil {
+		compList = config.NewComponentListFromData(*spec.Components)
+	} else {
+		componentsFile := spec.ComponentsFile
+		if componentsFile == "" {
+			componentsFile = defaultComponentsFile
+		}
+		compList, err = config.NewComponentList(resolvePath(sourceDir, componentsFile))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	kubeconfig := spec.Kubeconfig
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+
+	cfg := &config.Config{
+		WorkersCount:                  4,
+		CancelTimeout:                 20 * time.Minute,
+		QuitTimeout:                   25 * time.Minute,
+		HelmTimeoutSeconds:            60 * 6,
+		BackoffInitialIntervalSeconds: 3,
+		BackoffMaxElapsedTimeSeconds:  60 * 5,
+		Log:                           logger.NewLogger(spec.Settings.Verbose),
+		HelmMaxRevisionHistory:        10,
+		Profile:                       spec.Profile,
+		ComponentList:                 compList,
+		Bundles:                       spec.Bundles,
+		ResourcePath:                  filepath.Join(sourceDir, resourcesDir),
+		InstallationResourcePath:      filepath.Join(sourceDir, installationResourcesDir),
+		KubeconfigSource:              config.KubeconfigSource{Path: kubeconfig},
+		Version:                       spec.Version,
+		Source:                        m.source(),
+		Atomic:                        spec.Settings.Atomic,
+		Domain:                        spec.Domain,
+		Backend:                       spec.Backend,
+	}
+	if err := spec.Settings.applyTo(cfg); err != nil {
+		return nil, nil, err
+	}
+
+	ob := &OverridesBuilder{}
+	for _, file := range spec.OverridesFiles {
+		if err := ob.AddFile(file); err != nil {
+			return nil, nil, err
+		}
+	}
+	for chart, overrides := range spec.Overrides {
+		if err := ob.AddOverrides(chart, overrides); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return cfg, ob, nil
+}
+
+//FromManifest creates a Deployment out of an installation manifest
+func FromManifest(path string) (*Deployment, error) {
+	manifest, err := LoadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, ob, err := manifest.Build()
+	if err != nil {
+		return nil, err
+	}
+	return NewDeployment(cfg, ob, nil)
+}
+
+//sourceDir returns the directory of the Kyma sources. A configured workspace which exists already is fetched and reset to the revision,
+//otherwise the repository is cloned, by default to a new directory in the temp folder. Archives are extracted to a new directory in the temp folder.
+func (m *Manifest) sourceDir() (string, error) {
+	if m.Spec.Source.Archive != "" {
+		return extractArchive(m.Spec.Source.Archive)
+	}
+	if m.Spec.Source.Git == nil {
+		return m.Spec.Source.Local, nil
+	}
+
+	revision := m.gitRevision()
+	workspace := m.Spec.Source.Git.Workspace
+	if workspace == "" {
+		dir, err := ioutil.TempDir("", "kyma-")
+		if err != nil {
+			return "", err
+		}
+		if err := m.gitSource().Clone(dir, revision); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+		return dir, nil
+	}
+
+	if _, err := os.Stat(workspace); err == nil {
+		if err := m.gitSource().Update(workspace, revision); err != nil {
+			return "", errors.Wrapf(err, "Failed to update the workspace '%s' to revision %s", workspace, revision)
+		}
+		return workspace, nil
+	}
+	if err := m.gitSource().Clone(workspace, revision); err != nil {
+		return "", err
+	}
+	return workspace, nil
+}
+
+//source identifies the source of the manifest in the metadata of the releases
+func (m *Manifest) source() *config.Source {
+	src := m.Spec.Source
+	switch {
+	case src.Archive != "":
+		return &config.Source{Type: config.ArchiveSource, Reference: src.Archive}
+	case src.Git != nil:
+		return &config.Source{Type: config.GitSource, Reference: fmt.Sprintf("%s@%s", m.gitSource().URL, m.gitRevision())}
+	default:
+		//the working directory must not change the ID of the source
+		dir, err := filepath.Abs(src.Local)
+		if err != nil {
+			dir = src.Local
+		}
+		return &config.Source{Type: config.LocalSource, Reference: dir}
+	}
+}
+
+//count returns the number of defined sources
+func (s ManifestSource) count() int {
+	count := 0
+	for _, defined := range []bool{s.Local != "", s.Git != nil, s.Archive != ""} {
+		if defined {
+			count++
+		}
+	}
+	return count
+}
+
+//extractArchive downloads the archive if it is a URL and extracts it to a new directory in the temp folder.
+//Release archives contain a single top-level directory, e.g. kyma-1.20.0, which is returned instead of the extraction directory.
+func extractArchive(archive string) (string, error) {
+	dir, err := ioutil.TempDir("", "kyma-")
+	if err != nil {
+		return "", err
+	}
+	file, err := download.GetFile(archive, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", errors.Wrapf(err, "Failed to get the archive '%s'", archive)
+	}
+
+	dst := filepath.Join(dir, "sources")
+	if strings.HasSuffix(file, ".zip") {
+		err = archivepkg.Unzip(file, dst)
+	} else {
+		err = archivepkg.Untar(file, dst)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", errors.Wrapf(err, "Failed to extract the archive '%s'", archive)
+	}
+
+	entries, err := ioutil.ReadDir(dst)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dst, entries[0].Name()), nil
+	}
+	return dst, nil
+}
+
+func isURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+func (m *Manifest) gitSource() git.Source {
+	src := m.Spec.Source.Git
+	url := src.URL
+	if url == "" {
+		url = defaultKymaRepository
+	}
+	return git.Source{URL: url, Mirrors: src.Mirrors}
+}
+
+func (m *Manifest) gitRevision() string {
+	if revision := m.Spec.Source.Git.Revision; revision != "" {
+		return revision
+	}
+	return m.Spec.Version
+}
+
+func (s ManifestSettings) applyTo(cfg *config.Config) error {
+	if s.WorkersCount > 0 {
+		cfg.WorkersCount = s.WorkersCount
+	}
+	if s.HelmMaxRevisionHistory > 0 {
+		cfg.HelmMaxRevisionHistory = s.HelmMaxRevisionHistory
+	}
+	for _, d := range []struct {
+		value  string
+		target *time.Duration
+	}{
+		{s.CancelTimeout, &cfg.CancelTimeout},
+		{s.QuitTimeout, &cfg.QuitTimeout},
+	} {
+		if d.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(d.value)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid duration '%s' in installation manifest", d.value)
+		}
+		*d.target = duration
+	}
+	if s.HelmTimeout != "" {
+		duration, err := time.ParseDuration(s.HelmTimeout)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid duration '%s' in installation manifest", s.HelmTimeout)
+		}
+		cfg.HelmTimeoutSeconds = int(duration.Seconds())
+	}
+	if s.LogFormat != "" {
+		log, err := logger.New(s.Verbose, logger.Format(s.LogFormat))
+		if err != nil {
+			return errors.Wrap(err, "Invalid log format in installation manifest")
+		}
+		cfg.Log = log
+	}
+	return nil
+}
+
+func resolvePath(baseDir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
@@ -0,0 +1,163 @@
+package deployment
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadManifest(t *testing.T) {
+
+	t.Run("Build config from manifest", func(t *testing.T) {
+		manifest, err := LoadManifest("../test/data/manifest/installation.yaml")
+		require.NoError(t, err)
+
+		cfg, ob, err := manifest.Build()
+		require.NoError(t, err)
+
+		require.Equal(t, "1.20.0", cfg.Version)
+		require.Equal(t, "evaluation", cfg.Profile)
+		require.Equal(t, "../test/data/manifest/kyma/resources", cfg.ResourcePath)
+		require.Equal(t, "../test/data/manifest/kyma/installation/resources", cfg.InstallationResourcePath)
+		require.Equal(t, "../test/data/test-kubeconfig.yaml", cfg.KubeconfigSource.Path)
+		require.Equal(t, 2, cfg.WorkersCount)
+		require.Equal(t, 20*time.Minute, cfg.CancelTimeout)
+		require.Equal(t, 30*time.Minute, cfg.QuitTimeout)
+		require.Equal(t, 300, cfg.HelmTimeoutSeconds)
+		require.Equal(t, []config.ComponentDefinition{{Name: "cluster-essentials", Namespace: "kyma-system"}}, cfg.ComponentList.Prerequisites)
+		require.Equal(t, []config.ComponentDefinition{{Name: "serverless", Namespace: "kyma-system"}}, cfg.ComponentList.Components)
+		sourceDir, err := filepath.Abs("../test/data/manifest/kyma")
+		require.NoError(t, err)
+		require.Equal(t, &config.Source{Type: config.LocalSource, Reference: sourceDir}, cfg.Source)
+		require.NoError(t, cfg.ValidateDeployment())
+
+		overrides, err := ob.Build()
+		require.NoError(t, err)
+		value, found := overrides.Find("global.isBEBEnabled")
+		require.True(t, found)
+		require.Equal(t, true, value)
+	})
+
+	t.Run("Inline component list", func(t *testing.T) {
+		manifest, err := LoadManifest("../test/data/manifest/installation-inline-components.yaml")
+		require.NoError(t, err)
+
+		cfg, _, err := manifest.Build()
+		require.NoError(t, err)
+		require.Equal(t, []config.ComponentDefinition{{Name: "istio", Namespace: "istio-system"}}, cfg.ComponentList.Prerequisites)
+		require.Equal(t, []config.ComponentDefinition{{Name: "eventing", Namespace: "kyma-system"}}, cfg.ComponentList.Components)
+	})
+
+	t.Run("Unknown fields are rejected", func(t *testing.T) {
+		_, err := LoadManifest("../test/data/manifest/installation-typo.yaml")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "overides")
+	})
+
+	t.Run("Archive source", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "manifest-")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		writeTarGz(t, filepath.Join(dir, "kyma-1.20.0.tar.gz"), "../test/data/manifest/kyma", "kyma-1.20.0")
+		manifest := `apiVersion: hydroform.kyma-project.io/v1alpha1
+kind: Installation
+spec:
+  version: 1.20.0
+  source:
+    archive: kyma-1.20.0.tar.gz
+`
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "installation.yaml"), []byte(manifest), 0600))
+
+		m, err := LoadManifest(filepath.Join(dir, "installation.yaml"))
+		require.NoError(t, err)
+		cfg, _, err := m.Build()
+		require.NoError(t, err)
+		defer os.RemoveAll(filepath.Dir(filepath.Dir(cfg.ResourcePath)))
+
+		require.Equal(t, "kyma-1.20.0", filepath.Base(filepath.Dir(cfg.ResourcePath)))
+		require.FileExists(t, filepath.Join(cfg.InstallationResourcePath, "components.yaml"))
+		require.Equal(t, config.ArchiveSource, cfg.Source.Type)
+		require.Equal(t, filepath.Join(dir, "kyma-1.20.0.tar.gz"), cfg.Source.Reference)
+	})
+
+	t.Run("Multiple sources are rejected", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "manifest-")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		manifest := `apiVersion: hydroform.kyma-project.io/v1alpha1
+kind: Installation
+spec:
+  version: 1.20.0
+  source:
+    local: kyma
+    archive: https://github.com/kyma-project/kyma/archive/1.20.0.tar.gz
+`
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "installation.yaml"), []byte(manifest), 0600))
+
+		_, err = LoadManifest(filepath.Join(dir, "installation.yaml"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "archive source")
+	})
+
+	t.Run("Missing manifest", func(t *testing.T) {
+		_, err := LoadManifest("../test/data/manifest/notexisting.yaml")
+		require.Error(t, err)
+	})
+
+	t.Run("JSON log format", func(t *testing.T) {
+		cfg := &config.Config{Log: logger.NewLogger(true)}
+		require.NoError(t, ManifestSettings{LogFormat: "json", Verbose: true}.applyTo(cfg))
+		require.Implements(t, (*logger.StructuredInterface)(nil), cfg.Log)
+
+		require.Error(t, ManifestSettings{LogFormat: "xml"}.applyTo(cfg))
+	})
+}
+
+//writeTarGz archives the content of the source directory below the prefix directory
+func writeTarGz(t *testing.T, dst, src, prefix string) {
+	file, err := os.Create(dst)
+	require.NoError(t, err)
+	defer file.Close()
+	gw := gzip.NewWriter(file)
+	defer gw.Close()
+	tw := tar.NewWriter(gw)
+	defer tw.Close()
+
+	err = filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(prefix, rel))
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,192 @@
+package deployment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/statistics"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultPushgatewayNamespace = "kyma-system"
+	defaultPushgatewayService   = "monitoring-prometheus-pushgateway"
+	defaultPushgatewayPort      = 9091
+	defaultPushgatewayJob       = "kyma-installer"
+	metricsPushTimeout          = 30 * time.Second
+	metricsContentType          = "text/plain; version=0.0.4"
+)
+
+//metricsPusher collects the metrics of a deployment and pushes them to the Prometheus Pushgateway when the deployment succeeded.
+//A nil pusher pushes nothing.
+type metricsPusher struct {
+	cfg        *config.MetricsPushConfig
+	httpClient func(timeout time.Duration) (*http.Client, error)
+	kubeClient kubernetes.Interface
+	log        logger.Interface
+	run        *statistics.Run
+}
+
+func newMetricsPusher(cfg *config.Config, kubeClient kubernetes.Interface) *metricsPusher {
+	if cfg.MetricsPush == nil {
+		return nil
+	}
+	return &metricsPusher{
+		cfg:        cfg.MetricsPush,
+		httpClient: cfg.HTTPClient,
+		kubeClient: kubeClient,
+		log:        cfg.Log,
+	}
+}
+
+func (p *metricsPusher) start(operation, version string) {
+	if p == nil {
+		return
+	}
+	p.run = statistics.NewRun(operation, version, time.Now())
+}
+
+func (p *metricsPusher) recordComponent(phase InstallationPhase, comp components.KymaComponent) {
+	if p == nil || p.run == nil || !processed(comp) {
+		return
+	}
+	p.run.Record(statistics.ComponentStats{
+		Name:      comp.Name,
+		Namespace: comp.Namespace,
+		Phase:     string(phase),
+		Duration:  comp.Duration,
+		Retries:   comp.Retries,
+		Failed:    comp.Status == components.StatusError,
+	})
+}
+
+//finish pushes the metrics of a successful run. Failed runs are not pushed, as the monitoring stack might not be running.
+//Errors are only logged, as the metrics must not affect the installation.
+func (p *metricsPusher) finish(err error) {
+	if p == nil || p.run == nil {
+		return
+	}
+	run := p.run
+	p.run = nil
+	if err != nil {
+		return
+	}
+	run.Finish(time.Now(), nil)
+	if err := p.push(run); err != nil {
+		p.log.Warnf("Failed to push the metrics of the %s to the Pushgateway: %v", run.Operation, err)
+		return
+	}
+	p.log.Infof("Pushed the metrics of the %s to the Pushgateway", run.Operation)
+}
+
+func (p *metricsPusher) push(run *statistics.Run) error {
+	job := p.cfg.Job
+	if job == "" {
+		job = defaultPushgatewayJob
+	}
+	path := "/metrics/job/" + url.PathEscape(job)
+	body := renderMetrics(run)
+
+	ctx, cancel := context.WithTimeout(context.Background(), metricsPushTimeout)
+	defer cancel()
+	if p.cfg.URL != "" {
+		return p.pushToURL(ctx, strings.TrimSuffix(p.cfg.URL, "/")+path, body)
+	}
+	return p.pushToService(ctx, path, body)
+}
+
+//pushToURL replaces the metrics of the job by a PUT request
+func (p *metricsPusher) pushToURL(ctx context.Context, pushURL string, body []byte) error {
+	client, err := p.httpClient(metricsPushTimeout)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, pushURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", metricsContentType)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("Pushgateway '%s' returned status %d: %s", pushURL, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+//pushToService replaces the metrics of the job through the API server proxy of the Pushgateway Service
+func (p *metricsPusher) pushToService(ctx context.Context, path string, body []byte) error {
+	namespace := p.cfg.Namespace
+	if namespace == "" {
+		namespace = defaultPushgatewayNamespace
+	}
+	service := p.cfg.Service
+	if service == "" {
+		service = defaultPushgatewayService
+	}
+	port := p.cfg.Port
+	if port == 0 {
+		port = defaultPushgatewayPort
+	}
+	return p.kubeClient.CoreV1().RESTClient().Put().
+		AbsPath(fmt.Sprintf("/api/v1/namespaces/%s/services/%s:%d/proxy%s", namespace, service, port, path)).
+		SetHeader("Content-Type", metricsContentType).
+		Body(body).
+		Do(ctx).
+		Error()
+}
+
+//renderMetrics renders the run in the Prometheus text format
+func renderMetrics(run *statistics.Run) []byte {
+	buf := &bytes.Buffer{}
+	runLabels := fmt.Sprintf(`operation="%s",version="%s"`, escapeLabel(run.Operation), escapeLabel(run.Version))
+
+	writeMetric(buf, "kyma_installation_started_timestamp_seconds", "Time the last successful run started", []string{runLabels},
+		func(string) float64 { return float64(run.Started.UnixNano()) / 1e9 })
+	writeMetric(buf, "kyma_installation_finished_timestamp_seconds", "Time the last successful run finished", []string{runLabels},
+		func(string) float64 { return float64(run.Finished.UnixNano()) / 1e9 })
+	writeMetric(buf, "kyma_installation_duration_seconds", "Duration of the last successful run", []string{runLabels},
+		func(string) float64 { return run.Duration().Seconds() })
+
+	byLabels := make(map[string]statistics.ComponentStats, len(run.Components))
+	var componentLabels []string
+	for _, stats := range run.Components {
+		labels := fmt.Sprintf(`%s,component="%s",namespace="%s",phase="%s"`, runLabels, escapeLabel(stats.Name), escapeLabel(stats.Namespace), escapeLabel(stats.Phase))
+		byLabels[labels] = stats
+		componentLabels = append(componentLabels, labels)
+	}
+	sort.Strings(componentLabels)
+	writeMetric(buf, "kyma_installation_component_duration_seconds", "Duration of the component in the last successful run", componentLabels,
+		func(labels string) float64 { return byLabels[labels].Duration.Seconds() })
+	writeMetric(buf, "kyma_installation_component_retries", "Retries of the component in the last successful run", componentLabels,
+		func(labels string) float64 { return float64(byLabels[labels].Retries) })
+	return buf.Bytes()
+}
+
+func writeMetric(buf *bytes.Buffer, name, help string, labels []string, value func(labels string) float64) {
+	if len(labels) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	for _, l := range labels {
+		fmt.Fprintf(buf, "%s{%s} %g\n", name, l, value(l))
+	}
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
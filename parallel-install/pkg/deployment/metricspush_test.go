@@ -0,0 +1,74 @@
+package deployment
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCore_PushMetrics(t *testing.T) {
+
+	t.Run("Metrics of a successful deployment are pushed", func(t *testing.T) {
+		var method, path, contentType, body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := ioutil.ReadAll(r.Body)
+			method, path, contentType, body = r.Method, r.URL.Path, r.Header.Get("Content-Type"), string(data)
+		}))
+		defer server.Close()
+
+		cfg := &config.Config{Log: logger.NewLogger(true), MetricsPush: &config.MetricsPushConfig{URL: server.URL, Job: "installer"}}
+		c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+
+		c.metrics.start(StatisticsDeploy, "1.20.0")
+		c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: "istio", Namespace: "istio-system", Status: components.StatusInstalled, Duration: 90 * time.Second, Retries: 2})
+		c.metrics.finish(nil)
+
+		require.Equal(t, http.MethodPut, method)
+		require.Equal(t, "/metrics/job/installer", path)
+		require.Equal(t, metricsContentType, contentType)
+		require.Contains(t, body, "# TYPE kyma_installation_duration_seconds gauge\n")
+		require.Contains(t, body, `kyma_installation_finished_timestamp_seconds{operation="deploy",version="1.20.0"} `)
+		require.Contains(t, body, `kyma_installation_component_duration_seconds{operation="deploy",version="1.20.0",component="istio",namespace="istio-system",phase="InstallComponents"} 90`+"\n")
+		require.Contains(t, body, `kyma_installation_component_retries{operation="deploy",version="1.20.0",component="istio",namespace="istio-system",phase="InstallComponents"} 2`+"\n")
+	})
+
+	t.Run("Metrics of a failed deployment are not pushed", func(t *testing.T) {
+		pushed := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pushed = true
+		}))
+		defer server.Close()
+
+		cfg := &config.Config{Log: logger.NewLogger(true), MetricsPush: &config.MetricsPushConfig{URL: server.URL}}
+		c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+
+		c.metrics.start(StatisticsDeploy, "1.20.0")
+		c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: "broken", Status: components.StatusError, Error: errors.New("chart failed")})
+		c.metrics.finish(errors.New("1 component failed"))
+
+		require.False(t, pushed)
+	})
+
+	t.Run("Push failures do not fail the deployment", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "pushgateway down", http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		cfg := &config.Config{Log: logger.NewLogger(true), MetricsPush: &config.MetricsPushConfig{URL: server.URL}}
+		c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+
+		c.metrics.start(StatisticsDeploy, "1.20.0")
+		require.NotPanics(t, func() { c.metrics.finish(nil) })
+		require.Nil(t, c.metrics.run)
+	})
+}
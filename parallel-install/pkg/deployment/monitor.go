@@ -0,0 +1,278 @@
+package deployment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	defaultMonitorPeriod           = 5 * time.Minute
+	defaultMonitorInterval         = 10 * time.Second
+	defaultMonitorRestartThreshold = 3
+	releaseNameAnnotation          = "meta.helm.sh/release-name"
+	crashLoopBackOff               = "CrashLoopBackOff"
+)
+
+//workload is a Deployment, StatefulSet, or DaemonSet of a component
+type workload struct {
+	kind      string
+	name      string
+	desired   int32
+	available int32
+	selector  *metav1.LabelSelector
+}
+
+//componentMonitor checks the workloads of the deployed components and keeps the degraded components
+type componentMonitor struct {
+	*core
+	interval         time.Duration
+	restartThreshold int32
+	//restarts are the container restarts observed by the first check, per Pod UID and container
+	restarts map[string]int32
+	//degraded are the reasons of the degraded components, per component name
+	degraded map[string]string
+}
+
+//Monitor watches the Deployments, StatefulSets, and DaemonSets of the deployed components for the configured period,
+//so that components which passed the Helm wait but fail minutes later are detected.
+//A component is degraded if one of its workloads has unavailable replicas, or a container of its Pods is in CrashLoopBackOff
+//or restarted more often than the restart threshold since the monitoring started.
+//A ProcessComponentDegraded update is sent when a component becomes degraded and a ProcessComponentRecovered update when it recovers.
+//Cancel the context to stop the monitoring before the end of the period.
+//It returns an error listing the components which are degraded at the end of the monitoring.
+func (d *Deployment) Monitor(ctx context.Context) error {
+	compList, err := d.componentList()
+	if err != nil {
+		return err
+	}
+	var comps []config.ComponentDefinition
+	comps = append(comps, compList.Prerequisites...)
+	comps = append(comps, compList.Components...)
+
+	cfg := d.cfg.Monitor
+	if cfg == nil {
+		cfg = &config.MonitorConfig{}
+	}
+	period := cfg.Period
+	if period == 0 {
+		period = defaultMonitorPeriod
+	}
+
+	m := newComponentMonitor(d.core)
+	d.cfg.Log.Info(d.cfg.Catalog().Text(messages.MonitoringStarted, messages.Args{"Count": len(comps), "Minutes": period.Minutes()}))
+	d.processUpdate(MonitorComponents, ProcessStart, nil)
+
+	m.watch(ctx, comps, period)
+
+	if failures := m.failures(comps); len(failures) > 0 {
+		err := d.cfg.Catalog().New(messages.MonitoringComponentsDegraded, messages.Args{"Count": len(failures)}).Wrap(failures)
+		d.processUpdate(MonitorComponents, ProcessExecutionFailure, err)
+		return err
+	}
+	d.processUpdate(MonitorComponents, ProcessFinished, nil)
+	return nil
+}
+
+//newComponentMonitor creates a monitor with the configured interval and restart threshold, or their defaults
+func newComponentMonitor(c *core) *componentMonitor {
+	cfg := c.cfg.Monitor
+	if cfg == nil {
+		cfg = &config.MonitorConfig{}
+	}
+	interval, threshold := cfg.Interval, cfg.RestartThreshold
+	if interval == 0 {
+		interval = defaultMonitorInterval
+	}
+	if threshold == 0 {
+		threshold = defaultMonitorRestartThreshold
+	}
+	return &componentMonitor{
+		core:             c,
+		interval:         interval,
+		restartThreshold: threshold,
+		restarts:         map[string]int32{},
+		degraded:         map[string]string{},
+	}
+}
+
+//watch checks the components every interval until the period is over or the context is cancelled
+func (m *componentMonitor) watch(ctx context.Context, comps []config.ComponentDefinition, period time.Duration) {
+	interval := m.interval
+	deadline := m.clock.Now().Add(period)
+	for {
+		m.check(ctx, comps)
+		remaining := deadline.Sub(m.clock.Now())
+		if remaining <= 0 {
+			return
+		}
+		if remaining < interval {
+			interval = remaining
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.clock.After(interval):
+		}
+	}
+}
+
+//failures returns a failure for every component which is degraded, in the order of the components
+func (m *componentMonitor) failures(comps []config.ComponentDefinition) installerrors.ComponentFailures {
+	var failures installerrors.ComponentFailures
+	for _, comp := range comps {
+		if reason, ok := m.degraded[comp.Name]; ok {
+			failures = append(failures, &installerrors.ErrComponentFailed{Component: comp.Name, Err: errors.New(reason)})
+		}
+	}
+	return failures
+}
+
+//check verifies the workloads of every component and sends updates for components whose health changed.
+//Errors of the API server are only logged, as they must not end the monitoring.
+func (m *componentMonitor) check(ctx context.Context, comps []config.ComponentDefinition) {
+	for _, comp := range comps {
+		reason, err := m.degradation(ctx, comp)
+		if err != nil {
+			m.cfg.Log.Warnf("Failed to check the workloads of component '%s': %v", comp.Name, err)
+			continue
+		}
+		_, wasDegraded := m.degraded[comp.Name]
+		kymaComp := components.KymaComponent{Name: comp.Name, Namespace: comp.Namespace}
+		switch {
+		case reason != "" && !wasDegraded:
+			m.degraded[comp.Name] = reason
+			m.cfg.Log.Warn(m.cfg.Catalog().Text(messages.MonitoringComponentDegraded, messages.Args{"Component": comp.Name, "Reason": reason}))
+			kymaComp.Status = components.StatusDegraded
+			kymaComp.Error = errors.New(reason)
+			m.sendUpdate(ProcessComponentDegraded, kymaComp)
+		case reason == "" && wasDegraded:
+			delete(m.degraded, comp.Name)
+			m.cfg.Log.Info(m.cfg.Catalog().Text(messages.MonitoringComponentRecovered, messages.Args{"Component": comp.Name}))
+			kymaComp.Status = components.StatusInstalled
+			m.sendUpdate(ProcessComponentRecovered, kymaComp)
+		case reason != "":
+			m.degraded[comp.Name] = reason
+		}
+	}
+}
+
+func (m *componentMonitor) sendUpdate(event ProcessEvent, comp components.KymaComponent) {
+	m.events.recordComponentDegraded(comp)
+	m.deliverUpdate(ProcessUpdate{
+		Event:     event,
+		Phase:     MonitorComponents,
+		Component: comp,
+	})
+}
+
+//degradation returns why the component is degraded, or an empty string if its workloads are healthy
+func (m *componentMonitor) degradation(ctx context.Context, comp config.ComponentDefinition) (string, error) {
+	workloads, err := m.workloads(ctx, comp)
+	if err != nil {
+		return "", err
+	}
+	var reasons []string
+	for _, w := range workloads {
+		if w.available < w.desired {
+			reasons = append(reasons, fmt.Sprintf("%s %s has %d of %d replicas available", w.kind, w.name, w.available, w.desired))
+		}
+		selector, err := metav1.LabelSelectorAsSelector(w.selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		podReasons, err := m.podDegradation(ctx, comp.Namespace, selector)
+		if err != nil {
+			return "", err
+		}
+		reasons = append(reasons, podReasons...)
+	}
+	return strings.Join(reasons, ", "), nil
+}
+
+//workloads returns the Deployments, StatefulSets, and DaemonSets of the Helm release of the component
+func (m *componentMonitor) workloads(ctx context.Context, comp config.ComponentDefinition) ([]workload, error) {
+	release := comp.Release()
+	ownedByRelease := func(meta metav1.ObjectMeta) bool {
+		return meta.Annotations[releaseNameAnnotation] == release
+	}
+	apps := m.kubeClient.AppsV1()
+	var workloads []workload
+
+	deployments, err := apps.Deployments(comp.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deployments.Items {
+		if !ownedByRelease(d.ObjectMeta) {
+			continue
+		}
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		workloads = append(workloads, workload{kind: "Deployment", name: d.Name, desired: desired, available: d.Status.AvailableReplicas, selector: d.Spec.Selector})
+	}
+
+	statefulSets, err := apps.StatefulSets(comp.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statefulSets.Items {
+		if !ownedByRelease(s.ObjectMeta) {
+			continue
+		}
+		desired := int32(1)
+		if s.Spec.Replicas != nil {
+			desired = *s.Spec.Replicas
+		}
+		workloads = append(workloads, workload{kind: "StatefulSet", name: s.Name, desired: desired, available: s.Status.ReadyReplicas, selector: s.Spec.Selector})
+	}
+
+	daemonSets, err := apps.DaemonSets(comp.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ds := range daemonSets.Items {
+		if !ownedByRelease(ds.ObjectMeta) {
+			continue
+		}
+		workloads = append(workloads, workload{kind: "DaemonSet", name: ds.Name, desired: ds.Status.DesiredNumberScheduled, available: ds.Status.NumberAvailable, selector: ds.Spec.Selector})
+	}
+	return workloads, nil
+}
+
+//podDegradation returns the containers of the selected Pods which are in CrashLoopBackOff or exceeded the restart threshold
+func (m *componentMonitor) podDegradation(ctx context.Context, namespace string, selector labels.Selector) ([]string, error) {
+	pods, err := m.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	var reasons []string
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			key := fmt.Sprintf("%s/%s", pod.UID, status.Name)
+			initial, ok := m.restarts[key]
+			if !ok {
+				m.restarts[key] = status.RestartCount
+				initial = status.RestartCount
+			}
+			switch {
+			case status.State.Waiting != nil && status.State.Waiting.Reason == crashLoopBackOff:
+				reasons = append(reasons, fmt.Sprintf("container %s of Pod %s is in %s", status.Name, pod.Name, crashLoopBackOff))
+			case status.RestartCount-initial >= m.restartThreshold:
+				reasons = append(reasons, fmt.Sprintf("container %s of Pod %s restarted %d times", status.Name, pod.Name, status.RestartCount-initial))
+			}
+		}
+	}
+	return reasons, nil
+}
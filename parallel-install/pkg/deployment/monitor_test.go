@@ -0,0 +1,133 @@
+package deployment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeployment_Monitor(t *testing.T) {
+
+	t.Run("Degraded component recovers", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(fixMonitoredDeployment(0))
+		d, clock, updates := newMonitoredDeployment(kubeClient)
+
+		result := make(chan error, 1)
+		go func() { result <- d.Monitor(context.Background()) }()
+
+		update := <-updates
+		require.Equal(t, ProcessComponentDegraded, update.Event)
+		require.Equal(t, MonitorComponents, update.Phase)
+		require.Equal(t, components.StatusDegraded, update.Component.Status)
+		require.EqualError(t, update.Component.Error, "Deployment function-controller has 0 of 1 replicas available")
+
+		_, err := kubeClient.AppsV1().Deployments("kyma-system").UpdateStatus(context.Background(), fixMonitoredDeployment(1), metav1.UpdateOptions{})
+		require.NoError(t, err)
+		advanceMonitor(t, clock, 1)
+
+		update = <-updates
+		require.Equal(t, ProcessComponentRecovered, update.Event)
+		require.Equal(t, components.StatusInstalled, update.Component.Status)
+
+		advanceMonitor(t, clock, 2)
+		advanceMonitor(t, clock, 3)
+		require.NoError(t, <-result)
+	})
+
+	t.Run("Crashlooping component is reported at the end", func(t *testing.T) {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "function-controller-1", Namespace: "kyma-system", UID: "1", Labels: map[string]string{"app": "function-controller"}},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{
+				Name:         "manager",
+				RestartCount: 4,
+				State:        v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}}},
+		}
+		kubeClient := fake.NewSimpleClientset(fixMonitoredDeployment(1), pod)
+		d, _, updates := newMonitoredDeployment(kubeClient)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := make(chan error, 1)
+		go func() { result <- d.Monitor(ctx) }()
+
+		update := <-updates
+		require.Equal(t, ProcessComponentDegraded, update.Event)
+		cancel()
+
+		err := <-result
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "1 component(s) are degraded")
+		var failure *installerrors.ErrComponentFailed
+		require.True(t, errors.As(err, &failure))
+		require.Equal(t, "serverless", failure.Component)
+		require.Contains(t, failure.Error(), "container manager of Pod function-controller-1 is in CrashLoopBackOff")
+	})
+
+	t.Run("Restarts below the threshold are tolerated", func(t *testing.T) {
+		m := &componentMonitor{restartThreshold: 3, restarts: map[string]int32{}, core: &core{kubeClient: fake.NewSimpleClientset(&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "function-controller-1", Namespace: "kyma-system", UID: "1", Labels: map[string]string{"app": "function-controller"}},
+			Status:     v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "manager", RestartCount: 10}}},
+		})}}
+		selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "function-controller"}})
+		require.NoError(t, err)
+
+		reasons, err := m.podDegradation(context.Background(), "kyma-system", selector)
+		require.NoError(t, err)
+		require.Empty(t, reasons) //restarts before the monitoring are ignored
+
+		m.restarts["1/manager"] = 7
+		reasons, err = m.podDegradation(context.Background(), "kyma-system", selector)
+		require.NoError(t, err)
+		require.Equal(t, []string{"container manager of Pod function-controller-1 restarted 3 times"}, reasons)
+	})
+}
+
+func newMonitoredDeployment(kubeClient *fake.Clientset) (*Deployment, *fakeClock, chan ProcessUpdate) {
+	updates := make(chan ProcessUpdate, 10)
+	cfg := &config.Config{
+		Log:           logger.NewLogger(true),
+		ComponentList: &config.ComponentList{Components: []config.ComponentDefinition{{Name: "serverless", Namespace: "kyma-system"}}},
+		Monitor:       &config.MonitorConfig{Period: 30 * time.Second, Interval: 10 * time.Second},
+	}
+	d := &Deployment{newCore(cfg, &OverridesBuilder{}, kubeClient, func(update ProcessUpdate) {
+		if update.IsComponentUpdate() {
+			updates <- update
+		}
+	})}
+	clock := newFakeClock()
+	d.clock = clock
+	return d, clock, updates
+}
+
+//advanceMonitor advances the clock by the monitoring interval once the monitor waits for the given check
+func advanceMonitor(t *testing.T, clock *fakeClock, check int) {
+	require.Eventually(t, func() bool { return len(clock.Requested()) >= check }, time.Second, time.Millisecond)
+	clock.Advance(10 * time.Second)
+}
+
+func fixMonitoredDeployment(available int32) *appsv1.Deployment {
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "function-controller",
+			Namespace:   "kyma-system",
+			Annotations: map[string]string{"meta.helm.sh/release-name": "serverless"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "function-controller"}},
+		},
+		Status: appsv1.DeploymentStatus{AvailableReplicas: available},
+	}
+}
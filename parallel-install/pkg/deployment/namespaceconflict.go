@@ -0,0 +1,109 @@
+package deployment
+
+import (
+	"context"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/preinstaller"
+	"github.com/pkg/errors"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//installationLabel marks namespaces which are created or adopted by Kyma
+const installationLabel = "kyma-project.io/installation"
+
+//resolveNamespaceConflicts finds the namespaces of the components which already exist, but are not owned by Kyma,
+//and applies the namespace conflict policy to them.
+//A namespace is owned by Kyma if it carries the Kyma installation label or the label of the preinstaller,
+//or if it contains a Kyma release of the configured tenant.
+func (d *Deployment) resolveNamespaceConflicts() error {
+	compList, err := d.componentList()
+	if err != nil {
+		return err
+	}
+	foreign, err := d.foreignNamespaces(compList)
+	if err != nil || len(foreign) == 0 {
+		return err
+	}
+
+	switch d.cfg.NamespaceConflicts {
+	case config.NamespaceConflictFail:
+		return &installerrors.ErrNamespaceConflict{Namespaces: foreign}
+	case config.NamespaceConflictSkip:
+		d.skippedNamespaces = map[string]bool{}
+		for _, namespace := range foreign {
+			d.skippedNamespaces[namespace] = true
+		}
+	case config.NamespaceConflictAdopt:
+		for _, namespace := range foreign {
+			d.cfg.Log.Warn(d.cfg.Catalog().Text(messages.NamespaceAdopted, messages.Args{"Namespace": namespace}))
+			if err := d.adoptNamespace(namespace); err != nil {
+				return errors.Wrapf(err, "Failed to adopt namespace '%s'", namespace)
+			}
+		}
+	default:
+		for _, namespace := range foreign {
+			d.cfg.Log.Warn(d.cfg.Catalog().Text(messages.NamespaceConflict, messages.Args{"Namespace": namespace}))
+		}
+	}
+	return nil
+}
+
+//foreignNamespaces returns the existing namespaces of the components which are not owned by Kyma, in the order of the component list
+func (d *Deployment) foreignNamespaces(compList *config.ComponentList) ([]string, error) {
+	var foreign []string
+	var releaseNamespaces map[string]bool
+	seen := map[string]bool{}
+	for _, comp := range append(append([]config.ComponentDefinition{}, compList.Prerequisites...), compList.Components...) {
+		if seen[comp.Namespace] {
+			continue
+		}
+		seen[comp.Namespace] = true
+
+		ns, err := d.kubeClient.CoreV1().Namespaces().Get(context.Background(), comp.Namespace, metav1.GetOptions{})
+		if apierr.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read namespace '%s'", comp.Namespace)
+		}
+		if _, ok := ns.Labels[installationLabel]; ok {
+			continue
+		}
+		if _, ok := ns.Labels[preinstaller.InstallationIDLabel]; ok {
+			continue
+		}
+
+		if releaseNamespaces == nil {
+			versions, err := d.metadataProvider().Versions()
+			if err != nil {
+				return nil, err
+			}
+			releaseNamespaces = map[string]bool{}
+			for _, release := range versions.InstalledComponents() {
+				releaseNamespaces[release.Namespace] = true
+			}
+		}
+		if !releaseNamespaces[comp.Namespace] {
+			foreign = append(foreign, comp.Namespace)
+		}
+	}
+	return foreign, nil
+}
+
+//adoptNamespace sets the Kyma installation label on the namespace
+func (d *Deployment) adoptNamespace(namespace string) error {
+	ns, err := d.kubeClient.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if ns.Labels == nil {
+		ns.Labels = map[string]string{}
+	}
+	ns.Labels[installationLabel] = ""
+	_, err = d.kubeClient.CoreV1().Namespaces().Update(context.Background(), ns, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,77 @@
+package deployment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/preinstaller"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeployment_ResolveNamespaceConflicts(t *testing.T) {
+	newDeployment := func(policy config.NamespaceConflictPolicy) (*Deployment, *fake.Clientset) {
+		kubeClient := fake.NewSimpleClientset(
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kyma-system", Labels: map[string]string{installationLabel: ""}}},
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system", Labels: map[string]string{preinstaller.InstallationIDLabel: "kyma"}}},
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"}},
+		)
+		cfg := &config.Config{
+			Log: logger.NewLogger(true),
+			ComponentList: &config.ComponentList{
+				Prerequisites: []config.ComponentDefinition{{Name: "istio", Namespace: "istio-system"}},
+				Components: []config.ComponentDefinition{
+					{Name: "serverless", Namespace: "kyma-system"},
+					{Name: "tenant-app", Namespace: "tenant-a"},
+					{Name: "eventing", Namespace: "kyma-integration"},
+				},
+			},
+			NamespaceConflicts: policy,
+		}
+		return &Deployment{newCore(cfg, &OverridesBuilder{}, kubeClient, nil)}, kubeClient
+	}
+
+	t.Run("Fail", func(t *testing.T) {
+		d, _ := newDeployment(config.NamespaceConflictFail)
+		err := d.resolveNamespaceConflicts()
+		var conflict *installerrors.ErrNamespaceConflict
+		require.True(t, errors.As(err, &conflict))
+		require.Equal(t, []string{"tenant-a"}, conflict.Namespaces)
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		d, _ := newDeployment(config.NamespaceConflictSkip)
+		require.NoError(t, d.resolveNamespaceConflicts())
+		require.Equal(t, map[string]bool{"tenant-a": true}, d.skippedNamespaces)
+
+		filtered, skipped, err := d.withoutSkippedComponents(Overrides{}, d.cfg.ComponentList)
+		require.NoError(t, err)
+		require.Len(t, filtered.Components, 2)
+		require.Len(t, skipped, 1)
+		require.Equal(t, "tenant-app", skipped[0].definition.Name)
+		require.Equal(t, "Skipping component 'tenant-app' because namespace 'tenant-a' is not owned by Kyma", skipped[0].reason)
+	})
+
+	t.Run("Adopt", func(t *testing.T) {
+		d, kubeClient := newDeployment(config.NamespaceConflictAdopt)
+		require.NoError(t, d.resolveNamespaceConflicts())
+		ns, err := kubeClient.CoreV1().Namespaces().Get(context.Background(), "tenant-a", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Contains(t, ns.Labels, installationLabel)
+	})
+
+	t.Run("Reuse", func(t *testing.T) {
+		d, kubeClient := newDeployment("")
+		require.NoError(t, d.resolveNamespaceConflicts())
+		require.Nil(t, d.skippedNamespaces)
+		ns, err := kubeClient.CoreV1().Namespaces().Get(context.Background(), "tenant-a", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotContains(t, ns.Labels, installationLabel)
+	})
+}
@@ -0,0 +1,210 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/watcher"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+//deleteNamespaceWithDeadline deletes the namespace and waits until it is removed.
+//If the namespace is still blocked when the deadline is reached, it is either force-finalized
+//or reported as stuck, depending on the configuration.
+//The Pods and the namespace are watched, so the deletion continues as soon as they are gone.
+func (i *Deletion) deleteNamespaceWithDeadline(ns string, errorCh chan<- error) error {
+	cfg := i.cfg.NamespaceDeletion
+	deadline := time.Now().Add(cfg.Timeout)
+
+	err := watcher.Until(watcher.Pods(i.kubeClient, ns), &v1.Pod{}, time.Until(deadline), func(objects []interface{}) (bool, error) {
+		pods := make([]v1.Pod, 0, len(objects))
+		for _, obj := range objects {
+			pods = append(pods, *obj.(*v1.Pod))
+		}
+		blocking, err := i.filterBlockingPods(pods)
+		if err != nil {
+			return false, err
+		}
+		return len(blocking) == 0, nil
+	})
+	if err != nil && err != wait.ErrWaitTimeout {
+		return err
+	}
+	if err == wait.ErrWaitTimeout {
+		if !cfg.ForceFinalize {
+			return i.stuckNamespaceError(ns)
+		}
+		i.cfg.Log.Infof("Namespace %s still has blocking Pods after %v: deleting it anyway", ns, cfg.Timeout)
+	}
+
+	i.removeNamespace(ns, errorCh)
+
+	err = watcher.Until(watcher.Namespace(i.kubeClient, ns), &v1.Namespace{}, remaining(deadline), func(objects []interface{}) (bool, error) {
+		return watcher.Find(objects, ns) == nil, nil
+	})
+	if err != wait.ErrWaitTimeout {
+		return err
+	}
+	if !cfg.ForceFinalize {
+		return i.stuckNamespaceError(ns)
+	}
+	return i.forceFinalizeNamespace(ns)
+}
+
+//remaining returns the time left until the deadline. It is never zero, so that the removal of the namespace is still watched briefly.
+func remaining(deadline time.Time) time.Duration {
+	if d := time.Until(deadline); d > 0 {
+		return d
+	}
+	return time.Millisecond
+}
+
+//blockingPods returns the Pods which prevent the deletion of the namespace according to the Pod check policy
+func (i *Deletion) blockingPods(ns string) ([]v1.Pod, error) {
+	policy := i.cfg.NamespacePodCheck
+	if policy.Skip {
+		return nil, nil
+	}
+	pods, err := i.kubeClient.CoreV1().Pods(ns).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return i.filterBlockingPods(pods.Items)
+}
+
+//filterBlockingPods returns the Pods which block the deletion according to the Pod check policy
+func (i *Deletion) filterBlockingPods(pods []v1.Pod) ([]v1.Pod, error) {
+	policy := i.cfg.NamespacePodCheck
+	if policy.Skip {
+		return nil, nil
+	}
+	cordoned := map[string]bool{}
+	var blocking []v1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		if policy.IgnoreNotRunning && !containersRunning(pod) {
+			continue
+		}
+		if policy.IgnoreDaemonSetPodsOnCordonedNodes && ownedByDaemonSet(pod) && pod.Spec.NodeName != "" {
+			isCordoned, ok := cordoned[pod.Spec.NodeName]
+			if !ok {
+				var err error
+				isCordoned, err = i.nodeCordoned(pod.Spec.NodeName)
+				if err != nil {
+					return nil, err
+				}
+				cordoned[pod.Spec.NodeName] = isCordoned
+			}
+			if isCordoned {
+				continue
+			}
+		}
+		blocking = append(blocking, pod)
+	}
+	return blocking, nil
+}
+
+//containersRunning returns false if the Pod is terminating or one of its containers is not running, e.g. in CrashLoopBackOff
+func containersRunning(pod v1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Running == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func ownedByDaemonSet(pod v1.Pod) bool {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Deletion) nodeCordoned(name string) (bool, error) {
+	node, err := i.kubeClient.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+	if apierr.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return node.Spec.Unschedulable, nil
+}
+
+//forceFinalizeNamespace removes the finalizers of the namespace so that Kubernetes removes it without waiting for its content
+func (i *Deletion) forceFinalizeNamespace(ns string) error {
+	namespace, err := i.kubeClient.CoreV1().Namespaces().Get(context.Background(), ns, metav1.GetOptions{})
+	if apierr.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	i.cfg.Log.Infof("Namespace %s is stuck (%s): removing its finalizers", ns, i.namespaceDiagnostics(namespace))
+	namespace.Spec.Finalizers = nil
+	if _, err := i.kubeClient.CoreV1().Namespaces().Finalize(context.Background(), namespace, metav1.UpdateOptions{}); err != nil && !apierr.IsNotFound(err) {
+		return errors.Wrapf(err, "Failed to finalize namespace %s", ns)
+	}
+	return nil
+}
+
+func (i *Deletion) stuckNamespaceError(ns string) error {
+	namespace, err := i.kubeClient.CoreV1().Namespaces().Get(context.Background(), ns, metav1.GetOptions{})
+	if apierr.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("Namespace %s could not be deleted within %v: %s", ns, i.cfg.NamespaceDeletion.Timeout, i.namespaceDiagnostics(namespace))
+}
+
+//namespaceDiagnostics describes what blocks the deletion of the namespace: blocking Pods, remaining content and finalizers
+func (i *Deletion) namespaceDiagnostics(namespace *v1.Namespace) string {
+	var causes []string
+
+	pods, err := i.blockingPods(namespace.Name)
+	if err != nil {
+		causes = append(causes, fmt.Sprintf("failed to list Pods: %s", err))
+	}
+	if len(pods) > 0 {
+		names := make([]string, 0, len(pods))
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		causes = append(causes, fmt.Sprintf("blocking Pods: %s", strings.Join(names, ", ")))
+	}
+
+	for _, condition := range namespace.Status.Conditions {
+		if condition.Status == v1.ConditionTrue {
+			causes = append(causes, fmt.Sprintf("%s: %s", condition.Type, condition.Message))
+		}
+	}
+
+	if len(namespace.Spec.Finalizers) > 0 {
+		finalizers := make([]string, 0, len(namespace.Spec.Finalizers))
+		for _, finalizer := range namespace.Spec.Finalizers {
+			finalizers = append(finalizers, string(finalizer))
+		}
+		causes = append(causes, fmt.Sprintf("finalizers: %s", strings.Join(finalizers, ", ")))
+	}
+
+	if len(causes) == 0 {
+		return fmt.Sprintf("namespace is in phase %s", namespace.Status.Phase)
+	}
+	return strings.Join(causes, "; ")
+}
@@ -0,0 +1,149 @@
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDeletion_DeleteNamespaceWithDeadline(t *testing.T) {
+
+	t.Run("Namespace is removed", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(newTerminatingNamespace("kyma-test"))
+		i := newDeletion(t, nil, kubeClient, nil)
+		i.cfg.NamespaceDeletion = &config.NamespaceDeletionConfig{Timeout: time.Second, PollInterval: 10 * time.Millisecond}
+
+		err := i.deleteKymaNamespaces([]string{"kyma-test"})
+		require.NoError(t, err)
+
+		ns, err := kubeClient.CoreV1().Namespaces().List(nil, metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Empty(t, ns.Items)
+	})
+
+	t.Run("Namespace with running Pod is reported as stuck", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(newTerminatingNamespace("kyma-test"), newRunningPod("kyma-test", "blocking-pod"))
+		i := newDeletion(t, nil, kubeClient, nil)
+		i.cfg.NamespaceDeletion = &config.NamespaceDeletionConfig{Timeout: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond}
+
+		err := i.deleteKymaNamespaces([]string{"kyma-test"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "blocking-pod")
+
+		ns, err := kubeClient.CoreV1().Namespaces().List(nil, metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, ns.Items, 1)
+	})
+
+	t.Run("Namespace stuck in termination is reported with its conditions", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(newTerminatingNamespace("kyma-test"))
+		keepNamespaces(kubeClient)
+		i := newDeletion(t, nil, kubeClient, nil)
+		i.cfg.NamespaceDeletion = &config.NamespaceDeletionConfig{Timeout: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond}
+
+		err := i.deleteKymaNamespaces([]string{"kyma-test"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Some content in the namespace has finalizers remaining")
+		require.Contains(t, err.Error(), "finalizers: kubernetes")
+	})
+
+	t.Run("Namespace stuck in termination is force-finalized", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(newTerminatingNamespace("kyma-test"), newRunningPod("kyma-test", "blocking-pod"))
+		keepNamespaces(kubeClient)
+		i := newDeletion(t, nil, kubeClient, nil)
+		i.cfg.NamespaceDeletion = &config.NamespaceDeletionConfig{Timeout: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond, ForceFinalize: true}
+
+		err := i.deleteKymaNamespaces([]string{"kyma-test"})
+		require.NoError(t, err)
+
+		var finalized bool
+		for _, action := range kubeClient.Actions() {
+			if action.GetResource().Resource == "namespaces" && action.GetSubresource() == "finalize" {
+				finalized = true
+				ns := action.(k8stesting.CreateAction).GetObject().(*v1.Namespace)
+				require.Empty(t, ns.Spec.Finalizers)
+			}
+		}
+		require.True(t, finalized)
+	})
+}
+
+func TestDeletion_BlockingPods(t *testing.T) {
+	crashing := newRunningPod("kyma-test", "crashing")
+	crashing.Status.ContainerStatuses = []v1.ContainerStatus{
+		{Name: "app", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+	}
+	terminating := newRunningPod("kyma-test", "terminating")
+	terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	completed := newRunningPod("kyma-test", "completed")
+	completed.Status.Phase = v1.PodSucceeded
+	evicted := newRunningPod("kyma-test", "evicted")
+	evicted.Status.Phase = v1.PodFailed
+	evicted.Status.Reason = "Evicted"
+	daemon := newRunningPod("kyma-test", "daemon")
+	daemon.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent"}}
+	daemon.Spec.NodeName = "cordoned"
+	running := newRunningPod("kyma-test", "running")
+	cordonedNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cordoned"}, Spec: v1.NodeSpec{Unschedulable: true}}
+
+	tests := []struct {
+		name     string
+		policy   config.PodCheckPolicy
+		expected []string
+	}{
+		{name: "Default policy blocks on every running Pod", expected: []string{"crashing", "terminating", "daemon", "running"}},
+		{name: "Not running Pods are ignored", policy: config.PodCheckPolicy{IgnoreNotRunning: true}, expected: []string{"daemon", "running"}},
+		{name: "DaemonSet Pods on cordoned nodes are ignored", policy: config.PodCheckPolicy{IgnoreDaemonSetPodsOnCordonedNodes: true}, expected: []string{"crashing", "terminating", "running"}},
+		{name: "Check is skipped", policy: config.PodCheckPolicy{Skip: true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(cordonedNode, crashing, terminating, completed, evicted, daemon, running)
+			i := newDeletion(t, nil, kubeClient, nil)
+			i.cfg.NamespacePodCheck = test.policy
+
+			pods, err := i.blockingPods("kyma-test")
+			require.NoError(t, err)
+			var names []string
+			for _, pod := range pods {
+				names = append(names, pod.Name)
+			}
+			require.ElementsMatch(t, test.expected, names)
+		})
+	}
+}
+
+//keepNamespaces simulates namespaces which stay in termination after they were deleted
+func keepNamespaces(kubeClient *fake.Clientset) {
+	kubeClient.PrependReactor("delete", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+}
+
+func newTerminatingNamespace(name string) *v1.Namespace {
+	return &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1.NamespaceSpec{Finalizers: []v1.FinalizerName{v1.FinalizerKubernetes}},
+		Status: v1.NamespaceStatus{
+			Phase: v1.NamespaceTerminating,
+			Conditions: []v1.NamespaceCondition{
+				{Type: v1.NamespaceFinalizersRemaining, Status: v1.ConditionTrue, Message: "Some content in the namespace has finalizers remaining"},
+				{Type: v1.NamespaceDeletionDiscoveryFailure, Status: v1.ConditionFalse, Message: "All resources successfully discovered"},
+			},
+		},
+	}
+}
+
+func newRunningPod(namespace, name string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
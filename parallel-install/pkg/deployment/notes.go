@@ -0,0 +1,49 @@
+package deployment
+
+import (
+	"sync"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+)
+
+//ComponentNotes are the post-installation instructions a component chart renders from its NOTES.txt,
+//such as access URLs and hints where to find credentials
+type ComponentNotes struct {
+	Component string
+	Namespace string
+	Notes     string
+}
+
+//componentNotes collects the notes of the installed components. It is safe for concurrent use.
+type componentNotes struct {
+	mu    sync.Mutex
+	notes []ComponentNotes
+}
+
+func (n *componentNotes) reset() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notes = nil
+}
+
+//record keeps the notes of an installed component, components without notes are ignored
+func (n *componentNotes) record(cmp components.KymaComponent) {
+	if cmp.Status != components.StatusInstalled || cmp.Notes == "" {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notes = append(n.notes, ComponentNotes{Component: cmp.Name, Namespace: cmp.Namespace, Notes: cmp.Notes})
+}
+
+func (n *componentNotes) list() []ComponentNotes {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]ComponentNotes{}, n.notes...)
+}
+
+//Notes returns the rendered notes of the components installed by the last deployment, in the order in which they were installed.
+//Components whose chart has no NOTES.txt are not listed. Only the Helm backend renders notes.
+func (d *Deployment) Notes() []ComponentNotes {
+	return d.notes.list()
+}
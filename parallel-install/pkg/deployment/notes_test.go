@@ -0,0 +1,63 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/engine"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+//notesHelmClient renders notes for the releases in the notes map
+type notesHelmClient struct {
+	mockHelmClient
+	notes map[string]string
+}
+
+func (c *notesHelmClient) Notes(namespace, name string) string {
+	return c.notes[name]
+}
+
+type notesProvider struct {
+	hc *notesHelmClient
+}
+
+func (p *notesProvider) GetComponents() []components.KymaComponent {
+	var comps []components.KymaComponent
+	for _, name := range []string{"console", "monitoring"} {
+		comps = append(comps, components.KymaComponent{
+			Name:            name,
+			Namespace:       "kyma-system",
+			OverridesGetter: func() map[string]interface{} { return nil },
+			HelmClient:      p.hc,
+			Log:             logger.NewLogger(true),
+		})
+	}
+	return comps
+}
+
+func TestDeployment_Notes(t *testing.T) {
+	inst := newDeployment(t, nil, fake.NewSimpleClientset())
+	inst.notes.record(components.KymaComponent{Name: "stale", Status: components.StatusInstalled, Notes: "From the last deployment"})
+
+	overridesProvider := &mockOverridesProvider{}
+	prerequisitesEng := engine.NewEngine(overridesProvider, &mockProvider{hc: &mockHelmClient{}}, engine.Config{
+		WorkersCount: 1,
+		Log:          logger.NewLogger(true),
+	})
+	componentsEng := engine.NewEngine(overridesProvider, &notesProvider{hc: &notesHelmClient{
+		notes: map[string]string{"console": "Open the console at https://console.kyma.example.com"},
+	}}, engine.Config{
+		WorkersCount: 2,
+		Log:          logger.NewLogger(true),
+	})
+
+	require.NoError(t, inst.startKymaDeployment(overridesProvider, prerequisitesEng, componentsEng))
+	require.Equal(t, []ComponentNotes{{
+		Component: "console",
+		Namespace: "kyma-system",
+		Notes:     "Open the console at https://console.kyma.example.com",
+	}}, inst.Notes())
+}
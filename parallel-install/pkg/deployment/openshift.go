@@ -0,0 +1,142 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/pkg/errors"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const openShiftSCCRoleName = "kyma-security-context-constraints"
+
+var (
+	defaultOpenShiftSCCs           = []string{"anyuid", "privileged"}
+	defaultOpenShiftSkipComponents = []string{"monitoring"}
+)
+
+//applyOpenShiftCompatibility removes the components which conflict with OpenShift and exposes Kyma with Routes
+func applyOpenShiftCompatibility(ob *OverridesBuilder, cfg *config.Config) {
+	if cfg.OpenShift == nil {
+		return
+	}
+	skip := cfg.OpenShift.SkipComponents
+	if skip == nil {
+		skip = defaultOpenShiftSkipComponents
+	}
+	for _, component := range skip {
+		cfg.Log.Infof("Skipping component '%s' which is incompatible with OpenShift", component)
+		cfg.ComponentList.Remove(component)
+	}
+
+	ob.addDefaults(map[string]interface{}{
+		"global": map[string]interface{}{
+			"openshift": map[string]interface{}{
+				"enabled": true,
+			},
+			"ingress": map[string]interface{}{
+				"route": map[string]interface{}{
+					"enabled": !cfg.OpenShift.UseIngress,
+				},
+			},
+		},
+	})
+}
+
+//grantSecurityContextConstraints allows the service accounts of all Kyma namespaces to use the configured security context constraints
+func (i *core) grantSecurityContextConstraints() error {
+	if i.cfg.OpenShift == nil {
+		return nil
+	}
+	sccs := i.cfg.OpenShift.SecurityContextConstraints
+	if len(sccs) == 0 {
+		sccs = defaultOpenShiftSCCs
+	}
+
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: openShiftSCCRoleName},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups:     []string{"security.openshift.io"},
+			Resources:     []string{"securitycontextconstraints"},
+			ResourceNames: sccs,
+			Verbs:         []string{"use"},
+		}},
+	}
+	var subjects []rbacv1.Subject
+	for _, ns := range i.kymaNamespaces() {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     rbacv1.GroupKind,
+			APIGroup: rbacv1.GroupName,
+			Name:     fmt.Sprintf("system:serviceaccounts:%s", ns),
+		})
+	}
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: openShiftSCCRoleName},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     openShiftSCCRoleName,
+		},
+		Subjects: subjects,
+	}
+
+	roles := i.kubeClient.RbacV1().ClusterRoles()
+	if _, err := roles.Create(context.Background(), role, metav1.CreateOptions{}); err != nil {
+		if !apierr.IsAlreadyExists(err) {
+			return errors.Wrap(err, "Failed to create the security context constraints role")
+		}
+		if _, err := roles.Update(context.Background(), role, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrap(err, "Failed to update the security context constraints role")
+		}
+	}
+	bindings := i.kubeClient.RbacV1().ClusterRoleBindings()
+	if _, err := bindings.Create(context.Background(), binding, metav1.CreateOptions{}); err != nil {
+		if !apierr.IsAlreadyExists(err) {
+			return errors.Wrap(err, "Failed to bind the security context constraints role")
+		}
+		if _, err := bindings.Update(context.Background(), binding, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrap(err, "Failed to update the security context constraints role binding")
+		}
+	}
+	i.cfg.Log.Infof("Granted security context constraints %v to the service accounts of %d Kyma namespaces", sccs, len(subjects))
+	return nil
+}
+
+//revokeSecurityContextConstraints removes the role and binding created by grantSecurityContextConstraints
+func (i *core) revokeSecurityContextConstraints() error {
+	if i.cfg.OpenShift == nil {
+		return nil
+	}
+	err := i.kubeClient.RbacV1().ClusterRoleBindings().Delete(context.Background(), openShiftSCCRoleName, metav1.DeleteOptions{})
+	if err != nil && !apierr.IsNotFound(err) {
+		return err
+	}
+	err = i.kubeClient.RbacV1().ClusterRoles().Delete(context.Background(), openShiftSCCRoleName, metav1.DeleteOptions{})
+	if err != nil && !apierr.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+//kymaNamespaces returns the sorted namespaces of all prerequisites and components
+func (i *core) kymaNamespaces() []string {
+	compList, err := i.componentList()
+	if err != nil {
+		//templates are validated with the config, fall back to the namespaces of the component list
+		compList = i.cfg.ComponentList
+	}
+	unique := map[string]bool{"kyma-installer": true}
+	for _, comp := range append(append([]config.ComponentDefinition{}, compList.Prerequisites...), compList.Components...) {
+		unique[comp.Namespace] = true
+	}
+	namespaces := make([]string, 0, len(unique))
+	for ns := range unique {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
@@ -0,0 +1,95 @@
+package deployment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ApplyOpenShiftCompatibility(t *testing.T) {
+
+	t.Run("Incompatible components are skipped and Routes are enabled", func(t *testing.T) {
+		compList, err := config.NewComponentList("../test/data/componentlist.yaml")
+		require.NoError(t, err)
+		cfg := &config.Config{
+			Log:           logger.NewLogger(true),
+			ComponentList: compList,
+			OpenShift:     &config.OpenShiftConfig{SkipComponents: []string{"comp2"}},
+		}
+		builder := OverridesBuilder{}
+
+		applyOpenShiftCompatibility(&builder, cfg)
+
+		for _, comp := range cfg.ComponentList.Components {
+			require.NotEqual(t, "comp2", comp.Name)
+		}
+		require.Len(t, cfg.ComponentList.Components, 2)
+
+		overrides, err := builder.Raw()
+		require.NoError(t, err)
+		enabled, ok := overrides.Find("global.ingress.route.enabled")
+		require.True(t, ok)
+		require.Equal(t, true, enabled)
+	})
+
+	t.Run("Disabled compatibility mode changes nothing", func(t *testing.T) {
+		compList, err := config.NewComponentList("../test/data/componentlist.yaml")
+		require.NoError(t, err)
+		cfg := &config.Config{Log: logger.NewLogger(true), ComponentList: compList}
+		builder := OverridesBuilder{}
+
+		applyOpenShiftCompatibility(&builder, cfg)
+
+		require.Len(t, cfg.ComponentList.Components, 3)
+		require.Empty(t, builder.defaults)
+	})
+}
+
+func Test_SecurityContextConstraints(t *testing.T) {
+	compList, err := config.NewComponentList("../test/data/componentlist.yaml")
+	require.NoError(t, err)
+	cfg := &config.Config{
+		Log:           logger.NewLogger(true),
+		ComponentList: compList,
+		OpenShift:     &config.OpenShiftConfig{},
+	}
+	kubeClient := fake.NewSimpleClientset()
+	c := newCore(cfg, &OverridesBuilder{}, kubeClient, nil)
+
+	t.Run("Grant is idempotent", func(t *testing.T) {
+		require.NoError(t, c.grantSecurityContextConstraints())
+		require.NoError(t, c.grantSecurityContextConstraints())
+
+		role, err := kubeClient.RbacV1().ClusterRoles().Get(context.Background(), openShiftSCCRoleName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"anyuid", "privileged"}, role.Rules[0].ResourceNames)
+
+		binding, err := kubeClient.RbacV1().ClusterRoleBindings().Get(context.Background(), openShiftSCCRoleName, metav1.GetOptions{})
+		require.NoError(t, err)
+		var groups []string
+		for _, subject := range binding.Subjects {
+			groups = append(groups, subject.Name)
+		}
+		require.Equal(t, []string{
+			"system:serviceaccounts:compns2",
+			"system:serviceaccounts:kyma-installer",
+			"system:serviceaccounts:prereqns1",
+			"system:serviceaccounts:testns",
+		}, groups)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		require.NoError(t, c.revokeSecurityContextConstraints())
+
+		_, err := kubeClient.RbacV1().ClusterRoles().Get(context.Background(), openShiftSCCRoleName, metav1.GetOptions{})
+		require.True(t, apierr.IsNotFound(err))
+		_, err = kubeClient.RbacV1().ClusterRoleBindings().Get(context.Background(), openShiftSCCRoleName, metav1.GetOptions{})
+		require.True(t, apierr.IsNotFound(err))
+	})
+}
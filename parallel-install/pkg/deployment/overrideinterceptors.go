@@ -6,10 +6,11 @@ import (
 	"encoding/base64"
 	"fmt"
 	"strings"
-	"time"
 
-	"github.com/avast/retry-go"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
 	"github.com/pkg/errors"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -43,16 +44,22 @@ type DomainNameOverrideInterceptor struct {
 	kubeClient     kubernetes.Interface
 	log            logger.Interface
 	isLocalCluster func() (bool, error) // Returns true if we're on a local cluster like k3s
+	detectors      []DomainDetector     // Asked in order if the cluster is no Gardener cluster
+	detected       *DetectedDomain
+	retryPolicy    retry.Policy
 }
 
-func NewDomainNameOverrideInterceptor(kubeClient kubernetes.Interface, log logger.Interface) *DomainNameOverrideInterceptor {
-	return &DomainNameOverrideInterceptor{
-		kubeClient: kubeClient,
-		log:        log,
-		isLocalCluster: func() (bool, error) {
-			return isK3dCluster(kubeClient)
-		},
+func NewDomainNameOverrideInterceptor(kubeClient kubernetes.Interface, log logger.Interface, detectors ...DomainDetector) *DomainNameOverrideInterceptor {
+	res := &DomainNameOverrideInterceptor{
+		kubeClient:  kubeClient,
+		log:         log,
+		detectors:   detectors,
+		retryPolicy: retry.Default(),
 	}
+	res.isLocalCluster = func() (bool, error) {
+		return isK3dCluster(kubeClient, res.retryPolicy)
+	}
+	return res
 }
 
 func (i *DomainNameOverrideInterceptor) String(value interface{}, key string) string {
@@ -61,12 +68,13 @@ func (i *DomainNameOverrideInterceptor) String(value interface{}, key string) st
 
 func (i *DomainNameOverrideInterceptor) Intercept(value interface{}, key string) (interface{}, error) {
 	// On gardener, domain provided by user should be ignored
-	domainName, err := findGardenerDomain(i.kubeClient)
+	domainName, err := findGardenerDomain(i.kubeClient, i.retryPolicy)
 	if err != nil {
 		return nil, err
 	}
 
 	if domainName != "" {
+		i.detected = &DetectedDomain{Domain: domainName, Source: DomainSourceGardener}
 		return domainName, nil
 	}
 
@@ -75,34 +83,54 @@ func (i *DomainNameOverrideInterceptor) Intercept(value interface{}, key string)
 }
 
 func (i *DomainNameOverrideInterceptor) Undefined(overrides map[string]interface{}, key string) error {
-	domain, err := i.getDomainName()
+	detected, err := i.detectDomain()
 	if err != nil {
 		return err
 	}
+	if i.log != nil && (i.detected == nil || i.detected.Domain != detected.Domain) {
+		i.log.Infof("Detected domain %s", detected)
+	}
+	i.detected = detected
 
-	return NewFallbackOverrideInterceptor(domain).Undefined(overrides, key)
+	return NewFallbackOverrideInterceptor(detected.Domain).Undefined(overrides, key)
 }
 
-func (i *DomainNameOverrideInterceptor) getDomainName() (domainName string, err error) {
+// Detected returns the domain resolved by the interceptor, or nil if the domain provided by the user was used
+func (i *DomainNameOverrideInterceptor) Detected() *DetectedDomain {
+	return i.detected
+}
+
+func (i *DomainNameOverrideInterceptor) detectDomain() (*DetectedDomain, error) {
 
 	// On gardener always return gardener domain
-	domainName, err = findGardenerDomain(i.kubeClient)
+	domainName, err := findGardenerDomain(i.kubeClient, i.retryPolicy)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if domainName != "" {
-		return domainName, nil
+		return &DetectedDomain{Domain: domainName, Source: DomainSourceGardener}, nil
 	}
 
-	// On local k3s cluster return local development domain
+	// On local k3s cluster return local development domain, the detectors could not tell it from a remote cluster
 	domainName, err = i.findLocalDomain()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if domainName != "" {
-		return domainName, nil
+		return &DetectedDomain{Domain: domainName, Source: DomainSourceLocal}, nil
+	}
+
+	// Ask the pluggable detectors, e.g. for a wildcard DNS domain of the cluster IP
+	for _, detector := range i.detectors {
+		detected, err := detector.Detect()
+		if err != nil {
+			return nil, err
+		}
+		if detected != nil {
+			return detected, nil
+		}
 	}
-	return defaultRemoteKymaDomain, nil
+	return &DetectedDomain{Domain: defaultRemoteKymaDomain, Source: DomainSourceDefault}, nil
 }
 
 func (i *DomainNameOverrideInterceptor) findLocalDomain() (domainName string, err error) {
@@ -127,20 +155,24 @@ type CertificateOverrideInterceptor struct {
 	tlsKeyEnc         string
 	isLocalCluster    func() (bool, error)
 	isGardenerCluster func() (bool, error)
+	retryPolicy       retry.Policy
+	//fips rejects the built-in default certificates and certificates with parameters which are not approved
+	fips bool
 }
 
 func NewCertificateOverrideInterceptor(tlsCrtOverrideKey, tlsKeyOverrideKey string, kubeClient kubernetes.Interface) *CertificateOverrideInterceptor {
 	res := &CertificateOverrideInterceptor{
 		tlsCrtOverrideKey: tlsCrtOverrideKey,
 		tlsKeyOverrideKey: tlsKeyOverrideKey,
+		retryPolicy:       retry.Default(),
 	}
 
 	res.isLocalCluster = func() (bool, error) {
-		return isK3dCluster(kubeClient)
+		return isK3dCluster(kubeClient, res.retryPolicy)
 	}
 
 	res.isGardenerCluster = func() (bool, error) {
-		gardenerDomain, err := findGardenerDomain(kubeClient)
+		gardenerDomain, err := findGardenerDomain(kubeClient, res.retryPolicy)
 		if err != nil {
 			return false, err
 		}
@@ -184,6 +216,13 @@ func (i *CertificateOverrideInterceptor) Undefined(overrides map[string]interfac
 		return nil
 	}
 
+	if i.fips {
+		return &installerrors.ErrNotFIPSCompliant{
+			Source: key,
+			Reason: "the built-in default certificate is not allowed, provide a certificate with the TLS config or the overrides",
+		}
+	}
+
 	isLocalCluster, err := i.isLocalCluster()
 	if err != nil {
 		return err
@@ -236,10 +275,70 @@ func (i *CertificateOverrideInterceptor) validate() error {
 			return errors.Wrap(err,
 				fmt.Sprintf("Provided TLS certificate (passed in keys '%s' and '%s') is invalid", i.tlsCrtOverrideKey, i.tlsKeyOverrideKey))
 		}
+		if i.fips {
+			return config.CheckFIPSCertificate(i.tlsCrtOverrideKey, crt)
+		}
 	}
 	return nil
 }
 
+// CustomDomainOverrideInterceptor injects the custom domain and certificate defined in the installation config.
+// Configured values take precedence over overrides and over the domain detected on the cluster.
+type CustomDomainOverrideInterceptor struct {
+	value  string
+	masked bool
+}
+
+func (i *CustomDomainOverrideInterceptor) String(value interface{}, key string) string {
+	if i.masked {
+		return "<masked>"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func (i *CustomDomainOverrideInterceptor) Intercept(value interface{}, key string) (interface{}, error) {
+	return i.value, nil
+}
+
+func (i *CustomDomainOverrideInterceptor) Undefined(overrides map[string]interface{}, key string) error {
+	return NewFallbackOverrideInterceptor(i.value).Undefined(overrides, key)
+}
+
+// NewCustomDomainOverrideInterceptor creates an interceptor for the custom domain name
+func NewCustomDomainOverrideInterceptor(domainName string) *CustomDomainOverrideInterceptor {
+	return &CustomDomainOverrideInterceptor{value: domainName}
+}
+
+// NewCustomCertificateOverrideInterceptor creates an interceptor for the PEM encoded certificate or key of the custom domain
+func NewCustomCertificateOverrideInterceptor(pem []byte) *CustomDomainOverrideInterceptor {
+	return &CustomDomainOverrideInterceptor{
+		value:  base64.StdEncoding.EncodeToString(pem),
+		masked: true,
+	}
+}
+
+// GeneratedSecretOverrideInterceptor injects a generated secret value if the override is not defined by the user
+type GeneratedSecretOverrideInterceptor struct {
+	value string
+}
+
+func (i *GeneratedSecretOverrideInterceptor) String(value interface{}, key string) string {
+	return "<masked>"
+}
+
+func (i *GeneratedSecretOverrideInterceptor) Intercept(value interface{}, key string) (interface{}, error) {
+	return value, nil
+}
+
+func (i *GeneratedSecretOverrideInterceptor) Undefined(overrides map[string]interface{}, key string) error {
+	return NewFallbackOverrideInterceptor(i.value).Undefined(overrides, key)
+}
+
+// NewGeneratedSecretOverrideInterceptor creates an interceptor for a value of a generated secret
+func NewGeneratedSecretOverrideInterceptor(value string) *GeneratedSecretOverrideInterceptor {
+	return &GeneratedSecretOverrideInterceptor{value: value}
+}
+
 // FallbackOverrideInterceptor sets a default value for an undefined overwrite
 type FallbackOverrideInterceptor struct {
 	fallback interface{}
@@ -312,8 +411,8 @@ func NewInstallLegacyCRDsInterceptor() *InstallLegacyCRDsInterceptor {
 	return &InstallLegacyCRDsInterceptor{}
 }
 
-func findGardenerDomain(kubeClient kubernetes.Interface) (domainName string, err error) {
-	err = retry.Do(func() error {
+func findGardenerDomain(kubeClient kubernetes.Interface, retryPolicy retry.Policy) (domainName string, err error) {
+	err = retryPolicy.Do(func() error {
 		configMap, err := kubeClient.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), "shoot-info", metav1.GetOptions{})
 
 		if err != nil {
@@ -329,7 +428,7 @@ func findGardenerDomain(kubeClient kubernetes.Interface) (domainName string, err
 		}
 
 		return nil
-	}, defaultRetryOptions()...)
+	})
 
 	if err != nil {
 		return "", err
@@ -360,21 +459,16 @@ func NewDisableKCProxyInterceptor() *DisableKCProxyInterceptor {
 	return &DisableKCProxyInterceptor{}
 }
 
-func defaultRetryOptions() []retry.Option {
-	return []retry.Option{
-		retry.Delay(2 * time.Second),
-		retry.Attempts(3),
-		retry.DelayType(retry.FixedDelay),
-	}
-}
 
 type RegistryDisableInterceptor struct {
-	kubeClient kubernetes.Interface
+	kubeClient  kubernetes.Interface
+	retryPolicy retry.Policy
 }
 
 func NewRegistryDisableInterceptor(kubeClient kubernetes.Interface) *RegistryDisableInterceptor {
 	return &RegistryDisableInterceptor{
-		kubeClient: kubeClient,
+		kubeClient:  kubeClient,
+		retryPolicy: retry.Default(),
 	}
 }
 func (i *RegistryDisableInterceptor) String(value interface{}, key string) string {
@@ -386,7 +480,7 @@ func (i *RegistryDisableInterceptor) String(value interface{}, key string) strin
 }
 
 func (i *RegistryDisableInterceptor) Intercept(value interface{}, key string) (interface{}, error) {
-	k3dCluster, err := isK3dCluster(i.kubeClient)
+	k3dCluster, err := isK3dCluster(i.kubeClient, i.retryPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -397,7 +491,7 @@ func (i *RegistryDisableInterceptor) Intercept(value interface{}, key string) (i
 }
 
 func (i *RegistryDisableInterceptor) Undefined(overrides map[string]interface{}, key string) error {
-	k3dCluster, err := isK3dCluster(i.kubeClient)
+	k3dCluster, err := isK3dCluster(i.kubeClient, i.retryPolicy)
 	if err != nil {
 		return err
 	}
@@ -408,12 +502,14 @@ func (i *RegistryDisableInterceptor) Undefined(overrides map[string]interface{},
 }
 
 type RegistryInterceptor struct {
-	kubeClient kubernetes.Interface
+	kubeClient  kubernetes.Interface
+	retryPolicy retry.Policy
 }
 
 func NewRegistryInterceptor(kubeClient kubernetes.Interface) *RegistryInterceptor {
 	return &RegistryInterceptor{
-		kubeClient: kubeClient,
+		kubeClient:  kubeClient,
+		retryPolicy: retry.Default(),
 	}
 }
 
@@ -426,12 +522,12 @@ func (i *RegistryInterceptor) String(value interface{}, key string) string {
 }
 
 func (i *RegistryInterceptor) Intercept(value interface{}, key string) (interface{}, error) {
-	k3dCluster, err := isK3dCluster(i.kubeClient)
+	k3dCluster, err := isK3dCluster(i.kubeClient, i.retryPolicy)
 	if err != nil {
 		return nil, err
 	}
 	if k3dCluster {
-		k3dClusterName, err := getK3dClusterName(i.kubeClient)
+		k3dClusterName, err := getK3dClusterName(i.kubeClient, i.retryPolicy)
 		if err != nil {
 			return nil, err
 		}
@@ -441,12 +537,12 @@ func (i *RegistryInterceptor) Intercept(value interface{}, key string) (interfac
 }
 
 func (i *RegistryInterceptor) Undefined(overrides map[string]interface{}, key string) error {
-	k3dCluster, err := isK3dCluster(i.kubeClient)
+	k3dCluster, err := isK3dCluster(i.kubeClient, i.retryPolicy)
 	if err != nil {
 		return err
 	}
 	if k3dCluster {
-		k3dClusterName, err := getK3dClusterName(i.kubeClient)
+		k3dClusterName, err := getK3dClusterName(i.kubeClient, i.retryPolicy)
 		if err != nil {
 			return err
 		}
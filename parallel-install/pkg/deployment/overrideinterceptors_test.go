@@ -1,12 +1,19 @@
 package deployment
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"strings"
 	"testing"
 
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/secrets"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 	v1 "k8s.io/api/core/v1"
@@ -500,6 +507,50 @@ func Test_CertificateOverridesInterception(t *testing.T) {
 		require.Equal(t, getOverride(overrides.Map(), "global.tlsKey"), testFakeKey)
 	})
 
+	t.Run("test default cert is rejected in FIPS mode", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		interceptor := NewCertificateOverrideInterceptor("global.tlsCrt", "global.tlsKey", kubeClient)
+		interceptor.isLocalCluster = isLocalClusterFunc(true)
+		interceptor.fips = true
+
+		ob := OverridesBuilder{}
+
+		ob.AddInterceptor([]string{"global.tlsCrt", "global.tlsKey"}, interceptor)
+
+		// when
+		_, err := ob.Build()
+
+		// then
+		var notCompliant *installerrors.ErrNotFIPSCompliant
+		require.True(t, errors.As(err, &notCompliant))
+	})
+
+	t.Run("test user-provided cert is preserved in FIPS mode", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		interceptor := NewCertificateOverrideInterceptor("global.tlsCrt", "global.tlsKey", kubeClient)
+		interceptor.isLocalCluster = isLocalClusterFunc(false)
+		interceptor.fips = true
+
+		tlsOverrides := make(map[string]interface{})
+		tlsOverrides["tlsCrt"] = testFakeCrt
+		tlsOverrides["tlsKey"] = testFakeKey
+		ob := OverridesBuilder{}
+		err := ob.AddOverrides("global", tlsOverrides)
+		require.NoError(t, err)
+
+		ob.AddInterceptor([]string{"global.tlsCrt", "global.tlsKey"}, interceptor)
+
+		// when
+		overrides, err := ob.Build()
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, getOverride(overrides.Map(), "global.tlsCrt"), testFakeCrt)
+		require.Equal(t, getOverride(overrides.Map(), "global.tlsKey"), testFakeKey)
+	})
+
 	t.Run("test invalid crt key pair", func(t *testing.T) {
 		// given
 		kubeClient := fake.NewSimpleClientset()
@@ -547,6 +598,61 @@ func Test_CertificateOverridesInterception(t *testing.T) {
 	})
 }
 
+func Test_CustomDomainOverrideInterception(t *testing.T) {
+	gardenerCM := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "shoot-info", Namespace: "kube-system"},
+		Data:       map[string]string{"domain": "gardener.domain"},
+	}
+
+	t.Run("test custom domain takes precedence over user-provided and gardener domain", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(gardenerCM)
+		ob := OverridesBuilder{}
+		err := ob.AddOverrides("global", map[string]interface{}{"domainName": "user.domain"})
+		require.NoError(t, err)
+		registerOverridesInterceptors(&ob, kubeClient, logger.NewLogger(true), retry.Default(), false)
+		cfg := &config.Config{Domain: "custom.domain"}
+
+		// when
+		err = registerCustomDomainInterceptors(&ob, cfg, kubeClient)
+		require.NoError(t, err)
+		overrides, err := ob.Build()
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "custom.domain", getOverride(overrides.Map(), "global.domainName"))
+		require.Equal(t, "custom.domain", getOverride(overrides.Map(), "global.ingress.domainName"))
+	})
+
+	t.Run("test custom certificate is masked", func(t *testing.T) {
+		interceptor := NewCustomCertificateOverrideInterceptor([]byte("crt"))
+		require.Equal(t, "<masked>", interceptor.String("crt", "global.tlsCrt"))
+
+		value, err := interceptor.Intercept("", "global.tlsCrt")
+		require.NoError(t, err)
+		require.Equal(t, "Y3J0", value)
+	})
+
+	t.Run("test custom certificate must cover the domain", func(t *testing.T) {
+		// given
+		crt, err := base64.StdEncoding.DecodeString(defaultRemoteTLSCrtEnc)
+		require.NoError(t, err)
+		key, err := base64.StdEncoding.DecodeString(defaultRemoteTLSKeyEnc)
+		require.NoError(t, err)
+		cfg := &config.Config{
+			Domain: "custom.domain",
+			TLS:    &config.TLSConfig{Cert: string(crt), Key: string(key)},
+		}
+
+		// when
+		err = registerCustomDomainInterceptors(&OverridesBuilder{}, cfg, fake.NewSimpleClientset())
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not cover")
+	})
+}
+
 func Test_RegistryEnableOverrideInterception(t *testing.T) {
 	k3dNode := fakeK3dNode()
 	generalNode := fakeNode()
@@ -604,7 +710,7 @@ func Test_RegistryOverridesInterception(t *testing.T) {
 		kubeClient := fake.NewSimpleClientset(k3dNode)
 
 		// when
-		clusterName, err := getK3dClusterName(kubeClient)
+		clusterName, err := getK3dClusterName(kubeClient, retry.Default())
 
 		// then
 		require.NoError(t, err)
@@ -745,3 +851,36 @@ func extractKeys(overrides map[string]interface{}) []string {
 
 	return keys
 }
+
+func Test_GeneratedSecretOverrideInterception(t *testing.T) {
+	t.Run("test generated secret is exposed unless defined by the user", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		ob := OverridesBuilder{}
+		err := ob.AddOverrides("global", map[string]interface{}{"user": map[string]interface{}{"password": "user-password"}})
+		require.NoError(t, err)
+		err = ob.AddGeneratedSecret(secrets.Spec{Name: "generated", Kind: secrets.Password, Overrides: map[string]string{"password": "global.generated.password"}})
+		require.NoError(t, err)
+		err = ob.AddGeneratedSecret(secrets.Spec{Name: "user", Kind: secrets.Password, Overrides: map[string]string{"password": "global.user.password"}})
+		require.NoError(t, err)
+
+		// when
+		err = registerGeneratedSecretInterceptors(&ob, kubeClient, &config.Config{Log: logger.NewLogger(true)})
+		require.NoError(t, err)
+		overrides, err := ob.Build()
+
+		// then
+		require.NoError(t, err)
+		secret, err := kubeClient.CoreV1().Secrets("kyma-installer").Get(context.Background(), "generated", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, string(secret.Data["password"]), getOverride(overrides.Map(), "global.generated.password"))
+		require.Equal(t, "user-password", getOverride(overrides.Map(), "global.user.password"))
+		require.NotContains(t, overrides.String(), string(secret.Data["password"]))
+	})
+
+	t.Run("test invalid spec", func(t *testing.T) {
+		ob := OverridesBuilder{}
+		err := ob.AddGeneratedSecret(secrets.Spec{Name: "generated", Kind: secrets.Password, Overrides: map[string]string{"tls.crt": "global.tlsCrt"}})
+		require.Error(t, err)
+	})
+}
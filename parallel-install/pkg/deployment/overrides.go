@@ -9,7 +9,11 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/imdario/mergo"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides/typed"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/secrets"
 	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/kubernetes"
 )
 
 var (
@@ -25,9 +29,12 @@ const (
 
 // Overrides manages override merges
 type OverridesBuilder struct {
-	files        []string
-	overrides    []map[string]interface{}
-	interceptors map[string]OverrideInterceptor
+	files           []string
+	overrides       []map[string]interface{}
+	defaults        []map[string]interface{}
+	interceptors    map[string]OverrideInterceptor
+	domainDetectors []func(kubeClient kubernetes.Interface) DomainDetector
+	secretSpecs     []secrets.Spec
 }
 
 // AddFile adds overrides defined in a file to the builder
@@ -42,19 +49,74 @@ func (ob *OverridesBuilder) AddFile(file string) error {
 }
 
 // AddOverrides adds overrides for a chart to the builder
-func (ob *OverridesBuilder) AddOverrides(chart string, overrides map[string]interface{}) error {
+func (ob *OverridesBuilder) AddOverrides(chart string, values map[string]interface{}) error {
 	if chart == "" {
 		return fmt.Errorf("Chart name cannot be empty when adding overrides")
 	}
-	if len(overrides) < 1 {
+	if chart == overrides.ComponentsKey {
+		return fmt.Errorf("Chart name '%s' is reserved for overrides scoped to a component: use AddComponentOverrides", chart)
+	}
+	if len(values) < 1 {
 		return fmt.Errorf("Empty overrides map provided for chart '%s'", chart)
 	}
 	overridesMap := make(map[string]interface{})
-	overridesMap[chart] = overrides
+	overridesMap[chart] = values
 	ob.overrides = append(ob.overrides, overridesMap)
 	return nil
 }
 
+// AddComponentOverrides adds overrides which are scoped to a single component. They take precedence over the global
+// overrides and the overrides added for the chart of the component, and can also override global values for this component only.
+func (ob *OverridesBuilder) AddComponentOverrides(component string, values map[string]interface{}) error {
+	if component == "" {
+		return fmt.Errorf("Component name cannot be empty when adding overrides")
+	}
+	if len(values) < 1 {
+		return fmt.Errorf("Empty overrides map provided for component '%s'", component)
+	}
+	ob.overrides = append(ob.overrides, map[string]interface{}{
+		overrides.ComponentsKey: map[string]interface{}{component: values},
+	})
+	return nil
+}
+
+// AddTyped validates typed overrides, such as typed.Istio, and adds them to the builder
+func (ob *OverridesBuilder) AddTyped(typedOverrides typed.Overrides) error {
+	if typedOverrides == nil {
+		return fmt.Errorf("Typed overrides cannot be nil")
+	}
+	if err := typedOverrides.Validate(); err != nil {
+		return errors.Wrap(err, "Invalid typed overrides")
+	}
+	for chart, values := range typedOverrides.Overrides() {
+		valuesMap, ok := values.(map[string]interface{})
+		if !ok || len(valuesMap) == 0 {
+			continue
+		}
+		if err := ob.AddOverrides(chart, valuesMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddGeneratedSecret declares a secret which is generated when the deployment is created and exposed as overrides.
+// Existing secrets are reused. Overrides provided by the user take precedence over the generated values.
+func (ob *OverridesBuilder) AddGeneratedSecret(spec secrets.Spec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	ob.secretSpecs = append(ob.secretSpecs, spec)
+	return nil
+}
+
+// addDefaults adds overrides per chart which are overwritten by any other source
+func (ob *OverridesBuilder) addDefaults(overrides map[string]interface{}) {
+	if len(overrides) > 0 {
+		ob.defaults = append(ob.defaults, overrides)
+	}
+}
+
 // AddInterceptor registers an interceptor for particular override keys
 func (ob *OverridesBuilder) AddInterceptor(overrideKeys []string, interceptor OverrideInterceptor) {
 	if ob.interceptors == nil {
@@ -65,6 +127,20 @@ func (ob *OverridesBuilder) AddInterceptor(overrideKeys []string, interceptor Ov
 	}
 }
 
+// AddDomainDetector registers a detector for the domain name, which is used if no domain name is defined in the overrides.
+// The factory receives the client of the cluster Kyma gets deployed on. Detectors are asked in the order they were added.
+func (ob *OverridesBuilder) AddDomainDetector(factory func(kubeClient kubernetes.Interface) DomainDetector) {
+	ob.domainDetectors = append(ob.domainDetectors, factory)
+}
+
+// DetectedDomain returns the domain name resolved for the cluster, or nil if the overrides were not built yet or defined the domain name
+func (ob *OverridesBuilder) DetectedDomain() *DetectedDomain {
+	if interceptor, ok := ob.interceptors["global.domainName"].(*DomainNameOverrideInterceptor); ok {
+		return interceptor.Detected()
+	}
+	return nil
+}
+
 // Build an overrides object merging all provided sources and applying interceptors
 // WARNING: call this function sparingly, it runs all interceptors, potentially incurring heavy computations.
 func (ob *OverridesBuilder) Build() (Overrides, error) {
@@ -95,6 +171,18 @@ func (ob *OverridesBuilder) Raw() (Overrides, error) {
 func (ob *OverridesBuilder) mergeSources() (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
+	// merge defaults
+	for _, defaults := range ob.defaults {
+		if err := mergo.Map(&result, copyMap(defaults), mergo.WithOverride); err != nil {
+			return nil, err
+		}
+	}
+
+	return ob.mergeSupplied(result)
+}
+
+// mergeSupplied merges the overrides supplied as files and values, without the defaults, into the result
+func (ob *OverridesBuilder) mergeSupplied(result map[string]interface{}) (map[string]interface{}, error) {
 	// merge files
 	var fileOverrides map[string]interface{}
 	for _, file := range ob.files {
@@ -4,8 +4,15 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides/typed"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func Test_MergeOverrides(t *testing.T) {
@@ -65,3 +72,95 @@ func Test_AddOverrides(t *testing.T) {
 	err = builder.AddOverrides("xyz", data)
 	require.NoError(t, err)
 }
+
+func Test_AddComponentOverrides(t *testing.T) {
+	builder := OverridesBuilder{}
+
+	// invalid
+	require.Error(t, builder.AddComponentOverrides("", map[string]interface{}{"test": "abc"}))
+	require.Error(t, builder.AddComponentOverrides("istio", map[string]interface{}{}))
+	require.Error(t, builder.AddOverrides("components", map[string]interface{}{"test": "abc"}))
+
+	//valid
+	require.NoError(t, builder.AddOverrides("global", map[string]interface{}{"proxy": "global"}))
+	require.NoError(t, builder.AddComponentOverrides("istio", map[string]interface{}{"global": map[string]interface{}{"proxy": "istio"}}))
+	require.NoError(t, builder.AddComponentOverrides("istio", map[string]interface{}{"replicas": 2}))
+
+	o, err := builder.Build()
+	require.NoError(t, err)
+	provider, err := overrides.New(fake.NewSimpleClientset(), o.Map(), logger.NewLogger(true))
+	require.NoError(t, err)
+
+	istio := provider.OverridesGetterFunctionFor("istio")()
+	require.Equal(t, map[string]interface{}{"proxy": "istio"}, istio["global"])
+	require.Equal(t, 2, istio["replicas"])
+	monitoring := provider.OverridesGetterFunctionFor("monitoring")()
+	require.Equal(t, map[string]interface{}{"proxy": "global"}, monitoring["global"])
+}
+
+func Test_AddTyped(t *testing.T) {
+	builder := OverridesBuilder{}
+
+	// no overrides
+	err := builder.AddTyped(nil)
+	require.Error(t, err)
+
+	// invalid overrides
+	err = builder.AddTyped(&typed.Serverless{DefaultFunctionPreset: "XXL"})
+	require.Error(t, err)
+
+	//valid
+	err = builder.AddTyped(&typed.Ory{Persistence: &typed.OryPersistence{}, OathkeeperReplicas: 2})
+	require.NoError(t, err)
+
+	overrides, err := builder.Build()
+	require.NoError(t, err)
+	enabled, found := overrides.Find("global.ory.hydra.persistence.enabled")
+	require.True(t, found)
+	require.Equal(t, true, enabled)
+	replicas, found := overrides.Find("ory.oathkeeper.deployment.replicaCount")
+	require.True(t, found)
+	require.Equal(t, 2, replicas)
+}
+
+func Test_ApplyProviderQuirks(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{"eks.amazonaws.com/nodegroup": "default"}},
+	})
+
+	t.Run("Quirk overrides are defaults", func(t *testing.T) {
+		builder := OverridesBuilder{}
+		err := builder.AddOverrides("istio", map[string]interface{}{
+			"components": map[string]interface{}{
+				"ingressGateways": map[string]interface{}{
+					"config": map[string]interface{}{
+						"serviceAnnotations": map[string]interface{}{
+							"service.beta.kubernetes.io/aws-load-balancer-type": "external",
+						},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		adjustments, err := applyProviderQuirks(&builder, &config.Config{ProviderQuirks: true, Log: logger.NewLogger(true)}, kubeClient)
+		require.NoError(t, err)
+		require.Equal(t, []string{"eks-load-balancer"}, adjustments.Quirks)
+		require.True(t, adjustments.Expectations.LoadBalancer)
+
+		overrides, err := builder.Raw()
+		require.NoError(t, err)
+		annotations, ok := overrides.Find("istio.components.ingressGateways.config.serviceAnnotations")
+		require.True(t, ok)
+		require.Equal(t, "external", annotations.(map[string]interface{})["service.beta.kubernetes.io/aws-load-balancer-type"])
+		require.Equal(t, "true", annotations.(map[string]interface{})["service.beta.kubernetes.io/aws-load-balancer-cross-zone-load-balancing-enabled"])
+	})
+
+	t.Run("Disabled quirks leave the overrides untouched", func(t *testing.T) {
+		builder := OverridesBuilder{}
+		adjustments, err := applyProviderQuirks(&builder, &config.Config{Log: logger.NewLogger(true)}, kubeClient)
+		require.NoError(t, err)
+		require.Nil(t, adjustments)
+		require.Empty(t, builder.defaults)
+	})
+}
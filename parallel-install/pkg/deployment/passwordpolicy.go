@@ -0,0 +1,72 @@
+package deployment
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/secrets"
+)
+
+//checkPasswordPolicy checks the passwords of the generated secrets and the passwords provided as overrides against the password policy.
+//User overrides take precedence over generated passwords, so a generated password is only checked if the deployment uses it.
+func checkPasswordPolicy(ob *OverridesBuilder, generated map[*secrets.Spec]string, policy *config.PasswordPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	o, err := ob.Raw()
+	if err != nil {
+		return err
+	}
+
+	var violations []installerrors.PasswordViolation
+	check := func(source, password string) {
+		rules := policy.Violations(password)
+		if len(rules) == 0 {
+			return
+		}
+		violation := installerrors.PasswordViolation{Source: source}
+		for _, rule := range rules {
+			violation.Rules = append(violation.Rules, string(rule))
+		}
+		violations = append(violations, violation)
+	}
+
+	provided := make(map[string]string)
+	for _, key := range policy.OverrideKeys {
+		if value, ok := o.Find(key); ok {
+			provided[key] = fmt.Sprint(value)
+		}
+	}
+	for spec, password := range generated {
+		overrideKey, exposed := spec.Overrides[secrets.PasswordKey]
+		if !exposed {
+			check(spec.NamespacedName(), password)
+			continue
+		}
+		value, overridden := o.Find(overrideKey)
+		if !overridden {
+			check(spec.NamespacedName(), password)
+			continue
+		}
+		provided[overrideKey] = fmt.Sprint(value)
+		if spec.Base64 {
+			if decoded, err := base64.StdEncoding.DecodeString(provided[overrideKey]); err == nil {
+				provided[overrideKey] = string(decoded)
+			}
+		}
+	}
+	for key, password := range provided {
+		check(key, password)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Source < violations[j].Source
+	})
+	return &installerrors.ErrPasswordPolicy{Violations: violations}
+}
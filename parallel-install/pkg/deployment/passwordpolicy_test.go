@@ -0,0 +1,75 @@
+package deployment
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/secrets"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_PasswordPolicy(t *testing.T) {
+	policy := &config.PasswordPolicy{
+		MinLength:           16,
+		MinCharacterClasses: 3,
+		Forbidden:           []string{"Change-Me-Please-1"},
+		OverrideKeys:        []string{"global.adminPassword"},
+	}
+
+	newBuilder := func(t *testing.T, overrides map[string]interface{}) *OverridesBuilder {
+		ob := &OverridesBuilder{}
+		require.NoError(t, ob.AddOverrides("global", overrides))
+		require.NoError(t, ob.AddGeneratedSecret(secrets.Spec{Name: "db", Kind: secrets.Password, Overrides: map[string]string{"password": "global.db.password"}}))
+		require.NoError(t, ob.AddGeneratedSecret(secrets.Spec{Name: "grafana", Kind: secrets.Password, Overrides: map[string]string{"password": "global.grafana.password"}, Base64: true}))
+		return ob
+	}
+
+	t.Run("Generated and provided passwords comply", func(t *testing.T) {
+		ob := newBuilder(t, map[string]interface{}{"adminPassword": "Long-enough-admin-pw"})
+		require.NoError(t, registerGeneratedSecretInterceptors(ob, fake.NewSimpleClientset(), &config.Config{Log: logger.NewLogger(true), PasswordPolicy: policy}))
+	})
+
+	t.Run("Weak passwords are violations", func(t *testing.T) {
+		existing := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "kyma-installer"},
+			Data:       map[string][]byte{"password": []byte("abc")},
+		}
+		ob := newBuilder(t, map[string]interface{}{
+			"adminPassword": "admin",
+			"grafana":       map[string]interface{}{"password": base64.StdEncoding.EncodeToString([]byte("Change-Me-Please-1"))},
+		})
+
+		err := registerGeneratedSecretInterceptors(ob, fake.NewSimpleClientset(existing), &config.Config{Log: logger.NewLogger(true), PasswordPolicy: policy})
+		var violations *installerrors.ErrPasswordPolicy
+		require.True(t, errors.As(err, &violations))
+		require.Equal(t, []installerrors.PasswordViolation{
+			{Source: "global.adminPassword", Rules: []string{"minLength", "characterClasses"}},
+			{Source: "global.grafana.password", Rules: []string{"forbidden"}},
+			{Source: "kyma-installer/db", Rules: []string{"minLength", "characterClasses"}},
+		}, violations.Violations)
+	})
+
+	t.Run("Overridden generated passwords are not checked", func(t *testing.T) {
+		existing := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "kyma-installer"},
+			Data:       map[string][]byte{"password": []byte("abc")},
+		}
+		ob := newBuilder(t, map[string]interface{}{
+			"adminPassword": "Long-enough-admin-pw",
+			"db":            map[string]interface{}{"password": "Long-enough-db-pw"},
+		})
+		require.NoError(t, registerGeneratedSecretInterceptors(ob, fake.NewSimpleClientset(existing), &config.Config{Log: logger.NewLogger(true), PasswordPolicy: policy}))
+	})
+
+	t.Run("No policy", func(t *testing.T) {
+		ob := newBuilder(t, map[string]interface{}{"adminPassword": "admin"})
+		require.NoError(t, registerGeneratedSecretInterceptors(ob, fake.NewSimpleClientset(), &config.Config{Log: logger.NewLogger(true)}))
+	})
+}
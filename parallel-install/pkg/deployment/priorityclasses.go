@@ -0,0 +1,62 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//createPriorityClasses creates or updates the configured priority classes, which must exist before Pods refer to them
+func (i *core) createPriorityClasses() error {
+	if i.cfg.PriorityClasses == nil {
+		return nil
+	}
+	classes := i.kubeClient.SchedulingV1().PriorityClasses()
+	for _, class := range i.cfg.PriorityClasses.Classes {
+		priorityClass := &schedulingv1.PriorityClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: class.Name},
+			Value:       class.Value,
+			Description: class.Description,
+		}
+		_, err := classes.Create(context.Background(), priorityClass, metav1.CreateOptions{})
+		if err == nil {
+			continue
+		}
+		if !apierr.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "Failed to create priority class '%s'", class.Name)
+		}
+		existing, err := classes.Get(context.Background(), class.Name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "Failed to get priority class '%s'", class.Name)
+		}
+		//the value of a priority class is immutable
+		if existing.Value != class.Value {
+			return fmt.Errorf("Priority class '%s' exists with value %d instead of %d. Delete it to change its value", class.Name, existing.Value, class.Value)
+		}
+		existing.Description = class.Description
+		if _, err := classes.Update(context.Background(), existing, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "Failed to update priority class '%s'", class.Name)
+		}
+	}
+	i.cfg.Log.Infof("Created %d priority classes", len(i.cfg.PriorityClasses.Classes))
+	return nil
+}
+
+//deletePriorityClasses removes the priority classes created by createPriorityClasses.
+//The classes are kept if Kyma is installed per tenant, because the tenants share them.
+func (i *core) deletePriorityClasses() error {
+	if i.cfg.PriorityClasses == nil || i.cfg.Tenancy != nil {
+		return nil
+	}
+	for _, class := range i.cfg.PriorityClasses.Classes {
+		err := i.kubeClient.SchedulingV1().PriorityClasses().Delete(context.Background(), class.Name, metav1.DeleteOptions{})
+		if err != nil && !apierr.IsNotFound(err) {
+			return errors.Wrapf(err, "Failed to delete priority class '%s'", class.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package deployment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_PriorityClasses(t *testing.T) {
+	newCfg := func(value int32) *config.Config {
+		return &config.Config{
+			Log: logger.NewLogger(true),
+			PriorityClasses: &config.PriorityClassConfig{
+				Classes: []config.PriorityClass{{Name: "kyma-system", Value: value, Description: "Kyma system workloads"}},
+				Default: "kyma-system",
+			},
+		}
+	}
+
+	t.Run("Create is idempotent", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		c := newCore(newCfg(100000), &OverridesBuilder{}, kubeClient, nil)
+		require.NoError(t, c.createPriorityClasses())
+		require.NoError(t, c.createPriorityClasses())
+
+		class, err := kubeClient.SchedulingV1().PriorityClasses().Get(context.Background(), "kyma-system", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, int32(100000), class.Value)
+		require.Equal(t, "Kyma system workloads", class.Description)
+	})
+
+	t.Run("Value of an existing class cannot change", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "kyma-system"}, Value: 1000})
+		c := newCore(newCfg(100000), &OverridesBuilder{}, kubeClient, nil)
+		require.EqualError(t, c.createPriorityClasses(), "Priority class 'kyma-system' exists with value 1000 instead of 100000. Delete it to change its value")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		c := newCore(newCfg(100000), &OverridesBuilder{}, kubeClient, nil)
+		require.NoError(t, c.createPriorityClasses())
+		require.NoError(t, c.deletePriorityClasses())
+		require.NoError(t, c.deletePriorityClasses())
+
+		_, err := kubeClient.SchedulingV1().PriorityClasses().Get(context.Background(), "kyma-system", metav1.GetOptions{})
+		require.True(t, apierr.IsNotFound(err))
+	})
+
+	t.Run("Tenants keep the classes", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		cfg := newCfg(100000)
+		cfg.Tenancy = &config.TenancyConfig{Tenant: "acme"}
+		c := newCore(cfg, &OverridesBuilder{}, kubeClient, nil)
+		require.NoError(t, c.createPriorityClasses())
+		require.NoError(t, c.deletePriorityClasses())
+
+		_, err := kubeClient.SchedulingV1().PriorityClasses().Get(context.Background(), "kyma-system", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+}
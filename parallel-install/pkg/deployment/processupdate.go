@@ -22,12 +22,18 @@ const (
 	ProcessTimeoutFailure ProcessEvent = "ProcessTimeoutFailure"
 	// ProcessForceQuitFailure indicates an cancelled main process
 	ProcessForceQuitFailure ProcessEvent = "ProcessForceQuitFailure"
+	// ProcessComponentDegraded indicates a component whose workloads became unhealthy after the installation
+	ProcessComponentDegraded ProcessEvent = "ProcessComponentDegraded"
+	// ProcessComponentRecovered indicates a degraded component whose workloads are healthy again
+	ProcessComponentRecovered ProcessEvent = "ProcessComponentRecovered"
 )
 
 // InstallationPhase represents the current installation phase
 type InstallationPhase string
 
 const (
+	// ProvisionCluster indicates the main process is provisioning the cluster Kyma gets deployed on
+	ProvisionCluster InstallationPhase = "ProvisionCluster"
 	// InstallPreRequisites indicates the main process is installing pre-requisites
 	InstallPreRequisites InstallationPhase = "InstallPreRequisites"
 	// UninstallPreRequisites indicates the main process is removing pre-requisites
@@ -36,6 +42,8 @@ const (
 	InstallComponents InstallationPhase = "InstallComponents"
 	// UninstallComponents indicates the main process is removing components
 	UninstallComponents InstallationPhase = "UninstallComponents"
+	// MonitorComponents indicates the main process is watching the workloads of the installed components
+	MonitorComponents InstallationPhase = "MonitorComponents"
 )
 
 // ProcessUpdate is an update of the main process
@@ -45,6 +53,8 @@ type ProcessUpdate struct {
 	Error error
 	//Component is only set during the component install/uninstall phase
 	Component components.KymaComponent
+	//Suppressed is the number of identical updates the update throttle dropped since this update was delivered last
+	Suppressed int
 }
 
 func (pu *ProcessUpdate) IsComponentUpdate() bool {
@@ -0,0 +1,222 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/git"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
+	"github.com/pkg/errors"
+)
+
+//ReconcilePlan lists the changes required to bring the cluster to the desired component list
+type ReconcilePlan struct {
+	//Install contains the desired components which are not installed
+	Install []string
+	//Upgrade contains the installed components whose version or source differs from the desired one
+	Upgrade []string
+	//Uninstall contains the installed components which are not desired anymore
+	Uninstall []string
+	//Changes contains the commits and changelog entries of the upgraded components by component name.
+	//It is only set by AttachChanges.
+	Changes map[string]*git.DirectoryChanges
+	deploy  *config.ComponentList
+	remove  *config.ComponentList
+	//installedVersions are the installed Kyma versions of the upgraded components
+	installedVersions map[string]string
+}
+
+//Empty returns true if the cluster is in the desired state
+func (p *ReconcilePlan) Empty() bool {
+	return len(p.Install) == 0 && len(p.Upgrade) == 0 && len(p.Uninstall) == 0
+}
+
+func (p *ReconcilePlan) String() string {
+	return fmt.Sprintf("install: %v, upgrade: %v, uninstall: %v", p.Install, p.Upgrade, p.Uninstall)
+}
+
+//AttachChanges adds the commits and changelog entries of the chart directories of the upgraded components
+//between their installed version and the target revision of the Kyma sources in the local repository repoPath
+func (p *ReconcilePlan) AttachChanges(repoPath, targetRev string) error {
+	byVersion := make(map[string][]string)
+	for _, name := range p.Upgrade {
+		version := p.installedVersions[name]
+		byVersion[version] = append(byVersion[version], name)
+	}
+
+	p.Changes = make(map[string]*git.DirectoryChanges)
+	for version, names := range byVersion {
+		dirs := make([]string, 0, len(names))
+		for _, name := range names {
+			dirs = append(dirs, path.Join("resources", name))
+		}
+		changes, err := git.ChangeSummary(repoPath, version, targetRev, dirs)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to summarize the changes since version %s", version)
+		}
+		for i, name := range names {
+			p.Changes[name] = changes[i]
+		}
+	}
+	return nil
+}
+
+//Report returns the plan followed by the attached changes of the upgraded components, for review before the plan is applied
+func (p *ReconcilePlan) Report() string {
+	var b strings.Builder
+	b.WriteString(p.String())
+	for _, name := range p.Upgrade {
+		changes, ok := p.Changes[name]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s (%s -> target): ", name, p.installedVersions[name])
+		if changes.Empty() {
+			b.WriteString("no changes")
+			continue
+		}
+		b.WriteString(changes.String())
+	}
+	return b.String()
+}
+
+//Plan compares the configured component list to the components installed on the cluster
+func (d *Deployment) Plan() (*ReconcilePlan, error) {
+	versions, err := d.metadataProvider().Versions()
+	if err != nil {
+		return nil, err
+	}
+	compList, err := d.componentList()
+	if err != nil {
+		return nil, err
+	}
+	if d.evaluateSkipConditions {
+		o, err := d.overrides.Build()
+		if err != nil {
+			return nil, err
+		}
+		if compList, _, err = d.withoutSkippedComponents(o, compList); err != nil {
+			return nil, err
+		}
+	}
+	return newReconcilePlan(compList, versions.InstalledComponents(), d.cfg.Version, d.cfg.Source), nil
+}
+
+//Reconcile installs missing components, upgrades drifted components and uninstalls components which were removed
+//from the component list in one operation. Components which are already in the desired state are not touched.
+func (d *Deployment) Reconcile() error {
+	plan, err := d.Plan()
+	if err != nil {
+		return err
+	}
+	if plan.Empty() {
+		d.cfg.Log.Info("Kyma components are up to date")
+		return nil
+	}
+	d.cfg.Log.Infof("Reconciling Kyma components: %s", plan)
+
+	if len(plan.Uninstall) > 0 {
+		if err := d.uninstallRemoved(plan.remove); err != nil {
+			return err
+		}
+	}
+
+	if len(plan.Install) > 0 || len(plan.Upgrade) > 0 {
+		cfg := *d.cfg
+		cfg.ComponentList = plan.deploy
+		//the plan only contains components of the selected bundles
+		cfg.Bundles = nil
+		deployment := &Deployment{newCore(&cfg, d.overrides, d.kubeClient, d.processUpdates)}
+		deployment.clock = d.clock
+		deployment.shutdown = d.shutdown
+		deployment.streams = d.streams
+		return deployment.StartKymaDeployment()
+	}
+	return nil
+}
+
+//uninstallRemoved uninstalls the given components first and prerequisites afterwards, without deleting Kyma namespaces
+func (d *Deployment) uninstallRemoved(remove *config.ComponentList) error {
+	cfg := *d.cfg
+	cfg.ComponentList = remove
+	cfg.Bundles = nil
+	//the names and namespaces of installed releases are not templated again
+	cfg.ReleaseNaming = nil
+	cfg.Tenancy = nil
+	deletion := &Deletion{core: newCore(&cfg, d.overrides, d.kubeClient, d.processUpdates)}
+	deletion.clock = d.clock
+	deletion.shutdown = d.shutdown
+	deletion.streams = d.streams
+
+	_, prerequisitesEng, componentsEng, err := deletion.getConfig()
+	if err != nil {
+		return err
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	deadlines := deletion.newDeadlines()
+	if err := deletion.uninstallComponents(cancelCtx, cancel, UninstallComponents, componentsEng, deadlines); err != nil {
+		return err
+	}
+	return deletion.uninstallComponents(cancelCtx, cancel, UninstallPreRequisites, prerequisitesEng, deadlines)
+}
+
+//newReconcilePlan compares the desired components to the installed ones. Components installed from another source are upgraded
+//even if their version did not change. Components whose source was not recorded only compare the version.
+func newReconcilePlan(desired *config.ComponentList, installed []*helm.KymaComponentMetadata, version string, source *config.Source) *ReconcilePlan {
+	plan := &ReconcilePlan{
+		deploy:            &config.ComponentList{},
+		remove:            &config.ComponentList{},
+		installedVersions: make(map[string]string),
+	}
+
+	installedByName := make(map[string]*helm.KymaComponentMetadata)
+	for _, comp := range installed {
+		installedByName[comp.Name] = comp
+	}
+
+	desiredNames := make(map[string]bool)
+	check := func(comps []config.ComponentDefinition, deploy *[]config.ComponentDefinition) {
+		for _, comp := range comps {
+			desiredNames[comp.Release()] = true
+			current, ok := installedByName[comp.Release()]
+			switch {
+			case !ok:
+				plan.Install = append(plan.Install, comp.Name)
+			case current.Version != version, sourceChanged(current, source):
+				plan.Upgrade = append(plan.Upgrade, comp.Name)
+				plan.installedVersions[comp.Name] = current.Version
+			default:
+				continue
+			}
+			*deploy = append(*deploy, comp)
+		}
+	}
+	check(desired.Prerequisites, &plan.deploy.Prerequisites)
+	check(desired.Components, &plan.deploy.Components)
+
+	for _, comp := range installed {
+		if desiredNames[comp.Name] {
+			continue
+		}
+		plan.Uninstall = append(plan.Uninstall, comp.Name)
+		def := config.ComponentDefinition{Name: comp.Name, Namespace: comp.Namespace}
+		if comp.Prerequisite {
+			plan.remove.Prerequisites = append(plan.remove.Prerequisites, def)
+		} else {
+			plan.remove.Components = append(plan.remove.Components, def)
+		}
+	}
+
+	return plan
+}
+
+//sourceChanged returns true if the component was installed from a recorded source which differs from the given one
+func sourceChanged(installed *helm.KymaComponentMetadata, source *config.Source) bool {
+	return source != nil && installed.SourceID != "" && installed.SourceID != source.ID()
+}
@@ -0,0 +1,119 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/git"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReconcilePlan(t *testing.T) {
+	desired := &config.ComponentList{
+		Prerequisites: []config.ComponentDefinition{
+			{Name: "cluster-essentials", Namespace: "kyma-system"},
+			{Name: "istio", Namespace: "istio-system"},
+		},
+		Components: []config.ComponentDefinition{
+			{Name: "serverless", Namespace: "kyma-system"},
+			{Name: "eventing", Namespace: "kyma-system"},
+		},
+	}
+
+	t.Run("Nothing installed", func(t *testing.T) {
+		plan := newReconcilePlan(desired, nil, "2.0.0", nil)
+		require.Equal(t, []string{"cluster-essentials", "istio", "serverless", "eventing"}, plan.Install)
+		require.Empty(t, plan.Upgrade)
+		require.Empty(t, plan.Uninstall)
+		require.Equal(t, desired, plan.deploy)
+	})
+
+	t.Run("Up to date", func(t *testing.T) {
+		installed := []*helm.KymaComponentMetadata{
+			{Name: "cluster-essentials", Namespace: "kyma-system", Version: "2.0.0", Prerequisite: true},
+			{Name: "istio", Namespace: "istio-system", Version: "2.0.0", Prerequisite: true},
+			{Name: "serverless", Namespace: "kyma-system", Version: "2.0.0"},
+			{Name: "eventing", Namespace: "kyma-system", Version: "2.0.0"},
+		}
+		plan := newReconcilePlan(desired, installed, "2.0.0", nil)
+		require.True(t, plan.Empty())
+	})
+
+	t.Run("Install, upgrade and uninstall", func(t *testing.T) {
+		installed := []*helm.KymaComponentMetadata{
+			{Name: "cluster-essentials", Namespace: "kyma-system", Version: "2.0.0", Prerequisite: true},
+			{Name: "istio", Namespace: "istio-system", Version: "1.24.0", Prerequisite: true},
+			{Name: "serverless", Namespace: "kyma-system", Version: "2.0.0"},
+			{Name: "monitoring", Namespace: "kyma-system", Version: "2.0.0"},
+		}
+		plan := newReconcilePlan(desired, installed, "2.0.0", nil)
+		require.False(t, plan.Empty())
+		require.Equal(t, []string{"eventing"}, plan.Install)
+		require.Equal(t, []string{"istio"}, plan.Upgrade)
+		require.Equal(t, []string{"monitoring"}, plan.Uninstall)
+
+		require.Equal(t, []config.ComponentDefinition{{Name: "istio", Namespace: "istio-system"}}, plan.deploy.Prerequisites)
+		require.Equal(t, []config.ComponentDefinition{{Name: "eventing", Namespace: "kyma-system"}}, plan.deploy.Components)
+		require.Empty(t, plan.remove.Prerequisites)
+		require.Equal(t, []config.ComponentDefinition{{Name: "monitoring", Namespace: "kyma-system"}}, plan.remove.Components)
+	})
+
+	t.Run("Report with changes", func(t *testing.T) {
+		installed := []*helm.KymaComponentMetadata{
+			{Name: "cluster-essentials", Namespace: "kyma-system", Version: "2.0.0", Prerequisite: true},
+			{Name: "istio", Namespace: "istio-system", Version: "1.24.0", Prerequisite: true},
+			{Name: "serverless", Namespace: "kyma-system", Version: "1.24.0"},
+			{Name: "eventing", Namespace: "kyma-system", Version: "2.0.0"},
+		}
+		plan := newReconcilePlan(desired, installed, "2.0.0", nil)
+		require.Equal(t, map[string]string{"istio": "1.24.0", "serverless": "1.24.0"}, plan.installedVersions)
+
+		plan.Changes = map[string]*git.DirectoryChanges{
+			"istio": {Path: "resources/istio"},
+			"serverless": {Path: "resources/serverless", Commits: []git.Commit{
+				{Hash: "34edf09a5e6c", Author: "Jane Doe", Subject: "Scale serverless"},
+			}},
+		}
+		require.Equal(t, "install: [], upgrade: [istio serverless], uninstall: []\n"+
+			"istio (1.24.0 -> target): no changes\n"+
+			"serverless (1.24.0 -> target): resources/serverless: 1 commit(s)\n  34edf09a Scale serverless (Jane Doe)", plan.Report())
+	})
+
+	t.Run("Source changed", func(t *testing.T) {
+		archive := &config.Source{Type: config.ArchiveSource, Reference: "https://github.com/kyma-project/kyma/archive/2.0.0.tar.gz"}
+		gitSource := &config.Source{Type: config.GitSource, Reference: "https://github.com/kyma-project/kyma@2.0.0"}
+		installed := []*helm.KymaComponentMetadata{
+			{Name: "cluster-essentials", Namespace: "kyma-system", Version: "2.0.0", Prerequisite: true, SourceType: "archive", SourceID: archive.ID()},
+			{Name: "istio", Namespace: "istio-system", Version: "2.0.0", Prerequisite: true, SourceType: "git", SourceID: gitSource.ID()},
+			{Name: "serverless", Namespace: "kyma-system", Version: "2.0.0"},
+			{Name: "eventing", Namespace: "kyma-system", Version: "2.0.0", SourceType: "archive", SourceID: archive.ID()},
+		}
+
+		plan := newReconcilePlan(desired, installed, "2.0.0", gitSource)
+		require.Empty(t, plan.Install)
+		require.Equal(t, []string{"cluster-essentials", "eventing"}, plan.Upgrade, "Components without recorded source should only compare the version")
+		require.Empty(t, plan.Uninstall)
+
+		plan = newReconcilePlan(desired, installed, "2.0.0", nil)
+		require.True(t, plan.Empty(), "Sources should not be compared if the target source is unknown")
+	})
+
+	t.Run("Templated release names", func(t *testing.T) {
+		naming := &config.ReleaseNaming{ReleaseName: "{{.Component}}-{{.InstallationID}}", InstallationID: "tenant1"}
+		templated, err := naming.Apply(desired)
+		require.NoError(t, err)
+
+		installed := []*helm.KymaComponentMetadata{
+			{Name: "cluster-essentials-tenant1", Namespace: "kyma-system", Version: "2.0.0", Prerequisite: true},
+			{Name: "istio-tenant1", Namespace: "istio-system", Version: "2.0.0", Prerequisite: true},
+			{Name: "serverless-tenant1", Namespace: "kyma-system", Version: "2.0.0"},
+			{Name: "serverless", Namespace: "kyma-system", Version: "2.0.0"},
+		}
+		plan := newReconcilePlan(templated, installed, "2.0.0", nil)
+		require.Equal(t, []string{"eventing"}, plan.Install)
+		require.Empty(t, plan.Upgrade)
+		require.Equal(t, []string{"serverless"}, plan.Uninstall)
+		require.Equal(t, "eventing-tenant1", plan.deploy.Components[0].Release())
+	})
+}
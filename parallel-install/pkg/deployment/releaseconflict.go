@@ -0,0 +1,112 @@
+package deployment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+//foreignRelease is an existing Helm release of a component which was not installed by Kyma
+type foreignRelease struct {
+	name         string
+	namespace    string
+	revision     int
+	prerequisite bool
+}
+
+func (r foreignRelease) String() string {
+	return fmt.Sprintf("%s/%s", r.namespace, r.name)
+}
+
+//resolveReleaseConflicts finds the Helm releases of the components which already exist, but carry no Kyma metadata,
+//and applies the release conflict policy to them. Only the Helm backend deploys releases, so other backends have no conflicts.
+func (d *Deployment) resolveReleaseConflicts() error {
+	if d.cfg.Backend != "" && d.cfg.Backend != config.HelmBackend {
+		return nil
+	}
+	compList, err := d.componentList()
+	if err != nil {
+		return err
+	}
+	foreign, err := d.foreignReleases(compList)
+	if err != nil || len(foreign) == 0 {
+		return err
+	}
+
+	switch d.cfg.ReleaseConflicts {
+	case config.ReleaseConflictFail:
+		releases := make([]string, 0, len(foreign))
+		for _, rel := range foreign {
+			releases = append(releases, rel.String())
+		}
+		return &installerrors.ErrReleaseConflict{Releases: releases}
+	case config.ReleaseConflictAdopt:
+		tpl := d.metadataTemplate()
+		for _, rel := range foreign {
+			d.cfg.Log.Warn(d.cfg.Catalog().Text(messages.ReleaseAdopted, messages.Args{"Release": rel.name, "Namespace": rel.namespace}))
+			relTpl := tpl.ForComponents()
+			if rel.prerequisite {
+				relTpl = tpl.ForPrerequisites()
+			}
+			err := d.metadataProvider().Set(&release.Release{Name: rel.name, Namespace: rel.namespace, Version: rel.revision}, relTpl)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to adopt release '%s'", rel)
+			}
+		}
+	case config.ReleaseConflictReplace:
+		ctx, cancel := d.shutdown.context(context.Background())
+		defer cancel()
+		for _, rel := range foreign {
+			if d.shutdown.requested() {
+				return d.interrupted()
+			}
+			d.cfg.Log.Warn(d.cfg.Catalog().Text(messages.ReleaseReplaced, messages.Args{"Release": rel.name, "Namespace": rel.namespace}))
+			comp := d.releaseComponent(rel.name, rel.namespace)
+			if err := comp.Uninstall(ctx); err != nil {
+				if d.shutdown.requested() {
+					return d.interrupted()
+				}
+				return errors.Wrapf(err, "Failed to uninstall release '%s'", rel)
+			}
+		}
+	default:
+		for _, rel := range foreign {
+			d.cfg.Log.Warn(d.cfg.Catalog().Text(messages.ReleaseConflict, messages.Args{"Release": rel.name, "Namespace": rel.namespace}))
+		}
+	}
+	return nil
+}
+
+//foreignReleases returns the existing releases of the components which were not installed by Kyma, in the order of the component list.
+//Components in namespaces which are skipped due to a namespace conflict are not deployed, so their releases are ignored.
+func (d *Deployment) foreignReleases(compList *config.ComponentList) ([]foreignRelease, error) {
+	var foreign []foreignRelease
+	mp := d.metadataProvider()
+	check := func(comps []config.ComponentDefinition, prerequisite bool) error {
+		for _, comp := range comps {
+			if d.skippedNamespaces[comp.Namespace] {
+				continue
+			}
+			revision, owned, err := mp.ReleaseOwnership(comp.Namespace, comp.Name)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to read release '%s' in namespace '%s'", comp.Name, comp.Namespace)
+			}
+			if revision > 0 && !owned {
+				foreign = append(foreign, foreignRelease{name: comp.Name, namespace: comp.Namespace, revision: revision, prerequisite: prerequisite})
+			}
+		}
+		return nil
+	}
+	if err := check(compList.Prerequisites, true); err != nil {
+		return nil, err
+	}
+	if err := check(compList.Components, false); err != nil {
+		return nil, err
+	}
+	return foreign, nil
+}
@@ -0,0 +1,119 @@
+package deployment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type uninstallRecorder struct {
+	mockHelmClient
+	uninstalled []string
+}
+
+func (c *uninstallRecorder) UninstallRelease(ctx context.Context, namespace, name string) error {
+	c.uninstalled = append(c.uninstalled, namespace+"/"+name)
+	return nil
+}
+
+func TestDeployment_ResolveReleaseConflicts(t *testing.T) {
+	releaseSecret := func(name, namespace, version string, labels map[string]string) *v1.Secret {
+		secretLabels := map[string]string{"owner": "helm", "name": name, "version": version}
+		for k, v := range labels {
+			secretLabels[k] = v
+		}
+		return &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1." + name + ".v" + version,
+			Namespace: namespace,
+			Labels:    secretLabels,
+		}}
+	}
+
+	newDeployment := func(policy config.ReleaseConflictPolicy) (*Deployment, *fake.Clientset) {
+		kubeClient := fake.NewSimpleClientset(
+			releaseSecret("istio", "istio-system", "1", map[string]string{helm.KymaLabelPrefix + "component": "true"}),
+			releaseSecret("serverless", "kyma-system", "1", nil),
+			releaseSecret("serverless", "kyma-system", "2", nil),
+		)
+		cfg := &config.Config{
+			Log:     logger.NewLogger(true),
+			Version: "1.20.0",
+			ComponentList: &config.ComponentList{
+				Prerequisites: []config.ComponentDefinition{{Name: "istio", Namespace: "istio-system"}},
+				Components: []config.ComponentDefinition{
+					{Name: "serverless", Namespace: "kyma-system"},
+					{Name: "eventing", Namespace: "kyma-system"},
+				},
+			},
+			ReleaseConflicts: policy,
+		}
+		return &Deployment{newCore(cfg, &OverridesBuilder{}, kubeClient, nil)}, kubeClient
+	}
+
+	t.Run("Fail", func(t *testing.T) {
+		d, _ := newDeployment(config.ReleaseConflictFail)
+		err := d.resolveReleaseConflicts()
+		var conflict *installerrors.ErrReleaseConflict
+		require.True(t, errors.As(err, &conflict))
+		require.Equal(t, []string{"kyma-system/serverless"}, conflict.Releases)
+	})
+
+	t.Run("Adopt", func(t *testing.T) {
+		d, kubeClient := newDeployment(config.ReleaseConflictAdopt)
+		require.NoError(t, d.resolveReleaseConflicts())
+
+		secret, err := kubeClient.CoreV1().Secrets("kyma-system").Get(context.Background(), "sh.helm.release.v1.serverless.v2", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "true", secret.Labels[helm.KymaLabelPrefix+"component"])
+		require.Equal(t, "1.20.0", secret.Labels[helm.KymaLabelPrefix+"version"])
+		require.Equal(t, "false", secret.Labels[helm.KymaLabelPrefix+"prerequisite"])
+
+		require.NoError(t, d.resolveReleaseConflicts())
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		d, _ := newDeployment(config.ReleaseConflictReplace)
+		recorder := &uninstallRecorder{}
+		d.newReleaseComponent = func(name, namespace string) components.KymaComponent {
+			return components.KymaComponent{Name: name, Namespace: namespace, HelmClient: recorder, Log: d.cfg.Log}
+		}
+		require.NoError(t, d.resolveReleaseConflicts())
+		require.Equal(t, []string{"kyma-system/serverless"}, recorder.uninstalled)
+	})
+
+	t.Run("Replace is interrupted by a shutdown request", func(t *testing.T) {
+		d, _ := newDeployment(config.ReleaseConflictReplace)
+		recorder := &uninstallRecorder{}
+		d.newReleaseComponent = func(name, namespace string) components.KymaComponent {
+			return components.KymaComponent{Name: name, Namespace: namespace, HelmClient: recorder, Log: d.cfg.Log}
+		}
+		d.Shutdown()
+		err := d.resolveReleaseConflicts()
+		require.True(t, errors.Is(err, installerrors.ErrCancelled))
+		require.Empty(t, recorder.uninstalled)
+	})
+
+	t.Run("Upgrade", func(t *testing.T) {
+		d, kubeClient := newDeployment("")
+		require.NoError(t, d.resolveReleaseConflicts())
+		secret, err := kubeClient.CoreV1().Secrets("kyma-system").Get(context.Background(), "sh.helm.release.v1.serverless.v2", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotContains(t, secret.Labels, helm.KymaLabelPrefix+"component")
+	})
+
+	t.Run("Other backends", func(t *testing.T) {
+		d, _ := newDeployment(config.ReleaseConflictFail)
+		d.cfg.Backend = config.SimulationBackend
+		require.NoError(t, d.resolveReleaseConflicts())
+	})
+}
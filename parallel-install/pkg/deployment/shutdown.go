@@ -0,0 +1,118 @@
+package deployment
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+)
+
+//shutdown tracks the shutdown requests of a process, e.g. triggered by OS signals.
+//The first request closes the cancel channel, the second one the quit channel.
+type shutdown struct {
+	mu       sync.Mutex
+	requests int
+	cancel   chan struct{}
+	quit     chan struct{}
+}
+
+func newShutdown() *shutdown {
+	return &shutdown{
+		cancel: make(chan struct{}),
+		quit:   make(chan struct{}),
+	}
+}
+
+func (s *shutdown) request() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	switch s.requests {
+	case 1:
+		close(s.cancel)
+	case 2:
+		close(s.quit)
+	}
+}
+
+//context returns a context which is cancelled by the first shutdown request, so that blocking waits outside of
+//the processing of the components, e.g. for a backup or a release uninstallation, end as soon as a shutdown is requested.
+//Call the returned function to release the context.
+func (s *shutdown) context(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-s.cancel:
+			cancel()
+		case <-s.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+//requested returns true if a shutdown was requested
+func (s *shutdown) requested() bool {
+	select {
+	case <-s.cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+//interrupted returns the error of a deployment whose wait was ended by a shutdown request.
+//The second request is reported as a force quit, like during the processing of the components.
+func (i *core) interrupted() error {
+	select {
+	case <-i.shutdown.quit:
+		return i.cfg.Catalog().New(messages.DeploymentInterruptedForceQuit, nil).Wrap(installerrors.ErrCancelled)
+	default:
+		return i.cfg.Catalog().New(messages.DeploymentInterrupted, nil).Wrap(installerrors.ErrCancelled)
+	}
+}
+
+//Shutdown requests the running process to stop. The first request cancels the process like the cancel timeout:
+//no further components are processed, and the running components get the regular time until the quit timeout to finish.
+//The second request quits the process immediately. Requests also end the waits before the components are processed,
+//e.g. for the maintenance window or the backups; other requests which arrive earlier take effect as soon as the processing starts.
+func (i *core) Shutdown() {
+	i.shutdown.request()
+}
+
+//HandleSignals calls Shutdown for every OS signal received, by default for SIGINT and SIGTERM.
+//So the first Ctrl+C or SIGTERM cancels the process gracefully, and the second one quits it.
+//Call the returned function to stop handling the signals, e.g. after the process returned.
+func (i *core) HandleSignals(signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	received := make(chan os.Signal, 2)
+	signal.Notify(received, signals...)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-received:
+				i.cfg.Log.Warnf("Received signal %s, requesting shutdown", sig)
+				i.Shutdown()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package deployment
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/engine"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestShutdown(t *testing.T) {
+
+	newEngines := func(processingTime int) (*mockOverridesProvider, *engine.Engine, *engine.Engine) {
+		provider := &mockProvider{hc: &mockHelmClient{componentProcessingTime: processingTime}}
+		overridesProvider := &mockOverridesProvider{}
+		prerequisitesEng := engine.NewEngine(overridesProvider, provider, engine.Config{
+			WorkersCount: 1,
+			Log:          logger.NewLogger(true),
+		})
+		componentsEng := engine.NewEngine(overridesProvider, provider, engine.Config{
+			WorkersCount: 2,
+			Log:          logger.NewLogger(true),
+		})
+		return overridesProvider, prerequisitesEng, componentsEng
+	}
+
+	t.Run("Requests close cancel and quit", func(t *testing.T) {
+		s := newShutdown()
+		s.request()
+		require.True(t, isClosed(s.cancel))
+		require.False(t, isClosed(s.quit))
+		s.request()
+		require.True(t, isClosed(s.quit))
+		s.request()
+	})
+
+	t.Run("First request cancels the deployment", func(t *testing.T) {
+		inst := newDeployment(t, nil, fake.NewSimpleClientset())
+		overridesProvider, prerequisitesEng, componentsEng := newEngines(50)
+
+		inst.Shutdown()
+		start := time.Now()
+		err := inst.startKymaDeployment(overridesProvider, prerequisitesEng, componentsEng)
+
+		require.Error(t, err)
+		require.True(t, messages.Is(err, messages.DeploymentInterrupted))
+		require.True(t, errors.Is(err, installerrors.ErrCancelled))
+		require.Less(t, time.Since(start).Milliseconds(), cancelTimeout.Milliseconds())
+	})
+
+	t.Run("Second request quits the deployment", func(t *testing.T) {
+		inst := newDeployment(t, nil, fake.NewSimpleClientset())
+		overridesProvider, prerequisitesEng, componentsEng := newEngines(100)
+
+		inst.Shutdown()
+		inst.Shutdown()
+		err := inst.startKymaDeployment(overridesProvider, prerequisitesEng, componentsEng)
+
+		require.Error(t, err)
+		require.True(t, messages.Is(err, messages.DeploymentInterruptedForceQuit))
+		require.True(t, errors.Is(err, installerrors.ErrCancelled))
+	})
+
+	t.Run("Signals request the shutdown", func(t *testing.T) {
+		inst := newDeployment(t, nil, fake.NewSimpleClientset())
+		stop := inst.HandleSignals(os.Interrupt)
+		defer stop()
+
+		process, err := os.FindProcess(os.Getpid())
+		require.NoError(t, err)
+		require.NoError(t, process.Signal(os.Interrupt))
+
+		select {
+		case <-inst.shutdown.cancel:
+		case <-time.After(time.Second):
+			t.Fatal("Signal did not request the shutdown")
+		}
+		require.False(t, isClosed(inst.shutdown.quit))
+	})
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,94 @@
+package deployment
+
+import (
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/cluster"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+	"github.com/pkg/errors"
+)
+
+//skippedComponent is a component whose skip condition holds or whose namespace is skipped
+type skippedComponent struct {
+	phase      InstallationPhase
+	definition config.ComponentDefinition
+	//reason is the logged message
+	reason string
+}
+
+//withoutSkippedComponents returns the component list without the components whose skip conditions hold
+//or whose namespace is skipped due to a namespace conflict.
+//Conditions are evaluated against the overrides and, if they refer to cluster facts, against the inspected cluster.
+func (i *core) withoutSkippedComponents(o Overrides, compList *config.ComponentList) (*config.ComponentList, []skippedComponent, error) {
+	var info *cluster.Info
+	if refersToClusterFacts(compList) {
+		var err error
+		if info, err = cluster.InspectClient(i.kubeClient); err != nil {
+			return nil, nil, errors.Wrap(err, "Failed to inspect the cluster for the skip conditions of the components")
+		}
+	}
+	lookup := func(key string) (interface{}, bool) {
+		if strings.HasPrefix(key, config.ClusterFactPrefix) {
+			return info.Fact(strings.TrimPrefix(key, config.ClusterFactPrefix))
+		}
+		return o.Find(key)
+	}
+
+	var skipped []skippedComponent
+	filter := func(phase InstallationPhase, comps []config.ComponentDefinition) ([]config.ComponentDefinition, error) {
+		var kept []config.ComponentDefinition
+		for _, comp := range comps {
+			if i.skippedNamespaces[comp.Namespace] {
+				reason := i.cfg.Catalog().Text(messages.NamespaceConflictSkipped, messages.Args{"Component": comp.Name, "Namespace": comp.Namespace})
+				skipped = append(skipped, skippedComponent{phase: phase, definition: comp, reason: reason})
+				continue
+			}
+			skip, condition, err := comp.Skipped(lookup)
+			if err != nil {
+				return nil, err
+			}
+			if skip {
+				reason := i.cfg.Catalog().Text(messages.ComponentSkipped, messages.Args{"Component": comp.Name, "Condition": condition})
+				skipped = append(skipped, skippedComponent{phase: phase, definition: comp, reason: reason})
+				continue
+			}
+			kept = append(kept, comp)
+		}
+		return kept, nil
+	}
+
+	filtered := &config.ComponentList{}
+	var err error
+	if filtered.Prerequisites, err = filter(InstallPreRequisites, compList.Prerequisites); err != nil {
+		return nil, nil, err
+	}
+	if filtered.Components, err = filter(InstallComponents, compList.Components); err != nil {
+		return nil, nil, err
+	}
+	return filtered, skipped, nil
+}
+
+//reportSkipped logs the skipped components and sends an update with the Skipped status for each of them
+func (i *core) reportSkipped(skipped []skippedComponent) {
+	for _, s := range skipped {
+		i.cfg.Log.Info(s.reason)
+		i.processUpdateComponent(s.phase, components.KymaComponent{
+			Name:      s.definition.Release(),
+			Namespace: s.definition.Namespace,
+			Status:    components.StatusSkipped,
+		})
+	}
+}
+
+func refersToClusterFacts(compList *config.ComponentList) bool {
+	for _, comp := range append(append([]config.ComponentDefinition{}, compList.Prerequisites...), compList.Components...) {
+		for _, condition := range comp.SkipIf {
+			if strings.HasPrefix(condition.Key(), config.ClusterFactPrefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCore_WithoutSkippedComponents(t *testing.T) {
+	ob := &OverridesBuilder{}
+	require.NoError(t, ob.AddOverrides("global", map[string]interface{}{"monitoring": map[string]interface{}{"enabled": false}}))
+	o, err := ob.Build()
+	require.NoError(t, err)
+
+	compList := &config.ComponentList{
+		Prerequisites: []config.ComponentDefinition{
+			{Name: "cluster-essentials", Namespace: "kyma-system"},
+			{Name: "istio", Namespace: "istio-system", SkipIf: []config.SkipCondition{"cluster.provider==unknown"}},
+		},
+		Components: []config.ComponentDefinition{
+			{Name: "monitoring", Namespace: "kyma-system", SkipIf: []config.SkipCondition{"global.monitoring.enabled==false"}},
+			{Name: "serverless", Namespace: "kyma-system", SkipIf: []config.SkipCondition{"global.serverless.enabled==false"}},
+		},
+	}
+
+	var updates []ProcessUpdate
+	cfg := &config.Config{Log: logger.NewLogger(true), ComponentList: compList}
+	c := newCore(cfg, ob, fake.NewSimpleClientset(), func(update ProcessUpdate) {
+		updates = append(updates, update)
+	})
+
+	filtered, skipped, err := c.withoutSkippedComponents(o, compList)
+	require.NoError(t, err)
+	require.Equal(t, []config.ComponentDefinition{{Name: "cluster-essentials", Namespace: "kyma-system"}}, filtered.Prerequisites)
+	require.Len(t, filtered.Components, 1)
+	require.Equal(t, "serverless", filtered.Components[0].Name)
+	require.Len(t, skipped, 2)
+
+	c.reportSkipped(skipped)
+	require.Len(t, updates, 2)
+	require.Equal(t, InstallPreRequisites, updates[0].Phase)
+	require.Equal(t, "istio", updates[0].Component.Name)
+	require.Equal(t, components.StatusSkipped, updates[0].Component.Status)
+	require.Equal(t, InstallComponents, updates[1].Phase)
+	require.Equal(t, "monitoring", updates[1].Component.Name)
+	require.Equal(t, ProcessRunning, updates[1].Event)
+}
@@ -0,0 +1,126 @@
+package deployment
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/engine"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+)
+
+const defaultVerificationPeriod = time.Minute
+
+//isStagedUpgrade returns whether the components are upgraded in batches. A staged upgrade requires an installed Kyma.
+func (d *Deployment) isStagedUpgrade() (bool, error) {
+	if d.cfg.StagedUpgrade == nil {
+		return false, nil
+	}
+	return d.kymaInstalled()
+}
+
+//deployInStages upgrades the components in batches. The workloads of a deployed batch are verified for the verification period
+//before the next batch is upgraded. If a batch fails to deploy or to pass the verification, the remaining batches are not upgraded
+//and the components of the failed batch are rolled back if configured.
+func (d *Deployment) deployInStages(ctx context.Context, cancelFunc context.CancelFunc, eng *engine.Engine, deadlines deadlines) error {
+	batches := eng.Batches(d.cfg.StagedUpgrade.BatchSize)
+	for n, batch := range batches {
+		cmps := batch.Components()
+		names := make([]string, 0, len(cmps))
+		for _, comp := range cmps {
+			names = append(names, comp.Name)
+		}
+		args := messages.Args{"Batch": n + 1, "Batches": len(batches)}
+		d.cfg.Log.Info(d.cfg.Catalog().Text(messages.StagedUpgradeBatchStarted, messages.Args{"Batch": n + 1, "Batches": len(batches), "Components": strings.Join(names, ", ")}))
+
+		err := d.deployComponents(ctx, cancelFunc, InstallComponents, batch, deadlines)
+		if err == nil {
+			err = d.verifyBatch(ctx, cmps, deadlines)
+		}
+		if err != nil {
+			return d.stopStagedUpgrade(ctx, cmps, args, err)
+		}
+	}
+	return nil
+}
+
+//verifyBatch watches the workloads of the components for the verification period.
+//It returns an error listing the components which are degraded at the end of the period.
+//The verification ends early at the cancel deadline or on a shutdown request, and the returned error wraps ErrCancelled.
+func (d *Deployment) verifyBatch(ctx context.Context, cmps []components.KymaComponent, deadlines deadlines) error {
+	period := d.cfg.StagedUpgrade.VerificationPeriod
+	if period == 0 {
+		period = defaultVerificationPeriod
+	}
+	remaining := remainingDuration(d.clock.Now(), deadlines.cancel)
+	timeout := remaining < period
+	if timeout {
+		period = remaining
+	}
+	comps := make([]config.ComponentDefinition, 0, len(cmps))
+	for _, comp := range cmps {
+		comps = append(comps, config.ComponentDefinition{Name: comp.Name, Namespace: comp.Namespace})
+	}
+	d.cfg.Log.Info(d.cfg.Catalog().Text(messages.StagedUpgradeBatchVerifying, messages.Args{"Count": len(comps), "Period": period}))
+
+	watchCtx, cancel := d.shutdown.context(ctx)
+	defer cancel()
+	m := newComponentMonitor(d.core)
+	m.watch(watchCtx, comps, period)
+	if d.shutdown.requested() {
+		err := d.interrupted()
+		d.processUpdate(InstallComponents, ProcessTimeoutFailure, err)
+		return err
+	}
+	if timeout {
+		err := d.timeoutError(deadlines)
+		d.processUpdate(InstallComponents, ProcessTimeoutFailure, err)
+		return err
+	}
+	if failures := m.failures(comps); len(failures) > 0 {
+		return d.cfg.Catalog().New(messages.MonitoringComponentsDegraded, messages.Args{"Count": len(failures)}).Wrap(failures)
+	}
+	return nil
+}
+
+//stopStagedUpgrade pauses the upgrade after a failed batch or rolls the batch back, depending on the configured failure action.
+//Nothing is rolled back after a timeout, because the components may still be deployed.
+func (d *Deployment) stopStagedUpgrade(ctx context.Context, cmps []components.KymaComponent, args messages.Args, err error) error {
+	if ctx.Err() != nil || errors.Is(err, installerrors.ErrCancelled) {
+		return err
+	}
+	if d.cfg.StagedUpgrade.OnFailure != config.RollbackOnFailure {
+		return d.cfg.Catalog().New(messages.StagedUpgradePaused, args).Wrap(err)
+	}
+	d.cfg.Log.Errorf("Rolling back the batch after the failure: %v", err)
+	if rollbackErr := d.rollbackBatch(ctx, cmps); rollbackErr != nil {
+		return d.cfg.Catalog().New(messages.StagedUpgradeRollbackFailed, args).Wrap(rollbackErr)
+	}
+	return d.cfg.Catalog().New(messages.StagedUpgradeRolledBack, args).Wrap(err)
+}
+
+//rollbackBatch rolls the components back to the revision before the upgrade and reports their status
+func (d *Deployment) rollbackBatch(ctx context.Context, cmps []components.KymaComponent) error {
+	var failures installerrors.ComponentFailures
+	for _, comp := range cmps {
+		comp.Status = components.StatusRollingBack
+		comp.Error = nil
+		d.processUpdateComponent(InstallComponents, comp)
+		if err := comp.Rollback(ctx); err != nil {
+			comp.Status = components.StatusError
+			comp.Error = err
+			failures = append(failures, componentFailure(comp))
+		} else {
+			comp.Status = components.StatusInstalled
+		}
+		d.processUpdateComponent(InstallComponents, comp)
+	}
+	if len(failures) > 0 {
+		return failures
+	}
+	return nil
+}
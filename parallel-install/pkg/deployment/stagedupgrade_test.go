@@ -0,0 +1,165 @@
+package deployment
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/engine"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeployment_DeployInStages(t *testing.T) {
+
+	t.Run("All batches are upgraded", func(t *testing.T) {
+		d, eng, hc := newStagedDeployment(t, fake.NewSimpleClientset(), config.PauseOnFailure)
+
+		err := d.deployInStages(context.Background(), func() {}, eng, d.newDeadlines())
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"comp1", "comp2", "comp3", "comp4"}, hc.deployed)
+		require.Empty(t, hc.rolledBack)
+	})
+
+	t.Run("Upgrade pauses after a failed batch", func(t *testing.T) {
+		d, eng, hc := newStagedDeployment(t, fake.NewSimpleClientset(), config.PauseOnFailure, "comp1")
+
+		err := d.deployInStages(context.Background(), func() {}, eng, d.newDeadlines())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Staged upgrade paused because batch 1 of 2 failed")
+		var failure *installerrors.ErrComponentFailed
+		require.True(t, errors.As(err, &failure))
+		require.Equal(t, "comp1", failure.Component)
+		require.ElementsMatch(t, []string{"comp1", "comp2"}, hc.deployed) //the second batch is not upgraded
+		require.Empty(t, hc.rolledBack)
+	})
+
+	t.Run("Failed batch is rolled back", func(t *testing.T) {
+		d, eng, hc := newStagedDeployment(t, fake.NewSimpleClientset(), config.RollbackOnFailure, "comp3")
+
+		err := d.deployInStages(context.Background(), func() {}, eng, d.newDeadlines())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "batch 2 of 2 failed. The components of the batch were rolled back")
+		require.Equal(t, []string{"comp3", "comp4"}, hc.rolledBack)
+	})
+
+	t.Run("Degraded batch fails the verification", func(t *testing.T) {
+		replicas := int32(1)
+		kubeClient := fake.NewSimpleClientset(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "comp1-controller", Namespace: "kyma-system", Annotations: map[string]string{"meta.helm.sh/release-name": "comp1"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		})
+		d, eng, hc := newStagedDeployment(t, kubeClient, config.PauseOnFailure)
+
+		err := d.deployInStages(context.Background(), func() {}, eng, d.newDeadlines())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Staged upgrade paused because batch 1 of 2 failed")
+		var failure *installerrors.ErrComponentFailed
+		require.True(t, errors.As(err, &failure))
+		require.Equal(t, "comp1", failure.Component)
+		require.Contains(t, failure.Error(), "Deployment comp1-controller has 0 of 1 replicas available")
+		require.ElementsMatch(t, []string{"comp1", "comp2"}, hc.deployed)
+	})
+
+	t.Run("Verification ends at the cancel deadline", func(t *testing.T) {
+		d, _, hc := newStagedDeployment(t, fake.NewSimpleClientset(), config.RollbackOnFailure)
+		d.cfg.StagedUpgrade.VerificationPeriod = time.Hour
+		cmps := []components.KymaComponent{{Name: "comp1", Namespace: "kyma-system", HelmClient: hc, Log: d.cfg.Log}}
+
+		start := time.Now()
+		err := d.verifyBatch(context.Background(), cmps, deadlines{cancel: d.clock.Now().Add(10 * time.Millisecond)})
+		require.True(t, messages.Is(err, messages.DeploymentTimeout))
+		require.True(t, errors.Is(err, installerrors.ErrCancelled))
+		require.Less(t, time.Since(start).Milliseconds(), time.Minute.Milliseconds())
+
+		//nothing is rolled back after a timeout
+		err = d.stopStagedUpgrade(context.Background(), cmps, messages.Args{"Batch": 1, "Batches": 1}, err)
+		require.True(t, messages.Is(err, messages.DeploymentTimeout))
+		require.Empty(t, hc.rolledBack)
+	})
+
+	t.Run("Shutdown request ends the verification", func(t *testing.T) {
+		d, _, hc := newStagedDeployment(t, fake.NewSimpleClientset(), config.RollbackOnFailure)
+		d.cfg.StagedUpgrade.VerificationPeriod = time.Hour
+		cmps := []components.KymaComponent{{Name: "comp1", Namespace: "kyma-system", HelmClient: hc, Log: d.cfg.Log}}
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			d.Shutdown()
+		}()
+		err := d.verifyBatch(context.Background(), cmps, d.newDeadlines())
+		require.True(t, messages.Is(err, messages.DeploymentInterrupted))
+		require.True(t, errors.Is(err, installerrors.ErrCancelled))
+	})
+}
+
+func newStagedDeployment(t *testing.T, kubeClient *fake.Clientset, onFailure config.StagedUpgradeFailureAction, failing ...string) (*Deployment, *engine.Engine, *stagedHelmClient) {
+	cfg := &config.Config{
+		CancelTimeout: time.Minute,
+		QuitTimeout:   2 * time.Minute,
+		Log:           logger.NewLogger(true),
+		StagedUpgrade: &config.StagedUpgradeConfig{BatchSize: 2, VerificationPeriod: time.Millisecond, OnFailure: onFailure},
+	}
+	hc := &stagedHelmClient{failing: map[string]bool{}}
+	for _, name := range failing {
+		hc.failing[name] = true
+	}
+	eng := engine.NewEngine(&mockOverridesProvider{}, &stagedProvider{hc: hc}, engine.Config{WorkersCount: 2, Log: cfg.Log})
+	return &Deployment{newCore(cfg, &OverridesBuilder{}, kubeClient, nil)}, eng, hc
+}
+
+//stagedHelmClient fails the deployment of the given releases and records the deployments and rollbacks
+type stagedHelmClient struct {
+	failing    map[string]bool
+	mu         sync.Mutex
+	deployed   []string
+	rolledBack []string
+}
+
+func (c *stagedHelmClient) DeployRelease(ctx context.Context, chartDir, namespace, name string, overrides map[string]interface{}, profile string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deployed = append(c.deployed, name)
+	if c.failing[name] {
+		return errors.New("upgrade failed")
+	}
+	return nil
+}
+
+func (c *stagedHelmClient) UninstallRelease(ctx context.Context, namespace, name string) error {
+	return nil
+}
+
+func (c *stagedHelmClient) RollbackRelease(ctx context.Context, namespace, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rolledBack = append(c.rolledBack, name)
+	return nil
+}
+
+type stagedProvider struct {
+	hc *stagedHelmClient
+}
+
+func (p *stagedProvider) GetComponents() []components.KymaComponent {
+	var comps []components.KymaComponent
+	for _, name := range []string{"comp1", "comp2", "comp3", "comp4"} {
+		comps = append(comps, components.KymaComponent{
+			Name:            name,
+			Namespace:       "kyma-system",
+			OverridesGetter: func() map[string]interface{} { return nil },
+			HelmClient:      p.hc,
+			Log:             logger.NewLogger(true),
+		})
+	}
+	return comps
+}
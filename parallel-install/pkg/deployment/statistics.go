@@ -0,0 +1,101 @@
+package deployment
+
+import (
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/statistics"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultStatisticsNamespace = "kyma-installer"
+	defaultStatisticsConfigMap = "kyma-installation-statistics"
+
+	//StatisticsDeploy is the operation of deployment runs in the statistics
+	StatisticsDeploy = "deploy"
+	//StatisticsUninstall is the operation of uninstallation runs in the statistics
+	StatisticsUninstall = "uninstall"
+)
+
+//statisticsRecorder collects the statistics of a run and stores them when the run is finished.
+//A nil recorder records nothing.
+type statisticsRecorder struct {
+	store statistics.Store
+	log   logger.Interface
+	run   *statistics.Run
+}
+
+func newStatisticsRecorder(cfg *config.Config, kubeClient kubernetes.Interface) *statisticsRecorder {
+	if cfg.Statistics == nil {
+		return nil
+	}
+	return &statisticsRecorder{
+		store: newStatisticsStore(cfg.Statistics, kubeClient),
+		log:   cfg.Log,
+	}
+}
+
+func newStatisticsStore(cfg *config.StatisticsConfig, kubeClient kubernetes.Interface) statistics.Store {
+	if cfg.Directory != "" {
+		return statistics.NewFileStore(cfg.Directory)
+	}
+	namespace := cfg.Namespace
+	if namespace == "" {
+		namespace = defaultStatisticsNamespace
+	}
+	configMap := cfg.ConfigMap
+	if configMap == "" {
+		configMap = defaultStatisticsConfigMap
+	}
+	return statistics.NewConfigMapStore(kubeClient, namespace, configMap, cfg.MaxRuns)
+}
+
+func (r *statisticsRecorder) start(operation, version string) {
+	if r == nil {
+		return
+	}
+	r.run = statistics.NewRun(operation, version, time.Now())
+}
+
+func (r *statisticsRecorder) recordComponent(phase InstallationPhase, comp components.KymaComponent) {
+	if r == nil || r.run == nil || !processed(comp) {
+		return
+	}
+	stats := statistics.ComponentStats{
+		Name:      comp.Name,
+		Namespace: comp.Namespace,
+		Phase:     string(phase),
+		Duration:  comp.Duration,
+		Retries:   comp.Retries,
+		Failed:    comp.Status == components.StatusError,
+	}
+	if comp.Error != nil {
+		stats.Error = comp.Error.Error()
+	}
+	r.run.Record(stats)
+}
+
+//finish stores the statistics of the run. Errors are only logged, as the statistics must not affect the installation.
+func (r *statisticsRecorder) finish(err error) {
+	if r == nil || r.run == nil {
+		return
+	}
+	r.run.Finish(time.Now(), err)
+	if err := r.store.Save(r.run); err != nil {
+		r.log.Warnf("Failed to store the statistics of run '%s': %v", r.run.ID, err)
+	} else {
+		r.log.Infof("Stored statistics of run %s", r.run)
+	}
+	r.run = nil
+}
+
+//Statistics returns the store of the run statistics. It returns nil if statistics are disabled.
+func (i *core) Statistics() statistics.Store {
+	if i.statistics == nil {
+		return nil
+	}
+	return i.statistics.store
+}
@@ -0,0 +1,54 @@
+package deployment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCore_RecordStatistics(t *testing.T) {
+
+	t.Run("Component statistics are stored when the run is finished", func(t *testing.T) {
+		cfg := &config.Config{Log: logger.NewLogger(true), Statistics: &config.StatisticsConfig{}}
+		c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+
+		c.statistics.start(StatisticsDeploy, "1.20.0")
+		c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: "ok", Namespace: "kyma-system", Status: components.StatusInstalled, Duration: time.Minute, Retries: 1})
+		c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: "broken", Namespace: "kyma-system", Status: components.StatusError, Error: errors.New("chart failed")})
+		c.statistics.finish(errors.New("1 component failed"))
+
+		ids, err := c.Statistics().List()
+		require.NoError(t, err)
+		require.Len(t, ids, 1)
+
+		run, err := c.Statistics().Load(ids[0])
+		require.NoError(t, err)
+		require.Equal(t, StatisticsDeploy, run.Operation)
+		require.Equal(t, "1.20.0", run.Version)
+		require.Equal(t, "1 component failed", run.Error)
+		require.Equal(t, []string{"broken"}, run.Failures())
+
+		ok, found := run.Component("ok")
+		require.True(t, found)
+		require.Equal(t, time.Minute, ok.Duration)
+		require.Equal(t, 1, ok.Retries)
+		require.Equal(t, string(InstallComponents), ok.Phase)
+	})
+
+	t.Run("Nothing is recorded if disabled", func(t *testing.T) {
+		cfg := &config.Config{Log: logger.NewLogger(true)}
+		c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+
+		c.statistics.start(StatisticsDeploy, "1.20.0")
+		c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: "ok", Status: components.StatusInstalled})
+		c.statistics.finish(nil)
+
+		require.Nil(t, c.Statistics())
+	})
+}
@@ -0,0 +1,44 @@
+package deployment
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
+)
+
+//applyTenancy removes the cluster-scoped components, which are skipped or reused from the cluster-wide installation, from a tenant installation
+func applyTenancy(cfg *config.Config) {
+	if cfg.Tenancy == nil {
+		return
+	}
+	for _, component := range cfg.Tenancy.ClusterScoped() {
+		if cfg.Tenancy.IsShared(component) {
+			cfg.Log.Infof("Reusing shared component '%s' for tenant '%s'", component, cfg.Tenancy.Tenant)
+		} else {
+			cfg.Log.Infof("Skipping cluster-scoped component '%s' for tenant '%s'", component, cfg.Tenancy.Tenant)
+		}
+		cfg.ComponentList.Remove(component)
+	}
+}
+
+//checkSharedComponents verifies that the shared components of a tenant are installed by the cluster-wide installation
+func (d *Deployment) checkSharedComponents() error {
+	if d.cfg.Tenancy == nil || len(d.cfg.Tenancy.SharedComponents) == 0 {
+		return nil
+	}
+	versions, err := helm.GetKymaMetadataProvider(d.kubeClient).WithStorage(d.cfg.HelmStorage).WithTenant("").Versions()
+	if err != nil {
+		return err
+	}
+	installed := map[string]bool{}
+	for _, comp := range versions.InstalledComponents() {
+		installed[comp.Name] = true
+	}
+	for _, component := range d.cfg.Tenancy.SharedComponents {
+		if !installed[component] {
+			return fmt.Errorf("Shared component '%s' is not installed on the cluster: install it before deploying tenant '%s'", component, d.cfg.Tenancy.Tenant)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ApplyTenancy(t *testing.T) {
+	t.Run("Cluster-scoped components are removed", func(t *testing.T) {
+		compList, err := config.NewComponentList("../test/data/componentlist.yaml")
+		require.NoError(t, err)
+		cfg := &config.Config{
+			Log:           logger.NewLogger(true),
+			ComponentList: compList,
+			Tenancy: &config.TenancyConfig{
+				Tenant:                  "acme",
+				ClusterScopedComponents: []string{"prereqcomp1", "comp2"},
+				SharedComponents:        []string{"prereqcomp1"},
+			},
+		}
+
+		applyTenancy(cfg)
+
+		require.Equal(t, []config.ComponentDefinition{{Name: "prereqcomp2", Namespace: "testns"}}, cfg.ComponentList.Prerequisites)
+		require.Len(t, cfg.ComponentList.Components, 2)
+		for _, comp := range cfg.ComponentList.Components {
+			require.NotEqual(t, "comp2", comp.Name)
+		}
+
+		c := newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), nil)
+		rendered, err := c.componentList()
+		require.NoError(t, err)
+		require.Equal(t, config.ComponentDefinition{Name: "prereqcomp2", Namespace: "acme-testns", ReleaseName: "acme-prereqcomp2"}, rendered.Prerequisites[0])
+	})
+
+	t.Run("Disabled tenancy changes nothing", func(t *testing.T) {
+		compList, err := config.NewComponentList("../test/data/componentlist.yaml")
+		require.NoError(t, err)
+		cfg := &config.Config{Log: logger.NewLogger(true), ComponentList: compList}
+
+		applyTenancy(cfg)
+
+		require.Len(t, cfg.ComponentList.Prerequisites, 2)
+		require.Len(t, cfg.ComponentList.Components, 3)
+	})
+}
+
+func Test_CheckSharedComponents(t *testing.T) {
+	release := func(name, tenant string) *v1.Secret {
+		labels := map[string]string{
+			helm.KymaLabelPrefix + "name":         name,
+			helm.KymaLabelPrefix + "namespace":    "istio-system",
+			helm.KymaLabelPrefix + "component":    "true",
+			helm.KymaLabelPrefix + "version":      "2.0.0",
+			helm.KymaLabelPrefix + "operationID":  "opsid-" + tenant,
+			helm.KymaLabelPrefix + "creationTime": "1615831194",
+			helm.KymaLabelPrefix + "priority":     "1",
+			helm.KymaLabelPrefix + "prerequisite": "true",
+		}
+		if tenant != "" {
+			labels[helm.KymaLabelPrefix+"tenant"] = tenant
+		}
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sh.helm.release.v1." + name + ".v1",
+				Namespace: "istio-system",
+				Labels:    labels,
+			},
+		}
+	}
+	newDeployment := func(kubeClient *fake.Clientset) *Deployment {
+		cfg := &config.Config{
+			Log: logger.NewLogger(true),
+			Tenancy: &config.TenancyConfig{
+				Tenant:           "acme",
+				SharedComponents: []string{"istio"},
+			},
+		}
+		return &Deployment{newCore(cfg, &OverridesBuilder{}, kubeClient, nil)}
+	}
+
+	t.Run("Shared component is installed", func(t *testing.T) {
+		d := newDeployment(fake.NewSimpleClientset(release("istio", "")))
+		require.NoError(t, d.checkSharedComponents())
+	})
+
+	t.Run("Shared component is missing", func(t *testing.T) {
+		d := newDeployment(fake.NewSimpleClientset())
+		require.EqualError(t, d.checkSharedComponents(), "Shared component 'istio' is not installed on the cluster: install it before deploying tenant 'acme'")
+	})
+
+	t.Run("Releases of other tenants are not shared", func(t *testing.T) {
+		d := newDeployment(fake.NewSimpleClientset(release("istio", "other")))
+		require.Error(t, d.checkSharedComponents())
+	})
+}
@@ -0,0 +1,91 @@
+package deployment
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+)
+
+const defaultThrottleInterval = time.Second
+
+//updateThrottle drops process updates which are identical to an update delivered within the interval,
+//and limits the number of running updates delivered per second. The dropped updates are counted,
+//so that the next delivered update of the same kind reports them.
+type updateThrottle struct {
+	mu           sync.Mutex
+	interval     time.Duration
+	maxPerSecond int
+	//delivered is the time the last update was delivered, per update key
+	delivered map[string]time.Time
+	//suppressed is the number of dropped updates, per update key
+	suppressed map[string]int
+	//windowStart and windowCount track the running updates delivered in the current second
+	windowStart time.Time
+	windowCount int
+}
+
+func newUpdateThrottle(cfg *config.Config) *updateThrottle {
+	if cfg.UpdateThrottle == nil {
+		return nil
+	}
+	throttle := &updateThrottle{
+		interval:     cfg.UpdateThrottle.Interval,
+		maxPerSecond: cfg.UpdateThrottle.MaxPerSecond,
+		delivered:    map[string]time.Time{},
+		suppressed:   map[string]int{},
+	}
+	if throttle.interval == 0 {
+		throttle.interval = defaultThrottleInterval
+	}
+	return throttle
+}
+
+//send delivers the update unless it is dropped. The lock is held while delivering, so that updates keep their order.
+func (t *updateThrottle) send(update ProcessUpdate, now time.Time, deliver func(ProcessUpdate)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := updateKey(update)
+	if last, ok := t.delivered[key]; ok && now.Sub(last) < t.interval {
+		t.suppressed[key]++
+		return
+	}
+	if update.Event == ProcessRunning && !t.allow(now) {
+		t.suppressed[key]++
+		return
+	}
+	t.delivered[key] = now
+	update.Suppressed = t.suppressed[key]
+	delete(t.suppressed, key)
+	deliver(update)
+}
+
+//allow returns whether the rate limit permits another running update in the current second
+func (t *updateThrottle) allow(now time.Time) bool {
+	if t.maxPerSecond == 0 {
+		return true
+	}
+	if now.Sub(t.windowStart) >= time.Second {
+		t.windowStart = now
+		t.windowCount = 0
+	}
+	if t.windowCount >= t.maxPerSecond {
+		return false
+	}
+	t.windowCount++
+	return true
+}
+
+//updateKey identifies identical updates
+func updateKey(update ProcessUpdate) string {
+	var errMsg string
+	if update.Error != nil {
+		errMsg = update.Error.Error()
+	}
+	if update.Component.Error != nil {
+		errMsg += update.Component.Error.Error()
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s", update.Event, update.Phase, update.Component.Namespace, update.Component.Name, update.Component.Status, errMsg)
+}
@@ -0,0 +1,86 @@
+package deployment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCore_UpdateThrottle(t *testing.T) {
+
+	t.Run("Identical updates are coalesced", func(t *testing.T) {
+		var updates []ProcessUpdate
+		c := newThrottledCore(&config.UpdateThrottleConfig{Interval: time.Second}, func(update ProcessUpdate) {
+			updates = append(updates, update)
+		})
+		clock := newFakeClock()
+		c.clock = clock
+
+		retrying := components.KymaComponent{Name: "istio", Namespace: "istio-system", Status: components.StatusError, Error: errors.New("connection refused")}
+		for i := 0; i < 5; i++ {
+			c.processUpdateComponent(InstallComponents, retrying)
+		}
+		c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: "istio", Namespace: "istio-system", Status: components.StatusInstalled})
+		clock.Advance(time.Second)
+		c.processUpdateComponent(InstallComponents, retrying)
+
+		require.Len(t, updates, 3)
+		require.Equal(t, components.StatusError, updates[0].Component.Status)
+		require.Equal(t, 0, updates[0].Suppressed)
+		require.Equal(t, components.StatusInstalled, updates[1].Component.Status)
+		require.Equal(t, components.StatusError, updates[2].Component.Status)
+		require.Equal(t, 4, updates[2].Suppressed)
+	})
+
+	t.Run("Running updates are rate-limited", func(t *testing.T) {
+		var updates []ProcessUpdate
+		c := newThrottledCore(&config.UpdateThrottleConfig{MaxPerSecond: 2}, func(update ProcessUpdate) {
+			updates = append(updates, update)
+		})
+		clock := newFakeClock()
+		c.clock = clock
+
+		c.processUpdate(InstallComponents, ProcessStart, nil)
+		for _, name := range []string{"istio", "cluster-essentials", "serverless", "eventing"} {
+			c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: name, Status: components.StatusInstalled})
+		}
+		c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: "broken", Status: components.StatusError, Error: errors.New("chart failed")})
+		clock.Advance(time.Second)
+		c.processUpdateComponent(InstallComponents, components.KymaComponent{Name: "serverless", Status: components.StatusInstalled})
+
+		var events []string
+		for _, update := range updates {
+			events = append(events, string(update.Event)+":"+update.Component.Name)
+		}
+		require.Equal(t, []string{
+			"ProcessStart:",
+			"ProcessRunning:istio",
+			"ProcessRunning:cluster-essentials",
+			"ProcessExecutionFailure:broken",
+			"ProcessRunning:serverless",
+		}, events)
+		require.Equal(t, 1, updates[4].Suppressed)
+	})
+
+	t.Run("Updates are not throttled by default", func(t *testing.T) {
+		var updates []ProcessUpdate
+		c := newThrottledCore(nil, func(update ProcessUpdate) {
+			updates = append(updates, update)
+		})
+		for i := 0; i < 3; i++ {
+			c.processUpdate(InstallComponents, ProcessRunning, nil)
+		}
+		require.Len(t, updates, 3)
+	})
+}
+
+func newThrottledCore(throttle *config.UpdateThrottleConfig, processUpdates func(ProcessUpdate)) *core {
+	cfg := &config.Config{Log: logger.NewLogger(true), UpdateThrottle: throttle}
+	return newCore(cfg, &OverridesBuilder{}, fake.NewSimpleClientset(), processUpdates)
+}
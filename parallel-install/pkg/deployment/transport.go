@@ -0,0 +1,29 @@
+package deployment
+
+import (
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/download"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/git"
+)
+
+//applyTransport makes all outbound connections use the proxy, the CA bundles, and the TLS settings of the config.
+//Git and downloads use the transport of the config, and backups, metrics pushes, and the image check create their HTTP client from the config.
+//Kubernetes clients get the proxy by the kubeconfig and the TLS settings by the KubeconfigSource, and the Helm chart downloader reads the proxy from the environment and the TLS settings from the Helm config.
+func applyTransport(cfg *config.Config) error {
+	tlsConfig := cfg.OutboundTLS()
+	if cfg.Proxy == nil && tlsConfig == nil {
+		return nil
+	}
+	transport, err := cfg.Transport()
+	if err != nil {
+		return err
+	}
+	git.SetTransport(transport)
+	download.SetTransport(transport)
+	cfg.KubeconfigSource.Proxy = cfg.Proxy
+	cfg.KubeconfigSource.TLS = tlsConfig
+	if cfg.Proxy == nil {
+		return nil
+	}
+	return cfg.Proxy.Export()
+}
@@ -0,0 +1,160 @@
+package deployment
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/messages"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+//checkUnknownOverrides cross-checks the keys of the supplied overrides with the values of the component charts
+//and applies the unknown overrides policy to the keys which no chart defines. Defaults of the installer are not checked.
+func (d *Deployment) checkUnknownOverrides() error {
+	if d.cfg.UnknownOverrides == "" {
+		return nil
+	}
+	supplied, err := d.overrides.mergeSupplied(make(map[string]interface{}))
+	if err != nil {
+		return err
+	}
+	compList, err := d.componentList()
+	if err != nil {
+		return err
+	}
+	comps := append(append([]config.ComponentDefinition{}, compList.Prerequisites...), compList.Components...)
+	unknown, err := unknownOverrides(supplied, d.cfg.ResourcePath, comps)
+	if err != nil || len(unknown) == 0 {
+		return err
+	}
+
+	if d.cfg.UnknownOverrides == config.UnknownOverridesFail {
+		return &installerrors.ErrUnknownOverrides{Keys: unknown}
+	}
+	for _, key := range unknown {
+		d.cfg.Log.Warn(d.cfg.Catalog().Text(messages.UnknownOverride, messages.Args{"Key": key}))
+	}
+	return nil
+}
+
+//unknownOverrides returns the sorted keys of the overrides which are not defined in the values of the component charts.
+//Global overrides are checked against the global values of all charts and their subcharts.
+func unknownOverrides(supplied map[string]interface{}, resourcePath string, comps []config.ComponentDefinition) ([]string, error) {
+	global := make(map[string]interface{})
+	chartValues := make(map[string]map[string]interface{})
+	for _, comp := range comps {
+		ch, err := loader.Load(filepath.Join(resourcePath, comp.Name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to load the chart of component '%s'", comp.Name)
+		}
+		values, err := definedValues(ch, global)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read the values of component '%s'", comp.Name)
+		}
+		chartValues[comp.Name] = values
+	}
+
+	var unknown []string
+	for key, value := range supplied {
+		switch key {
+		case "global":
+			unknown = append(unknown, undefinedKeys(map[string]interface{}{key: value}, map[string]interface{}{key: global}, "")...)
+		case overrides.ComponentsKey:
+			scoped, _ := value.(map[string]interface{})
+			for comp, compValues := range scoped {
+				unknown = append(unknown, componentUndefinedKeys(overrides.ComponentsKey+"."+comp, compValues, chartValues, global, comp)...)
+			}
+		default:
+			unknown = append(unknown, componentUndefinedKeys(key, value, chartValues, global, key)...)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+//componentUndefinedKeys checks the overrides of a component, which can also contain global values for this component only
+func componentUndefinedKeys(prefix string, value interface{}, chartValues map[string]map[string]interface{}, global map[string]interface{}, comp string) []string {
+	values, found := chartValues[comp]
+	compValues, isMap := value.(map[string]interface{})
+	if !found || !isMap {
+		return []string{prefix}
+	}
+	defined := make(map[string]interface{}, len(values)+1)
+	for k, v := range values {
+		defined[k] = v
+	}
+	defined["global"] = global
+	return undefinedKeys(compValues, defined, prefix)
+}
+
+//undefinedKeys walks the values along the defined values and returns the paths of the keys which are not defined.
+//Defined values which are no map or an empty map accept any nested keys, e.g. podAnnotations: {}
+func undefinedKeys(values, defined map[string]interface{}, prefix string) []string {
+	var undefined []string
+	for key, value := range values {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		definedValue, found := defined[key]
+		if !found {
+			undefined = append(undefined, path)
+			continue
+		}
+		nested, isMap := value.(map[string]interface{})
+		definedNested, definedIsMap := definedValue.(map[string]interface{})
+		if isMap && definedIsMap && len(definedNested) > 0 {
+			undefined = append(undefined, undefinedKeys(nested, definedNested, path)...)
+		}
+	}
+	return undefined
+}
+
+//definedValues returns the values a chart consumes: its values, the values of its profiles,
+//and the values of its subcharts under their names or aliases. The global values of all of them are merged into global.
+func definedValues(ch *chart.Chart, global map[string]interface{}) (map[string]interface{}, error) {
+	values := overrides.MergeMaps(nil, ch.Values)
+	for _, f := range ch.Files {
+		if !strings.HasPrefix(f.Name, "profile-") || filepath.Ext(f.Name) != ".yaml" {
+			continue
+		}
+		profileValues, err := chartutil.ReadValues(f.Data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read profile '%s'", f.Name)
+		}
+		values = overrides.MergeMaps(values, profileValues)
+	}
+	if chartGlobal, ok := values["global"].(map[string]interface{}); ok {
+		for k, v := range overrides.MergeMaps(global, chartGlobal) {
+			global[k] = v
+		}
+	}
+
+	aliases := make(map[string]string)
+	if ch.Metadata != nil {
+		for _, dep := range ch.Metadata.Dependencies {
+			if dep.Alias != "" {
+				aliases[dep.Name] = dep.Alias
+			}
+		}
+	}
+	for _, sub := range ch.Dependencies() {
+		subValues, err := definedValues(sub, global)
+		if err != nil {
+			return nil, err
+		}
+		key := sub.Name()
+		if alias, ok := aliases[key]; ok {
+			key = alias
+		}
+		values = overrides.MergeMaps(values, map[string]interface{}{key: subValues})
+	}
+	return values, nil
+}
@@ -0,0 +1,110 @@
+package deployment
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeployment_CheckUnknownOverrides(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unknown-overrides-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFile := func(path, content string) {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+	writeFile("istio/Chart.yaml", "apiVersion: v2\nname: istio\nversion: 1.0.0\n")
+	writeFile("istio/values.yaml", "global:\n  domainName: example.com\n  proxy:\n    resources:\n      cpu: 100m\n")
+	writeFile("istio/charts/pilot/Chart.yaml", "apiVersion: v2\nname: pilot\nversion: 1.0.0\n")
+	writeFile("istio/charts/pilot/values.yaml", "replicas: 1\nglobal:\n  tracing: {}\n")
+	writeFile("serverless/Chart.yaml", "apiVersion: v2\nname: serverless\nversion: 1.0.0\n")
+	writeFile("serverless/values.yaml", "podAnnotations: {}\nwebhook:\n  enabled: true\n")
+	writeFile("serverless/profile-evaluation.yaml", "resources:\n  limits:\n    memory: 128Mi\n")
+
+	newDeployment := func(policy config.UnknownOverridesPolicy, ob *OverridesBuilder) *Deployment {
+		cfg := &config.Config{
+			Log:          logger.NewLogger(true),
+			ResourcePath: dir,
+			ComponentList: &config.ComponentList{
+				Prerequisites: []config.ComponentDefinition{{Name: "istio", Namespace: "istio-system"}},
+				Components:    []config.ComponentDefinition{{Name: "serverless", Namespace: "kyma-system"}},
+			},
+			UnknownOverrides: policy,
+		}
+		return &Deployment{newCore(cfg, ob, fake.NewSimpleClientset(), nil)}
+	}
+
+	validOverrides := func(t *testing.T) *OverridesBuilder {
+		ob := &OverridesBuilder{}
+		require.NoError(t, ob.AddOverrides("global", map[string]interface{}{
+			"domainName": "kyma.example.com",
+			"proxy":      map[string]interface{}{"resources": map[string]interface{}{"cpu": "200m"}},
+			"tracing":    map[string]interface{}{"enabled": true},
+		}))
+		require.NoError(t, ob.AddOverrides("istio", map[string]interface{}{
+			"pilot": map[string]interface{}{"replicas": 2},
+		}))
+		require.NoError(t, ob.AddOverrides("serverless", map[string]interface{}{
+			"podAnnotations": map[string]interface{}{"sidecar.istio.io/inject": "false"},
+			"resources":      map[string]interface{}{"limits": map[string]interface{}{"memory": "256Mi"}},
+		}))
+		require.NoError(t, ob.AddComponentOverrides("serverless", map[string]interface{}{
+			"global": map[string]interface{}{"domainName": "serverless.example.com"},
+		}))
+		return ob
+	}
+
+	invalidOverrides := func(t *testing.T) *OverridesBuilder {
+		ob := validOverrides(t)
+		require.NoError(t, ob.AddOverrides("global", map[string]interface{}{"domianName": "kyma.example.com"}))
+		require.NoError(t, ob.AddOverrides("serverless", map[string]interface{}{
+			"webhook": map[string]interface{}{"enabled": false, "enbled": false},
+		}))
+		require.NoError(t, ob.AddOverrides("monitoring", map[string]interface{}{"enabled": false}))
+		require.NoError(t, ob.AddComponentOverrides("istio", map[string]interface{}{"replicaz": 3}))
+		return ob
+	}
+
+	t.Run("Fail on unknown keys", func(t *testing.T) {
+		err := newDeployment(config.UnknownOverridesFail, invalidOverrides(t)).checkUnknownOverrides()
+		var unknown *installerrors.ErrUnknownOverrides
+		require.True(t, errors.As(err, &unknown))
+		require.Equal(t, []string{
+			"components.istio.replicaz",
+			"global.domianName",
+			"monitoring",
+			"serverless.webhook.enbled",
+		}, unknown.Keys)
+	})
+
+	t.Run("Known keys", func(t *testing.T) {
+		require.NoError(t, newDeployment(config.UnknownOverridesFail, validOverrides(t)).checkUnknownOverrides())
+	})
+
+	t.Run("Defaults are not checked", func(t *testing.T) {
+		ob := validOverrides(t)
+		ob.addDefaults(map[string]interface{}{"monitoring": map[string]interface{}{"enabled": false}})
+		require.NoError(t, newDeployment(config.UnknownOverridesFail, ob).checkUnknownOverrides())
+	})
+
+	t.Run("Warn on unknown keys", func(t *testing.T) {
+		require.NoError(t, newDeployment(config.UnknownOverridesWarn, invalidOverrides(t)).checkUnknownOverrides())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		d := newDeployment("", invalidOverrides(t))
+		d.cfg.ResourcePath = filepath.Join(dir, "missing")
+		require.NoError(t, d.checkUnknownOverrides())
+	})
+}
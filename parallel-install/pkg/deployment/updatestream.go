@@ -0,0 +1,114 @@
+package deployment
+
+import (
+	"sync"
+)
+
+//updateStreamBuffer is the number of process updates buffered in the channel before the process waits for the caller
+const updateStreamBuffer = 64
+
+//StartKymaDeploymentAsync deploys Kyma to a cluster in the background. Instead of passing a callback to NewDeployment,
+//range over the returned channel of process updates. The update channel is closed when the deployment has finished,
+//afterwards its result is sent to the error channel, which is closed as well. The result is nil if the deployment succeeded.
+//
+//The caller has to receive the updates until the channel is closed, as the deployment blocks if the buffer of the channel is full.
+//A callback passed to NewDeployment still receives all updates.
+func (d *Deployment) StartKymaDeploymentAsync() (<-chan ProcessUpdate, <-chan error) {
+	return d.streamUpdates(d.StartKymaDeployment)
+}
+
+//streamUpdates runs the process in the background and delivers its updates to the returned channel in addition to the callback.
+//Updates of workers which are still running when the process returned, e.g. after a force quit, are dropped.
+func (i *core) streamUpdates(process func() error) (<-chan ProcessUpdate, <-chan error) {
+	stream := i.streams.open()
+	result := make(chan error, 1)
+
+	go func() {
+		err := process()
+		i.streams.close(stream)
+
+		result <- err
+		close(result)
+	}()
+	return stream.updates, result
+}
+
+//updateStreams delivers the process updates to the channels of the running streamUpdates calls.
+//The callback of the core is not wrapped, so every update reaches it exactly once.
+type updateStreams struct {
+	mu      sync.Mutex
+	streams []*updateStream
+}
+
+//updateStream is the channel of a single streamUpdates call
+type updateStream struct {
+	updates chan ProcessUpdate
+	//closed when the process returned, so senders which wait for the caller give up
+	done chan struct{}
+	//senders which are delivering an update to the channel
+	sending sync.WaitGroup
+}
+
+func newUpdateStreams() *updateStreams {
+	return &updateStreams{}
+}
+
+//open adds a new stream which receives all updates until it is closed
+func (s *updateStreams) open() *updateStream {
+	stream := &updateStream{
+		updates: make(chan ProcessUpdate, updateStreamBuffer),
+		done:    make(chan struct{}),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streams = append(s.streams, stream)
+	return stream
+}
+
+//close removes the stream, drops the updates which wait for the caller, and closes the update channel
+func (s *updateStreams) close(stream *updateStream) {
+	s.mu.Lock()
+	for n, open := range s.streams {
+		if open == stream {
+			s.streams = append(s.streams[:n], s.streams[n+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	close(stream.done)
+	stream.sending.Wait()
+	close(stream.updates)
+}
+
+//active returns true if a stream is open
+func (s *updateStreams) active() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.streams) > 0
+}
+
+//send delivers the update to all open streams. The lock is not held while the update waits for a caller.
+func (s *updateStreams) send(update ProcessUpdate) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	open := make([]*updateStream, len(s.streams))
+	copy(open, s.streams)
+	for _, stream := range open {
+		stream.sending.Add(1)
+	}
+	s.mu.Unlock()
+
+	for _, stream := range open {
+		select {
+		case stream.updates <- update:
+		case <-stream.done:
+		}
+		stream.sending.Done()
+	}
+}
@@ -0,0 +1,122 @@
+package deployment
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/engine"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeployment_StreamUpdates(t *testing.T) {
+
+	t.Run("Updates are streamed until the deployment finished", func(t *testing.T) {
+		var mu sync.Mutex
+		callbackUpdates := 0
+		inst := newDeployment(t, func(ProcessUpdate) {
+			mu.Lock()
+			callbackUpdates++
+			mu.Unlock()
+		}, fake.NewSimpleClientset())
+
+		provider := &mockProvider{hc: &mockHelmClient{}}
+		overridesProvider := &mockOverridesProvider{}
+		prerequisitesEng := engine.NewEngine(overridesProvider, provider, engine.Config{
+			WorkersCount: 1,
+			Log:          logger.NewLogger(true),
+		})
+		componentsEng := engine.NewEngine(overridesProvider, provider, engine.Config{
+			WorkersCount: 2,
+			Log:          logger.NewLogger(true),
+		})
+
+		updates, result := inst.streamUpdates(func() error {
+			return inst.startKymaDeployment(overridesProvider, prerequisitesEng, componentsEng)
+		})
+
+		var received []ProcessUpdate
+		for update := range updates {
+			received = append(received, update)
+		}
+		require.NoError(t, <-result)
+		_, open := <-result
+		require.False(t, open)
+
+		require.Len(t, received, 10)
+		require.Equal(t, ProcessStart, received[0].Event)
+		require.Equal(t, InstallPreRequisites, received[0].Phase)
+		require.Equal(t, ProcessFinished, received[len(received)-1].Event)
+		require.Equal(t, InstallComponents, received[len(received)-1].Phase)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, 10, callbackUpdates)
+	})
+
+	t.Run("Failure before the first update", func(t *testing.T) {
+		inst := newDeployment(t, nil, fake.NewSimpleClientset())
+
+		updates, result := inst.streamUpdates(func() error {
+			return errors.New("maintenance window missed")
+		})
+
+		_, open := <-updates
+		require.False(t, open)
+		require.EqualError(t, <-result, "maintenance window missed")
+	})
+	t.Run("Every update is delivered once per call", func(t *testing.T) {
+		var mu sync.Mutex
+		callbackUpdates := 0
+		inst := newDeployment(t, func(ProcessUpdate) {
+			mu.Lock()
+			callbackUpdates++
+			mu.Unlock()
+		}, fake.NewSimpleClientset())
+
+		for call := 1; call <= 2; call++ {
+			updates, result := inst.streamUpdates(func() error {
+				inst.processUpdate(InstallComponents, ProcessStart, nil)
+				inst.processUpdate(InstallComponents, ProcessFinished, nil)
+				return nil
+			})
+
+			var received []ProcessUpdate
+			for update := range updates {
+				received = append(received, update)
+			}
+			require.NoError(t, <-result)
+			require.Len(t, received, 2)
+			require.Equal(t, ProcessStart, received[0].Event)
+			require.Equal(t, ProcessFinished, received[1].Event)
+
+			mu.Lock()
+			require.Equal(t, 2*call, callbackUpdates)
+			mu.Unlock()
+		}
+		require.False(t, inst.streams.active())
+	})
+
+	t.Run("Update waiting for the caller is dropped when the process returned", func(t *testing.T) {
+		inst := newDeployment(t, nil, fake.NewSimpleClientset())
+
+		updates, result := inst.streamUpdates(func() error {
+			for n := 0; n < updateStreamBuffer; n++ {
+				inst.processUpdate(InstallComponents, ProcessRunning, nil)
+			}
+			//a worker which is still running after the process returned
+			go inst.processUpdate(InstallComponents, ProcessRunning, nil)
+			return nil
+		})
+
+		require.NoError(t, <-result)
+		received := 0
+		for range updates {
+			received++
+		}
+		require.GreaterOrEqual(t, received, updateStreamBuffer)
+		require.LessOrEqual(t, received, updateStreamBuffer+1)
+	})
+}
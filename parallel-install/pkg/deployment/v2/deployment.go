@@ -0,0 +1,144 @@
+//Package deployment is the versioned API of the Kyma installer.
+//
+//Deployments and deletions are created with the config and a list of functional options,
+//so that new settings can be added as options without breaking the constructor signatures.
+//The constructors of the v1 package in pkg/deployment remain available and are used internally.
+package deployment
+
+import (
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	v1 "github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
+	"github.com/pkg/errors"
+)
+
+//Deployment deploys Kyma on a cluster
+type Deployment = v1.Deployment
+
+//Deletion removes Kyma from a cluster
+type Deletion = v1.Deletion
+
+//ProcessUpdate is sent for every phase transition and processed component
+type ProcessUpdate = v1.ProcessUpdate
+
+//OverridesBuilder collects the overrides of the installation
+type OverridesBuilder = v1.OverridesBuilder
+
+//Options of a deployment or deletion
+type Options struct {
+	//Config of the installation. It is copied, so that options do not modify the config of the caller.
+	Config *config.Config
+	//Overrides of the installation. Defaults to an empty OverridesBuilder.
+	Overrides *OverridesBuilder
+	//ProcessUpdates receives the updates of the running process. Optional.
+	ProcessUpdates func(ProcessUpdate)
+}
+
+//Option sets an option of a deployment or deletion
+type Option func(*Options)
+
+//WithOverrides sets the overrides of the installation
+func WithOverrides(ob *OverridesBuilder) Option {
+	return func(o *Options) {
+		o.Overrides = ob
+	}
+}
+
+//WithProcessUpdates sets the callback receiving the updates of the running process
+func WithProcessUpdates(processUpdates func(ProcessUpdate)) Option {
+	return func(o *Options) {
+		o.ProcessUpdates = processUpdates
+	}
+}
+
+//WithLogger sets the logger of the installation
+func WithLogger(log logger.Interface) Option {
+	return func(o *Options) {
+		o.Config.Log = log
+	}
+}
+
+//WithRetryPolicy sets the retry policy of Kubernetes operations
+func WithRetryPolicy(policy retry.Policy) Option {
+	return func(o *Options) {
+		o.Config.RetryPolicy = policy
+	}
+}
+
+//WithFeatureGates enables or disables features by name
+func WithFeatureGates(gates config.FeatureGates) Option {
+	return func(o *Options) {
+		o.Config.FeatureGates = gates
+	}
+}
+
+//NewOptions creates the options out of the config and applies the functional options
+func NewOptions(cfg *config.Config, opts ...Option) (Options, error) {
+	if cfg == nil {
+		return Options{}, errors.New("Config is undefined")
+	}
+	cfgCopy := *cfg
+	options := Options{Config: &cfgCopy}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.Overrides == nil {
+		options.Overrides = &OverridesBuilder{}
+	}
+	return options, nil
+}
+
+//NewDeployment creates a new Deployment instance for deploying Kyma on a cluster
+func NewDeployment(cfg *config.Config, opts ...Option) (*Deployment, error) {
+	options, err := NewOptions(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewDeploymentWithOptions(options)
+}
+
+//NewDeploymentWithOptions creates a new Deployment instance out of the options struct
+func NewDeploymentWithOptions(options Options) (*Deployment, error) {
+	if options.Config == nil {
+		return nil, errors.New("Config is undefined")
+	}
+	if options.Overrides == nil {
+		options.Overrides = &OverridesBuilder{}
+	}
+	return v1.NewDeployment(options.Config, options.Overrides, options.ProcessUpdates)
+}
+
+//NewDeletion creates a new Deletion instance for removing Kyma from a cluster.
+//Kubernetes operations are retried according to the retry policy of the config.
+func NewDeletion(cfg *config.Config, opts ...Option) (*Deletion, error) {
+	options, err := NewOptions(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewDeletionWithOptions(options)
+}
+
+//NewDeletionWithOptions creates a new Deletion instance out of the options struct
+func NewDeletionWithOptions(options Options) (*Deletion, error) {
+	if options.Config == nil {
+		return nil, errors.New("Config is undefined")
+	}
+	if options.Overrides == nil {
+		options.Overrides = &OverridesBuilder{}
+	}
+	return v1.NewDeletion(options.Config, options.Overrides, options.ProcessUpdates, nil)
+}
+
+//FromManifest creates a Deployment out of an installation manifest. The options are applied on top of the manifest.
+func FromManifest(path string, opts ...Option) (*Deployment, error) {
+	manifest, err := v1.LoadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, ob, err := manifest.Build()
+	if err != nil {
+		return nil, err
+	}
+	return NewDeployment(cfg, append([]Option{WithOverrides(ob)}, opts...)...)
+}
@@ -0,0 +1,60 @@
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOptions(t *testing.T) {
+
+	t.Run("Defaults", func(t *testing.T) {
+		cfg := &config.Config{WorkersCount: 4}
+		options, err := NewOptions(cfg)
+		require.NoError(t, err)
+		require.Equal(t, 4, options.Config.WorkersCount)
+		require.NotNil(t, options.Overrides)
+		require.Nil(t, options.ProcessUpdates)
+	})
+
+	t.Run("Options are applied to a copy of the config", func(t *testing.T) {
+		cfg := &config.Config{WorkersCount: 4}
+		ob := &OverridesBuilder{}
+		log := logger.NewLogger(true)
+		policy := retry.Fixed(time.Second, 5)
+		var updates []ProcessUpdate
+
+		options, err := NewOptions(cfg,
+			WithOverrides(ob),
+			WithLogger(log),
+			WithRetryPolicy(policy),
+			WithFeatureGates(config.FeatureGates{string(config.ServerSideApply): true}),
+			WithProcessUpdates(func(update ProcessUpdate) { updates = append(updates, update) }),
+		)
+		require.NoError(t, err)
+		require.Same(t, ob, options.Overrides)
+		require.Equal(t, log, options.Config.Log)
+		require.Equal(t, policy, options.Config.RetryPolicy)
+		require.True(t, options.Config.FeatureGates.Enabled(config.ServerSideApply))
+		require.NotNil(t, options.ProcessUpdates)
+
+		require.Nil(t, cfg.Log)
+		require.Nil(t, cfg.RetryPolicy)
+		require.Nil(t, cfg.FeatureGates)
+	})
+
+	t.Run("Config is required", func(t *testing.T) {
+		_, err := NewOptions(nil)
+		require.Error(t, err)
+
+		_, err = NewDeploymentWithOptions(Options{})
+		require.Error(t, err)
+
+		_, err = NewDeletionWithOptions(Options{})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,268 @@
+//Package domain provisions DNS records and TLS certificates for the Kyma domain before Kyma gets deployed.
+//
+//The resulting domain name and certificate are passed to the deployment as overrides,
+//so the installer does not fall back to its default domain and self-signed certificate.
+package domain
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/watcher"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultNamespace  = "kube-system"
+	defaultSecretName = "kyma-domain-tls"
+	defaultTimeout    = 10 * time.Minute
+)
+
+var (
+	gardenerCertificateResource = schema.GroupVersionResource{Group: "cert.gardener.cloud", Version: "v1alpha1", Resource: "certificates"}
+	certManagerResource         = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	dnsEndpointResource         = schema.GroupVersionResource{Group: "externaldns.k8s.io", Version: "v1alpha1", Resource: "dnsendpoints"}
+)
+
+//Bootstrapper provisions the DNS records and the TLS certificate of the Kyma domain
+type Bootstrapper interface {
+	//Bootstrap blocks until the certificate is issued and returns the domain and its certificate
+	Bootstrap() (*Result, error)
+}
+
+//Result contains the domain name and the PEM encoded TLS certificate and key issued for it
+type Result struct {
+	Domain  string
+	TLSCert []byte
+	TLSKey  []byte
+}
+
+//ApplyTo adds the domain name and the TLS certificate as global overrides to the builder
+func (r *Result) ApplyTo(ob *deployment.OverridesBuilder) error {
+	if r.Domain == "" {
+		return fmt.Errorf("domain name is empty")
+	}
+	overrides := map[string]interface{}{
+		"domainName": r.Domain,
+		"ingress": map[string]interface{}{
+			"domainName": r.Domain,
+		},
+	}
+	if len(r.TLSCert) > 0 && len(r.TLSKey) > 0 {
+		overrides["tlsCrt"] = base64.StdEncoding.EncodeToString(r.TLSCert)
+		overrides["tlsKey"] = base64.StdEncoding.EncodeToString(r.TLSKey)
+	}
+	return ob.AddOverrides("global", overrides)
+}
+
+//Options are shared by all bootstrappers
+type Options struct {
+	//Namespace the certificate resources and the TLS secret are created in. Defaults to kube-system.
+	Namespace string
+	//SecretName is the name of the TLS secret the issued certificate gets stored in. Defaults to kyma-domain-tls.
+	SecretName string
+	//Timeout is the maximum time to wait for the certificate to be issued. Defaults to 10 minutes.
+	Timeout time.Duration
+}
+
+func (o *Options) setDefaults() {
+	if o.Namespace == "" {
+		o.Namespace = defaultNamespace
+	}
+	if o.SecretName == "" {
+		o.SecretName = defaultSecretName
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = defaultTimeout
+	}
+}
+
+//GardenerBootstrapper uses the DNS and certificate extensions of a Gardener shoot.
+//The shoot domain is served by Gardener, so only the certificate has to be requested.
+type GardenerBootstrapper struct {
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	ops           Options
+}
+
+//NewGardenerBootstrapper creates a new GardenerBootstrapper instance
+func NewGardenerBootstrapper(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, ops Options) *GardenerBootstrapper {
+	ops.setDefaults()
+	return &GardenerBootstrapper{
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+		ops:           ops,
+	}
+}
+
+//Bootstrap requests a wildcard certificate for the shoot domain and waits until it is issued
+func (b *GardenerBootstrapper) Bootstrap() (*Result, error) {
+	configMap, err := b.kubeClient.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), "shoot-info", metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the shoot-info configmap, make sure the cluster is a Gardener shoot")
+	}
+	domainName := configMap.Data["domain"]
+	if domainName == "" {
+		return nil, fmt.Errorf("domain is empty in %s configmap", "shoot-info")
+	}
+
+	certificate := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "cert.gardener.cloud/v1alpha1",
+			"kind":       "Certificate",
+			"metadata": map[string]interface{}{
+				"name":      b.ops.SecretName,
+				"namespace": b.ops.Namespace,
+			},
+			"spec": map[string]interface{}{
+				"commonName": fmt.Sprintf("*.%s", domainName),
+				"secretRef": map[string]interface{}{
+					"name":      b.ops.SecretName,
+					"namespace": b.ops.Namespace,
+				},
+			},
+		},
+	}
+	if err := apply(b.dynamicClient, gardenerCertificateResource, certificate); err != nil {
+		return nil, errors.Wrap(err, "could not request the certificate")
+	}
+
+	return waitForCertificate(b.kubeClient, domainName, b.ops)
+}
+
+//CertManagerBootstrapper requests the certificate from cert-manager, for example from a Let's Encrypt issuer using DNS-01 challenges.
+//If DNS targets are given, a DNSEndpoint resource is created so that an external-dns compatible controller publishes the wildcard record.
+type CertManagerBootstrapper struct {
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	ops           Options
+	//Domain is the Kyma domain
+	Domain string
+	//Issuer is the name of the cert-manager ClusterIssuer signing the certificate
+	Issuer string
+	//DNSTargets are the IP addresses the wildcard record of the domain points to
+	DNSTargets []string
+}
+
+//NewCertManagerBootstrapper creates a new CertManagerBootstrapper instance
+func NewCertManagerBootstrapper(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, domain, issuer string, dnsTargets []string, ops Options) *CertManagerBootstrapper {
+	ops.setDefaults()
+	return &CertManagerBootstrapper{
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+		ops:           ops,
+		Domain:        domain,
+		Issuer:        issuer,
+		DNSTargets:    dnsTargets,
+	}
+}
+
+//Bootstrap publishes the DNS record, requests a wildcard certificate for the domain and waits until it is issued
+func (b *CertManagerBootstrapper) Bootstrap() (*Result, error) {
+	if b.Domain == "" {
+		return nil, fmt.Errorf("domain name is empty")
+	}
+	if b.Issuer == "" {
+		return nil, fmt.Errorf("issuer is empty")
+	}
+	wildcard := fmt.Sprintf("*.%s", b.Domain)
+
+	if len(b.DNSTargets) > 0 {
+		targets := make([]interface{}, 0, len(b.DNSTargets))
+		for _, t := range b.DNSTargets {
+			targets = append(targets, t)
+		}
+		endpoint := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "externaldns.k8s.io/v1alpha1",
+				"kind":       "DNSEndpoint",
+				"metadata": map[string]interface{}{
+					"name":      b.ops.SecretName,
+					"namespace": b.ops.Namespace,
+				},
+				"spec": map[string]interface{}{
+					"endpoints": []interface{}{
+						map[string]interface{}{
+							"dnsName":    wildcard,
+							"recordType": "A",
+							"targets":    targets,
+						},
+					},
+				},
+			},
+		}
+		if err := apply(b.dynamicClient, dnsEndpointResource, endpoint); err != nil {
+			return nil, errors.Wrap(err, "could not create the DNS record")
+		}
+	}
+
+	certificate := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "cert-manager.io/v1",
+			"kind":       "Certificate",
+			"metadata": map[string]interface{}{
+				"name":      b.ops.SecretName,
+				"namespace": b.ops.Namespace,
+			},
+			"spec": map[string]interface{}{
+				"secretName": b.ops.SecretName,
+				"dnsNames":   []interface{}{b.Domain, wildcard},
+				"issuerRef": map[string]interface{}{
+					"name": b.Issuer,
+					"kind": "ClusterIssuer",
+				},
+			},
+		},
+	}
+	if err := apply(b.dynamicClient, certManagerResource, certificate); err != nil {
+		return nil, errors.Wrap(err, "could not request the certificate")
+	}
+
+	return waitForCertificate(b.kubeClient, b.Domain, b.ops)
+}
+
+//apply creates the resource or updates its spec if it already exists
+func apply(dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	client := dynamicClient.Resource(gvr).Namespace(obj.GetNamespace())
+	_, err := client.Create(context.TODO(), obj, metav1.CreateOptions{})
+	if err == nil || !apierr.IsAlreadyExists(err) {
+		return err
+	}
+
+	current, err := client.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	current.Object["spec"] = obj.Object["spec"]
+	_, err = client.Update(context.TODO(), current, metav1.UpdateOptions{})
+	return err
+}
+
+//waitForCertificate blocks until the TLS secret of the certificate contains a certificate and a key.
+//The secret is watched, so the certificate is returned as soon as it is issued.
+func waitForCertificate(kubeClient kubernetes.Interface, domainName string, ops Options) (*Result, error) {
+	result := &Result{Domain: domainName}
+	err := watcher.Until(watcher.Secret(kubeClient, ops.Namespace, ops.SecretName), &v1.Secret{}, ops.Timeout, func(objects []interface{}) (bool, error) {
+		secret, ok := watcher.Find(objects, ops.SecretName).(*v1.Secret)
+		if !ok {
+			return false, nil
+		}
+		result.TLSCert = secret.Data["tls.crt"]
+		result.TLSKey = secret.Data["tls.key"]
+		return len(result.TLSCert) > 0 && len(result.TLSKey) > 0, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "certificate for domain '%s' was not issued", domainName)
+	}
+	return result, nil
+}
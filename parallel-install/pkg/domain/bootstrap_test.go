@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGardenerBootstrapper(t *testing.T) {
+	t.Run("Should request certificate for shoot domain", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(
+			&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "shoot-info", Namespace: "kube-system"},
+				Data:       map[string]string{"domain": "my.shoot.example.com"},
+			},
+			fixTLSSecret(defaultNamespace, defaultSecretName),
+		)
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+		result, err := NewGardenerBootstrapper(kubeClient, dynamicClient, Options{}).Bootstrap()
+		require.NoError(t, err)
+		require.Equal(t, "my.shoot.example.com", result.Domain)
+		require.Equal(t, []byte("crt"), result.TLSCert)
+		require.Equal(t, []byte("key"), result.TLSKey)
+
+		cert, err := dynamicClient.Resource(gardenerCertificateResource).Namespace(defaultNamespace).Get(context.TODO(), defaultSecretName, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "*.my.shoot.example.com", cert.Object["spec"].(map[string]interface{})["commonName"])
+	})
+
+	t.Run("Should fail on non-Gardener clusters", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+		_, err := NewGardenerBootstrapper(kubeClient, dynamicClient, Options{}).Bootstrap()
+		require.Error(t, err)
+	})
+}
+
+func TestCertManagerBootstrapper(t *testing.T) {
+	t.Run("Should create DNS record and certificate", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(fixTLSSecret("kyma-system", "tls"))
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+		b := NewCertManagerBootstrapper(kubeClient, dynamicClient, "kyma.example.com", "letsencrypt", []string{"1.2.3.4"},
+			Options{Namespace: "kyma-system", SecretName: "tls"})
+		result, err := b.Bootstrap()
+		require.NoError(t, err)
+		require.Equal(t, "kyma.example.com", result.Domain)
+
+		_, err = dynamicClient.Resource(dnsEndpointResource).Namespace("kyma-system").Get(context.TODO(), "tls", metav1.GetOptions{})
+		require.NoError(t, err)
+		_, err = dynamicClient.Resource(certManagerResource).Namespace("kyma-system").Get(context.TODO(), "tls", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("Should time out if certificate is not issued", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+		b := NewCertManagerBootstrapper(kubeClient, dynamicClient, "kyma.example.com", "letsencrypt", nil, Options{Timeout: time.Millisecond})
+		_, err := b.Bootstrap()
+		require.Error(t, err)
+	})
+
+	t.Run("Should fail without issuer", func(t *testing.T) {
+		b := NewCertManagerBootstrapper(nil, nil, "kyma.example.com", "", nil, Options{})
+		_, err := b.Bootstrap()
+		require.Error(t, err)
+	})
+}
+
+func TestResultApplyTo(t *testing.T) {
+	ob := &deployment.OverridesBuilder{}
+	result := &Result{Domain: "kyma.example.com", TLSCert: []byte("crt"), TLSKey: []byte("key")}
+	require.NoError(t, result.ApplyTo(ob))
+
+	overrides, err := ob.Raw()
+	require.NoError(t, err)
+
+	domain, ok := overrides.Find("global.domainName")
+	require.True(t, ok)
+	require.Equal(t, "kyma.example.com", domain)
+
+	crt, ok := overrides.Find("global.tlsCrt")
+	require.True(t, ok)
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte("crt")), crt)
+}
+
+func fixTLSSecret(namespace, name string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data: map[string][]byte{
+			"tls.crt": []byte("crt"),
+			"tls.key": []byte("key"),
+		},
+	}
+}
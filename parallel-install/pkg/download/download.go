@@ -12,6 +12,13 @@ import (
 	"github.com/pkg/errors"
 )
 
+var transport http.RoundTripper = http.DefaultTransport
+
+// SetTransport makes all downloads use the given transport, for example to connect through a proxy or to trust additional CAs.
+func SetTransport(t http.RoundTripper) {
+	transport = t
+}
+
 // GetFile downloads a file. Destination directory will be created if it does not exist.
 // It returns the path to the downloaded file.
 // If the provided file is not a URL, it checks if it exists locally
@@ -57,7 +64,8 @@ func GetFiles(files []string, dstDir string) ([]string, error) {
 // RemoteReader returns a reader to a remote file.
 func RemoteReader(path string) (io.ReadCloser, error) {
 	client := &http.Client{
-		Timeout: 5 * time.Second,
+		Transport: transport,
+		Timeout:   5 * time.Second,
 	}
 	// nolint: gosec
 	resp, err := client.Get(path)
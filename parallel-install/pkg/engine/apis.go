@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	defaultRequiredAPIsTimeout = 5 * time.Minute
+	requiredAPIsPollInterval   = 2 * time.Second
+)
+
+//APIChecker checks whether the cluster serves an API
+type APIChecker interface {
+	Served(api config.APIRequirement) (bool, error)
+}
+
+//DiscoveryAPIChecker checks the APIs with the discovery API of the cluster
+type DiscoveryAPIChecker struct {
+	Discovery discovery.DiscoveryInterface
+}
+
+//Served implements APIChecker.Served
+func (c *DiscoveryAPIChecker) Served(api config.APIRequirement) (bool, error) {
+	resources, err := c.Discovery.ServerResourcesForGroupVersion(api.GroupVersion())
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if api.Resource() == "" {
+		return true, nil
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == api.Resource() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+//awaitRequiredAPIs waits until the APIs required by the component are served.
+//While other components are pending which do not wait for APIs, the component is put back to the queue of the worker
+//and false is returned, so that the worker processes them first. They may install the CRDs of the required APIs.
+//An error is returned if the APIs are not served within the timeout.
+func (e *Engine) awaitRequiredAPIs(ctx context.Context, id int, sched *scheduler, component components.KymaComponent) (bool, error) {
+	if len(component.RequiredAPIs) == 0 || e.cfg.APIs == nil {
+		return true, nil
+	}
+	timeout := e.cfg.RequiredAPIsTimeout
+	if timeout <= 0 {
+		timeout = defaultRequiredAPIsTimeout
+	}
+	for {
+		missing, err := e.missingAPIs(component)
+		if err != nil {
+			return false, err
+		}
+		if len(missing) == 0 {
+			sched.served(component.Name)
+			return true, nil
+		}
+		since := sched.awaiting(component.Name, e.clock().Now())
+		if e.clock().Since(since) >= timeout {
+			sched.served(component.Name)
+			return false, fmt.Errorf("Required APIs %s are not served after %v", strings.Join(missing, ", "), timeout)
+		}
+		if sched.requeue(id, component) {
+			e.cfg.Log.Infof("%s Component %s waits for the APIs %s: processing other components first", logPrefix, component.Name, strings.Join(missing, ", "))
+			return false, nil
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-e.clock().After(requiredAPIsPollInterval):
+		}
+	}
+}
+
+//missingAPIs returns the required APIs of the component which are not served
+func (e *Engine) missingAPIs(component components.KymaComponent) ([]string, error) {
+	var missing []string
+	for _, api := range component.RequiredAPIs {
+		served, err := e.cfg.APIs.Served(api)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to check whether API '%s' is served: %v", api, err)
+		}
+		if !served {
+			missing = append(missing, string(api))
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+//crdHelmClient serves the APIs of a component once it is deployed
+type crdHelmClient struct {
+	mu       sync.Mutex
+	provides map[string]config.APIRequirement
+	served   map[config.APIRequirement]bool
+	deployed []string
+}
+
+func (c *crdHelmClient) DeployRelease(ctx context.Context, chartDir, namespace, name string, overrides map[string]interface{}, profile string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deployed = append(c.deployed, name)
+	if api, ok := c.provides[name]; ok {
+		c.served[api] = true
+	}
+	return nil
+}
+
+func (c *crdHelmClient) UninstallRelease(ctx context.Context, namespace, name string) error {
+	return nil
+}
+
+func (c *crdHelmClient) Served(api config.APIRequirement) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.served[api], nil
+}
+
+type apiComponentsProvider struct {
+	components []components.KymaComponent
+}
+
+func (p *apiComponentsProvider) GetComponents() []components.KymaComponent {
+	return p.components
+}
+
+func TestRequiredAPIs(t *testing.T) {
+	virtualServices := config.APIRequirement("networking.istio.io/v1alpha3/virtualservices")
+	newComponent := func(name string, hc *crdHelmClient, apis ...config.APIRequirement) components.KymaComponent {
+		return components.KymaComponent{
+			Name:            name,
+			Namespace:       "test",
+			RequiredAPIs:    apis,
+			OverridesGetter: func() map[string]interface{} { return nil },
+			HelmClient:      hc,
+			Log:             logger.NewLogger(true),
+		}
+	}
+
+	t.Run("Components wait for the APIs installed by other components", func(t *testing.T) {
+		hc := &crdHelmClient{
+			provides: map[string]config.APIRequirement{"istio": virtualServices},
+			served:   map[config.APIRequirement]bool{},
+		}
+		provider := &apiComponentsProvider{components: []components.KymaComponent{
+			newComponent("api-gateway", hc, virtualServices),
+			newComponent("istio", hc),
+		}}
+		e := NewEngine(&mockOverridesProvider{}, provider, Config{
+			WorkersCount: 1,
+			Log:          logger.NewLogger(true),
+			APIs:         hc,
+		})
+
+		statusChan, err := e.Deploy(context.TODO())
+		require.NoError(t, err)
+		for cmp := range statusChan {
+			require.Equal(t, components.StatusInstalled, cmp.Status)
+		}
+		require.Equal(t, []string{"istio", "api-gateway"}, hc.deployed)
+	})
+
+	t.Run("Components fail if the APIs are not served within the timeout", func(t *testing.T) {
+		hc := &crdHelmClient{served: map[config.APIRequirement]bool{}}
+		provider := &apiComponentsProvider{components: []components.KymaComponent{
+			newComponent("api-gateway", hc, virtualServices),
+		}}
+		e := NewEngine(&mockOverridesProvider{}, provider, Config{
+			WorkersCount:        1,
+			Log:                 logger.NewLogger(true),
+			Clock:               &steppingClock{step: time.Hour},
+			APIs:                hc,
+			RequiredAPIsTimeout: time.Minute,
+		})
+
+		statusChan, err := e.Deploy(context.TODO())
+		require.NoError(t, err)
+		cmp := <-statusChan
+		require.Equal(t, components.StatusError, cmp.Status)
+		require.Contains(t, cmp.Error.Error(), string(virtualServices))
+		require.Empty(t, hc.deployed)
+	})
+}
+
+func TestDiscoveryAPIChecker(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "networking.istio.io/v1alpha3",
+		APIResources: []metav1.APIResource{{Name: "virtualservices", Kind: "VirtualService"}},
+	}}
+	checker := &DiscoveryAPIChecker{Discovery: kubeClient.Discovery()}
+
+	served, err := checker.Served("networking.istio.io/v1alpha3")
+	require.NoError(t, err)
+	require.True(t, served)
+
+	served, err = checker.Served("networking.istio.io/v1alpha3/virtualservices")
+	require.NoError(t, err)
+	require.True(t, served)
+
+	served, err = checker.Served("networking.istio.io/v1alpha3/gateways")
+	require.NoError(t, err)
+	require.False(t, served)
+}
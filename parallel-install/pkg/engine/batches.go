@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+)
+
+//batchProvider provides a fixed batch of components
+type batchProvider struct {
+	components []components.KymaComponent
+}
+
+func (p *batchProvider) GetComponents() []components.KymaComponent {
+	return p.components
+}
+
+//Components returns the components processed by the engine
+func (e *Engine) Components() []components.KymaComponent {
+	return e.componentsProvider.GetComponents()
+}
+
+//Batches splits the components into batches of the given size in the order of the components provider.
+//It returns an engine with the configuration of this engine for every batch.
+//All components are in one batch if the size is not positive.
+func (e *Engine) Batches(size int) []*Engine {
+	cmps := e.Components()
+	if size <= 0 {
+		size = len(cmps)
+	}
+	var batches []*Engine
+	for start := 0; start < len(cmps); start += size {
+		end := start + size
+		if end > len(cmps) {
+			end = len(cmps)
+		}
+		batches = append(batches, NewEngine(e.overridesProvider, &batchProvider{components: cmps[start:end]}, e.cfg))
+	}
+	return batches
+}
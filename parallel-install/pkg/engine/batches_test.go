@@ -0,0 +1,34 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatches(t *testing.T) {
+	eng := NewEngine(&mockOverridesProvider{}, &mockComponentsProvider{t: t, hc: &mockSimpleHelmClient{}}, Config{WorkersCount: 2, Log: logger.NewLogger(true)})
+
+	t.Run("Components are split in order", func(t *testing.T) {
+		batches := eng.Batches(4)
+		require.Len(t, batches, 2)
+
+		var names [][]string
+		for _, batch := range batches {
+			var batchNames []string
+			for _, comp := range batch.Components() {
+				batchNames = append(batchNames, comp.Name)
+			}
+			names = append(names, batchNames)
+			require.Equal(t, eng.cfg.WorkersCount, batch.cfg.WorkersCount)
+		}
+		require.Equal(t, [][]string{{"test0", "test1", "test2", "test3"}, {"test4", "test5"}}, names)
+	})
+
+	t.Run("All components are in one batch without size", func(t *testing.T) {
+		batches := eng.Batches(0)
+		require.Len(t, batches, 1)
+		require.Len(t, batches[0].Components(), len(testComponentsNames))
+	})
+}
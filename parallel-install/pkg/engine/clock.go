@@ -0,0 +1,31 @@
+package engine
+
+import "time"
+
+//Clock is the time source of the engine and of the timeout handling of deployments and uninstallations.
+//It can be replaced to test time-dependent logic deterministically.
+type Clock interface {
+	//Now returns the current time
+	Now() time.Time
+	//Since returns the time elapsed since t
+	Since(t time.Time) time.Duration
+	//After returns a channel which receives the current time once the duration elapsed
+	After(d time.Duration) <-chan time.Time
+}
+
+//RealClock is the Clock of the system time
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
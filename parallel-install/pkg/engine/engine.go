@@ -10,10 +10,13 @@ package engine
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
 
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
 )
 
@@ -29,6 +32,15 @@ type installationType string
 type Config struct {
 	WorkersCount int              //Number of parallel processes for install/uninstall operations
 	Log          logger.Interface //Logger to be used
+	Durations    *Durations       //Observed component durations used for scheduling (optional). The engine records the durations of processed components.
+	Clock        Clock            //Time source of the component durations (optional). Defaults to RealClock.
+	//APIs checks whether the APIs required by components are served before they are deployed (optional). Required APIs are not checked if nil.
+	APIs APIChecker
+	//RequiredAPIsTimeout is the maximum time a component waits for its required APIs. Defaults to 5 minutes.
+	RequiredAPIsTimeout time.Duration
+	//ReportProgress sends the intermediate statuses of the components through the status channel as well:
+	//Pending when the processing starts, the phases of a deployment, and Skipped for the components which are not processed after a cancellation.
+	ReportProgress bool
 }
 
 //Engine implements Installation interface
@@ -113,84 +125,123 @@ func (e *Engine) Uninstall(ctx context.Context) (<-chan components.KymaComponent
 
 //Blocking function used to spawn a configured number of workers and then await their completion.
 func (e *Engine) run(ctx context.Context, statusChan chan<- components.KymaComponent, cmps []components.KymaComponent, installType installationType) {
-	//TODO: Size dependent on number of components?
-	jobChan := make(chan components.KymaComponent, 30)
-
-	//Fill the queue with jobs
-	for _, comp := range cmps {
-		if !e.enqueueJob(comp, jobChan) {
-			e.cfg.Log.Errorf("%s Max capacity reached, component dismissed: %s", logPrefix, comp.Name)
+	sched := newScheduler(cmps, e.cfg.WorkersCount, e.cfg.Durations, installType)
+	if e.cfg.ReportProgress {
+		for _, component := range cmps {
+			component.Status = components.StatusPending
+			statusChan <- component
 		}
 	}
 
 	//Spawn workers
 	var wg sync.WaitGroup
-
-	//TODO: Configurable number of workers
 	for i := 0; i < e.cfg.WorkersCount; i++ {
 		wg.Add(1)
-		go e.worker(ctx, &wg, jobChan, statusChan, installType)
+		go e.worker(ctx, &wg, i, sched, statusChan, installType)
 	}
 
-	// to stop the workers, first close the job channel
-	close(jobChan)
-
 	// block until workers quit
 	wg.Wait()
 }
 
 //Non-blocking worker.
-//Designed to run in parallel (several workers are processing the same jobChan).
+//Designed to run in parallel (several workers are processing the components of the same scheduler).
 //Detects Context cancellation.
 //Context cancellation is not detected immediately. It's detected between component processing operations because such operations are blocking.
 //If the Context is cancelled, the worker quits immediately, skipping the remaining components.
-func (e *Engine) worker(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan components.KymaComponent, statusChan chan<- components.KymaComponent, installType installationType) {
+func (e *Engine) worker(ctx context.Context, wg *sync.WaitGroup, id int, sched *scheduler, statusChan chan<- components.KymaComponent, installType installationType) {
 	defer wg.Done()
 
 	for {
-		select {
-		//TODO: Perhaps this should be removed/refactored. Golang choses cases randomly if both are possible, so it might chose processing component instead, and that is invalid.
-		case <-ctx.Done():
-			e.cfg.Log.Infof("%s Finishing work: %v", logPrefix, ctx.Err())
+		if err := ctx.Err(); err != nil {
+			e.cfg.Log.Infof("%s Finishing work: %v.", logPrefix, err)
+			e.skipRemaining(id, sched, statusChan)
 			return
+		}
+		component, ok := sched.next(id)
+		if !ok {
+			e.cfg.Log.Infof("%s Finishing work: no more jobs in queue.", logPrefix)
+			return
+		}
 
-		case component, ok := <-jobChan:
-			//TODO: Is there a better way to find out if Context is canceled?
-			if err := ctx.Err(); err != nil {
-				e.cfg.Log.Infof("%s Finishing work: %v.", logPrefix, err)
-				return
+		if installType == deploy {
+			ready, err := e.awaitRequiredAPIs(ctx, id, sched, component)
+			if err != nil && ctx.Err() != nil {
+				sched.putBack(id, component)
+				continue
+			}
+			if err != nil {
+				component.Status = components.StatusError
+				component.Error = &errors.ErrComponentFailed{Component: component.Name, Err: err}
+				statusChan <- component
+				continue
+			}
+			if !ready {
+				continue
+			}
+		}
+
+		start := e.clock().Now()
+		if installType == deploy {
+			if err := component.Deploy(e.progressContext(ctx, component, statusChan)); err != nil {
+				component.Status = components.StatusError
+				component.Error = &errors.ErrComponentFailed{Component: component.Name, Err: err}
+			} else {
+				component.Status = components.StatusInstalled
 			}
-			if ok {
-				if installType == deploy {
-					if err := component.Deploy(ctx); err != nil {
-						component.Status = components.StatusError
-						component.Error = err
-					} else {
-						component.Status = components.StatusInstalled
-					}
-					statusChan <- component
-				} else if installType == uninstall {
-					if err := component.Uninstall(ctx); err != nil {
-						component.Status = components.StatusError
-						component.Error = err
-					} else {
-						component.Status = components.StatusUninstalled
-					}
-					statusChan <- component
-				}
+		} else if installType == uninstall {
+			if err := component.Uninstall(ctx); err != nil {
+				component.Status = components.StatusError
+				component.Error = &errors.ErrComponentFailed{Component: component.Name, Err: err}
 			} else {
-				e.cfg.Log.Infof("%s Finishing work: no more jobs in queue.", logPrefix)
-				return
+				component.Status = components.StatusUninstalled
 			}
 		}
+		component.Duration = e.clock().Since(start)
+		if e.cfg.Durations != nil && component.Status != components.StatusError {
+			e.cfg.Durations.Observe(durationKey(installType, component.Name), component.Duration)
+		}
+		statusChan <- component
+	}
+}
+
+//progressContext returns a context which sends the phases of the deployment of the component as intermediate statuses,
+//if the progress is reported
+func (e *Engine) progressContext(ctx context.Context, component components.KymaComponent, statusChan chan<- components.KymaComponent) context.Context {
+	if !e.cfg.ReportProgress {
+		return ctx
+	}
+	var last components.Status
+	return helm.WithPhaseReporter(ctx, func(phase helm.ReleasePhase) {
+		status := components.StatusOf(phase)
+		if status == last {
+			return
+		}
+		last = status
+		update := component
+		update.Status = status
+		statusChan <- update
+	})
+}
+
+//skipRemaining reports the components which are not processed after a cancellation as skipped, if the progress is reported
+func (e *Engine) skipRemaining(id int, sched *scheduler, statusChan chan<- components.KymaComponent) {
+	if !e.cfg.ReportProgress {
+		return
+	}
+	for {
+		component, ok := sched.next(id)
+		if !ok {
+			return
+		}
+		component.Status = components.StatusSkipped
+		statusChan <- component
 	}
 }
 
-func (e *Engine) enqueueJob(job components.KymaComponent, jobChan chan<- components.KymaComponent) bool {
-	select {
-	case jobChan <- job:
-		return true
-	default:
-		return false
+func (e *Engine) clock() Clock {
+	if e.cfg.Clock == nil {
+		return RealClock
 	}
+	return e.cfg.Clock
 }
@@ -8,8 +8,10 @@ import (
 
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/simulation"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/semaphore"
 )
@@ -197,6 +199,9 @@ func TestErrorScenario(t *testing.T) {
 		componentStatus := <-statusChan
 		if componentStatus.Name == expectedFailedComponents[0] || componentStatus.Name == expectedFailedComponents[1] {
 			require.Equal(t, components.StatusError, componentStatus.Status)
+			var failure *errors.ErrComponentFailed
+			require.ErrorAs(t, componentStatus.Error, &failure)
+			require.Equal(t, componentStatus.Name, failure.Component)
 		} else {
 			require.Equal(t, components.StatusInstalled, componentStatus.Status)
 		}
@@ -206,6 +211,25 @@ func TestErrorScenario(t *testing.T) {
 	require.Zero(t, len(statusChan))
 }
 
+func TestClock(t *testing.T) {
+	//Test that the component durations are measured with the configured clock
+	overridesProvider := &mockOverridesProvider{}
+	componentsProvider := &mockComponentsProvider{t, &mockSimpleHelmClient{}}
+
+	e := NewEngine(overridesProvider, componentsProvider, Config{
+		WorkersCount: defualtWorkersCount,
+		Log:          logger.NewLogger(true),
+		Clock:        &steppingClock{step: time.Hour},
+	})
+	statusChan, err := e.Deploy(context.TODO())
+	require.NoError(t, err)
+
+	for component := range statusChan {
+		require.Equal(t, components.StatusInstalled, component.Status)
+		require.Equal(t, time.Hour, component.Duration)
+	}
+}
+
 func TestContextCancelScenario(t *testing.T) {
 	//Test cancel scenario: Configure two workers and six components (A, B, C, D, E, F), then after B is reported via statusChan, cancel the context.
 	//Expected: Components A, B, C, D are reported via statusChan. This is because context is canceled after B, but workers should already start processing C and D.
@@ -325,6 +349,23 @@ func (c *mockSimpleHelmClient) UninstallRelease(ctx context.Context, namespace,
 	return nil
 }
 
+//steppingClock measures the same duration for every component
+type steppingClock struct {
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	return time.Time{}
+}
+
+func (c *steppingClock) Since(t time.Time) time.Duration {
+	return c.step
+}
+
+func (c *steppingClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
 type mockOverridesProvider struct{}
 
 func (o *mockOverridesProvider) ReadOverridesFromCluster() error {
@@ -334,3 +375,128 @@ func (o *mockOverridesProvider) ReadOverridesFromCluster() error {
 func (o *mockOverridesProvider) OverridesGetterFunctionFor(name string) func() map[string]interface{} {
 	return nil
 }
+
+func TestSimulation(t *testing.T) {
+	client := simulation.NewClient(config.SimulationConfig{
+		DefaultLatency: 10 * time.Millisecond,
+		Failures:       map[string]config.SimulatedFailure{"test3": {Message: "injected"}},
+	})
+	var defs []config.ComponentDefinition
+	for _, name := range testComponentsNames {
+		defs = append(defs, config.ComponentDefinition{Name: name, Namespace: "kyma-system"})
+	}
+	values := map[string]interface{}{
+		"global": map[string]interface{}{"domainName": "kyma.example.com"},
+		"test1":  map[string]interface{}{"replicas": 2},
+	}
+	e := NewSimulation(client, defs, values, Config{WorkersCount: defualtWorkersCount, Log: logger.NewLogger(true)})
+
+	statusChan, err := e.Deploy(context.TODO())
+	require.NoError(t, err)
+
+	statuses := make(map[string]components.Status)
+	for cmp := range statusChan {
+		statuses[cmp.Name] = cmp.Status
+	}
+	require.Len(t, statuses, len(testComponentsNames))
+	require.Equal(t, components.StatusError, statuses["test3"])
+	require.Equal(t, components.StatusInstalled, statuses["test1"])
+
+	for _, call := range client.Calls() {
+		require.Equal(t, map[string]interface{}{"domainName": "kyma.example.com"}, call.Overrides["global"])
+		if call.Name == "test1" {
+			require.Equal(t, 2, call.Overrides["replicas"])
+		}
+	}
+}
+
+func TestReportProgress(t *testing.T) {
+
+	t.Run("Intermediate statuses are reported", func(t *testing.T) {
+		e := NewEngine(&mockOverridesProvider{}, &mockComponentsProvider{t: t, hc: &mockPhasesHelmClient{}}, Config{
+			WorkersCount:   1,
+			Log:            logger.NewLogger(true),
+			ReportProgress: true,
+		})
+
+		statusChan, err := e.Deploy(context.TODO())
+		require.NoError(t, err)
+
+		statuses := make(map[string][]components.Status)
+		for cmp := range statusChan {
+			statuses[cmp.Name] = append(statuses[cmp.Name], cmp.Status)
+		}
+		require.Len(t, statuses, len(testComponentsNames))
+		for _, name := range testComponentsNames {
+			require.Equal(t, []components.Status{
+				components.StatusPending,
+				components.StatusRendering,
+				components.StatusApplying,
+				components.StatusWaitingForReadiness,
+				components.StatusInstalled,
+			}, statuses[name])
+		}
+	})
+
+	t.Run("Remaining components are skipped after a cancellation", func(t *testing.T) {
+		e := NewEngine(&mockOverridesProvider{}, &mockComponentsProvider{t: t, hc: &mockSimpleHelmClient{}}, Config{
+			WorkersCount:   1,
+			Log:            logger.NewLogger(true),
+			ReportProgress: true,
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		statusChan, err := e.Deploy(ctx)
+		require.NoError(t, err)
+
+		final := make(map[string]components.Status)
+		for cmp := range statusChan {
+			if cmp.Status == components.StatusInstalled {
+				cancel()
+			}
+			if cmp.Status.Final() {
+				final[cmp.Name] = cmp.Status
+			}
+		}
+		cancel()
+		require.Len(t, final, len(testComponentsNames))
+		var skipped int
+		for _, status := range final {
+			if status == components.StatusSkipped {
+				skipped++
+			}
+		}
+		require.NotZero(t, skipped)
+	})
+
+	t.Run("Intermediate statuses are not reported by default", func(t *testing.T) {
+		e := NewEngine(&mockOverridesProvider{}, &mockComponentsProvider{t: t, hc: &mockPhasesHelmClient{}}, Config{
+			WorkersCount: 2,
+			Log:          logger.NewLogger(true),
+		})
+
+		statusChan, err := e.Deploy(context.TODO())
+		require.NoError(t, err)
+
+		var count int
+		for cmp := range statusChan {
+			require.Equal(t, components.StatusInstalled, cmp.Status)
+			count++
+		}
+		require.Equal(t, len(testComponentsNames), count)
+	})
+}
+
+//mockPhasesHelmClient reports the phases of a deployment like the Helm client
+type mockPhasesHelmClient struct{}
+
+func (c *mockPhasesHelmClient) DeployRelease(ctx context.Context, chartDir, namespace, name string, overrides map[string]interface{}, profile string) error {
+	for _, phase := range []helm.ReleasePhase{helm.PhaseRendering, helm.PhaseApplying, helm.PhaseApplying, helm.PhaseWaiting} {
+		helm.ReportPhase(ctx, phase)
+	}
+	return nil
+}
+
+func (c *mockPhasesHelmClient) UninstallRelease(ctx context.Context, namespace, name string) error {
+	return nil
+}
@@ -0,0 +1,225 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+)
+
+//Durations keeps the observed processing time of components. It is safe for concurrent use.
+//The scheduler uses it to start heavyweight components first, so they do not serialize behind quick ones.
+type Durations struct {
+	mu        sync.Mutex
+	durations map[string]time.Duration
+}
+
+//NewDurations creates an empty Durations instance
+func NewDurations() *Durations {
+	return &Durations{durations: make(map[string]time.Duration)}
+}
+
+//LoadDurations reads durations stored with Save. A missing file results in empty durations.
+func LoadDurations(path string) (*Durations, error) {
+	d := NewDurations()
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &d.durations); err != nil {
+		return nil, fmt.Errorf("Failed to read component durations from '%s': %v", path, err)
+	}
+	return d, nil
+}
+
+//Save writes the durations to a file
+func (d *Durations) Save(path string) error {
+	d.mu.Lock()
+	data, err := json.MarshalIndent(d.durations, "", "  ")
+	d.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+//Observe records the processing time of a component. Repeated observations are averaged to smooth outliers.
+func (d *Durations) Observe(key string, duration time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if previous, ok := d.durations[key]; ok {
+		duration = (previous + duration) / 2
+	}
+	d.durations[key] = duration
+}
+
+//Get returns the observed processing time of a component
+func (d *Durations) Get(key string) (time.Duration, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	duration, ok := d.durations[key]
+	return duration, ok
+}
+
+func durationKey(installType installationType, name string) string {
+	return fmt.Sprintf("%s/%s", installType, name)
+}
+
+//job is a component together with its estimated processing time
+type job struct {
+	component components.KymaComponent
+	estimate  time.Duration
+}
+
+//scheduler distributes components over per-worker queues.
+//Components are ordered by their estimated duration, heaviest first, and assigned to the worker with the least estimated load.
+//A worker whose queue is empty steals the heaviest pending component of the worker with the most remaining load.
+type scheduler struct {
+	mu     sync.Mutex
+	queues [][]job
+	//awaitingSince tracks since when components wait for their required APIs
+	awaitingSince map[string]time.Time
+}
+
+//newScheduler creates a scheduler for the given number of workers.
+//With a single worker, the order of the components is kept, as prerequisites depend on it.
+func newScheduler(cmps []components.KymaComponent, workers int, durations *Durations, installType installationType) *scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := estimate(cmps, durations, installType)
+	if workers > 1 {
+		sort.SliceStable(jobs, func(i, j int) bool {
+			return jobs[i].estimate > jobs[j].estimate
+		})
+	}
+
+	s := &scheduler{queues: make([][]job, workers), awaitingSince: make(map[string]time.Time)}
+	loads := make([]time.Duration, workers)
+	for _, j := range jobs {
+		target := 0
+		for worker := range loads {
+			if loads[worker] < loads[target] || (loads[worker] == loads[target] && len(s.queues[worker]) < len(s.queues[target])) {
+				target = worker
+			}
+		}
+		s.queues[target] = append(s.queues[target], j)
+		loads[target] += j.estimate
+	}
+	return s
+}
+
+//estimate creates the jobs of the components. Components without observed duration are estimated with the mean of the others.
+func estimate(cmps []components.KymaComponent, durations *Durations, installType installationType) []job {
+	jobs := make([]job, len(cmps))
+	var known int
+	var total time.Duration
+	for i, comp := range cmps {
+		jobs[i] = job{component: comp, estimate: -1}
+		if durations == nil {
+			continue
+		}
+		if duration, ok := durations.Get(durationKey(installType, comp.Name)); ok {
+			jobs[i].estimate = duration
+			known++
+			total += duration
+		}
+	}
+	var mean time.Duration
+	if known > 0 {
+		mean = total / time.Duration(known)
+	}
+	for i := range jobs {
+		if jobs[i].estimate < 0 {
+			jobs[i].estimate = mean
+		}
+	}
+	return jobs
+}
+
+//next returns the next component of a worker, or false if no component is pending anymore
+func (s *scheduler) next(worker int) (components.KymaComponent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	victim := worker
+	if len(s.queues[worker]) == 0 {
+		victim = -1
+		var victimLoad time.Duration
+		for other, queue := range s.queues {
+			if len(queue) == 0 {
+				continue
+			}
+			load := queueLoad(queue)
+			if victim == -1 || load > victimLoad || (load == victimLoad && len(queue) > len(s.queues[victim])) {
+				victim = other
+				victimLoad = load
+			}
+		}
+		if victim == -1 {
+			return components.KymaComponent{}, false
+		}
+	}
+
+	j := s.queues[victim][0]
+	s.queues[victim] = s.queues[victim][1:]
+	return j.component, true
+}
+
+func queueLoad(queue []job) time.Duration {
+	var load time.Duration
+	for _, j := range queue {
+		load += j.estimate
+	}
+	return load
+}
+
+//awaiting marks a component as waiting for its required APIs and returns since when it waits
+func (s *scheduler) awaiting(name string, now time.Time) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	since, ok := s.awaitingSince[name]
+	if !ok {
+		since = now
+		s.awaitingSince[name] = since
+	}
+	return since
+}
+
+//served marks a component as no longer waiting for its required APIs
+func (s *scheduler) served(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.awaitingSince, name)
+}
+
+//requeue puts a component which waits for its required APIs back to the end of the queue of the worker,
+//if a component which does not wait for APIs is pending. Otherwise, false is returned and the queues are not changed.
+func (s *scheduler) requeue(worker int, component components.KymaComponent) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, queue := range s.queues {
+		for _, j := range queue {
+			if _, awaiting := s.awaitingSince[j.component.Name]; !awaiting {
+				s.queues[worker] = append(s.queues[worker], job{component: component})
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//putBack puts a component back to the front of the queue of the worker, e.g. because the processing was cancelled before it started
+func (s *scheduler) putBack(worker int, component components.KymaComponent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queues[worker] = append([]job{{component: component}}, s.queues[worker]...)
+}
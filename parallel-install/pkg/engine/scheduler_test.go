@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestComponents(names ...string) []components.KymaComponent {
+	var cmps []components.KymaComponent
+	for _, name := range names {
+		cmps = append(cmps, components.KymaComponent{Name: name})
+	}
+	return cmps
+}
+
+func queueNames(queue []job) []string {
+	var names []string
+	for _, j := range queue {
+		names = append(names, j.component.Name)
+	}
+	return names
+}
+
+func TestScheduler(t *testing.T) {
+	durations := NewDurations()
+	durations.Observe(durationKey(deploy, "istio"), 10*time.Minute)
+	durations.Observe(durationKey(deploy, "monitoring"), 6*time.Minute)
+	durations.Observe(durationKey(deploy, "dex"), time.Minute)
+	durations.Observe(durationKey(deploy, "apiserver-proxy"), time.Minute)
+
+	t.Run("Heavyweight components are started first on balanced workers", func(t *testing.T) {
+		s := newScheduler(newTestComponents("dex", "apiserver-proxy", "monitoring", "istio", "logging"), 2, durations, deploy)
+		//logging is estimated with the mean of the observed durations (4.5 minutes)
+		require.Equal(t, []string{"istio", "dex"}, queueNames(s.queues[0]))
+		require.Equal(t, []string{"monitoring", "logging", "apiserver-proxy"}, queueNames(s.queues[1]))
+	})
+
+	t.Run("A single worker keeps the order", func(t *testing.T) {
+		s := newScheduler(newTestComponents("dex", "monitoring", "istio"), 1, durations, deploy)
+		require.Equal(t, []string{"dex", "monitoring", "istio"}, queueNames(s.queues[0]))
+	})
+
+	t.Run("Without durations the order is kept", func(t *testing.T) {
+		s := newScheduler(newTestComponents("a", "b", "c", "d", "e"), 2, nil, deploy)
+		require.Equal(t, []string{"a", "c", "e"}, queueNames(s.queues[0]))
+		require.Equal(t, []string{"b", "d"}, queueNames(s.queues[1]))
+	})
+
+	t.Run("Idle workers steal from the most loaded worker", func(t *testing.T) {
+		s := newScheduler(newTestComponents("dex", "apiserver-proxy", "monitoring", "istio", "logging"), 2, durations, deploy)
+
+		for _, expected := range []string{"istio", "dex"} {
+			comp, ok := s.next(0)
+			require.True(t, ok)
+			require.Equal(t, expected, comp.Name)
+		}
+
+		var stolen []string
+		for {
+			comp, ok := s.next(0)
+			if !ok {
+				break
+			}
+			stolen = append(stolen, comp.Name)
+		}
+		require.Equal(t, []string{"monitoring", "logging", "apiserver-proxy"}, stolen)
+
+		_, ok := s.next(1)
+		require.False(t, ok)
+	})
+}
+
+func TestDurations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "durations")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "durations.json")
+
+	durations, err := LoadDurations(path)
+	require.NoError(t, err)
+	_, ok := durations.Get("deploy/istio")
+	require.False(t, ok)
+
+	durations.Observe("deploy/istio", 4*time.Minute)
+	durations.Observe("deploy/istio", 2*time.Minute)
+	require.NoError(t, durations.Save(path))
+
+	loaded, err := LoadDurations(path)
+	require.NoError(t, err)
+	duration, ok := loaded.Get("deploy/istio")
+	require.True(t, ok)
+	require.Equal(t, 3*time.Minute, duration)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("invalid"), 0600))
+	_, err = LoadDurations(path)
+	require.Error(t, err)
+}
+
+func TestEngineRecordsDurations(t *testing.T) {
+	var names []string
+	for i := 0; i < 40; i++ {
+		names = append(names, fmt.Sprintf("comp%d", i))
+	}
+	durations := NewDurations()
+	e := NewEngine(&mockOverridesProvider{}, &namedComponentsProvider{names: names}, Config{
+		WorkersCount: defualtWorkersCount,
+		Log:          logger.NewLogger(true),
+		Durations:    durations,
+	})
+
+	statusChan, err := e.Deploy(context.TODO())
+	require.NoError(t, err)
+
+	processed := 0
+	for cmp := range statusChan {
+		require.Equal(t, components.StatusInstalled, cmp.Status)
+		processed++
+	}
+	//components beyond the former queue capacity of 30 are processed as well
+	require.Equal(t, len(names), processed)
+	for _, name := range names {
+		_, ok := durations.Get(durationKey(deploy, name))
+		require.True(t, ok, name)
+	}
+}
+
+type namedComponentsProvider struct {
+	names []string
+}
+
+func (p *namedComponentsProvider) GetComponents() []components.KymaComponent {
+	var comps []components.KymaComponent
+	for _, name := range p.names {
+		comps = append(comps, components.KymaComponent{
+			Name:            name,
+			Namespace:       "test",
+			OverridesGetter: func() map[string]interface{} { return nil },
+			HelmClient:      &mockSimpleHelmClient{},
+			Log:             logger.NewLogger(true),
+		})
+	}
+	return comps
+}
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"path"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/simulation"
+)
+
+//NewSimulation returns an Engine which processes the given components with the simulation client.
+//It does not need a cluster: overrides are taken as they are, using the same structure as the overrides of a deployment
+//(values of a component under its name, global values under "global").
+//Use the Calls function of the client to inspect the order and timing of the operations.
+func NewSimulation(client *simulation.Client, defs []config.ComponentDefinition, values map[string]interface{}, cfg Config) *Engine {
+	overridesProvider := &staticOverridesProvider{values: values}
+	return NewEngine(overridesProvider, &simulationComponentsProvider{
+		client:            client,
+		defs:              defs,
+		overridesProvider: overridesProvider,
+		log:               cfg.Log,
+	}, cfg)
+}
+
+//staticOverridesProvider provides overrides without reading the cluster
+type staticOverridesProvider struct {
+	values map[string]interface{}
+}
+
+func (p *staticOverridesProvider) OverridesGetterFunctionFor(name string) func() map[string]interface{} {
+	return func() map[string]interface{} {
+		result := make(map[string]interface{})
+		if global, ok := p.values["global"].(map[string]interface{}); ok {
+			result["global"] = global
+		}
+		if componentValues, ok := p.values[name].(map[string]interface{}); ok {
+			result = overrides.MergeMaps(componentValues, result)
+		}
+		return result
+	}
+}
+
+func (p *staticOverridesProvider) ReadOverridesFromCluster() error {
+	return nil
+}
+
+type simulationComponentsProvider struct {
+	client            *simulation.Client
+	defs              []config.ComponentDefinition
+	overridesProvider overrides.Provider
+	log               logger.Interface
+}
+
+func (p *simulationComponentsProvider) GetComponents() []components.KymaComponent {
+	var cmps []components.KymaComponent
+	for _, def := range p.defs {
+		cmps = append(cmps, components.KymaComponent{
+			Name:            def.Name,
+			Namespace:       def.Namespace,
+			ChartDir:        path.Join("simulation", def.Name),
+			OverridesGetter: p.overridesProvider.OverridesGetterFunctionFor(def.Name),
+			HelmClient:      p.client,
+			Log:             p.log,
+		})
+	}
+	return cmps
+}
@@ -0,0 +1,186 @@
+//Package errors contains the errors returned by the installer.
+//
+//Match them with errors.Is and errors.As of the standard library instead of comparing error messages,
+//which are subject to change and can be translated.
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	//ErrCancelled is returned if the operation was cancelled because the cancel timeout was reached
+	ErrCancelled = errors.New("operation cancelled")
+	//ErrQuitTimeout is returned if the operation did not stop after it was cancelled and the quit timeout was reached
+	ErrQuitTimeout = errors.New("operation quit after timeout")
+)
+
+//ErrComponentFailed is returned if a component could not be installed or uninstalled
+type ErrComponentFailed struct {
+	//Name of the component
+	Component string
+	//Err is the cause of the failure
+	Err error
+}
+
+func (e *ErrComponentFailed) Error() string {
+	return fmt.Sprintf("component %s failed: %v", e.Component, e.Err)
+}
+
+//Unwrap returns the cause of the failure
+func (e *ErrComponentFailed) Unwrap() error {
+	return e.Err
+}
+
+//ComponentFailures is returned if one or more components failed. errors.As extracts the first failure as ErrComponentFailed.
+type ComponentFailures []*ErrComponentFailed
+
+func (f ComponentFailures) Error() string {
+	failures := make([]string, 0, len(f))
+	for _, failure := range f {
+		failures = append(failures, failure.Error())
+	}
+	return strings.Join(failures, "; ")
+}
+
+//As supports errors.As with a target of type **ErrComponentFailed
+func (f ComponentFailures) As(target interface{}) bool {
+	failure, ok := target.(**ErrComponentFailed)
+	if !ok || len(f) == 0 {
+		return false
+	}
+	*failure = f[0]
+	return true
+}
+
+//Components returns the names of the failed components
+func (f ComponentFailures) Components() []string {
+	names := make([]string, 0, len(f))
+	for _, failure := range f {
+		names = append(names, failure.Component)
+	}
+	return names
+}
+
+//FailedComponents returns the names of the failed components if the error is caused by component failures
+func FailedComponents(err error) []string {
+	var failures ComponentFailures
+	if errors.As(err, &failures) {
+		return failures.Components()
+	}
+	var failure *ErrComponentFailed
+	if errors.As(err, &failure) {
+		return []string{failure.Component}
+	}
+	return nil
+}
+
+//ErrInvalidKubeconfig is returned if the kubeconfig cannot be read or does not contain the selected context, cluster, or user
+type ErrInvalidKubeconfig struct {
+	//Err is the cause of the failure
+	Err error
+}
+
+func (e *ErrInvalidKubeconfig) Error() string {
+	return fmt.Sprintf("invalid kubeconfig: %v", e.Err)
+}
+
+//Unwrap returns the cause of the failure
+func (e *ErrInvalidKubeconfig) Unwrap() error {
+	return e.Err
+}
+
+//ErrClusterUnreachable is returned if the API server of the kubeconfig does not answer a version request
+type ErrClusterUnreachable struct {
+	//Host is the address of the API server
+	Host string
+	//Err is the cause of the failure
+	Err error
+}
+
+func (e *ErrClusterUnreachable) Error() string {
+	return fmt.Sprintf("cluster %s is unreachable: %v", e.Host, e.Err)
+}
+
+//Unwrap returns the cause of the failure
+func (e *ErrClusterUnreachable) Unwrap() error {
+	return e.Err
+}
+
+//ErrUnsupportedKubernetesVersion is returned if the Kubernetes version of the cluster is outside of the supported range
+type ErrUnsupportedKubernetesVersion struct {
+	//Version of the API server
+	Version string
+	//Min is the oldest supported minor version
+	Min string
+	//Max is the newest supported minor version
+	Max string
+}
+
+func (e *ErrUnsupportedKubernetesVersion) Error() string {
+	return fmt.Sprintf("Kubernetes version %s is not supported: supported versions are %s to %s", e.Version, e.Min, e.Max)
+}
+
+//ErrNamespaceConflict is returned if namespaces required by Kyma already exist, but are not owned by Kyma
+type ErrNamespaceConflict struct {
+	//Namespaces which are not owned by Kyma
+	Namespaces []string
+}
+
+func (e *ErrNamespaceConflict) Error() string {
+	return fmt.Sprintf("namespaces %s already exist and are not owned by Kyma", strings.Join(e.Namespaces, ", "))
+}
+
+//ErrReleaseConflict is returned if Helm releases of components already exist, but were not installed by Kyma
+type ErrReleaseConflict struct {
+	//Releases which were not installed by Kyma, in the format namespace/name
+	Releases []string
+}
+
+func (e *ErrReleaseConflict) Error() string {
+	return fmt.Sprintf("Helm releases %s already exist and were not installed by Kyma", strings.Join(e.Releases, ", "))
+}
+
+//ErrUnknownOverrides is returned in strict mode if override keys are not defined in the values of any chart
+type ErrUnknownOverrides struct {
+	//Keys of the overrides which no chart defines, separated by "."
+	Keys []string
+}
+
+func (e *ErrUnknownOverrides) Error() string {
+	return fmt.Sprintf("overrides %s are not defined in the values of any chart", strings.Join(e.Keys, ", "))
+}
+
+//PasswordViolation is a password which does not comply with the password policy
+type PasswordViolation struct {
+	//Source of the password: the generated secret as namespace/name, or the override key
+	Source string
+	//Rules the password violates, see config.PasswordRule
+	Rules []string
+}
+
+//ErrPasswordPolicy is returned if passwords do not comply with the password policy
+type ErrPasswordPolicy struct {
+	Violations []PasswordViolation
+}
+
+func (e *ErrPasswordPolicy) Error() string {
+	violations := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		violations = append(violations, fmt.Sprintf("%s (%s)", violation.Source, strings.Join(violation.Rules, ", ")))
+	}
+	return fmt.Sprintf("passwords %s violate the password policy", strings.Join(violations, ", "))
+}
+
+//ErrNotFIPSCompliant is returned in FIPS mode if a key or certificate uses parameters which are not approved
+type ErrNotFIPSCompliant struct {
+	//Source of the key or certificate: the override key, the generated secret as namespace/name, or the TLS config
+	Source string
+	Reason string
+}
+
+func (e *ErrNotFIPSCompliant) Error() string {
+	return fmt.Sprintf("%s is not FIPS compliant: %s", e.Source, e.Reason)
+}
@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrComponentFailed(t *testing.T) {
+	err := fmt.Errorf("deployment failed: %w", &ErrComponentFailed{Component: "istio", Err: context.DeadlineExceeded})
+
+	var failure *ErrComponentFailed
+	require.True(t, errors.As(err, &failure))
+	require.Equal(t, "istio", failure.Component)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Equal(t, "deployment failed: component istio failed: context deadline exceeded", err.Error())
+}
+
+func TestComponentFailures(t *testing.T) {
+	failures := ComponentFailures{
+		{Component: "istio", Err: errors.New("timeout")},
+		{Component: "serverless", Err: errors.New("chart not found")},
+	}
+	err := fmt.Errorf("2 components failed: %w", failures)
+
+	require.Equal(t, []string{"istio", "serverless"}, FailedComponents(err))
+	require.Equal(t, "component istio failed: timeout; component serverless failed: chart not found", failures.Error())
+
+	var failure *ErrComponentFailed
+	require.True(t, errors.As(err, &failure))
+	require.Equal(t, "istio", failure.Component)
+
+	require.Equal(t, []string{"istio"}, FailedComponents(&ErrComponentFailed{Component: "istio"}))
+	require.Nil(t, FailedComponents(ErrCancelled))
+}
@@ -0,0 +1,232 @@
+package git
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+// changelogFile is the file of a directory whose added lines are reported as changelog entries
+const changelogFile = "CHANGELOG.md"
+
+// Commit is a commit which changed a directory
+type Commit struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Subject string
+}
+
+// DirectoryChanges are the changes of a directory between two revisions
+type DirectoryChanges struct {
+	// Path of the directory relative to the repository root
+	Path string
+	// Commits which changed files in the directory, newest first. Merge commits are not listed.
+	Commits []Commit
+	// Changelog are the lines added to the CHANGELOG.md of the directory
+	Changelog []string
+}
+
+// Empty returns true if the directory did not change
+func (c *DirectoryChanges) Empty() bool {
+	return len(c.Commits) == 0 && len(c.Changelog) == 0
+}
+
+// String lists the commits and the changelog entries
+func (c *DirectoryChanges) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d commit(s)", c.Path, len(c.Commits))
+	for _, commit := range c.Commits {
+		fmt.Fprintf(&b, "\n  %.8s %s (%s)", commit.Hash, commit.Subject, commit.Author)
+	}
+	if len(c.Changelog) > 0 {
+		b.WriteString("\n  Changelog:")
+		for _, entry := range c.Changelog {
+			fmt.Fprintf(&b, "\n    %s", entry)
+		}
+	}
+	return b.String()
+}
+
+// ChangeSummary returns the changes of each directory between two revisions of the local repository in repoPath.
+// The revisions can be branches, tags, or commit hashes. The commits are those reachable from the to revision
+// which are not reachable from the from revision, like in 'git log from..to'.
+func ChangeSummary(repoPath, fromRev, toRev string, dirs []string) ([]*DirectoryChanges, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error opening repository %s", repoPath)
+	}
+	from, err := commitOf(repo, fromRev)
+	if err != nil {
+		return nil, err
+	}
+	to, err := commitOf(repo, toRev)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*DirectoryChanges, 0, len(dirs))
+	for _, dir := range dirs {
+		changes = append(changes, &DirectoryChanges{Path: path.Clean(dir)})
+	}
+
+	excluded, err := reachable(repo, from.Hash)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := repo.Log(&git.LogOptions{From: to.Hash})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading the commits")
+	}
+	err = iter.ForEach(func(commit *object.Commit) error {
+		if excluded[commit.Hash] || commit.NumParents() > 1 {
+			return nil
+		}
+		changed, err := changedPaths(commit)
+		if err != nil {
+			return err
+		}
+		for _, dirChanges := range changes {
+			if containsPath(changed, dirChanges.Path) {
+				dirChanges.Commits = append(dirChanges.Commits, Commit{
+					Hash:    commit.Hash.String(),
+					Author:  commit.Author.Name,
+					Date:    commit.Author.When,
+					Subject: strings.SplitN(strings.TrimSpace(commit.Message), "\n", 2)[0],
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading the commits")
+	}
+
+	for _, dirChanges := range changes {
+		if dirChanges.Changelog, err = addedChangelogLines(from, to, path.Join(dirChanges.Path, changelogFile)); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// commitOf returns the commit a revision resolves to
+func commitOf(repo *git.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error resolving revision %s", rev)
+	}
+	return repo.CommitObject(*hash)
+}
+
+// reachable returns the hashes of the commits reachable from the given commit
+func reachable(repo *git.Repository, hash plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	iter, err := repo.Log(&git.LogOptions{From: hash})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading the commits")
+	}
+	hashes := map[plumbing.Hash]bool{}
+	err = iter.ForEach(func(commit *object.Commit) error {
+		hashes[commit.Hash] = true
+		return nil
+	})
+	return hashes, err
+}
+
+// changedPaths returns the files the commit changed compared to its parent
+func changedPaths(commit *object.Commit) ([]string, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	parentTree := &object.Tree{}
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	diff, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, change := range diff {
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if name != "" {
+				paths = append(paths, name)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// containsPath returns true if one of the paths is in the directory
+func containsPath(paths []string, dir string) bool {
+	for _, p := range paths {
+		if dir == "." || strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// addedChangelogLines returns the non-empty lines of the changelog file of the to commit which are not in the file of the from commit
+func addedChangelogLines(from, to *object.Commit, file string) ([]string, error) {
+	toLines, err := fileLines(to, file)
+	if err != nil || len(toLines) == 0 {
+		return nil, err
+	}
+	fromLines, err := fileLines(from, file)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, line := range fromLines {
+		existing[line] = true
+	}
+	var added []string
+	for _, line := range toLines {
+		if !existing[line] {
+			added = append(added, line)
+		}
+	}
+	return added, nil
+}
+
+// fileLines returns the non-empty lines of a file of the commit, or nothing if the file does not exist
+func fileLines(commit *object.Commit, file string) ([]string, error) {
+	f, err := commit.File(file)
+	if err == object.ErrFileNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	reader, err := f.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
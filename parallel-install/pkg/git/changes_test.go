@@ -0,0 +1,83 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeSummary(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "changes")
+	require.NoError(t, err)
+	defer os.RemoveAll(repoPath)
+
+	repo, err := git.PlainInit(repoPath, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	commit := func(message string, files map[string]string) {
+		for name, content := range files {
+			file := filepath.Join(repoPath, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(file), 0700))
+			require.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+			_, err := worktree.Add(name)
+			require.NoError(t, err)
+		}
+		_, err := worktree.Commit(message, &git.CommitOptions{
+			Author: &object.Signature{Name: "Jane Doe", Email: "jane@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+
+	commit("Initial version", map[string]string{
+		"resources/serverless/values.yaml":  "replicas: 1\n",
+		"resources/serverless/CHANGELOG.md": "## 1.0.0\n- Initial version\n",
+		"resources/monitoring/values.yaml":  "retention: 1d\n",
+	})
+	head, err := repo.Head()
+	require.NoError(t, err)
+	_, err = repo.CreateTag("1.0.0", head.Hash(), nil)
+	require.NoError(t, err)
+
+	commit("Scale serverless\n\nTwo replicas are required for high availability.", map[string]string{
+		"resources/serverless/values.yaml":  "replicas: 2\n",
+		"resources/serverless/CHANGELOG.md": "## 1.1.0\n- Two replicas\n\n## 1.0.0\n- Initial version\n",
+	})
+	commit("Update the documentation", map[string]string{
+		"docs/README.md": "Kyma\n",
+	})
+
+	changes, err := ChangeSummary(repoPath, "1.0.0", "HEAD", []string{"resources/serverless", "resources/monitoring/"})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	serverless := changes[0]
+	require.Equal(t, "resources/serverless", serverless.Path)
+	require.Len(t, serverless.Commits, 1)
+	require.Equal(t, "Scale serverless", serverless.Commits[0].Subject)
+	require.Equal(t, "Jane Doe", serverless.Commits[0].Author)
+	require.Equal(t, []string{"## 1.1.0", "- Two replicas"}, serverless.Changelog)
+	require.False(t, serverless.Empty())
+
+	monitoring := changes[1]
+	require.Equal(t, "resources/monitoring", monitoring.Path)
+	require.True(t, monitoring.Empty())
+	require.Equal(t, "resources/monitoring: 0 commit(s)", monitoring.String())
+
+	t.Run("Same revision", func(t *testing.T) {
+		changes, err := ChangeSummary(repoPath, "HEAD", "HEAD", []string{"resources/serverless"})
+		require.NoError(t, err)
+		require.True(t, changes[0].Empty())
+	})
+
+	t.Run("Unknown revision", func(t *testing.T) {
+		_, err := ChangeSummary(repoPath, "0.9.0", "HEAD", []string{"resources/serverless"})
+		require.Error(t, err)
+	})
+}
@@ -3,28 +3,33 @@ package git
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
 	// "github.com/go-git/go-git/config"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/pkg/errors"
 )
 
 var defaultCloner repoCloner = &remoteRepoCloner{}
 
+// SetTransport makes all git operations of the process use the given transport for HTTP(S) remotes,
+// for example to connect through a proxy or to trust additional CAs.
+func SetTransport(transport http.RoundTripper) {
+	httpClient := githttp.NewClient(&http.Client{Transport: transport})
+	client.InstallProtocol("http", httpClient)
+	client.InstallProtocol("https", httpClient)
+}
+
 // CloneRepo clones the repository in the given URL to the given dstPath and checks out the given revision.
 // revision can be 'main', a release version (e.g. 1.4.1), a commit hash (e.g. 34edf09a) or a PR (e.g. PR-9486).
+// Failed downloads are retried with the default retry policy. To fail over to mirrors, use Source.Clone.
 func CloneRepo(url, dstPath, rev string) error {
-	repo, err := defaultCloner.Clone(url, dstPath, true)
-	if err != nil {
-		return errors.Wrapf(err, "Error downloading repository (%s)", url)
-	}
-	if rev != "" {
-		return checkout(repo, url, rev)
-	}
-	return nil
+	return Source{URL: url}.Clone(dstPath, rev)
 }
 
 type repoCloner interface {
@@ -43,11 +48,11 @@ func (rc *remoteRepoCloner) Clone(url, path string, autoCheckout bool) (*git.Rep
 }
 
 // revision can be 'main', a release version (e.g. 1.4.1), a commit hash (e.g. 34edf09a) or a PR (e.g. PR-9486).
-func resolveRevision(repo *git.Repository, url, rev string) (*plumbing.Hash, error) {
+func resolveRevision(repo *git.Repository, src Source, rev string) (*plumbing.Hash, error) {
 	if strings.HasPrefix(rev, prPrefix) {
 		fetchPR(repo, strings.TrimPrefix(rev, prPrefix)) // to ensure that the rev hash can be checked out
 		err := error(nil)
-		rev, err = resolvePRrevision(url, rev)
+		rev, err = src.PullRequestHead(rev)
 		if err != nil {
 			return nil, err
 		}
@@ -60,12 +65,12 @@ func fetchPR(repo *git.Repository, prNmbr string) error {
 	return repo.Fetch(&git.FetchOptions{RefSpecs: refs})
 }
 
-func checkout(repo *git.Repository, url, rev string) error {
+func checkout(repo *git.Repository, src Source, rev string) error {
 	w, err := repo.Worktree()
 	if err != nil {
 		return errors.Wrap(err, "Error getting the worktree")
 	}
-	hash, err := resolveRevision(repo, url, rev)
+	hash, err := resolveRevision(repo, src, rev)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,159 @@
+package git
+
+import (
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
+	"github.com/pkg/errors"
+)
+
+// Source is a repository with mirrors. Remote operations are retried and fail over to the next mirror
+// if the repository is not reachable, so an outage of a single Git host does not block the installation.
+type Source struct {
+	// URL of the repository
+	URL string
+	// Mirrors are tried in order if the repository is not reachable
+	Mirrors []string
+	// Retry policy of each URL. Defaults to retry.Default().
+	Retry retry.Policy
+}
+
+// Clone clones the repository to the given dstPath and checks out the given revision.
+// revision can be 'main', a release version (e.g. 1.4.1), a commit hash (e.g. 34edf09a) or a PR (e.g. PR-9486).
+func (s Source) Clone(dstPath, rev string) error {
+	_, statErr := os.Stat(dstPath)
+	existed := statErr == nil
+
+	var repo *git.Repository
+	url, err := s.failover(func(url string) (err error) {
+		repo, err = defaultCloner.Clone(url, dstPath, true)
+		if err != nil && !existed {
+			// a partial clone would let the next attempt fail
+			os.RemoveAll(dstPath)
+		}
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Error downloading repository (%s)", strings.Join(s.urls(), ", "))
+	}
+	if rev != "" {
+		return checkout(repo, Source{URL: url, Retry: s.Retry}, rev)
+	}
+	return nil
+}
+
+// BranchHead finds the HEAD commit hash of the given branch
+func (s Source) BranchHead(branch string) (string, error) {
+	refs, url, err := s.listRefs()
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		if ref.Name().IsBranch() && ref.Name().Short() == branch {
+			return ref.Hash().String(), nil
+		}
+	}
+	return "", errors.Errorf("could not find HEAD of branch %s in %s", branch, url)
+}
+
+// Tag finds the commit hash of the given tag
+func (s Source) Tag(tag string) (string, error) {
+	refs, url, err := s.listRefs()
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		if ref.Name().IsTag() && ref.Name().Short() == tag {
+			return ref.Hash().String(), nil
+		}
+	}
+	return "", errors.Errorf("could not find tag %s in %s", tag, url)
+}
+
+// PullRequestHead finds the HEAD commit hash of the given pull request, e.g. PR-9486
+func (s Source) PullRequestHead(pr string) (string, error) {
+	refs, url, err := s.listRefs()
+	if err != nil {
+		return "", err
+	}
+
+	if strings.HasPrefix(pr, prPrefix) {
+		pr = strings.TrimLeft(pr, prPrefix)
+	}
+
+	for _, ref := range refs {
+		if strings.HasPrefix(ref.Name().String(), "refs/pull") && strings.HasSuffix(ref.Name().String(), "head") && strings.Contains(ref.Name().String(), pr) {
+			return ref.Hash().String(), nil
+		}
+	}
+	return "", errors.Errorf("could not find HEAD of pull request %s in %s", pr, url)
+}
+
+// ResolveCommit resolves a revision to the hash of the commit it currently points to, so the revision can be pinned.
+// revision can be a branch (e.g. 'main'), a release version (e.g. 1.4.1), a commit hash or a PR (e.g. PR-9486).
+// Abbreviated commit hashes are only resolved if a branch or tag points to the commit.
+func (s Source) ResolveCommit(rev string) (string, error) {
+	if strings.HasPrefix(rev, prPrefix) {
+		return s.PullRequestHead(rev)
+	}
+	if isHex(rev) && len(rev) == len(plumbing.ZeroHash.String()) {
+		return rev, nil
+	}
+
+	refs, url, err := s.listRefs()
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		if (ref.Name().IsBranch() || ref.Name().IsTag()) && ref.Name().Short() == rev {
+			return ref.Hash().String(), nil
+		}
+	}
+	if isHex(rev) {
+		for _, ref := range refs {
+			if strings.HasPrefix(ref.Hash().String(), rev) {
+				return ref.Hash().String(), nil
+			}
+		}
+	}
+	return "", errors.Errorf("could not resolve revision %s in %s", rev, url)
+}
+
+// listRefs lists the references of the first reachable URL and returns the URL
+func (s Source) listRefs() ([]*plumbing.Reference, string, error) {
+	var refs []*plumbing.Reference
+	url, err := s.failover(func(url string) (err error) {
+		refs, err = defaultLister.List(url)
+		return err
+	})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "could not list commits")
+	}
+	return refs, url, nil
+}
+
+// failover calls the operation with the URL and then with the mirrors, until it succeeds. Each URL is retried according to the retry policy.
+// It returns the URL the operation succeeded with or the error of the last URL.
+func (s Source) failover(operation func(url string) error) (string, error) {
+	policy := s.Retry
+	if policy == nil {
+		policy = retry.Default()
+	}
+	var err error
+	for _, url := range s.urls() {
+		err = policy.Do(func() error {
+			return operation(url)
+		})
+		if err == nil {
+			return url, nil
+		}
+	}
+	return "", err
+}
+
+func (s Source) urls() []string {
+	return append([]string{s.URL}, s.Mirrors...)
+}
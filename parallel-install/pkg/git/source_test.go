@@ -0,0 +1,97 @@
+package git
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
+	"github.com/stretchr/testify/require"
+)
+
+type flakyRefLister struct {
+	refs     []*plumbing.Reference
+	failures map[string]int
+	calls    []string
+}
+
+func (fl *flakyRefLister) List(repoURL string) ([]*plumbing.Reference, error) {
+	fl.calls = append(fl.calls, repoURL)
+	if fl.failures[repoURL] != 0 {
+		fl.failures[repoURL]--
+		return nil, errors.New("connection refused")
+	}
+	return fl.refs, nil
+}
+
+func TestSourceFailover(t *testing.T) {
+	origLister := defaultLister
+	defer func() { defaultLister = origLister }()
+
+	refs := []*plumbing.Reference{
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), plumbing.NewHash("34edf09a34edf09a34edf09a34edf09a34edf09a")),
+	}
+	source := Source{
+		URL:     "https://github.com/kyma-project/kyma",
+		Mirrors: []string{"https://mirror1.example.com/kyma", "https://mirror2.example.com/kyma"},
+		Retry:   retry.Fixed(0, 2),
+	}
+
+	t.Run("Transient failures are retried", func(t *testing.T) {
+		lister := &flakyRefLister{refs: refs, failures: map[string]int{source.URL: 1}}
+		defaultLister = lister
+
+		head, err := source.BranchHead("main")
+		require.NoError(t, err)
+		require.Equal(t, "34edf09a34edf09a34edf09a34edf09a34edf09a", head)
+		require.Equal(t, []string{source.URL, source.URL}, lister.calls)
+	})
+
+	t.Run("Unreachable repository fails over to the next mirror", func(t *testing.T) {
+		lister := &flakyRefLister{refs: refs, failures: map[string]int{source.URL: 2, source.Mirrors[0]: 2}}
+		defaultLister = lister
+
+		head, err := source.BranchHead("main")
+		require.NoError(t, err)
+		require.Equal(t, "34edf09a34edf09a34edf09a34edf09a34edf09a", head)
+		require.Equal(t, []string{source.URL, source.URL, source.Mirrors[0], source.Mirrors[0], source.Mirrors[1]}, lister.calls)
+	})
+
+	t.Run("Error if all mirrors are unreachable", func(t *testing.T) {
+		defaultLister = &flakyRefLister{refs: refs, failures: map[string]int{source.URL: 2, source.Mirrors[0]: 2, source.Mirrors[1]: 2}}
+
+		_, err := source.Tag("1.0.0")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection refused")
+	})
+}
+
+func TestResolveCommit(t *testing.T) {
+	origLister := defaultLister
+	defer func() { defaultLister = origLister }()
+
+	mainHash := "34edf09a34edf09a34edf09a34edf09a34edf09a"
+	tagHash := "9486f00d9486f00d9486f00d9486f00d9486f00d"
+	prHash := "1111222233334444555566667777888899990000"
+	defaultLister = &fakeRefLister{refs: []*plumbing.Reference{
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), plumbing.NewHash(mainHash)),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("1.20.0"), plumbing.NewHash(tagHash)),
+		plumbing.NewHashReference(plumbing.ReferenceName("refs/pull/9486/head"), plumbing.NewHash(prHash)),
+	}}
+	source := Source{URL: "https://github.com/kyma-project/kyma", Retry: retry.Fixed(0, 1)}
+
+	for rev, expected := range map[string]string{
+		"main":     mainHash,
+		"1.20.0":   tagHash,
+		"PR-9486":  prHash,
+		"34edf09a": mainHash,
+		tagHash:    tagHash,
+	} {
+		commit, err := source.ResolveCommit(rev)
+		require.NoError(t, err, rev)
+		require.Equal(t, expected, commit, rev)
+	}
+
+	_, err := source.ResolveCommit("feature")
+	require.Error(t, err)
+}
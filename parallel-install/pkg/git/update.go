@@ -0,0 +1,77 @@
+package git
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pkg/errors"
+)
+
+const remoteRefsPrefix = "refs/remotes/origin/"
+
+var defaultFetcher repoFetcher = &remoteRepoFetcher{}
+
+type repoFetcher interface {
+	Fetch(repo *git.Repository, url string) error
+}
+
+type remoteRepoFetcher struct {
+}
+
+// Fetch updates the remote branches and the tags of the repository from the given URL
+func (rf *remoteRepoFetcher) Fetch(repo *git.Repository, url string) error {
+	remote := git.NewRemote(repo.Storer, &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	})
+	err := remote.Fetch(&git.FetchOptions{
+		RefSpecs: []config.RefSpec{config.RefSpec("+refs/heads/*:" + remoteRefsPrefix + "*")},
+		Tags:     git.AllTags,
+		Force:    true,
+	})
+	if err == git.NoErrAlreadyUpToDate {
+		return nil
+	}
+	return err
+}
+
+// Update fetches the repository which was cloned to dstPath before and resets it to the given revision,
+// so a reused clone provides the same sources as a new one. Local changes in the clone are discarded.
+// revision can be 'main', a release version (e.g. 1.4.1), a commit hash (e.g. 34edf09a) or a PR (e.g. PR-9486).
+func (s Source) Update(dstPath, rev string) error {
+	repo, err := git.PlainOpen(dstPath)
+	if err != nil {
+		return errors.Wrapf(err, "Error opening repository %s", dstPath)
+	}
+	url, err := s.failover(func(url string) error {
+		return defaultFetcher.Fetch(repo, url)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Error fetching repository (%s)", strings.Join(s.urls(), ", "))
+	}
+
+	hash, err := resolveFetchedRevision(repo, Source{URL: url, Retry: s.Retry}, rev)
+	if err != nil {
+		return err
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		return errors.Wrap(err, "Error getting the worktree")
+	}
+	if err := w.Reset(&git.ResetOptions{Commit: *hash, Mode: git.HardReset}); err != nil {
+		return errors.Wrap(err, "Error resetting to revision")
+	}
+	return nil
+}
+
+// resolveFetchedRevision prefers the fetched remote branch over the local branch of the same name, which is not updated by fetching
+func resolveFetchedRevision(repo *git.Repository, src Source, rev string) (*plumbing.Hash, error) {
+	if !strings.HasPrefix(rev, prPrefix) {
+		if hash, err := repo.ResolveRevision(plumbing.Revision(remoteRefsPrefix + rev)); err == nil {
+			return hash, nil
+		}
+	}
+	return resolveRevision(repo, src, rev)
+}
@@ -0,0 +1,58 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/alcortesm/tgz"
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFetcher struct {
+	urls []string
+}
+
+func (ff *fakeFetcher) Fetch(repo *git.Repository, url string) error {
+	ff.urls = append(ff.urls, url)
+	return nil
+}
+
+// TestUpdate resets the dummy git repository of TestCloneRepo, whose HEAD is the commit tagged with 2.0.0, to an older revision
+func TestUpdate(t *testing.T) {
+	localRepoRootPath, err := tgz.Extract("testdata/repo.tgz")
+	defer func() {
+		require.NoError(t, os.RemoveAll(localRepoRootPath))
+	}()
+	require.NoError(t, err)
+	repoPath := path.Join(localRepoRootPath, "repo")
+
+	fetcher := &fakeFetcher{}
+	orgFetcher := defaultFetcher
+	defaultFetcher = fetcher
+	defer func() { defaultFetcher = orgFetcher }()
+
+	// local changes are discarded
+	require.NoError(t, ioutil.WriteFile(path.Join(repoPath, "README.md"), []byte("changed"), 0600))
+
+	err = Source{URL: "github.com/foo"}.Update(repoPath, "1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"github.com/foo"}, fetcher.urls)
+
+	repo, err := git.PlainOpen(repoPath)
+	require.NoError(t, err)
+	headRef, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(headRef.Hash())
+	require.NoError(t, err)
+	require.Equal(t, "Add README\n", commit.Message)
+
+	readme, err := ioutil.ReadFile(path.Join(repoPath, "README.md"))
+	require.NoError(t, err)
+	require.Equal(t, "Tiny repository for integration tests\n", string(readme))
+
+	err = Source{URL: "github.com/foo"}.Update(path.Join(localRepoRootPath, "missing"), "1.0.0")
+	require.Error(t, err)
+}
@@ -7,6 +7,7 @@ package helm
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -17,14 +18,15 @@ import (
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
 	"helm.sh/helm/v3/pkg/chartutil"
 
+	"helm.sh/helm/v3/pkg/storage"
 	"helm.sh/helm/v3/pkg/storage/driver"
 
 	"github.com/cenkalti/backoff/v4"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/postrender"
 	"helm.sh/helm/v3/pkg/release"
-	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
 const logPrefix = "[helm/client.go]"
@@ -39,11 +41,25 @@ type Config struct {
 	Atomic                        bool
 	KymaComponentMetadataTemplate *KymaComponentMetadataTemplate
 	KubeconfigSource              config.KubeconfigSource
+	Repositories                  []config.HelmRepository  //Chart repositories used to resolve chart dependencies
+	Storage                       config.HelmStorageDriver //Storage driver of the releases, defaults to secrets
+	SQLConnectionString           string                   //Connection string of the sql storage driver
+	ChartMemoryBudget             int64                    //Upper limit of the summed chart sizes in bytes deployed in parallel, no limit if 0
+	CreateChunkSize               int                      //Number of resources created in parallel when a release is installed, no limit if 0
+	Proxy                         *config.ProxyConfig      //CA bundles trusted by chart repositories without own CA, disabled if nil
+	ClientTLS                     *config.ClientTLSConfig  //TLS settings of the chart repository connections, the defaults of Helm if nil
+	KeepKinds                     []string                 //Kinds of the release resources which are kept when a release is uninstalled
+	SkipUnchanged                 bool                     //Skips the upgrade of releases whose digest matches the digest of the deployed release
+	SeedDeployedValues            bool                     //Merges the values over the values of the deployed release instead of reusing the values on upgrades
+	Transformations               []Transformation         //Applied to the rendered resources of the components before they are deployed
 }
 
 //Client implements the ClientInterface.
 type Client struct {
-	cfg Config
+	cfg     Config
+	budget  *chartBudget
+	retries retryCounts
+	notes   releaseNotes
 }
 
 //ClientInterface defines the contract for the Helm-related installation processes.
@@ -75,7 +91,8 @@ type ClientInterface interface {
 //just create two different Client instances with different configurations.
 func NewClient(cfg Config) *Client {
 	return &Client{
-		cfg: cfg,
+		cfg:    cfg,
+		budget: newChartBudget(cfg.ChartMemoryBudget),
 	}
 }
 
@@ -97,10 +114,21 @@ func (c *Client) UninstallRelease(ctx context.Context, namespace, name string) e
 		return err
 	}
 
+	if len(c.cfg.KeepKinds) > 0 {
+		if err := keepResources(cfg, name, c.cfg.KeepKinds); err != nil {
+			return err
+		}
+	}
+
 	uninstall := action.NewUninstall(cfg)
 	uninstall.Timeout = time.Duration(c.cfg.HelmTimeoutSeconds) * time.Second
+	kubeClient := cfg.KubeClient
 
 	operation := func() error {
+		var releaseDeadline context.CancelFunc
+		cfg.KubeClient, releaseDeadline = c.withDeadline(kubeClient)
+		defer releaseDeadline()
+
 		c.cfg.Log.Infof("%s Starting uninstall for release %s in namespace %s", logPrefix, name, namespace)
 		rel, err := uninstall.Run(name)
 		if err != nil {
@@ -129,7 +157,7 @@ func (c *Client) UninstallRelease(ctx context.Context, namespace, name string) e
 
 	initialInterval := time.Duration(c.cfg.BackoffInitialIntervalSeconds) * time.Second
 	maxElapsedTime := time.Duration(c.cfg.BackoffMaxElapsedTimeSeconds) * time.Second
-	err = c.retryWithBackoff(ctx, operation, initialInterval, maxElapsedTime)
+	err = c.retryWithBackoff(ctx, c.retries.counted(namespace, name, operation), initialInterval, maxElapsedTime)
 	if err != nil {
 		return fmt.Errorf("Error: Failed to uninstall %s within the configured time. Error: %v", name, err)
 	}
@@ -137,12 +165,13 @@ func (c *Client) UninstallRelease(ctx context.Context, namespace, name string) e
 	return nil
 }
 
-func (c *Client) upgradeRelease(namespace, name string, overrides map[string]interface{}, cfg *action.Configuration, chart *chart.Chart) error {
+func (c *Client) upgradeRelease(namespace, name string, overrides map[string]interface{}, cfg *action.Configuration, chart *chart.Chart, postRenderer postrender.PostRenderer) error {
 	upgrade := action.NewUpgrade(cfg)
+	upgrade.PostRenderer = postRenderer
 	upgrade.Atomic = c.cfg.Atomic
 	upgrade.CleanupOnFail = true
 	upgrade.Wait = true
-	upgrade.ReuseValues = true
+	upgrade.ReuseValues = c.reuseValues()
 	upgrade.Recreate = false
 	upgrade.MaxHistory = c.cfg.MaxHistory
 	upgrade.Timeout = time.Duration(c.cfg.HelmTimeoutSeconds) * time.Second
@@ -170,17 +199,22 @@ func (c *Client) upgradeRelease(namespace, name string, overrides map[string]int
 		return err
 	}
 
+	c.notes.set(namespace, name, rel.Info.Notes)
 	return nil
 }
 
-func (c *Client) installRelease(namespace, name string, overrides map[string]interface{}, cfg *action.Configuration, chart *chart.Chart) error {
+func (c *Client) installRelease(namespace, name string, overrides map[string]interface{}, cfg *action.Configuration, chart *chart.Chart, postRenderer postrender.PostRenderer) error {
 	install := action.NewInstall(cfg)
+	install.PostRenderer = postRenderer
 	install.ReleaseName = name
 	install.Namespace = namespace
 	install.Atomic = c.cfg.Atomic
 	install.Wait = true
 	install.CreateNamespace = true
 	install.Timeout = time.Duration(c.cfg.HelmTimeoutSeconds) * time.Second
+	if c.cfg.CreateChunkSize > 0 {
+		cfg.KubeClient = &chunkedKubeClient{Interface: cfg.KubeClient, chunkSize: c.cfg.CreateChunkSize}
+	}
 
 	c.cfg.Log.Infof("%s Starting install for release %s in namespace %s", logPrefix, name, namespace)
 	rel, err := install.Run(chart, overrides)
@@ -205,6 +239,7 @@ func (c *Client) installRelease(namespace, name string, overrides map[string]int
 		return err
 	}
 
+	c.notes.set(namespace, name, rel.Info.Notes)
 	return nil
 }
 
@@ -235,40 +270,87 @@ func (c *Client) DeployRelease(ctx context.Context, chartDir, namespace, name st
 		}
 	}()
 
+	settings, cleanupSettings, err := repositorySettings(c.cfg.Repositories, c.cfg.Proxy)
+	if err != nil {
+		return err
+	}
+	built, err := buildDependencies(chartDir, settings, c.cfg.ClientTLS)
+	cleanupSettings()
+	if err != nil {
+		return err
+	}
+	if built {
+		c.cfg.Log.Infof("%s Built missing dependencies of chart %s", logPrefix, chartDir)
+	}
+
+	//the notes of a previous deployment are not reported if this one fails
+	c.notes.set(namespace, name, "")
+	//the chart directory is named after the component, which can differ from the release name
+	postRenderer := c.postRenderer(filepath.Base(chartDir))
 	operation := func() error {
-		cfg, err := c.newActionConfig(namespace, path)
+		ReportPhase(ctx, PhaseRendering)
+		chart, err := loader.Load(chartDir)
 		if err != nil {
 			return err
 		}
 
-		chart, err := loader.Load(chartDir)
+		profileValues, err := getProfileValues(*chart, profile)
 		if err != nil {
 			return err
 		}
 
-		profileValues, err := getProfileValues(*chart, profile)
+		comboValues := overrides.MergeMaps(profileValues, overridesValues)
+
+		releaseBudget, err := c.budget.acquire(ctx, chart)
 		if err != nil {
 			return err
 		}
+		defer releaseBudget()
 
-		comboValues := overrides.MergeMaps(profileValues, overridesValues)
+		//the deadline of the action starts after waiting for the memory budget
+		cfg, err := c.newActionConfig(namespace, path)
+		if err != nil {
+			return err
+		}
+		var releaseDeadline context.CancelFunc
+		cfg.KubeClient, releaseDeadline = c.withDeadline(cfg.KubeClient)
+		defer releaseDeadline()
+		if reporter := phaseReporter(ctx); reporter != nil {
+			cfg.KubeClient = &phaseReportingKubeClient{Interface: cfg.KubeClient, report: reporter}
+		}
 
 		isInstalled, err := c.isReleaseInstalled(ctx, namespace, name, cfg)
 		if err != nil {
 			return err
 		}
 
+		//the deployed values take precedence over the chart defaults and the profile, but not over the overrides
+		if isInstalled && c.cfg.SeedDeployedValues {
+			seededValues, err := seedDeployedValues(cfg, name, overridesValues)
+			if err != nil {
+				return err
+			}
+			comboValues = overrides.MergeMaps(profileValues, seededValues)
+		}
+
+		if isInstalled && c.cfg.SkipUnchanged {
+			skipped, err := c.skipUnchanged(namespace, name, comboValues, cfg, chart, postRenderer)
+			if err != nil || skipped {
+				return err
+			}
+		}
+
 		if isInstalled {
-			err = c.upgradeRelease(namespace, name, comboValues, cfg, chart)
+			err = c.upgradeRelease(namespace, name, comboValues, cfg, chart, postRenderer)
 		} else {
-			err = c.installRelease(namespace, name, comboValues, cfg, chart)
+			err = c.installRelease(namespace, name, comboValues, cfg, chart, postRenderer)
 		}
 		return err
 	}
 
 	initialInterval := time.Duration(c.cfg.BackoffInitialIntervalSeconds) * time.Second
 	maxElapsedTime := time.Duration(c.cfg.BackoffMaxElapsedTimeSeconds) * time.Second
-	err = c.retryWithBackoff(ctx, operation, initialInterval, maxElapsedTime)
+	err = c.retryWithBackoff(ctx, c.retries.counted(namespace, name, operation), initialInterval, maxElapsedTime)
 	if err != nil {
 		return fmt.Errorf("Error: Failed to deploy %s within the configured time. Error: %v", name, err)
 	}
@@ -292,24 +374,8 @@ func (c *Client) isReleaseInstalled(ctx context.Context, namespace, name string,
 	}
 
 	//ensure last release is in consistent status
-	relsCount := len(rels)
-	lastRelease := rels[relsCount-1]
-	if c.isPendingReleaseStatus(lastRelease.Info.Status) {
-		c.cfg.Log.Infof("%s Release '%s' is in pending state '%s': starting cleanup", logPrefix, name, lastRelease.Info.Status)
-		if relsCount > 1 {
-			//rollback to previous release
-			c.cfg.Log.Infof("%s Release '%s' was already installed before: trigger rollback of pending release", logPrefix, name)
-			if err := c.rollbackRelease(name, cfg); err != nil {
-				return true, err
-			}
-		} else {
-			//first release installation wasn't finished: delete incomplete release
-			c.cfg.Log.Infof("%s Release '%s' was not installed before: trigger uninstall of pending release", logPrefix, name)
-			if err := c.UninstallRelease(ctx, namespace, name); err != nil {
-				return false, err
-			}
-			return false, nil
-		}
+	if c.isPendingReleaseStatus(rels[len(rels)-1].Info.Status) {
+		return c.repairStuckRelease(ctx, cfg, name, rels)
 	}
 
 	c.cfg.Log.Infof("%s Release '%s' is installed and has non-pending status", logPrefix, name)
@@ -352,27 +418,49 @@ func (c *Client) retryWithBackoff(ctx context.Context, operation func() error, i
 }
 
 func (c *Client) newActionConfig(namespace string, kubeconfigPath string) (*action.Configuration, error) {
-	clientGetter := genericclioptions.NewConfigFlags(false)
-	clientGetter.Namespace = &namespace
-	clientGetter.KubeConfig = &kubeconfigPath
+	clientGetter := c.newClientGetter(namespace, kubeconfigPath)
 
 	cfg := new(action.Configuration)
 
-	debugLogFunc := func(format string, args ...interface{}) { //leverage debugLog function to use logger instance
-		c.cfg.Log.Info(fmt.Sprintf(format, args...))
+	debugLogFunc := c.debugLog
+	helmDriver := string(config.HelmStorageSecrets)
+	if c.cfg.Storage == config.HelmStorageConfigMaps {
+		helmDriver = string(config.HelmStorageConfigMaps)
 	}
-	if err := cfg.Init(clientGetter, namespace, "secrets", debugLogFunc); err != nil {
+	if err := cfg.Init(clientGetter, namespace, helmDriver, debugLogFunc); err != nil {
 		return nil, err
 	}
+	if c.cfg.Storage == config.HelmStorageSQL {
+		//the connection string is passed explicitly instead of through the HELM_DRIVER_SQL_CONNECTION_STRING environment variable
+		sqlDriver, err := driver.NewSQL(c.cfg.SQLConnectionString, debugLogFunc, namespace)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Releases = storage.Init(sqlDriver)
+	}
 
 	return cfg, nil
 }
 
+//debugLog is the debug log function of Helm, which logs with the logger instance
+func (c *Client) debugLog(format string, args ...interface{}) {
+	c.cfg.Log.Info(fmt.Sprintf(format, args...))
+}
+
 func (c *Client) updateKymaMetadata(cfg *action.Configuration, rel *release.Release) error {
 	//add Kyma metadata to Helm release secret
+	tpl := c.cfg.KymaComponentMetadataTemplate
+	if c.cfg.SkipUnchanged && tpl != nil {
+		digest, err := releaseDigest(rel)
+		if err != nil {
+			c.cfg.Log.Errorf("%s Error: %v", logPrefix, err)
+			return err
+		}
+		tpl = tpl.withDigest(digest)
+	}
 	kubeClient, err := cfg.KubernetesClientSet()
 	if err == nil {
-		err = (&KymaMetadataProvider{kubeClient: kubeClient}).Set(rel, c.cfg.KymaComponentMetadataTemplate)
+		err = (&KymaMetadataProvider{kubeClient: kubeClient, storage: c.cfg.Storage}).Set(rel, tpl)
 	}
 	if err != nil {
 		c.cfg.Log.Errorf("%s Error: %v", logPrefix, err)
@@ -0,0 +1,73 @@
+package helm
+
+import (
+	"sync"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+//tlsClientGetter restricts the Kubernetes clients of Helm to the TLS versions and cipher suites of the installer.
+//The kubeconfig and the namespace are loaded by the embedded ConfigFlags.
+type tlsClientGetter struct {
+	*genericclioptions.ConfigFlags
+	tls *config.ClientTLSConfig
+
+	lock            sync.Mutex
+	discoveryClient discovery.CachedDiscoveryInterface
+}
+
+//newClientGetter returns the client getter of Helm for the kubeconfig file and the namespace
+func (c *Client) newClientGetter(namespace string, kubeconfigPath string) genericclioptions.RESTClientGetter {
+	configFlags := genericclioptions.NewConfigFlags(false)
+	configFlags.Namespace = &namespace
+	configFlags.KubeConfig = &kubeconfigPath
+	if c.cfg.KubeconfigSource.TLS == nil {
+		return configFlags
+	}
+	return &tlsClientGetter{ConfigFlags: configFlags, tls: c.cfg.KubeconfigSource.TLS}
+}
+
+//ToRESTConfig implements genericclioptions.RESTClientGetter
+func (g *tlsClientGetter) ToRESTConfig() (*rest.Config, error) {
+	restConfig, err := g.ConfigFlags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	restConfig.Wrap(g.tls.WrapTransport)
+	return restConfig, nil
+}
+
+//ToDiscoveryClient implements genericclioptions.RESTClientGetter. The discovery client is created once and caches in memory.
+func (g *tlsClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.discoveryClient != nil {
+		return g.discoveryClient, nil
+	}
+	restConfig, err := g.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	g.discoveryClient = memory.NewMemCacheClient(discoveryClient)
+	return g.discoveryClient, nil
+}
+
+//ToRESTMapper implements genericclioptions.RESTClientGetter
+func (g *tlsClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	discoveryClient, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
+	return restmapper.NewShortcutExpander(mapper, discoveryClient), nil
+}
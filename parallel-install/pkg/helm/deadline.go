@@ -0,0 +1,145 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/kube"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+//notReadyPattern matches the messages the Helm wait logs for resources which are not ready yet,
+//e.g. "Deployment is not ready: kyma-system/api-gateway. 0 out of 1 expected pods are ready"
+var notReadyPattern = regexp.MustCompile(`^\w+ (is not ready|is not completed|is not bound|is failed|does not have [a-z ]+): `)
+
+//withDeadline bounds the waits of the Kubernetes client by the Helm timeout, starting now.
+//The returned function releases the deadline contexts and has to be called when the action is finished.
+func (c *Client) withDeadline(kubeClient kube.Interface) (kube.Interface, context.CancelFunc) {
+	timeout := time.Duration(c.cfg.HelmTimeoutSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	bounded := &deadlineKubeClient{Interface: kubeClient, ctx: ctx, readiness: &readinessLog{log: c.debugLog}}
+	if helmKubeClient, ok := kubeClient.(*kube.Client); ok {
+		helmKubeClient.Log = bounded.readiness.record
+	}
+	if !c.cfg.Atomic {
+		return bounded, cancel
+	}
+	//an atomic action rolls back after a timeout, which gets another Helm timeout
+	cleanupCtx, cancelCleanup := context.WithTimeout(context.Background(), 2*timeout)
+	bounded.cleanupCtx = cleanupCtx
+	return bounded, func() {
+		cancel()
+		cancelCleanup()
+	}
+}
+
+//deadlineKubeClient bounds all waits of a Helm action, i.e. for the CRDs, the hooks, and the readiness of the resources,
+//by the deadline of the action context. Otherwise, every wait of an action could take the full Helm timeout.
+//When a wait times out, the error names the resource which was not ready.
+type deadlineKubeClient struct {
+	kube.Interface
+	ctx context.Context
+	//cleanupCtx bounds the waits after the deadline, e.g. of the rollback of an atomic action. Nothing may wait after the deadline if nil.
+	cleanupCtx context.Context
+	readiness  *readinessLog
+}
+
+func (c *deadlineKubeClient) Wait(resources kube.ResourceList, timeout time.Duration) error {
+	timeout, err := c.bound(timeout)
+	if err != nil {
+		return err
+	}
+	c.readiness.reset()
+	return c.describeTimeout(c.Interface.Wait(resources, timeout))
+}
+
+func (c *deadlineKubeClient) WaitWithJobs(resources kube.ResourceList, timeout time.Duration) error {
+	timeout, err := c.bound(timeout)
+	if err != nil {
+		return err
+	}
+	c.readiness.reset()
+	return c.describeTimeout(c.Interface.WaitWithJobs(resources, timeout))
+}
+
+func (c *deadlineKubeClient) WatchUntilReady(resources kube.ResourceList, timeout time.Duration) error {
+	timeout, err := c.bound(timeout)
+	if err != nil {
+		return err
+	}
+	err = c.Interface.WatchUntilReady(resources, timeout)
+	if err == wait.ErrWaitTimeout && len(resources) > 0 {
+		return fmt.Errorf("%v: hook %s %s/%s is not ready", err, resources[0].Mapping.GroupVersionKind.Kind, resources[0].Namespace, resources[0].Name)
+	}
+	return err
+}
+
+func (c *deadlineKubeClient) WaitAndGetCompletedPodPhase(name string, timeout time.Duration) (v1.PodPhase, error) {
+	timeout, err := c.bound(timeout)
+	if err != nil {
+		return v1.PodUnknown, err
+	}
+	return c.Interface.WaitAndGetCompletedPodPhase(name, timeout)
+}
+
+//bound limits the timeout of a wait to the remaining time of the action
+func (c *deadlineKubeClient) bound(timeout time.Duration) (time.Duration, error) {
+	ctx := c.ctx
+	if ctx.Err() != nil && c.cleanupCtx != nil {
+		ctx = c.cleanupCtx
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("Helm timeout exceeded: %v", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			return remaining, nil
+		}
+	}
+	return timeout, nil
+}
+
+//describeTimeout adds the resource which was not ready to the error of a timed out wait
+func (c *deadlineKubeClient) describeTimeout(err error) error {
+	if err != wait.ErrWaitTimeout {
+		return err
+	}
+	if notReady := c.readiness.last(); notReady != "" {
+		return fmt.Errorf("%v: %s", err, notReady)
+	}
+	return err
+}
+
+//readinessLog forwards the log of the Helm Kubernetes client and remembers the last resource which was reported as not ready
+type readinessLog struct {
+	log      func(string, ...interface{})
+	mu       sync.Mutex
+	notReady string
+}
+
+func (l *readinessLog) record(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if notReadyPattern.MatchString(msg) {
+		l.mu.Lock()
+		l.notReady = strings.TrimSpace(msg)
+		l.mu.Unlock()
+	}
+	l.log(format, args...)
+}
+
+func (l *readinessLog) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.notReady = ""
+}
+
+func (l *readinessLog) last() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.notReady
+}
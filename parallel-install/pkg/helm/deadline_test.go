@@ -0,0 +1,76 @@
+package helm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/kube"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+//waitingKubeClient records the timeouts of the waits and reports a resource as not ready if it times out
+type waitingKubeClient struct {
+	kube.Interface
+	timeouts []time.Duration
+	timeout  bool
+	log      func(string, ...interface{})
+}
+
+func (c *waitingKubeClient) Wait(resources kube.ResourceList, timeout time.Duration) error {
+	c.timeouts = append(c.timeouts, timeout)
+	if c.timeout {
+		c.log("beginning wait for %d resources with timeout of %v", len(resources), timeout)
+		c.log("Deployment is not ready: %s/%s. %d out of %d expected pods are ready", "kyma-system", "api-gateway", 0, 1)
+		return wait.ErrWaitTimeout
+	}
+	return nil
+}
+
+func Test_DeadlineKubeClient(t *testing.T) {
+	newDeadlineKubeClient := func(ctx context.Context, timeout bool) (*deadlineKubeClient, *waitingKubeClient) {
+		readiness := &readinessLog{log: func(string, ...interface{}) {}}
+		waiting := &waitingKubeClient{timeout: timeout, log: readiness.record}
+		return &deadlineKubeClient{Interface: waiting, ctx: ctx, readiness: readiness}, waiting
+	}
+
+	t.Run("Waits are bounded by the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		client, waiting := newDeadlineKubeClient(ctx, false)
+
+		require.NoError(t, client.Wait(nil, time.Second))
+		require.NoError(t, client.Wait(nil, time.Hour))
+		require.Equal(t, time.Second, waiting.timeouts[0])
+		require.True(t, waiting.timeouts[1] <= time.Minute)
+	})
+
+	t.Run("Waits fail after the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		client, waiting := newDeadlineKubeClient(ctx, false)
+
+		require.Error(t, client.Wait(nil, time.Minute))
+		require.Empty(t, waiting.timeouts)
+	})
+
+	t.Run("Cleanup waits after the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		cleanupCtx, cancelCleanup := context.WithTimeout(context.Background(), time.Minute)
+		defer cancelCleanup()
+		client, waiting := newDeadlineKubeClient(ctx, false)
+		client.cleanupCtx = cleanupCtx
+
+		require.NoError(t, client.Wait(nil, time.Hour))
+		require.True(t, waiting.timeouts[0] <= time.Minute)
+	})
+
+	t.Run("Timeouts name the resource which is not ready", func(t *testing.T) {
+		client, _ := newDeadlineKubeClient(context.Background(), true)
+
+		err := client.Wait(nil, time.Minute)
+		require.EqualError(t, err, "timed out waiting for the condition: Deployment is not ready: kyma-system/api-gateway. 0 out of 1 expected pods are ready")
+	})
+}
@@ -0,0 +1,136 @@
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+//dependencyBuildMutex serializes dependency builds, as they share the repository cache
+var dependencyBuildMutex sync.Mutex
+
+//systemCABundles are the locations of the system CA bundle of common Linux distributions
+var systemCABundles = []string{
+	"/etc/ssl/certs/ca-certificates.crt",
+	"/etc/pki/tls/certs/ca-bundle.crt",
+	"/etc/ssl/ca-bundle.pem",
+	"/etc/ssl/cert.pem",
+}
+
+//buildDependencies resolves the dependencies of the chart which are missing in its charts/ directory.
+//They are downloaded from the declared repositories or, for file:// repositories, packaged from the local chart.
+//Charts with vendored dependencies are left untouched. The repositories are accessed with the TLS settings if they are set.
+func buildDependencies(chartDir string, settings *cli.EnvSettings, clientTLS *config.ClientTLSConfig) (bool, error) {
+	chart, err := loader.Load(chartDir)
+	if err != nil {
+		return false, err
+	}
+	if chart.Metadata == nil || len(chart.Metadata.Dependencies) == 0 {
+		return false, nil
+	}
+	if err := action.CheckDependencies(chart, chart.Metadata.Dependencies); err == nil {
+		return false, nil
+	}
+
+	dependencyBuildMutex.Lock()
+	defer dependencyBuildMutex.Unlock()
+
+	getters, err := dependencyGetters(settings, clientTLS)
+	if err != nil {
+		return false, err
+	}
+	manager := &downloader.Manager{
+		Out:              ioutil.Discard,
+		ChartPath:        chartDir,
+		Getters:          getters,
+		RepositoryConfig: settings.RepositoryConfig,
+		RepositoryCache:  settings.RepositoryCache,
+	}
+	if err := manager.Build(); err != nil {
+		return false, errors.Wrapf(err, "Failed to build the dependencies of chart '%s'", chartDir)
+	}
+	return true, nil
+}
+
+//repositorySettings returns the Helm CLI settings extended by the configured repositories.
+//The repositories are added to a temporary copy of the repository config, which is removed by the returned cleanup function.
+//Repositories without own CA trust the CA bundles of the proxy config.
+//The proxy itself is read from the environment by Helm, see config.ProxyConfig.Export.
+func repositorySettings(repositories []config.HelmRepository, proxy *config.ProxyConfig) (*cli.EnvSettings, func(), error) {
+	settings := cli.New()
+	if len(repositories) == 0 {
+		return settings, func() {}, nil
+	}
+
+	repoFile := repo.NewFile()
+	if _, err := os.Stat(settings.RepositoryConfig); err == nil {
+		if repoFile, err = repo.LoadFile(settings.RepositoryConfig); err != nil {
+			return nil, nil, errors.Wrapf(err, "Failed to read Helm repository config '%s'", settings.RepositoryConfig)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "helm-repositories")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		os.RemoveAll(dir)
+	}
+	caBundle, err := proxyCABundle(proxy, dir)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	for _, repository := range repositories {
+		caFile := repository.CAFile
+		if caFile == "" {
+			caFile = caBundle
+		}
+		repoFile.Update(&repo.Entry{
+			Name:                  repository.Name,
+			URL:                   repository.URL,
+			Username:              repository.Username,
+			Password:              repository.Password,
+			CAFile:                caFile,
+			CertFile:              repository.CertFile,
+			KeyFile:               repository.KeyFile,
+			InsecureSkipTLSverify: repository.InsecureSkipTLSVerify,
+		})
+	}
+	settings.RepositoryConfig = filepath.Join(dir, "repositories.yaml")
+	if err := repoFile.WriteFile(settings.RepositoryConfig, 0600); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return settings, cleanup, nil
+}
+
+//proxyCABundle writes the system CAs and the CA bundles of the proxy config to a file in dir, as Helm replaces the system CAs by the CA file of a repository.
+//It returns an empty path if there are no CA bundles.
+func proxyCABundle(proxy *config.ProxyConfig, dir string) (string, error) {
+	if proxy == nil || len(proxy.CABundles) == 0 {
+		return "", nil
+	}
+	data, err := proxy.CAData()
+	if err != nil {
+		return "", err
+	}
+	for _, systemBundle := range systemCABundles {
+		if systemData, err := ioutil.ReadFile(systemBundle); err == nil {
+			data = append(append(systemData, '\n'), data...)
+			break
+		}
+	}
+	path := filepath.Join(dir, "ca-bundle.pem")
+	return path, ioutil.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,140 @@
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func Test_BuildDependencies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-dependencies")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFile := func(path, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	writeFile(filepath.Join(dir, "sub", "Chart.yaml"), "apiVersion: v2\nname: sub\nversion: 0.1.0\n")
+	writeFile(filepath.Join(dir, "component", "Chart.yaml"), `apiVersion: v2
+name: component
+version: 1.0.0
+dependencies:
+  - name: sub
+    version: 0.1.0
+    repository: file://../sub
+`)
+	writeFile(filepath.Join(dir, "vendored", "Chart.yaml"), "apiVersion: v2\nname: vendored\nversion: 1.0.0\n")
+
+	settings := cli.New()
+	settings.RepositoryConfig = filepath.Join(dir, "repositories.yaml")
+	settings.RepositoryCache = filepath.Join(dir, "cache")
+
+	t.Run("Missing dependencies are built", func(t *testing.T) {
+		built, err := buildDependencies(filepath.Join(dir, "component"), settings, nil)
+		require.NoError(t, err)
+		require.True(t, built)
+		require.FileExists(t, filepath.Join(dir, "component", "charts", "sub-0.1.0.tgz"))
+	})
+
+	t.Run("Existing dependencies are not built again", func(t *testing.T) {
+		built, err := buildDependencies(filepath.Join(dir, "component"), settings, nil)
+		require.NoError(t, err)
+		require.False(t, built)
+	})
+
+	t.Run("Charts without dependencies are left untouched", func(t *testing.T) {
+		built, err := buildDependencies(filepath.Join(dir, "vendored"), settings, nil)
+		require.NoError(t, err)
+		require.False(t, built)
+	})
+
+	t.Run("Unresolvable dependencies fail", func(t *testing.T) {
+		writeFile(filepath.Join(dir, "broken", "Chart.yaml"), `apiVersion: v2
+name: broken
+version: 1.0.0
+dependencies:
+  - name: missing
+    version: 0.1.0
+    repository: file://../missing
+`)
+		_, err := buildDependencies(filepath.Join(dir, "broken"), settings, nil)
+		require.Error(t, err)
+	})
+}
+
+func Test_RepositorySettings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-repositories")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	userConfig := filepath.Join(dir, "repositories.yaml")
+	userRepos := repo.NewFile()
+	userRepos.Add(&repo.Entry{Name: "stable", URL: "https://charts.example.com/stable"})
+	require.NoError(t, userRepos.WriteFile(userConfig, 0600))
+	os.Setenv("HELM_REPOSITORY_CONFIG", userConfig)
+	defer os.Unsetenv("HELM_REPOSITORY_CONFIG")
+
+	t.Run("Without repositories the Helm CLI settings are used", func(t *testing.T) {
+		settings, cleanup, err := repositorySettings(nil, nil)
+		require.NoError(t, err)
+		defer cleanup()
+		require.Equal(t, userConfig, settings.RepositoryConfig)
+	})
+
+	t.Run("Repositories are added to a copy of the repository config", func(t *testing.T) {
+		settings, cleanup, err := repositorySettings([]config.HelmRepository{{
+			Name:     "harbor",
+			URL:      "https://harbor.example.com/chartrepo/kyma",
+			Username: "robot",
+			Password: "secret",
+			CAFile:   "/etc/ssl/harbor-ca.crt",
+		}}, nil)
+		require.NoError(t, err)
+		require.NotEqual(t, userConfig, settings.RepositoryConfig)
+
+		repos, err := repo.LoadFile(settings.RepositoryConfig)
+		require.NoError(t, err)
+		require.True(t, repos.Has("stable"))
+		harbor := repos.Get("harbor")
+		require.NotNil(t, harbor)
+		require.Equal(t, "robot", harbor.Username)
+		require.Equal(t, "/etc/ssl/harbor-ca.crt", harbor.CAFile)
+
+		cleanup()
+		require.NoFileExists(t, settings.RepositoryConfig)
+
+		userRepos, err := repo.LoadFile(userConfig)
+		require.NoError(t, err)
+		require.False(t, userRepos.Has("harbor"))
+	})
+	t.Run("Repositories without CA trust the CA bundles of the proxy", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "proxy-ca")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		proxyCA := filepath.Join(dir, "proxy-ca.pem")
+		require.NoError(t, ioutil.WriteFile(proxyCA, []byte("-----BEGIN CERTIFICATE-----\nproxy\n-----END CERTIFICATE-----\n"), 0600))
+
+		settings, cleanup, err := repositorySettings([]config.HelmRepository{
+			{Name: "harbor", URL: "https://harbor.example.com/chartrepo/kyma", CAFile: "/etc/ssl/harbor-ca.crt"},
+			{Name: "public", URL: "https://charts.example.com"},
+		}, &config.ProxyConfig{CABundles: []string{proxyCA}})
+		require.NoError(t, err)
+		defer cleanup()
+
+		repos, err := repo.LoadFile(settings.RepositoryConfig)
+		require.NoError(t, err)
+		require.Equal(t, "/etc/ssl/harbor-ca.crt", repos.Get("harbor").CAFile)
+		caFile := repos.Get("public").CAFile
+		require.NotEmpty(t, caFile)
+		data, err := ioutil.ReadFile(caFile)
+		require.NoError(t, err)
+		require.Contains(t, string(data), "proxy")
+	})
+}
@@ -0,0 +1,78 @@
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/postrender"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+//digestLength is the number of hex characters of the digest, which has to fit into a label value
+const digestLength = 32
+
+//releaseDigest calculates the SHA-256 of the rendered manifest, the hooks, and the values of a release.
+//Releases rendered from the same chart with the same values have the same digest.
+func releaseDigest(rel *release.Release) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(rel.Manifest))
+	for _, hook := range rel.Hooks {
+		hash.Write([]byte(hook.Path))
+		hash.Write([]byte(hook.Manifest))
+	}
+	//maps are marshalled with sorted keys, so equal values result in equal bytes
+	values, err := json.Marshal(rel.Config)
+	if err != nil {
+		return "", fmt.Errorf("Failed to calculate the digest of release '%s': %v", rel.Name, err)
+	}
+	hash.Write(values)
+	return hex.EncodeToString(hash.Sum(nil))[:digestLength], nil
+}
+
+//skipUnchanged renders the release without applying it and compares its digest with the digest recorded for the deployed release.
+//If the digests match, only the Kyma metadata of the deployed release is updated and true is returned.
+func (c *Client) skipUnchanged(namespace, name string, overrides map[string]interface{}, cfg *action.Configuration, chart *chart.Chart, postRenderer postrender.PostRenderer) (bool, error) {
+	deployed, err := action.NewGet(cfg).Run(name)
+	if err != nil {
+		return false, err
+	}
+	if deployed.Info == nil || deployed.Info.Status != release.StatusDeployed {
+		return false, nil
+	}
+
+	kubeClient, err := cfg.KubernetesClientSet()
+	if err != nil {
+		return false, err
+	}
+	stored, err := (&KymaMetadataProvider{kubeClient: kubeClient, storage: c.cfg.Storage}).releaseDigest(namespace, name, deployed.Version)
+	if err != nil || stored == "" {
+		return false, err
+	}
+
+	//render the release like upgradeRelease does, but without applying it
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.DryRun = true
+	upgrade.Namespace = namespace
+	upgrade.ReuseValues = c.reuseValues()
+	upgrade.PostRenderer = postRenderer
+	rendered, err := upgrade.Run(name, chart, overrides)
+	if err != nil {
+		return false, err
+	}
+	digest, err := releaseDigest(rendered)
+	if err != nil {
+		return false, err
+	}
+	if digest != stored {
+		c.cfg.Log.Infof("%s Release '%s' changed since its last deployment", logPrefix, name)
+		return false, nil
+	}
+
+	c.cfg.Log.Infof("%s Release '%s' is unchanged, skipping the upgrade", logPrefix, name)
+	c.notes.set(namespace, name, deployed.Info.Notes)
+	return true, c.updateKymaMetadata(cfg, deployed)
+}
@@ -0,0 +1,91 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ReleaseDigest(t *testing.T) {
+	newRelease := func(manifest string, values map[string]interface{}) *release.Release {
+		return &release.Release{
+			Name:     "test",
+			Manifest: manifest,
+			Hooks:    []*release.Hook{{Path: "test/templates/job.yaml", Manifest: "kind: Job"}},
+			Config:   values,
+		}
+	}
+
+	t.Run("Same release", func(t *testing.T) {
+		digest, err := releaseDigest(newRelease("kind: Deployment", map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": true}}))
+		require.NoError(t, err)
+		require.Len(t, digest, digestLength)
+
+		same, err := releaseDigest(newRelease("kind: Deployment", map[string]interface{}{"b": map[string]interface{}{"c": true}, "a": 1}))
+		require.NoError(t, err)
+		require.Equal(t, digest, same)
+	})
+
+	t.Run("Changed manifest or values", func(t *testing.T) {
+		digest, err := releaseDigest(newRelease("kind: Deployment", map[string]interface{}{"a": 1}))
+		require.NoError(t, err)
+
+		changedManifest, err := releaseDigest(newRelease("kind: StatefulSet", map[string]interface{}{"a": 1}))
+		require.NoError(t, err)
+		require.NotEqual(t, digest, changedManifest)
+
+		changedValues, err := releaseDigest(newRelease("kind: Deployment", map[string]interface{}{"a": 2}))
+		require.NoError(t, err)
+		require.NotEqual(t, digest, changedValues)
+	})
+}
+
+func Test_MetadataDigest(t *testing.T) {
+	t.Run("Digest is recorded per release", func(t *testing.T) {
+		k8sMock := fake.NewSimpleClientset(
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sh.helm.release.v1.test.v1",
+					Namespace: "testNs",
+				},
+			},
+		)
+		metaProv := getKymaMetadataProvider(k8sMock)
+		err := metaProv.Set((&release.Release{Name: "test", Namespace: "testNs", Version: 1}), kymaCompMetaTpl.ForComponents().withDigest("0123456789abcdef0123456789abcdef"))
+		require.NoError(t, err)
+
+		digest, err := metaProv.releaseDigest("testNs", "test", 1)
+		require.NoError(t, err)
+		require.Equal(t, "0123456789abcdef0123456789abcdef", digest)
+
+		metadata, err := metaProv.Get("test")
+		require.NoError(t, err)
+		require.Equal(t, "0123456789abcdef0123456789abcdef", metadata.Digest)
+		require.False(t, metadata.Prerequisite)
+	})
+
+	t.Run("No digest recorded", func(t *testing.T) {
+		k8sMock := fake.NewSimpleClientset(
+			&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sh.helm.release.v1.test.v1",
+					Namespace: "testNs",
+					Labels:    expectedLabels,
+				},
+			},
+		)
+		metaProv := getKymaMetadataProvider(k8sMock).WithStorage(config.HelmStorageConfigMaps)
+		digest, err := metaProv.releaseDigest("testNs", "test", 1)
+		require.NoError(t, err)
+		require.Empty(t, digest)
+
+		digest, err = metaProv.releaseDigest("testNs", "test", 2)
+		require.NoError(t, err)
+		require.Empty(t, digest)
+	})
+}
@@ -0,0 +1,245 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+//ReleaseDrift reports the differences between a deployed release, the release rendered with the current chart and values,
+//and the objects running in the cluster
+type ReleaseDrift struct {
+	Name      string
+	Namespace string
+	//NotInstalled is true if the release does not exist
+	NotInstalled bool
+	//ChangedValues lists the value paths which differ between the stored and the current values
+	ChangedValues []string
+	//ChangedManifests lists the resources which differ between the stored and the newly rendered manifest
+	ChangedManifests []string
+	//ModifiedResources lists the resources whose live state deviates from the stored manifest, e.g. due to manual edits
+	ModifiedResources []string
+	//MissingResources lists the resources of the stored manifest which do not exist in the cluster
+	MissingResources []string
+}
+
+//Drifted returns true if any difference was found
+func (d *ReleaseDrift) Drifted() bool {
+	return d.NotInstalled || len(d.ChangedValues) > 0 || len(d.ChangedManifests) > 0 ||
+		len(d.ModifiedResources) > 0 || len(d.MissingResources) > 0
+}
+
+func (d *ReleaseDrift) String() string {
+	if d.NotInstalled {
+		return fmt.Sprintf("%s/%s: not installed", d.Namespace, d.Name)
+	}
+	return fmt.Sprintf("%s/%s: changed values %v, changed manifests %v, modified resources %v, missing resources %v",
+		d.Namespace, d.Name, d.ChangedValues, d.ChangedManifests, d.ModifiedResources, d.MissingResources)
+}
+
+//DriftDetector is implemented by clients which are able to detect the drift of a release
+type DriftDetector interface {
+	//DetectDrift compares the deployed release against the release rendered from the chart with the given overrides and against the live objects.
+	DetectDrift(ctx context.Context, chartDir, namespace, name string, overrides map[string]interface{}, profile string) (*ReleaseDrift, error)
+}
+
+//DetectDrift implements DriftDetector.DetectDrift
+func (c *Client) DetectDrift(ctx context.Context, chartDir, namespace, name string, overridesValues map[string]interface{}, profile string) (*ReleaseDrift, error) {
+	path, cleanupFunc, err := config.Path(c.cfg.KubeconfigSource)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		cleanupErr := cleanupFunc()
+		if cleanupErr != nil {
+			c.cfg.Log.Error(cleanupErr)
+		}
+	}()
+
+	cfg, err := c.newActionConfig(namespace, path)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := &ReleaseDrift{Name: name, Namespace: namespace}
+
+	deployed, err := action.NewGet(cfg).Run(name)
+	if err != nil {
+		if err == driver.ErrReleaseNotFound {
+			drift.NotInstalled = true
+			return drift, nil
+		}
+		return nil, err
+	}
+
+	chart, err := loader.Load(chartDir)
+	if err != nil {
+		return nil, err
+	}
+	profileValues, err := getProfileValues(*chart, profile)
+	if err != nil {
+		return nil, err
+	}
+	comboValues := overrides.MergeMaps(profileValues, overridesValues)
+
+	drift.ChangedValues = diffValues(deployed.Config, comboValues, "")
+
+	//render the release without applying it
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.DryRun = true
+	upgrade.Namespace = namespace
+	rendered, err := upgrade.Run(name, chart, comboValues)
+	if err != nil {
+		return nil, err
+	}
+
+	deployedResources, err := c.buildResources(cfg, deployed.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	renderedResources, err := c.buildResources(cfg, rendered.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	drift.ChangedManifests = diffResources(deployedResources, renderedResources)
+
+	for _, key := range sortedKeys(deployedResources) {
+		expected := deployedResources[key]
+		info := expected.info
+		if err := info.Get(); err != nil {
+			if apierr.IsNotFound(err) {
+				drift.MissingResources = append(drift.MissingResources, key)
+				continue
+			}
+			return nil, err
+		}
+		live, ok := info.Object.(*unstructured.Unstructured)
+		if !ok || !isSubset(expected.object.Object, live.Object) {
+			drift.ModifiedResources = append(drift.ModifiedResources, key)
+		}
+	}
+
+	return drift, nil
+}
+
+type manifestResource struct {
+	object *unstructured.Unstructured
+	info   *resource.Info
+}
+
+//buildResources parses a release manifest into resources indexed by kind, namespace and name
+func (c *Client) buildResources(cfg *action.Configuration, manifest string) (map[string]manifestResource, error) {
+	resources, err := cfg.KubeClient.Build(bytes.NewBufferString(manifest), false)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]manifestResource)
+	for _, info := range resources {
+		obj, ok := info.Object.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s/%s", obj.GetKind(), info.Namespace, info.Name)
+		result[key] = manifestResource{object: obj.DeepCopy(), info: info}
+	}
+	return result, nil
+}
+
+//diffResources returns the keys of resources which were added, removed or changed
+func diffResources(deployed, rendered map[string]manifestResource) []string {
+	var changed []string
+	for key, d := range deployed {
+		r, ok := rendered[key]
+		if !ok || !reflect.DeepEqual(d.object.Object, r.object.Object) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range rendered {
+		if _, ok := deployed[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+//diffValues returns the paths of all leaf values which differ between the two maps
+func diffValues(old, new map[string]interface{}, prefix string) []string {
+	var changed []string
+	keys := make(map[string]bool)
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		oldMap, oldIsMap := old[k].(map[string]interface{})
+		newMap, newIsMap := new[k].(map[string]interface{})
+		if oldIsMap && newIsMap {
+			changed = append(changed, diffValues(oldMap, newMap, path)...)
+			continue
+		}
+		if !reflect.DeepEqual(old[k], new[k]) {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+//isSubset returns true if all fields defined in expected have the same value in actual.
+//Fields only set in actual, such as defaults and status added by the API server, are ignored.
+func isSubset(expected, actual interface{}) bool {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range e {
+			if !isSubset(v, a[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(e) {
+			return false
+		}
+		for i := range e {
+			if !isSubset(e[i], a[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(expected, actual)
+	}
+}
+
+func sortedKeys(m map[string]manifestResource) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
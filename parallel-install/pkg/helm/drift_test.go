@@ -0,0 +1,86 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_DiffValues(t *testing.T) {
+	old := map[string]interface{}{
+		"global": map[string]interface{}{
+			"domainName": "kyma.example.com",
+			"isLocal":    true,
+		},
+		"replicas": 1,
+	}
+	new := map[string]interface{}{
+		"global": map[string]interface{}{
+			"domainName": "kyma.local",
+			"isLocal":    true,
+		},
+		"replicas": 1,
+		"image":    "serverless:1.0",
+	}
+	require.Equal(t, []string{"global.domainName", "image"}, diffValues(old, new, ""))
+	require.Empty(t, diffValues(old, old, ""))
+}
+
+func Test_IsSubset(t *testing.T) {
+	expected := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"ports":    []interface{}{map[string]interface{}{"port": int64(80)}},
+		},
+	}
+
+	t.Run("Server-side fields are ignored", func(t *testing.T) {
+		actual := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"replicas":        int64(1),
+				"ports":           []interface{}{map[string]interface{}{"port": int64(80), "protocol": "TCP"}},
+				"sessionAffinity": "None",
+			},
+			"status": map[string]interface{}{},
+		}
+		require.True(t, isSubset(expected, actual))
+	})
+
+	t.Run("Manual edits are detected", func(t *testing.T) {
+		actual := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+				"ports":    []interface{}{map[string]interface{}{"port": int64(80)}},
+			},
+		}
+		require.False(t, isSubset(expected, actual))
+	})
+
+	t.Run("Removed list entries are detected", func(t *testing.T) {
+		actual := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"replicas": int64(1),
+				"ports":    []interface{}{},
+			},
+		}
+		require.False(t, isSubset(expected, actual))
+	})
+}
+
+func Test_DiffResources(t *testing.T) {
+	cm := func(data string) manifestResource {
+		return manifestResource{object: &unstructured.Unstructured{Object: map[string]interface{}{"data": data}}}
+	}
+	deployed := map[string]manifestResource{
+		"ConfigMap/ns/a": cm("1"),
+		"ConfigMap/ns/b": cm("1"),
+		"ConfigMap/ns/c": cm("1"),
+	}
+	rendered := map[string]manifestResource{
+		"ConfigMap/ns/a": cm("1"),
+		"ConfigMap/ns/b": cm("2"),
+		"ConfigMap/ns/d": cm("1"),
+	}
+	require.Equal(t, []string{"ConfigMap/ns/b", "ConfigMap/ns/c", "ConfigMap/ns/d"}, diffResources(deployed, rendered))
+}
@@ -0,0 +1,128 @@
+package helm
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+//dependencyGetters returns the getters of the dependency builds. If TLS settings are set, HTTP downloads use a tlsGetter,
+//as the HTTP getter of Helm creates its own transport with the default TLS settings of Go.
+func dependencyGetters(settings *cli.EnvSettings, clientTLS *config.ClientTLSConfig) (getter.Providers, error) {
+	if clientTLS == nil {
+		return getter.All(settings), nil
+	}
+	repoFile := repo.NewFile()
+	if _, err := os.Stat(settings.RepositoryConfig); err == nil {
+		if repoFile, err = repo.LoadFile(settings.RepositoryConfig); err != nil {
+			return nil, errors.Wrapf(err, "Failed to read Helm repository config '%s'", settings.RepositoryConfig)
+		}
+	}
+	g := &tlsGetter{clientTLS: clientTLS, repositories: repoFile.Repositories}
+	httpProvider := getter.Provider{
+		Schemes: []string{"http", "https"},
+		New: func(options ...getter.Option) (getter.Getter, error) {
+			return g, nil
+		},
+	}
+	//the first provider of a scheme wins, so the other schemes keep the getters of Helm
+	return append(getter.Providers{httpProvider}, getter.All(settings)...), nil
+}
+
+//tlsGetter downloads repository indexes and charts with the TLS settings of the installer.
+//The options passed by Helm are opaque, so the credentials, the CA file, the client certificate, and the TLS verification
+//are taken from the repository entry whose URL the download URL starts with. Other URLs are downloaded without credentials.
+type tlsGetter struct {
+	clientTLS    *config.ClientTLSConfig
+	repositories []*repo.Entry
+}
+
+//Get implements getter.Getter
+func (g *tlsGetter) Get(href string, _ ...getter.Option) (*bytes.Buffer, error) {
+	entry := g.repository(href)
+	client, err := g.httpClient(entry)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodGet, href, nil)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && (entry.Username != "" || entry.Password != "") {
+		req.SetBasicAuth(entry.Username, entry.Password)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch %s : %s", href, resp.Status)
+	}
+	buf := bytes.NewBuffer(nil)
+	_, err = io.Copy(buf, resp.Body)
+	return buf, err
+}
+
+//repository returns the repository entry of the URL, nil if the URL belongs to no repository
+func (g *tlsGetter) repository(href string) *repo.Entry {
+	var result *repo.Entry
+	for _, entry := range g.repositories {
+		prefix := strings.TrimSuffix(entry.URL, "/") + "/"
+		if entry.URL == "" || !strings.HasPrefix(href, prefix) {
+			continue
+		}
+		//nested repository URLs prefer the most specific entry
+		if result == nil || len(entry.URL) > len(result.URL) {
+			result = entry
+		}
+	}
+	return result
+}
+
+//httpClient returns a client which uses the proxy of the environment, the TLS settings, and the TLS files of the repository entry
+func (g *tlsGetter) httpClient(entry *repo.Entry) (*http.Client, error) {
+	tlsConfig := &tls.Config{}
+	if err := g.clientTLS.Apply(tlsConfig); err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		if entry.CertFile != "" && entry.KeyFile != "" {
+			cert, err := tls.LoadX509KeyPair(entry.CertFile, entry.KeyFile)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to load the client certificate of repository '%s'", entry.Name)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		if entry.CAFile != "" {
+			data, err := ioutil.ReadFile(entry.CAFile)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to read the CA file of repository '%s'", entry.Name)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, errors.Errorf("CA file of repository '%s' contains no certificates", entry.Name)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		tlsConfig.InsecureSkipVerify = entry.InsecureSkipTLSverify
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DisableCompression: true,
+			Proxy:              http.ProxyFromEnvironment,
+			TLSClientConfig:    tlsConfig,
+		},
+	}, nil
+}
@@ -0,0 +1,73 @@
+package helm
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func Test_DependencyGetters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-getters")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "apiVersion: v1\nentries: {}\n")
+	}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	repoFile := repo.NewFile()
+	repoFile.Add(&repo.Entry{Name: "private", URL: server.URL + "/charts", Username: "user", Password: "secret", CAFile: caFile})
+	settings := cli.New()
+	settings.RepositoryConfig = filepath.Join(dir, "repositories.yaml")
+	require.NoError(t, repoFile.WriteFile(settings.RepositoryConfig, 0600))
+
+	get := func(clientTLS *config.ClientTLSConfig, url string) error {
+		getters, err := dependencyGetters(settings, clientTLS)
+		require.NoError(t, err)
+		g, err := getters.ByScheme("https")
+		require.NoError(t, err)
+		_, err = g.Get(url)
+		return err
+	}
+
+	t.Run("Without TLS settings, the getters of Helm are used", func(t *testing.T) {
+		getters, err := dependencyGetters(settings, nil)
+		require.NoError(t, err)
+		g, err := getters.ByScheme("https")
+		require.NoError(t, err)
+		_, ok := g.(*tlsGetter)
+		require.False(t, ok)
+	})
+
+	t.Run("Repository downloads use the TLS settings and the repository entry", func(t *testing.T) {
+		require.NoError(t, get(&config.ClientTLSConfig{MinVersion: "1.2"}, server.URL+"/charts/index.yaml"))
+	})
+
+	t.Run("Servers below the minimum TLS version are rejected", func(t *testing.T) {
+		require.Error(t, get(&config.ClientTLSConfig{MinVersion: "1.3"}, server.URL+"/charts/index.yaml"))
+	})
+
+	t.Run("URLs outside of the repositories get no credentials", func(t *testing.T) {
+		require.Error(t, get(&config.ClientTLSConfig{}, server.URL+"/other/index.yaml"))
+	})
+}
@@ -0,0 +1,82 @@
+package helm
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+//keepResources marks the resources of the given kinds in the manifest of the last release revision with the keep resource policy,
+//so that Helm does not delete them when the release is uninstalled
+func keepResources(cfg *action.Configuration, name string, kinds []string) error {
+	rel, err := cfg.Releases.Last(name)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return nil
+		}
+		return err
+	}
+	manifest, kept, err := annotateKeep(rel.Manifest, kinds)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to keep the resources of release %s", name)
+	}
+	if kept == 0 {
+		return nil
+	}
+	rel.Manifest = manifest
+	return cfg.Releases.Update(rel)
+}
+
+//annotateKeep adds the keep resource policy to the resources of the given kinds and returns the manifest and the number of kept resources
+func annotateKeep(manifest string, kinds []string) (string, int, error) {
+	keep := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		keep[kind] = true
+	}
+
+	manifests := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(manifests))
+	for key := range manifests {
+		keys = append(keys, key)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	var docs []string
+	var kept int
+	for _, key := range keys {
+		doc := manifests[key]
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return "", 0, err
+		}
+		kind, _ := obj["kind"].(string)
+		if !keep[kind] {
+			docs = append(docs, doc)
+			continue
+		}
+		metadata, _ := obj["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+			obj["metadata"] = metadata
+		}
+		annotations, _ := metadata["annotations"].(map[string]interface{})
+		if annotations == nil {
+			annotations = map[string]interface{}{}
+			metadata["annotations"] = annotations
+		}
+		annotations[kube.ResourcePolicyAnno] = kube.KeepPolicy
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return "", 0, err
+		}
+		docs = append(docs, strings.TrimSpace(string(data)))
+		kept++
+	}
+	return "---\n" + strings.Join(docs, "\n---\n") + "\n", kept, nil
+}
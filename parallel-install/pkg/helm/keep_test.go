@@ -0,0 +1,94 @@
+package helm
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+const keepManifest = `---
+# Source: eventing/crds/subscription.yaml
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: subscriptions.eventing.kyma-project.io
+---
+# Source: eventing/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller
+---
+# Source: eventing/templates/pvc.yaml
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  annotations:
+    owner: eventing
+`
+
+func Test_KeepResources(t *testing.T) {
+	kinds := []string{"CustomResourceDefinition", "PersistentVolumeClaim"}
+
+	t.Run("Annotate the resources of the kept kinds", func(t *testing.T) {
+		manifest, kept, err := annotateKeep(keepManifest, kinds)
+		require.NoError(t, err)
+		require.Equal(t, 2, kept)
+
+		resources := manifestResources(t, manifest)
+		require.Len(t, resources, 3)
+		require.Equal(t, "keep", annotation(resources[0], "helm.sh/resource-policy"))
+		require.Equal(t, "", annotation(resources[1], "helm.sh/resource-policy"))
+		require.Equal(t, "keep", annotation(resources[2], "helm.sh/resource-policy"))
+		require.Equal(t, "eventing", annotation(resources[2], "owner"))
+	})
+
+	t.Run("Update the last release revision", func(t *testing.T) {
+		cfg := &action.Configuration{Releases: storage.Init(driver.NewMemory())}
+		rel := &release.Release{Name: "eventing", Namespace: "kyma-system", Version: 1, Manifest: keepManifest, Info: &release.Info{Status: release.StatusDeployed}}
+		require.NoError(t, cfg.Releases.Create(rel))
+
+		require.NoError(t, keepResources(cfg, "eventing", kinds))
+
+		last, err := cfg.Releases.Last("eventing")
+		require.NoError(t, err)
+		require.Contains(t, last.Manifest, "helm.sh/resource-policy: keep")
+	})
+
+	t.Run("Ignore missing releases", func(t *testing.T) {
+		cfg := &action.Configuration{Releases: storage.Init(driver.NewMemory())}
+		require.NoError(t, keepResources(cfg, "eventing", kinds))
+	})
+}
+
+func manifestResources(t *testing.T, manifest string) []map[string]interface{} {
+	manifests := releaseutil.SplitManifests(manifest)
+	var keys []string
+	for key := range manifests {
+		keys = append(keys, key)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	var resources []map[string]interface{}
+	for _, key := range keys {
+		var obj map[string]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(manifests[key]), &obj))
+		resources = append(resources, obj)
+	}
+	return resources
+}
+
+func annotation(obj map[string]interface{}, name string) string {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	value, _ := annotations[name].(string)
+	return value
+}
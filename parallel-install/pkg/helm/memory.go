@@ -0,0 +1,88 @@
+package helm
+
+import (
+	"context"
+
+	"golang.org/x/sync/semaphore"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/kube"
+)
+
+//chartBudget limits the summed size of the charts a Client deploys in parallel.
+//Helm holds the rendered manifest and the parsed objects of a release in memory, so parallel deployments of large charts
+//multiply the memory consumption of the installer.
+type chartBudget struct {
+	sem  *semaphore.Weighted
+	size int64
+}
+
+//newChartBudget creates a budget of the given size in bytes. It returns nil, which means no limit, if the size is not positive.
+func newChartBudget(size int64) *chartBudget {
+	if size <= 0 {
+		return nil
+	}
+	return &chartBudget{
+		sem:  semaphore.NewWeighted(size),
+		size: size,
+	}
+}
+
+//acquire blocks until the chart fits into the budget and returns the function which releases it again.
+//Charts which are larger than the budget are deployed alone.
+func (b *chartBudget) acquire(ctx context.Context, ch *chart.Chart) (func(), error) {
+	if b == nil {
+		return func() {}, nil
+	}
+	weight := chartSize(ch)
+	if weight > b.size {
+		weight = b.size
+	}
+	if weight < 1 {
+		weight = 1
+	}
+	if err := b.sem.Acquire(ctx, weight); err != nil {
+		return nil, err
+	}
+	return func() { b.sem.Release(weight) }, nil
+}
+
+//chartSize estimates the memory required to deploy a chart by the size of its templates and files, including its dependencies
+func chartSize(ch *chart.Chart) int64 {
+	var size int64
+	for _, file := range ch.Templates {
+		size += int64(len(file.Data))
+	}
+	for _, file := range ch.Files {
+		size += int64(len(file.Data))
+	}
+	for _, dependency := range ch.Dependencies() {
+		size += chartSize(dependency)
+	}
+	return size
+}
+
+//chunkedKubeClient creates the resources of a release in chunks.
+//Helm creates all resources of a release in parallel, which keeps a request body per resource in memory at the same time.
+type chunkedKubeClient struct {
+	kube.Interface
+	chunkSize int
+}
+
+//Create creates the resources chunk by chunk and stops at the first failing chunk
+func (c *chunkedKubeClient) Create(resources kube.ResourceList) (*kube.Result, error) {
+	result := &kube.Result{}
+	for start := 0; start < len(resources); start += c.chunkSize {
+		end := start + c.chunkSize
+		if end > len(resources) {
+			end = len(resources)
+		}
+		chunkResult, err := c.Interface.Create(resources[start:end])
+		if chunkResult != nil {
+			result.Created = append(result.Created, chunkResult.Created...)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/kube"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+func Test_ChartSize(t *testing.T) {
+	dependency := &chart.Chart{
+		Templates: []*chart.File{{Name: "templates/cm.yaml", Data: make([]byte, 30)}},
+	}
+	ch := &chart.Chart{
+		Templates: []*chart.File{{Name: "templates/deployment.yaml", Data: make([]byte, 100)}},
+		Files:     []*chart.File{{Name: "files/dashboard.json", Data: make([]byte, 50)}},
+	}
+	ch.AddDependency(dependency)
+	require.Equal(t, int64(180), chartSize(ch))
+}
+
+func Test_ChartBudget(t *testing.T) {
+	large := &chart.Chart{Templates: []*chart.File{{Name: "templates/crds.yaml", Data: make([]byte, 200)}}}
+	small := &chart.Chart{Templates: []*chart.File{{Name: "templates/cm.yaml", Data: make([]byte, 40)}}}
+
+	t.Run("No budget", func(t *testing.T) {
+		budget := newChartBudget(0)
+		require.Nil(t, budget)
+		release, err := budget.acquire(context.Background(), large)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("Large charts are deployed alone", func(t *testing.T) {
+		budget := newChartBudget(100)
+		releaseLarge, err := budget.acquire(context.Background(), large)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = budget.acquire(ctx, small)
+		require.Error(t, err)
+
+		releaseLarge()
+		releaseSmall, err := budget.acquire(context.Background(), small)
+		require.NoError(t, err)
+		releaseOther, err := budget.acquire(context.Background(), small)
+		require.NoError(t, err)
+		releaseSmall()
+		releaseOther()
+	})
+}
+
+type recordingKubeClient struct {
+	kube.Interface
+	chunks []int
+	failAt int
+}
+
+func (c *recordingKubeClient) Create(resources kube.ResourceList) (*kube.Result, error) {
+	c.chunks = append(c.chunks, len(resources))
+	if len(c.chunks) == c.failAt {
+		return nil, fmt.Errorf("creation failed")
+	}
+	return &kube.Result{Created: resources}, nil
+}
+
+func Test_ChunkedKubeClient(t *testing.T) {
+	var resources kube.ResourceList
+	for i := 0; i < 7; i++ {
+		resources = append(resources, &resource.Info{Name: fmt.Sprintf("cm%d", i)})
+	}
+
+	t.Run("Resources are created in chunks", func(t *testing.T) {
+		recorder := &recordingKubeClient{}
+		result, err := (&chunkedKubeClient{Interface: recorder, chunkSize: 3}).Create(resources)
+		require.NoError(t, err)
+		require.Equal(t, []int{3, 3, 1}, recorder.chunks)
+		require.Equal(t, resources, result.Created)
+	})
+
+	t.Run("Creation stops at the first failing chunk", func(t *testing.T) {
+		recorder := &recordingKubeClient{failAt: 2}
+		result, err := (&chunkedKubeClient{Interface: recorder, chunkSize: 3}).Create(resources)
+		require.Error(t, err)
+		require.Equal(t, []int{3, 3}, recorder.chunks)
+		require.Len(t, result.Created, 3)
+	})
+}
@@ -24,6 +24,10 @@ type KymaComponentMetadataTemplate struct {
 	Component    bool   //indicator flag to which is always set to 'true' (used in lookups)
 	OperationID  string //unique ID used to distinguish versions with the same name
 	CreationTime int64  //timestamp when the version was installed
+	Tenant       string //tenant of a namespace-scoped installation, empty for cluster-wide installations
+	SourceType   string //type of the source the charts were taken from, empty if not recorded
+	SourceID     string //digest of the source the charts were taken from, empty if not recorded
+	Digest       string //digest of the rendered manifests and values of a release, only set by withDigest()
 	ready        bool   //indicates whether the the ForPrerequisites() or ForComponents() function was called
 }
 
@@ -45,11 +49,22 @@ func (kmt *KymaComponentMetadataTemplate) clone(isPrerequisiteTemplate bool) *Ky
 		Component:    kmt.Component,
 		OperationID:  kmt.OperationID,
 		CreationTime: kmt.CreationTime,
+		Tenant:       kmt.Tenant,
+		SourceType:   kmt.SourceType,
+		SourceID:     kmt.SourceID,
 		Prerequisite: isPrerequisiteTemplate,
 		ready:        true,
 	}
 }
 
+//withDigest creates a copy of the template instance which records the digest of a release
+func (kmt *KymaComponentMetadataTemplate) withDigest(digest string) *KymaComponentMetadataTemplate {
+	tpl := kmt.clone(kmt.Prerequisite)
+	tpl.ready = kmt.ready
+	tpl.Digest = digest
+	return tpl
+}
+
 //Build creates a KymaComponentMetadata
 func (kmt *KymaComponentMetadataTemplate) Build(namespace, name string) (*KymaComponentMetadata, error) {
 	if !kmt.ready {
@@ -69,6 +84,10 @@ func (kmt *KymaComponentMetadataTemplate) Build(namespace, name string) (*KymaCo
 		Namespace:    namespace,
 		Priority:     kymaComponentPriority,
 		Prerequisite: kmt.Prerequisite,
+		Tenant:       kmt.Tenant,
+		SourceType:   kmt.SourceType,
+		SourceID:     kmt.SourceID,
+		Digest:       kmt.Digest,
 	}
 	if err := compMeta.isValid(); err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("Kyma component '%s' is invalid", compMeta))
@@ -87,6 +106,10 @@ type KymaComponentMetadata struct {
 	Namespace    string
 	Priority     int64
 	Prerequisite bool
+	Tenant       string `structs:",omitempty"` //label is only set for namespace-scoped installations
+	SourceType   string `structs:",omitempty"` //label is only set if the source of the charts is known
+	SourceID     string `structs:",omitempty"` //label is only set if the source of the charts is known
+	Digest       string `structs:",omitempty"` //label is only set if unchanged releases are skipped
 }
 
 //isValid verifies the completeness of a metadata instance
@@ -174,6 +197,9 @@ type KymaVersion struct {
 	Profile      string
 	OperationID  string
 	CreationTime int64
+	Tenant       string
+	SourceType   string
+	SourceID     string
 	Components   []*KymaComponentMetadata
 }
 
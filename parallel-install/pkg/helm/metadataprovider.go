@@ -2,8 +2,10 @@ package helm
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -12,9 +14,9 @@ import (
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage"
-	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -63,6 +65,11 @@ func (err *kymaMetadataFieldUnknownError) Error() string {
 //KymaMetadataProvider enables access to Kyma component metadata and version information
 type KymaMetadataProvider struct {
 	kubeClient kubernetes.Interface
+	//storage is the Helm storage driver the releases are stored with. Defaults to secrets.
+	storage config.HelmStorageDriver
+	//tenant restricts the metadata to the releases of a tenant if tenantScoped is set
+	tenant       string
+	tenantScoped bool
 }
 
 //NewKymaMetadataProvider creates a new KymaMetadataProvider
@@ -89,6 +96,20 @@ func GetKymaMetadataProvider(kubeClient kubernetes.Interface) *KymaMetadataProvi
 	}
 }
 
+//WithStorage sets the Helm storage driver the releases are stored with
+func (mp *KymaMetadataProvider) WithStorage(driver config.HelmStorageDriver) *KymaMetadataProvider {
+	mp.storage = driver
+	return mp
+}
+
+//WithTenant restricts the metadata to the releases of a tenant. An empty tenant selects the releases of cluster-wide installations.
+//Without calling WithTenant, the releases of all installations are considered.
+func (mp *KymaMetadataProvider) WithTenant(tenant string) *KymaMetadataProvider {
+	mp.tenant = tenant
+	mp.tenantScoped = true
+	return mp
+}
+
 //Namespaces returns the set of installed Kyma namespaces
 func (mp *KymaMetadataProvider) Namespaces() ([]string, error) {
 	//get all secrets which are labeled as Kyma component
@@ -99,7 +120,7 @@ func (mp *KymaMetadataProvider) Namespaces() ([]string, error) {
 	options := metaV1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=true", mp.labelName(compField)),
 	}
-	secrets, err := mp.kubeClient.CoreV1().Secrets("").List(context.Background(), options)
+	secrets, err := mp.listReleaseObjects("", options)
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +131,7 @@ func (mp *KymaMetadataProvider) Namespaces() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	for _, secret := range secrets.Items {
+	for _, secret := range secrets {
 		if ns, ok := secret.Labels[mp.labelName(nsField)]; ok {
 			namespaces[ns] = true
 		}
@@ -133,7 +154,7 @@ func (mp *KymaMetadataProvider) Versions() (*KymaVersionSet, error) {
 	options := metaV1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=true", mp.labelName(compField)),
 	}
-	secrets, err := mp.kubeClient.CoreV1().Secrets("").List(context.Background(), options)
+	secrets, err := mp.listReleaseObjects("", options)
 	if err != nil {
 		return nil, err
 	}
@@ -143,8 +164,8 @@ func (mp *KymaMetadataProvider) Versions() (*KymaVersionSet, error) {
 	if err != nil {
 		return nil, err
 	}
-	secretsPerComp := make(map[string][]v1.Secret)
-	for _, secret := range secrets.Items {
+	secretsPerComp := make(map[string][]metaV1.ObjectMeta)
+	for _, secret := range secrets {
 		if name, ok := secret.Labels[mp.labelName(nameField)]; ok {
 			secretsPerComp[name] = append(secretsPerComp[name], secret)
 		}
@@ -159,8 +180,63 @@ func (mp *KymaMetadataProvider) Versions() (*KymaVersionSet, error) {
 	}, nil
 }
 
+//ReleasesBySelector returns the metadata of the installed Kyma releases whose Helm secrets match the label selector,
+//e.g. "kyma-project.io/install.namespace=kyma-system". Only the latest matching revision of each release is considered.
+//Releases which were not installed by Kyma are never returned. The releases are sorted by namespace and name.
+func (mp *KymaMetadataProvider) ReleasesBySelector(selector string) ([]*KymaComponentMetadata, error) {
+	compField, err := mp.structField("Component")
+	if err != nil {
+		return nil, err
+	}
+	labelSelector := fmt.Sprintf("%s=true", mp.labelName(compField))
+	if selector != "" {
+		labelSelector = fmt.Sprintf("%s,%s", labelSelector, selector)
+	}
+	secrets, err := mp.listReleaseObjects("", metaV1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	//group secrets by namespace and release name (releases with the same name can exist in different namespaces)
+	nameField, err := mp.structField("Name")
+	if err != nil {
+		return nil, err
+	}
+	type releaseKey struct {
+		namespace string
+		name      string
+	}
+	secretsPerRelease := make(map[releaseKey][]metaV1.ObjectMeta)
+	for _, secret := range secrets {
+		if name, ok := secret.Labels[mp.labelName(nameField)]; ok {
+			key := releaseKey{namespace: secret.Namespace, name: name}
+			secretsPerRelease[key] = append(secretsPerRelease[key], secret)
+		}
+	}
+
+	releases := make([]*KymaComponentMetadata, 0, len(secretsPerRelease))
+	for key, secrets := range secretsPerRelease {
+		latestSecret, err := mp.findLatestSecret(key.name, secrets)
+		if err != nil {
+			return nil, err
+		}
+		compMeta, err := mp.unmarshalMetadata(latestSecret)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, compMeta)
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		if releases[i].Namespace != releases[j].Namespace {
+			return releases[i].Namespace < releases[j].Namespace
+		}
+		return releases[i].Name < releases[j].Name
+	})
+	return releases, nil
+}
+
 //resolveKymaVersions creates KymaVersion instances from Helm Secret labels
-func (mp *KymaMetadataProvider) resolveKymaVersions(secretsPerComp map[string][]v1.Secret) ([]*KymaVersion, error) {
+func (mp *KymaMetadataProvider) resolveKymaVersions(secretsPerComp map[string][]metaV1.ObjectMeta) ([]*KymaVersion, error) {
 	versions := make(map[string]*KymaVersion) //we se the opsID as differentiator between the different versions
 	for compName, secrets := range secretsPerComp {
 		latestSecret, err := mp.findLatestSecret(compName, secrets)
@@ -179,6 +255,9 @@ func (mp *KymaMetadataProvider) resolveKymaVersions(secretsPerComp map[string][]
 				Profile:      compMeta.Profile,
 				OperationID:  compMeta.OperationID,
 				CreationTime: compMeta.CreationTime,
+				Tenant:       compMeta.Tenant,
+				SourceType:   compMeta.SourceType,
+				SourceID:     compMeta.SourceID,
 			}
 			versions[compMeta.OperationID] = kymaVersion
 		}
@@ -208,8 +287,8 @@ func (mp *KymaMetadataProvider) versionFromMap(versionMap map[string]*KymaVersio
 }
 
 //findLatestSecret returns the latest Helm secret of a component
-func (mp *KymaMetadataProvider) findLatestSecret(name string, secrets []v1.Secret) (*v1.Secret, error) {
-	var latestSecret v1.Secret
+func (mp *KymaMetadataProvider) findLatestSecret(name string, secrets []metaV1.ObjectMeta) (*metaV1.ObjectMeta, error) {
+	var latestSecret metaV1.ObjectMeta
 
 	//find latest Helm secret
 	latestChartVersion := -1
@@ -241,26 +320,42 @@ func (mp *KymaMetadataProvider) Set(release *release.Release, compMetaTpl *KymaC
 		return fmt.Errorf("No Kyma metadata factory provided for Helm release '%s' (namespace '%s')", release.Name, release.Namespace)
 	}
 
-	secretName := mp.secretName(release.Name, release.Version)
-	//get existing secret
-	secret, err := mp.kubeClient.CoreV1().Secrets(release.Namespace).Get(context.Background(), secretName, metaV1.GetOptions{})
+	metadata, err := compMetaTpl.Build(release.Namespace, release.Name)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			return &helmReleaseNotFoundError{name: secretName}
-		}
 		return err
 	}
+	secretName := mp.secretName(release.Name, release.Version)
+	switch mp.storage {
+	case config.HelmStorageConfigMaps:
+		return mp.setConfigMapMetadata(release.Namespace, secretName, metadata, false)
+	case config.HelmStorageSQL:
+		return mp.setConfigMapMetadata(release.Namespace, secretName, metadata, true)
+	}
 
-	//update secret
-	metadata, err := compMetaTpl.Build(release.Namespace, release.Name)
+	//patch the labels into the secret, an update of the whole secret could overwrite concurrent changes
+	patch, err := mp.labelPatch(metadata)
 	if err != nil {
 		return err
 	}
-	mp.marshalMetadata(secret, metadata)
-	_, err = mp.kubeClient.CoreV1().Secrets(release.Namespace).Update(context.Background(), secret, metaV1.UpdateOptions{})
+	_, err = mp.kubeClient.CoreV1().Secrets(release.Namespace).Patch(context.Background(), secretName, types.MergePatchType, patch, metaV1.PatchOptions{})
+	if errors.IsNotFound(err) {
+		return &helmReleaseNotFoundError{name: secretName}
+	}
 	return err
 }
 
+//labelPatch returns a JSON merge patch which sets the Kyma labels of the metadata and keeps all other fields of the object.
+//The API server applies it atomically, so parallel workers writing the labels of their releases cannot lose updates.
+func (mp *KymaMetadataProvider) labelPatch(metadata *KymaComponentMetadata) ([]byte, error) {
+	objectMeta := metaV1.ObjectMeta{}
+	mp.marshalMetadata(&objectMeta, metadata)
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": objectMeta.Labels,
+		},
+	})
+}
+
 //Get returns Kyma metadata of an installed component
 func (mp *KymaMetadataProvider) Get(name string) (*KymaComponentMetadata, error) {
 	secret, err := mp.latestSecret(name, "")
@@ -271,13 +366,13 @@ func (mp *KymaMetadataProvider) Get(name string) (*KymaComponentMetadata, error)
 }
 
 //latestSecret returns the latest Helm secret of a component
-func (mp *KymaMetadataProvider) latestSecret(name, namespace string) (*v1.Secret, error) {
-	secrets, err := mp.kubeClient.CoreV1().Secrets(namespace).List(context.Background(), metaV1.ListOptions{})
+func (mp *KymaMetadataProvider) latestSecret(name, namespace string) (*metaV1.ObjectMeta, error) {
+	secrets, err := mp.listReleaseObjects(namespace, metaV1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	latestSecret, err := mp.findLatestSecret(name, secrets.Items)
+	latestSecret, err := mp.findLatestSecret(name, secrets)
 	if err != nil {
 		return nil, err
 	}
@@ -289,12 +384,15 @@ func (mp *KymaMetadataProvider) latestSecret(name, namespace string) (*v1.Secret
 }
 
 //marshalMetadata creates a KymaComponentMetadata from secret labels
-func (mp *KymaMetadataProvider) marshalMetadata(secret *v1.Secret, metadata *KymaComponentMetadata) {
+func (mp *KymaMetadataProvider) marshalMetadata(secret *metaV1.ObjectMeta, metadata *KymaComponentMetadata) {
 	if secret.Labels == nil {
 		secret.Labels = make(map[string]string)
 	}
 	var labelValue string
 	for _, field := range structs.New(metadata).Fields() {
+		if field.IsZero() && strings.Contains(field.Tag("structs"), "omitempty") {
+			continue
+		}
 		switch field.Kind() {
 		case reflect.Bool:
 			labelValue = fmt.Sprintf("%t", field.Value())
@@ -309,7 +407,7 @@ func (mp *KymaMetadataProvider) marshalMetadata(secret *v1.Secret, metadata *Kym
 }
 
 //unmarshalMetadata converts a KymaComponentMetadata to secret labels
-func (mp *KymaMetadataProvider) unmarshalMetadata(secret *v1.Secret) (*KymaComponentMetadata, error) {
+func (mp *KymaMetadataProvider) unmarshalMetadata(secret *metaV1.ObjectMeta) (*KymaComponentMetadata, error) {
 	var metadata *KymaComponentMetadata = &KymaComponentMetadata{}
 	var typedValue interface{}
 	var err error
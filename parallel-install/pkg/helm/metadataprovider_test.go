@@ -1,15 +1,18 @@
 package helm
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"github.com/stretchr/testify/require"
 	"helm.sh/helm/v3/pkg/release"
 	"k8s.io/client-go/kubernetes"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 	k8st "k8s.io/client-go/testing"
 )
@@ -116,7 +119,7 @@ func Test_MetadataSet(t *testing.T) {
 		metaProv := getKymaMetadataProvider(k8sMock)
 		err := metaProv.Set((&release.Release{Name: "test", Namespace: "testNs", Version: 1}), kymaCompMetaTpl.ForComponents())
 		require.NoError(t, err)
-		require.Equal(t, expectedLabels, k8sMock.Fake.Actions()[1].(k8st.UpdateAction).GetObject().(*v1.Secret).GetObjectMeta().GetLabels())
+		require.Equal(t, expectedLabels, secretLabels(t, k8sMock, "testNs", "sh.helm.release.v1.test.v1"))
 	})
 
 	t.Run("Happy path - for prerequisites", func(t *testing.T) {
@@ -142,13 +145,42 @@ func Test_MetadataSet(t *testing.T) {
 		expectedLabelsCopy[KymaLabelPrefix+"priority"] = "2"
 		expectedLabelsCopy[KymaLabelPrefix+"prerequisite"] = "true"
 
-		require.Equal(t, expectedLabelsCopy, k8sMock.Fake.Actions()[1].(k8st.UpdateAction).GetObject().(*v1.Secret).GetObjectMeta().GetLabels())
+		require.Equal(t, expectedLabelsCopy, secretLabels(t, k8sMock, "testNs", "sh.helm.release.v1.test.v1"))
+	})
+
+	t.Run("Labels are patched", func(t *testing.T) {
+		k8sMock := fake.NewSimpleClientset(
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sh.helm.release.v1.test.v1",
+					Namespace: "testNs",
+					Labels:    map[string]string{"owner": "helm", "status": "deployed"},
+				},
+				Data: map[string][]byte{"release": []byte("release")},
+			},
+		)
+		metaProv := getKymaMetadataProvider(k8sMock)
+		err := metaProv.Set((&release.Release{Name: "test", Namespace: "testNs", Version: 1}), kymaCompMetaTpl.ForComponents())
+		require.NoError(t, err)
+
+		require.Len(t, k8sMock.Actions(), 1)
+		patch, ok := k8sMock.Actions()[0].(k8st.PatchAction)
+		require.True(t, ok)
+		require.Equal(t, types.MergePatchType, patch.GetPatchType())
+
+		labels := secretLabels(t, k8sMock, "testNs", "sh.helm.release.v1.test.v1")
+		require.Equal(t, "helm", labels["owner"])
+		require.Equal(t, "deployed", labels["status"])
+		require.Equal(t, "test", labels[KymaLabelPrefix+"name"])
+		secret, err := k8sMock.CoreV1().Secrets("testNs").Get(context.Background(), "sh.helm.release.v1.test.v1", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []byte("release"), secret.Data["release"])
 	})
 
 	t.Run("Release not found", func(t *testing.T) {
 		k8sMock := fake.NewSimpleClientset()
 		metaProv := getKymaMetadataProvider(k8sMock)
-		err := metaProv.Set((&release.Release{Name: "test", Namespace: "default", Version: 1}), (&KymaComponentMetadataTemplate{}))
+		err := metaProv.Set((&release.Release{Name: "test", Namespace: "default", Version: 1}), kymaCompMetaTpl.ForComponents())
 		require.Error(t, err)
 		require.Equal(t, err.Error(), (&helmReleaseNotFoundError{name: "sh.helm.release.v1.test.v1"}).Error())
 	})
@@ -356,8 +388,189 @@ func Test_Versions(t *testing.T) {
 	})
 }
 
+func Test_ReleasesBySelector(t *testing.T) {
+	releaseLabels := func(name, namespace string) map[string]string {
+		labels := make(map[string]string, len(expectedLabels))
+		for k, v := range expectedLabels {
+			labels[k] = v
+		}
+		labels[KymaLabelPrefix+"name"] = name
+		labels[KymaLabelPrefix+"namespace"] = namespace
+		return labels
+	}
+	k8sMock := fake.NewSimpleClientset(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sh.helm.release.v1.test.v1",
+				Namespace: "testNs",
+				Labels:    releaseLabels("test", "testNs"),
+			},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sh.helm.release.v1.test.v2",
+				Namespace: "testNs",
+				Labels:    releaseLabels("test", "testNs"),
+			},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sh.helm.release.v1.test.v1",
+				Namespace: "otherNs",
+				Labels:    releaseLabels("test", "otherNs"),
+			},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sh.helm.release.v1.other.v1",
+				Namespace: "testNs",
+				Labels:    map[string]string{"owner": "helm", "name": "other"},
+			},
+		},
+	)
+	metaProv := getKymaMetadataProvider(k8sMock)
+
+	t.Run("All Kyma releases", func(t *testing.T) {
+		releases, err := metaProv.ReleasesBySelector("")
+		require.NoError(t, err)
+		require.Len(t, releases, 2)
+		require.Equal(t, "otherNs", releases[0].Namespace)
+		require.Equal(t, "testNs", releases[1].Namespace)
+	})
+
+	t.Run("Releases of a namespace", func(t *testing.T) {
+		releases, err := metaProv.ReleasesBySelector(fmt.Sprintf("%snamespace=testNs", KymaLabelPrefix))
+		require.NoError(t, err)
+		require.Len(t, releases, 1)
+		require.Equal(t, "test", releases[0].Name)
+	})
+
+	t.Run("Releases not installed by Kyma", func(t *testing.T) {
+		releases, err := metaProv.ReleasesBySelector("owner=helm")
+		require.NoError(t, err)
+		require.Empty(t, releases)
+	})
+}
+
+func secretLabels(t *testing.T, client kubernetes.Interface, namespace, name string) map[string]string {
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return secret.Labels
+}
+
 func getKymaMetadataProvider(client kubernetes.Interface) *KymaMetadataProvider {
 	return &KymaMetadataProvider{
 		kubeClient: client,
 	}
 }
+
+func Test_MetadataStorage(t *testing.T) {
+	t.Run("ConfigMaps driver", func(t *testing.T) {
+		k8sMock := fake.NewSimpleClientset(
+			&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sh.helm.release.v1.test.v1",
+					Namespace: "testNs",
+				},
+			},
+		)
+		metaProv := getKymaMetadataProvider(k8sMock).WithStorage(config.HelmStorageConfigMaps)
+		err := metaProv.Set((&release.Release{Name: "test", Namespace: "testNs", Version: 1}), kymaCompMetaTpl.ForComponents())
+		require.NoError(t, err)
+
+		cm, err := k8sMock.CoreV1().ConfigMaps("testNs").Get(context.Background(), "sh.helm.release.v1.test.v1", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, expectedLabels, cm.Labels)
+
+		metadata, err := metaProv.Get("test")
+		require.NoError(t, err)
+		require.Equal(t, expectedKymaCompMetadata, metadata)
+
+		namespaces, err := metaProv.Namespaces()
+		require.NoError(t, err)
+		require.Equal(t, []string{"testNs"}, namespaces)
+	})
+
+	t.Run("ConfigMaps driver without release", func(t *testing.T) {
+		metaProv := getKymaMetadataProvider(fake.NewSimpleClientset()).WithStorage(config.HelmStorageConfigMaps)
+		err := metaProv.Set((&release.Release{Name: "test", Namespace: "testNs", Version: 1}), kymaCompMetaTpl.ForComponents())
+		require.Error(t, err)
+	})
+
+	t.Run("SQL driver keeps metadata in ConfigMaps", func(t *testing.T) {
+		k8sMock := fake.NewSimpleClientset()
+		metaProv := getKymaMetadataProvider(k8sMock).WithStorage(config.HelmStorageSQL)
+		err := metaProv.Set((&release.Release{Name: "test", Namespace: "testNs", Version: 1}), kymaCompMetaTpl.ForComponents())
+		require.NoError(t, err)
+
+		versions, err := metaProv.Versions()
+		require.NoError(t, err)
+		require.Len(t, versions.Versions, 1)
+		require.Equal(t, "123", versions.Versions[0].Version)
+
+		secrets, err := k8sMock.CoreV1().Secrets("").List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Empty(t, secrets.Items)
+	})
+	t.Run("Tenant metadata", func(t *testing.T) {
+		k8sMock := fake.NewSimpleClientset(
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sh.helm.release.v1.acme-test.v1",
+					Namespace: "acme-testNs",
+				},
+			},
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sh.helm.release.v1.test.v1",
+					Namespace: "testNs",
+					Labels:    expectedLabels,
+				},
+			},
+		)
+		tenantTpl := *kymaCompMetaTpl
+		tenantTpl.Tenant = "acme"
+		err := getKymaMetadataProvider(k8sMock).Set((&release.Release{Name: "acme-test", Namespace: "acme-testNs", Version: 1}), tenantTpl.ForComponents())
+		require.NoError(t, err)
+
+		versions, err := getKymaMetadataProvider(k8sMock).WithTenant("acme").Versions()
+		require.NoError(t, err)
+		require.Len(t, versions.Versions, 1)
+		require.Equal(t, "acme", versions.Versions[0].Tenant)
+		require.Equal(t, "acme-test", versions.Versions[0].Components[0].Name)
+
+		namespaces, err := getKymaMetadataProvider(k8sMock).WithTenant("").Namespaces()
+		require.NoError(t, err)
+		require.Equal(t, []string{"testNs"}, namespaces)
+
+		versions, err = getKymaMetadataProvider(k8sMock).Versions()
+		require.NoError(t, err)
+		require.Len(t, versions.InstalledComponents(), 2)
+	})
+	t.Run("Source metadata", func(t *testing.T) {
+		k8sMock := fake.NewSimpleClientset(
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sh.helm.release.v1.test.v1",
+					Namespace: "testNs",
+				},
+			},
+		)
+		sourceTpl := *kymaCompMetaTpl
+		sourceTpl.SourceType = "git"
+		sourceTpl.SourceID = "0123456789abcdef"
+		err := getKymaMetadataProvider(k8sMock).Set((&release.Release{Name: "test", Namespace: "testNs", Version: 1}), sourceTpl.ForComponents())
+		require.NoError(t, err)
+
+		secret, err := k8sMock.CoreV1().Secrets("testNs").Get(context.Background(), "sh.helm.release.v1.test.v1", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "git", secret.Labels["kyma-project.io/install.sourceType"])
+		require.Equal(t, "0123456789abcdef", secret.Labels["kyma-project.io/install.sourceID"])
+
+		versions, err := getKymaMetadataProvider(k8sMock).Versions()
+		require.NoError(t, err)
+		require.Len(t, versions.Versions, 1)
+		require.Equal(t, "git", versions.Versions[0].SourceType)
+		require.Equal(t, "0123456789abcdef", versions.Versions[0].Components[0].SourceID)
+	})
+}
@@ -0,0 +1,159 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+//listReleaseObjects returns the metadata of the objects which carry the Kyma labels of the Helm releases.
+//These are the release Secrets or ConfigMaps of Helm, or, for the SQL driver, ConfigMaps maintained by the installer.
+func (mp *KymaMetadataProvider) listReleaseObjects(namespace string, options metaV1.ListOptions) ([]metaV1.ObjectMeta, error) {
+	var objects []metaV1.ObjectMeta
+	switch mp.storage {
+	case config.HelmStorageConfigMaps, config.HelmStorageSQL:
+		configMaps, err := mp.kubeClient.CoreV1().ConfigMaps(namespace).List(context.Background(), options)
+		if err != nil {
+			return nil, err
+		}
+		for _, cm := range configMaps.Items {
+			objects = append(objects, cm.ObjectMeta)
+		}
+	default:
+		secrets, err := mp.kubeClient.CoreV1().Secrets(namespace).List(context.Background(), options)
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets.Items {
+			objects = append(objects, secret.ObjectMeta)
+		}
+	}
+	return mp.filterTenant(objects), nil
+}
+
+//filterTenant drops the objects which do not belong to the tenant of the provider
+func (mp *KymaMetadataProvider) filterTenant(objects []metaV1.ObjectMeta) []metaV1.ObjectMeta {
+	if !mp.tenantScoped {
+		return objects
+	}
+	tenantLabel := KymaLabelPrefix + "tenant"
+	var filtered []metaV1.ObjectMeta
+	for _, object := range objects {
+		if object.Labels[tenantLabel] == mp.tenant {
+			filtered = append(filtered, object)
+		}
+	}
+	return filtered
+}
+
+//setConfigMapMetadata adds the Kyma labels to the ConfigMap of a release.
+//If create is true, a missing ConfigMap is created, as the SQL driver does not store releases in the cluster.
+func (mp *KymaMetadataProvider) setConfigMapMetadata(namespace, name string, metadata *KymaComponentMetadata, create bool) error {
+	patch, err := mp.labelPatch(metadata)
+	if err != nil {
+		return err
+	}
+	configMaps := mp.kubeClient.CoreV1().ConfigMaps(namespace)
+	_, err = configMaps.Patch(context.Background(), name, types.MergePatchType, patch, metaV1.PatchOptions{})
+	if !errors.IsNotFound(err) {
+		return err
+	}
+	if !create {
+		return &helmReleaseNotFoundError{name: name}
+	}
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	mp.marshalMetadata(&cm.ObjectMeta, metadata)
+	_, err = configMaps.Create(context.Background(), cm, metaV1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		//created by a concurrent write in the meantime
+		_, err = configMaps.Patch(context.Background(), name, types.MergePatchType, patch, metaV1.PatchOptions{})
+	}
+	return err
+}
+
+//releaseDigest returns the digest recorded in the labels of a release revision, or an empty string if none is recorded
+func (mp *KymaMetadataProvider) releaseDigest(namespace, name string, version int) (string, error) {
+	objectName := mp.secretName(name, version)
+	var labels map[string]string
+	switch mp.storage {
+	case config.HelmStorageConfigMaps, config.HelmStorageSQL:
+		cm, err := mp.kubeClient.CoreV1().ConfigMaps(namespace).Get(context.Background(), objectName, metaV1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		if cm != nil {
+			labels = cm.Labels
+		}
+	default:
+		secret, err := mp.kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), objectName, metaV1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		if secret != nil {
+			labels = secret.Labels
+		}
+	}
+	digestField, err := mp.structField("Digest")
+	if err != nil {
+		return "", err
+	}
+	return labels[mp.labelName(digestField)], nil
+}
+
+//ReleaseOwnership returns the latest revision of a Helm release in the namespace, 0 if the release does not exist, and whether this revision
+//carries the Kyma metadata, i.e. whether the release was installed or adopted by Kyma. Releases of other tenants are considered as well.
+//The SQL driver does not store releases in the cluster, so its releases are never found.
+func (mp *KymaMetadataProvider) ReleaseOwnership(namespace, name string) (revision int, owned bool, err error) {
+	options := metaV1.ListOptions{LabelSelector: fmt.Sprintf("owner=helm,name=%s", name)}
+	var objects []metaV1.ObjectMeta
+	switch mp.storage {
+	case config.HelmStorageSQL:
+		return 0, false, nil
+	case config.HelmStorageConfigMaps:
+		configMaps, err := mp.kubeClient.CoreV1().ConfigMaps(namespace).List(context.Background(), options)
+		if err != nil {
+			return 0, false, err
+		}
+		for _, cm := range configMaps.Items {
+			objects = append(objects, cm.ObjectMeta)
+		}
+	default:
+		secrets, err := mp.kubeClient.CoreV1().Secrets(namespace).List(context.Background(), options)
+		if err != nil {
+			return 0, false, err
+		}
+		for _, secret := range secrets.Items {
+			objects = append(objects, secret.ObjectMeta)
+		}
+	}
+	if len(objects) == 0 {
+		return 0, false, nil
+	}
+
+	latest, err := mp.findLatestSecret(name, objects)
+	if err != nil {
+		return 0, false, err
+	}
+	revision, err = strconv.Atoi(strings.TrimPrefix(latest.Name, mp.secretPrefix(name)))
+	if err != nil {
+		return 0, false, err
+	}
+	compField, err := mp.structField("Component")
+	if err != nil {
+		return 0, false, err
+	}
+	return revision, latest.Labels[mp.labelName(compField)] == "true", nil
+}
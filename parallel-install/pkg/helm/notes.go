@@ -0,0 +1,38 @@
+package helm
+
+import (
+	"fmt"
+	"sync"
+)
+
+//NotesReader is implemented by clients which keep the rendered NOTES.txt of the deployed charts
+type NotesReader interface {
+	//Notes returns the rendered notes of the last successful deployment of a release, or an empty string if the chart has no notes
+	Notes(namespace, name string) string
+}
+
+//releaseNotes keeps the rendered notes of the last deployment per release. It is safe for concurrent use.
+type releaseNotes struct {
+	mu    sync.Mutex
+	notes map[string]string
+}
+
+func (r *releaseNotes) set(namespace, name, notes string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.notes == nil {
+		r.notes = make(map[string]string)
+	}
+	r.notes[fmt.Sprintf("%s/%s", namespace, name)] = notes
+}
+
+func (r *releaseNotes) get(namespace, name string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.notes[fmt.Sprintf("%s/%s", namespace, name)]
+}
+
+//Notes implements NotesReader
+func (c *Client) Notes(namespace, name string) string {
+	return c.notes.get(namespace, name)
+}
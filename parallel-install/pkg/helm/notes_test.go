@@ -0,0 +1,18 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Notes(t *testing.T) {
+	client := NewClient(Config{})
+	require.Empty(t, client.Notes("kyma-system", "console"))
+
+	client.notes.set("kyma-system", "console", "Open the console at https://console.kyma.example.com")
+	client.notes.set("kyma-system", "monitoring", "")
+	require.Equal(t, "Open the console at https://console.kyma.example.com", client.Notes("kyma-system", "console"))
+	require.Empty(t, client.Notes("kyma-system", "monitoring"))
+	require.Empty(t, client.Notes("default", "console"))
+}
@@ -0,0 +1,69 @@
+package helm
+
+import (
+	"context"
+	"time"
+
+	"helm.sh/helm/v3/pkg/kube"
+)
+
+//ReleasePhase is a step of a release deployment
+type ReleasePhase string
+
+const (
+	//PhaseRendering loads the chart and merges the values
+	PhaseRendering ReleasePhase = "Rendering"
+	//PhaseApplying creates or updates the resources of the release
+	PhaseApplying ReleasePhase = "Applying"
+	//PhaseWaiting waits for the resources of the release to become ready
+	PhaseWaiting ReleasePhase = "WaitingForReadiness"
+	//PhaseRollingBack rolls back a pending release
+	PhaseRollingBack ReleasePhase = "RollingBack"
+)
+
+type phaseReporterKey struct{}
+
+//WithPhaseReporter returns a context which makes DeployRelease report the phases of the deployment to the reporter.
+//The reporter is called by the goroutine which deploys the release and can be called several times with the same phase.
+func WithPhaseReporter(ctx context.Context, reporter func(ReleasePhase)) context.Context {
+	return context.WithValue(ctx, phaseReporterKey{}, reporter)
+}
+
+func phaseReporter(ctx context.Context) func(ReleasePhase) {
+	reporter, _ := ctx.Value(phaseReporterKey{}).(func(ReleasePhase))
+	return reporter
+}
+
+//ReportPhase reports the phase to the reporter of the context, if there is one.
+//Installation backends other than Helm use it to report their progress.
+func ReportPhase(ctx context.Context, phase ReleasePhase) {
+	if reporter := phaseReporter(ctx); reporter != nil {
+		reporter(phase)
+	}
+}
+
+//phaseReportingKubeClient reports when Helm applies the resources of a release and when it waits for them
+type phaseReportingKubeClient struct {
+	kube.Interface
+	report func(ReleasePhase)
+}
+
+func (c *phaseReportingKubeClient) Create(resources kube.ResourceList) (*kube.Result, error) {
+	c.report(PhaseApplying)
+	return c.Interface.Create(resources)
+}
+
+func (c *phaseReportingKubeClient) Update(original, target kube.ResourceList, force bool) (*kube.Result, error) {
+	c.report(PhaseApplying)
+	return c.Interface.Update(original, target, force)
+}
+
+func (c *phaseReportingKubeClient) Wait(resources kube.ResourceList, timeout time.Duration) error {
+	c.report(PhaseWaiting)
+	return c.Interface.Wait(resources, timeout)
+}
+
+func (c *phaseReportingKubeClient) WaitWithJobs(resources kube.ResourceList, timeout time.Duration) error {
+	c.report(PhaseWaiting)
+	return c.Interface.WaitWithJobs(resources, timeout)
+}
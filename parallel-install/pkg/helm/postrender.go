@@ -0,0 +1,120 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"helm.sh/helm/v3/pkg/postrender"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+//Transformation modifies the rendered resources of the components before they are applied,
+//for example to inject settings into all workloads without editing the values of each chart
+type Transformation interface {
+	//Transform modifies a rendered resource of the component in place
+	Transform(component string, resource map[string]interface{}) error
+}
+
+//Expansion is implemented by transformations which add resources to the rendered manifests of a component,
+//for example a PodDisruptionBudget per Deployment. Expand is called once with all transformed resources of the component.
+type Expansion interface {
+	Expand(component string, resources []map[string]interface{}) ([]map[string]interface{}, error)
+}
+
+//generatedComment marks the resources added by expansions in the manifest of a release
+const generatedComment = "# Generated by the Kyma installer\n"
+
+//transformingPostRenderer applies the transformations to each resource of the rendered manifests of a component
+type transformingPostRenderer struct {
+	component       string
+	transformations []Transformation
+}
+
+//renderedResource is a resource of the rendered manifests with its leading comments
+type renderedResource struct {
+	comments string
+	body     string
+	object   map[string]interface{}
+}
+
+//postRenderer returns the post-renderer of the component's releases, or nil without transformations,
+//so releases are rendered as before if no transformation is configured
+func (c *Client) postRenderer(component string) postrender.PostRenderer {
+	if len(c.cfg.Transformations) == 0 {
+		return nil
+	}
+	return &transformingPostRenderer{component: component, transformations: c.cfg.Transformations}
+}
+
+//Run implements postrender.PostRenderer. The comments of the manifests, such as the source template, are kept.
+func (r *transformingPostRenderer) Run(rendered *bytes.Buffer) (*bytes.Buffer, error) {
+	manifests := releaseutil.SplitManifests(rendered.String())
+	var resources []*renderedResource
+	var objects []map[string]interface{}
+	for i := 0; i < len(manifests); i++ {
+		comments, body := splitComments(manifests[fmt.Sprintf("manifest-%d", i)])
+		resource := &renderedResource{comments: comments, body: body}
+		if err := yaml.Unmarshal([]byte(body), &resource.object); err != nil {
+			return nil, fmt.Errorf("Failed to parse the rendered manifest of component '%s': %v", r.component, err)
+		}
+		resources = append(resources, resource)
+		if len(resource.object) == 0 {
+			continue
+		}
+		for _, transformation := range r.transformations {
+			if err := transformation.Transform(r.component, resource.object); err != nil {
+				obj := unstructured.Unstructured{Object: resource.object}
+				return nil, fmt.Errorf("Failed to transform %s '%s' of component '%s': %v", obj.GetKind(), obj.GetName(), r.component, err)
+			}
+		}
+		objects = append(objects, resource.object)
+	}
+
+	for _, transformation := range r.transformations {
+		expansion, ok := transformation.(Expansion)
+		if !ok {
+			continue
+		}
+		added, err := expansion.Expand(r.component, objects)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to add resources to component '%s': %v", r.component, err)
+		}
+		for _, object := range added {
+			resources = append(resources, &renderedResource{comments: generatedComment, object: object})
+		}
+		objects = append(objects, added...)
+	}
+
+	result := &bytes.Buffer{}
+	for _, resource := range resources {
+		body := resource.body
+		if len(resource.object) > 0 {
+			data, err := yaml.Marshal(resource.object)
+			if err != nil {
+				return nil, err
+			}
+			body = string(data)
+		}
+		fmt.Fprintf(result, "---\n%s%s\n", resource.comments, strings.TrimSuffix(body, "\n"))
+	}
+	return result, nil
+}
+
+//splitComments separates the leading comment lines of a manifest from its body
+func splitComments(manifest string) (string, string) {
+	var comments strings.Builder
+	lines := strings.SplitAfter(manifest, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "#") {
+			return comments.String(), strings.Join(lines[i:], "")
+		}
+		comments.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			comments.WriteString("\n")
+		}
+	}
+	return comments.String(), ""
+}
@@ -0,0 +1,107 @@
+package helm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+//labelTransformation labels the resources with the component
+type labelTransformation struct {
+	err error
+}
+
+func (l *labelTransformation) Transform(component string, resource map[string]interface{}) error {
+	if l.err != nil {
+		return l.err
+	}
+	metadata := resource["metadata"].(map[string]interface{})
+	metadata["labels"] = map[string]interface{}{"component": component}
+	return nil
+}
+
+//configMapExpansion adds a ConfigMap listing the kinds of the resources of the component
+type configMapExpansion struct {
+	labelTransformation
+}
+
+func (e *configMapExpansion) Expand(component string, resources []map[string]interface{}) ([]map[string]interface{}, error) {
+	var kinds []string
+	for _, resource := range resources {
+		kinds = append(kinds, fmt.Sprintf("%v", resource["kind"]))
+	}
+	return []map[string]interface{}{{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": component + "-kinds"},
+		"data":       map[string]interface{}{"kinds": strings.Join(kinds, ",")},
+	}}, nil
+}
+
+func Test_PostRenderer(t *testing.T) {
+	rendered := `---
+# Source: monitoring/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+# Source: monitoring/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+`
+
+	t.Run("No post-renderer without transformations", func(t *testing.T) {
+		require.Nil(t, NewClient(Config{}).postRenderer("monitoring"))
+	})
+
+	t.Run("Transform resources", func(t *testing.T) {
+		client := NewClient(Config{Transformations: []Transformation{&labelTransformation{}}})
+		result, err := client.postRenderer("monitoring").Run(bytes.NewBufferString(rendered))
+		require.NoError(t, err)
+		require.Equal(t, `---
+# Source: monitoring/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels:
+    component: monitoring
+  name: config
+---
+# Source: monitoring/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  labels:
+    component: monitoring
+  name: secret
+`, result.String())
+	})
+
+	t.Run("Add resources", func(t *testing.T) {
+		client := NewClient(Config{Transformations: []Transformation{&configMapExpansion{}}})
+		result, err := client.postRenderer("monitoring").Run(bytes.NewBufferString(rendered))
+		require.NoError(t, err)
+		require.Contains(t, result.String(), `---
+# Generated by the Kyma installer
+apiVersion: v1
+data:
+  kinds: ConfigMap,Secret
+kind: ConfigMap
+metadata:
+  name: monitoring-kinds
+`)
+	})
+
+	t.Run("Transformation fails", func(t *testing.T) {
+		client := NewClient(Config{Transformations: []Transformation{&labelTransformation{err: errors.New("failure")}}})
+		_, err := client.postRenderer("monitoring").Run(bytes.NewBufferString(rendered))
+		require.EqualError(t, err, "Failed to transform ConfigMap 'config' of component 'monitoring': failure")
+	})
+}
@@ -0,0 +1,52 @@
+package helm
+
+import (
+	"fmt"
+	"sync"
+)
+
+//RetryCounter is implemented by clients which count the retries of their operations
+type RetryCounter interface {
+	//Retries returns the number of retries of the last operation on a release
+	Retries(namespace, name string) int
+}
+
+//retryCounts keeps the number of retries of the last operation per release. It is safe for concurrent use.
+type retryCounts struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+//counted resets the retries of a release and returns the operation, which counts its repeated invocations
+func (r *retryCounts) counted(namespace, name string, operation func() error) func() error {
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	r.set(key, 0)
+	attempts := 0
+	return func() error {
+		if attempts > 0 {
+			r.set(key, attempts)
+		}
+		attempts++
+		return operation()
+	}
+}
+
+func (r *retryCounts) set(key string, retries int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counts == nil {
+		r.counts = make(map[string]int)
+	}
+	r.counts[key] = retries
+}
+
+func (r *retryCounts) get(namespace, name string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[fmt.Sprintf("%s/%s", namespace, name)]
+}
+
+//Retries implements RetryCounter
+func (c *Client) Retries(namespace, name string) int {
+	return c.retries.get(namespace, name)
+}
@@ -94,3 +94,24 @@ func TestBackoffWithCancel(t *testing.T) {
 	require.LessOrEqual(t, count, expectedMaxCount, "total retries count too big")
 	require.Less(t, int64(timeDiff), expectedMaxTime, "total time of retries outside the expected range")
 }
+
+func TestRetryCounts(t *testing.T) {
+	client := newClient()
+	var count int = 0
+	o := func() error {
+		count++
+		if count < 3 {
+			return errors.New("failure")
+		}
+		return nil
+	}
+
+	err := client.retryWithBackoff(context.TODO(), client.retries.counted("kyma-system", "serverless", o), 1*time.Millisecond, 100*time.Millisecond)
+
+	require.NoError(t, err)
+	require.Equal(t, 2, client.Retries("kyma-system", "serverless"))
+	require.Equal(t, 0, client.Retries("kyma-system", "eventing"))
+
+	client.retries.counted("kyma-system", "serverless", o)
+	require.Equal(t, 0, client.Retries("kyma-system", "serverless"))
+}
@@ -0,0 +1,52 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+)
+
+//Rollbacker is implemented by clients which can roll back releases
+type Rollbacker interface {
+	//RollbackRelease rolls back a release to the revision before its last installation or upgrade
+	RollbackRelease(ctx context.Context, namespace, name string) error
+}
+
+//RollbackRelease implements Rollbacker.
+//It fails if the release has no previous revision, i.e. it was installed and not upgraded.
+func (c *Client) RollbackRelease(ctx context.Context, namespace, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	path, cleanupFunc, err := config.Path(c.cfg.KubeconfigSource)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		cleanupErr := cleanupFunc()
+		if cleanupErr != nil {
+			c.cfg.Log.Error(cleanupErr)
+		}
+	}()
+
+	cfg, err := c.newActionConfig(namespace, path)
+	if err != nil {
+		return err
+	}
+
+	last, err := cfg.Releases.Last(name)
+	if err != nil {
+		return err
+	}
+	if last.Version <= 1 {
+		return fmt.Errorf("Release %s has no previous revision to roll back to", name)
+	}
+
+	var releaseDeadline context.CancelFunc
+	cfg.KubeClient, releaseDeadline = c.withDeadline(cfg.KubeClient)
+	defer releaseDeadline()
+	return c.rollbackRelease(name, cfg)
+}
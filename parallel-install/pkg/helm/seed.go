@@ -0,0 +1,38 @@
+package helm
+
+import (
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/overrides"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+//seedDeployedValues merges the values over the user-supplied values of the deployed revision of the release, as helm get values returns them.
+//So values which were set on the release outside of Kyma are kept unless the new values define them.
+//Without a deployed revision, the values are returned unchanged.
+func seedDeployedValues(cfg *action.Configuration, name string, values map[string]interface{}) (map[string]interface{}, error) {
+	rels, err := cfg.Releases.History(name)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return values, nil
+		}
+		return nil, err
+	}
+	var deployed *release.Release
+	for _, rel := range rels {
+		if rel.Info != nil && rel.Info.Status == release.StatusDeployed && (deployed == nil || rel.Version > deployed.Version) {
+			deployed = rel
+		}
+	}
+	if deployed == nil {
+		return values, nil
+	}
+	return overrides.MergeMaps(deployed.Config, values), nil
+}
+
+//reuseValues returns whether upgrades reuse the values of the current release.
+//Seeded values already contain the values of the deployed revision, so they are not reused a second time.
+func (c *Client) reuseValues() bool {
+	return !c.cfg.SeedDeployedValues
+}
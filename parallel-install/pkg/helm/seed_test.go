@@ -0,0 +1,61 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func Test_SeedDeployedValues(t *testing.T) {
+	newRelease := func(version int, status release.Status, values map[string]interface{}) *release.Release {
+		return &release.Release{Name: "eventing", Namespace: "kyma-system", Version: version, Info: &release.Info{Status: status}, Config: values}
+	}
+	newConfig := func(t *testing.T, rels ...*release.Release) *action.Configuration {
+		cfg := &action.Configuration{Releases: storage.Init(driver.NewMemory())}
+		for _, rel := range rels {
+			require.NoError(t, cfg.Releases.Create(rel))
+		}
+		return cfg
+	}
+
+	t.Run("Overrides take precedence over the deployed values", func(t *testing.T) {
+		cfg := newConfig(t,
+			newRelease(1, release.StatusSuperseded, map[string]interface{}{"replicas": 1}),
+			newRelease(2, release.StatusDeployed, map[string]interface{}{
+				"replicas":  3,
+				"resources": map[string]interface{}{"limits": map[string]interface{}{"memory": "1Gi", "cpu": "500m"}},
+			}),
+			newRelease(3, release.StatusFailed, map[string]interface{}{"replicas": 5}),
+		)
+
+		values, err := seedDeployedValues(cfg, "eventing", map[string]interface{}{
+			"resources": map[string]interface{}{"limits": map[string]interface{}{"memory": "2Gi"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"replicas":  3,
+			"resources": map[string]interface{}{"limits": map[string]interface{}{"memory": "2Gi", "cpu": "500m"}},
+		}, values)
+	})
+
+	t.Run("No deployed revision", func(t *testing.T) {
+		overrides := map[string]interface{}{"replicas": 2}
+
+		values, err := seedDeployedValues(newConfig(t), "eventing", overrides)
+		require.NoError(t, err)
+		require.Equal(t, overrides, values)
+
+		values, err = seedDeployedValues(newConfig(t, newRelease(1, release.StatusFailed, map[string]interface{}{"replicas": 5})), "eventing", overrides)
+		require.NoError(t, err)
+		require.Equal(t, overrides, values)
+	})
+
+	t.Run("Seeded upgrades do not reuse values", func(t *testing.T) {
+		require.True(t, NewClient(Config{}).reuseValues())
+		require.False(t, NewClient(Config{SeedDeployedValues: true}).reuseValues())
+	})
+}
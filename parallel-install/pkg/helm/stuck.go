@@ -0,0 +1,78 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+//repairStuckRelease repairs a release whose latest revision is stuck in a pending status, e.g. because a previous installer was killed.
+//Helm refuses to operate on such a release with "another operation is in progress".
+//A stuck upgrade or rollback is rolled back to the previous revision. If the rollback fails, the pending revisions are discarded instead.
+//A stuck first installation is discarded, so that the next installation adopts the resources it already created.
+//It returns whether the release is still installed after the repair.
+func (c *Client) repairStuckRelease(ctx context.Context, cfg *action.Configuration, name string, rels []*release.Release) (bool, error) {
+	lastRelease := rels[len(rels)-1]
+	c.cfg.Log.Infof("%s Release '%s' is stuck in pending state '%s': starting repair", logPrefix, name, lastRelease.Info.Status)
+
+	if len(rels) > 1 {
+		//rollback to previous release
+		c.cfg.Log.Infof("%s Release '%s' was already installed before: trigger rollback of pending release", logPrefix, name)
+		ReportPhase(ctx, PhaseRollingBack)
+		err := c.rollbackRelease(name, cfg)
+		if err == nil {
+			return true, nil
+		}
+		c.cfg.Log.Warnf("%s Rollback of release '%s' failed: discarding its pending revisions", logPrefix, name)
+	} else {
+		//first release installation wasn't finished: discard the incomplete release
+		c.cfg.Log.Infof("%s Release '%s' was not installed before: discarding the pending release", logPrefix, name)
+	}
+
+	remaining, err := discardPendingRevisions(cfg, name)
+	if err != nil {
+		return false, fmt.Errorf("Failed to repair stuck release '%s': %v", name, err)
+	}
+	return remaining > 0, nil
+}
+
+//discardPendingRevisions deletes the pending revisions of a release from the Helm storage.
+//If no deployed revision remains, the latest superseded revision is marked as deployed again.
+//It returns the number of remaining revisions.
+func discardPendingRevisions(cfg *action.Configuration, name string) (int, error) {
+	rels, err := cfg.Releases.History(name)
+	if err != nil {
+		return 0, err
+	}
+	releaseutil.SortByRevision(rels)
+
+	var remaining []*release.Release
+	for _, rel := range rels {
+		if rel.Info.Status.IsPending() {
+			if _, err := cfg.Releases.Delete(rel.Name, rel.Version); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		remaining = append(remaining, rel)
+	}
+
+	for _, rel := range remaining {
+		if rel.Info.Status == release.StatusDeployed {
+			return len(remaining), nil
+		}
+	}
+	if len(remaining) > 0 {
+		latest := remaining[len(remaining)-1]
+		if latest.Info.Status == release.StatusSuperseded {
+			latest.Info.Status = release.StatusDeployed
+			if err := cfg.Releases.Update(latest); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(remaining), nil
+}
@@ -0,0 +1,73 @@
+package helm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func Test_RepairStuckRelease(t *testing.T) {
+	newRelease := func(version int, status release.Status) *release.Release {
+		return &release.Release{Name: "eventing", Namespace: "kyma-system", Version: version, Info: &release.Info{Status: status}}
+	}
+	newConfig := func(t *testing.T, rels ...*release.Release) *action.Configuration {
+		cfg := &action.Configuration{Releases: storage.Init(driver.NewMemory())}
+		for _, rel := range rels {
+			require.NoError(t, cfg.Releases.Create(rel))
+		}
+		return cfg
+	}
+
+	t.Run("Discard a stuck first installation", func(t *testing.T) {
+		rel := newRelease(1, release.StatusPendingInstall)
+		cfg := newConfig(t, rel)
+
+		installed, err := newClient().repairStuckRelease(context.TODO(), cfg, "eventing", []*release.Release{rel})
+		require.NoError(t, err)
+		require.False(t, installed)
+
+		_, err = cfg.Releases.History("eventing")
+		require.Equal(t, driver.ErrReleaseNotFound, err)
+	})
+
+	t.Run("Discard pending revisions and restore the deployed revision", func(t *testing.T) {
+		cfg := newConfig(t,
+			newRelease(1, release.StatusSuperseded),
+			newRelease(2, release.StatusSuperseded),
+			newRelease(3, release.StatusPendingUpgrade),
+		)
+
+		remaining, err := discardPendingRevisions(cfg, "eventing")
+		require.NoError(t, err)
+		require.Equal(t, 2, remaining)
+
+		last, err := cfg.Releases.Last("eventing")
+		require.NoError(t, err)
+		require.Equal(t, 2, last.Version)
+		require.Equal(t, release.StatusDeployed, last.Info.Status)
+	})
+
+	t.Run("Keep the deployed revision", func(t *testing.T) {
+		cfg := newConfig(t,
+			newRelease(1, release.StatusDeployed),
+			newRelease(2, release.StatusFailed),
+			newRelease(3, release.StatusPendingRollback),
+		)
+
+		remaining, err := discardPendingRevisions(cfg, "eventing")
+		require.NoError(t, err)
+		require.Equal(t, 2, remaining)
+
+		deployed, err := cfg.Releases.Deployed("eventing")
+		require.NoError(t, err)
+		require.Equal(t, 1, deployed.Version)
+		last, err := cfg.Releases.Last("eventing")
+		require.NoError(t, err)
+		require.Equal(t, release.StatusFailed, last.Info.Status)
+	})
+}
@@ -0,0 +1,78 @@
+package images
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+//Result of the architecture check of an image
+type Result struct {
+	Image string
+	//Architectures of the cluster nodes the image does not support
+	Missing []string
+	//Mirror image which supports all architectures. Empty if the image supports them or no mirror does.
+	Mirror string
+	//Err is set if the image could not be checked
+	Err error
+}
+
+//OK returns true if the image supports all architectures
+func (r Result) OK() bool {
+	return r.Err == nil && len(r.Missing) == 0
+}
+
+//CheckArchitectures checks that the images support all architectures.
+//For images which do not, the image with the longest matching prefix in mirrors replaced is checked.
+func (r *Registry) CheckArchitectures(ctx context.Context, images []string, architectures []string, mirrors map[string]string) []Result {
+	results := make([]Result, 0, len(images))
+	for _, image := range images {
+		result := Result{Image: image}
+		result.Missing, result.Err = r.missingArchitectures(ctx, image, architectures)
+		if result.Err == nil && len(result.Missing) > 0 {
+			if mirror := mirrorOf(image, mirrors); mirror != "" {
+				missing, err := r.missingArchitectures(ctx, mirror, architectures)
+				if err == nil && len(missing) == 0 {
+					result.Mirror = mirror
+				}
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func (r *Registry) missingArchitectures(ctx context.Context, image string, architectures []string) ([]string, error) {
+	platforms, err := r.Platforms(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+	supported := map[string]bool{}
+	for _, platform := range platforms {
+		if platform.OS == "" || platform.OS == "linux" {
+			supported[platform.Architecture] = true
+		}
+	}
+	var missing []string
+	for _, arch := range architectures {
+		if !supported[arch] {
+			missing = append(missing, arch)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+//mirrorOf replaces the longest matching prefix of the image with its mirror
+func mirrorOf(image string, mirrors map[string]string) string {
+	var prefix string
+	for p := range mirrors {
+		if strings.HasPrefix(image, p) && len(p) > len(prefix) {
+			prefix = p
+		}
+	}
+	if prefix == "" {
+		return ""
+	}
+	return mirrors[prefix] + strings.TrimPrefix(image, prefix)
+}
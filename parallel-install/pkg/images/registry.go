@@ -0,0 +1,239 @@
+//Package images queries container registries for the platforms an image supports.
+//
+//Only anonymous access is supported, which covers the public images of Kyma and its mirrors.
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+
+	mediaTypeManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeOCIIndex     = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest  = "application/vnd.oci.image.manifest.v1+json"
+)
+
+//Platform an image is built for
+type Platform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+//Reference is a parsed image reference
+type Reference struct {
+	//Registry host, e.g. eu.gcr.io
+	Registry string
+	//Repository in the registry, e.g. kyma-project/function-controller
+	Repository string
+	//Tag or digest
+	Tag string
+}
+
+//ParseReference parses an image reference like eu.gcr.io/kyma-project/function-controller:v1.
+//Images without registry refer to Docker Hub, images without tag to latest.
+func ParseReference(image string) (Reference, error) {
+	if image == "" {
+		return Reference{}, fmt.Errorf("image reference is empty")
+	}
+	ref := Reference{Registry: dockerHubRegistry, Tag: "latest"}
+	name := image
+	if idx := strings.Index(name, "@"); idx >= 0 {
+		ref.Tag = name[idx+1:]
+		name = name[:idx]
+	} else if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		ref.Tag = name[idx+1:]
+		name = name[:idx]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry = parts[0]
+		name = parts[1]
+	}
+	if ref.Registry == dockerHubRegistry || ref.Registry == "docker.io" {
+		ref.Registry = dockerHubRegistry
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+	if name == "" {
+		return Reference{}, fmt.Errorf("image reference '%s' has no repository", image)
+	}
+	ref.Repository = name
+	return ref, nil
+}
+
+//String returns the reference with registry, repository and tag
+func (r Reference) String() string {
+	separator := ":"
+	if strings.Contains(r.Tag, ":") {
+		separator = "@"
+	}
+	return fmt.Sprintf("%s/%s%s%s", r.Registry, r.Repository, separator, r.Tag)
+}
+
+//Registry reads image manifests from container registries
+type Registry struct {
+	client *http.Client
+}
+
+//NewRegistry creates a new Registry instance
+func NewRegistry(client *http.Client) *Registry {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Registry{client: client}
+}
+
+type manifest struct {
+	MediaType string `json:"mediaType"`
+	Manifests []struct {
+		Platform Platform `json:"platform"`
+	} `json:"manifests"`
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+//Platforms returns the platforms the image supports
+func (r *Registry) Platforms(ctx context.Context, image string) ([]Platform, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	accept := strings.Join([]string{mediaTypeManifestList, mediaTypeOCIIndex, mediaTypeManifest, mediaTypeOCIManifest}, ", ")
+	body, token, err := r.get(ctx, ref, fmt.Sprintf("/v2/%s/manifests/%s", ref.Repository, ref.Tag), accept, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read the manifest of image '%s'", image)
+	}
+	var m manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse the manifest of image '%s'", image)
+	}
+
+	if len(m.Manifests) > 0 {
+		platforms := make([]Platform, 0, len(m.Manifests))
+		for _, entry := range m.Manifests {
+			platforms = append(platforms, entry.Platform)
+		}
+		return platforms, nil
+	}
+
+	//single platform image: the platform is part of the image config
+	if m.Config.Digest == "" {
+		return nil, fmt.Errorf("manifest of image '%s' has neither platforms nor a config", image)
+	}
+	body, _, err = r.get(ctx, ref, fmt.Sprintf("/v2/%s/blobs/%s", ref.Repository, m.Config.Digest), "*/*", token)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read the config of image '%s'", image)
+	}
+	var platform Platform
+	if err := json.Unmarshal(body, &platform); err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse the config of image '%s'", image)
+	}
+	return []Platform{platform}, nil
+}
+
+//get requests the path from the registry. If the registry requires a token, an anonymous token is requested and returned for subsequent requests.
+func (r *Registry) get(ctx context.Context, ref Reference, path, accept, token string) ([]byte, string, error) {
+	resp, err := r.do(ctx, fmt.Sprintf("https://%s%s", ref.Registry, path), accept, token)
+	if err != nil {
+		return nil, token, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		token, err = r.token(ctx, challenge, ref)
+		if err != nil {
+			return nil, "", err
+		}
+		resp, err = r.do(ctx, fmt.Sprintf("https://%s%s", ref.Registry, path), accept, token)
+		if err != nil {
+			return nil, token, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, token, fmt.Errorf("registry returned status %d", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	return body, token, err
+}
+
+func (r *Registry) do(ctx context.Context, target, accept, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r.client.Do(req)
+}
+
+//token requests an anonymous pull token as described by the Bearer challenge of the registry
+func (r *Registry) token(ctx context.Context, challenge string, ref Reference) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("registry requires unsupported authentication '%s'", challenge)
+	}
+	params := map[string]string{}
+	for _, param := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("registry challenge '%s' has no valid realm", challenge)
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", ref.Repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request returned status %d", resp.StatusCode)
+	}
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", err
+	}
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	return tokenResp.AccessToken, nil
+}
@@ -0,0 +1,110 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected Reference
+	}{
+		{"alpine", Reference{Registry: dockerHubRegistry, Repository: "library/alpine", Tag: "latest"}},
+		{"bitnami/redis:6.0", Reference{Registry: dockerHubRegistry, Repository: "bitnami/redis", Tag: "6.0"}},
+		{"eu.gcr.io/kyma-project/function-controller:v1", Reference{Registry: "eu.gcr.io", Repository: "kyma-project/function-controller", Tag: "v1"}},
+		{"localhost:5000/app@sha256:abc", Reference{Registry: "localhost:5000", Repository: "app", Tag: "sha256:abc"}},
+	}
+	for _, test := range tests {
+		ref, err := ParseReference(test.image)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, ref)
+	}
+
+	_, err := ParseReference("")
+	require.Error(t, err)
+}
+
+//newFakeRegistry serves a multi-arch image 'multi', an amd64 image 'single', and the multi-arch mirror 'mirror/single'.
+//Manifests require an anonymous token.
+func newFakeRegistry(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			require.Contains(t, r.URL.Query().Get("scope"), ":pull")
+			fmt.Fprint(w, `{"token": "secret"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/multi/manifests/v1", "/v2/mirror/single/manifests/v1":
+			w.Header().Set("Content-Type", mediaTypeManifestList)
+			fmt.Fprint(w, `{"mediaType": "`+mediaTypeManifestList+`", "manifests": [
+				{"platform": {"os": "linux", "architecture": "amd64"}},
+				{"platform": {"os": "linux", "architecture": "arm64", "variant": "v8"}}
+			]}`)
+		case "/v2/single/manifests/v1":
+			fmt.Fprint(w, `{"mediaType": "`+mediaTypeManifest+`", "config": {"digest": "sha256:config"}}`)
+		case "/v2/single/blobs/sha256:config":
+			fmt.Fprint(w, `{"os": "linux", "architecture": "amd64"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestRegistry_Platforms(t *testing.T) {
+	server := newFakeRegistry(t)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+	registry := NewRegistry(server.Client())
+
+	t.Run("Manifest list", func(t *testing.T) {
+		platforms, err := registry.Platforms(context.Background(), host+"/multi:v1")
+		require.NoError(t, err)
+		require.Equal(t, []Platform{
+			{OS: "linux", Architecture: "amd64"},
+			{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		}, platforms)
+	})
+
+	t.Run("Single platform image", func(t *testing.T) {
+		platforms, err := registry.Platforms(context.Background(), host+"/single:v1")
+		require.NoError(t, err)
+		require.Equal(t, []Platform{{OS: "linux", Architecture: "amd64"}}, platforms)
+	})
+
+	t.Run("Unknown image", func(t *testing.T) {
+		_, err := registry.Platforms(context.Background(), host+"/unknown:v1")
+		require.Error(t, err)
+	})
+}
+
+func TestRegistry_CheckArchitectures(t *testing.T) {
+	server := newFakeRegistry(t)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+	registry := NewRegistry(server.Client())
+
+	results := registry.CheckArchitectures(context.Background(),
+		[]string{host + "/multi:v1", host + "/single:v1"},
+		[]string{"amd64", "arm64"},
+		map[string]string{host + "/": host + "/mirror/"})
+
+	require.Len(t, results, 2)
+	require.True(t, results[0].OK())
+	require.False(t, results[1].OK())
+	require.Equal(t, []string{"arm64"}, results[1].Missing)
+	require.Equal(t, host+"/mirror/single:v1", results[1].Mirror)
+}
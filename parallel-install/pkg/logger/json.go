@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Format of the log output
+type Format string
+
+const (
+	// TextFormat prints human readable log lines
+	TextFormat Format = "text"
+	// JSONFormat prints one JSON object per log entry
+	JSONFormat Format = "json"
+)
+
+// StructuredInterface is implemented by loggers which support key-value pairs, such as the JSON logger.
+// The installer logs process updates with their phase, event and component as fields if the logger implements it.
+type StructuredInterface interface {
+	Interface
+
+	// Infow prints info message with key-value pairs.
+	Infow(msg string, keysAndValues ...interface{})
+
+	// Errorw prints error message with key-value pairs.
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+// JSONLogger prints machine-parsable logs, one JSON object per entry
+type JSONLogger struct {
+	*Logger
+}
+
+// New instantiates a logger with the given output format
+func New(verbose bool, format Format) (Interface, error) {
+	switch format {
+	case "", TextFormat:
+		return NewLogger(verbose), nil
+	case JSONFormat:
+		return NewJSONLogger(verbose, os.Stderr), nil
+	}
+	return nil, fmt.Errorf("Unknown log format '%s'", format)
+}
+
+// NewJSONLogger instantiates a JSON logger writing to out.
+// Depending on `verbose` flag it either prints everything
+// or only high priority messages (of at least warning level).
+func NewJSONLogger(verbose bool, out io.Writer) *JSONLogger {
+	level := zap.InfoLevel
+	if !verbose {
+		level = zap.WarnLevel
+	}
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(out), zap.NewAtomicLevelAt(level))
+	return &JSONLogger{
+		Logger: &Logger{
+			internalLogger: zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1)).Sugar(),
+		},
+	}
+}
+
+func (l *JSONLogger) Infow(msg string, keysAndValues ...interface{}) {
+	l.internalLogger.Infow(msg, keysAndValues...)
+}
+
+func (l *JSONLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.internalLogger.Errorw(msg, keysAndValues...)
+}
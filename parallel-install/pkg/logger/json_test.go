@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLogger(t *testing.T) {
+
+	t.Run("Entries are JSON objects", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		log := NewJSONLogger(true, out)
+
+		log.Infof("Deploying %s", "istio")
+		log.Errorw("Component failed", "component", "istio", "phase", "InstallComponents")
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 2)
+
+		var info map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &info))
+		require.Equal(t, "info", info["level"])
+		require.Equal(t, "Deploying istio", info["msg"])
+
+		var failure map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &failure))
+		require.Equal(t, "error", failure["level"])
+		require.Equal(t, "istio", failure["component"])
+		require.Equal(t, "InstallComponents", failure["phase"])
+	})
+
+	t.Run("Silent logger prints warnings only", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		log := NewJSONLogger(false, out)
+
+		log.Info("hidden")
+		log.Warn("shown")
+
+		require.NotContains(t, out.String(), "hidden")
+		require.Contains(t, out.String(), "shown")
+	})
+}
+
+func TestNew(t *testing.T) {
+	log, err := New(true, JSONFormat)
+	require.NoError(t, err)
+	require.Implements(t, (*StructuredInterface)(nil), log)
+
+	log, err = New(true, "")
+	require.NoError(t, err)
+	require.IsType(t, &Logger{}, log)
+
+	_, err = New(true, "xml")
+	require.Error(t, err)
+}
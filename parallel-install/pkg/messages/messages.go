@@ -0,0 +1,223 @@
+//Package messages contains the catalog of user-facing messages of the installer.
+//
+//Every message has a stable ID, so that CLIs embedding the library can translate or rephrase messages
+//by providing their own templates, and match on message IDs instead of English strings.
+package messages
+
+import (
+	"bytes"
+	"errors"
+	"text/template"
+)
+
+//ID identifies a message. IDs are stable across releases.
+type ID string
+
+//IDs of the user-facing messages
+const (
+	DeploymentStarted              ID = "deployment.started"
+	PrerequisitesDeploymentStarted ID = "deployment.prerequisites.started"
+	DeploymentComponentsFailed     ID = "deployment.components.failed"
+	DeploymentTimeout              ID = "deployment.timeout"
+	DeploymentCancelled            ID = "deployment.cancelled"
+	DeploymentForceQuit            ID = "deployment.forcequit"
+	DeploymentForceQuitting        ID = "deployment.forcequitting"
+	DeploymentInterrupting         ID = "deployment.interrupting"
+	DeploymentInterrupted          ID = "deployment.interrupted"
+	DeploymentInterruptedForceQuit ID = "deployment.interrupted.forcequit"
+	ComponentSkipped               ID = "deployment.component.skipped"
+	NamespaceConflict              ID = "deployment.namespace.conflict"
+	NamespaceAdopted               ID = "deployment.namespace.adopted"
+	NamespaceConflictSkipped       ID = "deployment.namespace.skipped"
+	ReleaseConflict                ID = "deployment.release.conflict"
+	ReleaseAdopted                 ID = "deployment.release.adopted"
+	ReleaseReplaced                ID = "deployment.release.replaced"
+	UnknownOverride                ID = "deployment.overrides.unknown"
+	MaintenanceWindowWaiting       ID = "deployment.window.waiting"
+	MaintenanceWindowOpen          ID = "deployment.window.open"
+	MaintenanceWindowClosing       ID = "deployment.window.closing"
+	MaintenanceWindowExceeded      ID = "deployment.window.exceeded"
+
+	UninstallationStarted              ID = "uninstallation.started"
+	PrerequisitesUninstallationStarted ID = "uninstallation.prerequisites.started"
+	UninstallationComponentsFailed     ID = "uninstallation.components.failed"
+	UninstallationTimeout              ID = "uninstallation.timeout"
+	UninstallationCancelled            ID = "uninstallation.cancelled"
+	UninstallationForceQuit            ID = "uninstallation.forcequit"
+	UninstallationForceQuitting        ID = "uninstallation.forcequitting"
+	UninstallationInterrupting         ID = "uninstallation.interrupting"
+	UninstallationInterrupted          ID = "uninstallation.interrupted"
+	UninstallationInterruptedForceQuit ID = "uninstallation.interrupted.forcequit"
+	NamespaceBlocked                   ID = "uninstallation.namespace.blocked"
+	NamespaceRemoved                   ID = "uninstallation.namespace.removed"
+	NamespacesKept                     ID = "uninstallation.namespaces.kept"
+	ReleaseUninstallationStarted       ID = "uninstallation.release.started"
+	ReleaseNotFound                    ID = "uninstallation.release.notfound"
+
+	MonitoringStarted            ID = "monitoring.started"
+	MonitoringComponentDegraded  ID = "monitoring.component.degraded"
+	MonitoringComponentRecovered ID = "monitoring.component.recovered"
+	MonitoringComponentsDegraded ID = "monitoring.components.degraded"
+
+	StagedUpgradeBatchStarted   ID = "upgrade.batch.started"
+	StagedUpgradeBatchVerifying ID = "upgrade.batch.verifying"
+	StagedUpgradePaused         ID = "upgrade.paused"
+	StagedUpgradeRolledBack     ID = "upgrade.rolledback"
+	StagedUpgradeRollbackFailed ID = "upgrade.rollback.failed"
+
+	PhaseStarted    ID = "phase.started"
+	PhaseFinished   ID = "phase.finished"
+	PhaseFailed     ID = "phase.failed"
+	ComponentStatus ID = "component.status"
+)
+
+//Default contains the English templates of all messages. Templates use the text/template syntax.
+var Default = Catalog{
+	DeploymentStarted:              "Kyma deployment",
+	PrerequisitesDeploymentStarted: "Kyma prerequisites deployment",
+	DeploymentComponentsFailed:     "Kyma deployment failed due to errors in {{.Count}} component(s)",
+	DeploymentTimeout:              "Kyma deployment failed due to the timeout",
+	DeploymentCancelled:            "Timeout occurred after {{.Minutes}} minutes. Cancelling deployment",
+	DeploymentForceQuit:            "Force quit: Kyma deployment failed due to the timeout",
+	DeploymentForceQuitting:        "Deployment doesn't stop after it's canceled. Enforcing quit",
+	DeploymentInterrupting:         "Shutdown requested. Cancelling deployment, running components may finish until the quit timeout",
+	DeploymentInterrupted:          "Kyma deployment was cancelled by a shutdown request",
+	DeploymentInterruptedForceQuit: "Force quit: Kyma deployment was stopped by a second shutdown request",
+	ComponentSkipped:               "Skipping component '{{.Component}}' because its condition '{{.Condition}}' holds",
+	NamespaceConflict:              "Namespace '{{.Namespace}}' already exists and is not owned by Kyma: reusing it",
+	NamespaceAdopted:               "Namespace '{{.Namespace}}' already exists and is not owned by Kyma: adopting it",
+	NamespaceConflictSkipped:       "Skipping component '{{.Component}}' because namespace '{{.Namespace}}' is not owned by Kyma",
+	ReleaseConflict:                "Release '{{.Release}}' in namespace '{{.Namespace}}' was not installed by Kyma: upgrading it",
+	ReleaseAdopted:                 "Release '{{.Release}}' in namespace '{{.Namespace}}' was not installed by Kyma: adopting it",
+	ReleaseReplaced:                "Release '{{.Release}}' in namespace '{{.Namespace}}' was not installed by Kyma: uninstalling it",
+	UnknownOverride:                "Override '{{.Key}}' is not defined in the values of any chart: check it for typos",
+	MaintenanceWindowWaiting:       "Waiting for the maintenance window which opens at {{.Start}}",
+	MaintenanceWindowOpen:          "Maintenance window is open until {{.End}}",
+	MaintenanceWindowClosing:       "Maintenance window closes at {{.End}}. Cancelling deployment",
+	MaintenanceWindowExceeded:      "Kyma deployment was cancelled because the maintenance window closed at {{.End}}",
+
+	UninstallationStarted:              "Kyma uninstallation started",
+	PrerequisitesUninstallationStarted: "Kyma prerequisites uninstallation",
+	UninstallationComponentsFailed:     "Kyma uninstallation failed due to errors in {{.Count}} component(s)",
+	UninstallationTimeout:              "Kyma uninstallation failed due to the timeout",
+	UninstallationCancelled:            "Timeout occurred after {{.Minutes}} minutes. Cancelling uninstallation",
+	UninstallationForceQuit:            "Force quit: Kyma uninstallation failed due to the timeout",
+	UninstallationForceQuitting:        "Uninstallation doesn't stop after it's canceled. Enforcing quit",
+	UninstallationInterrupting:         "Shutdown requested. Cancelling uninstallation, running components may finish until the quit timeout",
+	UninstallationInterrupted:          "Kyma uninstallation was cancelled by a shutdown request",
+	UninstallationInterruptedForceQuit: "Force quit: Kyma uninstallation was stopped by a second shutdown request",
+	NamespaceBlocked:                   "Namespace {{.Namespace}} could not be deleted because of running Pod(s)",
+	NamespaceRemoved:                   "Namespace '{{.Namespace}}' is removed",
+	NamespacesKept:                     "Soft reset: keeping the namespaces {{.Namespaces}} with their custom resources and PersistentVolumeClaims",
+	ReleaseUninstallationStarted:       "Uninstalling release '{{.Release}}' in namespace '{{.Namespace}}'",
+	ReleaseNotFound:                    "No Kyma release '{{.Release}}' found in namespace '{{.Namespace}}'",
+
+	MonitoringStarted:            "Monitoring the workloads of {{.Count}} component(s) for {{.Minutes}} minutes",
+	MonitoringComponentDegraded:  "Component '{{.Component}}' is degraded: {{.Reason}}",
+	MonitoringComponentRecovered: "Component '{{.Component}}' recovered",
+	MonitoringComponentsDegraded: "{{.Count}} component(s) are degraded at the end of the monitoring",
+
+	StagedUpgradeBatchStarted:   "Upgrading batch {{.Batch}} of {{.Batches}}: {{.Components}}",
+	StagedUpgradeBatchVerifying: "Verifying the workloads of {{.Count}} component(s) for {{.Period}}",
+	StagedUpgradePaused:         "Staged upgrade paused because batch {{.Batch}} of {{.Batches}} failed. Deploy again to resume the upgrade",
+	StagedUpgradeRolledBack:     "Staged upgrade stopped because batch {{.Batch}} of {{.Batches}} failed. The components of the batch were rolled back",
+	StagedUpgradeRollbackFailed: "Staged upgrade stopped because batch {{.Batch}} of {{.Batches}} failed, and the rollback of the batch failed",
+
+	PhaseStarted:    "Starting installation phase '{{.Phase}}'",
+	PhaseFinished:   "Finished installation phase '{{.Phase}}' successfully",
+	PhaseFailed:     "Process failed in phase '{{.Phase}}' with error state '{{.Event}}':",
+	ComponentStatus: "Status of component '{{.Component}}': {{.Status}}",
+}
+
+//Args are the values the template of a message refers to
+type Args map[string]interface{}
+
+//Catalog maps message IDs to templates. Messages missing in a catalog fall back to the Default catalog.
+type Catalog map[ID]string
+
+//Message is a rendered message. It implements error, so that failures can be matched by their ID.
+type Message struct {
+	ID   ID
+	Args Args
+	Text string
+	//Err is the cause of a failure, e.g. one of the errors of the errors package
+	Err error
+}
+
+//Error implements the error interface
+func (m *Message) Error() string {
+	return m.Text
+}
+
+//Unwrap returns the cause of the failure
+func (m *Message) Unwrap() error {
+	return m.Err
+}
+
+//Wrap sets the cause of the failure
+func (m *Message) Wrap(err error) *Message {
+	m.Err = err
+	return m
+}
+
+//String returns the text of the message
+func (m *Message) String() string {
+	return m.Text
+}
+
+//New renders the message with the given arguments.
+//If the template of the catalog cannot be rendered, the default template is used.
+func (c Catalog) New(id ID, args Args) *Message {
+	msg := &Message{ID: id, Args: args}
+	if text, ok := c[id]; ok {
+		if rendered, err := render(text, args); err == nil {
+			msg.Text = rendered
+			return msg
+		}
+	}
+	rendered, err := render(Default[id], args)
+	if err != nil {
+		rendered = Default[id]
+	}
+	if rendered == "" {
+		rendered = string(id)
+	}
+	msg.Text = rendered
+	return msg
+}
+
+//Text renders the message and returns its text
+func (c Catalog) Text(id ID, args Args) string {
+	return c.New(id, args).Text
+}
+
+//Validate verifies that all templates of the catalog can be parsed and refer to known message IDs
+func (c Catalog) Validate() error {
+	for id, text := range c {
+		if _, ok := Default[id]; !ok {
+			return errors.New("unknown message ID '" + string(id) + "'")
+		}
+		if _, err := template.New(string(id)).Option("missingkey=error").Parse(text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Is returns true if the error is a message with the given ID
+func Is(err error, id ID) bool {
+	var msg *Message
+	return errors.As(err, &msg) && msg.ID == id
+}
+
+func render(text string, args Args) (string, error) {
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, args); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
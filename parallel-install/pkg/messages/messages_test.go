@@ -0,0 +1,49 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_New(t *testing.T) {
+
+	t.Run("Default messages are rendered with their arguments", func(t *testing.T) {
+		msg := Default.New(DeploymentComponentsFailed, Args{"Count": 2})
+		require.Equal(t, DeploymentComponentsFailed, msg.ID)
+		require.Equal(t, "Kyma deployment failed due to errors in 2 component(s)", msg.Error())
+	})
+
+	t.Run("Custom templates replace the defaults", func(t *testing.T) {
+		catalog := Catalog{DeploymentComponentsFailed: "Kyma-Installation fehlgeschlagen: {{.Count}} Komponente(n) mit Fehlern"}
+		require.Equal(t, "Kyma-Installation fehlgeschlagen: 3 Komponente(n) mit Fehlern", catalog.Text(DeploymentComponentsFailed, Args{"Count": 3}))
+	})
+
+	t.Run("Missing messages fall back to the defaults", func(t *testing.T) {
+		catalog := Catalog{}
+		require.Equal(t, "Kyma deployment failed due to the timeout", catalog.Text(DeploymentTimeout, nil))
+	})
+
+	t.Run("Broken templates fall back to the defaults", func(t *testing.T) {
+		catalog := Catalog{NamespaceRemoved: "{{.Unknown}} removed"}
+		require.Equal(t, "Namespace 'kyma-system' is removed", catalog.Text(NamespaceRemoved, Args{"Namespace": "kyma-system"}))
+	})
+
+	t.Run("Every default template is valid", func(t *testing.T) {
+		require.NoError(t, Default.Validate())
+	})
+}
+
+func TestCatalog_Validate(t *testing.T) {
+	require.NoError(t, Catalog(nil).Validate())
+	require.Error(t, Catalog{"unknown.id": "text"}.Validate())
+	require.Error(t, Catalog{DeploymentTimeout: "{{.Broken"}.Validate())
+}
+
+func TestIs(t *testing.T) {
+	err := errors.Wrap(Default.New(UninstallationTimeout, nil), "uninstallation failed")
+	require.True(t, Is(err, UninstallationTimeout))
+	require.False(t, Is(err, DeploymentTimeout))
+	require.False(t, Is(errors.New("other"), DeploymentTimeout))
+}
@@ -0,0 +1,290 @@
+//Package modules implements the installation of Kyma components as modules of the Kyma lifecycle manager.
+//Instead of deploying Helm releases, components are added to the Kyma custom resource and the lifecycle manager installs them.
+//
+//The code in the package uses the user-provided function for logging.
+package modules
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/pkg/errors"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	logPrefix = "[modules/client.go]"
+
+	//ModuleTemplateFile is the file in the component directory containing the ModuleTemplate of the component
+	ModuleTemplateFile = "moduletemplate.yaml"
+
+	defaultKymaName      = "default-kyma"
+	defaultKymaNamespace = "kyma-system"
+	defaultChannel       = "regular"
+	stateReady           = "Ready"
+	stateError           = "Error"
+	pollInterval         = 5 * time.Second
+)
+
+var (
+	kymaResource           = schema.GroupVersionResource{Group: "operator.kyma-project.io", Version: "v1beta2", Resource: "kymas"}
+	moduleTemplateResource = schema.GroupVersionResource{Group: "operator.kyma-project.io", Version: "v1beta2", Resource: "moduletemplates"}
+)
+
+//Config provides configuration for the Client.
+type Config struct {
+	//Channel is the release channel of the modules. Defaults to "regular".
+	Channel string
+	//Timeout for a module to become ready or to be removed
+	Timeout time.Duration
+	//Log is used for logging
+	Log              logger.Interface
+	KubeconfigSource config.KubeconfigSource
+}
+
+//Client adds components as modules to the Kyma custom resource.
+//It fulfils the same contract as the helm.ClientInterface, so it can replace the Helm client of a component.
+type Client struct {
+	cfg Config
+	//dynamicClient is created lazily, tests inject a fake
+	dynamicClient dynamic.Interface
+}
+
+//NewClient returns a new Client instance.
+func NewClient(cfg Config) *Client {
+	if cfg.Channel == "" {
+		cfg.Channel = defaultChannel
+	}
+	return &Client{
+		cfg: cfg,
+	}
+}
+
+//DeployRelease applies the ModuleTemplate found in the chart directory, if any, adds the module to the Kyma custom resource
+//and waits until the lifecycle manager reports the module as ready.
+//Overrides and profile are not applied, modules are configured with their own custom resources.
+func (c *Client) DeployRelease(ctx context.Context, chartDir, namespace, name string, overrides map[string]interface{}, profile string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	if err := c.applyModuleTemplate(client, chartDir); err != nil {
+		return errors.Wrapf(err, "Failed to apply the module template of %s", name)
+	}
+
+	c.cfg.Log.Infof("%s Adding module %s to the Kyma resource", logPrefix, name)
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		kyma, err := c.kyma(client)
+		if err != nil {
+			return err
+		}
+		modules, _, err := unstructured.NestedSlice(kyma.Object, "spec", "modules")
+		if err != nil {
+			return err
+		}
+		for _, module := range modules {
+			if m, ok := module.(map[string]interface{}); ok && m["name"] == name {
+				return nil
+			}
+		}
+		modules = append(modules, map[string]interface{}{"name": name})
+		if err := unstructured.SetNestedSlice(kyma.Object, modules, "spec", "modules"); err != nil {
+			return err
+		}
+		_, err = client.Resource(kymaResource).Namespace(defaultKymaNamespace).Update(ctx, kyma, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to add module %s to the Kyma resource", name)
+	}
+
+	return c.waitForModule(ctx, client, name, func(state string, found bool) (bool, error) {
+		if state == stateError {
+			return false, fmt.Errorf("module %s is in state %s", name, state)
+		}
+		return state == stateReady, nil
+	})
+}
+
+//UninstallRelease removes the module from the Kyma custom resource and waits until the lifecycle manager removed it.
+func (c *Client) UninstallRelease(ctx context.Context, namespace, name string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	c.cfg.Log.Infof("%s Removing module %s from the Kyma resource", logPrefix, name)
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		kyma, err := client.Resource(kymaResource).Namespace(defaultKymaNamespace).Get(ctx, defaultKymaName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		modules, _, err := unstructured.NestedSlice(kyma.Object, "spec", "modules")
+		if err != nil {
+			return err
+		}
+		var remaining []interface{}
+		for _, module := range modules {
+			if m, ok := module.(map[string]interface{}); ok && m["name"] == name {
+				continue
+			}
+			remaining = append(remaining, module)
+		}
+		if len(remaining) == len(modules) {
+			return nil
+		}
+		if err := unstructured.SetNestedSlice(kyma.Object, remaining, "spec", "modules"); err != nil {
+			return err
+		}
+		_, err = client.Resource(kymaResource).Namespace(defaultKymaNamespace).Update(ctx, kyma, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "Failed to remove module %s from the Kyma resource", name)
+	}
+
+	return c.waitForModule(ctx, client, name, func(state string, found bool) (bool, error) {
+		return !found, nil
+	})
+}
+
+func (c *Client) client() (dynamic.Interface, error) {
+	if c.dynamicClient != nil {
+		return c.dynamicClient, nil
+	}
+	restConfig, err := config.RestConfig(c.cfg.KubeconfigSource)
+	if err != nil {
+		return nil, err
+	}
+	c.dynamicClient, err = dynamic.NewForConfig(restConfig)
+	return c.dynamicClient, err
+}
+
+//kyma returns the Kyma custom resource and creates it if it does not exist
+func (c *Client) kyma(client dynamic.Interface) (*unstructured.Unstructured, error) {
+	kyma, err := client.Resource(kymaResource).Namespace(defaultKymaNamespace).Get(context.TODO(), defaultKymaName, metav1.GetOptions{})
+	if err == nil || !apierr.IsNotFound(err) {
+		return kyma, err
+	}
+
+	kyma = &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "operator.kyma-project.io/v1beta2",
+			"kind":       "Kyma",
+			"metadata": map[string]interface{}{
+				"name":      defaultKymaName,
+				"namespace": defaultKymaNamespace,
+			},
+			"spec": map[string]interface{}{
+				"channel": c.cfg.Channel,
+				"modules": []interface{}{},
+			},
+		},
+	}
+	kyma, err = client.Resource(kymaResource).Namespace(defaultKymaNamespace).Create(context.TODO(), kyma, metav1.CreateOptions{})
+	if apierr.IsAlreadyExists(err) {
+		// created by a parallel worker, force a retry with the current version
+		return nil, apierr.NewConflict(kymaResource.GroupResource(), defaultKymaName, err)
+	}
+	return kyma, err
+}
+
+//applyModuleTemplate creates or updates the ModuleTemplate stored in the component directory
+func (c *Client) applyModuleTemplate(client dynamic.Interface, chartDir string) error {
+	file, err := os.Open(filepath.Join(chartDir, ModuleTemplateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	tpl := &unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(&tpl.Object); err != nil && err != io.EOF {
+		return err
+	}
+	if tpl.GetNamespace() == "" {
+		tpl.SetNamespace(defaultKymaNamespace)
+	}
+
+	resource := client.Resource(moduleTemplateResource).Namespace(tpl.GetNamespace())
+	current, err := resource.Get(context.TODO(), tpl.GetName(), metav1.GetOptions{})
+	if apierr.IsNotFound(err) {
+		_, err = resource.Create(context.TODO(), tpl, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	tpl.SetResourceVersion(current.GetResourceVersion())
+	_, err = resource.Update(context.TODO(), tpl, metav1.UpdateOptions{})
+	return err
+}
+
+//waitForModule polls the module state in the status of the Kyma custom resource until the condition is met
+func (c *Client) waitForModule(ctx context.Context, client dynamic.Interface, name string, condition func(state string, found bool) (bool, error)) error {
+	err := wait.PollImmediateUntil(pollInterval, func() (bool, error) {
+		kyma, err := client.Resource(kymaResource).Namespace(defaultKymaNamespace).Get(ctx, defaultKymaName, metav1.GetOptions{})
+		if err != nil {
+			if apierr.IsNotFound(err) {
+				return condition("", false)
+			}
+			return false, err
+		}
+		state, found := moduleState(kyma, name)
+		return condition(state, found)
+	}, timeoutChannel(ctx, c.cfg.Timeout))
+	if err != nil {
+		return errors.Wrapf(err, "Module %s did not reach the expected state", name)
+	}
+	return nil
+}
+
+//moduleState returns the state of the module reported in the status of the Kyma custom resource
+func moduleState(kyma *unstructured.Unstructured, name string) (string, bool) {
+	modules, _, _ := unstructured.NestedSlice(kyma.Object, "status", "modules")
+	for _, module := range modules {
+		m, ok := module.(map[string]interface{})
+		if !ok || m["name"] != name {
+			continue
+		}
+		state, _ := m["state"].(string)
+		return state, true
+	}
+	return "", false
+}
+
+//timeoutChannel is closed when the context is done or the timeout elapsed
+func timeoutChannel(ctx context.Context, timeout time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if timeout <= 0 {
+			<-ctx.Done()
+			return
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(timeout):
+		}
+	}()
+	return done
+}
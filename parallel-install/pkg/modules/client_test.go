@@ -0,0 +1,117 @@
+package modules
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestClient_DeployRelease(t *testing.T) {
+
+	t.Run("Module is added and module template is applied", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "modules")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		tpl := "apiVersion: operator.kyma-project.io/v1beta2\nkind: ModuleTemplate\nmetadata:\n  name: serverless-regular\nspec:\n  channel: regular\n"
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ModuleTemplateFile), []byte(tpl), 0600))
+
+		dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), fixKyma(nil, map[string]string{"serverless": stateReady}))
+		client := newTestClient(dynamicClient)
+
+		err = client.DeployRelease(context.Background(), dir, "kyma-system", "serverless", nil, "")
+		require.NoError(t, err)
+
+		kyma := getKyma(t, client)
+		modules, _, _ := unstructured.NestedSlice(kyma.Object, "spec", "modules")
+		require.Equal(t, []interface{}{map[string]interface{}{"name": "serverless"}}, modules)
+
+		_, err = dynamicClient.Resource(moduleTemplateResource).Namespace(defaultKymaNamespace).Get(context.Background(), "serverless-regular", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("Kyma resource is created", func(t *testing.T) {
+		dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme())
+		client := newTestClient(dynamicClient)
+
+		err := client.DeployRelease(context.Background(), "", "kyma-system", "serverless", nil, "")
+		require.Error(t, err) // the module never gets ready without a lifecycle manager
+
+		kyma := getKyma(t, client)
+		channel, _, _ := unstructured.NestedString(kyma.Object, "spec", "channel")
+		require.Equal(t, defaultChannel, channel)
+	})
+
+	t.Run("Module in error state", func(t *testing.T) {
+		dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), fixKyma(nil, map[string]string{"serverless": stateError}))
+		client := newTestClient(dynamicClient)
+
+		err := client.DeployRelease(context.Background(), "", "kyma-system", "serverless", nil, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Error")
+	})
+}
+
+func TestClient_UninstallRelease(t *testing.T) {
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), fixKyma([]string{"serverless", "eventing"}, map[string]string{"eventing": stateReady}))
+	client := newTestClient(dynamicClient)
+
+	err := client.UninstallRelease(context.Background(), "kyma-system", "serverless")
+	require.NoError(t, err)
+
+	kyma := getKyma(t, client)
+	modules, _, _ := unstructured.NestedSlice(kyma.Object, "spec", "modules")
+	require.Equal(t, []interface{}{map[string]interface{}{"name": "eventing"}}, modules)
+}
+
+func newTestClient(dynamicClient *fake.FakeDynamicClient) *Client {
+	client := NewClient(Config{
+		Timeout: 100 * time.Millisecond,
+		Log:     logger.NewLogger(true),
+	})
+	client.dynamicClient = dynamicClient
+	return client
+}
+
+func getKyma(t *testing.T, client *Client) *unstructured.Unstructured {
+	kyma, err := client.dynamicClient.Resource(kymaResource).Namespace(defaultKymaNamespace).Get(context.Background(), defaultKymaName, metav1.GetOptions{})
+	require.NoError(t, err)
+	return kyma
+}
+
+func fixKyma(modules []string, states map[string]string) *unstructured.Unstructured {
+	specModules := []interface{}{}
+	for _, m := range modules {
+		specModules = append(specModules, map[string]interface{}{"name": m})
+	}
+	statusModules := []interface{}{}
+	for m, state := range states {
+		statusModules = append(statusModules, map[string]interface{}{"name": m, "state": state})
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "operator.kyma-project.io/v1beta2",
+			"kind":       "Kyma",
+			"metadata": map[string]interface{}{
+				"name":      defaultKymaName,
+				"namespace": defaultKymaNamespace,
+			},
+			"spec": map[string]interface{}{
+				"channel": defaultChannel,
+				"modules": specModules,
+			},
+			"status": map[string]interface{}{
+				"modules": statusModules,
+			},
+		},
+	}
+}
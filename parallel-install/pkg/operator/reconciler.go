@@ -0,0 +1,258 @@
+//Package operator runs the Kyma installation as an in-cluster operator.
+//
+//The Reconciler reacts to KymaInstallation custom resources and uses the Deployment and Deletion
+//of the deployment package to bring the cluster to the described state.
+//The package is optional: the library can still be used without controller-runtime.
+package operator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	finalizer = "installer.kyma-project.io/finalizer"
+
+	//PhaseInstalling indicates the deployment is running
+	PhaseInstalling = "Installing"
+	//PhaseInstalled indicates the cluster is in the desired state
+	PhaseInstalled = "Installed"
+	//PhaseUninstalling indicates the deletion is running
+	PhaseUninstalling = "Uninstalling"
+	//PhaseError indicates the last operation failed
+	PhaseError = "Error"
+
+	//DefaultRetryInterval is the delay before a failed installation or uninstallation is retried
+	DefaultRetryInterval = time.Minute
+)
+
+//GroupVersionKind of the KymaInstallation custom resource
+var GroupVersionKind = schema.GroupVersionKind{Group: "installer.kyma-project.io", Version: "v1alpha1", Kind: "KymaInstallation"}
+
+//Reconciler reconciles KymaInstallation custom resources
+type Reconciler struct {
+	Client client.Client
+	//Config is the template of the installation config.
+	//Version, profile, paths and component list are taken from the custom resource.
+	Config config.Config
+	//RetryInterval is the delay before a failed installation or uninstallation is retried. Defaults to DefaultRetryInterval.
+	RetryInterval time.Duration
+	//newDeployment and newDeletion are replaced in tests
+	newDeployment func(cfg *config.Config, ob *deployment.OverridesBuilder) (installer, error)
+	newDeletion   func(cfg *config.Config, ob *deployment.OverridesBuilder) (uninstaller, error)
+}
+
+type installer interface {
+	Reconcile() error
+}
+
+type uninstaller interface {
+	StartKymaUninstallation() error
+}
+
+//NewReconciler creates a new Reconciler instance.
+//The kubeconfig source of the config has to point to the cluster the operator runs in.
+func NewReconciler(c client.Client, cfg config.Config) *Reconciler {
+	if cfg.Log == nil {
+		cfg.Log = logger.NewLogger(false)
+	}
+	return &Reconciler{
+		Client: c,
+		Config: cfg,
+		newDeployment: func(cfg *config.Config, ob *deployment.OverridesBuilder) (installer, error) {
+			return deployment.NewDeployment(cfg, ob, nil)
+		},
+		newDeletion: func(cfg *config.Config, ob *deployment.OverridesBuilder) (uninstaller, error) {
+			return deployment.NewDeletion(cfg, ob, nil, nil)
+		},
+	}
+}
+
+//SetupWithManager registers the Reconciler for KymaInstallation resources
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GroupVersionKind)
+	return ctrl.NewControllerManagedBy(mgr).
+		For(obj).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
+
+//Reconcile deploys Kyma as described by the KymaInstallation resource, or uninstalls it if the resource is deleted
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	inst := &unstructured.Unstructured{}
+	inst.SetGroupVersionKind(GroupVersionKind)
+	if err := r.Client.Get(ctx, req.NamespacedName, inst); err != nil {
+		if apierr.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	cfg, ob, err := r.installationConfig(inst)
+
+	if !inst.GetDeletionTimestamp().IsZero() {
+		if !controllerutil.ContainsFinalizer(inst, finalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err != nil {
+			// without a valid config there is nothing to uninstall, the resource must still be deletable
+			r.Config.Log.Warnf("Skipping the uninstallation of %s, its spec is invalid: %v", req.NamespacedName, err)
+		} else {
+			if err := r.updateStatus(ctx, inst, PhaseUninstalling, ""); err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := r.uninstall(cfg, ob); err != nil {
+				// the finalizer is kept until the uninstallation succeeds
+				return r.retryAfterFailure(ctx, inst, err)
+			}
+		}
+		controllerutil.RemoveFinalizer(inst, finalizer)
+		return ctrl.Result{}, r.Client.Update(ctx, inst)
+	}
+
+	if err != nil {
+		// an invalid spec is not retried, as it fails until the spec changes
+		return ctrl.Result{}, r.updateStatus(ctx, inst, PhaseError, err.Error())
+	}
+
+	if !controllerutil.ContainsFinalizer(inst, finalizer) {
+		controllerutil.AddFinalizer(inst, finalizer)
+		if err := r.Client.Update(ctx, inst); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	phase, _, _ := unstructured.NestedString(inst.Object, "status", "phase")
+	observed, _, _ := unstructured.NestedInt64(inst.Object, "status", "observedGeneration")
+	if phase == PhaseInstalled && observed == inst.GetGeneration() {
+		// nothing changed since the last installation
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.updateStatus(ctx, inst, PhaseInstalling, ""); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.install(cfg, ob); err != nil {
+		return r.retryAfterFailure(ctx, inst, err)
+	}
+	return ctrl.Result{}, r.updateStatus(ctx, inst, PhaseInstalled, "")
+}
+
+//retryAfterFailure records the failed operation in the status and requeues the resource after the retry interval,
+//as the GenerationChangedPredicate does not trigger another reconciliation for the status update
+func (r *Reconciler) retryAfterFailure(ctx context.Context, inst *unstructured.Unstructured, opErr error) (ctrl.Result, error) {
+	if err := r.updateStatus(ctx, inst, PhaseError, opErr.Error()); err != nil {
+		return ctrl.Result{}, err
+	}
+	interval := r.RetryInterval
+	if interval <= 0 {
+		interval = DefaultRetryInterval
+	}
+	r.Config.Log.Warnf("Operation on %s/%s failed, retrying in %s: %v", inst.GetNamespace(), inst.GetName(), interval, opErr)
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+func (r *Reconciler) install(cfg *config.Config, ob *deployment.OverridesBuilder) error {
+	d, err := r.newDeployment(cfg, ob)
+	if err != nil {
+		return err
+	}
+	return d.Reconcile()
+}
+
+func (r *Reconciler) uninstall(cfg *config.Config, ob *deployment.OverridesBuilder) error {
+	d, err := r.newDeletion(cfg, ob)
+	if err != nil {
+		return err
+	}
+	return d.StartKymaUninstallation()
+}
+
+//installationConfig builds the installation config and the overrides out of the spec of the resource
+func (r *Reconciler) installationConfig(inst *unstructured.Unstructured) (*config.Config, *deployment.OverridesBuilder, error) {
+	cfg := r.Config
+	cfg.Version, _, _ = unstructured.NestedString(inst.Object, "spec", "version")
+	cfg.Profile, _, _ = unstructured.NestedString(inst.Object, "spec", "profile")
+	cfg.ResourcePath, _, _ = unstructured.NestedString(inst.Object, "spec", "resourcePath")
+	cfg.InstallationResourcePath, _, _ = unstructured.NestedString(inst.Object, "spec", "installationResourcePath")
+
+	prerequisites, err := componentDefinitions(inst, "prerequisites")
+	if err != nil {
+		return nil, nil, err
+	}
+	components, err := componentDefinitions(inst, "components")
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg.ComponentList = &config.ComponentList{
+		Prerequisites: prerequisites,
+		Components:    components,
+	}
+
+	ob := &deployment.OverridesBuilder{}
+	overrides, _, err := unstructured.NestedMap(inst.Object, "spec", "overrides")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid overrides: %v", err)
+	}
+	for chart, values := range overrides {
+		chartOverrides, ok := values.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("overrides of chart '%s' must be a map", chart)
+		}
+		if err := ob.AddOverrides(chart, chartOverrides); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &cfg, ob, nil
+}
+
+func componentDefinitions(inst *unstructured.Unstructured, field string) ([]config.ComponentDefinition, error) {
+	list, _, err := unstructured.NestedSlice(inst.Object, "spec", field)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", field, err)
+	}
+	var defs []config.ComponentDefinition
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid entry in %s: %v", field, item)
+		}
+		name, _ := m["name"].(string)
+		namespace, _ := m["namespace"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("entry in %s has no name", field)
+		}
+		if namespace == "" {
+			namespace = "kyma-system"
+		}
+		defs = append(defs, config.ComponentDefinition{Name: name, Namespace: namespace})
+	}
+	return defs, nil
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, inst *unstructured.Unstructured, phase, message string) error {
+	status := map[string]interface{}{
+		"phase":              phase,
+		"observedGeneration": inst.GetGeneration(),
+	}
+	if message != "" {
+		status["message"] = message
+	}
+	if err := unstructured.SetNestedMap(inst.Object, status, "status"); err != nil {
+		return err
+	}
+	return r.Client.Status().Update(ctx, inst)
+}
@@ -0,0 +1,166 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type installerFunc func() error
+
+func (f installerFunc) Reconcile() error {
+	return f()
+}
+
+type uninstallerFunc func() error
+
+func (f uninstallerFunc) StartKymaUninstallation() error {
+	return f()
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+
+	t.Run("Installation is deployed", func(t *testing.T) {
+		inst := fixInstallation()
+		r := newTestReconciler(inst)
+		var deployedCfg *config.Config
+		r.newDeployment = func(cfg *config.Config, ob *deployment.OverridesBuilder) (installer, error) {
+			deployedCfg = cfg
+			return installerFunc(func() error { return nil }), nil
+		}
+
+		_, err := r.Reconcile(context.Background(), request())
+		require.NoError(t, err)
+
+		require.Equal(t, "2.0.0", deployedCfg.Version)
+		require.Equal(t, []config.ComponentDefinition{{Name: "istio", Namespace: "istio-system"}}, deployedCfg.ComponentList.Prerequisites)
+		require.Equal(t, []config.ComponentDefinition{{Name: "serverless", Namespace: "kyma-system"}}, deployedCfg.ComponentList.Components)
+
+		result := get(t, r)
+		phase, _, _ := unstructured.NestedString(result.Object, "status", "phase")
+		require.Equal(t, PhaseInstalled, phase)
+		require.Contains(t, result.GetFinalizers(), finalizer)
+	})
+
+	t.Run("Deployment failure is reported in the status and retried", func(t *testing.T) {
+		r := newTestReconciler(fixInstallation())
+		attempts := 0
+		r.newDeployment = func(cfg *config.Config, ob *deployment.OverridesBuilder) (installer, error) {
+			attempts++
+			return installerFunc(func() error { return errors.New("istio failed") }), nil
+		}
+
+		res, err := r.Reconcile(context.Background(), request())
+		require.NoError(t, err)
+		require.Equal(t, DefaultRetryInterval, res.RequeueAfter)
+
+		result := get(t, r)
+		phase, _, _ := unstructured.NestedString(result.Object, "status", "phase")
+		message, _, _ := unstructured.NestedString(result.Object, "status", "message")
+		require.Equal(t, PhaseError, phase)
+		require.Equal(t, "istio failed", message)
+
+		// the requeued reconciliation deploys again, although the spec did not change
+		r.RetryInterval = time.Second
+		res, err = r.Reconcile(context.Background(), request())
+		require.NoError(t, err)
+		require.Equal(t, time.Second, res.RequeueAfter)
+		require.Equal(t, 2, attempts)
+	})
+
+	t.Run("Uninstallation failure keeps the finalizer and is retried", func(t *testing.T) {
+		inst := fixInstallation()
+		inst.SetFinalizers([]string{finalizer})
+		now := metav1.Now()
+		inst.SetDeletionTimestamp(&now)
+		r := newTestReconciler(inst)
+		r.newDeletion = func(cfg *config.Config, ob *deployment.OverridesBuilder) (uninstaller, error) {
+			return uninstallerFunc(func() error { return errors.New("namespace stuck") }), nil
+		}
+
+		res, err := r.Reconcile(context.Background(), request())
+		require.NoError(t, err)
+		require.Equal(t, DefaultRetryInterval, res.RequeueAfter)
+
+		result := get(t, r)
+		phase, _, _ := unstructured.NestedString(result.Object, "status", "phase")
+		require.Equal(t, PhaseError, phase)
+		require.Contains(t, result.GetFinalizers(), finalizer)
+	})
+
+	t.Run("Resource with an invalid spec can be deleted", func(t *testing.T) {
+		inst := fixInstallation()
+		require.NoError(t, unstructured.SetNestedField(inst.Object, "invalid", "spec", "components"))
+		inst.SetFinalizers([]string{finalizer})
+		now := metav1.Now()
+		inst.SetDeletionTimestamp(&now)
+		r := newTestReconciler(inst)
+		r.newDeletion = func(cfg *config.Config, ob *deployment.OverridesBuilder) (uninstaller, error) {
+			t.Fatal("uninstallation must not run without a valid spec")
+			return nil, nil
+		}
+
+		_, err := r.Reconcile(context.Background(), request())
+		require.NoError(t, err)
+
+		result := get(t, r)
+		require.NotContains(t, result.GetFinalizers(), finalizer)
+	})
+
+	t.Run("Deleted resource is ignored", func(t *testing.T) {
+		r := newTestReconciler()
+		_, err := r.Reconcile(context.Background(), request())
+		require.NoError(t, err)
+	})
+}
+
+func newTestReconciler(objs ...runtime.Object) *Reconciler {
+	c := fake.NewFakeClientWithScheme(runtime.NewScheme(), objs...)
+	return NewReconciler(c, config.Config{Log: logger.NewLogger(true)})
+}
+
+func request() ctrl.Request {
+	return ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "kyma"}}
+}
+
+func get(t *testing.T, r *Reconciler) *unstructured.Unstructured {
+	inst := &unstructured.Unstructured{}
+	inst.SetGroupVersionKind(GroupVersionKind)
+	require.NoError(t, r.Client.Get(context.Background(), request().NamespacedName, inst))
+	return inst
+}
+
+func fixInstallation() *unstructured.Unstructured {
+	inst := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"version": "2.0.0",
+				"prerequisites": []interface{}{
+					map[string]interface{}{"name": "istio", "namespace": "istio-system"},
+				},
+				"components": []interface{}{
+					map[string]interface{}{"name": "serverless"},
+				},
+				"overrides": map[string]interface{}{
+					"global": map[string]interface{}{"domainName": "kyma.example.com"},
+				},
+			},
+		},
+	}
+	inst.SetGroupVersionKind(GroupVersionKind)
+	inst.SetNamespace("default")
+	inst.SetName("kyma")
+	return inst
+}
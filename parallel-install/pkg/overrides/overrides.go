@@ -1,5 +1,6 @@
 //Package overrides implements the logic related to handling overrides.
 //The manually-provided overrides have precedence over standard Kyma overrides defined in the cluster.
+//Overrides scoped to a component under the ComponentsKey have precedence over all other overrides of the component.
 //
 //The code in the package uses the user-provided function for logging.
 package overrides
@@ -16,6 +17,10 @@ import (
 
 const logPrefix = "[overrides/overrides.go]"
 
+//ComponentsKey is the reserved top-level key of the overrides which are scoped to a single component,
+//e.g. components.istio.global.proxy.resources applies to the istio component only
+const ComponentsKey = "components"
+
 var commonListOpts = metav1.ListOptions{LabelSelector: "installer=overrides, !component"}
 var componentListOpts = metav1.ListOptions{LabelSelector: "installer=overrides, component"}
 
@@ -26,6 +31,7 @@ type defaultProvider struct {
 	additionalOverrides          map[string]interface{}
 	componentOverrides           map[string]map[string]interface{}
 	additionalComponentOverrides map[string]map[string]interface{}
+	scopedOverrides              map[string]map[string]interface{}
 	kubeClient                   kubernetes.Interface
 	log                          logger.Interface
 }
@@ -48,6 +54,8 @@ type Provider interface {
 //There is one difference from the plain Helm's values.yaml file: These are not values for a single release but for the entire Kyma installation.
 //Because of that, you have to put values for a specific Component (e.g: Component name is "foo") under a key equal to the component's name (i.e: "foo").
 //You can also put overrides under a "global" key. These will merge with the top-level "global" Helm key for every Helm chart.
+//Values under the "components" key are scoped to a single component (e.g: "components.foo.global.bar") and take precedence
+//over the global overrides and the overrides of the component, including those defined in the cluster.
 func New(client kubernetes.Interface, overrides map[string]interface{}, log logger.Interface) (Provider, error) {
 	provider := defaultProvider{
 		kubeClient: client,
@@ -64,11 +72,14 @@ func New(client kubernetes.Interface, overrides map[string]interface{}, log logg
 
 func (p *defaultProvider) OverridesGetterFunctionFor(name string) func() map[string]interface{} {
 	return func() map[string]interface{} {
+		values := p.overrides
 		if val, ok := p.componentOverrides[name]; ok {
-			val = MergeMaps(val, p.overrides)
-			return val
+			values = MergeMaps(val, p.overrides)
+		}
+		if scoped, ok := p.scopedOverrides[name]; ok {
+			values = MergeMaps(values, scoped)
 		}
-		return p.overrides
+		return values
 	}
 }
 
@@ -154,7 +165,11 @@ func (p *defaultProvider) parseAdditionalOverrides(additionalOverrides map[strin
 	}
 
 	for k, v := range additionalOverrides {
-		if k == "global" {
+		if k == ComponentsKey {
+			if err := p.parseScopedOverrides(v); err != nil {
+				return err
+			}
+		} else if k == "global" {
 			globalOverrides := make(map[string]interface{})
 			globalOverrides[k] = v
 			p.overrides = MergeMaps(p.overrides, globalOverrides)
@@ -179,6 +194,25 @@ func (p *defaultProvider) parseAdditionalOverrides(additionalOverrides map[strin
 	return nil
 }
 
+//parseScopedOverrides reads the overrides under the ComponentsKey, which are maps of values per component
+func (p *defaultProvider) parseScopedOverrides(scoped interface{}) error {
+	components, ok := scoped.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Cannot add overrides under '%s' as the value has to be a map of components", ComponentsKey)
+	}
+	if p.scopedOverrides == nil {
+		p.scopedOverrides = make(map[string]map[string]interface{})
+	}
+	for component, values := range components {
+		valuesMap, ok := values.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Cannot add overrides '%s.%s=%v' as the value has to be a map", ComponentsKey, component, values)
+		}
+		p.scopedOverrides[component] = MergeMaps(p.scopedOverrides[component], valuesMap)
+	}
+	return nil
+}
+
 func MergeMaps(a, b map[string]interface{}) map[string]interface{} {
 	out := make(map[string]interface{}, len(a))
 	for k, v := range a {
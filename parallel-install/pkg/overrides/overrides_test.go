@@ -132,3 +132,34 @@ func Test_ReadOverridesFromCluster(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func Test_ScopedOverrides(t *testing.T) {
+	overrides := map[string]interface{}{
+		"global": map[string]interface{}{"domainName": "kyma.example.com", "tlsCrt": "global-crt"},
+		"istio":  map[string]interface{}{"global": map[string]interface{}{"tlsCrt": "chart-crt"}, "replicas": 1},
+		"components": map[string]interface{}{
+			"istio": map[string]interface{}{"global": map[string]interface{}{"tlsCrt": "istio-crt"}, "replicas": 2},
+		},
+	}
+	testProvider, err := New(fake.NewSimpleClientset(), overrides, logger.NewLogger(true))
+	require.NoError(t, err)
+	require.NoError(t, testProvider.ReadOverridesFromCluster())
+
+	t.Run("Scoped overrides take precedence over global and chart overrides", func(t *testing.T) {
+		res := testProvider.OverridesGetterFunctionFor("istio")()
+		require.Equal(t, 2, res["replicas"])
+		require.Equal(t, map[string]interface{}{"domainName": "kyma.example.com", "tlsCrt": "istio-crt"}, res["global"])
+		require.NotContains(t, res, "components")
+	})
+
+	t.Run("Scoped overrides do not apply to other components", func(t *testing.T) {
+		res := testProvider.OverridesGetterFunctionFor("monitoring")()
+		require.Equal(t, map[string]interface{}{"domainName": "kyma.example.com", "tlsCrt": "global-crt"}, res["global"])
+		require.NotContains(t, res, "replicas")
+	})
+
+	t.Run("Scoped overrides must be maps", func(t *testing.T) {
+		_, err := New(fake.NewSimpleClientset(), map[string]interface{}{"components": map[string]interface{}{"istio": "value"}}, logger.NewLogger(true))
+		require.EqualError(t, err, "Cannot add overrides 'components.istio=value' as the value has to be a map")
+	})
+}
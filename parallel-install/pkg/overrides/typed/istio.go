@@ -0,0 +1,84 @@
+package typed
+
+import "fmt"
+
+const istioChart = "istio"
+
+//Istio overrides of the istio chart
+type Istio struct {
+	//Autoscaling and resources of the ingress gateway
+	IngressGateway *IstioIngressGateway
+	//Resources of the Envoy sidecar proxies
+	ProxyResources *Resources
+}
+
+//IstioIngressGateway configures the Istio ingress gateway
+type IstioIngressGateway struct {
+	//Minimum number of replicas. Defaults to 1.
+	MinReplicas int
+	//Maximum number of replicas. Defaults to the minimum number of replicas.
+	MaxReplicas int
+	Resources   *Resources
+}
+
+//Validate implements Overrides.Validate
+func (i *Istio) Validate() error {
+	if gw := i.IngressGateway; gw != nil {
+		if gw.MinReplicas < 0 || gw.MaxReplicas < 0 {
+			return fmt.Errorf("istio ingress gateway replicas cannot be negative")
+		}
+		if gw.MaxReplicas > 0 && gw.MinReplicas > gw.MaxReplicas {
+			return fmt.Errorf("istio ingress gateway min replicas (%d) exceed max replicas (%d)", gw.MinReplicas, gw.MaxReplicas)
+		}
+		if gw.Resources != nil {
+			if err := gw.Resources.validate(); err != nil {
+				return fmt.Errorf("istio ingress gateway: %v", err)
+			}
+		}
+	}
+	if i.ProxyResources != nil {
+		if err := i.ProxyResources.validate(); err != nil {
+			return fmt.Errorf("istio proxy: %v", err)
+		}
+	}
+	return nil
+}
+
+//Overrides implements Overrides.Overrides
+func (i *Istio) Overrides() map[string]interface{} {
+	values := make(map[string]interface{})
+	if gw := i.IngressGateway; gw != nil {
+		minReplicas := gw.MinReplicas
+		if minReplicas == 0 {
+			minReplicas = 1
+		}
+		maxReplicas := gw.MaxReplicas
+		if maxReplicas == 0 {
+			maxReplicas = minReplicas
+		}
+		gwConfig := map[string]interface{}{
+			"hpaSpec": map[string]interface{}{
+				"minReplicas": minReplicas,
+				"maxReplicas": maxReplicas,
+			},
+		}
+		if gw.Resources != nil {
+			gwConfig["resources"] = gw.Resources.values()
+		}
+		values["components"] = map[string]interface{}{
+			"ingressGateways": map[string]interface{}{
+				"config": gwConfig,
+			},
+		}
+	}
+	if i.ProxyResources != nil {
+		values["helmValues"] = map[string]interface{}{
+			"global": map[string]interface{}{
+				"proxy": map[string]interface{}{
+					"resources": i.ProxyResources.values(),
+				},
+			},
+		}
+	}
+	return map[string]interface{}{istioChart: values}
+}
@@ -0,0 +1,86 @@
+package typed
+
+import "fmt"
+
+const (
+	//OryPostgreSQL stores the Hydra data in a PostgreSQL database deployed with Kyma
+	OryPostgreSQL = "postgresql"
+	//OryGCloud stores the Hydra data in a Google Cloud SQL database
+	OryGCloud = "gcloud"
+)
+
+const oryChart = "ory"
+
+//Ory overrides of the ory chart
+type Ory struct {
+	//Persistence of the Hydra OAuth2 server. Hydra keeps its data in memory if nil.
+	Persistence *OryPersistence
+	//Number of Oathkeeper replicas. The chart default is used if zero.
+	OathkeeperReplicas int
+}
+
+//OryPersistence configures the database of Hydra
+type OryPersistence struct {
+	//Database type: postgresql or gcloud. Defaults to postgresql.
+	Type     string
+	Database string
+	Username string
+	Password string
+}
+
+//Validate implements Overrides.Validate
+func (o *Ory) Validate() error {
+	if o.OathkeeperReplicas < 0 {
+		return fmt.Errorf("ory oathkeeper replicas cannot be negative")
+	}
+	if p := o.Persistence; p != nil {
+		if p.Type != "" && p.Type != OryPostgreSQL && p.Type != OryGCloud {
+			return fmt.Errorf("unknown ory persistence type '%s', supported types: %s, %s", p.Type, OryPostgreSQL, OryGCloud)
+		}
+		if (p.Username == "") != (p.Password == "") {
+			return fmt.Errorf("ory persistence requires both username and password")
+		}
+	}
+	return nil
+}
+
+//Overrides implements Overrides.Overrides
+func (o *Ory) Overrides() map[string]interface{} {
+	persistence := map[string]interface{}{"enabled": o.Persistence != nil}
+	global := map[string]interface{}{
+		"ory": map[string]interface{}{
+			"hydra": map[string]interface{}{"persistence": persistence},
+		},
+	}
+
+	if p := o.Persistence; p != nil {
+		dbType := p.Type
+		if dbType == "" {
+			dbType = OryPostgreSQL
+		}
+		persistence[OryPostgreSQL] = map[string]interface{}{"enabled": dbType == OryPostgreSQL}
+		persistence[OryGCloud] = map[string]interface{}{"enabled": dbType == OryGCloud}
+
+		postgresql := make(map[string]interface{})
+		if p.Database != "" {
+			postgresql["postgresqlDatabase"] = p.Database
+		}
+		if p.Username != "" {
+			postgresql["postgresqlUsername"] = p.Username
+			postgresql["postgresqlPassword"] = p.Password
+		}
+		if len(postgresql) > 0 {
+			global["postgresql"] = postgresql
+		}
+	}
+
+	result := map[string]interface{}{GlobalChart: global}
+	if o.OathkeeperReplicas > 0 {
+		result[oryChart] = map[string]interface{}{
+			"oathkeeper": map[string]interface{}{
+				"deployment": map[string]interface{}{"replicaCount": o.OathkeeperReplicas},
+			},
+		}
+	}
+	return result
+}
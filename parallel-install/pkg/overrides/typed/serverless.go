@@ -0,0 +1,93 @@
+package typed
+
+import "fmt"
+
+const serverlessChart = "serverless"
+
+var (
+	functionPresets = []string{"XS", "S", "M", "L", "XL"}
+	buildJobPresets = []string{"local-dev", "slow", "normal", "fast"}
+)
+
+//Serverless overrides of the serverless chart
+type Serverless struct {
+	//Use the Docker registry deployed with Kyma. Defaults to true if no external registry is set.
+	InternalRegistry *bool
+	//External Docker registry to push function images to
+	ExternalRegistry *DockerRegistry
+	//Default resource preset of functions: XS, S, M, L or XL
+	DefaultFunctionPreset string
+	//Default resource preset of function build jobs: local-dev, slow, normal or fast
+	DefaultBuildJobPreset string
+}
+
+//DockerRegistry defines the access to an external Docker registry
+type DockerRegistry struct {
+	//Address of the registry server, e.g. https://eu.gcr.io
+	ServerAddress string
+	//Address images are pushed to, e.g. eu.gcr.io/my-project. Defaults to the server address.
+	RegistryAddress string
+	Username        string
+	Password        string
+}
+
+//Validate implements Overrides.Validate
+func (s *Serverless) Validate() error {
+	if s.ExternalRegistry != nil {
+		if s.InternalRegistry != nil && *s.InternalRegistry {
+			return fmt.Errorf("serverless cannot use the internal and an external registry at the same time")
+		}
+		if s.ExternalRegistry.ServerAddress == "" {
+			return fmt.Errorf("serverless external registry requires a server address")
+		}
+	}
+	if s.DefaultFunctionPreset != "" && !contains(functionPresets, s.DefaultFunctionPreset) {
+		return fmt.Errorf("unknown serverless function preset '%s', supported presets: %v", s.DefaultFunctionPreset, functionPresets)
+	}
+	if s.DefaultBuildJobPreset != "" && !contains(buildJobPresets, s.DefaultBuildJobPreset) {
+		return fmt.Errorf("unknown serverless build job preset '%s', supported presets: %v", s.DefaultBuildJobPreset, buildJobPresets)
+	}
+	return nil
+}
+
+//Overrides implements Overrides.Overrides
+func (s *Serverless) Overrides() map[string]interface{} {
+	internal := s.InternalRegistry
+	if internal == nil {
+		internal = boolPtr(s.ExternalRegistry == nil)
+	}
+	registry := map[string]interface{}{
+		"enableInternal": *internal,
+	}
+	if ext := s.ExternalRegistry; ext != nil {
+		registryAddress := ext.RegistryAddress
+		if registryAddress == "" {
+			registryAddress = ext.ServerAddress
+		}
+		registry["serverAddress"] = ext.ServerAddress
+		registry["registryAddress"] = registryAddress
+		if ext.Username != "" {
+			registry["username"] = ext.Username
+			registry["password"] = ext.Password
+		}
+	}
+	values := map[string]interface{}{
+		"dockerRegistry": registry,
+	}
+
+	webhookValues := make(map[string]interface{})
+	if s.DefaultFunctionPreset != "" {
+		webhookValues["function"] = map[string]interface{}{
+			"resources": map[string]interface{}{"defaultPreset": s.DefaultFunctionPreset},
+		}
+	}
+	if s.DefaultBuildJobPreset != "" {
+		webhookValues["buildJob"] = map[string]interface{}{
+			"resources": map[string]interface{}{"defaultPreset": s.DefaultBuildJobPreset},
+		}
+	}
+	if len(webhookValues) > 0 {
+		values["webhook"] = map[string]interface{}{"values": webhookValues}
+	}
+	return map[string]interface{}{serverlessChart: values}
+}
@@ -0,0 +1,81 @@
+//Package typed provides strongly typed overrides for well-known Kyma components.
+//
+//Each type validates its fields and applies defaults before it is converted into the override maps
+//consumed by the OverridesBuilder, so misspelled or malformed override keys are caught at compile or validation time.
+package typed
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+//GlobalChart is the chart name under which global overrides are stored
+const GlobalChart = "global"
+
+//Overrides is implemented by all typed overrides
+type Overrides interface {
+	//Validate returns an error if the overrides are invalid
+	Validate() error
+	//Overrides returns the values per chart. Global values are stored under GlobalChart.
+	Overrides() map[string]interface{}
+}
+
+//Resources defines compute resource requests and limits
+type Resources struct {
+	Requests ResourceList
+	Limits   ResourceList
+}
+
+//ResourceList defines CPU and memory quantities, e.g. 100m and 128Mi
+type ResourceList struct {
+	CPU    string
+	Memory string
+}
+
+func (r *Resources) validate() error {
+	for _, q := range []string{r.Requests.CPU, r.Requests.Memory, r.Limits.CPU, r.Limits.Memory} {
+		if q == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(q); err != nil {
+			return fmt.Errorf("invalid resource quantity '%s'", q)
+		}
+	}
+	return nil
+}
+
+func (r *Resources) values() map[string]interface{} {
+	values := make(map[string]interface{})
+	if list := r.Requests.values(); len(list) > 0 {
+		values["requests"] = list
+	}
+	if list := r.Limits.values(); len(list) > 0 {
+		values["limits"] = list
+	}
+	return values
+}
+
+func (l ResourceList) values() map[string]interface{} {
+	values := make(map[string]interface{})
+	if l.CPU != "" {
+		values["cpu"] = l.CPU
+	}
+	if l.Memory != "" {
+		values["memory"] = l.Memory
+	}
+	return values
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
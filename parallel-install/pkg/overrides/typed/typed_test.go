@@ -0,0 +1,97 @@
+package typed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIstio(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		istio := &Istio{IngressGateway: &IstioIngressGateway{MinReplicas: 2}}
+		require.NoError(t, istio.Validate())
+		require.Equal(t, map[string]interface{}{
+			"istio": map[string]interface{}{
+				"components": map[string]interface{}{
+					"ingressGateways": map[string]interface{}{
+						"config": map[string]interface{}{
+							"hpaSpec": map[string]interface{}{"minReplicas": 2, "maxReplicas": 2},
+						},
+					},
+				},
+			},
+		}, istio.Overrides())
+	})
+	t.Run("Invalid replicas", func(t *testing.T) {
+		istio := &Istio{IngressGateway: &IstioIngressGateway{MinReplicas: 3, MaxReplicas: 2}}
+		require.Error(t, istio.Validate())
+	})
+	t.Run("Invalid quantity", func(t *testing.T) {
+		istio := &Istio{ProxyResources: &Resources{Limits: ResourceList{Memory: "lots"}}}
+		require.Error(t, istio.Validate())
+	})
+}
+
+func TestServerless(t *testing.T) {
+	t.Run("Internal registry by default", func(t *testing.T) {
+		serverless := &Serverless{DefaultFunctionPreset: "M"}
+		require.NoError(t, serverless.Validate())
+		values := serverless.Overrides()["serverless"].(map[string]interface{})
+		require.Equal(t, map[string]interface{}{"enableInternal": true}, values["dockerRegistry"])
+		require.Equal(t, map[string]interface{}{
+			"values": map[string]interface{}{
+				"function": map[string]interface{}{
+					"resources": map[string]interface{}{"defaultPreset": "M"},
+				},
+			},
+		}, values["webhook"])
+	})
+	t.Run("External registry", func(t *testing.T) {
+		serverless := &Serverless{ExternalRegistry: &DockerRegistry{ServerAddress: "https://eu.gcr.io", Username: "user", Password: "pwd"}}
+		require.NoError(t, serverless.Validate())
+		values := serverless.Overrides()["serverless"].(map[string]interface{})
+		require.Equal(t, map[string]interface{}{
+			"enableInternal":  false,
+			"serverAddress":   "https://eu.gcr.io",
+			"registryAddress": "https://eu.gcr.io",
+			"username":        "user",
+			"password":        "pwd",
+		}, values["dockerRegistry"])
+	})
+	t.Run("Internal and external registry", func(t *testing.T) {
+		serverless := &Serverless{InternalRegistry: boolPtr(true), ExternalRegistry: &DockerRegistry{ServerAddress: "https://eu.gcr.io"}}
+		require.Error(t, serverless.Validate())
+	})
+	t.Run("Unknown preset", func(t *testing.T) {
+		require.Error(t, (&Serverless{DefaultBuildJobPreset: "turbo"}).Validate())
+	})
+}
+
+func TestOry(t *testing.T) {
+	t.Run("In-memory by default", func(t *testing.T) {
+		ory := &Ory{}
+		require.NoError(t, ory.Validate())
+		require.Equal(t, map[string]interface{}{
+			"global": map[string]interface{}{
+				"ory": map[string]interface{}{
+					"hydra": map[string]interface{}{
+						"persistence": map[string]interface{}{"enabled": false},
+					},
+				},
+			},
+		}, ory.Overrides())
+	})
+	t.Run("GCloud persistence", func(t *testing.T) {
+		ory := &Ory{Persistence: &OryPersistence{Type: OryGCloud, Database: "db", Username: "user", Password: "pwd"}}
+		require.NoError(t, ory.Validate())
+		global := ory.Overrides()["global"].(map[string]interface{})
+		persistence := global["ory"].(map[string]interface{})["hydra"].(map[string]interface{})["persistence"].(map[string]interface{})
+		require.Equal(t, map[string]interface{}{"enabled": true}, persistence[OryGCloud])
+		require.Equal(t, map[string]interface{}{"enabled": false}, persistence[OryPostgreSQL])
+		require.Equal(t, "db", global["postgresql"].(map[string]interface{})["postgresqlDatabase"])
+	})
+	t.Run("Invalid persistence", func(t *testing.T) {
+		require.Error(t, (&Ory{Persistence: &OryPersistence{Type: "mysql"}}).Validate())
+		require.Error(t, (&Ory{Persistence: &OryPersistence{Username: "user"}}).Validate())
+	})
+}
@@ -0,0 +1,173 @@
+//Package pipeline chains the provisioning of a cluster with the deployment of Kyma on it.
+//
+//The pipeline is not bound to a provisioning library: any type implementing the Provisioner interface,
+//for example a wrapper around the hydroform provision package, can be used to create the cluster.
+package pipeline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/domain"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/watcher"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const defaultReadinessTimeout = 10 * time.Minute
+
+//Provisioner creates the cluster Kyma gets deployed on
+type Provisioner interface {
+	//Provision creates the cluster and returns its kubeconfig in YAML format
+	Provision() (kubeconfig []byte, err error)
+}
+
+//ProvisionerFunc is an adapter to use ordinary functions as Provisioner
+type ProvisionerFunc func() ([]byte, error)
+
+//Provision calls f()
+func (f ProvisionerFunc) Provision() ([]byte, error) {
+	return f()
+}
+
+//Pipeline provisions a cluster and deploys Kyma on it
+type Pipeline struct {
+	provisioner    Provisioner
+	cfg            *config.Config
+	ob             *deployment.OverridesBuilder
+	processUpdates func(deployment.ProcessUpdate)
+	//ReadinessTimeout is the maximum time to wait for all nodes of the provisioned cluster to be ready
+	ReadinessTimeout time.Duration
+	//NewBootstrapper is optional and creates the bootstrapper of the DNS records and TLS certificate of the Kyma domain.
+	//The resulting domain and certificate are added to the overrides before Kyma gets deployed.
+	NewBootstrapper func(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface) domain.Bootstrapper
+	//newKubeClient and newDynamicClient are replaced in tests
+	newKubeClient    func(kubeconfig []byte) (kubernetes.Interface, error)
+	newDynamicClient func(kubeconfig []byte) (dynamic.Interface, error)
+}
+
+//New creates a new Pipeline instance.
+//The kubeconfig source of the given config is set to the provisioned cluster, so it can be left empty.
+//The processUpdates callback receives the updates of the provisioning as well as the ones of the deployment.
+func New(provisioner Provisioner, cfg *config.Config, ob *deployment.OverridesBuilder, processUpdates func(deployment.ProcessUpdate)) *Pipeline {
+	return &Pipeline{
+		provisioner:      provisioner,
+		cfg:              cfg,
+		ob:               ob,
+		processUpdates:   processUpdates,
+		ReadinessTimeout: defaultReadinessTimeout,
+		newKubeClient:    newKubeClient,
+		newDynamicClient: newDynamicClient,
+	}
+}
+
+//Run provisions the cluster, waits until it is ready and deploys Kyma on it
+func (p *Pipeline) Run() error {
+	p.processUpdate(deployment.ProcessStart, nil)
+
+	kubeconfig, err := p.provisioner.Provision()
+	if err != nil {
+		err = fmt.Errorf("error while provisioning the cluster: %v", err)
+		p.processUpdate(deployment.ProcessExecutionFailure, err)
+		return err
+	}
+
+	kubeClient, err := p.newKubeClient(kubeconfig)
+	if err != nil {
+		p.processUpdate(deployment.ProcessExecutionFailure, err)
+		return err
+	}
+
+	p.processUpdate(deployment.ProcessRunning, nil)
+	if err := waitForNodes(kubeClient, p.ReadinessTimeout); err != nil {
+		err = fmt.Errorf("cluster did not get ready: %v", err)
+		p.processUpdate(deployment.ProcessTimeoutFailure, err)
+		return err
+	}
+
+	if p.NewBootstrapper != nil {
+		if err := p.bootstrapDomain(kubeClient, kubeconfig); err != nil {
+			p.processUpdate(deployment.ProcessExecutionFailure, err)
+			return err
+		}
+	}
+	p.processUpdate(deployment.ProcessFinished, nil)
+
+	p.cfg.KubeconfigSource = config.KubeconfigSource{
+		Content: string(kubeconfig),
+	}
+
+	d, err := deployment.NewDeployment(p.cfg, p.ob, p.processUpdates)
+	if err != nil {
+		return err
+	}
+	return d.StartKymaDeployment()
+}
+
+func (p *Pipeline) bootstrapDomain(kubeClient kubernetes.Interface, kubeconfig []byte) error {
+	dynamicClient, err := p.newDynamicClient(kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	result, err := p.NewBootstrapper(kubeClient, dynamicClient).Bootstrap()
+	if err != nil {
+		return fmt.Errorf("error while bootstrapping the domain: %v", err)
+	}
+	return result.ApplyTo(p.ob)
+}
+
+func (p *Pipeline) processUpdate(event deployment.ProcessEvent, err error) {
+	if p.processUpdates == nil {
+		return
+	}
+	p.processUpdates(deployment.ProcessUpdate{
+		Event: event,
+		Phase: deployment.ProvisionCluster,
+		Error: err,
+	})
+}
+
+func newKubeClient(kubeconfig []byte) (kubernetes.Interface, error) {
+	restConfig, err := config.RestConfig(config.KubeconfigSource{Content: string(kubeconfig)})
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
+
+func newDynamicClient(kubeconfig []byte) (dynamic.Interface, error) {
+	restConfig, err := config.RestConfig(config.KubeconfigSource{Content: string(kubeconfig)})
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(restConfig)
+}
+
+//waitForNodes blocks until the cluster has nodes and all of them are ready.
+//The nodes are watched, and failing requests are retried, as the API server might not be reachable right after provisioning.
+func waitForNodes(kubeClient kubernetes.Interface, timeout time.Duration) error {
+	return watcher.Until(watcher.Nodes(kubeClient), &v1.Node{}, timeout, func(objects []interface{}) (bool, error) {
+		if len(objects) == 0 {
+			return false, nil
+		}
+		for _, obj := range objects {
+			if !isNodeReady(*obj.(*v1.Node)) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+func isNodeReady(node v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package pipeline
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPipeline_Run(t *testing.T) {
+
+	t.Run("Provisioning failure is reported", func(t *testing.T) {
+		var updates []deployment.ProcessUpdate
+		provisioner := ProvisionerFunc(func() ([]byte, error) {
+			return nil, errors.New("quota exceeded")
+		})
+
+		p := New(provisioner, &config.Config{}, nil, func(update deployment.ProcessUpdate) {
+			updates = append(updates, update)
+		})
+
+		err := p.Run()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "quota exceeded")
+
+		require.Len(t, updates, 2)
+		assert.Equal(t, deployment.ProcessStart, updates[0].Event)
+		assert.Equal(t, deployment.ProcessExecutionFailure, updates[1].Event)
+		assert.Equal(t, deployment.ProvisionCluster, updates[1].Phase)
+	})
+
+	t.Run("Cluster which does not get ready is reported", func(t *testing.T) {
+		var updates []deployment.ProcessUpdate
+		provisioner := ProvisionerFunc(func() ([]byte, error) {
+			return []byte("kubeconfig"), nil
+		})
+
+		p := New(provisioner, &config.Config{}, nil, func(update deployment.ProcessUpdate) {
+			updates = append(updates, update)
+		})
+		p.ReadinessTimeout = time.Second
+		p.newKubeClient = func(kubeconfig []byte) (kubernetes.Interface, error) {
+			return fake.NewSimpleClientset(node("node-1", v1.ConditionFalse)), nil
+		}
+
+		err := p.Run()
+		require.Error(t, err)
+
+		require.Len(t, updates, 3)
+		assert.Equal(t, deployment.ProcessRunning, updates[1].Event)
+		assert.Equal(t, deployment.ProcessTimeoutFailure, updates[2].Event)
+	})
+
+	t.Run("Domain bootstrap failure is reported", func(t *testing.T) {
+		var updates []deployment.ProcessUpdate
+		provisioner := ProvisionerFunc(func() ([]byte, error) {
+			return []byte("kubeconfig"), nil
+		})
+
+		p := New(provisioner, &config.Config{}, &deployment.OverridesBuilder{}, func(update deployment.ProcessUpdate) {
+			updates = append(updates, update)
+		})
+		p.newKubeClient = func(kubeconfig []byte) (kubernetes.Interface, error) {
+			return fake.NewSimpleClientset(node("node-1", v1.ConditionTrue)), nil
+		}
+		p.newDynamicClient = func(kubeconfig []byte) (dynamic.Interface, error) {
+			return nil, nil
+		}
+		p.NewBootstrapper = func(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface) domain.Bootstrapper {
+			return bootstrapperFunc(func() (*domain.Result, error) {
+				return nil, errors.New("no issuer")
+			})
+		}
+
+		err := p.Run()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no issuer")
+
+		require.Len(t, updates, 3)
+		assert.Equal(t, deployment.ProcessExecutionFailure, updates[2].Event)
+	})
+}
+
+type bootstrapperFunc func() (*domain.Result, error)
+
+func (f bootstrapperFunc) Bootstrap() (*domain.Result, error) {
+	return f()
+}
+
+func TestWaitForNodes(t *testing.T) {
+
+	t.Run("All nodes ready", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(node("node-1", v1.ConditionTrue), node("node-2", v1.ConditionTrue))
+		assert.NoError(t, waitForNodes(kubeClient, time.Second))
+	})
+
+	t.Run("Node not ready", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(node("node-1", v1.ConditionTrue), node("node-2", v1.ConditionUnknown))
+		assert.Error(t, waitForNodes(kubeClient, time.Second))
+	})
+
+	t.Run("No nodes", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		assert.Error(t, waitForNodes(kubeClient, time.Second))
+	})
+}
+
+func node(name string, ready v1.ConditionStatus) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: ready},
+			},
+		},
+	}
+}
@@ -0,0 +1,116 @@
+package preinstaller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// managedResource is a resource type the PreInstaller applies from a directory of the installation resources
+type managedResource struct {
+	resourceType string
+	dirSuffix    string
+	gvr          schema.GroupVersionResource
+}
+
+var managedResources = []managedResource{
+	{
+		resourceType: "CustomResourceDefinition",
+		dirSuffix:    "crds",
+		gvr:          schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+	},
+	{
+		resourceType: "Namespace",
+		dirSuffix:    "namespaces",
+		gvr:          schema.GroupVersionResource{Version: "v1", Resource: "namespaces"},
+	},
+}
+
+// CleanupOutput contains the resources handled by Cleanup, in the format <kind>/<name>.
+type CleanupOutput struct {
+	// Deleted resources of previous runs.
+	Deleted []string
+	// NotDeleted resources of previous runs, which could not be deleted.
+	NotDeleted []string
+}
+
+// Cleanup garbage-collects the resources which were applied by a previous run with the same installation ID,
+// but are not part of the current installation resources anymore.
+// Resources without the installation ID label, e.g. created by other tools, are never deleted.
+// Deleting a CRD also deletes all of its custom resources, and deleting a Namespace deletes its content.
+func (i *PreInstaller) Cleanup() (CleanupOutput, error) {
+	var o CleanupOutput
+	// all directories are scanned before anything is deleted, so an incomplete scan deletes nothing
+	current := make(map[string]map[string]bool, len(managedResources))
+	for _, managed := range managedResources {
+		names, err := i.currentResourceNames(managed)
+		if err != nil {
+			return o, err
+		}
+		current[managed.resourceType] = names
+	}
+
+	for _, managed := range managedResources {
+
+		selector := fmt.Sprintf("%s=%s", InstallationIDLabel, i.cfg.installationID())
+		list, err := i.dynamicClient.Resource(managed.gvr).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return o, errors.Wrap(err, fmt.Sprintf("Failed to list the %s resources of previous runs", managed.resourceType))
+		}
+
+		for _, item := range list.Items {
+			if current[managed.resourceType][item.GetName()] {
+				continue
+			}
+			name := fmt.Sprintf("%s/%s", managed.resourceType, item.GetName())
+			i.cfg.Log.Infof("Deleting %s, which is not part of the installation resources anymore", name)
+			err := i.cfg.Retry().Do(func() error {
+				err := i.dynamicClient.Resource(managed.gvr).Delete(context.TODO(), item.GetName(), metav1.DeleteOptions{})
+				if apierrors.IsNotFound(err) {
+					return nil
+				}
+				return err
+			})
+			if err != nil {
+				i.cfg.Log.Warnf("Error occurred when deleting %s: %s", name, err.Error())
+				o.NotDeleted = append(o.NotDeleted, name)
+				continue
+			}
+			o.Deleted = append(o.Deleted, name)
+		}
+	}
+	return o, nil
+}
+
+// currentResourceNames returns the names of the resources of the installation resources.
+// A directory which cannot be read, an empty component directory, or a file which cannot be parsed fails the cleanup,
+// as the resources they define would be deleted otherwise.
+func (i *PreInstaller) currentResourceNames(managed managedResource) (map[string]bool, error) {
+	resources, emptyComponents, err := i.findResourcesIn(resourceInfoInput{
+		resourceType:             managed.resourceType,
+		dirSuffix:                managed.dirSuffix,
+		installationResourcePath: i.cfg.InstallationResourcePath,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("Failed to read the %s resources", managed.resourceType))
+	}
+	if len(emptyComponents) > 0 {
+		return nil, fmt.Errorf("Failed to read the %s resources: no resources found for components %v", managed.resourceType, emptyComponents)
+	}
+
+	names := make(map[string]bool)
+	for _, resource := range resources {
+		parsedResource, err := i.parser.ParseFile(resource.path)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("Failed to parse resource %s of component %s", resource.fileName, resource.component))
+		}
+		if parsedResource.GetKind() == managed.resourceType {
+			names[parsedResource.GetName()] = true
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,179 @@
+package preinstaller
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/preinstaller/mocks"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestPreInstaller_Cleanup(t *testing.T) {
+	resourcePath := fmt.Sprintf("%s%s", getTestingResourcesDirectory(), "/correct")
+	crdsGVR := managedResources[0].gvr
+	namespacesGVR := managedResources[1].gvr
+
+	newDynamicClient := func() *fake.FakeDynamicClient {
+		return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{crdsGVR: "CustomResourceDefinitionList", namespacesGVR: "NamespaceList"},
+			withLabel(fixCrdResourceWith("name"), "kyma"),
+			withLabel(fixCrdResourceWith("stale"), "kyma"),
+			withLabel(fixCrdResourceWith("other-installation"), "other"),
+			fixCrdResourceWith("foreign"),
+			withLabel(fixNamespaceResourceWith("stale-ns"), "kyma"),
+		)
+	}
+	newParser := func() *mocks.ResourceParser {
+		resourceParser := &mocks.ResourceParser{}
+		for _, comp := range []string{"comp1", "comp2"} {
+			resourceParser.On("ParseFile", fmt.Sprintf("%s/crds/%s/crd.yaml", resourcePath, comp)).Return(fixCrdResourceWith("name"), nil)
+			resourceParser.On("ParseFile", fmt.Sprintf("%s/namespaces/%s/ns.yaml", resourcePath, comp)).Return(fixNamespaceResourceWith("name"), nil)
+		}
+		return resourceParser
+	}
+
+	t.Run("should delete resources of previous runs", func(t *testing.T) {
+		// given
+		dynamicClient := newDynamicClient()
+		cfg := getTestingConfig()
+		cfg.InstallationResourcePath = resourcePath
+		i := getPreInstaller(&mocks.ResourceApplier{}, newParser(), cfg, dynamicClient, getTestingRetryOptions())
+
+		// when
+		output, err := i.Cleanup()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CustomResourceDefinition/stale", "Namespace/stale-ns"}, output.Deleted)
+		assert.Empty(t, output.NotDeleted)
+
+		crds, err := dynamicClient.Resource(crdsGVR).List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		var names []string
+		for _, crd := range crds.Items {
+			names = append(names, crd.GetName())
+		}
+		assert.ElementsMatch(t, []string{"name", "other-installation", "foreign"}, names)
+	})
+
+	t.Run("should not delete anything if a resource cannot be parsed", func(t *testing.T) {
+		// given
+		dynamicClient := newDynamicClient()
+		cfg := getTestingConfig()
+		cfg.InstallationResourcePath = resourcePath
+		resourceParser := &mocks.ResourceParser{}
+		resourceParser.On("ParseFile", fmt.Sprintf("%s/crds/comp1/crd.yaml", resourcePath)).Return(nil, errors.New("Parse error"))
+		i := getPreInstaller(&mocks.ResourceApplier{}, resourceParser, cfg, dynamicClient, getTestingRetryOptions())
+
+		// when
+		output, err := i.Cleanup()
+
+		// then
+		require.Error(t, err)
+		assert.Empty(t, output.Deleted)
+		_, err = dynamicClient.Resource(crdsGVR).Get(context.TODO(), "stale", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+}
+
+func TestPreInstaller_EmptyComponentDirectory(t *testing.T) {
+	// the empty directory of comp1 is read before the populated directory of comp2
+	resourcePath, err := ioutil.TempDir("", "preinstaller")
+	require.NoError(t, err)
+	defer os.RemoveAll(resourcePath)
+	for _, dir := range []string{"crds/comp1", "crds/comp2", "namespaces/comp1", "namespaces/comp2"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(resourcePath, dir), 0700))
+	}
+	crdFile := filepath.Join(resourcePath, "crds/comp2/crd.yaml")
+	require.NoError(t, ioutil.WriteFile(crdFile, []byte("kind: CustomResourceDefinition"), 0600))
+	nsFile := filepath.Join(resourcePath, "namespaces/comp2/ns.yaml")
+	require.NoError(t, ioutil.WriteFile(nsFile, []byte("kind: Namespace"), 0600))
+
+	cfg := getTestingConfig()
+	cfg.InstallationResourcePath = resourcePath
+	cfg.RetryPolicy = retry.Fixed(0, 1)
+
+	t.Run("should install the resources of the components after an empty directory", func(t *testing.T) {
+		// given
+		crdResource := fixCrdResourceWith("live")
+		resourceParser := &mocks.ResourceParser{}
+		resourceParser.On("ParseFile", crdFile).Return(crdResource, nil)
+		resourceApplier := &mocks.ResourceApplier{}
+		resourceApplier.On("Apply", crdResource).Return(nil)
+		i := getPreInstaller(resourceApplier, resourceParser, cfg, fake.NewSimpleDynamicClient(runtime.NewScheme()), getTestingRetryOptions())
+
+		// when
+		output, err := i.InstallCRDs()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, containsFileWithDetails(output.Installed, "comp2", crdFile))
+	})
+
+	t.Run("should not delete anything if a component directory is empty", func(t *testing.T) {
+		// given
+		crdsGVR := managedResources[0].gvr
+		namespacesGVR := managedResources[1].gvr
+		dynamicClient := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{crdsGVR: "CustomResourceDefinitionList", namespacesGVR: "NamespaceList"},
+			withLabel(fixCrdResourceWith("live"), "kyma"),
+			withLabel(fixCrdResourceWith("stale"), "kyma"),
+			withLabel(fixNamespaceResourceWith("live-ns"), "kyma"),
+		)
+		resourceParser := &mocks.ResourceParser{}
+		resourceParser.On("ParseFile", crdFile).Return(fixCrdResourceWith("live"), nil)
+		resourceParser.On("ParseFile", nsFile).Return(fixNamespaceResourceWith("live-ns"), nil)
+		i := getPreInstaller(&mocks.ResourceApplier{}, resourceParser, cfg, dynamicClient, getTestingRetryOptions())
+
+		// when
+		output, err := i.Cleanup()
+
+		// then
+		require.Error(t, err)
+		assert.Empty(t, output.Deleted)
+		for _, name := range []string{"live", "stale"} {
+			_, err = dynamicClient.Resource(crdsGVR).Get(context.TODO(), name, metav1.GetOptions{})
+			require.NoError(t, err)
+		}
+		_, err = dynamicClient.Resource(namespacesGVR).Get(context.TODO(), "live-ns", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+}
+
+func TestPreInstaller_LabelsAppliedResources(t *testing.T) {
+	resourcePath := fmt.Sprintf("%s%s", getTestingResourcesDirectory(), "/correct")
+	cfg := getTestingConfig()
+	cfg.InstallationResourcePath = resourcePath
+	cfg.InstallationID = "tenant1"
+	crdResource := fixCrdResourceWith("name")
+
+	resourceParser := &mocks.ResourceParser{}
+	resourceParser.On("ParseFile", fmt.Sprintf("%s/crds/comp1/crd.yaml", resourcePath)).Return(crdResource, nil)
+	resourceParser.On("ParseFile", fmt.Sprintf("%s/crds/comp2/crd.yaml", resourcePath)).Return(crdResource, nil)
+	resourceApplier := &mocks.ResourceApplier{}
+	resourceApplier.On("Apply", crdResource).Return(nil)
+	i := getPreInstaller(resourceApplier, resourceParser, cfg, fake.NewSimpleDynamicClient(runtime.NewScheme()), getTestingRetryOptions())
+
+	output, err := i.InstallCRDs()
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(output.Installed))
+	assert.Equal(t, "tenant1", crdResource.GetLabels()[InstallationIDLabel])
+}
+
+func withLabel(resource *unstructured.Unstructured, installationID string) *unstructured.Unstructured {
+	resource.SetLabels(map[string]string{InstallationIDLabel: installationID})
+	return resource
+}
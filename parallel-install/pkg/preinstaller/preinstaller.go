@@ -19,6 +19,9 @@
 // Installing CRDs resources requires a folder named `crds`.
 // Installing Namespace resources requires a folder named `namespaces`.
 // For now only these two resources types are supported.
+//
+// Every applied resource is labeled with the installation ID. Cleanup deletes the labeled resources
+// of previous runs which are not part of the installation resources anymore.
 
 package preinstaller
 
@@ -27,17 +30,40 @@ import (
 	"io/ioutil"
 	"os"
 
-	"github.com/avast/retry-go"
+	retrygo "github.com/avast/retry-go"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
 	"k8s.io/client-go/dynamic"
 )
 
+// InstallationIDLabel is set on every resource the PreInstaller applies. Its value is the installation ID.
+const InstallationIDLabel = "preinstaller.kyma-project.io/installation-id"
+
+const defaultInstallationID = "kyma"
+
 // Config defines configuration values for the PreInstaller.
 type Config struct {
 	InstallationResourcePath string                  //Path to the installation resources.
 	Log                      logger.Interface        //Logger to be used
 	KubeconfigSource         config.KubeconfigSource //KubeconfigSource to be used
+	InstallationID           string                  //InstallationID labels the applied resources, so that Cleanup can find them. Defaults to "kyma".
+	RetryPolicy              retry.Policy            //RetryPolicy of the deletions of Cleanup. Defaults to the retry options passed to NewPreInstaller.
+}
+
+func (c Config) installationID() string {
+	if c.InstallationID == "" {
+		return defaultInstallationID
+	}
+	return c.InstallationID
+}
+
+// Retry returns the configured retry policy or the default policy
+func (c Config) Retry() retry.Policy {
+	if c.RetryPolicy == nil {
+		return retry.Default()
+	}
+	return c.RetryPolicy
 }
 
 // PreInstaller prepares k8s cluster for Kyma installation.
@@ -46,7 +72,6 @@ type PreInstaller struct {
 	parser        ResourceParser
 	cfg           Config
 	dynamicClient dynamic.Interface
-	retryOptions  []retry.Option
 }
 
 // File consists of a path to the file that was a part of PreInstaller installation
@@ -78,7 +103,7 @@ type resourceInfoResult struct {
 }
 
 // NewPreInstaller creates a new instance of PreInstaller.
-func NewPreInstaller(applier ResourceApplier, parser ResourceParser, cfg Config, retryOptions []retry.Option) (*PreInstaller, error) {
+func NewPreInstaller(applier ResourceApplier, parser ResourceParser, cfg Config, retryOptions []retrygo.Option) (*PreInstaller, error) {
 	restConfig, err := config.RestConfig(cfg.KubeconfigSource)
 	if err != nil {
 		return nil, err
@@ -89,12 +114,15 @@ func NewPreInstaller(applier ResourceApplier, parser ResourceParser, cfg Config,
 		return nil, err
 	}
 
+	if cfg.RetryPolicy == nil && len(retryOptions) > 0 {
+		cfg.RetryPolicy = retry.FromOptions(retryOptions...)
+	}
+
 	return &PreInstaller{
 		applier:       applier,
 		parser:        parser,
 		cfg:           cfg,
 		dynamicClient: dynamicClient,
-		retryOptions:  retryOptions,
 	}, nil
 }
 
@@ -135,7 +163,7 @@ func (i *PreInstaller) CreateNamespaces() (Output, error) {
 }
 
 func (i *PreInstaller) install(input resourceInfoInput) (o Output, err error) {
-	resources, err := i.findResourcesIn(input)
+	resources, _, err := i.findResourcesIn(input)
 	if err != nil {
 		return Output{}, err
 	}
@@ -143,19 +171,20 @@ func (i *PreInstaller) install(input resourceInfoInput) (o Output, err error) {
 	return i.apply(resources)
 }
 
-func (i *PreInstaller) findResourcesIn(input resourceInfoInput) (results []resourceInfoResult, err error) {
+// findResourcesIn returns the resource files of all component directories, and the components whose directory contains no resources
+func (i *PreInstaller) findResourcesIn(input resourceInfoInput) (results []resourceInfoResult, emptyComponents []string, err error) {
 	installationResourcePath := input.installationResourcePath
 	path := fmt.Sprintf("%s/%s", installationResourcePath, input.dirSuffix)
 	rawComponentsDir, err := ioutil.ReadDir(path)
 	if err != nil {
-		return results, err
+		return results, nil, err
 	}
 
 	components := findOnlyDirectoriesAmong(rawComponentsDir)
 
 	if components == nil || len(components) == 0 {
 		i.cfg.Log.Warn("There were no components detected for installation. Skipping.")
-		return results, nil
+		return results, nil, nil
 	}
 
 	for _, component := range components {
@@ -163,12 +192,13 @@ func (i *PreInstaller) findResourcesIn(input resourceInfoInput) (results []resou
 		pathToComponent := fmt.Sprintf("%s/%s", path, componentName)
 		resources, err := ioutil.ReadDir(pathToComponent)
 		if err != nil {
-			return results, err
+			return results, nil, err
 		}
 
 		if len(resources) == 0 {
 			i.cfg.Log.Warnf("There were no resources detected for component: %s", componentName)
-			break
+			emptyComponents = append(emptyComponents, componentName)
+			continue
 		}
 
 		for _, resource := range resources {
@@ -185,7 +215,7 @@ func (i *PreInstaller) findResourcesIn(input resourceInfoInput) (results []resou
 		}
 	}
 
-	return results, nil
+	return results, emptyComponents, nil
 }
 
 func (i *PreInstaller) apply(resources []resourceInfoResult) (o Output, err error) {
@@ -208,6 +238,13 @@ func (i *PreInstaller) apply(resources []resourceInfoResult) (o Output, err erro
 			continue
 		}
 
+		labels := parsedResource.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[InstallationIDLabel] = i.cfg.installationID()
+		parsedResource.SetLabels(labels)
+
 		i.cfg.Log.Infof("Processing %s file: %s of component: %s", resource.resourceType, resource.fileName, resource.component)
 		err = i.applier.Apply(parsedResource)
 		if err != nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/preinstaller/mocks"
+	retrypolicy "github.com/kyma-incubator/hydroform/parallel-install/pkg/retry"
 	"github.com/kyma-incubator/hydroform/parallel-install/pkg/test"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -489,11 +490,13 @@ func fixNamespaceResourceWith(name string) *unstructured.Unstructured {
 }
 
 func getPreInstaller(applier ResourceApplier, parser ResourceParser, cfg Config, dynamicClient dynamic.Interface, retryOptions []retry.Option) *PreInstaller {
+	if cfg.RetryPolicy == nil {
+		cfg.RetryPolicy = retrypolicy.FromOptions(retryOptions...)
+	}
 	return &PreInstaller{
 		applier:       applier,
 		parser:        parser,
 		cfg:           cfg,
 		dynamicClient: dynamicClient,
-		retryOptions:  retryOptions,
 	}
 }
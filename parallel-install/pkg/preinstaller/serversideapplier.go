@@ -0,0 +1,87 @@
+package preinstaller
+
+import (
+	"context"
+
+	"github.com/avast/retry-go"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+const fieldManager = "kyma-installer"
+
+// ServerSideResourceApplier applies resources with server-side apply.
+// Fields which are owned by other managers, e.g. defaults set by controllers, are kept.
+type ServerSideResourceApplier struct {
+	log           logger.Interface
+	dynamicClient dynamic.Interface
+	retryOptions  []retry.Option
+}
+
+// NewServerSideResourceApplier returns a new instance of ServerSideResourceApplier.
+func NewServerSideResourceApplier(log logger.Interface, dynamicClient dynamic.Interface, retryOptions []retry.Option) *ServerSideResourceApplier {
+	return &ServerSideResourceApplier{
+		log:           log,
+		dynamicClient: dynamicClient,
+		retryOptions:  retryOptions,
+	}
+}
+
+// NewResourceApplier returns the ServerSideResourceApplier if the ServerSideApply feature is enabled,
+// and the GenericResourceApplier otherwise.
+func NewResourceApplier(featureGates config.FeatureGates, kubeconfigSource config.KubeconfigSource, log logger.Interface, retryOptions []retry.Option) (ResourceApplier, error) {
+	if !featureGates.Enabled(config.ServerSideApply) {
+		manager, err := NewDefaultResourceManager(kubeconfigSource, log, retryOptions)
+		if err != nil {
+			return nil, err
+		}
+		return NewGenericResourceApplier(log, manager), nil
+	}
+
+	restConfig, err := config.RestConfig(kubeconfigSource)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return NewServerSideResourceApplier(log, dynamicClient, retryOptions), nil
+}
+
+func (c *ServerSideResourceApplier) Apply(resource *unstructured.Unstructured) error {
+	if resource == nil {
+		return errors.New("Could not apply not existing resource")
+	}
+
+	gvk := resource.GroupVersionKind()
+	resourceSchema := schema.GroupVersionResource{
+		Group:    gvk.Group,
+		Version:  gvk.Version,
+		Resource: pluralForm(gvk.Kind),
+	}
+	data, err := resource.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	resourceName := resource.GetName()
+	force := true
+	c.log.Infof("Applying resource: %s.", resourceName)
+	return retry.Do(func() error {
+		_, err := c.dynamicClient.Resource(resourceSchema).Patch(context.TODO(), resourceName, types.ApplyPatchType, data, metav1.PatchOptions{
+			FieldManager: fieldManager,
+			Force:        &force,
+		})
+		if err != nil {
+			c.log.Errorf("Error occurred during resource apply: %s", err.Error())
+		}
+		return err
+	}, c.retryOptions...)
+}
@@ -0,0 +1,66 @@
+package preinstaller
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestServerSideResourceApplier_Apply(t *testing.T) {
+
+	t.Run("should apply resource with server-side apply", func(t *testing.T) {
+		// given
+		dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme())
+		var patch k8stesting.PatchAction
+		dynamicClient.PrependReactor("patch", "kinds", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			patch = action.(k8stesting.PatchAction)
+			return true, fixResourceWith("name"), nil
+		})
+		applier := NewServerSideResourceApplier(logger.NewLogger(true), dynamicClient, getTestingRetryOptions())
+
+		// when
+		err := applier.Apply(fixResourceWith("name"))
+
+		// then
+		assert.NoError(t, err)
+		assert.NotNil(t, patch)
+		assert.Equal(t, "name", patch.GetName())
+		assert.Equal(t, types.ApplyPatchType, patch.GetPatchType())
+		assert.Contains(t, string(patch.GetPatch()), `"kind":"Kind"`)
+	})
+
+	t.Run("should not apply resource", func(t *testing.T) {
+		t.Run("due to not existing resource", func(t *testing.T) {
+			// given
+			applier := NewServerSideResourceApplier(logger.NewLogger(true), fake.NewSimpleDynamicClient(runtime.NewScheme()), getTestingRetryOptions())
+
+			// when
+			err := applier.Apply(nil)
+
+			// then
+			assert.EqualError(t, err, "Could not apply not existing resource")
+		})
+
+		t.Run("due to apply error", func(t *testing.T) {
+			// given
+			dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme())
+			dynamicClient.PrependReactor("patch", "kinds", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.New("Apply error")
+			})
+			applier := NewServerSideResourceApplier(logger.NewLogger(true), dynamicClient, getTestingRetryOptions())
+
+			// when
+			err := applier.Apply(fixResourceWith("name"))
+
+			// then
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "Apply error")
+		})
+	})
+}
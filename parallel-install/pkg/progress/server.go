@@ -0,0 +1,289 @@
+//Package progress serves the process updates of a deployment or uninstallation over HTTP,
+//so that remote UIs can watch the progress of a headless installer, for example a Kubernetes Job.
+//
+//The server provides two endpoints:
+//  GET /status  returns the current status as JSON
+//  GET /events  streams every process update as server-sent event, and the final status when the process finished
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+)
+
+const (
+	statusPath       = "/status"
+	eventsPath       = "/events"
+	eventName        = "update"
+	finishedName     = "finished"
+	failedName       = "failed"
+	subscriberBuffer = 100
+	shutdownTimeout  = 5 * time.Second
+)
+
+//Update is the JSON representation of a deployment.ProcessUpdate
+type Update struct {
+	Event     deployment.ProcessEvent      `json:"event"`
+	Phase     deployment.InstallationPhase `json:"phase"`
+	Error     string                       `json:"error,omitempty"`
+	Component *Component                   `json:"component,omitempty"`
+	Time      time.Time                    `json:"time"`
+}
+
+//Component is the status of a component
+type Component struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Status    components.Status `json:"status"`
+	Error     string            `json:"error,omitempty"`
+}
+
+//Status is the current status of the process
+type Status struct {
+	Event      deployment.ProcessEvent      `json:"event,omitempty"`
+	Phase      deployment.InstallationPhase `json:"phase,omitempty"`
+	Error      string                       `json:"error,omitempty"`
+	Components []Component                  `json:"components"`
+	Updated    time.Time                    `json:"updated"`
+	Done       bool                         `json:"done"`
+}
+
+//Server keeps the status of the process and broadcasts its updates to the connected clients.
+//It is safe for concurrent use.
+type Server struct {
+	mu          sync.Mutex
+	status      Status
+	components  map[string]int
+	subscribers map[chan Update]bool
+	//closed by Finish, ends the event streams
+	done chan struct{}
+}
+
+//NewServer creates a server without updates
+func NewServer() *Server {
+	return &Server{
+		status:      Status{Components: []Component{}},
+		components:  make(map[string]int),
+		subscribers: make(map[chan Update]bool),
+		done:        make(chan struct{}),
+	}
+}
+
+//Updater returns a process update callback for deployment.NewDeployment or deployment.NewDeletion which publishes the updates.
+//Every update is passed on to next, if it is not nil, for example to keep the default logging of the updates.
+func (s *Server) Updater(next func(deployment.ProcessUpdate)) func(deployment.ProcessUpdate) {
+	return func(update deployment.ProcessUpdate) {
+		s.Publish(update)
+		if next != nil {
+			next(update)
+		}
+	}
+}
+
+//Publish updates the status and sends the update to all connected clients.
+//Clients which do not keep up lose updates instead of blocking the process.
+func (s *Server) Publish(processUpdate deployment.ProcessUpdate) {
+	update := newUpdate(processUpdate, time.Now())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apply(update)
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- update:
+		default:
+		}
+	}
+}
+
+//Finish marks the process as finished with the result of StartKymaDeployment or StartKymaUninstallation.
+//The event streams send the final status as a finished or failed event and end.
+//Later calls are ignored.
+func (s *Server) Finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.Done {
+		return
+	}
+	s.status.Done = true
+	s.status.Updated = time.Now()
+	//errors of intermediate updates do not fail a process which succeeded
+	s.status.Error = ""
+	if err != nil {
+		s.status.Error = err.Error()
+	}
+	close(s.done)
+}
+
+//Status returns the current status
+func (s *Server) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	status.Components = append([]Component{}, s.status.Components...)
+	return status
+}
+
+//Handler returns the HTTP handler of the status and events endpoints
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(statusPath, s.serveStatus)
+	mux.HandleFunc(eventsPath, s.serveEvents)
+	return mux
+}
+
+//ListenAndServe serves the endpoints on the given address until the context is cancelled
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	httpServer := &http.Server{Addr: addr, Handler: s.Handler()}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return httpServer.Shutdown(shutdownCtx)
+	}
+}
+
+func (s *Server) apply(update Update) {
+	s.status.Event = update.Event
+	s.status.Phase = update.Phase
+	s.status.Error = update.Error
+	s.status.Updated = update.Time
+	if update.Component == nil {
+		return
+	}
+	if index, ok := s.components[update.Component.Name]; ok {
+		s.status.Components[index] = *update.Component
+		return
+	}
+	s.components[update.Component.Name] = len(s.status.Components)
+	s.status.Components = append(s.status.Components, *update.Component)
+}
+
+func (s *Server) subscribe() chan Update {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subscriber := make(chan Update, subscriberBuffer)
+	s.subscribers[subscriber] = true
+	return subscriber
+}
+
+func (s *Server) unsubscribe(subscriber chan Update) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscribers, subscriber)
+}
+
+func (s *Server) serveStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.Status()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+//serveEvents streams the updates as server-sent events until the process finished or the client disconnects
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	subscriber := s.subscribe()
+	defer s.unsubscribe(subscriber)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case update := <-subscriber:
+			if err := writeEvent(w, eventName, update); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-s.done:
+			s.finishEvents(w, subscriber)
+			flusher.Flush()
+			return
+		}
+	}
+}
+
+//finishEvents writes the updates which are still buffered and the final status as finished or failed event
+func (s *Server) finishEvents(w http.ResponseWriter, subscriber chan Update) {
+	for {
+		select {
+		case update := <-subscriber:
+			if err := writeEvent(w, eventName, update); err != nil {
+				return
+			}
+		default:
+			status := s.Status()
+			name := finishedName
+			if status.Error != "" {
+				name = failedName
+			}
+			_ = writeEvent(w, name, status)
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, name string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+	return err
+}
+
+func newUpdate(processUpdate deployment.ProcessUpdate, now time.Time) Update {
+	update := Update{
+		Event: processUpdate.Event,
+		Phase: processUpdate.Phase,
+		Time:  now,
+	}
+	if processUpdate.Error != nil {
+		update.Error = processUpdate.Error.Error()
+	}
+	if processUpdate.IsComponentUpdate() {
+		comp := processUpdate.Component
+		update.Component = &Component{
+			Name:      comp.Name,
+			Namespace: comp.Namespace,
+			Status:    comp.Status,
+		}
+		if comp.Error != nil {
+			update.Component.Error = comp.Error.Error()
+		}
+	}
+	return update
+}
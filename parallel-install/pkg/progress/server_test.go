@@ -0,0 +1,152 @@
+package progress
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/components"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/deployment"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+
+	t.Run("Status contains the latest update of every component", func(t *testing.T) {
+		server := NewServer()
+		var forwarded int
+		updater := server.Updater(func(deployment.ProcessUpdate) { forwarded++ })
+
+		updater(deployment.ProcessUpdate{Event: deployment.ProcessStart, Phase: deployment.InstallComponents})
+		updater(deployment.ProcessUpdate{Event: deployment.ProcessRunning, Phase: deployment.InstallComponents,
+			Component: components.KymaComponent{Name: "istio", Namespace: "istio-system", Status: components.StatusError, Error: errors.New("timeout")}})
+		updater(deployment.ProcessUpdate{Event: deployment.ProcessRunning, Phase: deployment.InstallComponents,
+			Component: components.KymaComponent{Name: "istio", Namespace: "istio-system", Status: components.StatusInstalled}})
+		updater(deployment.ProcessUpdate{Event: deployment.ProcessRunning, Phase: deployment.InstallComponents,
+			Component: components.KymaComponent{Name: "dex", Namespace: "kyma-system", Status: components.StatusInstalled}})
+		require.Equal(t, 4, forwarded)
+
+		httpServer := httptest.NewServer(server.Handler())
+		defer httpServer.Close()
+
+		resp, err := http.Get(httpServer.URL + statusPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var status Status
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+		require.Equal(t, deployment.ProcessRunning, status.Event)
+		require.Equal(t, deployment.InstallComponents, status.Phase)
+		require.Equal(t, []Component{
+			{Name: "istio", Namespace: "istio-system", Status: components.StatusInstalled},
+			{Name: "dex", Namespace: "kyma-system", Status: components.StatusInstalled},
+		}, status.Components)
+	})
+
+	t.Run("Updates are streamed as server-sent events", func(t *testing.T) {
+		server := NewServer()
+		httpServer := httptest.NewServer(server.Handler())
+		defer httpServer.Close()
+
+		resp, err := http.Get(httpServer.URL + eventsPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		server.Publish(deployment.ProcessUpdate{Event: deployment.ProcessExecutionFailure, Phase: deployment.InstallComponents,
+			Error:     errors.New("1 component failed"),
+			Component: components.KymaComponent{Name: "istio", Status: components.StatusError, Error: errors.New("timeout")}})
+
+		reader := bufio.NewReader(resp.Body)
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		require.Equal(t, "event: update\n", line)
+		line, err = reader.ReadString('\n')
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(line, "data: "))
+
+		var update Update
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &update))
+		require.Equal(t, deployment.ProcessExecutionFailure, update.Event)
+		require.Equal(t, "1 component failed", update.Error)
+		require.Equal(t, &Component{Name: "istio", Status: components.StatusError, Error: "timeout"}, update.Component)
+	})
+
+	t.Run("Streams end with the final status", func(t *testing.T) {
+		readEvent := func(reader *bufio.Reader) (string, Status) {
+			name, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			data, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			_, err = reader.ReadString('\n')
+			require.NoError(t, err)
+			var status Status
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &status))
+			return strings.TrimSpace(strings.TrimPrefix(name, "event: ")), status
+		}
+
+		server := NewServer()
+		httpServer := httptest.NewServer(server.Handler())
+		defer httpServer.Close()
+
+		resp, err := http.Get(httpServer.URL + eventsPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		server.Publish(deployment.ProcessUpdate{Event: deployment.ProcessFinished, Phase: deployment.InstallComponents})
+		server.Finish(nil)
+
+		reader := bufio.NewReader(resp.Body)
+		name, _ := readEvent(reader)
+		require.Equal(t, "update", name)
+		name, status := readEvent(reader)
+		require.Equal(t, "finished", name)
+		require.True(t, status.Done)
+		require.Empty(t, status.Error)
+		_, err = reader.ReadString('\n')
+		require.Equal(t, io.EOF, err, "The response should be closed")
+
+		//clients which connect later get the final status right away
+		server.Finish(errors.New("ignored"))
+		resp, err = http.Get(httpServer.URL + eventsPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		name, status = readEvent(bufio.NewReader(resp.Body))
+		require.Equal(t, "finished", name)
+		require.True(t, status.Done)
+	})
+
+	t.Run("Failed process ends the streams with a failed event", func(t *testing.T) {
+		server := NewServer()
+		httpServer := httptest.NewServer(server.Handler())
+		defer httpServer.Close()
+
+		server.Finish(errors.New("maintenance window missed"))
+		resp, err := http.Get(httpServer.URL + eventsPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(body), "event: failed\n"))
+		require.Contains(t, string(body), `"error":"maintenance window missed"`)
+		require.Contains(t, string(body), `"done":true`)
+	})
+
+	t.Run("Only GET is allowed", func(t *testing.T) {
+		httpServer := httptest.NewServer(NewServer().Handler())
+		defer httpServer.Close()
+
+		resp, err := http.Post(httpServer.URL+statusPath, "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
@@ -0,0 +1,199 @@
+//Package retry defines how failed Kubernetes operations are retried.
+//
+//A Policy is configured once in the installation config and used by all Kubernetes operations
+//of the deployment and deletion, so retry behavior can be tuned consistently.
+package retry
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	retrygo "github.com/avast/retry-go"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+)
+
+//Policy decides whether and when a failed operation is retried
+type Policy interface {
+	//Do calls the operation until it succeeds, its error is not retryable, or the policy gives up.
+	//The last error is returned.
+	Do(operation func() error) error
+}
+
+//Backoff is a Policy with exponentially growing and optionally jittered delays.
+//If neither Attempts nor MaxElapsedTime is set, the operation is called only once.
+type Backoff struct {
+	//Delay before the first retry
+	InitialDelay time.Duration
+	//Upper bound of the delay. Unbounded if zero.
+	MaxDelay time.Duration
+	//Factor the delay grows by after each retry. Values <= 1 result in a fixed delay.
+	Multiplier float64
+	//Fraction of the delay which is randomized, between 0 and 1
+	Jitter float64
+	//Maximum number of calls of the operation. Unlimited if zero.
+	Attempts uint
+	//Time after which no further retry is started. Unlimited if zero.
+	MaxElapsedTime time.Duration
+	//Retryable classifies errors. Defaults to IsRetryable.
+	Retryable func(error) bool
+
+	//sleep is replaced in tests
+	sleep func(time.Duration)
+}
+
+//Fixed returns a policy which retries with a constant delay
+func Fixed(delay time.Duration, attempts uint) *Backoff {
+	return &Backoff{
+		InitialDelay: delay,
+		Multiplier:   1,
+		Attempts:     attempts,
+	}
+}
+
+//Exponential returns a policy which doubles the delay after each retry up to maxDelay, with 20% jitter
+func Exponential(initialDelay, maxDelay time.Duration, attempts uint) *Backoff {
+	return &Backoff{
+		InitialDelay: initialDelay,
+		MaxDelay:     maxDelay,
+		Multiplier:   2,
+		Jitter:       0.2,
+		Attempts:     attempts,
+	}
+}
+
+//Default returns the policy used if none is configured: three attempts with a fixed delay of two seconds
+func Default() Policy {
+	return Fixed(2*time.Second, 3)
+}
+
+//Do implements Policy.Do
+func (b *Backoff) Do(operation func() error) error {
+	retryable := b.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	sleep := b.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	start := time.Now()
+	delay := b.InitialDelay
+	for attempt := uint(1); ; attempt++ {
+		err := operation()
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if !retryable(err) || !b.retryAllowed(attempt, start, delay) {
+			return err
+		}
+
+		sleep(b.jittered(delay))
+		delay = b.next(delay)
+	}
+}
+
+func (b *Backoff) retryAllowed(attempt uint, start time.Time, delay time.Duration) bool {
+	if b.Attempts == 0 && b.MaxElapsedTime == 0 {
+		return false
+	}
+	if b.Attempts > 0 && attempt >= b.Attempts {
+		return false
+	}
+	if b.MaxElapsedTime > 0 && time.Since(start)+delay > b.MaxElapsedTime {
+		return false
+	}
+	return true
+}
+
+func (b *Backoff) jittered(delay time.Duration) time.Duration {
+	if b.Jitter <= 0 || delay <= 0 {
+		return delay
+	}
+	jitter := b.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	//randomize within [delay - jitter*delay, delay + jitter*delay]
+	spread := float64(delay) * jitter
+	return time.Duration(float64(delay) - spread + rand.Float64()*2*spread)
+}
+
+func (b *Backoff) next(delay time.Duration) time.Duration {
+	if b.Multiplier > 1 {
+		delay = time.Duration(float64(delay) * b.Multiplier)
+	}
+	if b.MaxDelay > 0 && delay > b.MaxDelay {
+		delay = b.MaxDelay
+	}
+	return delay
+}
+
+//FromOptions returns a policy which retries with the options of github.com/avast/retry-go
+func FromOptions(opts ...retrygo.Option) Policy {
+	return optionsPolicy(opts)
+}
+
+type optionsPolicy []retrygo.Option
+
+func (o optionsPolicy) Do(operation func() error) error {
+	var permanentErr error
+	opts := append([]retrygo.Option{
+		retrygo.RetryIf(func(err error) bool {
+			return IsRetryable(err)
+		}),
+		retrygo.LastErrorOnly(true),
+	}, o...)
+	err := retrygo.Do(func() error {
+		err := operation()
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			permanentErr = permanent.err
+			return nil
+		}
+		return err
+	}, opts...)
+	if permanentErr != nil {
+		return permanentErr
+	}
+	return err
+}
+
+type permanentError struct {
+	err error
+}
+
+func (p *permanentError) Error() string {
+	return p.err.Error()
+}
+
+func (p *permanentError) Unwrap() error {
+	return p.err
+}
+
+//Permanent marks an error as not retryable. The policy returns the wrapped error immediately.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+//IsRetryable returns false for errors which do not disappear by retrying, such as invalid or unauthorized requests
+func IsRetryable(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	switch {
+	case apierr.IsBadRequest(err), apierr.IsInvalid(err), apierr.IsForbidden(err), apierr.IsUnauthorized(err),
+		apierr.IsMethodNotSupported(err), apierr.IsNotAcceptable(err), apierr.IsUnsupportedMediaType(err):
+		return false
+	}
+	return true
+}
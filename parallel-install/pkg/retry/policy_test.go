@@ -0,0 +1,131 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	retrygo "github.com/avast/retry-go"
+	"github.com/stretchr/testify/require"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestBackoff_Do(t *testing.T) {
+
+	t.Run("Fixed delay is retried until attempts are exhausted", func(t *testing.T) {
+		var delays []time.Duration
+		policy := Fixed(time.Second, 3)
+		policy.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+		calls := 0
+		err := policy.Do(func() error {
+			calls++
+			return errors.New("failure")
+		})
+		require.EqualError(t, err, "failure")
+		require.Equal(t, 3, calls)
+		require.Equal(t, []time.Duration{time.Second, time.Second}, delays)
+	})
+
+	t.Run("Exponential delay grows up to the maximum", func(t *testing.T) {
+		var delays []time.Duration
+		policy := Exponential(time.Second, 5*time.Second, 5)
+		policy.Jitter = 0
+		policy.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+		_ = policy.Do(func() error {
+			return errors.New("failure")
+		})
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}, delays)
+	})
+
+	t.Run("Jitter stays within bounds", func(t *testing.T) {
+		policy := Exponential(time.Second, 0, 20)
+		policy.sleep = func(d time.Duration) {}
+		for i := 0; i < 100; i++ {
+			d := policy.jittered(time.Second)
+			require.True(t, d >= 800*time.Millisecond && d <= 1200*time.Millisecond, "delay %s out of bounds", d)
+		}
+	})
+
+	t.Run("Succeeds after retry", func(t *testing.T) {
+		policy := Fixed(time.Second, 3)
+		policy.sleep = func(d time.Duration) {}
+
+		calls := 0
+		err := policy.Do(func() error {
+			calls++
+			if calls < 2 {
+				return errors.New("failure")
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("Without limits the operation is called once", func(t *testing.T) {
+		policy := &Backoff{InitialDelay: time.Second}
+		calls := 0
+		err := policy.Do(func() error {
+			calls++
+			return errors.New("failure")
+		})
+		require.Error(t, err)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Permanent errors are not retried", func(t *testing.T) {
+		policy := Fixed(time.Second, 3)
+		policy.sleep = func(d time.Duration) {}
+
+		calls := 0
+		err := policy.Do(func() error {
+			calls++
+			return Permanent(errors.New("permanent"))
+		})
+		require.EqualError(t, err, "permanent")
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Invalid requests are not retried", func(t *testing.T) {
+		policy := Fixed(time.Second, 3)
+		policy.sleep = func(d time.Duration) {}
+
+		calls := 0
+		err := policy.Do(func() error {
+			calls++
+			return apierr.NewForbidden(schema.GroupResource{Resource: "secrets"}, "test", errors.New("forbidden"))
+		})
+		require.True(t, apierr.IsForbidden(err))
+		require.Equal(t, 1, calls)
+	})
+}
+
+func TestFromOptions(t *testing.T) {
+
+	t.Run("Retries with the given options", func(t *testing.T) {
+		policy := FromOptions(retrygo.Attempts(3), retrygo.Delay(time.Millisecond))
+
+		calls := 0
+		err := policy.Do(func() error {
+			calls++
+			return errors.New("failure")
+		})
+		require.EqualError(t, err, "failure")
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("Permanent errors are not retried", func(t *testing.T) {
+		policy := FromOptions(retrygo.Attempts(3), retrygo.Delay(time.Millisecond))
+
+		calls := 0
+		err := policy.Do(func() error {
+			calls++
+			return Permanent(errors.New("permanent"))
+		})
+		require.EqualError(t, err, "permanent")
+		require.Equal(t, 1, calls)
+	})
+}
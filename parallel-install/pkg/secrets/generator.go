@@ -0,0 +1,381 @@
+//Package secrets generates credentials required by Kyma components and stores them in Kubernetes secrets.
+//
+//Generated values are stored idempotently: if the secret already exists, its values are reused,
+//so passwords and certificates stay stable across upgrades.
+package secrets
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+//Kind of generated secret
+type Kind string
+
+const (
+	//Password generates a random alphanumeric password stored under the key 'password'
+	Password Kind = "password"
+	//KeyPair generates an RSA key pair stored under the keys 'private.key' and 'public.key'
+	KeyPair Kind = "keypair"
+	//Certificate generates a self-signed ECDSA certificate stored under the keys 'tls.crt' and 'tls.key'
+	Certificate Kind = "certificate"
+)
+
+const (
+	//PasswordKey is the data key of generated passwords
+	PasswordKey = "password"
+	//PrivateKeyKey is the data key of the PEM encoded private key of generated key pairs
+	PrivateKeyKey = "private.key"
+	//PublicKeyKey is the data key of the PEM encoded public key of generated key pairs
+	PublicKeyKey = "public.key"
+
+	defaultNamespace      = "kyma-installer"
+	defaultPasswordLength = 32
+	defaultKeyBits        = 2048
+	defaultValidity       = 365 * 24 * time.Hour
+	generatedByLabel      = "kyma-project.io/generated-by"
+	generatedByValue      = "parallel-install"
+	passwordCharset       = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	passwordSymbols       = "!#%+-.:=?@^_~"
+	//maxPasswordAttempts bounds the regeneration of passwords which miss a character class required by the password policy
+	maxPasswordAttempts = 100
+)
+
+//Spec declares a secret to generate
+type Spec struct {
+	//Name of the secret
+	Name string
+	//Namespace of the secret. Defaults to kyma-installer.
+	Namespace string
+	Kind      Kind
+	//Length of passwords. Defaults to 32.
+	Length int
+	//Size of RSA keys. Defaults to 2048, or 3072 in FIPS mode.
+	Bits int
+	//Common name of certificates
+	CommonName string
+	//Subject alternative names of certificates
+	DNSNames []string
+	//Validity of certificates. Defaults to one year.
+	Validity time.Duration
+	//Overrides maps data keys of the secret to the override keys the values are exposed as, e.g. "password" to "global.foo.password"
+	Overrides map[string]string
+	//Base64 encodes the override values
+	Base64 bool
+}
+
+//Validate verifies the spec
+func (s *Spec) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("secret name is required")
+	}
+	keys := s.Kind.keys()
+	if keys == nil {
+		return fmt.Errorf("unknown kind '%s' of secret '%s'", s.Kind, s.Name)
+	}
+	if s.Kind == Certificate && s.CommonName == "" && len(s.DNSNames) == 0 {
+		return fmt.Errorf("certificate secret '%s' requires a common name or DNS names", s.Name)
+	}
+	if s.Length < 0 || s.Bits < 0 || s.Validity < 0 {
+		return fmt.Errorf("length, bits and validity of secret '%s' cannot be negative", s.Name)
+	}
+	for dataKey := range s.Overrides {
+		if !contains(keys, dataKey) {
+			return fmt.Errorf("secret '%s' of kind '%s' has no key '%s', available keys: %v", s.Name, s.Kind, dataKey, keys)
+		}
+	}
+	return nil
+}
+
+//NamespacedName returns the secret in the format namespace/name, with the default namespace if the spec sets none
+func (s *Spec) NamespacedName() string {
+	namespace := s.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return fmt.Sprintf("%s/%s", namespace, s.Name)
+}
+
+func (k Kind) keys() []string {
+	switch k {
+	case Password:
+		return []string{PasswordKey}
+	case KeyPair:
+		return []string{PrivateKeyKey, PublicKeyKey}
+	case Certificate:
+		return []string{v1.TLSCertKey, v1.TLSPrivateKeyKey}
+	}
+	return nil
+}
+
+//Generator creates the declared secrets
+type Generator struct {
+	kubeClient kubernetes.Interface
+	log        logger.Interface
+	policy     *config.PasswordPolicy
+	fips       bool
+}
+
+//NewGenerator creates a new Generator instance
+func NewGenerator(kubeClient kubernetes.Interface, log logger.Interface) *Generator {
+	return &Generator{
+		kubeClient: kubeClient,
+		log:        log,
+	}
+}
+
+//WithPasswordPolicy makes the generated passwords comply with the policy. Existing secrets are not checked.
+func (g *Generator) WithPasswordPolicy(policy *config.PasswordPolicy) *Generator {
+	g.policy = policy
+	return g
+}
+
+//WithFIPS generates RSA keys of 3072 bits by default and rejects smaller keys than 2048 bits.
+//The keys and certificates of existing secrets are checked, as they are reused.
+func (g *Generator) WithFIPS(fips bool) *Generator {
+	g.fips = fips
+	return g
+}
+
+//Generate returns the data of the secret. The secret is created if it does not exist,
+//otherwise the stored values are returned. Missing keys of an existing secret are an error, as they cannot be regenerated consistently.
+func (g *Generator) Generate(spec Spec) (map[string][]byte, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	namespace := spec.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if g.fips && spec.Kind == KeyPair {
+		if spec.Bits == 0 {
+			spec.Bits = config.FIPSDefaultRSABits
+		}
+		if spec.Bits < config.FIPSMinRSABits {
+			return nil, &installerrors.ErrNotFIPSCompliant{
+				Source: spec.NamespacedName(),
+				Reason: fmt.Sprintf("RSA key of %d bits, at least %d bits are required", spec.Bits, config.FIPSMinRSABits),
+			}
+		}
+	}
+
+	secret, err := g.kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	if err == nil {
+		for _, key := range spec.Kind.keys() {
+			if _, ok := secret.Data[key]; !ok {
+				return nil, fmt.Errorf("existing secret '%s/%s' has no key '%s'", namespace, spec.Name, key)
+			}
+		}
+		if g.fips {
+			if err := checkFIPS(spec, secret.Data); err != nil {
+				return nil, err
+			}
+		}
+		g.log.Infof("Reusing secret '%s/%s'", namespace, spec.Name)
+		return secret.Data, nil
+	}
+	if !apierr.IsNotFound(err) {
+		return nil, err
+	}
+
+	data, err := generate(spec, g.policy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to generate secret '%s/%s'", namespace, spec.Name)
+	}
+	secret = &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      spec.Name,
+			Namespace: namespace,
+			Labels:    map[string]string{generatedByLabel: generatedByValue},
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: data,
+	}
+	if spec.Kind == Certificate {
+		secret.Type = v1.SecretTypeTLS
+	}
+
+	if err := g.ensureNamespace(namespace); err != nil {
+		return nil, err
+	}
+	created, err := g.kubeClient.CoreV1().Secrets(namespace).Create(context.Background(), secret, metav1.CreateOptions{})
+	if apierr.IsAlreadyExists(err) {
+		//created concurrently, use the stored values
+		created, err = g.kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), spec.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	g.log.Infof("Generated secret '%s/%s'", namespace, spec.Name)
+	return created.Data, nil
+}
+
+func (g *Generator) ensureNamespace(namespace string) error {
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+	_, err := g.kubeClient.CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{})
+	if err != nil && !apierr.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+//checkFIPS verifies that the key or certificate of an existing secret uses approved parameters
+func checkFIPS(spec Spec, data map[string][]byte) error {
+	switch spec.Kind {
+	case KeyPair:
+		block, _ := pem.Decode(data[PublicKeyKey])
+		if block == nil {
+			return fmt.Errorf("existing secret '%s' contains no PEM encoded public key", spec.NamespacedName())
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to parse the public key of secret '%s'", spec.NamespacedName())
+		}
+		return config.CheckFIPSKey(spec.NamespacedName(), key)
+	case Certificate:
+		return config.CheckFIPSCertificate(spec.NamespacedName(), data[v1.TLSCertKey])
+	}
+	return nil
+}
+
+func generate(spec Spec, policy *config.PasswordPolicy) (map[string][]byte, error) {
+	switch spec.Kind {
+	case Password:
+		length := spec.Length
+		if length == 0 {
+			length = defaultPasswordLength
+		}
+		password, err := policyPassword(length, policy)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]byte{PasswordKey: password}, nil
+	case KeyPair:
+		bits := spec.Bits
+		if bits == 0 {
+			bits = defaultKeyBits
+		}
+		return rsaKeyPair(bits)
+	default:
+		validity := spec.Validity
+		if validity == 0 {
+			validity = defaultValidity
+		}
+		return selfSignedCertificate(spec.CommonName, spec.DNSNames, validity)
+	}
+}
+
+//policyPassword generates a random password which complies with the policy. It is at least as long as the policy requires,
+//and contains symbols if the policy requires all character classes.
+func policyPassword(length int, policy *config.PasswordPolicy) ([]byte, error) {
+	if policy == nil {
+		return randomPassword(length, passwordCharset)
+	}
+	if length < policy.MinLength {
+		length = policy.MinLength
+	}
+	charset := passwordCharset
+	if policy.MinCharacterClasses > 3 {
+		charset += passwordSymbols
+	}
+	for attempt := 0; attempt < maxPasswordAttempts; attempt++ {
+		password, err := randomPassword(length, charset)
+		if err != nil {
+			return nil, err
+		}
+		if len(policy.Violations(string(password))) == 0 {
+			return password, nil
+		}
+	}
+	return nil, fmt.Errorf("no password of length %d complies with the password policy", length)
+}
+
+func randomPassword(length int, charset string) ([]byte, error) {
+	max := big.NewInt(int64(len(charset)))
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return nil, err
+		}
+		password[i] = charset[n.Int64()]
+	}
+	return password, nil
+}
+
+func rsaKeyPair(bits int) (map[string][]byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		PrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		PublicKeyKey:  pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}),
+	}, nil
+}
+
+func selfSignedCertificate(commonName string, dnsNames []string, validity time.Duration) (map[string][]byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              dnsNames,
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	crt, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		v1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: crt}),
+		v1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}),
+	}, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
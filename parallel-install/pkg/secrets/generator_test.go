@@ -0,0 +1,148 @@
+package secrets
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	installerrors "github.com/kyma-incubator/hydroform/parallel-install/pkg/errors"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/logger"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+
+	t.Run("Password is generated once", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		generator := NewGenerator(kubeClient, logger.NewLogger(true))
+		spec := Spec{Name: "db", Kind: Password, Length: 16}
+
+		data, err := generator.Generate(spec)
+		require.NoError(t, err)
+		require.Len(t, data[PasswordKey], 16)
+
+		again, err := generator.Generate(spec)
+		require.NoError(t, err)
+		require.Equal(t, data, again)
+
+		secret, err := kubeClient.CoreV1().Secrets(defaultNamespace).Get(context.Background(), "db", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, generatedByValue, secret.Labels[generatedByLabel])
+	})
+
+	t.Run("Password complies with the password policy", func(t *testing.T) {
+		policy := &config.PasswordPolicy{MinLength: 40, MinCharacterClasses: 4}
+		generator := NewGenerator(fake.NewSimpleClientset(), logger.NewLogger(true)).WithPasswordPolicy(policy)
+
+		data, err := generator.Generate(Spec{Name: "db", Kind: Password, Length: 16})
+		require.NoError(t, err)
+		require.Len(t, data[PasswordKey], 40)
+		require.Empty(t, policy.Violations(string(data[PasswordKey])))
+	})
+
+	t.Run("Spec reference", func(t *testing.T) {
+		require.Equal(t, "kyma-installer/db", (&Spec{Name: "db"}).NamespacedName())
+		require.Equal(t, "kyma-system/db", (&Spec{Name: "db", Namespace: "kyma-system"}).NamespacedName())
+	})
+
+	t.Run("Key pair", func(t *testing.T) {
+		generator := NewGenerator(fake.NewSimpleClientset(), logger.NewLogger(true))
+
+		data, err := generator.Generate(Spec{Name: "signing", Namespace: "kyma-system", Kind: KeyPair, Bits: 1024})
+		require.NoError(t, err)
+		block, _ := pem.Decode(data[PrivateKeyKey])
+		require.NotNil(t, block)
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		require.NoError(t, err)
+		require.Equal(t, 1024, key.N.BitLen())
+		require.NotEmpty(t, data[PublicKeyKey])
+	})
+
+	t.Run("Key pair in FIPS mode", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		generator := NewGenerator(kubeClient, logger.NewLogger(true)).WithFIPS(true)
+
+		_, err := generator.Generate(Spec{Name: "weak", Kind: KeyPair, Bits: 1024})
+		var notCompliant *installerrors.ErrNotFIPSCompliant
+		require.True(t, errors.As(err, &notCompliant))
+		require.Equal(t, "kyma-installer/weak", notCompliant.Source)
+
+		data, err := generator.Generate(Spec{Name: "signing", Kind: KeyPair})
+		require.NoError(t, err)
+		block, _ := pem.Decode(data[PrivateKeyKey])
+		require.NotNil(t, block)
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		require.NoError(t, err)
+		require.Equal(t, config.FIPSDefaultRSABits, key.N.BitLen())
+
+		_, err = generator.Generate(Spec{Name: "signing", Kind: KeyPair})
+		require.NoError(t, err)
+	})
+
+	t.Run("Existing weak key pair in FIPS mode", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		_, err := NewGenerator(kubeClient, logger.NewLogger(true)).Generate(Spec{Name: "signing", Kind: KeyPair, Bits: 1024})
+		require.NoError(t, err)
+
+		_, err = NewGenerator(kubeClient, logger.NewLogger(true)).WithFIPS(true).Generate(Spec{Name: "signing", Kind: KeyPair, Bits: 2048})
+		var notCompliant *installerrors.ErrNotFIPSCompliant
+		require.True(t, errors.As(err, &notCompliant))
+	})
+
+	t.Run("Self-signed certificate", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		generator := NewGenerator(kubeClient, logger.NewLogger(true))
+
+		data, err := generator.Generate(Spec{Name: "webhook-cert", Kind: Certificate, DNSNames: []string{"webhook.kyma-system.svc"}})
+		require.NoError(t, err)
+		pair, err := tls.X509KeyPair(data[v1.TLSCertKey], data[v1.TLSPrivateKeyKey])
+		require.NoError(t, err)
+		crt, err := x509.ParseCertificate(pair.Certificate[0])
+		require.NoError(t, err)
+		require.NoError(t, crt.VerifyHostname("webhook.kyma-system.svc"))
+
+		secret, err := kubeClient.CoreV1().Secrets(defaultNamespace).Get(context.Background(), "webhook-cert", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, v1.SecretTypeTLS, secret.Type)
+	})
+
+	t.Run("Self-signed certificate in FIPS mode", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		generator := NewGenerator(kubeClient, logger.NewLogger(true)).WithFIPS(true)
+		spec := Spec{Name: "webhook-cert", Kind: Certificate, DNSNames: []string{"webhook.kyma-system.svc"}}
+
+		data, err := generator.Generate(spec)
+		require.NoError(t, err)
+		require.NoError(t, config.CheckFIPSCertificate("webhook-cert", data[v1.TLSCertKey]))
+
+		_, err = generator.Generate(spec)
+		require.NoError(t, err)
+	})
+
+	t.Run("Existing secret with missing key", func(t *testing.T) {
+		existing := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: defaultNamespace},
+			Data:       map[string][]byte{"user": []byte("admin")},
+		}
+		generator := NewGenerator(fake.NewSimpleClientset(existing), logger.NewLogger(true))
+
+		_, err := generator.Generate(Spec{Name: "db", Kind: Password})
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid spec", func(t *testing.T) {
+		generator := NewGenerator(fake.NewSimpleClientset(), logger.NewLogger(true))
+
+		_, err := generator.Generate(Spec{Name: "cert", Kind: Certificate})
+		require.Error(t, err)
+		_, err = generator.Generate(Spec{Name: "foo", Kind: "token"})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,126 @@
+//Package simulation provides a fake Helm client which simulates component operations.
+//
+//The client does not touch any cluster. Each operation lasts the configured latency and fails as configured,
+//so the orchestration behavior (timeouts, cancellation, progress) can be tested deterministically.
+package simulation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+)
+
+const defaultFailureMessage = "simulated failure"
+
+//Call records a simulated operation
+type Call struct {
+	//Operation is deploy, uninstall, or rollback
+	Operation string
+	Name      string
+	Namespace string
+	Overrides map[string]interface{}
+	Start     time.Time
+	End       time.Time
+	Error     error
+}
+
+//Client simulates the operations of a Helm client
+type Client struct {
+	cfg      config.SimulationConfig
+	mu       sync.Mutex
+	attempts map[string]int
+	calls    []Call
+}
+
+//NewClient creates a new Client instance
+func NewClient(cfg config.SimulationConfig) *Client {
+	return &Client{
+		cfg:      cfg,
+		attempts: make(map[string]int),
+	}
+}
+
+//DeployRelease implements helm.ClientInterface.DeployRelease
+func (c *Client) DeployRelease(ctx context.Context, chartDir, namespace, name string, overrides map[string]interface{}, profile string) error {
+	return c.simulate(ctx, config.SimulateDeploy, namespace, name, overrides)
+}
+
+//UninstallRelease implements helm.ClientInterface.UninstallRelease
+func (c *Client) UninstallRelease(ctx context.Context, namespace, name string) error {
+	return c.simulate(ctx, config.SimulateUninstall, namespace, name, nil)
+}
+
+//RollbackRelease implements helm.Rollbacker.RollbackRelease
+func (c *Client) RollbackRelease(ctx context.Context, namespace, name string) error {
+	return c.simulate(ctx, config.SimulateRollback, namespace, name, nil)
+}
+
+//Calls returns the simulated operations in the order they completed
+func (c *Client) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	calls := make([]Call, len(c.calls))
+	copy(calls, c.calls)
+	return calls
+}
+
+func (c *Client) simulate(ctx context.Context, operation, namespace, name string, overrides map[string]interface{}) error {
+	call := Call{
+		Operation: operation,
+		Name:      name,
+		Namespace: namespace,
+		Overrides: overrides,
+		Start:     time.Now(),
+	}
+
+	latency := c.cfg.DefaultLatency
+	if l, ok := c.cfg.Latencies[name]; ok {
+		latency = l
+	}
+
+	if c.cfg.BlockOnCancel {
+		time.Sleep(latency)
+	} else {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			call.Error = ctx.Err()
+		}
+	}
+
+	if call.Error == nil {
+		call.Error = c.failure(operation, name)
+	}
+	call.End = time.Now()
+
+	c.mu.Lock()
+	c.calls = append(c.calls, call)
+	c.mu.Unlock()
+	return call.Error
+}
+
+//failure returns the injected error of the operation, if any
+func (c *Client) failure(operation, name string) error {
+	failure, ok := c.cfg.Failures[name]
+	if !ok || (failure.Operation != "" && failure.Operation != operation) {
+		return nil
+	}
+
+	c.mu.Lock()
+	key := operation + "/" + name
+	c.attempts[key]++
+	attempt := c.attempts[key]
+	c.mu.Unlock()
+
+	if failure.Attempts > 0 && attempt > failure.Attempts {
+		return nil
+	}
+	message := failure.Message
+	if message == "" {
+		message = defaultFailureMessage
+	}
+	return errors.New(message)
+}
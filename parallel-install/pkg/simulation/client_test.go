@@ -0,0 +1,68 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+
+	t.Run("Latency per component", func(t *testing.T) {
+		client := NewClient(config.SimulationConfig{
+			DefaultLatency: 10 * time.Millisecond,
+			Latencies:      map[string]time.Duration{"slow": 50 * time.Millisecond},
+		})
+
+		require.NoError(t, client.DeployRelease(context.Background(), "", "kyma-system", "fast", nil, ""))
+		require.NoError(t, client.DeployRelease(context.Background(), "", "kyma-system", "slow", nil, ""))
+
+		calls := client.Calls()
+		require.Len(t, calls, 2)
+		require.Equal(t, "fast", calls[0].Name)
+		require.True(t, calls[1].End.Sub(calls[1].Start) >= 50*time.Millisecond)
+	})
+
+	t.Run("Failure for the first attempts", func(t *testing.T) {
+		client := NewClient(config.SimulationConfig{
+			Failures: map[string]config.SimulatedFailure{"flaky": {Message: "boom", Attempts: 2}},
+		})
+
+		err := client.DeployRelease(context.Background(), "", "kyma-system", "flaky", nil, "")
+		require.EqualError(t, err, "boom")
+		require.Error(t, client.DeployRelease(context.Background(), "", "kyma-system", "flaky", nil, ""))
+		require.NoError(t, client.DeployRelease(context.Background(), "", "kyma-system", "flaky", nil, ""))
+	})
+
+	t.Run("Failure restricted to uninstallation", func(t *testing.T) {
+		client := NewClient(config.SimulationConfig{
+			Failures: map[string]config.SimulatedFailure{"stuck": {Operation: config.SimulateUninstall}},
+		})
+
+		require.NoError(t, client.DeployRelease(context.Background(), "", "kyma-system", "stuck", nil, ""))
+		require.EqualError(t, client.UninstallRelease(context.Background(), "kyma-system", "stuck"), defaultFailureMessage)
+	})
+
+	t.Run("Cancellation", func(t *testing.T) {
+		client := NewClient(config.SimulationConfig{DefaultLatency: time.Minute})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := client.DeployRelease(ctx, "", "kyma-system", "comp", nil, "")
+		require.Equal(t, context.DeadlineExceeded, err)
+	})
+
+	t.Run("Blocking on cancellation", func(t *testing.T) {
+		client := NewClient(config.SimulationConfig{DefaultLatency: 50 * time.Millisecond, BlockOnCancel: true})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		start := time.Now()
+		err := client.DeployRelease(ctx, "", "kyma-system", "comp", nil, "")
+		require.NoError(t, err)
+		require.True(t, time.Since(start) >= 50*time.Millisecond)
+	})
+}
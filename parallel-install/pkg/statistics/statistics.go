@@ -0,0 +1,213 @@
+//Package statistics persists the statistics of deployment and uninstallation runs
+//and compares runs, so that regressions, such as components which became slower with a new chart version, show up.
+package statistics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const idFormat = "20060102T150405.000Z"
+
+//Run contains the statistics of one deployment or uninstallation
+type Run struct {
+	ID         string           `json:"id"`
+	Operation  string           `json:"operation"`
+	Version    string           `json:"version"`
+	Started    time.Time        `json:"started"`
+	Finished   time.Time        `json:"finished"`
+	Error      string           `json:"error,omitempty"`
+	Components []ComponentStats `json:"components"`
+}
+
+//ComponentStats contains the statistics of a component within a run
+type ComponentStats struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Phase     string        `json:"phase"`
+	Duration  time.Duration `json:"duration"`
+	Retries   int           `json:"retries"`
+	Failed    bool          `json:"failed"`
+	Error     string        `json:"error,omitempty"`
+}
+
+//NewRun starts the statistics of a run. The ID is derived from the start time, so that IDs sort chronologically.
+func NewRun(operation, version string, started time.Time) *Run {
+	return &Run{
+		ID:        fmt.Sprintf("%s-%s", started.UTC().Format(idFormat), operation),
+		Operation: operation,
+		Version:   version,
+		Started:   started,
+	}
+}
+
+//Record adds the statistics of a processed component
+func (r *Run) Record(stats ComponentStats) {
+	r.Components = append(r.Components, stats)
+}
+
+//Finish marks the run as finished
+func (r *Run) Finish(finished time.Time, err error) {
+	r.Finished = finished
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+//Duration returns the total duration of the run
+func (r *Run) Duration() time.Duration {
+	return r.Finished.Sub(r.Started)
+}
+
+//Component returns the statistics of a component
+func (r *Run) Component(name string) (ComponentStats, bool) {
+	for _, stats := range r.Components {
+		if stats.Name == name {
+			return stats, true
+		}
+	}
+	return ComponentStats{}, false
+}
+
+//Failures returns the names of the failed components
+func (r *Run) Failures() []string {
+	var failures []string
+	for _, stats := range r.Components {
+		if stats.Failed {
+			failures = append(failures, stats.Name)
+		}
+	}
+	return failures
+}
+
+func (r *Run) String() string {
+	return fmt.Sprintf("%s: %d components in %s, %d failed", r.ID, len(r.Components), r.Duration(), len(r.Failures()))
+}
+
+//ComponentChange compares the statistics of a component in two runs
+type ComponentChange struct {
+	Component    string
+	BaseDuration time.Duration
+	Duration     time.Duration
+	BaseRetries  int
+	Retries      int
+	BaseFailed   bool
+	Failed       bool
+}
+
+//Factor returns how many times slower (> 1) or faster (< 1) the component got. It returns 0 if the base duration is unknown.
+func (c ComponentChange) Factor() float64 {
+	if c.BaseDuration <= 0 {
+		return 0
+	}
+	return float64(c.Duration) / float64(c.BaseDuration)
+}
+
+func (c ComponentChange) String() string {
+	var changes []string
+	if c.Failed && !c.BaseFailed {
+		changes = append(changes, "failed")
+	}
+	if factor := c.Factor(); factor > 1 {
+		changes = append(changes, fmt.Sprintf("got %.1fx slower (%s -> %s)", factor, c.BaseDuration, c.Duration))
+	} else if factor > 0 && factor < 1 {
+		changes = append(changes, fmt.Sprintf("got %.1fx faster (%s -> %s)", 1/factor, c.BaseDuration, c.Duration))
+	}
+	if c.Retries != c.BaseRetries {
+		changes = append(changes, fmt.Sprintf("retries %d -> %d", c.BaseRetries, c.Retries))
+	}
+	if len(changes) == 0 {
+		changes = append(changes, "unchanged")
+	}
+	return fmt.Sprintf("component %s %s", c.Component, strings.Join(changes, ", "))
+}
+
+//Comparison compares two runs
+type Comparison struct {
+	Base    *Run
+	Current *Run
+	//Changes of the components processed in both runs, in the order of the current run
+	Changes []ComponentChange
+	//Added lists the components which were only processed in the current run
+	Added []string
+	//Removed lists the components which were only processed in the base run
+	Removed []string
+}
+
+//Compare compares the statistics of the current run against a base run
+func Compare(base, current *Run) *Comparison {
+	comparison := &Comparison{Base: base, Current: current}
+	for _, stats := range current.Components {
+		baseStats, ok := base.Component(stats.Name)
+		if !ok {
+			comparison.Added = append(comparison.Added, stats.Name)
+			continue
+		}
+		comparison.Changes = append(comparison.Changes, ComponentChange{
+			Component:    stats.Name,
+			BaseDuration: baseStats.Duration,
+			Duration:     stats.Duration,
+			BaseRetries:  baseStats.Retries,
+			Retries:      stats.Retries,
+			BaseFailed:   baseStats.Failed,
+			Failed:       stats.Failed,
+		})
+	}
+	for _, stats := range base.Components {
+		if _, ok := current.Component(stats.Name); !ok {
+			comparison.Removed = append(comparison.Removed, stats.Name)
+		}
+	}
+	return comparison
+}
+
+//Regressions returns the components which got at least threshold times slower, failed only in the current run, or needed more retries.
+//The slowest regressions come first.
+func (c *Comparison) Regressions(threshold float64) []ComponentChange {
+	var regressions []ComponentChange
+	for _, change := range c.Changes {
+		if change.Factor() >= threshold || (change.Failed && !change.BaseFailed) || change.Retries > change.BaseRetries {
+			regressions = append(regressions, change)
+		}
+	}
+	sort.SliceStable(regressions, func(i, j int) bool {
+		return regressions[i].Factor() > regressions[j].Factor()
+	})
+	return regressions
+}
+
+//Store persists the statistics of runs
+type Store interface {
+	//Save stores a run
+	Save(run *Run) error
+	//Load returns the run with the given ID
+	Load(id string) (*Run, error)
+	//List returns the IDs of the stored runs, oldest first
+	List() ([]string, error)
+}
+
+//CompareLatest compares the latest run of an operation with the run of the same operation before it.
+//It returns nil if fewer than two runs of the operation are stored.
+func CompareLatest(store Store, operation string) (*Comparison, error) {
+	ids, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	var runs []*Run
+	for i := len(ids) - 1; i >= 0 && len(runs) < 2; i-- {
+		if !strings.HasSuffix(ids[i], "-"+operation) {
+			continue
+		}
+		run, err := store.Load(ids[i])
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	if len(runs) < 2 {
+		return nil, nil
+	}
+	return Compare(runs[1], runs[0]), nil
+}
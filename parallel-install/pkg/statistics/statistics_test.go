@@ -0,0 +1,91 @@
+package statistics
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRun(started time.Time, components ...ComponentStats) *Run {
+	run := NewRun("deploy", "2.0.0", started)
+	for _, stats := range components {
+		run.Record(stats)
+	}
+	run.Finish(started.Add(10*time.Minute), nil)
+	return run
+}
+
+func Test_Run(t *testing.T) {
+	started := time.Date(2021, 3, 15, 18, 0, 0, 0, time.UTC)
+	run := newTestRun(started,
+		ComponentStats{Name: "istio", Duration: time.Minute},
+		ComponentStats{Name: "serverless", Duration: time.Minute, Failed: true, Error: "timeout"},
+	)
+	run.Finish(started.Add(5*time.Minute), errors.New("failed"))
+
+	require.Equal(t, "20210315T180000.000Z-deploy", run.ID)
+	require.Equal(t, 5*time.Minute, run.Duration())
+	require.Equal(t, "failed", run.Error)
+	require.Equal(t, []string{"serverless"}, run.Failures())
+	stats, ok := run.Component("istio")
+	require.True(t, ok)
+	require.Equal(t, time.Minute, stats.Duration)
+	_, ok = run.Component("eventing")
+	require.False(t, ok)
+}
+
+func Test_Compare(t *testing.T) {
+	started := time.Date(2021, 3, 15, 18, 0, 0, 0, time.UTC)
+	base := newTestRun(started,
+		ComponentStats{Name: "istio", Duration: time.Minute},
+		ComponentStats{Name: "serverless", Duration: 2 * time.Minute},
+		ComponentStats{Name: "eventing", Duration: time.Minute},
+		ComponentStats{Name: "monitoring", Duration: time.Minute},
+	)
+	current := newTestRun(started.Add(time.Hour),
+		ComponentStats{Name: "istio", Duration: 3 * time.Minute},
+		ComponentStats{Name: "serverless", Duration: time.Minute},
+		ComponentStats{Name: "eventing", Duration: time.Minute, Retries: 2},
+		ComponentStats{Name: "logging", Duration: time.Minute, Failed: true},
+	)
+
+	comparison := Compare(base, current)
+	require.Equal(t, []string{"logging"}, comparison.Added)
+	require.Equal(t, []string{"monitoring"}, comparison.Removed)
+	require.Len(t, comparison.Changes, 3)
+	require.Equal(t, "component istio got 3.0x slower (1m0s -> 3m0s)", comparison.Changes[0].String())
+	require.Equal(t, "component serverless got 2.0x faster (2m0s -> 1m0s)", comparison.Changes[1].String())
+	require.Equal(t, "component eventing retries 0 -> 2", comparison.Changes[2].String())
+
+	regressions := comparison.Regressions(1.5)
+	require.Len(t, regressions, 2)
+	require.Equal(t, "istio", regressions[0].Component)
+	require.Equal(t, "eventing", regressions[1].Component)
+}
+
+func Test_CompareLatest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "statistics")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store := NewFileStore(dir)
+
+	started := time.Date(2021, 3, 15, 18, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Save(newTestRun(started, ComponentStats{Name: "istio", Duration: time.Minute})))
+
+	comparison, err := CompareLatest(store, "deploy")
+	require.NoError(t, err)
+	require.Nil(t, comparison)
+
+	uninstall := NewRun("uninstall", "2.0.0", started.Add(time.Hour))
+	require.NoError(t, store.Save(uninstall))
+	require.NoError(t, store.Save(newTestRun(started.Add(2*time.Hour), ComponentStats{Name: "istio", Duration: 2 * time.Minute})))
+
+	comparison, err = CompareLatest(store, "deploy")
+	require.NoError(t, err)
+	require.Equal(t, started, comparison.Base.Started.UTC())
+	require.Equal(t, 2.0, comparison.Changes[0].Factor())
+}
@@ -0,0 +1,176 @@
+package statistics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	fileExtension  = ".json"
+	defaultMaxRuns = 20
+)
+
+//FileStore stores every run as JSON file in a local directory
+type FileStore struct {
+	dir string
+}
+
+//NewFileStore creates a store for the given directory. The directory is created when the first run is saved.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+//Save implements Store.Save
+func (s *FileStore) Save(run *Run) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(s.dir, run.ID+fileExtension), data, 0600)
+}
+
+//Load implements Store.Load
+func (s *FileStore) Load(id string) (*Run, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, id+fileExtension))
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalRun(id, data)
+}
+
+//List implements Store.List
+func (s *FileStore) List() ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), fileExtension) {
+			ids = append(ids, strings.TrimSuffix(file.Name(), fileExtension))
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+//ConfigMapStore stores the runs in a ConfigMap in the cluster, one key per run.
+//Only the latest runs are kept, as the size of a ConfigMap is limited.
+type ConfigMapStore struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	name       string
+	maxRuns    int
+}
+
+//NewConfigMapStore creates a store which keeps up to maxRuns runs in the given ConfigMap. maxRuns defaults to 20.
+func NewConfigMapStore(kubeClient kubernetes.Interface, namespace, name string, maxRuns int) *ConfigMapStore {
+	if maxRuns <= 0 {
+		maxRuns = defaultMaxRuns
+	}
+	return &ConfigMapStore{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		name:       name,
+		maxRuns:    maxRuns,
+	}
+}
+
+//Save implements Store.Save. The namespace and the ConfigMap are created if they do not exist.
+func (s *ConfigMapStore) Save(run *Run) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	cm, err := s.get()
+	if apierr.IsNotFound(err) {
+		ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: s.namespace}}
+		if _, err := s.kubeClient.CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{}); err != nil && !apierr.IsAlreadyExists(err) {
+			return err
+		}
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: s.name, Namespace: s.namespace},
+			Data:       map[string]string{run.ID: string(data)},
+		}
+		_, err = s.kubeClient.CoreV1().ConfigMaps(s.namespace).Create(context.Background(), cm, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[run.ID] = string(data)
+	ids := sortedKeys(cm.Data)
+	for len(ids) > s.maxRuns {
+		delete(cm.Data, ids[0])
+		ids = ids[1:]
+	}
+	_, err = s.kubeClient.CoreV1().ConfigMaps(s.namespace).Update(context.Background(), cm, metav1.UpdateOptions{})
+	return err
+}
+
+//Load implements Store.Load
+func (s *ConfigMapStore) Load(id string) (*Run, error) {
+	cm, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	data, ok := cm.Data[id]
+	if !ok {
+		return nil, fmt.Errorf("Statistics of run '%s' not found in ConfigMap '%s/%s'", id, s.namespace, s.name)
+	}
+	return unmarshalRun(id, []byte(data))
+}
+
+//List implements Store.List
+func (s *ConfigMapStore) List() ([]string, error) {
+	cm, err := s.get()
+	if apierr.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sortedKeys(cm.Data), nil
+}
+
+func (s *ConfigMapStore) get() (*v1.ConfigMap, error) {
+	return s.kubeClient.CoreV1().ConfigMaps(s.namespace).Get(context.Background(), s.name, metav1.GetOptions{})
+}
+
+func unmarshalRun(id string, data []byte) (*Run, error) {
+	run := &Run{}
+	if err := json.Unmarshal(data, run); err != nil {
+		return nil, fmt.Errorf("Failed to read statistics of run '%s': %v", id, err)
+	}
+	return run, nil
+}
+
+func sortedKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
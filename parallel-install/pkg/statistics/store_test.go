@@ -0,0 +1,74 @@
+package statistics
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_FileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "statistics")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store := NewFileStore(fmt.Sprintf("%s/runs", dir))
+
+	ids, err := store.List()
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	started := time.Date(2021, 3, 15, 18, 0, 0, 0, time.UTC)
+	second := newTestRun(started.Add(time.Hour), ComponentStats{Name: "istio", Duration: time.Minute, Retries: 1})
+	first := newTestRun(started)
+	require.NoError(t, store.Save(second))
+	require.NoError(t, store.Save(first))
+
+	ids, err = store.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{first.ID, second.ID}, ids)
+
+	loaded, err := store.Load(second.ID)
+	require.NoError(t, err)
+	require.Equal(t, second.Components, loaded.Components)
+
+	_, err = store.Load("unknown")
+	require.Error(t, err)
+}
+
+func Test_ConfigMapStore(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	store := NewConfigMapStore(kubeClient, "kyma-installer", "kyma-installation-statistics", 2)
+
+	ids, err := store.List()
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	started := time.Date(2021, 3, 15, 18, 0, 0, 0, time.UTC)
+	var runs []*Run
+	for i := 0; i < 3; i++ {
+		run := newTestRun(started.Add(time.Duration(i)*time.Hour), ComponentStats{Name: "istio", Duration: time.Duration(i+1) * time.Minute})
+		require.NoError(t, store.Save(run))
+		runs = append(runs, run)
+	}
+
+	//only the latest runs are kept
+	ids, err = store.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{runs[1].ID, runs[2].ID}, ids)
+
+	loaded, err := store.Load(runs[2].ID)
+	require.NoError(t, err)
+	require.Equal(t, runs[2].Components, loaded.Components)
+
+	_, err = store.Load(runs[0].ID)
+	require.Error(t, err)
+
+	_, err = kubeClient.CoreV1().Namespaces().Get(context.Background(), "kyma-installer", metav1.GetOptions{})
+	require.NoError(t, err)
+}
@@ -0,0 +1,156 @@
+package transform
+
+import (
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+//HighAvailability scales the Deployments of the selected components, spreads their replicas,
+//and adds a PodDisruptionBudget for each of them
+type HighAvailability struct {
+	cfg config.HighAvailabilityConfig
+}
+
+//Transform implements helm.Transformation. Deployments which are scaled to zero are disabled and kept as they are.
+func (h *HighAvailability) Transform(component string, obj map[string]interface{}) error {
+	if !h.selects(component, obj) {
+		return nil
+	}
+	spec := obj["spec"].(map[string]interface{})
+	if replicas(spec) < int64(h.cfg.MinReplicas()) {
+		spec["replicas"] = int64(h.cfg.MinReplicas())
+	}
+
+	template, _ := podTemplate(obj)
+	podSpec, ok := template["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	affinity, _ := podSpec["affinity"].(map[string]interface{})
+	//the anti-affinity of the chart is kept, as it knows the workload better
+	if _, ok := affinity["podAntiAffinity"]; ok {
+		return nil
+	}
+	metadata, _ := template["metadata"].(map[string]interface{})
+	podLabels, _ := metadata["labels"].(map[string]interface{})
+	if len(podLabels) == 0 {
+		return nil
+	}
+	term := map[string]interface{}{
+		"labelSelector": map[string]interface{}{"matchLabels": podLabels},
+		"topologyKey":   h.cfg.SpreadTopologyKey(),
+	}
+	affinity = nestedMap(podSpec, "affinity")
+	if h.cfg.RequiredAntiAffinity {
+		affinity["podAntiAffinity"] = map[string]interface{}{
+			"requiredDuringSchedulingIgnoredDuringExecution": []interface{}{term},
+		}
+	} else {
+		affinity["podAntiAffinity"] = map[string]interface{}{
+			"preferredDuringSchedulingIgnoredDuringExecution": []interface{}{
+				map[string]interface{}{"weight": int64(100), "podAffinityTerm": term},
+			},
+		}
+	}
+	return nil
+}
+
+//Expand implements helm.Expansion. It adds a PodDisruptionBudget for each highly available Deployment,
+//unless a PodDisruptionBudget of the chart selects its Pods already, as the eviction of Pods with several budgets fails.
+func (h *HighAvailability) Expand(component string, resources []map[string]interface{}) ([]map[string]interface{}, error) {
+	var budgets []labels.Selector
+	for _, obj := range resources {
+		if obj["kind"] != "PodDisruptionBudget" {
+			continue
+		}
+		spec, _ := obj["spec"].(map[string]interface{})
+		selector, err := labelSelector(spec["selector"])
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, selector)
+	}
+
+	var added []map[string]interface{}
+	for _, obj := range resources {
+		if !h.selects(component, obj) {
+			continue
+		}
+		template, _ := podTemplate(obj)
+		metadata, _ := template["metadata"].(map[string]interface{})
+		podLabels := make(labels.Set)
+		if values, ok := metadata["labels"].(map[string]interface{}); ok {
+			for key, value := range values {
+				podLabels[key], _ = value.(string)
+			}
+		}
+		if covered(budgets, podLabels) {
+			continue
+		}
+		spec := obj["spec"].(map[string]interface{})
+		if _, ok := spec["selector"]; !ok {
+			continue
+		}
+		deploymentMetadata, _ := obj["metadata"].(map[string]interface{})
+		budgetMetadata := map[string]interface{}{"name": deploymentMetadata["name"]}
+		if namespace, ok := deploymentMetadata["namespace"]; ok {
+			budgetMetadata["namespace"] = namespace
+		}
+		added = append(added, map[string]interface{}{
+			"apiVersion": "policy/v1beta1",
+			"kind":       "PodDisruptionBudget",
+			"metadata":   budgetMetadata,
+			"spec": map[string]interface{}{
+				"maxUnavailable": int64(1),
+				"selector":       spec["selector"],
+			},
+		})
+	}
+	return added, nil
+}
+
+//selects returns whether the resource is a Deployment of a selected component which is not scaled to zero
+func (h *HighAvailability) selects(component string, obj map[string]interface{}) bool {
+	if obj["kind"] != "Deployment" || !h.cfg.Selects(component) {
+		return false
+	}
+	spec, ok := obj["spec"].(map[string]interface{})
+	return ok && replicas(spec) != 0
+}
+
+//replicas returns the replicas of a Deployment, which defaults to 1
+func replicas(spec map[string]interface{}) int64 {
+	switch value := spec["replicas"].(type) {
+	case float64:
+		return int64(value)
+	case int64:
+		return value
+	default:
+		return 1
+	}
+}
+
+//labelSelector converts the selector of a rendered resource to a selector which can be matched
+func labelSelector(value interface{}) (labels.Selector, error) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return labels.Nothing(), nil
+	}
+	var selector metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &selector); err != nil {
+		return nil, err
+	}
+	return metav1.LabelSelectorAsSelector(&selector)
+}
+
+//covered returns whether one of the selectors matches the labels
+func covered(selectors []labels.Selector, podLabels labels.Set) bool {
+	for _, selector := range selectors {
+		if selector.Matches(podLabels) {
+			return true
+		}
+	}
+	return false
+}
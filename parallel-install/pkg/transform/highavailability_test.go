@@ -0,0 +1,115 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+const gatewayDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api-gateway
+  namespace: kyma-system
+spec:
+  selector:
+    matchLabels:
+      app: api-gateway
+  template:
+    metadata:
+      labels:
+        app: api-gateway
+    spec:
+      containers:
+      - name: api-gateway
+`
+
+func Test_HighAvailability(t *testing.T) {
+	parse := func(t *testing.T, manifest string) map[string]interface{} {
+		var obj map[string]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(manifest), &obj))
+		return obj
+	}
+
+	t.Run("Scale and spread a Deployment", func(t *testing.T) {
+		ha := &HighAvailability{cfg: config.HighAvailabilityConfig{Replicas: 3}}
+		obj := parse(t, gatewayDeployment)
+		require.NoError(t, ha.Transform("api-gateway", obj))
+
+		require.Equal(t, int64(3), obj["spec"].(map[string]interface{})["replicas"])
+		spec, _ := podSpec(obj)
+		require.Equal(t, map[string]interface{}{
+			"podAntiAffinity": map[string]interface{}{
+				"preferredDuringSchedulingIgnoredDuringExecution": []interface{}{
+					map[string]interface{}{
+						"weight": int64(100),
+						"podAffinityTerm": map[string]interface{}{
+							"labelSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "api-gateway"}},
+							"topologyKey":   "kubernetes.io/hostname",
+						},
+					},
+				},
+			},
+		}, spec["affinity"])
+	})
+
+	t.Run("Require anti-affinity", func(t *testing.T) {
+		ha := &HighAvailability{cfg: config.HighAvailabilityConfig{RequiredAntiAffinity: true, TopologyKey: "topology.kubernetes.io/zone"}}
+		obj := parse(t, gatewayDeployment)
+		require.NoError(t, ha.Transform("api-gateway", obj))
+
+		require.Equal(t, int64(2), obj["spec"].(map[string]interface{})["replicas"])
+		spec, _ := podSpec(obj)
+		antiAffinity := spec["affinity"].(map[string]interface{})["podAntiAffinity"].(map[string]interface{})
+		require.Contains(t, antiAffinity, "requiredDuringSchedulingIgnoredDuringExecution")
+	})
+
+	t.Run("Keep Deployments scaled to zero and other components", func(t *testing.T) {
+		ha := &HighAvailability{cfg: config.HighAvailabilityConfig{}}
+		obj := parse(t, gatewayDeployment+"  replicas: 0\n")
+		require.NoError(t, ha.Transform("api-gateway", obj))
+		require.Equal(t, float64(0), obj["spec"].(map[string]interface{})["replicas"])
+
+		obj = parse(t, gatewayDeployment)
+		require.NoError(t, ha.Transform("monitoring", obj))
+		require.NotContains(t, obj["spec"], "replicas")
+	})
+
+	t.Run("Add PodDisruptionBudgets", func(t *testing.T) {
+		ha := &HighAvailability{cfg: config.HighAvailabilityConfig{}}
+		added, err := ha.Expand("api-gateway", []map[string]interface{}{parse(t, gatewayDeployment)})
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{
+			"apiVersion": "policy/v1beta1",
+			"kind":       "PodDisruptionBudget",
+			"metadata":   map[string]interface{}{"name": "api-gateway", "namespace": "kyma-system"},
+			"spec": map[string]interface{}{
+				"maxUnavailable": int64(1),
+				"selector":       map[string]interface{}{"matchLabels": map[string]interface{}{"app": "api-gateway"}},
+			},
+		}}, added)
+	})
+
+	t.Run("Keep the PodDisruptionBudgets of the chart", func(t *testing.T) {
+		ha := &HighAvailability{cfg: config.HighAvailabilityConfig{}}
+		budget := parse(t, `
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: api-gateway
+spec:
+  minAvailable: 1
+  selector:
+    matchExpressions:
+    - key: app
+      operator: In
+      values: [api-gateway]
+`)
+		added, err := ha.Expand("api-gateway", []map[string]interface{}{parse(t, gatewayDeployment), budget})
+		require.NoError(t, err)
+		require.Empty(t, added)
+	})
+}
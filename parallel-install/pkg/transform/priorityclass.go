@@ -0,0 +1,27 @@
+package transform
+
+import (
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+)
+
+//PriorityClass assigns the priority class of the component to its workloads
+type PriorityClass struct {
+	cfg config.PriorityClassConfig
+}
+
+//Transform implements helm.Transformation. Workloads whose charts set a priority class keep it,
+//as the charts assign classes such as system-node-critical deliberately.
+func (p *PriorityClass) Transform(component string, obj map[string]interface{}) error {
+	name := p.cfg.ClassOf(component)
+	if name == "" {
+		return nil
+	}
+	spec, ok := podSpec(obj)
+	if !ok {
+		return nil
+	}
+	if current, _ := spec["priorityClassName"].(string); current == "" {
+		spec["priorityClassName"] = name
+	}
+	return nil
+}
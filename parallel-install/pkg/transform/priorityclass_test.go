@@ -0,0 +1,42 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PriorityClass(t *testing.T) {
+	transformation := &PriorityClass{cfg: config.PriorityClassConfig{
+		Default:    "kyma-system",
+		Components: map[string]string{"serverless": ""},
+	}}
+	newDaemonSet := func(spec map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"kind": "DaemonSet",
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": spec}},
+		}
+	}
+
+	t.Run("Assign the class", func(t *testing.T) {
+		obj := newDaemonSet(map[string]interface{}{})
+		require.NoError(t, transformation.Transform("logging", obj))
+		spec, _ := podSpec(obj)
+		require.Equal(t, "kyma-system", spec["priorityClassName"])
+	})
+
+	t.Run("Keep the class of the chart", func(t *testing.T) {
+		obj := newDaemonSet(map[string]interface{}{"priorityClassName": "system-node-critical"})
+		require.NoError(t, transformation.Transform("logging", obj))
+		spec, _ := podSpec(obj)
+		require.Equal(t, "system-node-critical", spec["priorityClassName"])
+	})
+
+	t.Run("Skip components without class", func(t *testing.T) {
+		obj := newDaemonSet(map[string]interface{}{})
+		require.NoError(t, transformation.Transform("serverless", obj))
+		spec, _ := podSpec(obj)
+		require.NotContains(t, spec, "priorityClassName")
+	})
+}
@@ -0,0 +1,106 @@
+package transform
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+//ResourceScaling adjusts the resource requests and limits of the containers of the workloads of the selected components
+type ResourceScaling struct {
+	cfg config.ResourceScaling
+}
+
+//Transform implements helm.Transformation
+func (s *ResourceScaling) Transform(component string, obj map[string]interface{}) error {
+	if !s.cfg.Selects(component) {
+		return nil
+	}
+	spec, ok := podSpec(obj)
+	if !ok {
+		return nil
+	}
+	for _, container := range containers(spec) {
+		resources := nestedMap(container, "resources")
+		requests := nestedMap(resources, "requests")
+		limits := nestedMap(resources, "limits")
+
+		for _, quantities := range []map[string]interface{}{requests, limits} {
+			if err := s.scale(quantities); err != nil {
+				return fmt.Errorf("Failed to scale the resources of container '%v': %v", container["name"], err)
+			}
+		}
+		for name, value := range s.cfg.Requests {
+			requests[name] = value
+		}
+		for name, value := range s.cfg.Limits {
+			limits[name] = value
+		}
+		if err := capRequests(requests, limits); err != nil {
+			return fmt.Errorf("Failed to adjust the resources of container '%v': %v", container["name"], err)
+		}
+
+		if len(requests) == 0 {
+			delete(resources, "requests")
+		}
+		if len(limits) == 0 {
+			delete(resources, "limits")
+		}
+		if len(resources) == 0 {
+			delete(container, "resources")
+		}
+	}
+	return nil
+}
+
+//scale multiplies the quantities with the factor
+func (s *ResourceScaling) scale(quantities map[string]interface{}) error {
+	if s.cfg.Factor == 0 {
+		return nil
+	}
+	for name, value := range quantities {
+		q, err := parseQuantity(value)
+		if err != nil {
+			return err
+		}
+		scaled := resource.NewMilliQuantity(int64(float64(q.MilliValue())*s.cfg.Factor), q.Format)
+		quantities[name] = scaled.String()
+	}
+	return nil
+}
+
+//capRequests lowers the requests which exceed their limits, as the API server rejects such containers
+func capRequests(requests, limits map[string]interface{}) error {
+	for name, value := range requests {
+		limitValue, ok := limits[name]
+		if !ok {
+			continue
+		}
+		request, err := parseQuantity(value)
+		if err != nil {
+			return err
+		}
+		limit, err := parseQuantity(limitValue)
+		if err != nil {
+			return err
+		}
+		if request.Cmp(limit) > 0 {
+			requests[name] = limit.String()
+		}
+	}
+	return nil
+}
+
+//parseQuantity parses a quantity of a rendered manifest, which YAML decodes as a number if it has no unit
+func parseQuantity(value interface{}) (resource.Quantity, error) {
+	switch v := value.(type) {
+	case string:
+		return resource.ParseQuantity(v)
+	case float64:
+		return resource.ParseQuantity(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return resource.Quantity{}, fmt.Errorf("Invalid quantity '%v'", value)
+	}
+}
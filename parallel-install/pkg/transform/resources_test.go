@@ -0,0 +1,103 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+const deployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: prometheus
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+      containers:
+      - name: prometheus
+        resources:
+          requests:
+            cpu: 500m
+            memory: 1Gi
+          limits:
+            cpu: 1
+            memory: 2Gi
+`
+
+func Test_ResourceScaling(t *testing.T) {
+	transform := func(t *testing.T, cfg config.ResourceScaling, manifest string) map[string]interface{} {
+		var obj map[string]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(manifest), &obj))
+		require.NoError(t, (&ResourceScaling{cfg: cfg}).Transform("monitoring", obj))
+		return obj
+	}
+	resources := func(obj map[string]interface{}, container int) interface{} {
+		spec, _ := podSpec(obj)
+		return containers(spec)[container]["resources"]
+	}
+
+	t.Run("Scale by factor", func(t *testing.T) {
+		obj := transform(t, config.ResourceScaling{Factor: 0.5}, deployment)
+		require.Nil(t, resources(obj, 0))
+		require.Equal(t, map[string]interface{}{
+			"requests": map[string]interface{}{"cpu": "250m", "memory": "512Mi"},
+			"limits":   map[string]interface{}{"cpu": "500m", "memory": "1Gi"},
+		}, resources(obj, 1))
+	})
+
+	t.Run("Set absolute values", func(t *testing.T) {
+		obj := transform(t, config.ResourceScaling{
+			Requests: map[string]string{"memory": "64Mi"},
+			Limits:   map[string]string{"cpu": "100m"},
+		}, deployment)
+		require.Equal(t, map[string]interface{}{
+			"requests": map[string]interface{}{"memory": "64Mi"},
+			"limits":   map[string]interface{}{"cpu": "100m"},
+		}, resources(obj, 0))
+		require.Equal(t, map[string]interface{}{
+			"requests": map[string]interface{}{"cpu": "100m", "memory": "64Mi"},
+			"limits":   map[string]interface{}{"cpu": "100m", "memory": "2Gi"},
+		}, resources(obj, 1))
+	})
+
+	t.Run("Skip other components", func(t *testing.T) {
+		obj := transform(t, config.ResourceScaling{Components: []string{"istio"}, Factor: 0.5}, deployment)
+		require.Equal(t, "500m", resources(obj, 1).(map[string]interface{})["requests"].(map[string]interface{})["cpu"])
+	})
+
+	t.Run("Skip resources without Pods", func(t *testing.T) {
+		obj := transform(t, config.ResourceScaling{Factor: 0.5}, "kind: ConfigMap\ndata:\n  cpu: 1\n")
+		require.Equal(t, map[string]interface{}{"kind": "ConfigMap", "data": map[string]interface{}{"cpu": float64(1)}}, obj)
+	})
+
+	t.Run("CronJob", func(t *testing.T) {
+		manifest := `
+kind: CronJob
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: backup
+            resources:
+              requests:
+                memory: 100Mi
+`
+		obj := transform(t, config.ResourceScaling{Factor: 2}, manifest)
+		require.Equal(t, map[string]interface{}{
+			"requests": map[string]interface{}{"memory": "200Mi"},
+		}, resources(obj, 0))
+	})
+
+	t.Run("ForConfig", func(t *testing.T) {
+		cfg := &config.Config{ResourceScaling: []config.ResourceScaling{{Factor: 0.5}, {Limits: map[string]string{"cpu": "1"}}}}
+		require.Len(t, ForConfig(cfg), 2)
+		require.Empty(t, ForConfig(&config.Config{}))
+	})
+}
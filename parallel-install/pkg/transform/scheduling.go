@@ -0,0 +1,108 @@
+package transform
+
+import (
+	"reflect"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+)
+
+//SchedulingConstraints injects the node selector, the tolerations, and the topology spread constraints into the workloads
+type SchedulingConstraints struct {
+	cfg config.SchedulingConstraints
+}
+
+//Transform implements helm.Transformation
+func (s *SchedulingConstraints) Transform(component string, obj map[string]interface{}) error {
+	if !s.cfg.Selects(component) {
+		return nil
+	}
+	template, ok := podTemplate(obj)
+	if !ok {
+		return nil
+	}
+	spec, ok := template["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	if err := s.addTolerations(spec); err != nil {
+		return err
+	}
+	//DaemonSets run on every node, e.g. to collect logs, so they are not restricted to the node pool
+	if obj["kind"] == "DaemonSet" {
+		return nil
+	}
+	if len(s.cfg.NodeSelector) > 0 {
+		nodeSelector := nestedMap(spec, "nodeSelector")
+		for key, value := range s.cfg.NodeSelector {
+			nodeSelector[key] = value
+		}
+	}
+	return s.addTopologySpreadConstraints(spec, template)
+}
+
+//addTolerations adds the tolerations which the Pods do not have yet
+func (s *SchedulingConstraints) addTolerations(spec map[string]interface{}) error {
+	tolerations, _ := spec["tolerations"].([]interface{})
+	for _, toleration := range s.cfg.Tolerations {
+		value, err := toUnstructured(toleration)
+		if err != nil {
+			return err
+		}
+		if !contains(tolerations, value) {
+			tolerations = append(tolerations, value)
+		}
+	}
+	if len(tolerations) > 0 {
+		spec["tolerations"] = tolerations
+	}
+	return nil
+}
+
+//addTopologySpreadConstraints adds the constraints of the topology keys the Pods are not spread over yet,
+//as the API server rejects two constraints with the same topology key and action
+func (s *SchedulingConstraints) addTopologySpreadConstraints(spec, template map[string]interface{}) error {
+	constraints, _ := spec["topologySpreadConstraints"].([]interface{})
+	for _, constraint := range s.cfg.TopologySpreadConstraints {
+		value, err := toUnstructured(constraint)
+		if err != nil {
+			return err
+		}
+		if constraint.LabelSelector == nil {
+			//without a selector, the Pods would not be counted, so the labels of the template select the Pods of the workload
+			metadata, _ := template["metadata"].(map[string]interface{})
+			labels, _ := metadata["labels"].(map[string]interface{})
+			if len(labels) == 0 {
+				continue
+			}
+			value.(map[string]interface{})["labelSelector"] = map[string]interface{}{"matchLabels": labels}
+		}
+		constraints = appendConstraint(constraints, value)
+	}
+	if len(constraints) > 0 {
+		spec["topologySpreadConstraints"] = constraints
+	}
+	return nil
+}
+
+//appendConstraint appends the constraint unless the list has a constraint with the same topology key and action
+func appendConstraint(constraints []interface{}, constraint interface{}) []interface{} {
+	added := constraint.(map[string]interface{})
+	for _, item := range constraints {
+		existing, ok := item.(map[string]interface{})
+		if ok && existing["topologyKey"] == added["topologyKey"] && existing["whenUnsatisfiable"] == added["whenUnsatisfiable"] {
+			return constraints
+		}
+	}
+	return append(constraints, constraint)
+}
+
+//contains returns whether the list contains the value
+func contains(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
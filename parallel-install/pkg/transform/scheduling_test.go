@@ -0,0 +1,113 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_SchedulingConstraints(t *testing.T) {
+	cfg := config.SchedulingConstraints{
+		NodeSelector: map[string]string{"pool": "infra"},
+		Tolerations: []v1.Toleration{
+			{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "infra", Effect: v1.TaintEffectNoSchedule},
+		},
+		TopologySpreadConstraints: []v1.TopologySpreadConstraint{
+			{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.ScheduleAnyway},
+		},
+		ExcludedComponents: []string{"istio"},
+	}
+	transform := func(t *testing.T, component, manifest string) map[string]interface{} {
+		var obj map[string]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(manifest), &obj))
+		require.NoError(t, (&SchedulingConstraints{cfg: cfg}).Transform(component, obj))
+		return obj
+	}
+	spec := func(obj map[string]interface{}) map[string]interface{} {
+		spec, _ := podSpec(obj)
+		return spec
+	}
+
+	t.Run("Constrain a Deployment", func(t *testing.T) {
+		obj := transform(t, "monitoring", `
+kind: Deployment
+spec:
+  template:
+    metadata:
+      labels:
+        app: grafana
+    spec:
+      nodeSelector:
+        kubernetes.io/os: linux
+      tolerations:
+      - key: dedicated
+        operator: Equal
+        value: infra
+        effect: NoSchedule
+      containers:
+      - name: grafana
+`)
+		require.Equal(t, map[string]interface{}{"kubernetes.io/os": "linux", "pool": "infra"}, spec(obj)["nodeSelector"])
+		require.Len(t, spec(obj)["tolerations"], 1)
+		require.Equal(t, []interface{}{map[string]interface{}{
+			"maxSkew":           float64(1),
+			"topologyKey":       "topology.kubernetes.io/zone",
+			"whenUnsatisfiable": "ScheduleAnyway",
+			"labelSelector":     map[string]interface{}{"matchLabels": map[string]interface{}{"app": "grafana"}},
+		}}, spec(obj)["topologySpreadConstraints"])
+	})
+
+	t.Run("Only tolerate taints in DaemonSets", func(t *testing.T) {
+		obj := transform(t, "logging", `
+kind: DaemonSet
+spec:
+  template:
+    spec:
+      containers:
+      - name: fluent-bit
+`)
+		require.Nil(t, spec(obj)["nodeSelector"])
+		require.Nil(t, spec(obj)["topologySpreadConstraints"])
+		require.Equal(t, []interface{}{map[string]interface{}{
+			"key":      "dedicated",
+			"operator": "Equal",
+			"value":    "infra",
+			"effect":   "NoSchedule",
+		}}, spec(obj)["tolerations"])
+	})
+
+	t.Run("Keep existing topology spread constraints", func(t *testing.T) {
+		obj := transform(t, "monitoring", `
+kind: Pod
+spec:
+  topologySpreadConstraints:
+  - maxSkew: 2
+    topologyKey: topology.kubernetes.io/zone
+    whenUnsatisfiable: ScheduleAnyway
+`)
+		require.Len(t, spec(obj)["topologySpreadConstraints"], 1)
+		require.Equal(t, float64(2), spec(obj)["topologySpreadConstraints"].([]interface{})[0].(map[string]interface{})["maxSkew"])
+	})
+
+	t.Run("Skip excluded components", func(t *testing.T) {
+		obj := transform(t, "istio", "kind: Deployment\nspec:\n  template:\n    spec: {}\n")
+		require.Empty(t, spec(obj))
+	})
+
+	t.Run("Keep the label selector of the config", func(t *testing.T) {
+		selector := &metav1.LabelSelector{MatchLabels: map[string]string{"kyma-project.io/component": "monitoring"}}
+		constraints := &SchedulingConstraints{cfg: config.SchedulingConstraints{
+			TopologySpreadConstraints: []v1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: v1.DoNotSchedule, LabelSelector: selector},
+			},
+		}}
+		obj := map[string]interface{}{"kind": "Pod", "spec": map[string]interface{}{}}
+		require.NoError(t, constraints.Transform("monitoring", obj))
+		require.Equal(t, map[string]interface{}{"matchLabels": map[string]interface{}{"kyma-project.io/component": "monitoring"}},
+			spec(obj)["topologySpreadConstraints"].([]interface{})[0].(map[string]interface{})["labelSelector"])
+	})
+}
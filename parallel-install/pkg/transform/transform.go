@@ -0,0 +1,99 @@
+//Package transform provides the transformations which adjust the rendered resources of the Kyma components
+//to the installation config, such as the resources, the scheduling, the priority, and the availability of their workloads.
+//
+//The transformations are applied by the Helm client after the charts are rendered,
+//so they work for all charts, regardless of the values the charts offer.
+package transform
+
+import (
+	"encoding/json"
+
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/config"
+	"github.com/kyma-incubator/hydroform/parallel-install/pkg/helm"
+)
+
+//ForConfig returns the transformations of the config, in the order they are applied
+func ForConfig(cfg *config.Config) []helm.Transformation {
+	var transformations []helm.Transformation
+	for _, scaling := range cfg.ResourceScaling {
+		transformations = append(transformations, &ResourceScaling{cfg: scaling})
+	}
+	if cfg.SchedulingConstraints != nil {
+		transformations = append(transformations, &SchedulingConstraints{cfg: *cfg.SchedulingConstraints})
+	}
+	if cfg.PriorityClasses != nil {
+		transformations = append(transformations, &PriorityClass{cfg: *cfg.PriorityClasses})
+	}
+	if cfg.HighAvailability != nil {
+		transformations = append(transformations, &HighAvailability{cfg: *cfg.HighAvailability})
+	}
+	return transformations
+}
+
+//podTemplate returns the template of the Pods a workload creates, or the Pod itself
+func podTemplate(resource map[string]interface{}) (map[string]interface{}, bool) {
+	var path []string
+	switch resource["kind"] {
+	case "Pod":
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		path = []string{"spec", "template"}
+	case "CronJob":
+		path = []string{"spec", "jobTemplate", "spec", "template"}
+	default:
+		return nil, false
+	}
+	template := resource
+	for _, field := range path {
+		nested, ok := template[field].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		template = nested
+	}
+	return template, true
+}
+
+//podSpec returns the spec of the Pods a workload creates, or the spec of a Pod
+func podSpec(resource map[string]interface{}) (map[string]interface{}, bool) {
+	template, ok := podTemplate(resource)
+	if !ok {
+		return nil, false
+	}
+	spec, ok := template["spec"].(map[string]interface{})
+	return spec, ok
+}
+
+//containers returns the containers and init containers of a Pod spec
+func containers(spec map[string]interface{}) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, field := range []string{"initContainers", "containers"} {
+		list, _ := spec[field].([]interface{})
+		for _, item := range list {
+			if container, ok := item.(map[string]interface{}); ok {
+				result = append(result, container)
+			}
+		}
+	}
+	return result
+}
+
+//nestedMap returns the map under the key, which is created if it does not exist
+func nestedMap(obj map[string]interface{}, key string) map[string]interface{} {
+	if nested, ok := obj[key].(map[string]interface{}); ok {
+		return nested
+	}
+	nested := make(map[string]interface{})
+	obj[key] = nested
+	return nested
+}
+
+//toUnstructured converts a typed value, such as a toleration, to its representation in a rendered manifest
+func toUnstructured(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
@@ -0,0 +1,128 @@
+//Package watcher waits for conditions on Kubernetes resources by watching them instead of polling.
+//
+//The resources are cached by an informer, which lists them once and then receives their changes.
+//The condition is evaluated on the cached resources right after each change, which reacts immediately
+//and keeps the load of the API server low, even on large clusters.
+package watcher
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+//Condition is evaluated on the cached objects after the initial list and after every change.
+//Waiting stops as soon as it returns true or an error.
+type Condition func(objects []interface{}) (bool, error)
+
+//Until watches the resources of the ListerWatcher until the condition is met, the condition fails, or the timeout is reached.
+//Like the polling functions of the Kubernetes wait package, it returns wait.ErrWaitTimeout on timeout.
+//Failing list and watch requests are retried by the informer until the timeout, e.g. while the API server is not reachable yet.
+func Until(lw cache.ListerWatcher, objType runtime.Object, timeout time.Duration, condition Condition) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+			//a check is already pending, it sees this change as well
+		}
+	}
+	store, controller := cache.NewInformer(lw, objType, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { notify() },
+		UpdateFunc: func(oldObj, newObj interface{}) { notify() },
+		DeleteFunc: func(obj interface{}) { notify() },
+	})
+	go controller.Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), controller.HasSynced) {
+		return wait.ErrWaitTimeout
+	}
+
+	for {
+		done, err := condition(store.List())
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return wait.ErrWaitTimeout
+		}
+	}
+}
+
+//Pods lists and watches the Pods of a namespace
+func Pods(kubeClient kubernetes.Interface, namespace string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.CoreV1().Pods(namespace).List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.CoreV1().Pods(namespace).Watch(context.Background(), options)
+		},
+	}
+}
+
+//Nodes lists and watches the nodes of the cluster
+func Nodes(kubeClient kubernetes.Interface) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.CoreV1().Nodes().List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.CoreV1().Nodes().Watch(context.Background(), options)
+		},
+	}
+}
+
+//Namespace lists and watches a single namespace
+func Namespace(kubeClient kubernetes.Interface, name string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = nameSelector(name)
+			return kubeClient.CoreV1().Namespaces().List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = nameSelector(name)
+			return kubeClient.CoreV1().Namespaces().Watch(context.Background(), options)
+		},
+	}
+}
+
+//Secret lists and watches a single secret
+func Secret(kubeClient kubernetes.Interface, namespace, name string) cache.ListerWatcher {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = nameSelector(name)
+			return kubeClient.CoreV1().Secrets(namespace).List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = nameSelector(name)
+			return kubeClient.CoreV1().Secrets(namespace).Watch(context.Background(), options)
+		},
+	}
+}
+
+//Find returns the object with the given name, or nil. Conditions on single resources look them up by name,
+//as clients which ignore field selectors, such as fake clients, return other objects as well.
+func Find(objects []interface{}, name string) metav1.Object {
+	for _, obj := range objects {
+		if o, ok := obj.(metav1.Object); ok && o.GetName() == name {
+			return o
+		}
+	}
+	return nil
+}
+
+func nameSelector(name string) string {
+	return fields.OneTermEqualSelector("metadata.name", name).String()
+}
@@ -0,0 +1,63 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUntil(t *testing.T) {
+
+	t.Run("Condition is met by the initial list", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(newSecret("kyma-system", "tls"))
+		err := Until(Secret(kubeClient, "kyma-system", "tls"), &v1.Secret{}, time.Second, func(objects []interface{}) (bool, error) {
+			return Find(objects, "tls") != nil, nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Condition is met after a change", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(newPod("kyma-system", "blocking"))
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = kubeClient.CoreV1().Pods("kyma-system").Delete(context.Background(), "blocking", metav1.DeleteOptions{})
+		}()
+
+		err := Until(Pods(kubeClient, "kyma-system"), &v1.Pod{}, 5*time.Second, func(objects []interface{}) (bool, error) {
+			return len(objects) == 0, nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset()
+		err := Until(Nodes(kubeClient), &v1.Node{}, 50*time.Millisecond, func(objects []interface{}) (bool, error) {
+			return len(objects) > 0, nil
+		})
+		require.Equal(t, wait.ErrWaitTimeout, err)
+	})
+
+	t.Run("Condition fails", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kyma-system"}})
+		failure := errors.New("failure")
+		err := Until(Namespace(kubeClient, "kyma-system"), &v1.Namespace{}, time.Second, func(objects []interface{}) (bool, error) {
+			return false, failure
+		})
+		require.Equal(t, failure, err)
+	})
+}
+
+func newSecret(namespace, name string) *v1.Secret {
+	return &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+}
+
+func newPod(namespace, name string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+}
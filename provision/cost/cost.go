@@ -0,0 +1,206 @@
+// Package cost estimates the monthly cost of the cloud resources a cluster is provisioned with,
+// so that expensive configurations can be reviewed or rejected before they are applied.
+package cost
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+)
+
+// ErrNoPrice is returned by a PricingSource which has no price for a resource
+var ErrNoPrice = errors.New("no price available")
+
+// ResourceKind is the kind of a billed cloud resource
+type ResourceKind string
+
+const (
+	// Node is a worker node, the type is the machine type
+	Node ResourceKind = "node"
+	// Disk is the storage of the worker nodes in GB, the type is the disk type if known
+	Disk ResourceKind = "disk"
+	// ControlPlane is the managed control plane of the cluster, the type is the provider of the control plane
+	ControlPlane ResourceKind = "control-plane"
+)
+
+// Resource is a billed cloud resource of a cluster
+type Resource struct {
+	Kind ResourceKind
+	// Provider is the cloud provider which bills the resource. For Gardener clusters, it is the target provider.
+	Provider types.ProviderType
+	Location string
+	Type     string
+	// Quantity is the number of nodes or control planes, or the size of the disks in GB
+	Quantity float64
+	// MaxQuantity is the quantity the resource can grow to, e.g. with autoscaling. It equals Quantity for fixed resources.
+	MaxQuantity float64
+}
+
+func (r Resource) String() string {
+	return fmt.Sprintf("%s %s %s in %s", r.Provider, r.Kind, r.Type, r.Location)
+}
+
+// PricingSource returns the prices of resources, for example from a static price list or the pricing API of a cloud provider
+type PricingSource interface {
+	// MonthlyPrice returns the monthly price of one unit of the resource: one node, one GB of disk, or one control plane.
+	// It returns ErrNoPrice if the resource has no known price.
+	MonthlyPrice(r Resource) (float64, error)
+	// Currency of the prices, e.g. USD
+	Currency() string
+}
+
+// Item is the cost of a resource
+type Item struct {
+	Resource  Resource
+	UnitPrice float64
+	Monthly   float64
+	// MonthlyMax is the cost if the resource grows to its maximum quantity
+	MonthlyMax float64
+}
+
+// Estimate is the approximate monthly cost of a cluster
+type Estimate struct {
+	Currency   string
+	Items      []Item
+	Monthly    float64
+	MonthlyMax float64
+	// Unpriced are the resources the pricing source has no price for. They are not included in the totals.
+	Unpriced []Resource
+}
+
+// String lists the items and the totals
+func (e *Estimate) String() string {
+	var b strings.Builder
+	for _, item := range e.Items {
+		fmt.Fprintf(&b, "%s: %.2f %s\n", item.Resource, item.Monthly, e.Currency)
+	}
+	for _, r := range e.Unpriced {
+		fmt.Fprintf(&b, "%s: no price available\n", r)
+	}
+	fmt.Fprintf(&b, "Total: %.2f %s per month", e.Monthly, e.Currency)
+	if e.MonthlyMax > e.Monthly {
+		fmt.Fprintf(&b, " (up to %.2f %s with autoscaling)", e.MonthlyMax, e.Currency)
+	}
+	return b.String()
+}
+
+// Estimator maps the resources a cluster is provisioned with to their approximate monthly cost
+type Estimator struct {
+	Pricing PricingSource
+}
+
+// NewEstimator creates an estimator with the given pricing source
+func NewEstimator(pricing PricingSource) *Estimator {
+	return &Estimator{Pricing: pricing}
+}
+
+// Estimate returns the approximate monthly cost of the cluster on the provider
+func (e *Estimator) Estimate(cluster *types.Cluster, provider *types.Provider) (*Estimate, error) {
+	estimate := &Estimate{Currency: e.Pricing.Currency()}
+	for _, r := range Resources(cluster, provider) {
+		price, err := e.Pricing.MonthlyPrice(r)
+		if errors.Cause(err) == ErrNoPrice {
+			estimate.Unpriced = append(estimate.Unpriced, r)
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get the price of %s", r)
+		}
+		item := Item{Resource: r, UnitPrice: price, Monthly: price * r.Quantity, MonthlyMax: price * r.MaxQuantity}
+		estimate.Items = append(estimate.Items, item)
+		estimate.Monthly += item.Monthly
+		estimate.MonthlyMax += item.MonthlyMax
+	}
+	return estimate, nil
+}
+
+// Limit returns a cost check for types.WithCostCheck which rejects clusters whose maximum monthly cost exceeds the limit
+func (e *Estimator) Limit(maxMonthly float64) func(cluster *types.Cluster, provider *types.Provider) error {
+	return func(cluster *types.Cluster, provider *types.Provider) error {
+		estimate, err := e.Estimate(cluster, provider)
+		if err != nil {
+			return err
+		}
+		if estimate.MonthlyMax > maxMonthly {
+			return errors.Errorf("the estimated monthly cost of cluster %s of %.2f %s exceeds the limit of %.2f %s",
+				cluster.Name, estimate.MonthlyMax, estimate.Currency, maxMonthly, estimate.Currency)
+		}
+		return nil
+	}
+}
+
+// Resources returns the billed resources a cluster is provisioned with. Local kind and k3d clusters have no billed resources.
+func Resources(cluster *types.Cluster, provider *types.Provider) []Resource {
+	cfg := provider.CustomConfigurations
+	p := provider.Type
+	nodes := float64(cluster.NodeCount)
+	maxNodes := nodes
+	diskType, _ := cfg["disk_type"].(string)
+
+	switch p {
+	case types.Kind, types.K3d:
+		return nil
+	case types.Gardener:
+		// Gardener clusters are billed by the target provider, the control plane runs on the seed cluster
+		if target, ok := cfg["target_provider"].(string); ok {
+			p = types.ProviderType(target)
+		}
+		if max, ok := intValue(cfg["worker_maximum"]); ok {
+			maxNodes = max
+		}
+		if min, ok := intValue(cfg["worker_minimum"]); ok && min > nodes {
+			nodes = min
+		}
+	default:
+		if max, ok := intValue(cfg["autoscaling_max"]); ok {
+			maxNodes = max
+		}
+	}
+	if maxNodes < nodes {
+		maxNodes = nodes
+	}
+
+	resources := []Resource{
+		{Kind: Node, Provider: p, Location: cluster.Location, Type: cluster.MachineType, Quantity: nodes, MaxQuantity: maxNodes},
+	}
+	if cluster.DiskSizeGB > 0 {
+		disk := float64(cluster.DiskSizeGB)
+		resources = append(resources, Resource{Kind: Disk, Provider: p, Location: cluster.Location, Type: diskType, Quantity: disk * nodes, MaxQuantity: disk * maxNodes})
+	}
+	if provider.Type != types.Gardener {
+		resources = append(resources, Resource{Kind: ControlPlane, Provider: p, Location: cluster.Location, Type: string(p), Quantity: 1, MaxQuantity: 1})
+	}
+	return resources
+}
+
+func intValue(v interface{}) (float64, bool) {
+	i, ok := v.(int)
+	return float64(i), ok
+}
+
+// StaticPricing is a pricing source with a fixed price list
+type StaticPricing struct {
+	// PriceCurrency of the prices, e.g. USD
+	PriceCurrency string
+	// Prices are the monthly prices per unit by resource kind and type, e.g. Prices[Node]["n1-standard-4"].
+	// The type "*" is the price of all types of the kind without an own price.
+	Prices map[ResourceKind]map[string]float64
+}
+
+// MonthlyPrice returns the price of the resource type, or the price of all types of its kind
+func (s *StaticPricing) MonthlyPrice(r Resource) (float64, error) {
+	prices := s.Prices[r.Kind]
+	for _, t := range []string{r.Type, "*"} {
+		if price, ok := prices[t]; ok {
+			return price, nil
+		}
+	}
+	return 0, ErrNoPrice
+}
+
+// Currency of the prices
+func (s *StaticPricing) Currency() string {
+	return s.PriceCurrency
+}
@@ -0,0 +1,101 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+var pricing = &StaticPricing{
+	PriceCurrency: "USD",
+	Prices: map[ResourceKind]map[string]float64{
+		Node:         {"n1-standard-4": 100, "Standard_D4_v3": 150},
+		Disk:         {"*": 0.1},
+		ControlPlane: {"gcp": 70},
+	},
+}
+
+func TestResources(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Kind", func(t *testing.T) {
+		require.Empty(t, Resources(&types.Cluster{NodeCount: 1}, &types.Provider{Type: types.Kind}))
+	})
+
+	t.Run("K3d", func(t *testing.T) {
+		require.Empty(t, Resources(&types.Cluster{NodeCount: 3}, &types.Provider{Type: types.K3d}))
+	})
+
+	t.Run("Gardener", func(t *testing.T) {
+		cluster := &types.Cluster{NodeCount: 2, DiskSizeGB: 30, MachineType: "n1-standard-4", Location: "europe-west3"}
+		provider := &types.Provider{Type: types.Gardener, CustomConfigurations: map[string]interface{}{
+			"target_provider": "gcp",
+			"disk_type":       "pd-standard",
+			"worker_minimum":  3,
+			"worker_maximum":  5,
+		}}
+		require.Equal(t, []Resource{
+			{Kind: Node, Provider: types.GCP, Location: "europe-west3", Type: "n1-standard-4", Quantity: 3, MaxQuantity: 5},
+			{Kind: Disk, Provider: types.GCP, Location: "europe-west3", Type: "pd-standard", Quantity: 90, MaxQuantity: 150},
+		}, Resources(cluster, provider))
+	})
+}
+
+func TestEstimate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Autoscaling", func(t *testing.T) {
+		cluster := &types.Cluster{Name: "hydro", NodeCount: 3, DiskSizeGB: 50, MachineType: "n1-standard-4", Location: "europe-west3"}
+		provider := &types.Provider{Type: types.GCP, CustomConfigurations: map[string]interface{}{"autoscaling_max": 5}}
+
+		estimate, err := NewEstimator(pricing).Estimate(cluster, provider)
+		require.NoError(t, err)
+		require.Len(t, estimate.Items, 3)
+		require.Empty(t, estimate.Unpriced)
+		require.InDelta(t, 3*100+150*0.1+70, estimate.Monthly, 0.001)
+		require.InDelta(t, 5*100+250*0.1+70, estimate.MonthlyMax, 0.001)
+		require.Contains(t, estimate.String(), "Total: 385.00 USD per month (up to 595.00 USD with autoscaling)")
+	})
+
+	t.Run("Unpriced resources", func(t *testing.T) {
+		cluster := &types.Cluster{NodeCount: 2, MachineType: "Standard_D4_v3", Location: "westeurope"}
+		estimate, err := NewEstimator(pricing).Estimate(cluster, &types.Provider{Type: types.Azure})
+		require.NoError(t, err)
+		require.InDelta(t, 300, estimate.Monthly, 0.001)
+		require.Equal(t, []Resource{
+			{Kind: ControlPlane, Provider: types.Azure, Location: "westeurope", Type: "azure", Quantity: 1, MaxQuantity: 1},
+		}, estimate.Unpriced)
+	})
+
+	t.Run("Pricing error", func(t *testing.T) {
+		_, err := NewEstimator(failingPricing{}).Estimate(&types.Cluster{NodeCount: 1}, &types.Provider{Type: types.AWS})
+		require.Error(t, err)
+	})
+}
+
+func TestLimit(t *testing.T) {
+	t.Parallel()
+
+	cluster := &types.Cluster{Name: "hydro", NodeCount: 3, MachineType: "n1-standard-4"}
+	provider := &types.Provider{Type: types.GCP, CustomConfigurations: map[string]interface{}{"autoscaling_max": 10}}
+
+	check := NewEstimator(pricing).Limit(2000)
+	require.NoError(t, check(cluster, provider))
+
+	check = NewEstimator(pricing).Limit(500)
+	err := check(cluster, provider)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "1070.00 USD exceeds the limit of 500.00 USD")
+}
+
+type failingPricing struct{}
+
+func (failingPricing) MonthlyPrice(r Resource) (float64, error) {
+	return 0, errors.New("pricing API not reachable")
+}
+
+func (failingPricing) Currency() string {
+	return "USD"
+}
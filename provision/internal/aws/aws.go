@@ -0,0 +1,226 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform/states/statefile"
+	"github.com/kyma-incubator/hydroform/provision/internal/errs"
+	"github.com/kyma-incubator/hydroform/provision/internal/nodepool"
+	terraform_operator "github.com/kyma-incubator/hydroform/provision/internal/operator/terraform"
+
+	"github.com/kyma-incubator/hydroform/provision/internal/operator"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// awsProvisioner implements Provisioner
+type awsProvisioner struct {
+	provisionOperator operator.Operator
+	// cli runs the AWS CLI with the given environment, replaced in tests
+	cli func(env []string, args ...string) ([]byte, error)
+}
+
+// Provision requests provisioning of a new Kubernetes cluster on AWS EKS with the given configurations.
+func (a *awsProvisioner) Provision(cluster *types.Cluster, provider *types.Provider) (*types.Cluster, error) {
+	if err := a.validateInputs(cluster, provider); err != nil {
+		return cluster, err
+	}
+
+	config := a.loadConfigurations(cluster, provider)
+
+	clusterInfo, err := a.provisionOperator.Create(provider.Type, config)
+	if err != nil {
+		return cluster, errors.Wrap(err, "unable to provision aws cluster")
+	}
+
+	cluster.ClusterInfo = clusterInfo
+	return cluster, nil
+}
+
+// Status returns the ClusterStatus for the requested cluster.
+func (a *awsProvisioner) Status(cluster *types.Cluster, p *types.Provider) (*types.ClusterStatus, error) {
+	var state *statefile.File
+	if cluster.ClusterInfo != nil && cluster.ClusterInfo.InternalState != nil {
+		state = cluster.ClusterInfo.InternalState.TerraformState
+	}
+
+	if err := a.validateInputs(cluster, p); err != nil {
+		return nil, err
+	}
+
+	cfg := a.loadConfigurations(cluster, p)
+
+	return a.provisionOperator.Status(state, p.Type, cfg)
+}
+
+// Credentials returns the Kubeconfig file as a byte array for the requested cluster.
+// EKS does not issue static credentials, the kubeconfig fetches a token with the AWS CLI using the provider credentials.
+func (a *awsProvisioner) Credentials(cluster *types.Cluster, p *types.Provider) ([]byte, error) {
+	if err := a.validateInputs(cluster, p); err != nil {
+		return nil, err
+	}
+	if cluster.ClusterInfo == nil || cluster.ClusterInfo.Endpoint == "" || cluster.ClusterInfo.CertificateAuthorityData == nil {
+		return nil, errors.New(errs.EmptyClusterInfo)
+	}
+
+	userName := "cluster-user"
+	config := api.NewConfig()
+
+	config.Clusters[cluster.Name] = &api.Cluster{
+		Server:                   cluster.ClusterInfo.Endpoint,
+		CertificateAuthorityData: cluster.ClusterInfo.CertificateAuthorityData,
+	}
+
+	config.Contexts[cluster.Name] = &api.Context{
+		Cluster:  cluster.Name,
+		AuthInfo: userName,
+	}
+
+	config.CurrentContext = cluster.Name
+
+	env := []api.ExecEnvVar{
+		{Name: "AWS_SHARED_CREDENTIALS_FILE", Value: p.CredentialsFilePath},
+	}
+	if profile, ok := p.CustomConfigurations["profile"].(string); ok && profile != "" {
+		env = append(env, api.ExecEnvVar{Name: "AWS_PROFILE", Value: profile})
+	}
+
+	config.AuthInfos[userName] = &api.AuthInfo{
+		Exec: &api.ExecConfig{
+			APIVersion: "client.authentication.k8s.io/v1alpha1",
+			Command:    "aws",
+			Args:       []string{"eks", "get-token", "--cluster-name", cluster.Name, "--region", cluster.Location},
+			Env:        env,
+		},
+	}
+
+	return clientcmd.Write(*config)
+}
+
+// Deprovision requests deprovisioning of an existing cluster on AWS EKS with the given configurations.
+func (a *awsProvisioner) Deprovision(cluster *types.Cluster, p *types.Provider) error {
+	if err := a.validateInputs(cluster, p); err != nil {
+		return err
+	}
+
+	config := a.loadConfigurations(cluster, p)
+
+	var state *statefile.File
+	if cluster.ClusterInfo != nil && cluster.ClusterInfo.InternalState != nil {
+		state = cluster.ClusterInfo.InternalState.TerraformState
+	}
+
+	err := a.provisionOperator.Delete(state, p.Type, config)
+	if err != nil {
+		return errors.Wrap(err, "unable to deprovision aws cluster")
+	}
+
+	return nil
+}
+
+// New creates a new instance of awsProvisioner.
+func New(operatorType operator.Type, ops ...types.Option) *awsProvisioner {
+	// parse config
+	os := &types.Options{}
+	for _, o := range ops {
+		o(os)
+	}
+
+	var op operator.Operator
+	switch operatorType {
+	case operator.TerraformOperator:
+		tfOps := terraform_operator.ToTerraformOptions(os)
+		op = terraform_operator.New(tfOps...)
+	default:
+		op = &operator.Unknown{}
+	}
+
+	return &awsProvisioner{
+		provisionOperator: op,
+		cli:               awsCLI,
+	}
+}
+
+func (a *awsProvisioner) validateInputs(cluster *types.Cluster, provider *types.Provider) error {
+	var errMessage string
+	if cluster.NodeCount < 1 {
+		errMessage += fmt.Sprintf(errs.CannotBeLess, "Cluster.NodeCount", 1)
+	}
+	// Matches the regex for an EKS cluster name.
+	if match, _ := regexp.MatchString(`^[0-9A-Za-z][A-Za-z0-9\-_]{0,99}$`, cluster.Name); !match {
+		errMessage += fmt.Sprintf(errs.Custom, "Cluster.Name must start with a letter or number followed by up to 99 letters, "+
+			"numbers, hyphens, or underscores")
+	}
+	if cluster.Location == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Cluster.Location")
+	}
+	if cluster.MachineType == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Cluster.MachineType")
+	}
+	if cluster.KubernetesVersion == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Cluster.KubernetesVersion")
+	}
+	if cluster.DiskSizeGB <= 0 {
+		errMessage += fmt.Sprintf(errs.CannotBeLess, "Cluster.DiskSizeGB", 1)
+	}
+
+	if provider.CredentialsFilePath == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CredentialsFilePath")
+	}
+	// reusing a VPC requires subnets in at least two availability zones
+	if vpc, ok := provider.CustomConfigurations["vpc_id"]; ok && vpc != "" {
+		if subnets, _ := stringList(provider.CustomConfigurations["subnet_ids"]); len(subnets) < 2 {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['subnet_ids'] must contain at least 2 subnets when reusing a VPC")
+		}
+	}
+
+	errMessage += nodepool.Validate(provider.CustomConfigurations)
+
+	if errMessage != "" {
+		return errors.New("input validation failed with the following information: " + errMessage)
+	}
+
+	return nil
+}
+
+func (a *awsProvisioner) loadConfigurations(cluster *types.Cluster, provider *types.Provider) map[string]interface{} {
+	config := map[string]interface{}{}
+	config["cluster_name"] = cluster.Name
+	config["node_count"] = cluster.NodeCount
+	config["machine_type"] = cluster.MachineType
+	config["disk_size"] = cluster.DiskSizeGB
+	config["kubernetes_version"] = cluster.KubernetesVersion
+	config["location"] = cluster.Location
+	config["project"] = provider.ProjectName
+	config["credentials_file_path"] = provider.CredentialsFilePath
+	for k, v := range provider.CustomConfigurations {
+		config[k] = v
+	}
+	// configurations decoded from JSON or YAML contain lists of interfaces, which are not written to the Terraform variables
+	if subnets, ok := stringList(config["subnet_ids"]); ok {
+		config["subnet_ids"] = subnets
+	}
+	return config
+}
+
+// stringList returns the value as list of strings if it is a []string or a []interface{} containing only strings
+func stringList(v interface{}) ([]string, bool) {
+	switch l := v.(type) {
+	case []string:
+		return l, true
+	case []interface{}:
+		list := make([]string, 0, len(l))
+		for _, item := range l {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, s)
+		}
+		return list, true
+	}
+	return nil, false
+}
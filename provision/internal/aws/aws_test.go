@@ -0,0 +1,232 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/states/statefile"
+	"github.com/kyma-incubator/hydroform/provision/internal/operator/mocks"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateInputs(t *testing.T) {
+	t.Parallel()
+	a := &awsProvisioner{}
+
+	cluster := &types.Cluster{
+		KubernetesVersion: "1.18",
+		Name:              "hydro-cluster",
+		DiskSizeGB:        30,
+		NodeCount:         2,
+		Location:          "eu-central-1",
+		MachineType:       "m5.xlarge",
+	}
+	provider := &types.Provider{
+		Type:                types.AWS,
+		CredentialsFilePath: "/path/to/credentials",
+	}
+
+	require.NoError(t, a.validateInputs(cluster, provider), "Validation should pass")
+
+	cluster.NodeCount = -5
+	require.Error(t, a.validateInputs(cluster, provider), "Validation should fail when number of nodes is < 1")
+	cluster.NodeCount = 2
+
+	cluster.Name = ""
+	require.Error(t, a.validateInputs(cluster, provider), "Validation should fail when cluster name is empty")
+	cluster.Name = "-invalid-start"
+	require.Error(t, a.validateInputs(cluster, provider), "Validation should fail when cluster name starts with '-'")
+	cluster.Name = "hydro-cluster"
+
+	cluster.Location = ""
+	require.Error(t, a.validateInputs(cluster, provider), "Validation should fail when cluster location is empty")
+	cluster.Location = "eu-central-1"
+
+	cluster.MachineType = ""
+	require.Error(t, a.validateInputs(cluster, provider), "Validation should fail when cluster machine type is empty")
+	cluster.MachineType = "m5.xlarge"
+
+	cluster.KubernetesVersion = ""
+	require.Error(t, a.validateInputs(cluster, provider), "Validation should fail when Kubernetes version is empty")
+	cluster.KubernetesVersion = "1.18"
+
+	cluster.DiskSizeGB = 0
+	require.Error(t, a.validateInputs(cluster, provider), "Validation should fail when disk size is 0 or less")
+	cluster.DiskSizeGB = 30
+
+	provider.CredentialsFilePath = ""
+	require.Error(t, a.validateInputs(cluster, provider), "Validation should fail when credentials file path is empty")
+	provider.CredentialsFilePath = "/path/to/credentials"
+
+	provider.CustomConfigurations = map[string]interface{}{
+		"vpc_id": "vpc-12345",
+	}
+	require.Error(t, a.validateInputs(cluster, provider), "Validation should fail when reusing a VPC without subnets")
+	provider.CustomConfigurations["subnet_ids"] = []string{"subnet-1", "subnet-2"}
+	require.NoError(t, a.validateInputs(cluster, provider), "Validation should pass when reusing a VPC with subnets")
+	provider.CustomConfigurations["subnet_ids"] = []interface{}{"subnet-1", "subnet-2"}
+	require.NoError(t, a.validateInputs(cluster, provider), "Validation should pass with subnets decoded from JSON or YAML")
+	provider.CustomConfigurations["subnet_ids"] = []interface{}{"subnet-1", 2}
+	require.Error(t, a.validateInputs(cluster, provider), "Validation should fail when subnets are not strings")
+}
+
+func TestLoadConfigurations(t *testing.T) {
+	t.Parallel()
+	a := &awsProvisioner{}
+
+	cluster := &types.Cluster{
+		KubernetesVersion: "1.18",
+		Name:              "hydro-cluster",
+		DiskSizeGB:        30,
+		NodeCount:         2,
+		Location:          "eu-central-1",
+		MachineType:       "m5.xlarge",
+	}
+	provider := &types.Provider{
+		Type:                types.AWS,
+		ProjectName:         "my-project",
+		CredentialsFilePath: "/path/to/credentials",
+		CustomConfigurations: map[string]interface{}{
+			"profile":  "hydro",
+			"vpc_cidr": "10.1.0.0/16",
+		},
+	}
+
+	config := a.loadConfigurations(cluster, provider)
+
+	require.Equal(t, cluster.Name, config["cluster_name"])
+	require.Equal(t, provider.CredentialsFilePath, config["credentials_file_path"])
+	require.Equal(t, cluster.NodeCount, config["node_count"])
+	require.Equal(t, cluster.MachineType, config["machine_type"])
+	require.Equal(t, cluster.DiskSizeGB, config["disk_size"])
+	require.Equal(t, cluster.KubernetesVersion, config["kubernetes_version"])
+	require.Equal(t, cluster.Location, config["location"])
+	require.Equal(t, provider.ProjectName, config["project"])
+
+	for k, v := range provider.CustomConfigurations {
+		require.Equal(t, v, config[k], fmt.Sprintf("Custom config %s is incorrect", k))
+	}
+
+	provider.CustomConfigurations["subnet_ids"] = []interface{}{"subnet-1", "subnet-2"}
+	config = a.loadConfigurations(cluster, provider)
+	require.Equal(t, []string{"subnet-1", "subnet-2"}, config["subnet_ids"], "Subnets should be converted to a list of strings")
+}
+
+func TestProvision(t *testing.T) {
+	t.Parallel()
+	mockOp := &mocks.Operator{}
+	a := awsProvisioner{
+		provisionOperator: mockOp,
+	}
+
+	cluster := &types.Cluster{
+		KubernetesVersion: "1.18",
+		Name:              "hydro-cluster",
+		DiskSizeGB:        30,
+		NodeCount:         2,
+		Location:          "eu-central-1",
+		MachineType:       "m5.xlarge",
+	}
+	provider := &types.Provider{
+		Type:                types.AWS,
+		CredentialsFilePath: "/path/to/credentials",
+	}
+
+	result := &types.ClusterInfo{
+		CertificateAuthorityData: []byte("My cert"),
+		Endpoint:                 "https://cluster-url.fake",
+		Status: &types.ClusterStatus{
+			Phase: types.Provisioned,
+		},
+		InternalState: &types.InternalState{
+			TerraformState: nil,
+		},
+	}
+	mockOp.On("Create", types.AWS, a.loadConfigurations(cluster, provider)).Return(result, nil)
+
+	cluster, err := a.Provision(cluster, provider)
+	require.NoError(t, err, "Provision should succeed")
+	require.Equal(t, result, cluster.ClusterInfo, "The cluster info returned from the operator should be in the cluster returned by Provision")
+
+	badCluster := &types.Cluster{
+		NodeCount: 1,
+	}
+	_, err = a.Provision(badCluster, provider)
+	require.Error(t, err, "Provision should fail")
+}
+
+func TestCredentials(t *testing.T) {
+	t.Parallel()
+	a := &awsProvisioner{}
+
+	cluster := &types.Cluster{
+		KubernetesVersion: "1.18",
+		Name:              "hydro-cluster",
+		DiskSizeGB:        30,
+		NodeCount:         2,
+		Location:          "eu-central-1",
+		MachineType:       "m5.xlarge",
+	}
+	provider := &types.Provider{
+		Type:                types.AWS,
+		CredentialsFilePath: "/path/to/credentials",
+		CustomConfigurations: map[string]interface{}{
+			"profile": "hydro",
+		},
+	}
+
+	_, err := a.Credentials(cluster, provider)
+	require.Error(t, err, "Credentials should fail without cluster info")
+
+	cluster.ClusterInfo = &types.ClusterInfo{
+		CertificateAuthorityData: []byte("My cert"),
+		Endpoint:                 "https://cluster-url.fake",
+	}
+	content, err := a.Credentials(cluster, provider)
+	require.NoError(t, err, "Credentials should succeed")
+
+	kubeconfig, err := clientcmd.Load(content)
+	require.NoError(t, err)
+	require.Equal(t, "https://cluster-url.fake", kubeconfig.Clusters["hydro-cluster"].Server)
+	require.Equal(t, "aws", kubeconfig.AuthInfos["cluster-user"].Exec.Command)
+	require.Contains(t, kubeconfig.AuthInfos["cluster-user"].Exec.Args, "hydro-cluster")
+	require.Len(t, kubeconfig.AuthInfos["cluster-user"].Exec.Env, 2)
+}
+
+func TestDeprovision(t *testing.T) {
+	t.Parallel()
+	mockOp := &mocks.Operator{}
+	a := awsProvisioner{
+		provisionOperator: mockOp,
+	}
+
+	cluster := &types.Cluster{
+		KubernetesVersion: "1.18",
+		Name:              "hydro-cluster",
+		DiskSizeGB:        30,
+		NodeCount:         2,
+		Location:          "eu-central-1",
+		MachineType:       "m5.xlarge",
+		ClusterInfo:       &types.ClusterInfo{},
+	}
+	provider := &types.Provider{
+		Type:                types.AWS,
+		CredentialsFilePath: "/path/to/credentials",
+	}
+
+	var state *statefile.File
+	mockOp.On("Delete", state, types.AWS, a.loadConfigurations(cluster, provider)).Return(nil)
+
+	err := a.Deprovision(cluster, provider)
+	require.NoError(t, err, "Deprovision should succeed")
+
+	provider.CredentialsFilePath = "/wrong/credentials"
+	mockOp.On("Delete", state, types.AWS, a.loadConfigurations(cluster, provider)).Return(errors.New("Unable to deprovision cluster"))
+
+	err = a.Deprovision(cluster, provider)
+	require.Error(t, err, "Deprovision should fail")
+}
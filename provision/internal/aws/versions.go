@@ -0,0 +1,88 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/kyma-incubator/hydroform/provision/internal/errs"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+)
+
+// Capabilities returns the features Hydroform supports on EKS and the Kubernetes versions EKS offers in the region of the cluster.
+// EKS has no API listing its versions, they are collected from the versions the default kube-proxy add-on is compatible with,
+// using the AWS CLI like the kubeconfig of the cluster does.
+func (a *awsProvisioner) Capabilities(cluster *types.Cluster, p *types.Provider) (*types.Capabilities, error) {
+	var errMessage string
+	if cluster.Location == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Cluster.Location")
+	}
+	if p.CredentialsFilePath == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CredentialsFilePath")
+	}
+	if errMessage != "" {
+		return nil, errors.New("input validation failed with the following information: " + errMessage)
+	}
+
+	env := []string{"AWS_SHARED_CREDENTIALS_FILE=" + p.CredentialsFilePath}
+	if profile, ok := p.CustomConfigurations["profile"].(string); ok && profile != "" {
+		env = append(env, "AWS_PROFILE="+profile)
+	}
+	out, err := a.cli(env, "eks", "describe-addon-versions", "--addon-name", "kube-proxy", "--region", cluster.Location, "--output", "json")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list the EKS add-on versions")
+	}
+	versions, err := kubernetesVersions(out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Capabilities{
+		Autoscaling:        true,
+		SpotInstances:      true,
+		KubernetesVersions: versions,
+	}, nil
+}
+
+// kubernetesVersions returns the cluster versions the add-on versions in the output of describe-addon-versions are compatible with.
+func kubernetesVersions(out []byte) ([]string, error) {
+	var res struct {
+		Addons []struct {
+			AddonVersions []struct {
+				Compatibilities []struct {
+					ClusterVersion string `json:"clusterVersion"`
+				} `json:"compatibilities"`
+			} `json:"addonVersions"`
+		} `json:"addons"`
+	}
+	if err := json.Unmarshal(out, &res); err != nil {
+		return nil, errors.Wrap(err, "could not decode the EKS add-on versions")
+	}
+
+	seen := map[string]bool{}
+	var versions []string
+	for _, addon := range res.Addons {
+		for _, v := range addon.AddonVersions {
+			for _, c := range v.Compatibilities {
+				if c.ClusterVersion != "" && !seen[c.ClusterVersion] {
+					seen[c.ClusterVersion] = true
+					versions = append(versions, c.ClusterVersion)
+				}
+			}
+		}
+	}
+	return versions, nil
+}
+
+// awsCLI runs the AWS CLI with the given environment added to the environment of the process.
+func awsCLI(env []string, args ...string) ([]byte, error) {
+	cmd := exec.Command("aws", args...)
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return nil, errors.Errorf("%s: %s", err, exitErr.Stderr)
+	}
+	return out, err
+}
@@ -0,0 +1,44 @@
+package aws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	var env, args []string
+	a := &awsProvisioner{
+		cli: func(e []string, cliArgs ...string) ([]byte, error) {
+			env, args = e, cliArgs
+			return []byte(`{"addons": [{"addonName": "kube-proxy", "addonVersions": [
+				{"addonVersion": "v1.18.8-eksbuild.1", "compatibilities": [{"clusterVersion": "1.18"}]},
+				{"addonVersion": "v1.17.9-eksbuild.1", "compatibilities": [{"clusterVersion": "1.17"}, {"clusterVersion": "1.18"}]}]}]}`), nil
+		},
+	}
+	cluster := &types.Cluster{Name: "hydro-cluster", Location: "eu-central-1"}
+	provider := &types.Provider{
+		Type:                 types.AWS,
+		CredentialsFilePath:  "/path/to/credentials",
+		CustomConfigurations: map[string]interface{}{"profile": "dev"},
+	}
+
+	c, err := a.Capabilities(cluster, provider)
+	require.NoError(t, err)
+	require.Equal(t, &types.Capabilities{Autoscaling: true, SpotInstances: true, KubernetesVersions: []string{"1.18", "1.17"}}, c)
+	require.Equal(t, []string{"AWS_SHARED_CREDENTIALS_FILE=/path/to/credentials", "AWS_PROFILE=dev"}, env)
+	require.Equal(t, []string{"eks", "describe-addon-versions", "--addon-name", "kube-proxy", "--region", "eu-central-1", "--output", "json"}, args)
+
+	_, err = a.Capabilities(&types.Cluster{Name: "hydro-cluster"}, provider)
+	require.Error(t, err, "A cluster without a region should fail")
+
+	a.cli = func(e []string, cliArgs ...string) ([]byte, error) {
+		return nil, errors.New("aws: command not found")
+	}
+	_, err = a.Capabilities(cluster, provider)
+	require.Error(t, err, "CLI errors should be returned")
+}
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"regexp"
 
 	"github.com/hashicorp/terraform/states/statefile"
 	"github.com/kyma-incubator/hydroform/provision/internal/errs"
+	"github.com/kyma-incubator/hydroform/provision/internal/nodepool"
 	terraform_operator "github.com/kyma-incubator/hydroform/provision/internal/operator/terraform"
 
 	"github.com/kyma-incubator/hydroform/provision/internal/operator"
@@ -18,6 +20,7 @@ import (
 // azureProvisioner implements Provisioner
 type azureProvisioner struct {
 	provisionOperator operator.Operator
+	rotateCredentials bool
 }
 
 // Provision requests provisioning of a new Kubernetes cluster on Azure with the given configurations.
@@ -60,10 +63,14 @@ func (a *azureProvisioner) Status(cluster *types.Cluster, p *types.Provider) (*t
 }
 
 // Credentials returns the Kubeconfig file as a byte array for the requested cluster.
+// If credentials rotation is enabled, the cluster certificates are rotated first and the new admin kubeconfig is fetched from AKS.
 func (a *azureProvisioner) Credentials(cluster *types.Cluster, p *types.Provider) ([]byte, error) {
 	if err := a.validateInputs(cluster, p); err != nil {
 		return nil, err
 	}
+	if a.rotateCredentials {
+		return a.rotatedCredentials(cluster, p)
+	}
 	if cluster.ClusterInfo == nil || cluster.ClusterInfo.InternalState == nil || cluster.ClusterInfo.InternalState.TerraformState == nil {
 		// TODO add a way to get the kubeconfig from the state file if possible
 		return nil, errors.New(errs.EmptyClusterInfo)
@@ -74,6 +81,20 @@ func (a *azureProvisioner) Credentials(cluster *types.Cluster, p *types.Provider
 	return []byte(kubeconfig), nil
 }
 
+// rotatedCredentials rotates the certificates of the AKS cluster and returns its new admin kubeconfig.
+func (a *azureProvisioner) rotatedCredentials(cluster *types.Cluster, p *types.Provider) ([]byte, error) {
+	subscriptionID, tenantID, clientID, clientSecret, err := azureCredentials(p.CredentialsFilePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error loading credentials")
+	}
+	client := managementClient(azureLoginURL, azureManagementURL, tenantID, clientID, clientSecret)
+	url := clusterURL(azureManagementURL, subscriptionID, p.ProjectName, cluster.Name)
+	if err := rotateCertificates(client, url); err != nil {
+		return nil, err
+	}
+	return adminKubeconfig(client, url)
+}
+
 // Deprovision requests deprovisioning of an existing cluster on Azure with the given configurations.
 func (a *azureProvisioner) Deprovision(cluster *types.Cluster, p *types.Provider) error {
 	if err := a.validateInputs(cluster, p); err != nil {
@@ -116,6 +137,7 @@ func New(operatorType operator.Type, ops ...types.Option) *azureProvisioner {
 
 	return &azureProvisioner{
 		provisionOperator: op,
+		rotateCredentials: os.RotateCredentials,
 	}
 }
 
@@ -146,6 +168,9 @@ func (a *azureProvisioner) validateInputs(cluster *types.Cluster, provider *type
 		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CredentialsFilePath")
 	}
 
+	errMessage += nodepool.Validate(provider.CustomConfigurations)
+	errMessage += validateClusterOptions(provider.CustomConfigurations)
+
 	if errMessage != "" {
 		return errors.New("input validation failed with the following information: " + errMessage)
 	}
@@ -153,6 +178,85 @@ func (a *azureProvisioner) validateInputs(cluster *types.Cluster, provider *type
 	return nil
 }
 
+// validateClusterOptions checks the Azure AD, managed identity, availability zone, and virtual network custom configurations
+// and returns the validation errors in the errs format.
+func validateClusterOptions(cfg map[string]interface{}) string {
+	var errMessage string
+
+	// spot instances run in the extra user node pool, which only scales down the costs if it can scale down
+	if cfg[nodepool.Spot] == true {
+		if _, ok := cfg[nodepool.AutoscalingMax]; !ok {
+			errMessage += fmt.Sprintf(errs.Custom, fmt.Sprintf("Provider.CustomConfigurations['%s'] requires '%s' on Azure, as the system node pool cannot run on spot instances", nodepool.Spot, nodepool.AutoscalingMax))
+		}
+	}
+
+	for _, key := range []string{"aad_rbac", "managed_identity"} {
+		if v, ok := cfg[key]; ok {
+			if _, ok := v.(bool); !ok {
+				errMessage += fmt.Sprintf(errs.Custom, fmt.Sprintf("Provider.CustomConfigurations['%s'] must be a boolean", key))
+			}
+		}
+	}
+
+	if v, ok := cfg["aad_admin_group_ids"]; ok {
+		if _, ok := v.([]string); !ok {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['aad_admin_group_ids'] must be a list of group object IDs")
+		} else if cfg["aad_rbac"] != true {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['aad_admin_group_ids'] requires 'aad_rbac'")
+		}
+	}
+	if v, ok := cfg["managed_identity_id"]; ok {
+		if _, ok := v.(string); !ok {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['managed_identity_id'] must be the resource ID of a user-assigned identity")
+		} else if cfg["managed_identity"] != true {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['managed_identity_id'] requires 'managed_identity'")
+		}
+	}
+
+	if v, ok := cfg["availability_zones"]; ok {
+		zones, ok := v.([]string)
+		valid := ok && len(zones) > 0
+		for _, zone := range zones {
+			valid = valid && (zone == "1" || zone == "2" || zone == "3")
+		}
+		if !valid {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['availability_zones'] must be a list of the zones 1, 2, and 3")
+		}
+	}
+
+	if v, ok := cfg["vnet_subnet_id"]; ok {
+		if _, ok := v.(string); !ok {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['vnet_subnet_id'] must be the resource ID of a subnet")
+		}
+	}
+	for _, key := range []string{"service_cidr", "docker_bridge_cidr"} {
+		if v, ok := cfg[key]; ok {
+			if cidr, ok := v.(string); !ok || !isCIDR(cidr) {
+				errMessage += fmt.Sprintf(errs.Custom, fmt.Sprintf("Provider.CustomConfigurations['%s'] must be a CIDR range", key))
+			}
+		}
+	}
+	if v, ok := cfg["dns_service_ip"]; ok {
+		if ip, ok := v.(string); !ok || net.ParseIP(ip) == nil {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['dns_service_ip'] must be an IP address")
+		}
+	}
+	// the network profile is only set for custom virtual networks
+	_, subnet := cfg["vnet_subnet_id"]
+	for _, key := range []string{"service_cidr", "dns_service_ip", "docker_bridge_cidr"} {
+		if _, ok := cfg[key]; ok && !subnet {
+			errMessage += fmt.Sprintf(errs.Custom, fmt.Sprintf("Provider.CustomConfigurations['%s'] requires 'vnet_subnet_id'", key))
+		}
+	}
+
+	return errMessage
+}
+
+func isCIDR(s string) bool {
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+}
+
 func (a *azureProvisioner) loadConfigurations(cluster *types.Cluster, provider *types.Provider) (map[string]interface{}, error) {
 	config := map[string]interface{}{}
 	config["cluster_name"] = cluster.Name
@@ -92,6 +92,38 @@ func TestValidateInputs(t *testing.T) {
 	require.Error(t, g.validateInputs(cluster, provider), "Validation should fail when disk type is empty")
 }
 
+func TestValidateClusterOptions(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, validateClusterOptions(map[string]interface{}{}))
+	require.Empty(t, validateClusterOptions(map[string]interface{}{
+		"aad_rbac":            true,
+		"aad_admin_group_ids": []string{"00000000-0000-0000-0000-000000000000"},
+		"managed_identity":    true,
+		"managed_identity_id": "/subscriptions/id/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/aks",
+		"availability_zones":  []string{"1", "2", "3"},
+		"vnet_subnet_id":      "/subscriptions/id/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/aks",
+		"service_cidr":        "10.0.0.0/16",
+		"dns_service_ip":      "10.0.0.10",
+		"docker_bridge_cidr":  "172.17.0.1/16",
+	}))
+	require.Empty(t, validateClusterOptions(map[string]interface{}{"spot": true, "autoscaling_max": 3}))
+
+	for name, cfg := range map[string]map[string]interface{}{
+		"non boolean AAD RBAC":                {"aad_rbac": "yes"},
+		"admin groups without AAD RBAC":       {"aad_admin_group_ids": []string{"group"}},
+		"user-assigned identity without MSI":  {"managed_identity_id": "/subscriptions/id"},
+		"unknown availability zone":           {"availability_zones": []string{"1", "4"}},
+		"availability zones not in a list":    {"availability_zones": "1"},
+		"invalid service CIDR":                {"vnet_subnet_id": "/subscriptions/id", "service_cidr": "10.0.0.0"},
+		"invalid DNS service IP":              {"vnet_subnet_id": "/subscriptions/id", "dns_service_ip": "10.0.0"},
+		"network profile without custom VNet": {"service_cidr": "10.0.0.0/16"},
+		"spot without autoscaling":            {"spot": true},
+	} {
+		require.NotEmpty(t, validateClusterOptions(cfg), name)
+	}
+}
+
 func TestLoadConfigurations(t *testing.T) {
 	g := &azureProvisioner{}
 
@@ -0,0 +1,107 @@
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const aksAPIVersion = "2020-09-01"
+
+var (
+	// rotationPollInterval is the time between the status checks of a certificate rotation, replaced in tests
+	rotationPollInterval = 30 * time.Second
+	// rotationTimeout limits the certificate rotation, which restarts all nodes of the cluster
+	rotationTimeout = time.Hour
+)
+
+// clusterURL returns the Azure Resource Manager URL of the AKS cluster.
+func clusterURL(managementURL, subscriptionID, resourceGroup, name string) string {
+	return fmt.Sprintf("%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerService/managedClusters/%s",
+		managementURL, subscriptionID, resourceGroup, name)
+}
+
+// rotateCertificates rotates the certificates of the AKS cluster and waits until the rotation finished.
+// All kubeconfigs issued before the rotation stop working.
+func rotateCertificates(client *http.Client, clusterURL string) error {
+	resp, err := client.Post(fmt.Sprintf("%s/rotateClusterCertificates?api-version=%s", clusterURL, aksAPIVersion), "application/json", nil)
+	if err != nil {
+		return errors.Wrap(err, "could not rotate the AKS cluster certificates")
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		return errors.Errorf("could not rotate the AKS cluster certificates: %s", resp.Status)
+	}
+	operationURL := resp.Header.Get("Azure-AsyncOperation")
+	if resp.StatusCode == http.StatusNoContent || operationURL == "" {
+		return nil
+	}
+
+	deadline := time.Now().Add(rotationTimeout)
+	for {
+		status, err := operationStatus(client, operationURL)
+		if err != nil {
+			return err
+		}
+		switch status {
+		case "Succeeded":
+			return nil
+		case "Failed", "Canceled":
+			return errors.Errorf("rotation of the AKS cluster certificates ended with status %s", status)
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("rotation of the AKS cluster certificates did not finish within %s", rotationTimeout)
+		}
+		time.Sleep(rotationPollInterval)
+	}
+}
+
+// operationStatus returns the status of an asynchronous Azure Resource Manager operation.
+func operationStatus(client *http.Client, operationURL string) (string, error) {
+	resp, err := client.Get(operationURL)
+	if err != nil {
+		return "", errors.Wrap(err, "could not read the status of the AKS operation")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("could not read the status of the AKS operation: %s", resp.Status)
+	}
+
+	var operation struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&operation); err != nil {
+		return "", errors.Wrap(err, "could not decode the status of the AKS operation")
+	}
+	return operation.Status, nil
+}
+
+// adminKubeconfig fetches the current admin kubeconfig of the AKS cluster.
+func adminKubeconfig(client *http.Client, clusterURL string) ([]byte, error) {
+	resp, err := client.Post(fmt.Sprintf("%s/listClusterAdminCredential?api-version=%s", clusterURL, aksAPIVersion), "application/json", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list the AKS admin credentials")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("could not list the AKS admin credentials: %s", resp.Status)
+	}
+
+	// the kubeconfig values are base64 encoded, which encoding/json decodes into byte slices
+	var credentials struct {
+		Kubeconfigs []struct {
+			Name  string `json:"name"`
+			Value []byte `json:"value"`
+		} `json:"kubeconfigs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&credentials); err != nil {
+		return nil, errors.Wrap(err, "could not decode the AKS admin credentials")
+	}
+	if len(credentials.Kubeconfigs) == 0 {
+		return nil, errors.New("AKS returned no admin kubeconfig")
+	}
+	return credentials.Kubeconfigs[0].Value, nil
+}
@@ -0,0 +1,82 @@
+package azure
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatedCredentials(t *testing.T) {
+	rotationPollInterval = time.Millisecond
+
+	clusterPath := "/subscriptions/my-subscription/resourceGroups/my-resource-group/providers/Microsoft.ContainerService/managedClusters/my-cluster"
+	polls := 0
+	rotated := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/my-tenant/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "my-token", "token_type": "Bearer", "expires_in": 3600}`)
+	})
+	mux.HandleFunc(clusterPath+"/rotateClusterCertificates", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Azure-AsyncOperation", "http://"+r.Host+"/operations/rotation")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/operations/rotation", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 3 {
+			fmt.Fprint(w, `{"status": "InProgress"}`)
+			return
+		}
+		rotated = true
+		fmt.Fprint(w, `{"status": "Succeeded"}`)
+	})
+	mux.HandleFunc(clusterPath+"/listClusterAdminCredential", func(w http.ResponseWriter, r *http.Request) {
+		kubeconfig := "old-kubeconfig"
+		if rotated {
+			kubeconfig = "new-kubeconfig"
+		}
+		fmt.Fprintf(w, `{"kubeconfigs": [{"name": "clusterAdmin", "value": "%s"}]}`, base64.StdEncoding.EncodeToString([]byte(kubeconfig)))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := managementClient(server.URL, server.URL, "my-tenant", "my-client", "my-secret")
+	url := clusterURL(server.URL, "my-subscription", "my-resource-group", "my-cluster")
+	require.NoError(t, rotateCertificates(client, url))
+	require.Equal(t, 3, polls, "The rotation should be awaited")
+
+	kubeconfig, err := adminKubeconfig(client, url)
+	require.NoError(t, err)
+	require.Equal(t, "new-kubeconfig", string(kubeconfig))
+
+	url = clusterURL(server.URL, "my-subscription", "my-resource-group", "unknown-cluster")
+	require.Error(t, rotateCertificates(client, url), "Unknown clusters should fail")
+	_, err = adminKubeconfig(client, url)
+	require.Error(t, err, "Unknown clusters should fail")
+}
+
+func TestRotateCertificatesFailure(t *testing.T) {
+	rotationPollInterval = time.Millisecond
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cluster/rotateClusterCertificates", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Azure-AsyncOperation", "http://"+r.Host+"/operations/rotation")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/operations/rotation", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status": "Failed"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	require.Error(t, rotateCertificates(http.DefaultClient, server.URL+"/cluster"), "Failed rotations should fail")
+}
@@ -0,0 +1,90 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/kyma-incubator/hydroform/provision/internal/errs"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	azureLoginURL      = "https://login.microsoftonline.com"
+	azureManagementURL = "https://management.azure.com"
+)
+
+// Capabilities returns the features Hydroform supports on AKS and the Kubernetes versions AKS offers in the location of the cluster.
+func (a *azureProvisioner) Capabilities(cluster *types.Cluster, p *types.Provider) (*types.Capabilities, error) {
+	var errMessage string
+	if cluster.Location == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Cluster.Location")
+	}
+	if p.CredentialsFilePath == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CredentialsFilePath")
+	}
+	if errMessage != "" {
+		return nil, errors.New("input validation failed with the following information: " + errMessage)
+	}
+
+	subscriptionID, tenantID, clientID, clientSecret, err := azureCredentials(p.CredentialsFilePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error loading credentials")
+	}
+	client := managementClient(azureLoginURL, azureManagementURL, tenantID, clientID, clientSecret)
+	versions, err := kubernetesVersions(client, azureManagementURL, subscriptionID, cluster.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Capabilities{
+		Autoscaling:        true,
+		SpotInstances:      true,
+		KubernetesVersions: versions,
+	}, nil
+}
+
+// managementClient returns an HTTP client authenticated for the Azure Resource Manager API with the service principal.
+func managementClient(loginURL, managementURL, tenantID, clientID, clientSecret string) *http.Client {
+	cfg := &clientcredentials.Config{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		TokenURL:       fmt.Sprintf("%s/%s/oauth2/token", loginURL, tenantID),
+		EndpointParams: url.Values{"resource": {managementURL + "/"}},
+	}
+	return cfg.Client(context.Background())
+}
+
+// kubernetesVersions returns the Kubernetes versions AKS offers in the given location.
+func kubernetesVersions(client *http.Client, managementURL, subscriptionID, location string) ([]string, error) {
+	resp, err := client.Get(fmt.Sprintf("%s/subscriptions/%s/providers/Microsoft.ContainerService/locations/%s/orchestrators?api-version=2019-08-01&resource-type=managedClusters",
+		managementURL, subscriptionID, location))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list the AKS orchestrators")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("could not list the AKS orchestrators: %s", resp.Status)
+	}
+
+	var orchestrators struct {
+		Properties struct {
+			Orchestrators []struct {
+				Version string `json:"orchestratorVersion"`
+			} `json:"orchestrators"`
+		} `json:"properties"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&orchestrators); err != nil {
+		return nil, errors.Wrap(err, "could not decode the AKS orchestrators")
+	}
+
+	versions := make([]string, 0, len(orchestrators.Properties.Orchestrators))
+	for _, o := range orchestrators.Properties.Orchestrators {
+		versions = append(versions, o.Version)
+	}
+	return versions, nil
+}
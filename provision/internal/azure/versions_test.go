@@ -0,0 +1,46 @@
+package azure
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesVersions(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/my-tenant/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("resource") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "my-token", "token_type": "Bearer", "expires_in": 3600}`)
+	})
+	mux.HandleFunc("/subscriptions/my-subscription/providers/Microsoft.ContainerService/locations/westeurope/orchestrators", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"properties": {"orchestrators": [{"orchestratorType": "Kubernetes", "orchestratorVersion": "1.18.10"}, {"orchestratorType": "Kubernetes", "orchestratorVersion": "1.19.3"}]}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := managementClient(server.URL, server.URL, "my-tenant", "my-client", "my-secret")
+	versions, err := kubernetesVersions(client, server.URL, "my-subscription", "westeurope")
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.18.10", "1.19.3"}, versions)
+
+	_, err = kubernetesVersions(client, server.URL, "my-subscription", "marsnorth")
+	require.Error(t, err, "Unknown locations should fail")
+
+	client = managementClient(server.URL, server.URL, "other-tenant", "my-client", "my-secret")
+	_, err = kubernetesVersions(client, server.URL, "my-subscription", "westeurope")
+	require.Error(t, err, "Failed logins should fail")
+}
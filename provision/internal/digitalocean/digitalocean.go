@@ -0,0 +1,162 @@
+package digitalocean
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform/states/statefile"
+	"github.com/kyma-incubator/hydroform/provision/internal/errs"
+	terraform_operator "github.com/kyma-incubator/hydroform/provision/internal/operator/terraform"
+
+	"github.com/kyma-incubator/hydroform/provision/internal/operator"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+)
+
+// digitalOceanProvisioner implements Provisioner
+type digitalOceanProvisioner struct {
+	provisionOperator operator.Operator
+}
+
+// Provision requests provisioning of a new Kubernetes cluster on DigitalOcean with the given configurations.
+func (d *digitalOceanProvisioner) Provision(cluster *types.Cluster, provider *types.Provider) (*types.Cluster, error) {
+	if err := d.validateInputs(cluster, provider); err != nil {
+		return cluster, err
+	}
+
+	config := d.loadConfigurations(cluster, provider)
+
+	clusterInfo, err := d.provisionOperator.Create(provider.Type, config)
+	if err != nil {
+		return cluster, errors.Wrap(err, "unable to provision digitalocean cluster")
+	}
+
+	cluster.ClusterInfo = clusterInfo
+	return cluster, nil
+}
+
+// Status returns the ClusterStatus for the requested cluster.
+func (d *digitalOceanProvisioner) Status(cluster *types.Cluster, p *types.Provider) (*types.ClusterStatus, error) {
+	var state *statefile.File
+	if cluster.ClusterInfo != nil && cluster.ClusterInfo.InternalState != nil {
+		state = cluster.ClusterInfo.InternalState.TerraformState
+	}
+
+	if err := d.validateInputs(cluster, p); err != nil {
+		return nil, err
+	}
+
+	cfg := d.loadConfigurations(cluster, p)
+
+	return d.provisionOperator.Status(state, p.Type, cfg)
+}
+
+// Credentials returns the Kubeconfig file as a byte array for the requested cluster.
+func (d *digitalOceanProvisioner) Credentials(cluster *types.Cluster, p *types.Provider) ([]byte, error) {
+	if err := d.validateInputs(cluster, p); err != nil {
+		return nil, err
+	}
+	if cluster.ClusterInfo == nil || cluster.ClusterInfo.InternalState == nil || cluster.ClusterInfo.InternalState.TerraformState == nil {
+		return nil, errors.New(errs.EmptyClusterInfo)
+	}
+
+	state := cluster.ClusterInfo.InternalState.TerraformState.State
+	if state == nil || state.Modules[""] == nil {
+		return nil, errors.New("the cluster state is empty")
+	}
+	kubeconfig, ok := state.Modules[""].OutputValues["kube_config"]
+	if !ok {
+		return nil, errors.New("the cluster state does not contain a kubeconfig")
+	}
+
+	return []byte(kubeconfig.Value.AsString()), nil
+}
+
+// Deprovision requests deprovisioning of an existing cluster on DigitalOcean with the given configurations.
+func (d *digitalOceanProvisioner) Deprovision(cluster *types.Cluster, p *types.Provider) error {
+	if err := d.validateInputs(cluster, p); err != nil {
+		return err
+	}
+
+	config := d.loadConfigurations(cluster, p)
+
+	var state *statefile.File
+	if cluster.ClusterInfo != nil && cluster.ClusterInfo.InternalState != nil {
+		state = cluster.ClusterInfo.InternalState.TerraformState
+	}
+
+	err := d.provisionOperator.Delete(state, p.Type, config)
+	if err != nil {
+		return errors.Wrap(err, "unable to deprovision digitalocean cluster")
+	}
+
+	return nil
+}
+
+// New creates a new instance of digitalOceanProvisioner.
+func New(operatorType operator.Type, ops ...types.Option) *digitalOceanProvisioner {
+	// parse config
+	os := &types.Options{}
+	for _, o := range ops {
+		o(os)
+	}
+
+	var op operator.Operator
+	switch operatorType {
+	case operator.TerraformOperator:
+		tfOps := terraform_operator.ToTerraformOptions(os)
+		op = terraform_operator.New(tfOps...)
+	default:
+		op = &operator.Unknown{}
+	}
+
+	return &digitalOceanProvisioner{
+		provisionOperator: op,
+	}
+}
+
+func (d *digitalOceanProvisioner) validateInputs(cluster *types.Cluster, provider *types.Provider) error {
+	var errMessage string
+	if cluster.NodeCount < 1 {
+		errMessage += fmt.Sprintf(errs.CannotBeLess, "Cluster.NodeCount", 1)
+	}
+	// Matches the regex for a DigitalOcean cluster name.
+	if match, _ := regexp.MatchString(`^(?:[a-z0-9](?:[-a-z0-9]{0,61}[a-z0-9])?)$`, cluster.Name); !match {
+		errMessage += fmt.Sprintf(errs.Custom, "Cluster.Name must start with a lowercase letter or number followed by up to 62 lowercase letters, "+
+			"numbers, or hyphens, and cannot end with a hyphen")
+	}
+	if cluster.Location == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Cluster.Location")
+	}
+	if cluster.MachineType == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Cluster.MachineType")
+	}
+	if cluster.KubernetesVersion == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Cluster.KubernetesVersion")
+	}
+
+	if provider.CredentialsFilePath == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CredentialsFilePath")
+	}
+
+	if errMessage != "" {
+		return errors.New("input validation failed with the following information: " + errMessage)
+	}
+
+	return nil
+}
+
+func (d *digitalOceanProvisioner) loadConfigurations(cluster *types.Cluster, provider *types.Provider) map[string]interface{} {
+	config := map[string]interface{}{}
+	config["cluster_name"] = cluster.Name
+	config["node_count"] = cluster.NodeCount
+	config["machine_type"] = cluster.MachineType
+	config["kubernetes_version"] = cluster.KubernetesVersion
+	config["location"] = cluster.Location
+	config["project"] = provider.ProjectName
+	config["credentials_file_path"] = provider.CredentialsFilePath
+	for k, v := range provider.CustomConfigurations {
+		config[k] = v
+	}
+	return config
+}
@@ -0,0 +1,211 @@
+package digitalocean
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/states"
+	"github.com/hashicorp/terraform/states/statefile"
+	"github.com/kyma-incubator/hydroform/provision/internal/operator/mocks"
+	"github.com/pkg/errors"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateInputs(t *testing.T) {
+	t.Parallel()
+	d := &digitalOceanProvisioner{}
+
+	cluster := &types.Cluster{
+		KubernetesVersion: "1.19.3-do.2",
+		Name:              "hydro-cluster",
+		NodeCount:         2,
+		Location:          "fra1",
+		MachineType:       "s-2vcpu-4gb",
+	}
+	provider := &types.Provider{
+		Type:                types.DigitalOcean,
+		CredentialsFilePath: "/path/to/token",
+	}
+
+	require.NoError(t, d.validateInputs(cluster, provider), "Validation should pass")
+
+	cluster.NodeCount = -5
+	require.Error(t, d.validateInputs(cluster, provider), "Validation should fail when number of nodes is < 1")
+	cluster.NodeCount = 2
+
+	cluster.Name = ""
+	require.Error(t, d.validateInputs(cluster, provider), "Validation should fail when cluster name is empty")
+	cluster.Name = "-invalid-start"
+	require.Error(t, d.validateInputs(cluster, provider), "Validation should fail when cluster name starts with '-'")
+	cluster.Name = "invalid-end-"
+	require.Error(t, d.validateInputs(cluster, provider), "Validation should fail when cluster name ends with '-'")
+	cluster.Name = "hydro-cluster"
+
+	cluster.Location = ""
+	require.Error(t, d.validateInputs(cluster, provider), "Validation should fail when cluster location is empty")
+	cluster.Location = "fra1"
+
+	cluster.MachineType = ""
+	require.Error(t, d.validateInputs(cluster, provider), "Validation should fail when cluster machine type is empty")
+	cluster.MachineType = "s-2vcpu-4gb"
+
+	cluster.KubernetesVersion = ""
+	require.Error(t, d.validateInputs(cluster, provider), "Validation should fail when Kubernetes version is empty")
+	cluster.KubernetesVersion = "1.19.3-do.2"
+
+	provider.CredentialsFilePath = ""
+	require.Error(t, d.validateInputs(cluster, provider), "Validation should fail when credentials file path is empty")
+}
+
+func TestLoadConfigurations(t *testing.T) {
+	t.Parallel()
+	d := &digitalOceanProvisioner{}
+
+	cluster := &types.Cluster{
+		KubernetesVersion: "1.19.3-do.2",
+		Name:              "hydro-cluster",
+		NodeCount:         2,
+		Location:          "fra1",
+		MachineType:       "s-2vcpu-4gb",
+	}
+	provider := &types.Provider{
+		Type:                types.DigitalOcean,
+		ProjectName:         "my-project",
+		CredentialsFilePath: "/path/to/token",
+		CustomConfigurations: map[string]interface{}{
+			"vpc_uuid": "c33931f2-a26a-4e61-b85c-4e95a2ec431b",
+		},
+	}
+
+	config := d.loadConfigurations(cluster, provider)
+
+	require.Equal(t, cluster.Name, config["cluster_name"])
+	require.Equal(t, provider.CredentialsFilePath, config["credentials_file_path"])
+	require.Equal(t, cluster.NodeCount, config["node_count"])
+	require.Equal(t, cluster.MachineType, config["machine_type"])
+	require.Equal(t, cluster.KubernetesVersion, config["kubernetes_version"])
+	require.Equal(t, cluster.Location, config["location"])
+	require.Equal(t, provider.ProjectName, config["project"])
+
+	for k, v := range provider.CustomConfigurations {
+		require.Equal(t, v, config[k], fmt.Sprintf("Custom config %s is incorrect", k))
+	}
+}
+
+func TestProvision(t *testing.T) {
+	t.Parallel()
+	mockOp := &mocks.Operator{}
+	d := digitalOceanProvisioner{
+		provisionOperator: mockOp,
+	}
+
+	cluster := &types.Cluster{
+		KubernetesVersion: "1.19.3-do.2",
+		Name:              "hydro-cluster",
+		NodeCount:         2,
+		Location:          "fra1",
+		MachineType:       "s-2vcpu-4gb",
+	}
+	provider := &types.Provider{
+		Type:                types.DigitalOcean,
+		CredentialsFilePath: "/path/to/token",
+	}
+
+	result := &types.ClusterInfo{
+		CertificateAuthorityData: []byte("My cert"),
+		Endpoint:                 "https://cluster-url.fake",
+		Status: &types.ClusterStatus{
+			Phase: types.Provisioned,
+		},
+		InternalState: &types.InternalState{
+			TerraformState: nil,
+		},
+	}
+	mockOp.On("Create", types.DigitalOcean, d.loadConfigurations(cluster, provider)).Return(result, nil)
+
+	cluster, err := d.Provision(cluster, provider)
+	require.NoError(t, err, "Provision should succeed")
+	require.Equal(t, result, cluster.ClusterInfo, "The cluster info returned from the operator should be in the cluster returned by Provision")
+
+	badCluster := &types.Cluster{
+		NodeCount: 1,
+	}
+	_, err = d.Provision(badCluster, provider)
+	require.Error(t, err, "Provision should fail")
+}
+
+func TestCredentials(t *testing.T) {
+	t.Parallel()
+	d := &digitalOceanProvisioner{}
+
+	cluster := &types.Cluster{
+		KubernetesVersion: "1.19.3-do.2",
+		Name:              "hydro-cluster",
+		NodeCount:         2,
+		Location:          "fra1",
+		MachineType:       "s-2vcpu-4gb",
+	}
+	provider := &types.Provider{
+		Type:                types.DigitalOcean,
+		CredentialsFilePath: "/path/to/token",
+	}
+
+	_, err := d.Credentials(cluster, provider)
+	require.Error(t, err, "Credentials should fail without cluster info")
+
+	cluster.ClusterInfo = &types.ClusterInfo{
+		InternalState: &types.InternalState{
+			TerraformState: &statefile.File{},
+		},
+	}
+	_, err = d.Credentials(cluster, provider)
+	require.Error(t, err, "Credentials should fail with an empty state")
+
+	state := states.NewState()
+	state.RootModule().SetOutputValue("kube_config", cty.StringVal("kubeconfig content"), true)
+	cluster.ClusterInfo = &types.ClusterInfo{
+		InternalState: &types.InternalState{
+			TerraformState: &statefile.File{State: state},
+		},
+	}
+
+	content, err := d.Credentials(cluster, provider)
+	require.NoError(t, err, "Credentials should succeed")
+	require.Equal(t, "kubeconfig content", string(content))
+}
+
+func TestDeprovision(t *testing.T) {
+	t.Parallel()
+	mockOp := &mocks.Operator{}
+	d := digitalOceanProvisioner{
+		provisionOperator: mockOp,
+	}
+
+	cluster := &types.Cluster{
+		KubernetesVersion: "1.19.3-do.2",
+		Name:              "hydro-cluster",
+		NodeCount:         2,
+		Location:          "fra1",
+		MachineType:       "s-2vcpu-4gb",
+		ClusterInfo:       &types.ClusterInfo{},
+	}
+	provider := &types.Provider{
+		Type:                types.DigitalOcean,
+		CredentialsFilePath: "/path/to/token",
+	}
+
+	var state *statefile.File
+	mockOp.On("Delete", state, types.DigitalOcean, d.loadConfigurations(cluster, provider)).Return(nil)
+
+	err := d.Deprovision(cluster, provider)
+	require.NoError(t, err, "Deprovision should succeed")
+
+	provider.CredentialsFilePath = "/wrong/token"
+	mockOp.On("Delete", state, types.DigitalOcean, d.loadConfigurations(cluster, provider)).Return(errors.New("Unable to deprovision cluster"))
+
+	err = d.Deprovision(cluster, provider)
+	require.Error(t, err, "Deprovision should fail")
+}
@@ -0,0 +1,69 @@
+package digitalocean
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/provision/internal/errs"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+)
+
+const digitalOceanAPI = "https://api.digitalocean.com"
+
+// Capabilities returns the features Hydroform supports on DigitalOcean and the Kubernetes versions DigitalOcean offers.
+// The versions are the slugs to set as the Kubernetes version of the cluster, such as 1.19.3-do.2.
+func (d *digitalOceanProvisioner) Capabilities(cluster *types.Cluster, p *types.Provider) (*types.Capabilities, error) {
+	if p.CredentialsFilePath == "" {
+		return nil, errors.New("input validation failed with the following information: " + fmt.Sprintf(errs.CannotBeEmpty, "Provider.CredentialsFilePath"))
+	}
+
+	versions, err := kubernetesVersions(digitalOceanAPI, p.CredentialsFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Capabilities{KubernetesVersions: versions}, nil
+}
+
+// kubernetesVersions returns the slugs of the Kubernetes versions DigitalOcean offers, using the token in the given file.
+func kubernetesVersions(apiURL, tokenPath string) ([]string, error) {
+	token, err := ioutil.ReadFile(tokenPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the DigitalOcean token")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/kubernetes/options", apiURL), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get the DigitalOcean Kubernetes options")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("could not get the DigitalOcean Kubernetes options: %s", resp.Status)
+	}
+
+	var options struct {
+		Options struct {
+			Versions []struct {
+				Slug string `json:"slug"`
+			} `json:"versions"`
+		} `json:"options"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&options); err != nil {
+		return nil, errors.Wrap(err, "could not decode the DigitalOcean Kubernetes options")
+	}
+
+	versions := make([]string, 0, len(options.Options.Versions))
+	for _, v := range options.Options.Versions {
+		versions = append(versions, v.Slug)
+	}
+	return versions, nil
+}
@@ -0,0 +1,39 @@
+package digitalocean
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesVersions(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"options": {"versions": [{"slug": "1.19.3-do.2", "kubernetes_version": "1.19.3"}, {"slug": "1.18.10-do.2", "kubernetes_version": "1.18.10"}]}}`)
+	}))
+	defer server.Close()
+
+	token, err := ioutil.TempFile("", "do-token")
+	require.NoError(t, err)
+	defer os.Remove(token.Name())
+	_, err = token.WriteString("my-token\n")
+	require.NoError(t, err)
+	require.NoError(t, token.Close())
+
+	versions, err := kubernetesVersions(server.URL, token.Name())
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.19.3-do.2", "1.18.10-do.2"}, versions)
+
+	_, err = kubernetesVersions(server.URL, "/does/not/exist")
+	require.Error(t, err, "Missing token should fail")
+}
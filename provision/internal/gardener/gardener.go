@@ -2,30 +2,43 @@ package gardener
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"regexp"
 	"strconv"
+	"time"
 
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/hashicorp/terraform/states/statefile"
 	"github.com/kyma-incubator/hydroform/provision/internal/errs"
 	"github.com/kyma-incubator/hydroform/provision/internal/operator"
+	gardener_operator "github.com/kyma-incubator/hydroform/provision/internal/operator/gardener"
 	terraform_operator "github.com/kyma-incubator/hydroform/provision/internal/operator/terraform"
 	"github.com/kyma-incubator/hydroform/provision/types"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 const (
-	gcpProfile   string = "gcp"
-	awsProfile   string = "aws"
-	azureProfile string = "az"
+	gcpProfile       string = "gcp"
+	awsProfile       string = "aws"
+	azureProfile     string = "az"
+	openStackProfile string = "openstack"
 )
 
+// shootResource is the Gardener resource of the clusters
+var shootResource = schema.GroupVersionResource{Group: "core.gardener.cloud", Version: "v1beta1", Resource: "shoots"}
+
 type gardenerProvisioner struct {
-	operator operator.Operator
+	operator              operator.Operator
+	credentialsExpiration time.Duration
 }
 
 func New(operatorType operator.Type, ops ...types.Option) *gardenerProvisioner {
@@ -35,16 +48,23 @@ func New(operatorType operator.Type, ops ...types.Option) *gardenerProvisioner {
 		o(os)
 	}
 
+	if os.GardenerAPI {
+		operatorType = operator.GardenerOperator
+	}
+
 	var op operator.Operator
 	switch operatorType {
 	case operator.TerraformOperator:
 		tfOps := terraform_operator.ToTerraformOptions(os)
 		op = terraform_operator.New(tfOps...)
+	case operator.GardenerOperator:
+		op = gardener_operator.New(os)
 	default:
 		op = &operator.Unknown{}
 	}
 	return &gardenerProvisioner{
-		operator: op,
+		operator:              op,
+		credentialsExpiration: os.CredentialsExpiration,
 	}
 }
 
@@ -89,6 +109,10 @@ func (g *gardenerProvisioner) Credentials(cluster *types.Cluster, provider *type
 		return nil, err
 	}
 
+	if g.credentialsExpiration > 0 {
+		return adminKubeconfig(config, fmt.Sprintf("garden-%s", provider.ProjectName), cluster.Name, g.credentialsExpiration)
+	}
+
 	k8s, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
@@ -102,6 +126,76 @@ func (g *gardenerProvisioner) Credentials(cluster *types.Cluster, provider *type
 	return s.Data["kubeconfig"], nil
 }
 
+// adminKubeconfig requests a kubeconfig with short-lived admin credentials from the adminkubeconfig subresource of the shoot.
+func adminKubeconfig(config *rest.Config, namespace, shoot string, expiration time.Duration) ([]byte, error) {
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "authentication.gardener.cloud/v1alpha1",
+			"kind":       "AdminKubeconfigRequest",
+			"spec": map[string]interface{}{
+				"expirationSeconds": int64(expiration.Seconds()),
+			},
+		},
+	}
+
+	res, err := client.Resource(shootResource).Namespace(namespace).Create(context.Background(), req, metav1.CreateOptions{}, "adminkubeconfig")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not request the admin kubeconfig")
+	}
+
+	kubeconfig, found, err := unstructured.NestedString(res.Object, "status", "kubeconfig")
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("the admin kubeconfig request returned no kubeconfig")
+	}
+
+	return base64.StdEncoding.DecodeString(kubeconfig)
+}
+
+// Hibernate scales down the nodes and control plane of the requested cluster until it is woken up again.
+func (g *gardenerProvisioner) Hibernate(cluster *types.Cluster, provider *types.Provider) error {
+	return g.setHibernation(cluster, provider, true)
+}
+
+// WakeUp resumes the requested cluster from hibernation.
+func (g *gardenerProvisioner) WakeUp(cluster *types.Cluster, provider *types.Provider) error {
+	return g.setHibernation(cluster, provider, false)
+}
+
+func (g *gardenerProvisioner) setHibernation(cluster *types.Cluster, provider *types.Provider, enabled bool) error {
+	if err := g.validate(cluster, provider); err != nil {
+		return err
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", provider.CredentialsFilePath)
+	if err != nil {
+		return err
+	}
+
+	return patchHibernation(config, fmt.Sprintf("garden-%s", provider.ProjectName), cluster.Name, enabled)
+}
+
+// patchHibernation enables or disables the hibernation of the given shoot.
+func patchHibernation(config *rest.Config, namespace, shoot string, enabled bool) error {
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	patch := []byte(fmt.Sprintf(`{"spec":{"hibernation":{"enabled":%t}}}`, enabled))
+	if _, err := client.Resource(shootResource).Namespace(namespace).Patch(context.Background(), shoot, k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return errors.Wrapf(err, "could not set the hibernation of cluster %s", shoot)
+	}
+	return nil
+}
+
 func (g *gardenerProvisioner) Deprovision(cluster *types.Cluster, p *types.Provider) error {
 	if err := g.validate(cluster, p); err != nil {
 		return err
@@ -158,8 +252,8 @@ func (g *gardenerProvisioner) validate(cluster *types.Cluster, provider *types.P
 	// Custom gardener configuration
 	targetProvider, ok := provider.CustomConfigurations["target_provider"]
 	if ok {
-		if targetProvider != string(types.GCP) && targetProvider != string(types.AWS) && targetProvider != string(types.Azure) {
-			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['target_provider'] has to be one of: gcp, azure, aws")
+		if targetProvider != string(types.GCP) && targetProvider != string(types.AWS) && targetProvider != string(types.Azure) && targetProvider != string(types.OpenStack) {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['target_provider'] has to be one of: gcp, azure, aws, openstack")
 		}
 	} else {
 		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CustomConfigurations['target_provider']")
@@ -183,10 +277,10 @@ func (g *gardenerProvisioner) validate(cluster *types.Cluster, provider *types.P
 	if _, ok := provider.CustomConfigurations["worker_max_unavailable"]; !ok {
 		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CustomConfigurations['worker_max_unavailable']")
 	}
-	if _, ok := provider.CustomConfigurations["workercidr"]; !ok && (targetProvider == string(types.GCP) || targetProvider == string(types.Azure)) {
+	if _, ok := provider.CustomConfigurations["workercidr"]; !ok && (targetProvider == string(types.GCP) || targetProvider == string(types.Azure) || targetProvider == string(types.OpenStack)) {
 		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CustomConfigurations['workercidr']")
 	}
-	if _, ok := provider.CustomConfigurations["zones"]; !ok && (targetProvider == string(types.GCP) || targetProvider == string(types.AWS)) {
+	if _, ok := provider.CustomConfigurations["zones"]; !ok && (targetProvider == string(types.GCP) || targetProvider == string(types.AWS) || targetProvider == string(types.OpenStack)) {
 		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CustomConfigurations['zone']")
 	}
 	if _, ok := provider.CustomConfigurations["vnetcidr"]; !ok && (targetProvider == string(types.Azure) || targetProvider == string(types.AWS)) {
@@ -209,6 +303,9 @@ func (g *gardenerProvisioner) validate(cluster *types.Cluster, provider *types.P
 	if _, ok := provider.CustomConfigurations["gcp_control_plane_zone"]; !ok && targetProvider == string(types.GCP) {
 		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CustomConfigurations['gcp_control_plane_zone']")
 	}
+	if _, ok := provider.CustomConfigurations["floating_pool_name"]; !ok && targetProvider == string(types.OpenStack) {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CustomConfigurations['floating_pool_name']")
+	}
 
 	if errMessage != "" {
 		return errors.New("input validation failed with the following information: " + errMessage)
@@ -257,6 +354,13 @@ func (*gardenerProvisioner) loadConfigurations(cluster *types.Cluster, provider
 
 		// need to set the zoned property if we have a cluster with zones
 		config["zoned"] = strconv.FormatBool(len(config["zones"].([]string)) > 0) // add zoned boolean
+	case string(types.OpenStack):
+		config["target_profile"] = openStackProfile
+
+		// nodes CIDR is usually the same as workercidr
+		if v, ok := config["networking_nodes"]; !ok || v == "" {
+			config["networking_nodes"] = config["workercidr"]
+		}
 	}
 	return config
 }
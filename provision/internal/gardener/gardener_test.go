@@ -1,8 +1,14 @@
 package gardener
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/states/statefile"
 	"github.com/kyma-incubator/hydroform/provision/internal/operator/mocks"
@@ -10,6 +16,7 @@ import (
 
 	"github.com/kyma-incubator/hydroform/provision/types"
 	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
 )
 
 func TestValidate(t *testing.T) {
@@ -165,6 +172,53 @@ func TestValidate(t *testing.T) {
 		require.Error(t, g.validate(cluster, provider), "Validation should fail when vnetcidr is empty")
 		provider.CustomConfigurations["vnetcidr"] = "172.31.0.0/16"
 	})
+
+	t.Run("Validate OpenStack config", func(t *testing.T) {
+		t.Parallel()
+		g := gardenerProvisioner{}
+
+		cluster := &types.Cluster{
+			CPU:               1,
+			KubernetesVersion: "1.18",
+			Name:              "hydro-cluster",
+			DiskSizeGB:        35,
+			NodeCount:         2,
+			Location:          "eu-de-1",
+			MachineType:       "medium_4_8",
+		}
+		provider := &types.Provider{
+			Type:                types.Gardener,
+			ProjectName:         "my-project",
+			CredentialsFilePath: "/path/to/credentials",
+			CustomConfigurations: map[string]interface{}{
+				"target_provider":        "openstack",
+				"target_secret":          "secret-name",
+				"disk_type":              "default",
+				"workercidr":             "10.250.0.0/19",
+				"zones":                  []string{"eu-de-1a"},
+				"floating_pool_name":     "FloatingIP-external",
+				"worker_max_surge":       4,
+				"worker_max_unavailable": 1,
+				"worker_maximum":         4,
+				"worker_minimum":         2,
+				"networking_type":        "calico",
+			},
+		}
+
+		performBasicValidation(t, g, cluster, provider)
+
+		//openstack specific validation
+		provider.CustomConfigurations["target_provider"] = "openstack"
+		require.NoError(t, g.validate(cluster, provider), "Validation should pass")
+
+		delete(provider.CustomConfigurations, "zones")
+		require.Error(t, g.validate(cluster, provider), "Validation should fail when zone is empty")
+		provider.CustomConfigurations["zones"] = []string{"eu-de-1a"}
+
+		delete(provider.CustomConfigurations, "floating_pool_name")
+		require.Error(t, g.validate(cluster, provider), "Validation should fail when floating pool name is empty")
+		provider.CustomConfigurations["floating_pool_name"] = "FloatingIP-external"
+	})
 }
 
 func performBasicValidation(t *testing.T, g gardenerProvisioner, cluster *types.Cluster, provider *types.Provider) {
@@ -392,3 +446,83 @@ func TestDeProvision(t *testing.T) {
 	err = g.Deprovision(cluster, provider)
 	require.Error(t, err, "Deprovision should fail")
 }
+
+func TestAdminKubeconfig(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/apis/core.gardener.cloud/v1beta1/namespaces/garden-my-project/shoots/hydro-cluster/adminkubeconfig", r.URL.Path)
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(body, &req))
+		require.Equal(t, float64(600), req["spec"].(map[string]interface{})["expirationSeconds"])
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"apiVersion":"authentication.gardener.cloud/v1alpha1","kind":"AdminKubeconfigRequest","status":{"kubeconfig":"%s"}}`,
+			base64.StdEncoding.EncodeToString([]byte("kubeconfig content")))
+	}))
+	defer server.Close()
+
+	kubeconfig, err := adminKubeconfig(&rest.Config{Host: server.URL}, "garden-my-project", "hydro-cluster", 10*time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "kubeconfig content", string(kubeconfig))
+}
+
+func TestPatchHibernation(t *testing.T) {
+	t.Parallel()
+
+	var patch map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPatch, r.Method)
+		require.Equal(t, "/apis/core.gardener.cloud/v1beta1/namespaces/garden-my-project/shoots/hydro-cluster", r.URL.Path)
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &patch))
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"apiVersion":"core.gardener.cloud/v1beta1","kind":"Shoot","metadata":{"name":"hydro-cluster"}}`)
+	}))
+	defer server.Close()
+
+	config := &rest.Config{Host: server.URL}
+
+	require.NoError(t, patchHibernation(config, "garden-my-project", "hydro-cluster", true))
+	require.Equal(t, true, patch["spec"].(map[string]interface{})["hibernation"].(map[string]interface{})["enabled"])
+
+	require.NoError(t, patchHibernation(config, "garden-my-project", "hydro-cluster", false))
+	require.Equal(t, false, patch["spec"].(map[string]interface{})["hibernation"].(map[string]interface{})["enabled"])
+}
+
+func TestKubernetesVersions(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/core.gardener.cloud/v1beta1/cloudprofiles/gcp" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"apiVersion":"core.gardener.cloud/v1beta1","kind":"CloudProfile","metadata":{"name":"gcp"},"spec":{"kubernetes":{"versions":[
+			{"version":"1.19.4"},
+			{"version":"1.18.12","classification":"supported"},
+			{"version":"1.17.14","classification":"deprecated","expirationDate":"2021-01-31T23:59:59Z"}]}}}`)
+	}))
+	defer server.Close()
+
+	config := &rest.Config{Host: server.URL}
+
+	versions, err := kubernetesVersions(config, "gcp", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.19.4", "1.18.12", "1.17.14"}, versions)
+
+	versions, err = kubernetesVersions(config, "gcp", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.19.4", "1.18.12"}, versions, "Expired versions should be left out")
+
+	_, err = kubernetesVersions(config, "az", time.Now())
+	require.Error(t, err, "Unknown cloud profiles should fail")
+}
@@ -0,0 +1,101 @@
+package gardener
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/provision/internal/errs"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// cloudProfileResource is the Gardener resource of the infrastructure offerings, including the Kubernetes versions
+var cloudProfileResource = schema.GroupVersionResource{Group: "core.gardener.cloud", Version: "v1beta1", Resource: "cloudprofiles"}
+
+// Capabilities returns the features Hydroform supports on Gardener and the Kubernetes versions of the cloud profile of the target provider.
+// Expired versions are left out.
+func (g *gardenerProvisioner) Capabilities(cluster *types.Cluster, p *types.Provider) (*types.Capabilities, error) {
+	var errMessage string
+	if p.CredentialsFilePath == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CredentialsFilePath")
+	}
+	target, _ := p.CustomConfigurations["target_provider"].(string)
+	profile := targetProfile(target)
+	if profile == "" {
+		errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['target_provider'] has to be one of: gcp, azure, aws, openstack")
+	}
+	if errMessage != "" {
+		return nil, errors.New("input validation failed with the following information: " + errMessage)
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", p.CredentialsFilePath)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := kubernetesVersions(config, profile, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Capabilities{
+		Autoscaling:        true,
+		Hibernation:        true,
+		KubernetesVersions: versions,
+	}, nil
+}
+
+// targetProfile returns the cloud profile of the given target provider, or an empty string if the provider is not supported.
+func targetProfile(targetProvider string) string {
+	switch targetProvider {
+	case string(types.GCP):
+		return gcpProfile
+	case string(types.AWS):
+		return awsProfile
+	case string(types.Azure):
+		return azureProfile
+	case string(types.OpenStack):
+		return openStackProfile
+	}
+	return ""
+}
+
+// kubernetesVersions returns the Kubernetes versions of the given cloud profile which are not expired at the given time.
+func kubernetesVersions(config *rest.Config, profile string, now time.Time) ([]string, error) {
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Resource(cloudProfileResource).Get(context.Background(), profile, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get the cloud profile %s", profile)
+	}
+	entries, _, err := unstructured.NestedSlice(res.Object, "spec", "kubernetes", "versions")
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if expiration, ok := entry["expirationDate"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, expiration); err == nil && !now.Before(t) {
+				continue
+			}
+		}
+		if v, ok := entry["version"].(string); ok {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
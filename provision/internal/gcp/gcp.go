@@ -1,23 +1,43 @@
 package gcp
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
 	"regexp"
+	"time"
 
 	"github.com/hashicorp/terraform/states/statefile"
 	"github.com/kyma-incubator/hydroform/provision/internal/errs"
+	"github.com/kyma-incubator/hydroform/provision/internal/nodepool"
 	terraform_operator "github.com/kyma-incubator/hydroform/provision/internal/operator/terraform"
 
 	"github.com/kyma-incubator/hydroform/provision/internal/operator"
 	"github.com/kyma-incubator/hydroform/provision/types"
 	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
+const (
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+	iamCredentialsURL  = "https://iamcredentials.googleapis.com"
+	// maxTokenLifetime is the longest lifetime of a service account access token. Lifetimes above one hour
+	// require the constraints/iam.allowServiceAccountCredentialLifetimeExtension organization policy.
+	maxTokenLifetime = 12 * time.Hour
+)
+
 // gcpProvisioner implements Provisioner
 type gcpProvisioner struct {
-	provisionOperator operator.Operator
+	provisionOperator     operator.Operator
+	credentialsExpiration time.Duration
 }
 
 // Provision requests provisioning of a new Kubernetes cluster on GCP with the given configurations.
@@ -84,6 +104,17 @@ func (g *gcpProvisioner) Credentials(cluster *types.Cluster, p *types.Provider)
 		},
 	}
 
+	// short-lived credentials use a fresh access token of the service account instead of the gcloud auth provider
+	if g.credentialsExpiration > 0 {
+		token, err := accessToken(p.CredentialsFilePath, g.credentialsExpiration)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get an access token for the service account")
+		}
+		config.AuthInfos[userName] = &api.AuthInfo{
+			Token: token,
+		}
+	}
+
 	return clientcmd.Write(*config)
 }
 
@@ -126,8 +157,66 @@ func New(operatorType operator.Type, ops ...types.Option) *gcpProvisioner {
 	}
 
 	return &gcpProvisioner{
-		provisionOperator: op,
+		provisionOperator:     op,
+		credentialsExpiration: os.CredentialsExpiration,
+	}
+}
+
+// accessToken issues an OAuth2 access token for the service account in the given credentials file which expires after the given duration.
+// The token is generated with the IAM Credentials API, so the service account needs the Service Account Token Creator role on itself.
+func accessToken(credentialsFilePath string, expiration time.Duration) (string, error) {
+	if expiration < time.Second || expiration > maxTokenLifetime {
+		return "", errors.Errorf("credentials expiration must be between 1s and %s, got %s", maxTokenLifetime, expiration)
+	}
+	data, err := ioutil.ReadFile(credentialsFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	var account struct {
+		ClientEmail string `json:"client_email"`
 	}
+	if err := json.Unmarshal(data, &account); err != nil || account.ClientEmail == "" {
+		return "", errors.New("the credentials file does not contain a service account key")
+	}
+
+	creds, err := google.CredentialsFromJSON(context.Background(), data, cloudPlatformScope)
+	if err != nil {
+		return "", err
+	}
+	return generateAccessToken(oauth2.NewClient(context.Background(), creds.TokenSource), iamCredentialsURL, account.ClientEmail, expiration)
+}
+
+// generateAccessToken requests an access token of the service account with the given lifetime from the IAM Credentials API.
+func generateAccessToken(client *http.Client, baseURL, serviceAccount string, lifetime time.Duration) (string, error) {
+	body, err := json.Marshal(struct {
+		Scope    []string `json:"scope"`
+		Lifetime string   `json:"lifetime"`
+	}{
+		Scope:    []string{cloudPlatformScope},
+		Lifetime: fmt.Sprintf("%ds", int64(lifetime/time.Second)),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Post(fmt.Sprintf("%s/v1/projects/-/serviceAccounts/%s:generateAccessToken", baseURL, url.PathEscape(serviceAccount)),
+		"application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("generating the access token failed: %s", resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"accessToken"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", errors.Wrap(err, "could not decode the access token")
+	}
+	return token.AccessToken, nil
 }
 
 func (g *gcpProvisioner) validateInputs(cluster *types.Cluster, provider *types.Provider) error {
@@ -160,6 +249,9 @@ func (g *gcpProvisioner) validateInputs(cluster *types.Cluster, provider *types.
 		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.ProjectName")
 	}
 
+	errMessage += nodepool.Validate(provider.CustomConfigurations)
+	errMessage += validateClusterOptions(provider.CustomConfigurations)
+
 	if errMessage != "" {
 		return errors.New("input validation failed with the following information: " + errMessage)
 	}
@@ -167,6 +259,58 @@ func (g *gcpProvisioner) validateInputs(cluster *types.Cluster, provider *types.
 	return nil
 }
 
+// validateClusterOptions checks the private cluster, VPC-native networking, workload identity, and release channel custom configurations
+// and returns the validation errors in the errs format.
+func validateClusterOptions(cfg map[string]interface{}) string {
+	var errMessage string
+
+	for _, key := range []string{"private_cluster", "private_endpoint", "vpc_native", "workload_identity"} {
+		if v, ok := cfg[key]; ok {
+			if _, ok := v.(bool); !ok {
+				errMessage += fmt.Sprintf(errs.Custom, fmt.Sprintf("Provider.CustomConfigurations['%s'] must be a boolean", key))
+			}
+		}
+	}
+
+	for _, key := range []string{"master_ipv4_cidr", "pods_cidr", "services_cidr"} {
+		if v, ok := cfg[key]; ok {
+			if cidr, ok := v.(string); !ok || !isCIDR(cidr) {
+				errMessage += fmt.Sprintf(errs.Custom, fmt.Sprintf("Provider.CustomConfigurations['%s'] must be a CIDR range", key))
+			}
+		}
+	}
+
+	if cfg["private_cluster"] == true {
+		if _, ok := cfg["master_ipv4_cidr"]; !ok {
+			errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CustomConfigurations['master_ipv4_cidr']")
+		}
+	} else if cfg["private_endpoint"] == true {
+		errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['private_endpoint'] requires a private cluster")
+	}
+
+	if v, ok := cfg["master_authorized_networks"]; ok {
+		networks, ok := v.([]string)
+		valid := ok
+		for _, network := range networks {
+			valid = valid && isCIDR(network)
+		}
+		if !valid {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['master_authorized_networks'] must be a list of CIDR ranges")
+		}
+	}
+
+	if v, ok := cfg["release_channel"]; ok && v != "RAPID" && v != "REGULAR" && v != "STABLE" {
+		errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfigurations['release_channel'] has to be one of: RAPID, REGULAR, STABLE")
+	}
+
+	return errMessage
+}
+
+func isCIDR(s string) bool {
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+}
+
 func (g *gcpProvisioner) loadConfigurations(cluster *types.Cluster, provider *types.Provider) map[string]interface{} {
 	config := map[string]interface{}{}
 	config["cluster_name"] = cluster.Name
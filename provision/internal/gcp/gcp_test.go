@@ -1,8 +1,12 @@
 package gcp
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/states/statefile"
 	"github.com/kyma-incubator/hydroform/provision/internal/operator/mocks"
@@ -92,6 +96,36 @@ func TestValidateInputs(t *testing.T) {
 	require.Error(t, g.validateInputs(cluster, provider), "Validation should fail when disk type is empty")
 }
 
+func TestValidateClusterOptions(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, validateClusterOptions(map[string]interface{}{}))
+	require.Empty(t, validateClusterOptions(map[string]interface{}{
+		"private_cluster":            true,
+		"private_endpoint":           true,
+		"master_ipv4_cidr":           "172.16.0.0/28",
+		"master_authorized_networks": []string{"10.0.0.0/8", "192.168.1.0/24"},
+		"vpc_native":                 true,
+		"pods_cidr":                  "10.4.0.0/14",
+		"services_cidr":              "10.0.32.0/20",
+		"workload_identity":          true,
+		"release_channel":            "STABLE",
+	}))
+
+	for name, cfg := range map[string]map[string]interface{}{
+		"non boolean private cluster":         {"private_cluster": "true", "master_ipv4_cidr": "172.16.0.0/28"},
+		"private cluster without master CIDR": {"private_cluster": true},
+		"invalid master CIDR":                 {"private_cluster": true, "master_ipv4_cidr": "172.16.0.0"},
+		"private endpoint of public cluster":  {"private_endpoint": true},
+		"invalid pods CIDR":                   {"vpc_native": true, "pods_cidr": 14},
+		"invalid authorized network":          {"master_authorized_networks": []string{"10.0.0.0/8", "office"}},
+		"authorized network not in a list":    {"master_authorized_networks": "10.0.0.0/8"},
+		"unknown release channel":             {"release_channel": "NIGHTLY"},
+	} {
+		require.NotEmpty(t, validateClusterOptions(cfg), name)
+	}
+}
+
 func TestLoadConfigurations(t *testing.T) {
 	t.Parallel()
 	g := &gcpProvisioner{}
@@ -229,3 +263,31 @@ func TestDeprovision(t *testing.T) {
 	err = g.Deprovision(cluster, provider)
 	require.Error(t, err, "Deprovision should fail")
 }
+
+func TestGenerateAccessToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/projects/-/serviceAccounts/robot@my-project.iam.gserviceaccount.com:generateAccessToken", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Scope    []string `json:"scope"`
+			Lifetime string   `json:"lifetime"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Lifetime != "600s" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"accessToken": "my-token", "expireTime": "2021-04-01T12:10:00Z"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	token, err := generateAccessToken(http.DefaultClient, server.URL, "robot@my-project.iam.gserviceaccount.com", 10*time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "my-token", token)
+
+	_, err = generateAccessToken(http.DefaultClient, server.URL, "robot@my-project.iam.gserviceaccount.com", time.Hour)
+	require.Error(t, err, "Rejected lifetimes should fail")
+
+	_, err = accessToken("/path/to/credentials", 24*time.Hour)
+	require.Error(t, err, "Lifetimes above the maximum should fail")
+}
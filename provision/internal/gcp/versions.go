@@ -0,0 +1,71 @@
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kyma-incubator/hydroform/provision/internal/errs"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+)
+
+const containerAPI = "https://container.googleapis.com"
+
+// Capabilities returns the features Hydroform supports on GKE and the Kubernetes versions GKE offers in the location of the cluster.
+func (g *gcpProvisioner) Capabilities(cluster *types.Cluster, p *types.Provider) (*types.Capabilities, error) {
+	var errMessage string
+	if cluster.Location == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Cluster.Location")
+	}
+	if p.ProjectName == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.ProjectName")
+	}
+	if p.CredentialsFilePath == "" {
+		errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CredentialsFilePath")
+	}
+	if errMessage != "" {
+		return nil, errors.New("input validation failed with the following information: " + errMessage)
+	}
+
+	token, err := accessToken(p.CredentialsFilePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get an access token for the service account")
+	}
+	versions, err := kubernetesVersions(containerAPI, token, p.ProjectName, cluster.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Capabilities{
+		Autoscaling:        true,
+		SpotInstances:      true,
+		KubernetesVersions: versions,
+	}, nil
+}
+
+// kubernetesVersions returns the control plane versions GKE offers in the given zone or region.
+func kubernetesVersions(apiURL, token, project, location string) ([]string, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/projects/%s/locations/%s/serverConfig", apiURL, project, location), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get the GKE server configuration")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("could not get the GKE server configuration: %s", resp.Status)
+	}
+
+	var serverConfig struct {
+		ValidMasterVersions []string `json:"validMasterVersions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&serverConfig); err != nil {
+		return nil, errors.Wrap(err, "could not decode the GKE server configuration")
+	}
+	return serverConfig.ValidMasterVersions, nil
+}
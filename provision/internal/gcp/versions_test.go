@@ -0,0 +1,37 @@
+package gcp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesVersions(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v1/projects/my-project/locations/europe-west3-a/serverConfig" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"defaultClusterVersion": "1.17.14-gke.1600", "validMasterVersions": ["1.18.12-gke.1210", "1.17.14-gke.1600"]}`)
+	}))
+	defer server.Close()
+
+	versions, err := kubernetesVersions(server.URL, "my-token", "my-project", "europe-west3-a")
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.18.12-gke.1210", "1.17.14-gke.1600"}, versions)
+
+	_, err = kubernetesVersions(server.URL, "other-token", "my-project", "europe-west3-a")
+	require.Error(t, err, "Unauthorized requests should fail")
+
+	_, err = kubernetesVersions(server.URL, "my-token", "my-project", "mars-north1")
+	require.Error(t, err, "Unknown locations should fail")
+}
@@ -0,0 +1,192 @@
+package k3d
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/kyma-incubator/hydroform/provision/internal/errs"
+	"github.com/kyma-incubator/hydroform/provision/internal/operator"
+	k3d_operator "github.com/kyma-incubator/hydroform/provision/internal/operator/k3d"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+)
+
+// k3dProvisioner implements Provisioner
+type k3dProvisioner struct {
+	provisionOperator operator.Operator
+	// kubeconfig returns the kubeconfig of the cluster with the given name
+	kubeconfig func(name string) ([]byte, error)
+}
+
+// Provision requests provisioning of a new local Kubernetes cluster on k3d with the given configurations.
+func (k *k3dProvisioner) Provision(cluster *types.Cluster, p *types.Provider) (*types.Cluster, error) {
+	if err := k.validateInputs(cluster, p); err != nil {
+		return cluster, err
+	}
+
+	config := k.loadConfigurations(cluster, p)
+
+	clusterInfo, err := k.provisionOperator.Create(p.Type, config)
+	if err != nil {
+		return cluster, errors.Wrap(err, "unable to provision k3d cluster")
+	}
+
+	cluster.ClusterInfo = clusterInfo
+	return cluster, nil
+}
+
+// Status returns the ClusterStatus for the requested cluster.
+func (k *k3dProvisioner) Status(cluster *types.Cluster, p *types.Provider) (*types.ClusterStatus, error) {
+	if err := k.validateInputs(cluster, p); err != nil {
+		return nil, err
+	}
+
+	cfg := k.loadConfigurations(cluster, p)
+
+	return k.provisionOperator.Status(nil, p.Type, cfg)
+}
+
+// Credentials returns the kubeconfig of the cluster as a byte array, as issued by k3d.
+func (k *k3dProvisioner) Credentials(cluster *types.Cluster, p *types.Provider) ([]byte, error) {
+	if err := k.validateInputs(cluster, p); err != nil {
+		return nil, err
+	}
+	return k.kubeconfig(cluster.Name)
+}
+
+// Deprovision requests deprovisioning of an existing cluster on k3d with the given configurations.
+func (k *k3dProvisioner) Deprovision(cluster *types.Cluster, p *types.Provider) error {
+	if err := k.validateInputs(cluster, p); err != nil {
+		return err
+	}
+
+	config := k.loadConfigurations(cluster, p)
+
+	if err := k.provisionOperator.Delete(nil, p.Type, config); err != nil {
+		return errors.Wrap(err, "unable to deprovision k3d cluster")
+	}
+
+	return nil
+}
+
+// Capabilities returns the features Hydroform supports on k3d. The Kubernetes version is determined by the k3s image.
+func (k *k3dProvisioner) Capabilities(cluster *types.Cluster, p *types.Provider) (*types.Capabilities, error) {
+	return &types.Capabilities{}, nil
+}
+
+// New creates a new instance of k3dProvisioner. k3d clusters are always managed with the k3d CLI, the operator type is ignored.
+func New(operatorType operator.Type, ops ...types.Option) *k3dProvisioner {
+	// parse config
+	os := &types.Options{}
+	for _, o := range ops {
+		o(os)
+	}
+
+	op := k3d_operator.New(os)
+	return &k3dProvisioner{
+		provisionOperator: op,
+		kubeconfig:        op.Kubeconfig,
+	}
+}
+
+func (k *k3dProvisioner) validateInputs(cluster *types.Cluster, provider *types.Provider) error {
+	var errMessage string
+	// k3d prefixes the names of the containers with k3d- and the cluster name, they have to be valid host names.
+	if match, _ := regexp.MatchString(`^(?:[a-z](?:[-a-z0-9]{0,30}[a-z0-9])?)$`, cluster.Name); !match {
+		errMessage += fmt.Sprintf(errs.Custom, "Cluster.Name must start with a lowercase letter followed by up to 31 lowercase letters, "+
+			"numbers, or hyphens, and cannot end with a hyphen")
+	}
+	if cluster.NodeCount < 0 {
+		errMessage += fmt.Sprintf(errs.CannotBeLess, "Cluster.NodeCount", 0)
+	}
+
+	if v, ok := provider.CustomConfigurations["port_mappings"]; ok {
+		if _, ok := stringList(v); !ok {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfiguration.port_mappings must be a list of hostPort:containerPort entries")
+		}
+	}
+	if v, ok := provider.CustomConfigurations["registry_mirrors"]; ok {
+		if _, ok := stringMap(v); !ok {
+			errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfiguration.registry_mirrors must be a map of registries to mirror endpoints")
+		}
+	}
+
+	if errMessage != "" {
+		return errors.New("input validation failed with the following information: " + errMessage)
+	}
+
+	return nil
+}
+
+func (k *k3dProvisioner) loadConfigurations(cluster *types.Cluster, p *types.Provider) map[string]interface{} {
+	config := map[string]interface{}{}
+	config["cluster_name"] = cluster.Name
+	// the server of the cluster is a node as well, the other nodes are agents
+	if cluster.NodeCount > 1 {
+		config["agents"] = cluster.NodeCount - 1
+	}
+	for k, v := range p.CustomConfigurations {
+		config[k] = v
+	}
+	// configurations decoded from JSON or YAML contain lists and maps of interfaces, which the k3d operator reads as strings
+	if mappings, ok := stringList(config["port_mappings"]); ok {
+		config["port_mappings"] = mappings
+	}
+	if mirrors, ok := stringMap(config["registry_mirrors"]); ok {
+		config["registry_mirrors"] = mirrors
+	}
+	return config
+}
+
+// stringList returns the value as list of strings if it is a []string or a []interface{} containing only strings
+func stringList(v interface{}) ([]string, bool) {
+	switch l := v.(type) {
+	case []string:
+		return l, true
+	case []interface{}:
+		list := make([]string, 0, len(l))
+		for _, item := range l {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, s)
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// stringMap returns the value as map of strings if it is a map[string]string, or a map[string]interface{}
+// or map[interface{}]interface{} containing only string keys and values
+func stringMap(v interface{}) (map[string]string, bool) {
+	switch m := v.(type) {
+	case map[string]string:
+		return m, true
+	case map[string]interface{}:
+		result := make(map[string]string, len(m))
+		for key, value := range m {
+			s, ok := value.(string)
+			if !ok {
+				return nil, false
+			}
+			result[key] = s
+		}
+		return result, true
+	case map[interface{}]interface{}:
+		result := make(map[string]string, len(m))
+		for key, value := range m {
+			k, ok := key.(string)
+			if !ok {
+				return nil, false
+			}
+			s, ok := value.(string)
+			if !ok {
+				return nil, false
+			}
+			result[k] = s
+		}
+		return result, true
+	}
+	return nil, false
+}
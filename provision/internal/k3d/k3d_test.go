@@ -0,0 +1,126 @@
+package k3d
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/states/statefile"
+	"github.com/kyma-incubator/hydroform/provision/internal/operator/mocks"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateInputs(t *testing.T) {
+	t.Parallel()
+	k := &k3dProvisioner{}
+
+	cluster := &types.Cluster{
+		Name:      "hydro-cluster",
+		NodeCount: 3,
+	}
+	provider := &types.Provider{
+		Type: types.K3d,
+		CustomConfigurations: map[string]interface{}{
+			"port_mappings": []string{"80:80", "443:443"},
+		},
+	}
+
+	require.NoError(t, k.validateInputs(cluster, provider), "Validation should pass")
+
+	cluster.Name = "Invalid_Name"
+	require.Error(t, k.validateInputs(cluster, provider), "Validation should fail when cluster name is invalid")
+	cluster.Name = "hydro-cluster"
+
+	cluster.NodeCount = -1
+	require.Error(t, k.validateInputs(cluster, provider), "Validation should fail when node count is negative")
+	cluster.NodeCount = 3
+
+	provider.CustomConfigurations["registry_mirrors"] = []string{"http://registry.localhost:5000"}
+	require.Error(t, k.validateInputs(cluster, provider), "Validation should fail when registry mirrors are not a map")
+	provider.CustomConfigurations["registry_mirrors"] = map[string]string{"docker.io": "http://registry.localhost:5000"}
+	require.NoError(t, k.validateInputs(cluster, provider), "Validation should pass with registry mirrors")
+
+	provider.CustomConfigurations["port_mappings"] = []interface{}{"80:80", "443:443"}
+	provider.CustomConfigurations["registry_mirrors"] = map[string]interface{}{"docker.io": "http://registry.localhost:5000"}
+	require.NoError(t, k.validateInputs(cluster, provider), "Validation should pass with port mappings and registry mirrors decoded from JSON")
+	provider.CustomConfigurations["registry_mirrors"] = map[interface{}]interface{}{"docker.io": "http://registry.localhost:5000"}
+	require.NoError(t, k.validateInputs(cluster, provider), "Validation should pass with registry mirrors decoded from YAML")
+	provider.CustomConfigurations["port_mappings"] = []interface{}{"80:80", 443}
+	require.Error(t, k.validateInputs(cluster, provider), "Validation should fail when port mappings are not strings")
+	provider.CustomConfigurations["port_mappings"] = []string{"80:80", "443:443"}
+	provider.CustomConfigurations["registry_mirrors"] = map[string]interface{}{"docker.io": 5000}
+	require.Error(t, k.validateInputs(cluster, provider), "Validation should fail when registry mirrors are not strings")
+	provider.CustomConfigurations["registry_mirrors"] = map[string]string{"docker.io": "http://registry.localhost:5000"}
+}
+
+func TestLoadConfigurations(t *testing.T) {
+	t.Parallel()
+	k := &k3dProvisioner{}
+
+	cluster := &types.Cluster{Name: "hydro-cluster", NodeCount: 3}
+	provider := &types.Provider{
+		Type:                 types.K3d,
+		CustomConfigurations: map[string]interface{}{"node_image": "rancher/k3s:v1.20.7-k3s1"},
+	}
+
+	config := k.loadConfigurations(cluster, provider)
+	require.Equal(t, "hydro-cluster", config["cluster_name"])
+	require.Equal(t, 2, config["agents"], "The server should count as a node")
+	require.Equal(t, "rancher/k3s:v1.20.7-k3s1", config["node_image"])
+
+	cluster.NodeCount = 1
+	require.NotContains(t, k.loadConfigurations(cluster, provider), "agents")
+	provider.CustomConfigurations["port_mappings"] = []interface{}{"80:80"}
+	provider.CustomConfigurations["registry_mirrors"] = map[interface{}]interface{}{"docker.io": "http://registry.localhost:5000"}
+	config = k.loadConfigurations(cluster, provider)
+	require.Equal(t, []string{"80:80"}, config["port_mappings"], "Port mappings should be converted to a list of strings")
+	require.Equal(t, map[string]string{"docker.io": "http://registry.localhost:5000"}, config["registry_mirrors"], "Registry mirrors should be converted to a map of strings")
+}
+
+func TestProvision(t *testing.T) {
+	t.Parallel()
+	mockOp := &mocks.Operator{}
+	k := k3dProvisioner{provisionOperator: mockOp}
+
+	cluster := &types.Cluster{Name: "hydro-cluster"}
+	provider := &types.Provider{Type: types.K3d}
+
+	result := &types.ClusterInfo{
+		Endpoint: "https://0.0.0.0:6443",
+		Status:   &types.ClusterStatus{Phase: types.Provisioned},
+	}
+	mockOp.On("Create", types.K3d, k.loadConfigurations(cluster, provider)).Return(result, nil)
+
+	cluster, err := k.Provision(cluster, provider)
+	require.NoError(t, err, "Provision should succeed")
+	require.Equal(t, result, cluster.ClusterInfo)
+}
+
+func TestCredentials(t *testing.T) {
+	t.Parallel()
+	k := k3dProvisioner{
+		kubeconfig: func(name string) ([]byte, error) {
+			return []byte("kubeconfig of " + name), nil
+		},
+	}
+
+	content, err := k.Credentials(&types.Cluster{Name: "hydro-cluster"}, &types.Provider{Type: types.K3d})
+	require.NoError(t, err)
+	require.Equal(t, "kubeconfig of hydro-cluster", string(content))
+}
+
+func TestDeprovision(t *testing.T) {
+	t.Parallel()
+	mockOp := &mocks.Operator{}
+	k := k3dProvisioner{provisionOperator: mockOp}
+
+	cluster := &types.Cluster{Name: "hydro-cluster"}
+	provider := &types.Provider{Type: types.K3d}
+
+	var state *statefile.File
+	mockOp.On("Delete", state, types.K3d, k.loadConfigurations(cluster, provider)).Return(nil).Once()
+	require.NoError(t, k.Deprovision(cluster, provider), "Deprovision should succeed")
+
+	mockOp.On("Delete", state, types.K3d, k.loadConfigurations(cluster, provider)).Return(errors.New("docker is not running"))
+	require.Error(t, k.Deprovision(cluster, provider), "Deprovision should fail")
+}
@@ -112,6 +112,11 @@ func (k *kindProvisioner) Deprovision(cluster *types.Cluster, p *types.Provider)
 	return nil
 }
 
+// Capabilities returns the features Hydroform supports on kind. The Kubernetes version is determined by the node image.
+func (k *kindProvisioner) Capabilities(cluster *types.Cluster, p *types.Provider) (*types.Capabilities, error) {
+	return &types.Capabilities{}, nil
+}
+
 // New creates a new instance of gcpProvisioner.
 func New(operatorType operator.Type, ops ...types.Option) *kindProvisioner {
 	// parse config
@@ -150,6 +155,16 @@ func (k *kindProvisioner) validateInputs(cluster *types.Cluster, provider *types
 		if _, ok := provider.CustomConfigurations["node_image"]; !ok {
 			errMessage += fmt.Sprintf(errs.CannotBeEmpty, "Provider.CustomConfiguration.node_image")
 		}
+		if v, ok := provider.CustomConfigurations["port_mappings"]; ok {
+			if _, ok := stringList(v); !ok {
+				errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfiguration.port_mappings must be a list of hostPort:containerPort entries")
+			}
+		}
+		if v, ok := provider.CustomConfigurations["registry_mirrors"]; ok {
+			if _, ok := stringMap(v); !ok {
+				errMessage += fmt.Sprintf(errs.Custom, "Provider.CustomConfiguration.registry_mirrors must be a map of registries to mirror endpoints")
+			}
+		}
 	}
 
 	if errMessage != "" {
@@ -166,5 +181,65 @@ func (k *kindProvisioner) loadConfigurations(cluster *types.Cluster, p *types.Pr
 	for k, v := range p.CustomConfigurations {
 		config[k] = v
 	}
+	// configurations decoded from JSON or YAML contain lists and maps of interfaces, which the cluster template reads as strings
+	if mappings, ok := stringList(config["port_mappings"]); ok {
+		config["port_mappings"] = mappings
+	}
+	if mirrors, ok := stringMap(config["registry_mirrors"]); ok {
+		config["registry_mirrors"] = mirrors
+	}
 	return config
 }
+
+// stringList returns the value as list of strings if it is a []string or a []interface{} containing only strings
+func stringList(v interface{}) ([]string, bool) {
+	switch l := v.(type) {
+	case []string:
+		return l, true
+	case []interface{}:
+		list := make([]string, 0, len(l))
+		for _, item := range l {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, s)
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// stringMap returns the value as map of strings if it is a map[string]string, or a map[string]interface{}
+// or map[interface{}]interface{} containing only string keys and values
+func stringMap(v interface{}) (map[string]string, bool) {
+	switch m := v.(type) {
+	case map[string]string:
+		return m, true
+	case map[string]interface{}:
+		result := make(map[string]string, len(m))
+		for key, value := range m {
+			s, ok := value.(string)
+			if !ok {
+				return nil, false
+			}
+			result[key] = s
+		}
+		return result, true
+	case map[interface{}]interface{}:
+		result := make(map[string]string, len(m))
+		for key, value := range m {
+			k, ok := key.(string)
+			if !ok {
+				return nil, false
+			}
+			s, ok := value.(string)
+			if !ok {
+				return nil, false
+			}
+			result[k] = s
+		}
+		return result, true
+	}
+	return nil, false
+}
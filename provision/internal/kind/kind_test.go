@@ -42,6 +42,28 @@ func TestValidateInputs(t *testing.T) {
 	require.Error(t, k.validateInputs(cluster, provider), "Validation should fail when project name is empty")
 	provider.ProjectName = "my-project"
 
+	provider.CustomConfigurations["port_mappings"] = "80:80"
+	require.Error(t, k.validateInputs(cluster, provider), "Validation should fail when port mappings are not a list")
+	provider.CustomConfigurations["port_mappings"] = []string{"80:80", "443:443"}
+	require.NoError(t, k.validateInputs(cluster, provider), "Validation should pass with port mappings")
+
+	provider.CustomConfigurations["registry_mirrors"] = []string{"http://registry.localhost:5000"}
+	require.Error(t, k.validateInputs(cluster, provider), "Validation should fail when registry mirrors are not a map")
+	provider.CustomConfigurations["registry_mirrors"] = map[string]string{"docker.io": "http://registry.localhost:5000"}
+	require.NoError(t, k.validateInputs(cluster, provider), "Validation should pass with registry mirrors")
+
+	provider.CustomConfigurations["port_mappings"] = []interface{}{"80:80", "443:443"}
+	provider.CustomConfigurations["registry_mirrors"] = map[string]interface{}{"docker.io": "http://registry.localhost:5000"}
+	require.NoError(t, k.validateInputs(cluster, provider), "Validation should pass with port mappings and registry mirrors decoded from JSON")
+	provider.CustomConfigurations["registry_mirrors"] = map[interface{}]interface{}{"docker.io": "http://registry.localhost:5000"}
+	require.NoError(t, k.validateInputs(cluster, provider), "Validation should pass with registry mirrors decoded from YAML")
+	provider.CustomConfigurations["port_mappings"] = []interface{}{"80:80", 443}
+	require.Error(t, k.validateInputs(cluster, provider), "Validation should fail when port mappings are not strings")
+	provider.CustomConfigurations["port_mappings"] = []string{"80:80", "443:443"}
+	provider.CustomConfigurations["registry_mirrors"] = map[string]interface{}{"docker.io": 5000}
+	require.Error(t, k.validateInputs(cluster, provider), "Validation should fail when registry mirrors are not strings")
+	provider.CustomConfigurations["registry_mirrors"] = map[string]string{"docker.io": "http://registry.localhost:5000"}
+
 	delete(provider.CustomConfigurations, "node_image")
 	require.Error(t, k.validateInputs(cluster, provider), "Validation should fail when target provider is empty")
 	provider.CustomConfigurations["target_provider"] = "somerepo/image:v0.0.0"
@@ -70,6 +92,12 @@ func TestLoadConfigurations(t *testing.T) {
 	for k, v := range provider.CustomConfigurations {
 		require.Equal(t, v, config[k], fmt.Sprintf("Custom config %s is incorrect", k))
 	}
+
+	provider.CustomConfigurations["port_mappings"] = []interface{}{"80:80"}
+	provider.CustomConfigurations["registry_mirrors"] = map[string]interface{}{"docker.io": "http://registry.localhost:5000"}
+	config = k.loadConfigurations(cluster, provider)
+	require.Equal(t, []string{"80:80"}, config["port_mappings"], "Port mappings should be converted to a list of strings")
+	require.Equal(t, map[string]string{"docker.io": "http://registry.localhost:5000"}, config["registry_mirrors"], "Registry mirrors should be converted to a map of strings")
 }
 
 func TestProvision(t *testing.T) {
@@ -0,0 +1,143 @@
+// Package lease stores the expiration of clusters provisioned with a TTL in the data directory.
+package lease
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	leasesDir     = "leases"
+	leaseFileExt  = ".json"
+	keySeparator  = "_"
+	defaultPrefix = "default"
+)
+
+// Store keeps one lease file per cluster in the leases directory of the data directory.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store for the leases in the given data directory.
+func NewStore(dataDir string) *Store {
+	return &Store{dir: filepath.Join(dataDir, leasesDir)}
+}
+
+// Put records the lease, replacing the lease of the same cluster if it exists.
+// The cluster info is not stored, it contains the cluster state, which is kept by the operator.
+func (s *Store) Put(l *types.ClusterLease) error {
+	cluster := *l.Cluster
+	cluster.ClusterInfo = nil
+	stored := *l
+	stored.Cluster = &cluster
+
+	data, err := json.MarshalIndent(&stored, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not encode the cluster lease")
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.file(l.Workspace, l.Provider, l.Cluster), data, 0600)
+}
+
+// Delete removes the lease of the cluster if it exists.
+func (s *Store) Delete(workspace string, provider *types.Provider, cluster *types.Cluster) error {
+	err := os.Remove(s.file(workspace, provider, cluster))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List returns all leases ordered by their expiration.
+func (s *Store) List() ([]*types.ClusterLease, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var leases []*types.ClusterLease
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != leaseFileExt {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(s.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		l := &types.ClusterLease{}
+		if err := json.Unmarshal(data, l); err != nil {
+			return nil, errors.Wrapf(err, "could not read the cluster lease %s", e.Name())
+		}
+		if l.Cluster == nil || l.Provider == nil {
+			return nil, errors.Errorf("the cluster lease %s is incomplete", e.Name())
+		}
+		l.Provider.CustomConfigurations = normalize(l.Provider.CustomConfigurations)
+		leases = append(leases, l)
+	}
+
+	sort.SliceStable(leases, func(i, j int) bool {
+		return leases[i].ExpiresAt.Before(leases[j].ExpiresAt)
+	})
+	return leases, nil
+}
+
+// file returns the lease file of the cluster, named after its workspace, provider, project, and name
+func (s *Store) file(workspace string, provider *types.Provider, cluster *types.Cluster) string {
+	if workspace == "" {
+		workspace = defaultPrefix
+	}
+	key := strings.Join([]string{workspace, string(provider.Type), provider.ProjectName, cluster.Name}, keySeparator)
+	return filepath.Join(s.dir, url.PathEscape(key)+leaseFileExt)
+}
+
+// normalize restores the types the provisioners expect from the custom configurations decoded from JSON:
+// lists and maps of strings, and integers.
+func normalize(cfg map[string]interface{}) map[string]interface{} {
+	for k, v := range cfg {
+		cfg[k] = normalizeValue(v)
+	}
+	return cfg
+}
+
+func normalizeValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case float64:
+		if t == float64(int(t)) {
+			return int(t)
+		}
+	case []interface{}:
+		list := make([]string, 0, len(t))
+		for _, item := range t {
+			s, ok := item.(string)
+			if !ok {
+				return v
+			}
+			list = append(list, s)
+		}
+		return list
+	case map[string]interface{}:
+		m := make(map[string]string, len(t))
+		for key, item := range t {
+			s, ok := item.(string)
+			if !ok {
+				return v
+			}
+			m[key] = s
+		}
+		return m
+	}
+	return v
+}
@@ -0,0 +1,73 @@
+package lease
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "leases")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := NewStore(dir)
+	leases, err := s.List()
+	require.NoError(t, err)
+	require.Empty(t, leases, "A data directory without leases should have no leases")
+
+	expiresAt := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	kind := &types.ClusterLease{
+		Cluster: &types.Cluster{Name: "ci-1234", ClusterInfo: &types.ClusterInfo{Endpoint: "https://127.0.0.1:6443"}},
+		Provider: &types.Provider{
+			Type:        types.Kind,
+			ProjectName: "ci",
+			CustomConfigurations: map[string]interface{}{
+				"port_mappings":    []string{"80:80"},
+				"registry_mirrors": map[string]string{"docker.io": "http://registry.localhost:5000"},
+				"node_count":       3,
+				"spot":             true,
+			},
+		},
+		Workspace: "ci",
+		ExpiresAt: expiresAt.Add(time.Hour),
+	}
+	gcp := &types.ClusterLease{
+		Cluster:   &types.Cluster{Name: "ci-1234"},
+		Provider:  &types.Provider{Type: types.GCP, ProjectName: "my/project"},
+		ExpiresAt: expiresAt,
+	}
+	require.NoError(t, s.Put(kind))
+	require.NoError(t, s.Put(gcp))
+	require.NotNil(t, kind.Cluster.ClusterInfo, "The lease of the caller should not be changed")
+
+	leases, err = s.List()
+	require.NoError(t, err)
+	require.Len(t, leases, 2)
+	require.Equal(t, types.GCP, leases[0].Provider.Type, "Leases should be ordered by their expiration")
+	require.Equal(t, "ci", leases[1].Workspace)
+	require.Nil(t, leases[1].Cluster.ClusterInfo, "The cluster info should not be stored")
+	require.Equal(t, kind.Provider.CustomConfigurations, leases[1].Provider.CustomConfigurations, "The custom configurations should keep their types")
+	require.True(t, leases[0].Expired(expiresAt))
+	require.False(t, leases[1].Expired(expiresAt))
+
+	// extending the TTL replaces the lease
+	gcp.ExpiresAt = expiresAt.Add(2 * time.Hour)
+	require.NoError(t, s.Put(gcp))
+	leases, err = s.List()
+	require.NoError(t, err)
+	require.Len(t, leases, 2)
+	require.Equal(t, types.GCP, leases[1].Provider.Type)
+
+	require.NoError(t, s.Delete("", gcp.Provider, gcp.Cluster))
+	require.NoError(t, s.Delete("", gcp.Provider, gcp.Cluster), "Deleting a missing lease should succeed")
+	leases, err = s.List()
+	require.NoError(t, err)
+	require.Len(t, leases, 1)
+}
@@ -0,0 +1,59 @@
+// Package nodepool validates the node pool configurations shared by the cloud providers.
+package nodepool
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/hydroform/provision/internal/errs"
+)
+
+const (
+	// Spot is the custom configuration to run the nodes on spot (preemptible on GCP) instances.
+	Spot = "spot"
+	// AutoscalingMin is the custom configuration for the minimum number of nodes when autoscaling.
+	AutoscalingMin = "autoscaling_min"
+	// AutoscalingMax is the custom configuration for the maximum number of nodes when autoscaling, autoscaling is enabled if set.
+	AutoscalingMax = "autoscaling_max"
+)
+
+// Validate checks the spot and autoscaling custom configurations and returns the validation errors in the errs format.
+func Validate(cfg map[string]interface{}) string {
+	var errMessage string
+
+	if v, ok := cfg[Spot]; ok {
+		if _, ok := v.(bool); !ok {
+			errMessage += fmt.Sprintf(errs.Custom, fmt.Sprintf("Provider.CustomConfigurations['%s'] must be a boolean", Spot))
+		}
+	}
+
+	min, minOk := cfg[AutoscalingMin]
+	max, maxOk := cfg[AutoscalingMax]
+	if !minOk && !maxOk {
+		return errMessage
+	}
+	if !maxOk {
+		return errMessage + fmt.Sprintf(errs.CannotBeEmpty, fmt.Sprintf("Provider.CustomConfigurations['%s']", AutoscalingMax))
+	}
+
+	maxNodes, ok := max.(int)
+	if !ok {
+		return errMessage + fmt.Sprintf(errs.Custom, fmt.Sprintf("Provider.CustomConfigurations['%s'] must be an integer", AutoscalingMax))
+	}
+	if maxNodes < 1 {
+		errMessage += fmt.Sprintf(errs.CannotBeLess, fmt.Sprintf("Provider.CustomConfigurations['%s']", AutoscalingMax), 1)
+	}
+	if minOk {
+		minNodes, ok := min.(int)
+		if !ok {
+			return errMessage + fmt.Sprintf(errs.Custom, fmt.Sprintf("Provider.CustomConfigurations['%s'] must be an integer", AutoscalingMin))
+		}
+		if minNodes < 0 {
+			errMessage += fmt.Sprintf(errs.CannotBeLess, fmt.Sprintf("Provider.CustomConfigurations['%s']", AutoscalingMin), 0)
+		}
+		if minNodes > maxNodes {
+			errMessage += fmt.Sprintf(errs.Custom, fmt.Sprintf("Provider.CustomConfigurations['%s'] cannot be greater than '%s'", AutoscalingMin, AutoscalingMax))
+		}
+	}
+
+	return errMessage
+}
@@ -0,0 +1,22 @@
+package nodepool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, Validate(map[string]interface{}{}), "No node pool configuration should pass")
+	require.Empty(t, Validate(map[string]interface{}{"spot": true, "autoscaling_min": 1, "autoscaling_max": 5}), "Valid configuration should pass")
+	require.Empty(t, Validate(map[string]interface{}{"autoscaling_max": 5}), "Minimum should be optional")
+
+	require.NotEmpty(t, Validate(map[string]interface{}{"spot": "yes"}), "Spot should be a boolean")
+	require.NotEmpty(t, Validate(map[string]interface{}{"autoscaling_min": 1}), "Maximum should be required with a minimum")
+	require.NotEmpty(t, Validate(map[string]interface{}{"autoscaling_max": "5"}), "Maximum should be an integer")
+	require.NotEmpty(t, Validate(map[string]interface{}{"autoscaling_max": 0}), "Maximum should be at least 1")
+	require.NotEmpty(t, Validate(map[string]interface{}{"autoscaling_min": -1, "autoscaling_max": 5}), "Minimum should not be negative")
+	require.NotEmpty(t, Validate(map[string]interface{}{"autoscaling_min": 6, "autoscaling_max": 5}), "Minimum should not exceed the maximum")
+}
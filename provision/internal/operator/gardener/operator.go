@@ -0,0 +1,341 @@
+// Package gardener implements an operator which manages Gardener clusters directly via the Shoot resources of the Gardener API,
+// without Terraform and the Terraform provider for Gardener.
+package gardener
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/states/statefile"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	defaultCreateTimeout = 30 * time.Minute
+	defaultUpdateTimeout = 30 * time.Minute
+	defaultDeleteTimeout = 20 * time.Minute
+	defaultPollInterval  = 15 * time.Second
+
+	// deletionConfirmation is the annotation Gardener requires on a shoot before it can be deleted
+	deletionConfirmation = "confirmation.gardener.cloud/deletion"
+)
+
+// shootResource is the Gardener resource of the clusters
+var shootResource = schema.GroupVersionResource{Group: "core.gardener.cloud", Version: "v1beta1", Resource: "shoots"}
+
+// Gardener is an Operator which creates, updates, and deletes Shoot resources via the Gardener API.
+type Gardener struct {
+	timeouts     types.Timeouts
+	pollInterval time.Duration
+	// labels are added to the shoot, Gardener has no tags for the cloud resources of a shoot
+	labels map[string]string
+	// clients create the clients of the Gardener project from the kubeconfig file
+	clients func(kubeconfigPath string) (dynamic.Interface, kubernetes.Interface, error)
+}
+
+// New creates a new Gardener operator with the given options
+func New(ops *types.Options) *Gardener {
+	g := &Gardener{
+		pollInterval: defaultPollInterval,
+		labels:       ops.Tags,
+		clients:      newClients,
+	}
+	if ops.Timeouts != nil {
+		g.timeouts = *ops.Timeouts
+	}
+	if g.timeouts.Create == 0 {
+		g.timeouts.Create = defaultCreateTimeout
+	}
+	if g.timeouts.Update == 0 {
+		g.timeouts.Update = defaultUpdateTimeout
+	}
+	if g.timeouts.Delete == 0 {
+		g.timeouts.Delete = defaultDeleteTimeout
+	}
+	return g
+}
+
+func newClients(kubeconfigPath string) (dynamic.Interface, kubernetes.Interface, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dynamicClient, kubeClient, nil
+}
+
+// Create creates the shoot of the cluster, or updates its spec if the shoot already exists, and waits until Gardener reconciled it.
+// It returns the endpoint and the certificate authority of the cluster, or the errors Gardener reported for the shoot.
+func (g *Gardener) Create(p types.ProviderType, cfg map[string]interface{}) (*types.ClusterInfo, error) {
+	desired, err := newShoot(cfg)
+	if err != nil {
+		return nil, err
+	}
+	desired.Labels = g.labels
+	spec, err := toMap(desired.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, kubeClient, err := g.clients(stringValue(cfg, "credentials_file_path"))
+	if err != nil {
+		return nil, err
+	}
+	shoots := dynamicClient.Resource(shootResource).Namespace(desired.Namespace)
+
+	timeout := g.timeouts.Create
+	current, err := shoots.Get(context.Background(), desired.Name, metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		obj, err := toMap(desired)
+		if err != nil {
+			return nil, err
+		}
+		obj["spec"] = spec
+		if _, err := shoots.Create(context.Background(), &unstructured.Unstructured{Object: obj}, metav1.CreateOptions{}); err != nil {
+			return nil, errors.Wrapf(err, "could not create shoot %s", desired.Name)
+		}
+	case err != nil:
+		return nil, errors.Wrapf(err, "could not read shoot %s", desired.Name)
+	default:
+		timeout = g.timeouts.Update
+		currentSpec, _, err := unstructured.NestedMap(current.Object, "spec")
+		if err != nil {
+			return nil, err
+		}
+		mergeMaps(currentSpec, spec)
+		if err := unstructured.SetNestedMap(current.Object, currentSpec, "spec"); err != nil {
+			return nil, err
+		}
+		if len(g.labels) > 0 {
+			labels := current.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			for k, v := range g.labels {
+				labels[k] = v
+			}
+			current.SetLabels(labels)
+		}
+		if _, err := shoots.Update(context.Background(), current, metav1.UpdateOptions{}); err != nil {
+			return nil, errors.Wrapf(err, "could not update shoot %s", desired.Name)
+		}
+	}
+
+	if err := g.waitForReconciliation(shoots, desired.Name, timeout); err != nil {
+		return nil, err
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(desired.Namespace).Get(context.Background(), fmt.Sprintf("%s.kubeconfig", desired.Name), metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read the kubeconfig of shoot %s", desired.Name)
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(secret.Data["kubeconfig"])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid kubeconfig of shoot %s", desired.Name)
+	}
+	return &types.ClusterInfo{
+		Endpoint:                 config.Host,
+		CertificateAuthorityData: config.CAData,
+		InternalState:            &types.InternalState{},
+		Status:                   &types.ClusterStatus{Phase: types.Provisioned},
+	}, nil
+}
+
+// Status returns the phase of the shoot according to its last operation. The state is not used.
+func (g *Gardener) Status(state *statefile.File, p types.ProviderType, cfg map[string]interface{}) (*types.ClusterStatus, error) {
+	dynamicClient, _, err := g.clients(stringValue(cfg, "credentials_file_path"))
+	if err != nil {
+		return nil, err
+	}
+	obj, err := dynamicClient.Resource(shootResource).Namespace(stringValue(cfg, "namespace")).Get(context.Background(), stringValue(cfg, "cluster_name"), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return &types.ClusterStatus{Phase: types.Unknown}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status := newShootStatus(obj)
+	switch {
+	case status.failed() || status.state == "Error":
+		return &types.ClusterStatus{Phase: types.Errored}, nil
+	case status.succeeded():
+		return &types.ClusterStatus{Phase: types.Provisioned}, nil
+	default:
+		return &types.ClusterStatus{Phase: types.Unknown}, nil
+	}
+}
+
+// Delete confirms the deletion of the shoot, deletes it, and waits until Gardener removed it. The state is not used.
+func (g *Gardener) Delete(state *statefile.File, p types.ProviderType, cfg map[string]interface{}) error {
+	dynamicClient, _, err := g.clients(stringValue(cfg, "credentials_file_path"))
+	if err != nil {
+		return err
+	}
+	name := stringValue(cfg, "cluster_name")
+	shoots := dynamicClient.Resource(shootResource).Namespace(stringValue(cfg, "namespace"))
+
+	obj, err := shoots.Get(context.Background(), name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not read shoot %s", name)
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if annotations[deletionConfirmation] != "true" {
+		annotations[deletionConfirmation] = "true"
+		obj.SetAnnotations(annotations)
+		if _, err := shoots.Update(context.Background(), obj, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "could not confirm the deletion of shoot %s", name)
+		}
+	}
+	if err := shoots.Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrapf(err, "could not delete shoot %s", name)
+	}
+
+	return g.poll(g.timeouts.Delete, func() (bool, error) {
+		obj, err := shoots.Get(context.Background(), name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, errors.Wrapf(err, "could not read shoot %s", name)
+		}
+		if status := newShootStatus(obj); status.failed() {
+			return false, status.err(name)
+		}
+		return false, nil
+	}, fmt.Sprintf("timed out waiting for the deletion of shoot %s", name))
+}
+
+// waitForReconciliation waits until Gardener successfully reconciled the current generation of the shoot
+func (g *Gardener) waitForReconciliation(shoots dynamic.ResourceInterface, name string, timeout time.Duration) error {
+	var last *shootStatus
+	err := g.poll(timeout, func() (bool, error) {
+		obj, err := shoots.Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "could not read shoot %s", name)
+		}
+		last = newShootStatus(obj)
+		if last.failed() {
+			return false, last.err(name)
+		}
+		return last.succeeded(), nil
+	}, fmt.Sprintf("timed out waiting for the reconciliation of shoot %s", name))
+	if err != nil && last != nil && !last.failed() && (last.description != "" || len(last.errors) > 0) {
+		// errors of a shoot in the Error state are retried by Gardener until the timeout expires
+		return errors.Wrap(err, last.summary())
+	}
+	return err
+}
+
+// poll calls the condition until it is done, fails, or the timeout expires
+func (g *Gardener) poll(timeout time.Duration, condition func() (bool, error), timeoutMessage string) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := condition()
+		if err != nil || done {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return errors.New(timeoutMessage)
+		}
+		time.Sleep(g.pollInterval)
+	}
+}
+
+// shootStatus is the progress of the last operation Gardener performed on a shoot
+type shootStatus struct {
+	upToDate    bool
+	state       string
+	description string
+	errors      []string
+}
+
+func newShootStatus(obj *unstructured.Unstructured) *shootStatus {
+	observed, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	status := &shootStatus{upToDate: observed == obj.GetGeneration()}
+	status.state, _, _ = unstructured.NestedString(obj.Object, "status", "lastOperation", "state")
+	status.description, _, _ = unstructured.NestedString(obj.Object, "status", "lastOperation", "description")
+
+	lastErrors, _, _ := unstructured.NestedSlice(obj.Object, "status", "lastErrors")
+	for _, e := range lastErrors {
+		if m, ok := e.(map[string]interface{}); ok {
+			if description, ok := m["description"].(string); ok {
+				status.errors = append(status.errors, description)
+			}
+		}
+	}
+	return status
+}
+
+func (s *shootStatus) succeeded() bool {
+	return s.upToDate && s.state == "Succeeded"
+}
+
+// failed returns true if the last operation failed permanently. Gardener retries operations in the Error state.
+func (s *shootStatus) failed() bool {
+	return s.upToDate && s.state == "Failed"
+}
+
+// summary returns the errors of the last operation, or its description if there are none
+func (s *shootStatus) summary() string {
+	if len(s.errors) == 0 {
+		return fmt.Sprintf("last operation: %s", s.description)
+	}
+	return fmt.Sprintf("last errors: %s", strings.Join(s.errors, "; "))
+}
+
+// err reports the failure of the last operation
+func (s *shootStatus) err(name string) error {
+	return errors.Errorf("the last operation on shoot %s failed, %s", name, s.summary())
+}
+
+// toMap converts a typed object to its unstructured representation
+func toMap(obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// mergeMaps sets the values of src in dst. Nested maps are merged, all other values including lists are replaced.
+func mergeMaps(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcOk := value.(map[string]interface{})
+		dstMap, dstOk := dst[key].(map[string]interface{})
+		if srcOk && dstOk {
+			mergeMaps(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
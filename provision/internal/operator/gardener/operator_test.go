@@ -0,0 +1,286 @@
+package gardener
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"cluster_name":           "hydro-cluster",
+		"credentials_file_path":  "/path/to/credentials",
+		"namespace":              "garden-my-project",
+		"kubernetes_version":     "1.19.8",
+		"location":               "europe-west3",
+		"machine_type":           "n1-standard-4",
+		"disk_size":              30,
+		"disk_type":              "pd-standard",
+		"target_provider":        "gcp",
+		"target_profile":         "gcp",
+		"target_secret":          "secret-name",
+		"workercidr":             "10.250.0.0/19",
+		"networking_nodes":       "10.250.0.0/19",
+		"networking_type":        "calico",
+		"worker_max_surge":       4,
+		"worker_max_unavailable": "10%",
+		"worker_maximum":         4,
+		"worker_minimum":         "2",
+		"zones":                  []string{"europe-west3-b"},
+		"gcp_control_plane_zone": "europe-west3-b",
+	}
+}
+
+func TestNewShoot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GCP", func(t *testing.T) {
+		s, err := newShoot(testConfig())
+		require.NoError(t, err)
+
+		require.Equal(t, "hydro-cluster", s.Name)
+		require.Equal(t, "garden-my-project", s.Namespace)
+		require.Equal(t, "europe-west3", s.Spec.Region)
+		require.Equal(t, "1.19.8", s.Spec.Kubernetes.Version)
+		require.Nil(t, s.Spec.Hibernation)
+
+		w := s.Spec.Provider.Workers[0]
+		require.Equal(t, 2, w.Minimum)
+		require.Equal(t, 4, w.Maximum)
+		require.Equal(t, "4", w.MaxSurge.String())
+		require.Equal(t, "10%", w.MaxUnavailable.String())
+		require.Equal(t, volume{Type: "pd-standard", Size: "30Gi"}, w.Volume)
+		require.Nil(t, w.Machine.Image)
+
+		infra := s.Spec.Provider.InfrastructureConfig.(*gcpInfrastructureConfig)
+		require.Equal(t, "gcp.provider.extensions.gardener.cloud/v1alpha1", infra.APIVersion)
+		require.Equal(t, "10.250.0.0/19", infra.Networks.Workers)
+		require.Equal(t, "europe-west3-b", s.Spec.Provider.ControlPlaneConfig.(*gcpControlPlaneConfig).Zone)
+	})
+
+	t.Run("AWS", func(t *testing.T) {
+		cfg := testConfig()
+		cfg["target_provider"] = "aws"
+		cfg["vnetcidr"] = "10.250.0.0/16"
+		cfg["zones"] = []string{"eu-west-1a", "eu-west-1b"}
+		cfg["hibernation_start"] = "00 20 * * 1,2,3,4,5"
+
+		s, err := newShoot(cfg)
+		require.NoError(t, err)
+
+		infra := s.Spec.Provider.InfrastructureConfig.(*awsInfrastructureConfig)
+		require.Equal(t, "10.250.0.0/16", infra.Networks.VPC.CIDR)
+		require.Equal(t, []awsZone{
+			{Name: "eu-west-1a", Workers: "10.250.0.0/19", Public: "10.250.32.0/20", Internal: "10.250.48.0/20"},
+			{Name: "eu-west-1b", Workers: "10.250.64.0/19", Public: "10.250.96.0/20", Internal: "10.250.112.0/20"},
+		}, infra.Networks.Zones)
+		require.Nil(t, s.Spec.Provider.ControlPlaneConfig)
+		require.Equal(t, []hibernationSchedule{{Start: "00 20 * * 1,2,3,4,5", Location: "UTC"}}, s.Spec.Hibernation.Schedules)
+	})
+
+	t.Run("Invalid configuration", func(t *testing.T) {
+		cfg := testConfig()
+		cfg["worker_minimum"] = "two"
+		_, err := newShoot(cfg)
+		require.Error(t, err)
+
+		cfg = testConfig()
+		cfg["target_provider"] = "nimbus"
+		_, err = newShoot(cfg)
+		require.Error(t, err)
+	})
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("New shoot", func(t *testing.T) {
+		g, dynamicClient := newTestOperator(t)
+
+		go setLastOperation(t, dynamicClient, "Succeeded")
+		info, err := g.Create(types.Gardener, testConfig())
+		require.NoError(t, err)
+		require.Equal(t, "https://api.hydro-cluster.example.com", info.Endpoint)
+		require.Equal(t, []byte("My cert"), info.CertificateAuthorityData)
+		require.Equal(t, types.Provisioned, info.Status.Phase)
+
+		obj := getShoot(t, dynamicClient)
+		region, _, err := unstructured.NestedString(obj.Object, "spec", "region")
+		require.NoError(t, err)
+		require.Equal(t, "europe-west3", region)
+		workers, _, err := unstructured.NestedSlice(obj.Object, "spec", "provider", "workers")
+		require.NoError(t, err)
+		require.Len(t, workers, 1)
+	})
+
+	t.Run("Existing shoot", func(t *testing.T) {
+		existing := shootObject("Succeeded")
+		require.NoError(t, unstructured.SetNestedField(existing.Object, "evaluation", "spec", "purpose"))
+		require.NoError(t, unstructured.SetNestedField(existing.Object, "1.18.0", "spec", "kubernetes", "version"))
+		existing.SetLabels(map[string]string{"team": "hydro"})
+		g, dynamicClient := newTestOperator(t, existing)
+		g.labels = map[string]string{"owner": "jane"}
+
+		_, err := g.Create(types.Gardener, testConfig())
+		require.NoError(t, err)
+
+		obj := getShoot(t, dynamicClient)
+		require.Equal(t, map[string]string{"team": "hydro", "owner": "jane"}, obj.GetLabels(), "tags should be added to the existing labels")
+		version, _, err := unstructured.NestedString(obj.Object, "spec", "kubernetes", "version")
+		require.NoError(t, err)
+		require.Equal(t, "1.19.8", version)
+		purpose, _, err := unstructured.NestedString(obj.Object, "spec", "purpose")
+		require.NoError(t, err)
+		require.Equal(t, "evaluation", purpose, "fields which are not managed should be kept")
+	})
+
+	t.Run("Failed shoot", func(t *testing.T) {
+		g, _ := newTestOperator(t, shootObject("Failed", "quota exceeded for resource CPUS"))
+
+		_, err := g.Create(types.Gardener, testConfig())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "quota exceeded for resource CPUS")
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		g, _ := newTestOperator(t, shootObject("Error", "infrastructure is not ready"))
+		g.timeouts.Update = 10 * time.Millisecond
+
+		_, err := g.Create(types.Gardener, testConfig())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "infrastructure is not ready")
+	})
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	for state, phase := range map[string]types.Phase{
+		"Succeeded":  types.Provisioned,
+		"Processing": types.Unknown,
+		"Error":      types.Errored,
+		"Failed":     types.Errored,
+	} {
+		g, _ := newTestOperator(t, shootObject(state))
+		status, err := g.Status(nil, types.Gardener, testConfig())
+		require.NoError(t, err)
+		require.Equal(t, phase, status.Phase, "state %s", state)
+	}
+
+	g, _ := newTestOperator(t)
+	status, err := g.Status(nil, types.Gardener, testConfig())
+	require.NoError(t, err)
+	require.Equal(t, types.Unknown, status.Phase)
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	g, dynamicClient := newTestOperator(t, shootObject("Succeeded"))
+	require.NoError(t, g.Delete(nil, types.Gardener, testConfig()))
+
+	var confirmed bool
+	for _, action := range dynamicClient.Actions() {
+		if action.GetVerb() == "update" {
+			obj := action.(interface{ GetObject() runtime.Object }).GetObject().(*unstructured.Unstructured)
+			confirmed = obj.GetAnnotations()[deletionConfirmation] == "true"
+		}
+	}
+	require.True(t, confirmed, "the deletion should be confirmed")
+
+	_, err := dynamicClient.Resource(shootResource).Namespace("garden-my-project").Get(context.Background(), "hydro-cluster", metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+
+	// deleting a shoot which does not exist succeeds
+	require.NoError(t, g.Delete(nil, types.Gardener, testConfig()))
+}
+
+func newTestOperator(t *testing.T, objects ...runtime.Object) (*Gardener, *dynamicfake.FakeDynamicClient) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: shoot
+  cluster:
+    server: https://api.hydro-cluster.example.com
+    certificate-authority-data: %s
+contexts:
+- name: shoot
+  context:
+    cluster: shoot
+current-context: shoot
+`, base64.StdEncoding.EncodeToString([]byte("My cert")))
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "hydro-cluster.kubeconfig", Namespace: "garden-my-project"},
+		Data:       map[string][]byte{"kubeconfig": []byte(kubeconfig)},
+	})
+
+	g := New(&types.Options{})
+	g.pollInterval = time.Millisecond
+	g.clients = func(kubeconfigPath string) (dynamic.Interface, kubernetes.Interface, error) {
+		require.Equal(t, "/path/to/credentials", kubeconfigPath)
+		return dynamicClient, kubeClient, nil
+	}
+	return g, dynamicClient
+}
+
+// shootObject returns a shoot whose last operation is in the given state
+func shootObject(state string, lastErrors ...string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "core.gardener.cloud/v1beta1",
+		"kind":       "Shoot",
+		"metadata": map[string]interface{}{
+			"name":      "hydro-cluster",
+			"namespace": "garden-my-project",
+		},
+		"spec": map[string]interface{}{},
+	}}
+	setStatus(obj, state, lastErrors...)
+	return obj
+}
+
+func setStatus(obj *unstructured.Unstructured, state string, lastErrors ...string) {
+	var errs []interface{}
+	for _, e := range lastErrors {
+		errs = append(errs, map[string]interface{}{"description": e})
+	}
+	obj.Object["status"] = map[string]interface{}{
+		"lastOperation": map[string]interface{}{"state": state, "description": fmt.Sprintf("operation %s", state)},
+		"lastErrors":    errs,
+	}
+}
+
+// setLastOperation waits until the shoot is created and sets the state of its last operation
+func setLastOperation(t *testing.T, dynamicClient dynamic.Interface, state string) {
+	shoots := dynamicClient.Resource(shootResource).Namespace("garden-my-project")
+	for {
+		obj, err := shoots.Get(context.Background(), "hydro-cluster", metav1.GetOptions{})
+		if err == nil {
+			setStatus(obj, state)
+			_, err = shoots.Update(context.Background(), obj, metav1.UpdateOptions{})
+			require.NoError(t, err)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func getShoot(t *testing.T, dynamicClient dynamic.Interface) *unstructured.Unstructured {
+	obj, err := dynamicClient.Resource(shootResource).Namespace("garden-my-project").Get(context.Background(), "hydro-cluster", metav1.GetOptions{})
+	require.NoError(t, err)
+	return obj
+}
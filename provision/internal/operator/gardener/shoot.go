@@ -0,0 +1,312 @@
+package gardener
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// shoot is the part of the Gardener Shoot resource that Hydroform manages.
+// Fields which are not set here keep the values defaulted by Gardener when an existing shoot is updated.
+type shoot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              shootSpec `json:"spec"`
+}
+
+type shootSpec struct {
+	CloudProfileName  string       `json:"cloudProfileName"`
+	Region            string       `json:"region"`
+	SecretBindingName string       `json:"secretBindingName"`
+	Networking        networking   `json:"networking"`
+	Maintenance       maintenance  `json:"maintenance"`
+	Hibernation       *hibernation `json:"hibernation,omitempty"`
+	Provider          provider     `json:"provider"`
+	Kubernetes        kubernetes   `json:"kubernetes"`
+}
+
+type networking struct {
+	Type     string `json:"type"`
+	Nodes    string `json:"nodes,omitempty"`
+	Pods     string `json:"pods,omitempty"`
+	Services string `json:"services,omitempty"`
+}
+
+type maintenance struct {
+	AutoUpdate autoUpdate `json:"autoUpdate"`
+	TimeWindow timeWindow `json:"timeWindow"`
+}
+
+type autoUpdate struct {
+	KubernetesVersion   bool `json:"kubernetesVersion"`
+	MachineImageVersion bool `json:"machineImageVersion"`
+}
+
+type timeWindow struct {
+	Begin string `json:"begin"`
+	End   string `json:"end"`
+}
+
+type hibernation struct {
+	Schedules []hibernationSchedule `json:"schedules"`
+}
+
+type hibernationSchedule struct {
+	Start    string `json:"start,omitempty"`
+	End      string `json:"end,omitempty"`
+	Location string `json:"location"`
+}
+
+type provider struct {
+	Type                 string      `json:"type"`
+	ControlPlaneConfig   interface{} `json:"controlPlaneConfig,omitempty"`
+	InfrastructureConfig interface{} `json:"infrastructureConfig"`
+	Workers              []worker    `json:"workers"`
+}
+
+type worker struct {
+	Name           string              `json:"name"`
+	Machine        machine             `json:"machine"`
+	Minimum        int                 `json:"minimum"`
+	Maximum        int                 `json:"maximum"`
+	MaxSurge       *intstr.IntOrString `json:"maxSurge"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable"`
+	Volume         volume              `json:"volume"`
+	Zones          []string            `json:"zones,omitempty"`
+}
+
+type machine struct {
+	Type  string        `json:"type"`
+	Image *machineImage `json:"image,omitempty"`
+}
+
+type machineImage struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type volume struct {
+	Type string `json:"type"`
+	Size string `json:"size"`
+}
+
+type kubernetes struct {
+	AllowPrivilegedContainers bool   `json:"allowPrivilegedContainers"`
+	Version                   string `json:"version"`
+}
+
+// infrastructureConfig and controlPlaneConfig are the provider-specific configurations of the provider extensions
+
+type gcpInfrastructureConfig struct {
+	metav1.TypeMeta `json:",inline"`
+	Networks        struct {
+		Workers string `json:"workers"`
+	} `json:"networks"`
+}
+
+type gcpControlPlaneConfig struct {
+	metav1.TypeMeta `json:",inline"`
+	Zone            string `json:"zone"`
+}
+
+type awsInfrastructureConfig struct {
+	metav1.TypeMeta `json:",inline"`
+	Networks        struct {
+		VPC struct {
+			CIDR string `json:"cidr"`
+		} `json:"vpc"`
+		Zones []awsZone `json:"zones"`
+	} `json:"networks"`
+}
+
+type awsZone struct {
+	Name     string `json:"name"`
+	Workers  string `json:"workers"`
+	Public   string `json:"public"`
+	Internal string `json:"internal"`
+}
+
+type azureInfrastructureConfig struct {
+	metav1.TypeMeta `json:",inline"`
+	Networks        struct {
+		VNet struct {
+			CIDR string `json:"cidr"`
+		} `json:"vnet"`
+		Workers          string   `json:"workers"`
+		ServiceEndpoints []string `json:"serviceEndpoints,omitempty"`
+	} `json:"networks"`
+	Zoned bool `json:"zoned"`
+}
+
+type openStackInfrastructureConfig struct {
+	metav1.TypeMeta  `json:",inline"`
+	FloatingPoolName string `json:"floatingPoolName"`
+	Networks         struct {
+		Workers string           `json:"workers"`
+		Router  *openStackRouter `json:"router,omitempty"`
+	} `json:"networks"`
+}
+
+type openStackRouter struct {
+	ID string `json:"id"`
+}
+
+type openStackControlPlaneConfig struct {
+	metav1.TypeMeta      `json:",inline"`
+	LoadBalancerProvider string `json:"loadBalancerProvider"`
+}
+
+// extensionType returns the type meta of a configuration of the provider extension
+func extensionType(targetProvider, kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{APIVersion: fmt.Sprintf("%s.provider.extensions.gardener.cloud/v1alpha1", targetProvider), Kind: kind}
+}
+
+// newShoot builds the shoot from the configuration of the Gardener provisioner, which is the same as for the Terraform operator.
+func newShoot(cfg map[string]interface{}) (*shoot, error) {
+	targetProvider := stringValue(cfg, "target_provider")
+
+	s := &shoot{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "core.gardener.cloud/v1beta1", Kind: "Shoot"},
+		ObjectMeta: metav1.ObjectMeta{Name: stringValue(cfg, "cluster_name"), Namespace: stringValue(cfg, "namespace")},
+		Spec: shootSpec{
+			CloudProfileName:  stringValue(cfg, "target_profile"),
+			Region:            stringValue(cfg, "location"),
+			SecretBindingName: stringValue(cfg, "target_secret"),
+			Networking: networking{
+				Type:     stringValue(cfg, "networking_type"),
+				Nodes:    stringValue(cfg, "networking_nodes"),
+				Pods:     stringValue(cfg, "networking_pods"),
+				Services: stringValue(cfg, "networking_services"),
+			},
+			Maintenance: maintenance{
+				AutoUpdate: autoUpdate{KubernetesVersion: true, MachineImageVersion: true},
+				TimeWindow: timeWindow{Begin: "030000+0000", End: "040000+0000"},
+			},
+			Provider: provider{Type: targetProvider},
+			Kubernetes: kubernetes{
+				Version: stringValue(cfg, "kubernetes_version"),
+			},
+		},
+	}
+
+	if start := stringValue(cfg, "hibernation_start"); start != "" {
+		location := stringValue(cfg, "hibernation_location")
+		if location == "" {
+			location = "UTC"
+		}
+		s.Spec.Hibernation = &hibernation{Schedules: []hibernationSchedule{
+			{Start: start, End: stringValue(cfg, "hibernation_end"), Location: location},
+		}}
+	}
+
+	var err error
+	if privileged := stringValue(cfg, "privileged_containers"); privileged != "" {
+		if s.Spec.Kubernetes.AllowPrivilegedContainers, err = strconv.ParseBool(privileged); err != nil {
+			return nil, errors.Wrap(err, "invalid privileged_containers configuration")
+		}
+	}
+
+	w := worker{
+		Name:    "cpu-worker",
+		Machine: machine{Type: stringValue(cfg, "machine_type")},
+		Volume:  volume{Type: stringValue(cfg, "disk_type"), Size: fmt.Sprintf("%sGi", stringValue(cfg, "disk_size"))},
+		Zones:   stringsValue(cfg, "zones"),
+	}
+	if name := stringValue(cfg, "machine_image_name"); name != "" {
+		w.Machine.Image = &machineImage{Name: name, Version: stringValue(cfg, "machine_image_version")}
+	}
+	if w.Minimum, err = intValue(cfg, "worker_minimum"); err != nil {
+		return nil, err
+	}
+	if w.Maximum, err = intValue(cfg, "worker_maximum"); err != nil {
+		return nil, err
+	}
+	maxSurge := intstr.Parse(stringValue(cfg, "worker_max_surge"))
+	w.MaxSurge = &maxSurge
+	maxUnavailable := intstr.Parse(stringValue(cfg, "worker_max_unavailable"))
+	w.MaxUnavailable = &maxUnavailable
+	s.Spec.Provider.Workers = []worker{w}
+
+	switch targetProvider {
+	case string(types.GCP):
+		infra := &gcpInfrastructureConfig{TypeMeta: extensionType(targetProvider, "InfrastructureConfig")}
+		infra.Networks.Workers = stringValue(cfg, "workercidr")
+		s.Spec.Provider.InfrastructureConfig = infra
+		s.Spec.Provider.ControlPlaneConfig = &gcpControlPlaneConfig{
+			TypeMeta: extensionType(targetProvider, "ControlPlaneConfig"),
+			Zone:     stringValue(cfg, "gcp_control_plane_zone"),
+		}
+	case string(types.AWS):
+		infra := &awsInfrastructureConfig{TypeMeta: extensionType(targetProvider, "InfrastructureConfig")}
+		infra.Networks.VPC.CIDR = stringValue(cfg, "vnetcidr")
+		workerNets, publicNets, internalNets, err := AWSSubnets(infra.Networks.VPC.CIDR, len(w.Zones))
+		if err != nil {
+			return nil, errors.Wrap(err, "Error generating subnets for AWS zones")
+		}
+		for i, zone := range w.Zones {
+			infra.Networks.Zones = append(infra.Networks.Zones, awsZone{Name: zone, Workers: workerNets[i], Public: publicNets[i], Internal: internalNets[i]})
+		}
+		s.Spec.Provider.InfrastructureConfig = infra
+	case string(types.Azure):
+		infra := &azureInfrastructureConfig{TypeMeta: extensionType(targetProvider, "InfrastructureConfig"), Zoned: len(w.Zones) > 0}
+		infra.Networks.VNet.CIDR = stringValue(cfg, "vnetcidr")
+		infra.Networks.Workers = stringValue(cfg, "workercidr")
+		for _, endpoint := range stringsValue(cfg, "service_endpoints") {
+			if endpoint != "" {
+				infra.Networks.ServiceEndpoints = append(infra.Networks.ServiceEndpoints, endpoint)
+			}
+		}
+		s.Spec.Provider.InfrastructureConfig = infra
+	case string(types.OpenStack):
+		infra := &openStackInfrastructureConfig{TypeMeta: extensionType(targetProvider, "InfrastructureConfig"), FloatingPoolName: stringValue(cfg, "floating_pool_name")}
+		infra.Networks.Workers = stringValue(cfg, "workercidr")
+		if routerID := stringValue(cfg, "router_id"); routerID != "" {
+			infra.Networks.Router = &openStackRouter{ID: routerID}
+		}
+		s.Spec.Provider.InfrastructureConfig = infra
+		loadBalancerProvider := stringValue(cfg, "load_balancer_provider")
+		if loadBalancerProvider == "" {
+			loadBalancerProvider = "haproxy"
+		}
+		s.Spec.Provider.ControlPlaneConfig = &openStackControlPlaneConfig{
+			TypeMeta:             extensionType(targetProvider, "ControlPlaneConfig"),
+			LoadBalancerProvider: loadBalancerProvider,
+		}
+	default:
+		return nil, errors.Errorf("unsupported target provider %q", targetProvider)
+	}
+	return s, nil
+}
+
+// stringValue returns the configuration as string, or an empty string if it is not set.
+func stringValue(cfg map[string]interface{}, key string) string {
+	v, ok := cfg[key]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// stringsValue returns a list configuration. A single string is a list with one entry.
+func stringsValue(cfg map[string]interface{}, key string) []string {
+	switch v := cfg[key].(type) {
+	case []string:
+		return v
+	case string:
+		return []string{v}
+	}
+	return nil
+}
+
+// intValue returns a numeric configuration which can be given as number or as string.
+func intValue(cfg map[string]interface{}, key string) (int, error) {
+	i, err := strconv.Atoi(stringValue(cfg, key))
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s configuration", key)
+	}
+	return i, nil
+}
@@ -0,0 +1,203 @@
+// Package k3d implements an operator which manages local k3d clusters with the k3d CLI.
+// k3d runs k3s in Docker, so no cloud resources and no Terraform state are involved.
+package k3d
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/states/statefile"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const defaultCreateTimeout = 5 * time.Minute
+
+// K3d is an Operator which creates and deletes clusters with the k3d CLI, which has to be installed.
+type K3d struct {
+	createTimeout time.Duration
+	// run runs the k3d CLI with the given arguments and returns its standard output
+	run func(args ...string) ([]byte, error)
+}
+
+// New creates a new k3d operator with the given options
+func New(ops *types.Options) *K3d {
+	k := &K3d{
+		createTimeout: defaultCreateTimeout,
+		run:           runK3d,
+	}
+	if ops.Timeouts != nil && ops.Timeouts.Create != 0 {
+		k.createTimeout = ops.Timeouts.Create
+	}
+	return k
+}
+
+func runK3d(args ...string) ([]byte, error) {
+	out, err := exec.Command("k3d", args...).Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return out, errors.Errorf("k3d %s failed: %s", strings.Join(args, " "), strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return out, err
+}
+
+// clusterListEntry is the part of the cluster list of k3d which is used by the operator
+type clusterListEntry struct {
+	Name           string `json:"name"`
+	ServersCount   int    `json:"serversCount"`
+	ServersRunning int    `json:"serversRunning"`
+}
+
+// Create creates the cluster unless it already exists, and returns its endpoint and certificate authority.
+// The kubeconfig of the cluster is not merged into the default kubeconfig, use Kubeconfig to get it.
+func (k *K3d) Create(p types.ProviderType, cfg map[string]interface{}) (*types.ClusterInfo, error) {
+	name, _ := cfg["cluster_name"].(string)
+
+	cluster, err := k.cluster(name)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		args, cleanup, err := k.createArgs(cfg)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		if _, err := k.run(args...); err != nil {
+			return nil, errors.Wrapf(err, "could not create k3d cluster %s", name)
+		}
+	}
+
+	kubeconfig, err := k.Kubeconfig(name)
+	if err != nil {
+		return nil, err
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid kubeconfig of k3d cluster %s", name)
+	}
+	return &types.ClusterInfo{
+		Endpoint:                 config.Host,
+		CertificateAuthorityData: config.CAData,
+		InternalState:            &types.InternalState{},
+		Status:                   &types.ClusterStatus{Phase: types.Provisioned},
+	}, nil
+}
+
+// Status returns Provisioned if all servers of the cluster are running. The state is not used.
+func (k *K3d) Status(state *statefile.File, p types.ProviderType, cfg map[string]interface{}) (*types.ClusterStatus, error) {
+	name, _ := cfg["cluster_name"].(string)
+	cluster, err := k.cluster(name)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil || cluster.ServersRunning == 0 {
+		return &types.ClusterStatus{Phase: types.Unknown}, nil
+	}
+	if cluster.ServersRunning < cluster.ServersCount {
+		return &types.ClusterStatus{Phase: types.Errored}, nil
+	}
+	return &types.ClusterStatus{Phase: types.Provisioned}, nil
+}
+
+// Delete deletes the cluster if it exists. The state is not used.
+func (k *K3d) Delete(state *statefile.File, p types.ProviderType, cfg map[string]interface{}) error {
+	name, _ := cfg["cluster_name"].(string)
+	cluster, err := k.cluster(name)
+	if err != nil || cluster == nil {
+		return err
+	}
+	if _, err := k.run("cluster", "delete", name); err != nil {
+		return errors.Wrapf(err, "could not delete k3d cluster %s", name)
+	}
+	return nil
+}
+
+// Kubeconfig returns the kubeconfig of the cluster
+func (k *K3d) Kubeconfig(name string) ([]byte, error) {
+	kubeconfig, err := k.run("kubeconfig", "get", name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get the kubeconfig of k3d cluster %s", name)
+	}
+	return kubeconfig, nil
+}
+
+// cluster returns the cluster with the given name, or nil if it does not exist
+func (k *K3d) cluster(name string) (*clusterListEntry, error) {
+	out, err := k.run("cluster", "list", "-o", "json")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list the k3d clusters")
+	}
+	var clusters []clusterListEntry
+	if err := json.Unmarshal(out, &clusters); err != nil {
+		return nil, errors.Wrap(err, "could not decode the k3d clusters")
+	}
+	for i := range clusters {
+		if clusters[i].Name == name {
+			return &clusters[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// createArgs returns the arguments of the cluster creation, and a function removing the temporary registry config file
+func (k *K3d) createArgs(cfg map[string]interface{}) ([]string, func(), error) {
+	name, _ := cfg["cluster_name"].(string)
+	args := []string{"cluster", "create", name,
+		"--kubeconfig-update-default=false",
+		"--wait",
+		fmt.Sprintf("--timeout=%s", k.createTimeout),
+	}
+
+	if image, ok := cfg["node_image"].(string); ok && image != "" {
+		args = append(args, fmt.Sprintf("--image=%s", image))
+	}
+	if agents, ok := cfg["agents"].(int); ok && agents > 0 {
+		args = append(args, fmt.Sprintf("--agents=%d", agents))
+	}
+	// the ports are exposed on the load balancer of the cluster, which forwards them to all nodes
+	if mappings, ok := cfg["port_mappings"].([]string); ok {
+		for _, m := range mappings {
+			args = append(args, fmt.Sprintf("--port=%s@loadbalancer", m))
+		}
+	}
+
+	cleanup := func() {}
+	if mirrors, ok := cfg["registry_mirrors"].(map[string]string); ok && len(mirrors) > 0 {
+		dir, err := ioutil.TempDir("", "k3d")
+		if err != nil {
+			return nil, nil, err
+		}
+		cleanup = func() { os.RemoveAll(dir) }
+		file := filepath.Join(dir, "registries.yaml")
+		if err := ioutil.WriteFile(file, registriesConfig(mirrors), 0600); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		args = append(args, fmt.Sprintf("--registry-config=%s", file))
+	}
+	return args, cleanup, nil
+}
+
+// registriesConfig renders the registries.yaml of k3s, which configures the mirrors of the registries
+func registriesConfig(mirrors map[string]string) []byte {
+	var registries []string
+	for registry := range mirrors {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+
+	var b strings.Builder
+	b.WriteString("mirrors:\n")
+	for _, registry := range registries {
+		b.WriteString(fmt.Sprintf("  %q:\n    endpoint:\n    - %q\n", registry, mirrors[registry]))
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,120 @@
+package k3d
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// fakeK3d records the k3d commands and answers them like the k3d CLI with the given clusters
+type fakeK3d struct {
+	clusters string
+	commands [][]string
+	registry string
+}
+
+func (f *fakeK3d) run(args ...string) ([]byte, error) {
+	f.commands = append(f.commands, args)
+	switch strings.Join(args[:2], " ") {
+	case "cluster list":
+		return []byte(f.clusters), nil
+	case "cluster create":
+		for _, a := range args {
+			if strings.HasPrefix(a, "--registry-config=") {
+				data, err := ioutil.ReadFile(strings.TrimPrefix(a, "--registry-config="))
+				if err != nil {
+					return nil, err
+				}
+				f.registry = string(data)
+			}
+		}
+		return nil, nil
+	case "kubeconfig get":
+		config := api.NewConfig()
+		config.Clusters["k3d-hydro"] = &api.Cluster{Server: "https://0.0.0.0:6443", CertificateAuthorityData: []byte("My cert")}
+		config.AuthInfos["admin"] = &api.AuthInfo{Token: "token"}
+		config.Contexts["k3d-hydro"] = &api.Context{Cluster: "k3d-hydro", AuthInfo: "admin"}
+		config.CurrentContext = "k3d-hydro"
+		return clientcmd.Write(*config)
+	case "cluster delete":
+		return nil, nil
+	}
+	return nil, errors.New("unknown command")
+}
+
+func newTestOperator(clusters string) (*K3d, *fakeK3d) {
+	f := &fakeK3d{clusters: clusters}
+	k := New(&types.Options{})
+	k.run = f.run
+	return k, f
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("New cluster", func(t *testing.T) {
+		k, f := newTestOperator("[]")
+		info, err := k.Create(types.K3d, map[string]interface{}{
+			"cluster_name":     "hydro",
+			"node_image":       "rancher/k3s:v1.20.7-k3s1",
+			"agents":           2,
+			"port_mappings":    []string{"80:80", "443:443"},
+			"registry_mirrors": map[string]string{"docker.io": "http://registry.localhost:5000"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://0.0.0.0:6443", info.Endpoint)
+		require.Equal(t, []byte("My cert"), info.CertificateAuthorityData)
+		require.Equal(t, types.Provisioned, info.Status.Phase)
+
+		create := f.commands[1]
+		require.Equal(t, []string{"cluster", "create", "hydro"}, create[:3])
+		require.Contains(t, create, "--kubeconfig-update-default=false")
+		require.Contains(t, create, "--image=rancher/k3s:v1.20.7-k3s1")
+		require.Contains(t, create, "--agents=2")
+		require.Contains(t, create, "--port=443:443@loadbalancer")
+		require.Contains(t, f.registry, "\"docker.io\":\n    endpoint:\n    - \"http://registry.localhost:5000\"")
+	})
+
+	t.Run("Existing cluster", func(t *testing.T) {
+		k, f := newTestOperator(`[{"name": "hydro", "serversCount": 1, "serversRunning": 1}]`)
+		_, err := k.Create(types.K3d, map[string]interface{}{"cluster_name": "hydro"})
+		require.NoError(t, err)
+		for _, c := range f.commands {
+			require.NotEqual(t, "create", c[1], "Existing clusters should not be created again")
+		}
+	})
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	for clusters, phase := range map[string]types.Phase{
+		`[]`: types.Unknown,
+		`[{"name": "hydro", "serversCount": 1, "serversRunning": 1}]`: types.Provisioned,
+		`[{"name": "hydro", "serversCount": 3, "serversRunning": 1}]`: types.Errored,
+		`[{"name": "hydro", "serversCount": 1, "serversRunning": 0}]`: types.Unknown,
+	} {
+		k, _ := newTestOperator(clusters)
+		status, err := k.Status(nil, types.K3d, map[string]interface{}{"cluster_name": "hydro"})
+		require.NoError(t, err)
+		require.Equal(t, phase, status.Phase, clusters)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	k, f := newTestOperator(`[{"name": "hydro", "serversCount": 1, "serversRunning": 1}]`)
+	require.NoError(t, k.Delete(nil, types.K3d, map[string]interface{}{"cluster_name": "hydro"}))
+	require.Equal(t, []string{"cluster", "delete", "hydro"}, f.commands[1])
+
+	k, f = newTestOperator(`[]`)
+	require.NoError(t, k.Delete(nil, types.K3d, map[string]interface{}{"cluster_name": "hydro"}))
+	require.Len(t, f.commands, 1, "Missing clusters should not be deleted")
+}
@@ -25,4 +25,8 @@ type Type string
 const (
 	// TerraformOperator indicates the type of the operator is Terraform.
 	TerraformOperator Type = "terraform"
+	// GardenerOperator indicates the type of the operator is the Gardener API, which is only supported for Gardener clusters.
+	GardenerOperator Type = "gardener"
+	// K3dOperator indicates the type of the operator is the k3d CLI, which is only supported for k3d clusters.
+	K3dOperator Type = "k3d"
 )
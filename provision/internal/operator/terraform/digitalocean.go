@@ -0,0 +1,54 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const digitalOceanAPI = "https://api.digitalocean.com"
+
+// digitalOceanClusterID looks up the ID of the DigitalOcean cluster with the configured name.
+// DigitalOcean clusters can only be imported by their ID, which is not part of the configuration.
+func digitalOceanClusterID(apiURL string, cfg map[string]interface{}) (string, error) {
+	path, _ := cfg["credentials_file_path"].(string)
+	token, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "could not read the DigitalOcean token")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/kubernetes/clusters?per_page=200", apiURL), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "could not list the DigitalOcean clusters")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("could not list the DigitalOcean clusters: %s", resp.Status)
+	}
+
+	var list struct {
+		Clusters []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"kubernetes_clusters"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return "", errors.Wrap(err, "could not decode the DigitalOcean clusters")
+	}
+	for _, c := range list.Clusters {
+		if c.Name == cfg["cluster_name"] {
+			return c.ID, nil
+		}
+	}
+	return "", errors.Errorf("DigitalOcean cluster %s not found", cfg["cluster_name"])
+}
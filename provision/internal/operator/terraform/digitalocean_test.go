@@ -0,0 +1,42 @@
+package terraform
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigitalOceanClusterID(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"kubernetes_clusters": [{"id": "1b8b2100", "name": "other-cluster"}, {"id": "2c9c3211", "name": "my-cluster"}]}`)
+	}))
+	defer server.Close()
+
+	token, err := ioutil.TempFile("", "do-token")
+	require.NoError(t, err)
+	defer os.Remove(token.Name())
+	_, err = token.WriteString("my-token\n")
+	require.NoError(t, err)
+	require.NoError(t, token.Close())
+
+	id, err := digitalOceanClusterID(server.URL, map[string]interface{}{"cluster_name": "my-cluster", "credentials_file_path": token.Name()})
+	require.NoError(t, err)
+	require.Equal(t, "2c9c3211", id)
+
+	_, err = digitalOceanClusterID(server.URL, map[string]interface{}{"cluster_name": "unknown", "credentials_file_path": token.Name()})
+	require.Error(t, err, "Unknown clusters should not be found")
+
+	_, err = digitalOceanClusterID(server.URL, map[string]interface{}{"cluster_name": "my-cluster", "credentials_file_path": "/does/not/exist"})
+	require.Error(t, err, "Missing token should fail")
+}
@@ -4,16 +4,18 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
-	"net"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
 	"github.com/hashicorp/terraform/command/cliconfig"
 	"github.com/hashicorp/terraform/states/statefile"
+	gardener_operator "github.com/kyma-incubator/hydroform/provision/internal/operator/gardener"
 	"github.com/kyma-incubator/hydroform/provision/types"
 	"github.com/pkg/errors"
 )
@@ -23,11 +25,216 @@ const (
 	tfStateFile  = "terraform.tfstate"
 	tfModuleFile = "terraform.tf"
 	tfVarsFile   = "terraform.tfvars"
+	// file name for additional node pools of providers using modules
+	tfNodePoolFile = "nodepool.tf"
+	// file names for the cluster options of providers using modules, the override file is merged into the cluster of the module
+	tfOptionsFile  = "options.tf"
+	tfOverrideFile = "cluster_override.tf"
 	// TODO release modules and do not use master as ref when stable
 	azureMod = "git::https://github.com/kyma-incubator/terraform-modules//azurerm_kubernetes_cluster?ref=v0.0.3"
 
 	// TODO remove hardcoded TF templates once modules work
-	awsClusterTemplate = ``
+	awsClusterTemplate = `
+variable "project"					{
+	default = ""
+}
+variable "cluster_name"				{}
+variable "credentials_file_path"	{}
+variable "profile"					{
+	default = "default"
+}
+variable "location"					{}
+variable "node_count"				{}
+variable "machine_type"				{}
+variable "kubernetes_version"		{}
+variable "disk_size"				{}
+variable "vpc_id"					{
+	default = ""
+}
+variable "subnet_ids"				{
+	default = []
+}
+variable "vpc_cidr"					{
+	default = "10.0.0.0/16"
+}
+variable "spot"						{
+	default = false
+}
+variable "autoscaling_min"			{
+	default = 0
+}
+variable "autoscaling_max"			{
+	default = 0
+}
+variable "resource_tags"			{
+	default = {}
+}
+variable "create_timeout"			{}
+variable "update_timeout"			{}
+variable "delete_timeout"			{}
+
+provider "aws" {
+	shared_credentials_file = var.credentials_file_path
+	profile                 = var.profile
+	region                  = var.location
+}
+
+data "aws_availability_zones" "available" {
+	state = "available"
+}
+
+locals {
+	create_vpc = var.vpc_id == ""
+	subnet_ids = local.create_vpc ? aws_subnet.eks_subnet[*].id : var.subnet_ids
+}
+
+# VPC, only created if no existing VPC is given
+resource "aws_vpc" "eks_vpc" {
+	count                = local.create_vpc ? 1 : 0
+	cidr_block           = var.vpc_cidr
+	enable_dns_hostnames = true
+	enable_dns_support   = true
+
+	tags = merge(var.resource_tags, {
+		"Name"                                      = "${var.cluster_name}-vpc"
+		"kubernetes.io/cluster/${var.cluster_name}" = "shared"
+	})
+}
+
+resource "aws_subnet" "eks_subnet" {
+	count                   = local.create_vpc ? 2 : 0
+	vpc_id                  = aws_vpc.eks_vpc[0].id
+	cidr_block              = cidrsubnet(var.vpc_cidr, 4, count.index)
+	availability_zone       = data.aws_availability_zones.available.names[count.index]
+	map_public_ip_on_launch = true
+
+	tags = merge(var.resource_tags, {
+		"Name"                                      = "${var.cluster_name}-subnet-${count.index}"
+		"kubernetes.io/cluster/${var.cluster_name}" = "shared"
+		"kubernetes.io/role/elb"                    = "1"
+	})
+}
+
+resource "aws_internet_gateway" "eks_gateway" {
+	count  = local.create_vpc ? 1 : 0
+	vpc_id = aws_vpc.eks_vpc[0].id
+	tags   = var.resource_tags
+}
+
+resource "aws_route_table" "eks_routes" {
+	count  = local.create_vpc ? 1 : 0
+	vpc_id = aws_vpc.eks_vpc[0].id
+	tags   = var.resource_tags
+
+	route {
+		cidr_block = "0.0.0.0/0"
+		gateway_id = aws_internet_gateway.eks_gateway[0].id
+	}
+}
+
+resource "aws_route_table_association" "eks_routes" {
+	count          = local.create_vpc ? 2 : 0
+	subnet_id      = aws_subnet.eks_subnet[count.index].id
+	route_table_id = aws_route_table.eks_routes[0].id
+}
+
+# IAM roles for the control plane and the worker nodes
+resource "aws_iam_role" "eks_cluster_role" {
+	name = "${var.cluster_name}-cluster"
+	tags = var.resource_tags
+
+	assume_role_policy = jsonencode({
+		Version = "2012-10-17"
+		Statement = [{
+			Effect    = "Allow"
+			Principal = { Service = "eks.amazonaws.com" }
+			Action    = "sts:AssumeRole"
+		}]
+	})
+}
+
+resource "aws_iam_role_policy_attachment" "eks_cluster_policy" {
+	policy_arn = "arn:aws:iam::aws:policy/AmazonEKSClusterPolicy"
+	role       = aws_iam_role.eks_cluster_role.name
+}
+
+resource "aws_iam_role" "eks_node_role" {
+	name = "${var.cluster_name}-node"
+	tags = var.resource_tags
+
+	assume_role_policy = jsonencode({
+		Version = "2012-10-17"
+		Statement = [{
+			Effect    = "Allow"
+			Principal = { Service = "ec2.amazonaws.com" }
+			Action    = "sts:AssumeRole"
+		}]
+	})
+}
+
+resource "aws_iam_role_policy_attachment" "eks_node_policy" {
+	for_each = toset([
+		"arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy",
+		"arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy",
+		"arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly",
+	])
+	policy_arn = each.value
+	role       = aws_iam_role.eks_node_role.name
+}
+
+resource "aws_eks_cluster" "eks_cluster" {
+	name     = var.cluster_name
+	role_arn = aws_iam_role.eks_cluster_role.arn
+	version  = var.kubernetes_version
+	tags     = var.resource_tags
+
+	vpc_config {
+		subnet_ids = local.subnet_ids
+	}
+
+	timeouts {
+		create = var.create_timeout
+		update = var.update_timeout
+		delete = var.delete_timeout
+	}
+
+	depends_on = [aws_iam_role_policy_attachment.eks_cluster_policy]
+}
+
+resource "aws_eks_node_group" "eks_node_group" {
+	cluster_name    = aws_eks_cluster.eks_cluster.name
+	node_group_name = "${var.cluster_name}-workers"
+	node_role_arn   = aws_iam_role.eks_node_role.arn
+	subnet_ids      = local.subnet_ids
+	instance_types  = [var.machine_type]
+	disk_size       = var.disk_size
+	capacity_type   = var.spot ? "SPOT" : "ON_DEMAND"
+	tags            = var.resource_tags
+
+	# managed node groups are tagged for the cluster autoscaler auto-discovery
+	scaling_config {
+		desired_size = var.node_count
+		min_size     = var.autoscaling_max > 0 ? var.autoscaling_min : var.node_count
+		max_size     = var.autoscaling_max > 0 ? var.autoscaling_max : var.node_count
+	}
+
+	timeouts {
+		create = var.create_timeout
+		update = var.update_timeout
+		delete = var.delete_timeout
+	}
+
+	depends_on = [aws_iam_role_policy_attachment.eks_node_policy]
+}
+
+output "endpoint" {
+	value = aws_eks_cluster.eks_cluster.endpoint
+}
+
+output "cluster_ca_certificate" {
+	value = aws_eks_cluster.eks_cluster.certificate_authority.0.data
+}
+`
 	gcpClusterTemplate = `
   variable "node_count"    		{}
   variable "cluster_name"  		{}
@@ -40,6 +247,51 @@ const (
   variable "create_timeout" 	{}
   variable "update_timeout" 	{}
   variable "delete_timeout" 	{}
+  variable "spot"				{
+		default = false
+  }
+  variable "autoscaling_min"	{
+		default = 0
+  }
+  variable "autoscaling_max"	{
+		default = 0
+  }
+  variable "private_cluster"	{
+		default = false
+  }
+  variable "private_endpoint"	{
+		default = false
+  }
+  variable "master_ipv4_cidr"	{
+		default = null
+  }
+  variable "master_authorized_networks"	{
+		default = []
+  }
+  variable "vpc_native"			{
+		default = false
+  }
+  variable "network"			{
+		default = null
+  }
+  variable "subnetwork"			{
+		default = null
+  }
+  variable "pods_cidr"			{
+		default = null
+  }
+  variable "services_cidr"		{
+		default = null
+  }
+  variable "workload_identity"	{
+		default = false
+  }
+  variable "release_channel"	{
+		default = ""
+  }
+  variable "resource_tags"		{
+		default = {}
+  }
 
   provider "google" {
     	credentials   = file("${var.credentials_file_path}")
@@ -49,15 +301,68 @@ const (
   resource "google_container_cluster" "gke_cluster" {
     	name               = var.cluster_name
     	location 	       = var.location
-    	initial_node_count = var.node_count
     	min_master_version = var.kubernetes_version
+{{ if not (index .Cfg "release_channel") }}
     	node_version       = var.kubernetes_version
-    
+{{ end }}
+		network            = var.network
+		subnetwork         = var.subnetwork
+{{ if index .Cfg "resource_tags" }}
+		# GKE adds the cluster labels to the compute resources of the cluster
+		resource_labels    = var.resource_tags
+{{ end }}{{ if or (index .Cfg "vpc_native") (index .Cfg "private_cluster") }}
+		# private clusters are always VPC-native
+		ip_allocation_policy {
+			cluster_ipv4_cidr_block  = var.pods_cidr
+			services_ipv4_cidr_block = var.services_cidr
+		}
+{{ end }}
+{{ if index .Cfg "private_cluster" }}
+		private_cluster_config {
+			enable_private_nodes    = true
+			enable_private_endpoint = var.private_endpoint
+			master_ipv4_cidr_block  = var.master_ipv4_cidr
+		}
+{{ end }}
+{{ if index .Cfg "master_authorized_networks" }}
+		master_authorized_networks_config {
+			dynamic "cidr_blocks" {
+				for_each = var.master_authorized_networks
+				content {
+					cidr_block = cidr_blocks.value
+				}
+			}
+		}
+{{ end }}
+{{ if index .Cfg "workload_identity" }}
+		workload_identity_config {
+			identity_namespace = "${var.project}.svc.id.goog"
+		}
+{{ end }}
+{{ if index .Cfg "release_channel" }}
+		# the nodes are upgraded automatically with the release channel
+		release_channel {
+			channel = var.release_channel
+		}
+{{ end }}
+{{ if index .Cfg "autoscaling_max" }}
+		# autoscaling is only available on separately managed node pools
+		remove_default_node_pool = true
+		initial_node_count       = 1
+{{ else }}
+    	initial_node_count = var.node_count
+
     node_config {
       	machine_type = var.machine_type
 		disk_size_gb = var.disk_size
+		preemptible  = var.spot
+{{ if index .Cfg "workload_identity" }}
+		workload_metadata_config {
+			node_metadata = "GKE_METADATA_SERVER"
+		}
+{{ end }}
     }
-
+{{ end }}
 	timeouts {
 		create = var.create_timeout
 		update = var.update_timeout
@@ -70,7 +375,39 @@ const (
       		}
     	}
   }
+{{ if index .Cfg "autoscaling_max" }}
+  resource "google_container_node_pool" "gke_node_pool" {
+		name               = "${var.cluster_name}-pool"
+		location           = var.location
+		cluster            = google_container_cluster.gke_cluster.name
+{{ if not (index .Cfg "release_channel") }}
+		version            = var.kubernetes_version
+{{ end }}
+		initial_node_count = var.node_count
+
+		autoscaling {
+			min_node_count = var.autoscaling_min
+			max_node_count = var.autoscaling_max
+		}
 
+		node_config {
+			machine_type = var.machine_type
+			disk_size_gb = var.disk_size
+			preemptible  = var.spot
+{{ if index .Cfg "workload_identity" }}
+			workload_metadata_config {
+				node_metadata = "GKE_METADATA_SERVER"
+			}
+{{ end }}
+		}
+
+		timeouts {
+			create = var.create_timeout
+			update = var.update_timeout
+			delete = var.delete_timeout
+		}
+  }
+{{ end }}
   output "endpoint" {
     value = google_container_cluster.gke_cluster.endpoint
   }
@@ -114,6 +451,15 @@ variable "gcp_control_plane_zone"		{}
 variable "zoned"      				{}
 variable "service_endpoints"		{}
 {{ end }}
+{{ if eq (index .Cfg "target_provider") "openstack" }}
+variable "floating_pool_name"		{}
+variable "load_balancer_provider"	{
+	default = "haproxy"
+}
+variable "router_id"				{
+	default = ""
+}
+{{ end }}
 variable "machine_type"  			{}
 variable "kubernetes_version"   	{}
 variable "disk_size" 				{}
@@ -130,6 +476,18 @@ variable "machine_image_version"	{}
 variable "privileged_containers"	{
 	default = "false"
 }
+variable "hibernation_start"		{
+	default = ""
+}
+variable "hibernation_end"			{
+	default = ""
+}
+variable "hibernation_location"		{
+	default = "UTC"
+}
+variable "resource_tags"			{
+	default = {}
+}
 
 
 provider "gardener" {
@@ -140,7 +498,9 @@ resource "gardener_shoot" "gardener_cluster" {
 	metadata {
 	  name      = var.cluster_name
 	  namespace = var.namespace
-  
+{{ if index .Cfg "resource_tags" }}
+	  labels    = var.resource_tags
+{{ end }}
 	}
 
 	timeouts {
@@ -169,7 +529,15 @@ resource "gardener_shoot" "gardener_cluster" {
           end = "040000+0000"
         }
       }
-      provider {
+{{ if index .Cfg "hibernation_start" }}
+      hibernation {
+        schedules {
+          start = var.hibernation_start
+          end = var.hibernation_end != "" ? var.hibernation_end : null
+          location = var.hibernation_location
+        }
+      }
+{{ end }}      provider {
         type = var.target_provider
 		{{ if eq (index .Cfg "target_provider") "gcp" }}
 			control_plane_config {
@@ -178,6 +546,13 @@ resource "gardener_shoot" "gardener_cluster" {
  				}
 			}
 		{{ end }}
+		{{ if eq (index .Cfg "target_provider") "openstack" }}
+			control_plane_config {
+				openstack {
+					load_balancer_provider = var.load_balancer_provider
+				}
+			}
+		{{ end }}
         infrastructure_config {
            {{ if eq (index .Cfg "target_provider") "azure" }}
 			  azure {
@@ -215,6 +590,19 @@ resource "gardener_shoot" "gardener_cluster" {
 					}
 				}
 		   {{ end }}
+		   {{ if eq (index .Cfg "target_provider") "openstack" }}
+				openstack {
+					floating_pool_name = var.floating_pool_name
+					networks {
+						workers = var.workercidr
+						{{ if index .Cfg "router_id" }}
+						router {
+							id = var.router_id
+						}
+						{{ end }}
+					}
+				}
+		   {{ end }}
         }
         worker {
          name = "cpu-worker"
@@ -243,6 +631,196 @@ resource "gardener_shoot" "gardener_cluster" {
 	  }
   }
 }
+`
+
+	// azureNodePoolTemplate adds an autoscaled user node pool to the cluster of the azure module.
+	// The system node pool of the module cannot run on spot instances, nor does it support autoscaling.
+	// The cluster is referenced by its address in the module, see clusterResource.
+	azureNodePoolTemplate = `
+variable "spot"				{
+	default = false
+}
+variable "autoscaling_min"	{
+	default = 0
+}
+variable "autoscaling_max"	{
+	default = 0
+}
+
+resource "azurerm_kubernetes_cluster_node_pool" "azure_node_pool" {
+	name                  = "userpool"
+	kubernetes_cluster_id = azurerm_kubernetes_cluster.azure_cluster.id
+	vm_size               = var.agent_vm_size
+	os_disk_size_gb       = var.agent_disk_size
+	node_count            = var.agent_count
+	mode                  = "User"
+
+	priority        = var.spot ? "Spot" : "Regular"
+	eviction_policy = var.spot ? "Delete" : null
+	spot_max_price  = var.spot ? -1 : null
+
+	enable_auto_scaling = var.autoscaling_max > 0
+	min_count           = var.autoscaling_max > 0 ? var.autoscaling_min : null
+	max_count           = var.autoscaling_max > 0 ? var.autoscaling_max : null
+{{ if index .Cfg "availability_zones" }}
+	availability_zones  = var.availability_zones
+{{ end }}
+{{ if index .Cfg "vnet_subnet_id" }}
+	vnet_subnet_id      = var.vnet_subnet_id
+{{ end }}
+{{ if index .Cfg "resource_tags" }}
+	tags                = var.resource_tags
+{{ end }}
+}
+`
+
+	// azureClusterOptionsTemplate declares the variables of the cluster options of the azure module.
+	azureClusterOptionsTemplate = `
+variable "aad_rbac"				{
+	default = false
+}
+variable "aad_admin_group_ids"	{
+	default = []
+}
+variable "managed_identity"		{
+	default = false
+}
+variable "managed_identity_id"	{
+	default = null
+}
+variable "availability_zones"	{
+	default = null
+}
+variable "vnet_subnet_id"		{
+	default = null
+}
+variable "service_cidr"			{
+	default = null
+}
+variable "dns_service_ip"		{
+	default = null
+}
+variable "docker_bridge_cidr"	{
+	default = null
+}
+variable "resource_tags"		{
+	default = {}
+}
+`
+
+	// azureClusterOverrideTemplate adds the cluster options to the cluster of the azure module.
+	// Nested blocks of an override file replace all blocks of the same type of the module.
+	azureClusterOverrideTemplate = `
+resource "azurerm_kubernetes_cluster" "azure_cluster" {
+{{ if index .Cfg "resource_tags" }}
+	tags = var.resource_tags
+{{ end }}
+{{ if index .Cfg "aad_rbac" }}
+	role_based_access_control {
+		enabled = true
+		azure_active_directory {
+			managed                = true
+			admin_group_object_ids = var.aad_admin_group_ids
+		}
+	}
+{{ end }}
+{{ if index .Cfg "managed_identity" }}
+	identity {
+		type                      = var.managed_identity_id != null ? "UserAssigned" : "SystemAssigned"
+		user_assigned_identity_id = var.managed_identity_id
+	}
+
+	# the cluster uses either a managed identity or the service principal
+	dynamic "service_principal" {
+		for_each = []
+		content {
+			client_id     = var.client_id
+			client_secret = var.client_secret
+		}
+	}
+{{ end }}
+{{ if or (index .Cfg "availability_zones") (index .Cfg "vnet_subnet_id") }}
+	# zones and subnet can only be set on creation, the default node pool keeps the settings of the module
+	default_node_pool {
+		name               = "agentpool"
+		node_count         = var.agent_count
+		vm_size            = var.agent_vm_size
+		os_disk_size_gb    = var.agent_disk_size
+		availability_zones = var.availability_zones
+		vnet_subnet_id     = var.vnet_subnet_id
+	}
+{{ end }}
+{{ if index .Cfg "vnet_subnet_id" }}
+	network_profile {
+		network_plugin     = "azure"
+		service_cidr       = var.service_cidr
+		dns_service_ip     = var.dns_service_ip
+		docker_bridge_cidr = var.docker_bridge_cidr
+	}
+{{ end }}
+}
+`
+
+	digitalOceanClusterTemplate = `
+variable "project"					{
+	default = ""
+}
+variable "cluster_name"				{}
+variable "credentials_file_path"	{}
+variable "location"					{}
+variable "kubernetes_version"		{}
+variable "machine_type"				{}
+variable "node_count"				{}
+variable "vpc_uuid"					{
+	default = null
+}
+variable "resource_tags"			{
+	default = {}
+}
+variable "create_timeout"			{}
+variable "update_timeout"			{}
+variable "delete_timeout"			{}
+
+provider "digitalocean" {
+	token = trimspace(file(var.credentials_file_path))
+}
+
+# DigitalOcean tags have no values, so each tag is added as "key:value"
+locals {
+	tags = [for k, v in var.resource_tags : "${k}:${v}"]
+}
+
+resource "digitalocean_kubernetes_cluster" "digitalocean_cluster" {
+	name     = var.cluster_name
+	region   = var.location
+	version  = var.kubernetes_version
+	vpc_uuid = var.vpc_uuid
+	tags     = local.tags
+
+	node_pool {
+		name       = "${var.cluster_name}-workers"
+		size       = var.machine_type
+		node_count = var.node_count
+		tags       = local.tags
+	}
+
+	timeouts {
+		create = var.create_timeout
+	}
+}
+
+output "endpoint" {
+	value = digitalocean_kubernetes_cluster.digitalocean_cluster.endpoint
+}
+
+output "cluster_ca_certificate" {
+	value = digitalocean_kubernetes_cluster.digitalocean_cluster.kube_config.0.cluster_ca_certificate
+}
+
+output "kube_config" {
+	value     = digitalocean_kubernetes_cluster.digitalocean_cluster.kube_config.0.raw_config
+	sensitive = true
+}
 `
 
 	kindClusterTemplate = `
@@ -258,7 +836,29 @@ provider "kind" {
 resource "kind" "kind-cluster" {
 	name       = "${var.cluster_name}"
 	node_image = "${var.node_image}"
-
+{{ if or .PortMappings .RegistryMirrors }}
+	kind_config = <<KINDCONFIG
+kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+{{- if .RegistryMirrors }}
+containerdConfigPatches:
+- |-
+{{- range $registry, $endpoint := .RegistryMirrors }}
+  [plugins."io.containerd.grpc.v1.cri".registry.mirrors."{{ $registry }}"]
+    endpoint = ["{{ $endpoint }}"]
+{{- end }}
+{{- end }}
+nodes:
+- role: control-plane
+{{- if .PortMappings }}
+  extraPortMappings:
+{{- range .PortMappings }}
+  - hostPort: {{ .HostPort }}
+    containerPort: {{ .ContainerPort }}
+{{- end }}
+{{- end }}
+KINDCONFIG
+{{ end }}
 	timeouts {
 		create = "${var.create_timeout}"
 		update = "${var.update_timeout}"
@@ -280,7 +880,11 @@ func initClusterFiles(dataDir string, p types.ProviderType, cfg map[string]inter
 	var data []byte
 	switch p {
 	case types.GCP:
-		data = []byte(gcpClusterTemplate)
+		t, err := expandTemplate("gcpCluster", gcpClusterTemplate, cfg)
+		if err != nil {
+			return err
+		}
+		data = []byte(t)
 	case types.Gardener:
 		t, err := expandGardenerClusterTemplate(cfg)
 		if err != nil {
@@ -288,11 +892,32 @@ func initClusterFiles(dataDir string, p types.ProviderType, cfg map[string]inter
 		}
 		data = []byte(t)
 	case types.Azure:
-		break
+		// the cluster comes from the azure module, only add the extra node pool for autoscaling.
+		// A node pool of the fixed node count would double the capacity of the cluster, as the system node pool cannot be removed.
+		if _, ok := cfg["autoscaling_max"]; ok {
+			t, err := expandTemplate("azureNodePool", azureNodePoolTemplate, cfg)
+			if err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, tfNodePoolFile), []byte(t), 0700); err != nil {
+				return err
+			}
+		} else if err := os.Remove(filepath.Join(dir, tfNodePoolFile)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := writeAzureClusterOptions(dir, cfg); err != nil {
+			return err
+		}
 	case types.AWS:
 		data = []byte(awsClusterTemplate)
 	case types.Kind:
-		data = []byte(kindClusterTemplate)
+		t, err := expandKindClusterTemplate(cfg)
+		if err != nil {
+			return err
+		}
+		data = []byte(t)
+	case types.DigitalOcean:
+		data = []byte(digitalOceanClusterTemplate)
 	}
 
 	if len(data) > 0 {
@@ -314,6 +939,10 @@ func initClusterFiles(dataDir string, p types.ProviderType, cfg map[string]inter
 			if _, err := vars.WriteString(fmt.Sprintf("%s = \"%s\"\n", k, t)); err != nil {
 				return err
 			}
+		case bool:
+			if _, err := vars.WriteString(fmt.Sprintf("%s = %t\n", k, t)); err != nil {
+				return err
+			}
 		case time.Duration:
 			if _, err := vars.WriteString(fmt.Sprintf("%s = \"%s\"\n", k, t.String())); err != nil {
 				return err
@@ -328,6 +957,16 @@ func initClusterFiles(dataDir string, p types.ProviderType, cfg map[string]inter
 			if _, err := vars.WriteString(fmt.Sprintf("%s = [%s]\n", k, b)); err != nil {
 				return err
 			}
+		case map[string]string:
+			var a []string
+			for key, v := range t {
+				a = append(a, fmt.Sprintf("\"%s\" = \"%s\"", key, v))
+			}
+			sort.Strings(a)
+			b := strings.Join(a, ", ")
+			if _, err := vars.WriteString(fmt.Sprintf("%s = {%s}\n", k, b)); err != nil {
+				return err
+			}
 		}
 
 	}
@@ -441,6 +1080,55 @@ func clusterDir(dataDir, project, cluster string, p types.ProviderType) (string,
 	return clDir, nil
 }
 
+// azureClusterOptions are the custom configurations of the azure module cluster which are added by the override file
+var azureClusterOptions = []string{"aad_rbac", "aad_admin_group_ids", "managed_identity", "managed_identity_id", "availability_zones", "vnet_subnet_id", "service_cidr", "dns_service_ip", "docker_bridge_cidr", "resource_tags"}
+
+// writeAzureClusterOptions adds the options and override files for the azure cluster options, or removes them if no options are set
+func writeAzureClusterOptions(dir string, cfg map[string]interface{}) error {
+	optionsFile := filepath.Join(dir, tfOptionsFile)
+	overrideFile := filepath.Join(dir, tfOverrideFile)
+
+	var used bool
+	for _, option := range azureClusterOptions {
+		if _, ok := cfg[option]; ok {
+			used = true
+		}
+	}
+	if !used {
+		for _, f := range []string{optionsFile, overrideFile} {
+			if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	t, err := expandTemplate("azureClusterOverride", azureClusterOverrideTemplate, cfg)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(optionsFile, []byte(azureClusterOptionsTemplate), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(overrideFile, []byte(t), 0700)
+}
+
+// expandTemplate renders a cluster template which only depends on the given config.
+func expandTemplate(name, text string, cfg map[string]interface{}) (string, error) {
+	tmpCfg := struct {
+		Cfg map[string]interface{}
+	}{
+		Cfg: cfg,
+	}
+
+	t := template.Must(template.New(name).Parse(text))
+	s := &strings.Builder{}
+	if err := t.Execute(s, tmpCfg); err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
 func expandGardenerClusterTemplate(cfg map[string]interface{}) (string, error) {
 	tmpCfg := struct {
 		WorkerNets   []string
@@ -465,7 +1153,7 @@ func expandGardenerClusterTemplate(cfg map[string]interface{}) (string, error) {
 	if cfg["target_provider"] == string(types.AWS) {
 		// subnets for zones
 		var err error
-		tmpCfg.WorkerNets, tmpCfg.PublicNets, tmpCfg.InternalNets, err = generateGardenerAWSSubnets(cfg["vnetcidr"].(string), len(cfg["zones"].([]string)))
+		tmpCfg.WorkerNets, tmpCfg.PublicNets, tmpCfg.InternalNets, err = gardener_operator.AWSSubnets(cfg["vnetcidr"].(string), len(cfg["zones"].([]string)))
 		if err != nil {
 			return "", errors.Wrap(err, "Error generating subnets for AWS zones")
 		}
@@ -479,6 +1167,51 @@ func expandGardenerClusterTemplate(cfg map[string]interface{}) (string, error) {
 	return s.String(), nil
 }
 
+// kindPortMapping maps a port of the host to a port of the kind control plane node
+type kindPortMapping struct {
+	HostPort      int
+	ContainerPort int
+}
+
+// expandKindClusterTemplate renders the kind cluster template.
+// Port mappings are read from the "port_mappings" config as a list of "hostPort:containerPort" entries
+// and registry mirrors from the "registry_mirrors" config as a map of registry to mirror endpoint.
+func expandKindClusterTemplate(cfg map[string]interface{}) (string, error) {
+	tmpCfg := struct {
+		PortMappings    []kindPortMapping
+		RegistryMirrors map[string]string
+	}{}
+
+	if mappings, ok := cfg["port_mappings"].([]string); ok {
+		for _, m := range mappings {
+			ports := strings.Split(m, ":")
+			if len(ports) != 2 {
+				return "", errors.Errorf("invalid port mapping %q, expected the hostPort:containerPort format", m)
+			}
+			hostPort, err := strconv.Atoi(ports[0])
+			if err != nil {
+				return "", errors.Wrapf(err, "invalid host port in port mapping %q", m)
+			}
+			containerPort, err := strconv.Atoi(ports[1])
+			if err != nil {
+				return "", errors.Wrapf(err, "invalid container port in port mapping %q", m)
+			}
+			tmpCfg.PortMappings = append(tmpCfg.PortMappings, kindPortMapping{HostPort: hostPort, ContainerPort: containerPort})
+		}
+	}
+
+	if mirrors, ok := cfg["registry_mirrors"].(map[string]string); ok {
+		tmpCfg.RegistryMirrors = mirrors
+	}
+
+	t := template.Must(template.New("kindCluster").Parse(kindClusterTemplate))
+	s := &strings.Builder{}
+	if err := t.Execute(s, tmpCfg); err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
 // cleanup removes all terraform generated files for a given cluster
 func cleanup(dataDir, project, cluster string, p types.ProviderType) error {
 	d, err := clusterDir(dataDir, project, cluster, p)
@@ -497,30 +1230,3 @@ func isEmptyDir(path string) (bool, error) {
 	}
 	return len(entries) == 0, nil
 }
-
-func generateGardenerAWSSubnets(baseNet string, zoneCount int) (workerNets, publicNets, internalNets []string, err error) {
-	_, cidr, err := net.ParseCIDR(baseNet)
-	if err != nil {
-		return
-	}
-	if zoneCount < 1 {
-		err = errors.New("There must be at least 1 zone defined.")
-	}
-
-	// each zone gets its own subnet
-	const subnetSize = 64
-	for i := 0; i < zoneCount; i++ {
-		// workers subnet
-		cidr.IP[2] = byte(i * subnetSize)
-		cidr.Mask = net.CIDRMask(19, 8*net.IPv4len)
-		workerNets = append(workerNets, cidr.String())
-
-		// public and internal share the subnet and divide it further
-		cidr.Mask = net.CIDRMask(20, 8*net.IPv4len)
-		cidr.IP[2] = byte(i*subnetSize + subnetSize/2) // first half of the subnet after worker
-		publicNets = append(publicNets, cidr.String())
-		cidr.IP[2] = byte(int(cidr.IP[2]) + subnetSize/4) // second half of the subnet after worker
-		internalNets = append(internalNets, cidr.String())
-	}
-	return
-}
@@ -0,0 +1,185 @@
+package terraform
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandKindClusterTemplate(t *testing.T) {
+	t.Parallel()
+
+	// no kind config without port mappings or registry mirrors
+	tpl, err := expandKindClusterTemplate(map[string]interface{}{})
+	require.NoError(t, err)
+	require.NotContains(t, tpl, "kind_config")
+
+	tpl, err = expandKindClusterTemplate(map[string]interface{}{
+		"port_mappings":    []string{"80:80", "8443:443"},
+		"registry_mirrors": map[string]string{"docker.io": "http://registry.localhost:5000"},
+	})
+	require.NoError(t, err)
+	require.Contains(t, tpl, "kind_config")
+	require.Contains(t, tpl, "  - hostPort: 8443\n    containerPort: 443")
+	require.Contains(t, tpl, `registry.mirrors."docker.io"]`)
+	require.Contains(t, tpl, `endpoint = ["http://registry.localhost:5000"]`)
+
+	_, err = expandKindClusterTemplate(map[string]interface{}{
+		"port_mappings": []string{"80"},
+	})
+	require.Error(t, err, "Port mappings without container port should fail")
+
+	_, err = expandKindClusterTemplate(map[string]interface{}{
+		"port_mappings": []string{"http:80"},
+	})
+	require.Error(t, err, "Port mappings with non numeric ports should fail")
+}
+
+func TestExpandGCPClusterTemplate(t *testing.T) {
+	t.Parallel()
+
+	tpl, err := expandTemplate("gcpCluster", gcpClusterTemplate, map[string]interface{}{})
+	require.NoError(t, err)
+	require.NotContains(t, tpl, "google_container_node_pool", "Default node pool should be used without autoscaling")
+	require.Contains(t, tpl, "preemptible  = var.spot")
+
+	tpl, err = expandTemplate("gcpCluster", gcpClusterTemplate, map[string]interface{}{
+		"autoscaling_min": 1,
+		"autoscaling_max": 3,
+	})
+	require.NoError(t, err)
+	require.Contains(t, tpl, "remove_default_node_pool = true")
+	require.Contains(t, tpl, `resource "google_container_node_pool" "gke_node_pool"`)
+	require.Contains(t, tpl, "version            = var.kubernetes_version")
+
+	tpl, err = expandTemplate("gcpCluster", gcpClusterTemplate, map[string]interface{}{})
+	require.NoError(t, err)
+	for _, block := range []string{"ip_allocation_policy", "private_cluster_config", "master_authorized_networks_config", "workload_identity_config", "workload_metadata_config", "release_channel {"} {
+		require.NotContains(t, tpl, block)
+	}
+	require.Contains(t, tpl, "node_version       = var.kubernetes_version")
+
+	tpl, err = expandTemplate("gcpCluster", gcpClusterTemplate, map[string]interface{}{
+		"private_cluster":            true,
+		"master_ipv4_cidr":           "172.16.0.0/28",
+		"master_authorized_networks": []string{"10.0.0.0/8"},
+		"workload_identity":          true,
+		"release_channel":            "STABLE",
+		"autoscaling_max":            3,
+	})
+	require.NoError(t, err)
+	require.Contains(t, tpl, "ip_allocation_policy", "Private clusters should be VPC-native")
+	require.Contains(t, tpl, "private_cluster_config")
+	require.Contains(t, tpl, "master_authorized_networks_config")
+	require.Contains(t, tpl, "workload_identity_config")
+	require.Contains(t, tpl, "workload_metadata_config", "The node pool should use the GKE metadata server")
+	require.Contains(t, tpl, "channel = var.release_channel")
+	require.NotContains(t, tpl, "version            = var.kubernetes_version", "Nodes should follow the release channel")
+	require.NotContains(t, tpl, "node_version")
+	require.NotContains(t, tpl, "resource_labels")
+
+	tpl, err = expandTemplate("gcpCluster", gcpClusterTemplate, map[string]interface{}{
+		"resource_tags": map[string]string{"owner": "jane"},
+	})
+	require.NoError(t, err)
+	require.Contains(t, tpl, "resource_labels    = var.resource_tags")
+}
+
+func TestWriteAzureClusterOptions(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "azure")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, writeAzureClusterOptions(dir, map[string]interface{}{"aad_rbac": true, "managed_identity": true}))
+	override, err := ioutil.ReadFile(filepath.Join(dir, tfOverrideFile))
+	require.NoError(t, err)
+	require.Contains(t, string(override), "azure_active_directory")
+	require.Contains(t, string(override), "identity {")
+	require.Contains(t, string(override), `dynamic "service_principal"`, "The service principal should be removed for managed identities")
+	require.NotContains(t, string(override), "default_node_pool", "The default node pool of the module should be kept")
+	require.NotContains(t, string(override), "network_profile")
+	require.FileExists(t, filepath.Join(dir, tfOptionsFile))
+
+	require.NoError(t, writeAzureClusterOptions(dir, map[string]interface{}{"availability_zones": []string{"1", "2"}, "vnet_subnet_id": "/subscriptions/id"}))
+	override, err = ioutil.ReadFile(filepath.Join(dir, tfOverrideFile))
+	require.NoError(t, err)
+	require.Contains(t, string(override), "availability_zones = var.availability_zones")
+	require.Contains(t, string(override), `network_plugin     = "azure"`)
+	require.NotContains(t, string(override), "identity")
+
+	require.NoError(t, writeAzureClusterOptions(dir, map[string]interface{}{"resource_tags": map[string]string{"owner": "jane"}}))
+	override, err = ioutil.ReadFile(filepath.Join(dir, tfOverrideFile))
+	require.NoError(t, err)
+	require.Contains(t, string(override), "tags = var.resource_tags")
+
+	require.NoError(t, writeAzureClusterOptions(dir, map[string]interface{}{}))
+	_, err = os.Stat(filepath.Join(dir, tfOverrideFile))
+	require.True(t, os.IsNotExist(err), "The override file should be removed without options")
+	_, err = os.Stat(filepath.Join(dir, tfOptionsFile))
+	require.True(t, os.IsNotExist(err), "The options file should be removed without options")
+}
+
+func TestInitClusterFilesWithTags(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "tags")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := map[string]interface{}{
+		"project":       "my-project",
+		"cluster_name":  "my-cluster",
+		"resource_tags": map[string]string{"owner": "jane", "cost-center": "1234"},
+	}
+	require.NoError(t, initClusterFiles(dir, types.AWS, cfg))
+
+	clDir, err := clusterDir(dir, "my-project", "my-cluster", types.AWS)
+	require.NoError(t, err)
+	vars, err := ioutil.ReadFile(filepath.Join(clDir, tfVarsFile))
+	require.NoError(t, err)
+	require.Contains(t, string(vars), `resource_tags = {"cost-center" = "1234", "owner" = "jane"}`)
+}
+
+func TestInitClusterFilesAzureNodePool(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "azure-node-pool")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clDir, err := clusterDir(dir, "my-project", "my-cluster", types.Azure)
+	require.NoError(t, err)
+
+	cfg := map[string]interface{}{
+		"project":      "my-project",
+		"cluster_name": "my-cluster",
+		"spot":         true,
+	}
+	require.NoError(t, initClusterFiles(dir, types.Azure, cfg))
+	require.NoFileExists(t, filepath.Join(clDir, tfNodePoolFile), "A node pool of the fixed node count would double the capacity")
+
+	cfg["autoscaling_max"] = 3
+	cfg["resource_tags"] = map[string]string{"owner": "jane"}
+	require.NoError(t, initClusterFiles(dir, types.Azure, cfg))
+	nodePool, err := ioutil.ReadFile(filepath.Join(clDir, tfNodePoolFile))
+	require.NoError(t, err)
+	require.Contains(t, string(nodePool), fmt.Sprintf("kubernetes_cluster_id = %s.id", clusterResource(types.Azure)), "The node pool should belong to the cluster of the module")
+
+	// the override file only applies if it declares the cluster resource of the module
+	override, err := ioutil.ReadFile(filepath.Join(clDir, tfOverrideFile))
+	require.NoError(t, err)
+	address := strings.SplitN(clusterResource(types.Azure), ".", 2)
+	require.Contains(t, string(override), fmt.Sprintf("resource %q %q {", address[0], address[1]))
+
+	delete(cfg, "autoscaling_max")
+	require.NoError(t, initClusterFiles(dir, types.Azure, cfg))
+	require.NoFileExists(t, filepath.Join(clDir, tfNodePoolFile), "The node pool should be removed without autoscaling")
+}
@@ -34,10 +34,22 @@ func gardenerFilter(key string, value interface{}) bool {
 }
 
 func kindFilter(key string, value interface{}) bool {
-	// by default all keys stay in the vars for kind
+	// port mappings and registry mirrors are rendered into the kind config of the module file, kind creates no cloud resources to tag
+	excludedKeys := []string{"port_mappings", "registry_mirrors", tagsKey}
+
+	for _, e := range excludedKeys {
+		if key == e {
+			return false
+		}
+	}
 	return true
 }
 
+func digitalOceanFilter(key string, value interface{}) bool {
+	// disk size is defined by the machine type (droplet size) on DigitalOcean
+	return key != "disk_size"
+}
+
 // filterVars takes the full hydroform configuration map and given a provider, it fetches its filter function and removes the keys that should not be there.
 // Each provider should implement varFilter to control which vars it should have in its tfvars file.
 func filterVars(cfg map[string]interface{}, p types.ProviderType) map[string]interface{} {
@@ -51,9 +63,11 @@ func filterVars(cfg map[string]interface{}, p types.ProviderType) map[string]int
 	case types.Azure:
 		f = azureFilter
 	case types.AWS:
-		f = awsFilter // not supported yet
+		f = awsFilter
 	case types.Kind:
 		f = kindFilter
+	case types.DigitalOcean:
+		f = digitalOceanFilter
 	}
 
 	for key, value := range cfg {
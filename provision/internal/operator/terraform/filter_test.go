@@ -39,4 +39,17 @@ func TestFilterVars(t *testing.T) {
 	// filter Kind
 	r = filterVars(cfg, types.Kind)
 	require.Equal(t, cfg, r, "Kind should not filter out any variables")
+
+	// filter DigitalOcean
+	r = filterVars(map[string]interface{}{"cluster_name": "fake-cluster", "disk_size": 30}, types.DigitalOcean)
+	require.Equal(t, expected, r, "DigitalOcean should filter out the disk size")
+
+	kindCfg := map[string]interface{}{
+		"cluster_name":     "fake-cluster",
+		"port_mappings":    []string{"80:80"},
+		"registry_mirrors": map[string]string{"docker.io": "http://registry:5000"},
+		"resource_tags":    map[string]string{"owner": "jane"},
+	}
+	r = filterVars(kindCfg, types.Kind)
+	require.Equal(t, expected, r, "Kind should filter out the kind config variables and tags")
 }
@@ -32,6 +32,10 @@ func New(ops ...Option) *Terraform {
 // Create creates a new cluster for a specific provider based on configuration details. It returns a ClusterInfo object with provider-related information, or an error if cluster provisioning failed.
 func (t *Terraform) Create(p types.ProviderType, cfg map[string]interface{}) (*types.ClusterInfo, error) {
 	applyTimeouts(cfg, t.ops.Timeouts)
+	applyTags(cfg, t.ops.Tags)
+	if err := t.ops.validateWorkspace(); err != nil {
+		return nil, err
+	}
 
 	// silence stdErr during terraform execution, plugins send debug and trace entries there
 	if !t.ops.Verbose {
@@ -43,10 +47,10 @@ func (t *Terraform) Create(p types.ProviderType, cfg map[string]interface{}) (*t
 	// init cluster files
 	if !t.ops.Persistent {
 		// remove all files if not persistent after running
-		defer cleanup(t.ops.DataDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
+		defer cleanup(t.ops.workspaceDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
 	}
 
-	clusterDir, err := clusterDir(t.ops.DataDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
+	clusterDir, err := clusterDir(t.ops.workspaceDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
 	if err != nil {
 		return nil, err
 	}
@@ -60,20 +64,31 @@ func (t *Terraform) Create(p types.ProviderType, cfg map[string]interface{}) (*t
 		return nil, err
 	}
 
-	if err := initClusterFiles(t.ops.DataDir(), p, cfg); err != nil {
+	if err := initClusterFiles(t.ops.workspaceDir(), p, cfg); err != nil {
 		return nil, errors.Wrap(err, "Could not initialize cluster data")
 	}
 
+	// TAINT
+	if len(t.ops.Taints) > 0 {
+		if err := tfTaint(t.ops, t.ops.Taints, clusterDir); err != nil {
+			return nil, err
+		}
+	}
+
 	// APPLY
 	if err := tfApply(t.ops, p, cfg, clusterDir); err != nil {
 		return nil, err
 	}
-	return clusterInfoFromFile(t.ops.DataDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
+	return clusterInfoFromFile(t.ops.workspaceDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
 }
 
 // Status checks the current state of the cluster from the file
 func (t *Terraform) Status(sf *statefile.File, p types.ProviderType, cfg map[string]interface{}) (*types.ClusterStatus, error) {
 	applyTimeouts(cfg, t.ops.Timeouts)
+	applyTags(cfg, t.ops.Tags)
+	if err := t.ops.validateWorkspace(); err != nil {
+		return nil, err
+	}
 
 	cs := &types.ClusterStatus{
 		Phase: types.Unknown,
@@ -82,7 +97,7 @@ func (t *Terraform) Status(sf *statefile.File, p types.ProviderType, cfg map[str
 
 	// if no state given, try the file system
 	if sf == nil {
-		sf, err = stateFromFile(t.ops.DataDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
+		sf, err = stateFromFile(t.ops.workspaceDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
 		if err != nil {
 			return cs, errors.Wrap(err, "no state provided, attempted to load from file")
 		}
@@ -98,6 +113,10 @@ func (t *Terraform) Status(sf *statefile.File, p types.ProviderType, cfg map[str
 // Delete removes an existing cluster or returns an error if removing the cluster is not possible.
 func (t *Terraform) Delete(sf *statefile.File, p types.ProviderType, cfg map[string]interface{}) error {
 	applyTimeouts(cfg, t.ops.Timeouts)
+	applyTags(cfg, t.ops.Tags)
+	if err := t.ops.validateWorkspace(); err != nil {
+		return err
+	}
 
 	// silence stdErr during terraform execution, plugins send debug and trace entries there
 	stderr := os.Stderr
@@ -107,10 +126,10 @@ func (t *Terraform) Delete(sf *statefile.File, p types.ProviderType, cfg map[str
 	// init cluster files
 	if !t.ops.Persistent {
 		// remove all files if not persistent after running
-		defer cleanup(t.ops.DataDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
+		defer cleanup(t.ops.workspaceDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
 	}
 
-	clusterDir, err := clusterDir(t.ops.DataDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
+	clusterDir, err := clusterDir(t.ops.workspaceDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
 	if err != nil {
 		return err
 	}
@@ -124,19 +143,19 @@ func (t *Terraform) Delete(sf *statefile.File, p types.ProviderType, cfg map[str
 	if err := tfInit(t.ops, p, cfg, clusterDir); err != nil {
 		return err
 	}
-	if err := initClusterFiles(t.ops.DataDir(), p, cfg); err != nil {
+	if err := initClusterFiles(t.ops.workspaceDir(), p, cfg); err != nil {
 		return errors.Wrap(err, "Could not initialize cluster data")
 	}
 
 	// if no state given, check if it is already in the file system
 	if sf == nil {
-		_, err := stateFromFile(t.ops.DataDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
+		_, err := stateFromFile(t.ops.workspaceDir(), cfg["project"].(string), cfg["cluster_name"].(string), p)
 		if err != nil {
 			return errors.Wrap(err, "no state provided, attempted to load from file")
 		}
 	} else {
 		// otherwise save the state into a file so terraform can use it
-		if err := stateToFile(sf, t.ops.DataDir(), cfg["project"].(string), cfg["cluster_name"].(string), p); err != nil {
+		if err := stateToFile(sf, t.ops.workspaceDir(), cfg["project"].(string), cfg["cluster_name"].(string), p); err != nil {
 			return errors.Wrap(err, "could not store state into file")
 		}
 	}
@@ -31,6 +31,18 @@ type Options struct {
 
 	// Print terraform log for debugging
 	Verbose bool
+
+	// Targets limits apply and destroy operations to the given resource addresses
+	Targets []string
+
+	// Taints lists the resource addresses to be tainted before applying
+	Taints []string
+
+	// Workspace is the name of the workspace holding the cluster states
+	Workspace string
+
+	// Tags are added to all cloud resources of the cluster
+	Tags map[string]string
 }
 
 // Option is a function that allows to extensibly configure the terraform operator.
@@ -70,6 +82,34 @@ func Verbose(verbose bool) Option {
 	}
 }
 
+// Scope apply and destroy to the given resource addresses
+func WithTargets(addresses ...string) Option {
+	return func(ops *Options) {
+		ops.Targets = append(ops.Targets, addresses...)
+	}
+}
+
+// Taint the given resource addresses before applying
+func WithTaints(addresses ...string) Option {
+	return func(ops *Options) {
+		ops.Taints = append(ops.Taints, addresses...)
+	}
+}
+
+// Select the workspace to operate in
+func WithWorkspace(name string) Option {
+	return func(ops *Options) {
+		ops.Workspace = name
+	}
+}
+
+// Tag all cloud resources of the cluster
+func WithTags(tags map[string]string) Option {
+	return func(ops *Options) {
+		ops.Tags = tags
+	}
+}
+
 // ToTerraformOptions turns Hydroform options into terraform operator specific options
 func ToTerraformOptions(ops *types.Options) (tfOps []Option) {
 
@@ -89,6 +129,22 @@ func ToTerraformOptions(ops *types.Options) (tfOps []Option) {
 		tfOps = append(tfOps, Verbose(ops.Verbose))
 	}
 
+	if len(ops.Targets) > 0 {
+		tfOps = append(tfOps, WithTargets(ops.Targets...))
+	}
+
+	if len(ops.Taints) > 0 {
+		tfOps = append(tfOps, WithTaints(ops.Taints...))
+	}
+
+	if ops.Workspace != "" {
+		tfOps = append(tfOps, WithWorkspace(ops.Workspace))
+	}
+
+	if len(ops.Tags) > 0 {
+		tfOps = append(tfOps, WithTags(ops.Tags))
+	}
+
 	return tfOps
 }
 
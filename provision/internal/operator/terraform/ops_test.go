@@ -87,6 +87,26 @@ func TestToTerraformOptions(t *testing.T) {
 				Persistent: true,
 			},
 		},
+		{
+			Name: "Targets and taints",
+			Input: types.Options{
+				Targets: []string{"google_container_cluster.gke_cluster"},
+				Taints:  []string{"google_container_cluster.gke_cluster"},
+			},
+			Expected: Options{
+				Targets: []string{"google_container_cluster.gke_cluster"},
+				Taints:  []string{"google_container_cluster.gke_cluster"},
+			},
+		},
+		{
+			Name: "Tags",
+			Input: types.Options{
+				Tags: map[string]string{"owner": "jane"},
+			},
+			Expected: Options{
+				Tags: map[string]string{"owner": "jane"},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
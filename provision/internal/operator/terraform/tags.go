@@ -0,0 +1,12 @@
+package terraform
+
+// tagsKey is the config key of the tags added to all cloud resources of the cluster
+const tagsKey = "resource_tags"
+
+// applyTags adds the tags to the config, the templates of the providers only tag the resources if the key is present
+func applyTags(cfg map[string]interface{}, tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+	cfg[tagsKey] = tags
+}
@@ -0,0 +1,18 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTags(t *testing.T) {
+	t.Parallel()
+
+	cfg := map[string]interface{}{}
+	applyTags(cfg, nil)
+	require.NotContains(t, cfg, tagsKey, "No tags should be configured without tags")
+
+	applyTags(cfg, map[string]string{"owner": "jane"})
+	require.Equal(t, map[string]string{"owner": "jane"}, cfg[tagsKey])
+}
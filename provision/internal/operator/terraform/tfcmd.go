@@ -90,7 +90,7 @@ func tfApply(ops Options, p types.ProviderType, cfg map[string]interface{}, dir
 	a := &command.ApplyCommand{
 		Meta: ops.Meta,
 	}
-	e := a.Run(applyArgs(p, cfg, dir))
+	e := a.Run(applyArgs(p, cfg, dir, ops.Targets...))
 	if e != 0 {
 		errList := checkUIErrors(ops.Ui)
 
@@ -100,7 +100,11 @@ func tfApply(ops Options, p types.ProviderType, cfg map[string]interface{}, dir
 				Meta: ops.Meta,
 			}
 
-			if e := i.Run(importArgs(p, cfg, dir)); e != 0 {
+			args, err := importArgs(p, cfg, dir)
+			if err != nil {
+				return errors.Wrap(err, "could not import the existing cluster")
+			}
+			if e := i.Run(args); e != 0 {
 				return checkUIErrors(ops.Ui)
 			}
 
@@ -140,14 +144,33 @@ func tfDestroy(ops Options, p types.ProviderType, cfg map[string]interface{}, di
 		Meta:    ops.Meta,
 		Destroy: true,
 	}
-	if e := a.Run(applyArgs(p, cfg, dir)); e != 0 {
+	if e := a.Run(applyArgs(p, cfg, dir, ops.Targets...)); e != 0 {
 		return checkUIErrors(ops.Ui)
 	}
 	return nil
 }
 
+// tfTaint runs the 'terraform taint' command for each of the given resource addresses in the given working directory.
+// Tainted resources are destroyed and recreated on the next apply.
+func tfTaint(ops Options, addresses []string, dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, tfStateFile)); os.IsNotExist(err) {
+		return errors.New("no state available to taint resources in, make sure the cluster was provisioned with a persistent data directory")
+	}
+
+	for _, address := range addresses {
+		t := &command.TaintCommand{
+			Meta: ops.Meta,
+		}
+		if e := t.Run(taintArgs(address, dir)); e != 0 {
+			return errors.Wrapf(exitError(ops.Ui, e), "could not taint resource %s", address)
+		}
+	}
+	return nil
+}
+
 // applyArgs generates the flag list for the terraform apply command based on the operator configuration
-func applyArgs(p types.ProviderType, cfg map[string]interface{}, clusterDir string) []string {
+// If targets are provided, the operation is limited to those resource addresses.
+func applyArgs(p types.ProviderType, cfg map[string]interface{}, clusterDir string, targets ...string) []string {
 	args := make([]string, 0)
 
 	stateFile := filepath.Join(clusterDir, tfStateFile)
@@ -156,16 +179,37 @@ func applyArgs(p types.ProviderType, cfg map[string]interface{}, clusterDir stri
 	args = append(args,
 		fmt.Sprintf("-state=%s", stateFile),
 		fmt.Sprintf("-var-file=%s", varsFile),
-		"-auto-approve",
-		clusterDir)
+		"-auto-approve")
+
+	for _, target := range targets {
+		args = append(args, fmt.Sprintf("-target=%s", target))
+	}
+
+	args = append(args, clusterDir)
 
 	return args
 }
 
+// taintArgs generates the flag list for the terraform taint command for a single resource address
+func taintArgs(address, clusterDir string) []string {
+	stateFile := filepath.Join(clusterDir, tfStateFile)
+
+	return []string{
+		fmt.Sprintf("-state=%s", stateFile),
+		fmt.Sprintf("-state-out=%s", stateFile),
+		address,
+	}
+}
+
 // importArgs generates the flag list for the terraform import command based on the operator configuration
-func importArgs(p types.ProviderType, cfg map[string]interface{}, clusterDir string) []string {
+func importArgs(p types.ProviderType, cfg map[string]interface{}, clusterDir string) ([]string, error) {
 	args := make([]string, 0)
 
+	id, err := clusterID(p, cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	stateFile := filepath.Join(clusterDir, tfStateFile)
 	varsFile := filepath.Join(clusterDir, tfVarsFile)
 
@@ -175,9 +219,9 @@ func importArgs(p types.ProviderType, cfg map[string]interface{}, clusterDir str
 		fmt.Sprintf("-var-file=%s", varsFile),
 		fmt.Sprintf("-config=%s", clusterDir),
 		clusterResource(p), // cluster resource
-		clusterID(p, cfg))  // cluster ID
+		id)                 // cluster ID
 
-	return args
+	return args, nil
 }
 
 // clusterResource returns the cluster resource type defined in the terraform module for the given provider.
@@ -190,23 +234,27 @@ func clusterResource(p types.ProviderType) string {
 	case types.Gardener:
 		return "gardener_shoot.gardener_cluster"
 	case types.AWS:
-		return "not supported"
+		return "aws_eks_cluster.eks_cluster"
+	case types.DigitalOcean:
+		return "digitalocean_kubernetes_cluster.digitalocean_cluster"
 	}
 	return ""
 }
 
 // clusterID generates a cluster ID based on the given config.
 // Each provider has a different way of identifying clusters.
-func clusterID(p types.ProviderType, cfg map[string]interface{}) string {
+func clusterID(p types.ProviderType, cfg map[string]interface{}) (string, error) {
 	switch p {
 	case types.GCP:
-		return fmt.Sprintf("%s/%s/%s", cfg["project"], cfg["location"], cfg["cluster_name"])
+		return fmt.Sprintf("%s/%s/%s", cfg["project"], cfg["location"], cfg["cluster_name"]), nil
 	case types.Gardener:
-		return fmt.Sprintf("%s/%s", cfg["namespace"], cfg["cluster_name"])
+		return fmt.Sprintf("%s/%s", cfg["namespace"], cfg["cluster_name"]), nil
 	case types.AWS:
-		return "not supported"
+		return fmt.Sprintf("%s", cfg["cluster_name"]), nil
+	case types.DigitalOcean:
+		return digitalOceanClusterID(digitalOceanAPI, cfg)
 	}
-	return ""
+	return "", nil
 }
 
 // refreshArgs generates the flag list for the terraform refresh command based on the operator configuration
@@ -224,6 +272,15 @@ func refreshArgs(p types.ProviderType, cfg map[string]interface{}, clusterDir st
 	return args
 }
 
+// exitError returns the errors collected by the UI for a command which exited with the given non-zero code.
+// Commands do not always report their errors on the UI, so an error with the exit code is returned in that case.
+func exitError(ui hashiCli.Ui, code int) error {
+	if err := checkUIErrors(ui); err != nil {
+		return err
+	}
+	return errors.Errorf("terraform exited with code %d", code)
+}
+
 func checkUIErrors(ui hashiCli.Ui) error {
 	var errsum strings.Builder
 	if h, ok := ui.(*HydroUI); ok {
@@ -51,7 +51,8 @@ func TestImportArgs(t *testing.T) {
 	cfg := map[string]interface{}{"project": "my-project", "namespace": "my-namespace", "location": "somewhere", "cluster_name": "my-cluster"}
 
 	// test GCP
-	res := importArgs(types.GCP, cfg, "/path/to/cluster")
+	res, err := importArgs(types.GCP, cfg, "/path/to/cluster")
+	require.NoError(t, err)
 	require.Len(t, res, 6)
 	require.Equal(t, "-state=/path/to/cluster/terraform.tfstate", res[0])     // state file
 	require.Equal(t, "-state-out=/path/to/cluster/terraform.tfstate", res[1]) // state output file
@@ -61,7 +62,8 @@ func TestImportArgs(t *testing.T) {
 	require.Equal(t, "my-project/somewhere/my-cluster", res[5])               // cluster ID
 
 	// test Gardener
-	res = importArgs(types.Gardener, cfg, "/path/to/cluster")
+	res, err = importArgs(types.Gardener, cfg, "/path/to/cluster")
+	require.NoError(t, err)
 	require.Len(t, res, 6)
 	require.Equal(t, "-state=/path/to/cluster/terraform.tfstate", res[0])     // state file
 	require.Equal(t, "-state-out=/path/to/cluster/terraform.tfstate", res[1]) // state output file
@@ -70,3 +72,36 @@ func TestImportArgs(t *testing.T) {
 	require.Equal(t, "gardener_shoot.gardener_cluster", res[4])               // resource type for a GCP cluster
 	require.Equal(t, "my-namespace/my-cluster", res[5])                       // cluster ID
 }
+
+func TestApplyArgsWithTargets(t *testing.T) {
+	t.Parallel()
+	res := applyArgs("", nil, "/path/to/cluster", "google_container_cluster.gke_cluster", "google_container_node_pool.pool")
+
+	require.Len(t, res, 6)
+	require.Equal(t, "-auto-approve", res[2])
+	require.Equal(t, "-target=google_container_cluster.gke_cluster", res[3]) // first target
+	require.Equal(t, "-target=google_container_node_pool.pool", res[4])      // second target
+	require.Equal(t, "/path/to/cluster", res[5])                             // cluster config directory must stay last
+}
+
+func TestTaintArgs(t *testing.T) {
+	t.Parallel()
+	res := taintArgs("gardener_shoot.gardener_cluster", "/path/to/cluster")
+
+	require.Len(t, res, 3)
+	require.Equal(t, "-state=/path/to/cluster/terraform.tfstate", res[0])     // state file
+	require.Equal(t, "-state-out=/path/to/cluster/terraform.tfstate", res[1]) // state output file
+	require.Equal(t, "gardener_shoot.gardener_cluster", res[2])               // resource address to taint
+}
+
+func TestExitError(t *testing.T) {
+	t.Parallel()
+	ui := &HydroUI{}
+
+	err := exitError(ui, 1)
+	require.EqualError(t, err, "terraform exited with code 1", "A failed command without UI errors should fail")
+
+	ui.Error("resource not found")
+	err = exitError(ui, 1)
+	require.EqualError(t, err, "resource not found")
+}
@@ -0,0 +1,110 @@
+package terraform
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/hashicorp/terraform/states/statefile"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultWorkspace is the workspace used when none is selected, it is stored directly in the data directory
+	defaultWorkspace = "default"
+	// workspacesDir is the directory inside the data directory holding all named workspaces
+	workspacesDir = "workspaces"
+)
+
+// workspaceName matches valid workspace names, they are used as directory names and must not point outside of the workspaces directory
+var workspaceName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// validateWorkspace returns an error if the selected workspace name is not valid. No workspace selects the default workspace.
+func (o Options) validateWorkspace() error {
+	if o.Workspace != "" && !workspaceName.MatchString(o.Workspace) {
+		return errors.Errorf("invalid workspace name %q, only letters, numbers, underscores, and hyphens are allowed", o.Workspace)
+	}
+	return nil
+}
+
+// workspaceDir returns the directory where the files of the selected workspace are stored.
+func (o Options) workspaceDir() string {
+	if o.Workspace == "" || o.Workspace == defaultWorkspace {
+		return o.DataDir()
+	}
+	return filepath.Join(o.DataDir(), workspacesDir, o.Workspace)
+}
+
+// DataDir returns the data directory of the options, which contains the files of all workspaces.
+func DataDir(ops ...Option) string {
+	return options(ops...).DataDir()
+}
+
+// Workspaces returns the names of all workspaces available in the data directory, including the default workspace.
+func Workspaces(ops ...Option) ([]string, error) {
+	tfOps := options(ops...)
+
+	names := []string{defaultWorkspace}
+	entries, err := ioutil.ReadDir(filepath.Join(tfOps.DataDir(), workspacesDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return names, nil
+		}
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// DeleteWorkspace removes a workspace and all of its files.
+// Like in terraform, the default workspace cannot be deleted and workspaces still holding provisioned clusters are kept.
+func DeleteWorkspace(name string, ops ...Option) error {
+	if name == "" || name == defaultWorkspace {
+		return errors.New("the default workspace cannot be deleted")
+	}
+
+	tfOps := options(append(ops, WithWorkspace(name))...)
+	if err := tfOps.validateWorkspace(); err != nil {
+		return err
+	}
+	dir := tfOps.workspaceDir()
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return errors.Errorf("workspace %s does not exist", name)
+	}
+
+	// refuse to delete a workspace with remaining cluster resources, their state would be lost
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() != tfStateFile {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		sf, err := statefile.Read(f)
+		if err != nil {
+			return errors.Wrapf(err, "could not read state %s", path)
+		}
+		if sf.State.HasResources() {
+			return errors.Errorf("workspace %s still contains provisioned resources in %s, deprovision them first", name, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,56 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceDir(t *testing.T) {
+	t.Parallel()
+	ops := Options{}
+	WithDataDir("/path/to/data")(&ops)
+
+	require.Equal(t, "/path/to/data", ops.workspaceDir(), "No workspace should use the data dir")
+
+	WithWorkspace("default")(&ops)
+	require.Equal(t, "/path/to/data", ops.workspaceDir(), "Default workspace should use the data dir")
+
+	WithWorkspace("stage")(&ops)
+	require.Equal(t, "/path/to/data/workspaces/stage", ops.workspaceDir())
+	require.NoError(t, ops.validateWorkspace())
+
+	for _, name := range []string{".", "..", "../stage", "dev/stage", `dev\stage`} {
+		WithWorkspace(name)(&ops)
+		require.Error(t, ops.validateWorkspace(), "Workspace %q should be invalid", name)
+	}
+}
+
+func TestWorkspaces(t *testing.T) {
+	dataDir := ".hf-ws-test"
+	defer os.RemoveAll(dataDir)
+
+	ws, err := Workspaces(WithDataDir(dataDir))
+	require.NoError(t, err)
+	require.Equal(t, []string{"default"}, ws)
+
+	// a workspace with an empty cluster directory
+	_, err = clusterDir(filepath.Join(dataDir, "workspaces", "dev"), "project", "cluster", "gcp")
+	require.NoError(t, err)
+
+	ws, err = Workspaces(WithDataDir(dataDir))
+	require.NoError(t, err)
+	require.Equal(t, []string{"default", "dev"}, ws)
+
+	require.Error(t, DeleteWorkspace("default", WithDataDir(dataDir)), "Default workspace cannot be deleted")
+	require.Error(t, DeleteWorkspace("prod", WithDataDir(dataDir)), "Unknown workspace cannot be deleted")
+	require.Error(t, DeleteWorkspace("..", WithDataDir(dataDir)), "Workspaces outside of the workspaces directory cannot be deleted")
+	require.DirExists(t, dataDir)
+	require.NoError(t, DeleteWorkspace("dev", WithDataDir(dataDir)))
+
+	ws, err = Workspaces(WithDataDir(dataDir))
+	require.NoError(t, err)
+	require.Equal(t, []string{"default"}, ws)
+}
@@ -0,0 +1,237 @@
+// Package orphans finds the cloud resources which controllers in a cluster created, such as the disks of persistent volumes,
+// load balancers, and DNS records. Terraform does not know these resources, so destroying the cluster leaves them behind.
+package orphans
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// hostnameAnnotation lists the DNS names external-dns creates records for
+	hostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+
+	pollInterval = 5 * time.Second
+)
+
+// cloudDiskDrivers are the CSI drivers which provision cloud disks
+var cloudDiskDrivers = map[string]bool{
+	"pd.csi.storage.gke.io":     true,
+	"ebs.csi.aws.com":           true,
+	"disk.csi.azure.com":        true,
+	"dobs.csi.digitalocean.com": true,
+}
+
+// Find returns the cloud resources created by controllers in the cluster.
+// Disks of persistent volumes with the Retain reclaim policy are left out, they are meant to outlive the cluster.
+func Find(client kubernetes.Interface) ([]types.CloudResource, error) {
+	ctx := context.Background()
+	var resources []types.CloudResource
+
+	pvs, err := client.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list the persistent volumes")
+	}
+	for _, pv := range pvs.Items {
+		if pv.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimRetain {
+			continue
+		}
+		if id := diskID(&pv); id != "" {
+			resources = append(resources, types.CloudResource{Kind: types.CloudDisk, Object: "persistentvolumes/" + pv.Name, ID: id})
+		}
+	}
+
+	services, err := client.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list the services")
+	}
+	for _, svc := range services.Items {
+		object := fmt.Sprintf("services/%s/%s", svc.Namespace, svc.Name)
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			resources = append(resources, types.CloudResource{Kind: types.CloudLoadBalancer, Object: object, ID: loadBalancerID(svc.Status.LoadBalancer)})
+		}
+		resources = append(resources, dnsRecords(object, svc.Annotations)...)
+	}
+
+	ingresses, err := client.NetworkingV1beta1().Ingresses(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list the ingresses")
+	}
+	for _, ing := range ingresses.Items {
+		resources = append(resources, dnsRecords(fmt.Sprintf("ingresses/%s/%s", ing.Namespace, ing.Name), ing.Annotations)...)
+	}
+
+	return resources, nil
+}
+
+// Delete deletes the Kubernetes objects backing the cloud resources created by controllers in the cluster, so that the controllers remove the cloud resources.
+// It waits until the disks and load balancers are gone. DNS records are removed asynchronously by external-dns, if it runs with the sync policy.
+func Delete(client kubernetes.Interface, timeout time.Duration) error {
+	resources, err := Find(client)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	claims := map[string]map[string]bool{}
+	for _, r := range resources {
+		switch r.Kind {
+		case types.CloudDisk:
+			pv, err := client.CoreV1().PersistentVolumes().Get(ctx, strings.TrimPrefix(r.Object, "persistentvolumes/"), metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "could not get %s", r.Object)
+			}
+			ref := pv.Spec.ClaimRef
+			if ref == nil {
+				// unbound volumes are deleted directly, the provisioner deletes the disk
+				if err := ignoreNotFound(client.CoreV1().PersistentVolumes().Delete(ctx, pv.Name, metav1.DeleteOptions{})); err != nil {
+					return errors.Wrapf(err, "could not delete the persistent volume %s", pv.Name)
+				}
+				continue
+			}
+			if err := ignoreNotFound(client.CoreV1().PersistentVolumeClaims(ref.Namespace).Delete(ctx, ref.Name, metav1.DeleteOptions{})); err != nil {
+				return errors.Wrapf(err, "could not delete the persistent volume claim %s/%s", ref.Namespace, ref.Name)
+			}
+			if claims[ref.Namespace] == nil {
+				claims[ref.Namespace] = map[string]bool{}
+			}
+			claims[ref.Namespace][ref.Name] = true
+		case types.CloudLoadBalancer, types.CloudDNSRecord:
+			if err := deleteObject(client, r); err != nil {
+				return err
+			}
+		}
+	}
+
+	// claims in use are only deleted once no pod mounts them anymore
+	if err := deletePods(client, claims); err != nil {
+		return err
+	}
+
+	var remaining []string
+	err = wait.PollImmediate(pollInterval, timeout, func() (bool, error) {
+		resources, err := Find(client)
+		if err != nil {
+			return false, err
+		}
+		remaining = nil
+		for _, r := range resources {
+			if r.Kind != types.CloudDNSRecord {
+				remaining = append(remaining, r.String())
+			}
+		}
+		return len(remaining) == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return errors.Errorf("the following cloud resources were not deleted within %s:\n%s", timeout, strings.Join(remaining, "\n"))
+	}
+	return err
+}
+
+// deleteObject deletes the service or ingress backing a load balancer or DNS record
+func deleteObject(client kubernetes.Interface, r types.CloudResource) error {
+	parts := strings.Split(r.Object, "/")
+	if len(parts) != 3 {
+		return errors.Errorf("invalid object %s of %s", r.Object, r)
+	}
+
+	ctx := context.Background()
+	var err error
+	switch parts[0] {
+	case "services":
+		err = client.CoreV1().Services(parts[1]).Delete(ctx, parts[2], metav1.DeleteOptions{})
+	case "ingresses":
+		err = client.NetworkingV1beta1().Ingresses(parts[1]).Delete(ctx, parts[2], metav1.DeleteOptions{})
+	default:
+		return errors.Errorf("invalid object %s of %s", r.Object, r)
+	}
+	if err := ignoreNotFound(err); err != nil {
+		return errors.Wrapf(err, "could not delete %s", r.Object)
+	}
+	return nil
+}
+
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// deletePods deletes the pods mounting the given claims, by namespace and name
+func deletePods(client kubernetes.Interface, claims map[string]map[string]bool) error {
+	ctx := context.Background()
+	for ns, names := range claims {
+		pods, err := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "could not list the pods in namespace %s", ns)
+		}
+		for _, pod := range pods.Items {
+			for _, v := range pod.Spec.Volumes {
+				if v.PersistentVolumeClaim == nil || !names[v.PersistentVolumeClaim.ClaimName] {
+					continue
+				}
+				if err := ignoreNotFound(client.CoreV1().Pods(ns).Delete(ctx, pod.Name, metav1.DeleteOptions{})); err != nil {
+					return errors.Wrapf(err, "could not delete the pod %s/%s", ns, pod.Name)
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// diskID returns the ID of the cloud disk of the persistent volume, or an empty string if it is not a cloud disk
+func diskID(pv *corev1.PersistentVolume) string {
+	switch {
+	case pv.Spec.GCEPersistentDisk != nil:
+		return pv.Spec.GCEPersistentDisk.PDName
+	case pv.Spec.AWSElasticBlockStore != nil:
+		return pv.Spec.AWSElasticBlockStore.VolumeID
+	case pv.Spec.AzureDisk != nil:
+		return pv.Spec.AzureDisk.DataDiskURI
+	case pv.Spec.CSI != nil && cloudDiskDrivers[pv.Spec.CSI.Driver]:
+		return pv.Spec.CSI.VolumeHandle
+	}
+	return ""
+}
+
+// loadBalancerID returns the addresses of the load balancer, or "pending" if the load balancer has no address yet
+func loadBalancerID(status corev1.LoadBalancerStatus) string {
+	var addresses []string
+	for _, ingress := range status.Ingress {
+		if ingress.Hostname != "" {
+			addresses = append(addresses, ingress.Hostname)
+		} else if ingress.IP != "" {
+			addresses = append(addresses, ingress.IP)
+		}
+	}
+	if len(addresses) == 0 {
+		return "pending"
+	}
+	return strings.Join(addresses, ",")
+}
+
+// dnsRecords returns the DNS records external-dns creates for the annotations of the object
+func dnsRecords(object string, annotations map[string]string) []types.CloudResource {
+	var records []types.CloudResource
+	for _, host := range strings.Split(annotations[hostnameAnnotation], ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			records = append(records, types.CloudResource{Kind: types.CloudDNSRecord, Object: object, ID: host})
+		}
+	}
+	return records
+}
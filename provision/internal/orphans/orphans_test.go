@@ -0,0 +1,148 @@
+package orphans
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	client := fake.NewSimpleClientset(testObjects()...)
+
+	resources, err := Find(client)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []types.CloudResource{
+		{Kind: types.CloudDisk, Object: "persistentvolumes/pvc-data", ID: "projects/my-project/zones/europe-west3-a/disks/pvc-data"},
+		{Kind: types.CloudDisk, Object: "persistentvolumes/pvc-unbound", ID: "gke-pvc-unbound"},
+		{Kind: types.CloudLoadBalancer, Object: "services/istio-system/istio-ingressgateway", ID: "34.89.1.2"},
+		{Kind: types.CloudDNSRecord, Object: "services/istio-system/istio-ingressgateway", ID: "kyma.example.com"},
+		{Kind: types.CloudDNSRecord, Object: "services/istio-system/istio-ingressgateway", ID: "*.kyma.example.com"},
+		{Kind: types.CloudDNSRecord, Object: "ingresses/default/web", ID: "web.example.com"},
+	}, resources, "Retained volumes, local volumes, and cluster IP services should be left out")
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("delete the objects of the cloud resources", func(t *testing.T) {
+		t.Parallel()
+
+		client := fake.NewSimpleClientset(testObjects()...)
+		// the provisioner deletes the volume of a deleted claim
+		client.PrependReactor("delete", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			pvs := corev1.SchemeGroupVersion.WithResource("persistentvolumes")
+			require.NoError(t, client.Tracker().Delete(pvs, "", "pvc-data"))
+			return false, nil, nil
+		})
+
+		require.NoError(t, Delete(client, time.Second))
+
+		resources, err := Find(client)
+		require.NoError(t, err)
+		require.Empty(t, resources)
+
+		pods, err := client.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, pods.Items, 1, "Only the pod mounting the claim should be deleted")
+		require.Equal(t, "other", pods.Items[0].Name)
+
+		_, err = client.CoreV1().PersistentVolumes().Get(context.Background(), "pvc-retained", metav1.GetOptions{})
+		require.NoError(t, err, "Retained volumes should be kept")
+		_, err = client.CoreV1().Services("default").Get(context.Background(), "internal", metav1.GetOptions{})
+		require.NoError(t, err, "Cluster IP services should be kept")
+	})
+
+	t.Run("time out if the controllers do not delete the cloud resources", func(t *testing.T) {
+		t.Parallel()
+
+		client := fake.NewSimpleClientset(testObjects()...)
+
+		err := Delete(client, 10*time.Millisecond)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "disk projects/my-project/zones/europe-west3-a/disks/pvc-data of persistentvolumes/pvc-data")
+	})
+}
+
+func testObjects() []runtime.Object {
+	return []runtime.Object{
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-data"},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: "pd.csi.storage.gke.io", VolumeHandle: "projects/my-project/zones/europe-west3-a/disks/pvc-data"},
+				},
+				ClaimRef: &corev1.ObjectReference{Namespace: "default", Name: "data"},
+			},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-unbound"},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					GCEPersistentDisk: &corev1.GCEPersistentDiskVolumeSource{PDName: "gke-pvc-unbound"},
+				},
+			},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-retained"},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					GCEPersistentDisk: &corev1.GCEPersistentDiskVolumeSource{PDName: "gke-pvc-retained"},
+				},
+			},
+		},
+		&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "local"},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{Path: "/data"},
+				},
+			},
+		},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db"},
+			Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}},
+			}}},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "istio-system",
+				Name:        "istio-ingressgateway",
+				Annotations: map[string]string{hostnameAnnotation: "kyma.example.com, *.kyma.example.com"},
+			},
+			Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: "34.89.1.2"}},
+			}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "internal"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+		},
+		&networkingv1beta1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        "web",
+				Annotations: map[string]string{hostnameAnnotation: "web.example.com"},
+			},
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package provision
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/provision/action"
+	"github.com/kyma-incubator/hydroform/provision/internal/orphans"
+	"github.com/kyma-incubator/hydroform/provision/types"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// orphanCredentialsExpiration is the lifetime of the kubeconfig used to look for orphaned cloud resources
+	orphanCredentialsExpiration = time.Hour
+	defaultOrphanTimeout        = 10 * time.Minute
+)
+
+// CloudResources returns the cloud resources which controllers in the cluster created, such as the disks of persistent volumes,
+// load balancers, and DNS records created by external-dns. Deprovisioning the cluster does not delete them, see types.WithOrphanPolicy.
+func CloudResources(cluster *types.Cluster, provider *types.Provider, ops ...types.Option) ([]types.CloudResource, error) {
+	var err error
+	var res []types.CloudResource
+
+	if err = action.Before(); err != nil {
+		return res, err
+	}
+
+	if runtime.GOOS == "windows" {
+		provider.CredentialsFilePath = updateWindowsPath(provider.CredentialsFilePath)
+	}
+
+	client, err := clusterClient(cluster, provider, ops...)
+	if err != nil {
+		return res, err
+	}
+	if res, err = orphans.Find(client); err != nil {
+		return res, err
+	}
+	return res, action.After()
+}
+
+// handleOrphans applies the orphan policy of the options before the cluster is deprovisioned
+func handleOrphans(cluster *types.Cluster, provider *types.Provider, ops ...types.Option) error {
+	os := &types.Options{}
+	for _, o := range ops {
+		o(os)
+	}
+	switch provider.Type {
+	case types.Gardener, types.Kind, types.K3d:
+		// Gardener deletes these resources itself, local clusters create none
+		return nil
+	}
+
+	switch os.OrphanPolicy {
+	case types.OrphanIgnore:
+		return nil
+	case types.OrphanFail:
+		client, err := clusterClient(cluster, provider, ops...)
+		if err != nil {
+			return errors.Wrap(err, "could not look for cloud resources created in the cluster")
+		}
+		resources, err := orphans.Find(client)
+		if err != nil {
+			return err
+		}
+		if len(resources) == 0 {
+			return nil
+		}
+		list := make([]string, 0, len(resources))
+		for _, r := range resources {
+			list = append(list, r.String())
+		}
+		return fmt.Errorf("the cluster has cloud resources which deprovisioning would leave behind, delete them first:\n%s", strings.Join(list, "\n"))
+	case types.OrphanDelete:
+		client, err := clusterClient(cluster, provider, ops...)
+		if err != nil {
+			return errors.Wrap(err, "could not delete the cloud resources created in the cluster")
+		}
+		timeout := defaultOrphanTimeout
+		if os.Timeouts != nil && os.Timeouts.Delete != 0 {
+			timeout = os.Timeouts.Delete
+		}
+		return orphans.Delete(client, timeout)
+	default:
+		return fmt.Errorf("unknown orphan policy %q", os.OrphanPolicy)
+	}
+}
+
+// clusterClient returns a client of the cluster. It uses short-lived credentials where supported,
+// because the long-lived kubeconfig of GCP requires the gcloud auth provider.
+func clusterClient(cluster *types.Cluster, provider *types.Provider, ops ...types.Option) (kubernetes.Interface, error) {
+	kubeconfig, err := credentials(cluster, provider, append(ops, types.WithCredentialsExpiration(orphanCredentialsExpiration))...)
+	if err != nil {
+		return nil, err
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
@@ -2,19 +2,25 @@ package provision
 
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/kyma-incubator/hydroform/provision/action"
 
+	"github.com/kyma-incubator/hydroform/provision/internal/aws"
 	"github.com/kyma-incubator/hydroform/provision/internal/azure"
+	"github.com/kyma-incubator/hydroform/provision/internal/digitalocean"
 	"github.com/kyma-incubator/hydroform/provision/internal/gardener"
+	"github.com/kyma-incubator/hydroform/provision/internal/k3d"
 	"github.com/kyma-incubator/hydroform/provision/internal/kind"
 
 	"github.com/kyma-incubator/hydroform/provision/internal/gcp"
 	"github.com/kyma-incubator/hydroform/provision/internal/operator"
 	"github.com/kyma-incubator/hydroform/provision/types"
+	"k8s.io/apimachinery/pkg/util/version"
 )
 
 const provisioningOperator = operator.TerraformOperator
@@ -25,6 +31,7 @@ type Provisioner interface {
 	Status(cluster *types.Cluster, provider *types.Provider) (*types.ClusterStatus, error)
 	Credentials(cluster *types.Cluster, provider *types.Provider) ([]byte, error)
 	Deprovision(cluster *types.Cluster, provider *types.Provider) error
+	Capabilities(cluster *types.Cluster, provider *types.Provider) (*types.Capabilities, error)
 }
 
 // Provision creates a new cluster for a given provider based on specific cluster and provider parameters. It returns a cluster object enriched with information from the provider, such as the IP address or the connection endpoint. This object is necessary for the other operations, such as retrieving the cluster status or deprovisioning the cluster. If the cluster cannot be created, the function returns an error.
@@ -40,17 +47,37 @@ func Provision(cluster *types.Cluster, provider *types.Provider, ops ...types.Op
 		provider.CredentialsFilePath = updateWindowsPath(provider.CredentialsFilePath)
 	}
 
+	if err = checkTags(ops...); err != nil {
+		return cl, err
+	}
+
+	if err = checkCost(cluster, provider, ops...); err != nil {
+		return cl, err
+	}
+
+	ttlTag, err := recordLease(cluster, provider, ops...)
+	if err != nil {
+		return cl, err
+	}
+	if ttlTag != nil {
+		ops = append(ops, ttlTag)
+	}
+
 	switch provider.Type {
 	case types.GCP:
 		cl, err = newGCPProvisioner(provisioningOperator, ops...).Provision(cluster, provider)
 	case types.Gardener:
 		cl, err = newGardenerProvisioner(provisioningOperator, ops...).Provision(cluster, provider)
 	case types.AWS:
-		err = errors.New("aws not supported yet")
+		cl, err = newAWSProvisioner(provisioningOperator, ops...).Provision(cluster, provider)
 	case types.Azure:
 		cl, err = newAzureProvisioner(provisioningOperator, ops...).Provision(cluster, provider)
 	case types.Kind:
 		cl, err = newKindProvisioner(provisioningOperator, ops...).Provision(cluster, provider)
+	case types.K3d:
+		cl, err = newK3dProvisioner(provisioningOperator, ops...).Provision(cluster, provider)
+	case types.DigitalOcean:
+		cl, err = newDigitalOceanProvisioner(provisioningOperator, ops...).Provision(cluster, provider)
 	default:
 		err = errors.New("unknown provider")
 	}
@@ -80,11 +107,15 @@ func Status(cluster *types.Cluster, provider *types.Provider, ops ...types.Optio
 	case types.Gardener:
 		cs, err = newGardenerProvisioner(provisioningOperator, ops...).Status(cluster, provider)
 	case types.AWS:
-		err = errors.New("aws not supported yet")
+		cs, err = newAWSProvisioner(provisioningOperator, ops...).Status(cluster, provider)
 	case types.Azure:
 		cs, err = newAzureProvisioner(provisioningOperator, ops...).Status(cluster, provider)
 	case types.Kind:
 		cs, err = newKindProvisioner(provisioningOperator, ops...).Status(cluster, provider)
+	case types.K3d:
+		cs, err = newK3dProvisioner(provisioningOperator, ops...).Status(cluster, provider)
+	case types.DigitalOcean:
+		cs, err = newDigitalOceanProvisioner(provisioningOperator, ops...).Status(cluster, provider)
 	default:
 		err = errors.New("unknown provider")
 	}
@@ -108,25 +139,33 @@ func Credentials(cluster *types.Cluster, provider *types.Provider, ops ...types.
 		provider.CredentialsFilePath = updateWindowsPath(provider.CredentialsFilePath)
 	}
 
+	cr, err = credentials(cluster, provider, ops...)
+	if err != nil {
+		return cr, err
+	}
+	return cr, action.After()
+}
+
+// credentials returns the kubeconfig of the cluster from the provisioner of its provider
+func credentials(cluster *types.Cluster, provider *types.Provider, ops ...types.Option) ([]byte, error) {
 	switch provider.Type {
 	case types.GCP:
-		cr, err = newGCPProvisioner(provisioningOperator, ops...).Credentials(cluster, provider)
+		return newGCPProvisioner(provisioningOperator, ops...).Credentials(cluster, provider)
 	case types.Gardener:
-		cr, err = newGardenerProvisioner(provisioningOperator, ops...).Credentials(cluster, provider)
+		return newGardenerProvisioner(provisioningOperator, ops...).Credentials(cluster, provider)
 	case types.AWS:
-		err = errors.New("aws not supported yet")
+		return newAWSProvisioner(provisioningOperator, ops...).Credentials(cluster, provider)
 	case types.Azure:
-		cr, err = newAzureProvisioner(provisioningOperator, ops...).Credentials(cluster, provider)
+		return newAzureProvisioner(provisioningOperator, ops...).Credentials(cluster, provider)
 	case types.Kind:
-		cr, err = newKindProvisioner(provisioningOperator, ops...).Credentials(cluster, provider)
+		return newKindProvisioner(provisioningOperator, ops...).Credentials(cluster, provider)
+	case types.K3d:
+		return newK3dProvisioner(provisioningOperator, ops...).Credentials(cluster, provider)
+	case types.DigitalOcean:
+		return newDigitalOceanProvisioner(provisioningOperator, ops...).Credentials(cluster, provider)
 	default:
-		err = errors.New("unknown provider")
+		return nil, errors.New("unknown provider")
 	}
-
-	if err != nil {
-		return cr, err
-	}
-	return cr, action.After()
 }
 
 // Deprovision removes an existing cluster along or returns an error if removing the cluster is not possible.
@@ -141,26 +180,155 @@ func Deprovision(cluster *types.Cluster, provider *types.Provider, ops ...types.
 		provider.CredentialsFilePath = updateWindowsPath(provider.CredentialsFilePath)
 	}
 
+	if err = handleOrphans(cluster, provider, ops...); err != nil {
+		return err
+	}
+
 	switch provider.Type {
 	case types.GCP:
 		err = newGCPProvisioner(provisioningOperator, ops...).Deprovision(cluster, provider)
 	case types.Gardener:
 		err = newGardenerProvisioner(provisioningOperator, ops...).Deprovision(cluster, provider)
 	case types.AWS:
-		err = errors.New("aws not supported yet")
+		err = newAWSProvisioner(provisioningOperator, ops...).Deprovision(cluster, provider)
 	case types.Azure:
 		err = newAzureProvisioner(provisioningOperator, ops...).Deprovision(cluster, provider)
 	case types.Kind:
 		err = newKindProvisioner(provisioningOperator, ops...).Deprovision(cluster, provider)
+	case types.K3d:
+		err = newK3dProvisioner(provisioningOperator, ops...).Deprovision(cluster, provider)
+	case types.DigitalOcean:
+		err = newDigitalOceanProvisioner(provisioningOperator, ops...).Deprovision(cluster, provider)
+	default:
+		err = errors.New("unknown provider")
+	}
+	if err != nil {
+		return err
+	}
+	if err = releaseLease(cluster, provider, ops...); err != nil {
+		return err
+	}
+	return action.After()
+}
+
+// Capabilities returns the features Hydroform supports for a given provider and the Kubernetes versions the provider currently offers in the location of the cluster, newest first.
+// Only the location of the cluster is used. Use it to write provider-agnostic code, for example to pick the latest Kubernetes version before the cluster is provisioned.
+func Capabilities(cluster *types.Cluster, provider *types.Provider, ops ...types.Option) (*types.Capabilities, error) {
+	var err error
+	var c *types.Capabilities
+
+	if err = action.Before(); err != nil {
+		return c, err
+	}
+
+	if runtime.GOOS == "windows" {
+		provider.CredentialsFilePath = updateWindowsPath(provider.CredentialsFilePath)
+	}
+
+	switch provider.Type {
+	case types.GCP:
+		c, err = newGCPProvisioner(provisioningOperator, ops...).Capabilities(cluster, provider)
+	case types.Gardener:
+		c, err = newGardenerProvisioner(provisioningOperator, ops...).Capabilities(cluster, provider)
+	case types.AWS:
+		c, err = newAWSProvisioner(provisioningOperator, ops...).Capabilities(cluster, provider)
+	case types.Azure:
+		c, err = newAzureProvisioner(provisioningOperator, ops...).Capabilities(cluster, provider)
+	case types.Kind:
+		c, err = newKindProvisioner(provisioningOperator, ops...).Capabilities(cluster, provider)
+	case types.K3d:
+		c, err = newK3dProvisioner(provisioningOperator, ops...).Capabilities(cluster, provider)
+	case types.DigitalOcean:
+		c, err = newDigitalOceanProvisioner(provisioningOperator, ops...).Capabilities(cluster, provider)
 	default:
 		err = errors.New("unknown provider")
 	}
+
+	if err != nil {
+		return c, err
+	}
+	sortVersions(c.KubernetesVersions)
+	return c, action.After()
+}
+
+// Hibernate scales down an existing cluster to save costs while it is not used. Only Gardener clusters can be hibernated.
+func Hibernate(cluster *types.Cluster, provider *types.Provider, ops ...types.Option) error {
+	var err error
+
+	if err = action.Before(); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		provider.CredentialsFilePath = updateWindowsPath(provider.CredentialsFilePath)
+	}
+
+	switch provider.Type {
+	case types.Gardener:
+		err = gardener.New(provisioningOperator, ops...).Hibernate(cluster, provider)
+	default:
+		err = fmt.Errorf("hibernation is not supported for provider %s", provider.Type)
+	}
+	if err != nil {
+		return err
+	}
+	return action.After()
+}
+
+// WakeUp resumes a hibernated cluster. Only Gardener clusters can be hibernated.
+func WakeUp(cluster *types.Cluster, provider *types.Provider, ops ...types.Option) error {
+	var err error
+
+	if err = action.Before(); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		provider.CredentialsFilePath = updateWindowsPath(provider.CredentialsFilePath)
+	}
+
+	switch provider.Type {
+	case types.Gardener:
+		err = gardener.New(provisioningOperator, ops...).WakeUp(cluster, provider)
+	default:
+		err = fmt.Errorf("hibernation is not supported for provider %s", provider.Type)
+	}
 	if err != nil {
 		return err
 	}
 	return action.After()
 }
 
+// checkCost runs the cost check of the options, if any
+func checkCost(cluster *types.Cluster, provider *types.Provider, ops ...types.Option) error {
+	os := &types.Options{}
+	for _, o := range ops {
+		o(os)
+	}
+	if os.CostCheck == nil {
+		return nil
+	}
+	return os.CostCheck(cluster, provider)
+}
+
+// checkTags verifies that all required tags of the options are set
+func checkTags(ops ...types.Option) error {
+	os := &types.Options{}
+	for _, o := range ops {
+		o(os)
+	}
+	var missing []string
+	for _, key := range os.RequiredTags {
+		if os.Tags[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the following required tags are missing: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 func newGCPProvisioner(operatorType operator.Type, ops ...types.Option) Provisioner {
 	return gcp.New(operatorType, ops...)
 }
@@ -170,7 +338,7 @@ func newGardenerProvisioner(operatorType operator.Type, ops ...types.Option) Pro
 }
 
 func newAWSProvisioner(operatorType operator.Type, ops ...types.Option) Provisioner {
-	return nil
+	return aws.New(operatorType, ops...)
 }
 
 func newAzureProvisioner(operatorType operator.Type, ops ...types.Option) Provisioner {
@@ -181,6 +349,26 @@ func newKindProvisioner(operatorType operator.Type, ops ...types.Option) Provisi
 	return kind.New(operatorType, ops...)
 }
 
+func newK3dProvisioner(operatorType operator.Type, ops ...types.Option) Provisioner {
+	return k3d.New(operatorType, ops...)
+}
+
+func newDigitalOceanProvisioner(operatorType operator.Type, ops ...types.Option) Provisioner {
+	return digitalocean.New(operatorType, ops...)
+}
+
+// sortVersions orders the Kubernetes versions newest first. Provider-specific suffixes, such as -gke.1210, are ordered as strings.
+func sortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, errI := version.ParseGeneric(versions[i])
+		vj, errJ := version.ParseGeneric(versions[j])
+		if errI != nil || errJ != nil || vi.String() == vj.String() {
+			return versions[i] > versions[j]
+		}
+		return vj.LessThan(vi)
+	})
+}
+
 func updateWindowsPath(windowsPath string) string {
 	cleanWindowsPath := filepath.Clean(windowsPath)
 	return strings.Replace(cleanWindowsPath, `\`, `\\`, -1)
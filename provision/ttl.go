@@ -0,0 +1,84 @@
+package provision
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/provision/internal/lease"
+	terraform_operator "github.com/kyma-incubator/hydroform/provision/internal/operator/terraform"
+	"github.com/kyma-incubator/hydroform/provision/types"
+)
+
+// now is the time source of the cluster leases
+var now = time.Now
+
+// ReapExpired returns the clusters provisioned with the types.WithTTL option which expired, ordered by their expiration.
+// Unless dryRun is set, the expired clusters are deprovisioned with the given options in the workspace they were provisioned in.
+// A cluster which cannot be deprovisioned is kept in the list of leases, so the next call retries it, and its error is returned
+// after all other clusters were processed.
+func ReapExpired(dryRun bool, ops ...types.Option) ([]*types.ClusterLease, error) {
+	leases, err := leaseStore(ops...).List()
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []*types.ClusterLease
+	var failures []string
+	current := now()
+	for _, l := range leases {
+		if !l.Expired(current) {
+			continue
+		}
+		expired = append(expired, l)
+		if dryRun {
+			continue
+		}
+		if err := Deprovision(l.Cluster, l.Provider, append(ops, types.WithWorkspace(l.Workspace))...); err != nil {
+			failures = append(failures, fmt.Sprintf("%s/%s: %s", l.Provider.Type, l.Cluster.Name, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return expired, fmt.Errorf("could not deprovision the following expired clusters:\n%s", strings.Join(failures, "\n"))
+	}
+	return expired, nil
+}
+
+// recordLease stores the lease of a cluster provisioned with a TTL and returns the option adding the expiration tag.
+// The lease is stored before the cluster is created, so that clusters whose provisioning failed half-way expire as well.
+func recordLease(cluster *types.Cluster, provider *types.Provider, ops ...types.Option) (types.Option, error) {
+	os := &types.Options{}
+	for _, o := range ops {
+		o(os)
+	}
+	if os.TTL <= 0 {
+		return nil, nil
+	}
+
+	expiresAt := now().Add(os.TTL).UTC().Truncate(time.Second)
+	err := leaseStore(ops...).Put(&types.ClusterLease{
+		Cluster:   cluster,
+		Provider:  provider,
+		Workspace: os.Workspace,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return types.WithTags(map[string]string{types.ExpiresAtTag: strconv.FormatInt(expiresAt.Unix(), 10)}), nil
+}
+
+// releaseLease removes the lease of a deprovisioned cluster
+func releaseLease(cluster *types.Cluster, provider *types.Provider, ops ...types.Option) error {
+	os := &types.Options{}
+	for _, o := range ops {
+		o(os)
+	}
+	return leaseStore(ops...).Delete(os.Workspace, provider, cluster)
+}
+
+func leaseStore(ops ...types.Option) *lease.Store {
+	return lease.NewStore(terraform_operator.DataDir(terraformOptions(ops...)...))
+}
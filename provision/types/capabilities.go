@@ -0,0 +1,14 @@
+package types
+
+// Capabilities lists the features Hydroform supports for a provider, so that callers can write provider-agnostic code.
+type Capabilities struct {
+	// Autoscaling is true if the provider scales the nodes of a cluster automatically within configured limits.
+	Autoscaling bool `json:"autoscaling"`
+	// SpotInstances is true if the nodes of a cluster can run on spot instances.
+	SpotInstances bool `json:"spotInstances"`
+	// Hibernation is true if a cluster can be hibernated and woken up.
+	Hibernation bool `json:"hibernation"`
+	// KubernetesVersions lists the Kubernetes versions the provider currently offers in the location of the cluster, newest first.
+	// It is empty if the version is determined by the node image, such as on kind and k3d.
+	KubernetesVersions []string `json:"kubernetesVersions"`
+}
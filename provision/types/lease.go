@@ -0,0 +1,24 @@
+package types
+
+import "time"
+
+// ExpiresAtTag is the tag Provision adds to the cloud resources of a cluster provisioned with a TTL.
+// Its value is the expiration time in seconds since the Unix epoch, which is a valid tag and label value on all providers.
+const ExpiresAtTag = "hydroform-expires-at"
+
+// ClusterLease records the expiration of a cluster provisioned with a TTL, so that it can be deprovisioned once it expired.
+type ClusterLease struct {
+	// Cluster is the cluster as it was passed to Provision, without the ClusterInfo.
+	Cluster *Cluster `json:"cluster"`
+	// Provider is the provider as it was passed to Provision.
+	Provider *Provider `json:"provider"`
+	// Workspace is the workspace the cluster was provisioned in.
+	Workspace string `json:"workspace,omitempty"`
+	// ExpiresAt is the time after which the cluster can be deprovisioned.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Expired returns true if the lease expired at the given time.
+func (l *ClusterLease) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
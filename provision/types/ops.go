@@ -9,6 +9,25 @@ type Options struct {
 	Persistent bool
 	Timeouts   *Timeouts
 	Verbose    bool // Print terraform log for debugging
+	Targets    []string
+	Taints     []string
+	Workspace  string
+	// CredentialsExpiration requests short-lived credentials from Credentials, if supported by the provider
+	CredentialsExpiration time.Duration
+	// RotateCredentials makes Credentials rotate the cluster credentials, if supported by the provider
+	RotateCredentials bool
+	// GardenerAPI manages Gardener clusters via the Gardener API instead of Terraform
+	GardenerAPI bool
+	// CostCheck is called by Provision before the cluster is created or updated, an error stops the provisioning
+	CostCheck func(cluster *Cluster, provider *Provider) error
+	// Tags are added to all cloud resources created for the cluster
+	Tags map[string]string
+	// RequiredTags lists the tags Provision requires to be set
+	RequiredTags []string
+	// TTL is the time after which a provisioned cluster expires, zero if it does not expire
+	TTL time.Duration
+	// OrphanPolicy decides how Deprovision handles cloud resources created by controllers in the cluster
+	OrphanPolicy OrphanPolicy
 }
 
 // Timeouts specifies timeouts on various operation
@@ -47,3 +66,102 @@ func Verbose(verbose bool) Option {
 		ops.Verbose = verbose
 	}
 }
+
+// WithTargets scopes apply and destroy operations to the given resource addresses (e.g. "google_container_cluster.gke_cluster").
+// All other resources in the configuration are left untouched.
+func WithTargets(addresses ...string) Option {
+	return func(ops *Options) {
+		ops.Targets = append(ops.Targets, addresses...)
+	}
+}
+
+// WithTaints marks the given resource addresses as tainted before applying, so they get destroyed and recreated.
+// Tainting requires an existing state, so it should be combined with a persistent data directory.
+func WithTaints(addresses ...string) Option {
+	return func(ops *Options) {
+		ops.Taints = append(ops.Taints, addresses...)
+	}
+}
+
+// WithWorkspace selects a named workspace. Each workspace keeps separate cluster states, so the same configuration
+// can be used to manage several environments (e.g. dev, stage and prod). The workspace is created if it does not exist yet.
+func WithWorkspace(name string) Option {
+	return func(ops *Options) {
+		ops.Workspace = name
+	}
+}
+
+// WithCredentialsExpiration makes Credentials issue a fresh kubeconfig with short-lived credentials on each call instead of returning long-lived ones.
+// On Gardener, the admin kubeconfig expires after the given duration. On GCP, the kubeconfig contains an access token
+// of the service account, which expires after the given duration of up to 12 hours. Other providers ignore this option.
+func WithCredentialsExpiration(expiration time.Duration) Option {
+	return func(ops *Options) {
+		ops.CredentialsExpiration = expiration
+	}
+}
+
+// WithCredentialsRotation makes Credentials rotate the cluster credentials before returning a new admin kubeconfig,
+// so that all kubeconfigs issued before stop working. On Azure, the certificates of the AKS cluster are rotated, which restarts
+// all nodes and can take up to 30 minutes. Other providers ignore this option.
+func WithCredentialsRotation() Option {
+	return func(ops *Options) {
+		ops.RotateCredentials = true
+	}
+}
+
+// WithGardenerAPI creates, updates, and deletes Gardener clusters directly via the Shoot resources of the Gardener API instead of Terraform.
+// The errors Gardener reports for a shoot are returned as they are. The cluster state is kept by Gardener, so no data directory is used.
+// Other providers ignore this option.
+func WithGardenerAPI() Option {
+	return func(ops *Options) {
+		ops.GardenerAPI = true
+	}
+}
+
+// WithCostCheck runs the check before a cluster is provisioned and stops the provisioning if the check fails.
+// Use it with the cost package to reject clusters whose estimated cost is too high, for example:
+// types.WithCostCheck(cost.NewEstimator(pricing).Limit(500))
+func WithCostCheck(check func(cluster *Cluster, provider *Provider) error) Option {
+	return func(ops *Options) {
+		ops.CostCheck = check
+	}
+}
+
+// WithTags adds the tags to all cloud resources created for the cluster, for example its owner, cost center, or TTL.
+// The tags are set as tags on AWS and Azure, labels on GCP and on the Gardener shoot, and "key:value" tags on DigitalOcean.
+// Kind clusters create no cloud resources and ignore this option.
+func WithTags(tags map[string]string) Option {
+	return func(ops *Options) {
+		if ops.Tags == nil {
+			ops.Tags = map[string]string{}
+		}
+		for k, v := range tags {
+			ops.Tags[k] = v
+		}
+	}
+}
+
+// WithRequiredTags makes Provision fail before anything is created if one of the given tags is not set with WithTags or has an empty value.
+func WithRequiredTags(keys ...string) Option {
+	return func(ops *Options) {
+		ops.RequiredTags = append(ops.RequiredTags, keys...)
+	}
+}
+
+// WithTTL makes the cluster expire after the given duration from the time Provision is called.
+// The expiration is recorded in the data directory and added as the types.ExpiresAtTag tag to the cloud resources of the cluster.
+// Use ReapExpired to deprovision expired clusters, for example the throwaway clusters of CI runs.
+func WithTTL(ttl time.Duration) Option {
+	return func(ops *Options) {
+		ops.TTL = ttl
+	}
+}
+
+// WithOrphanPolicy makes Deprovision look for the disks, load balancers, and DNS records which controllers in the cluster created,
+// and either fail or delete them before the cluster is destroyed. Terraform does not know these resources, so they are left behind otherwise.
+// On Gardener, kind, and k3d, the option is ignored, because Gardener cleans up these resources itself and local clusters create none.
+func WithOrphanPolicy(policy OrphanPolicy) Option {
+	return func(ops *Options) {
+		ops.OrphanPolicy = policy
+	}
+}
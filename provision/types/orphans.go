@@ -0,0 +1,43 @@
+package types
+
+import "fmt"
+
+// CloudResourceKind lists the kinds of cloud resources which controllers in a cluster create.
+type CloudResourceKind string
+
+const (
+	// CloudDisk is the disk of a persistent volume.
+	CloudDisk CloudResourceKind = "disk"
+	// CloudLoadBalancer is the load balancer of a service of the LoadBalancer type.
+	CloudLoadBalancer CloudResourceKind = "load balancer"
+	// CloudDNSRecord is a DNS record external-dns creates for an annotated service or ingress.
+	CloudDNSRecord CloudResourceKind = "DNS record"
+)
+
+// CloudResource is a cloud resource which a controller in the cluster created, rather than Hydroform.
+// Deprovisioning the cluster does not delete it.
+type CloudResource struct {
+	// Kind is the kind of the cloud resource.
+	Kind CloudResourceKind `json:"kind"`
+	// Object is the Kubernetes object the resource belongs to, such as services/default/web or persistentvolumes/pvc-1234.
+	Object string `json:"object"`
+	// ID identifies the resource at the provider, such as the disk ID, the addresses of the load balancer, or the DNS name.
+	ID string `json:"id"`
+}
+
+func (r CloudResource) String() string {
+	return fmt.Sprintf("%s %s of %s", r.Kind, r.ID, r.Object)
+}
+
+// OrphanPolicy decides how Deprovision handles the cloud resources which controllers in the cluster created.
+type OrphanPolicy string
+
+const (
+	// OrphanIgnore destroys the cluster without looking for such resources. This is the default.
+	OrphanIgnore OrphanPolicy = ""
+	// OrphanFail stops Deprovision before anything is destroyed if the cluster has such resources, and lists them in the error.
+	OrphanFail OrphanPolicy = "fail"
+	// OrphanDelete deletes the Kubernetes objects the resources belong to, and waits until the controllers deleted the disks and
+	// load balancers, before the cluster is destroyed.
+	OrphanDelete OrphanPolicy = "delete"
+)
@@ -27,4 +27,10 @@ const (
 	Gardener ProviderType = "gardener"
 	// Kind stands for the kind (kubernetes in docker) platform.
 	Kind ProviderType = "kind"
+	// OpenStack stands for the OpenStack cloud platform, it is only available as Gardener target provider.
+	OpenStack ProviderType = "openstack"
+	// DigitalOcean stands for the DigitalOcean Kubernetes service.
+	DigitalOcean ProviderType = "digitalocean"
+	// K3d stands for local k3s clusters running in Docker, managed with the k3d CLI.
+	K3d ProviderType = "k3d"
 )
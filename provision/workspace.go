@@ -0,0 +1,26 @@
+package provision
+
+import (
+	terraform_operator "github.com/kyma-incubator/hydroform/provision/internal/operator/terraform"
+	"github.com/kyma-incubator/hydroform/provision/types"
+)
+
+// Workspaces returns the names of all workspaces available in the configured data directory.
+// Select a workspace for any Hydroform operation with the types.WithWorkspace option.
+func Workspaces(ops ...types.Option) ([]string, error) {
+	return terraform_operator.Workspaces(terraformOptions(ops...)...)
+}
+
+// DeleteWorkspace removes a workspace and all of its files.
+// The default workspace and workspaces with provisioned clusters cannot be deleted, deprovision the clusters first.
+func DeleteWorkspace(name string, ops ...types.Option) error {
+	return terraform_operator.DeleteWorkspace(name, terraformOptions(ops...)...)
+}
+
+func terraformOptions(ops ...types.Option) []terraform_operator.Option {
+	os := &types.Options{}
+	for _, o := range ops {
+		o(os)
+	}
+	return terraform_operator.ToTerraformOptions(os)
+}